@@ -0,0 +1,291 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package archive records executed templates and their outcome in a git repository,
+// giving a reviewable history of infrastructure changes
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/wallix/awless/template"
+	"github.com/wallix/awless/template/ast"
+)
+
+const (
+	// RepoKey is the config key of the local git repository archiving runs. It is created if missing
+	RepoKey = "archive.git.repo"
+	// RemoteKey is the config key of the git remote to push the archive to after each run. Ex: origin
+	RemoteKey = "archive.git.remote"
+
+	// Redacted replaces the values of secret params in archived templates
+	Redacted = "<redacted>"
+)
+
+var secretParamRegex = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|privatekey|apikey)`)
+
+// Run is a template execution to archive
+type Run struct {
+	ID       string
+	Date     time.Time
+	Region   string
+	Context  string
+	Template *template.Template
+	Err      error
+}
+
+type statement struct {
+	Line   string `json:"line"`
+	Result string `json:"result,omitempty"`
+	Err    string `json:"error,omitempty"`
+	// Skipped statements were not run because of a previous failure
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+type outcome struct {
+	ID         string      `json:"id"`
+	Date       time.Time   `json:"date"`
+	Region     string      `json:"region,omitempty"`
+	Context    string      `json:"context,omitempty"`
+	Status     string      `json:"status"`
+	Err        string      `json:"error,omitempty"`
+	Statements []statement `json:"statements"`
+}
+
+// Archive is a git repository of executed templates
+type Archive struct {
+	dir, remote string
+}
+
+// FromConfig returns the archive set in the given config values, or nil when not configured
+func FromConfig(defaults map[string]interface{}) *Archive {
+	dir, _ := defaults[RepoKey].(string)
+	if dir = strings.TrimSpace(dir); dir == "" {
+		return nil
+	}
+	if strings.HasPrefix(dir, "~/") {
+		dir = filepath.Join(os.Getenv("HOME"), dir[2:])
+	}
+	remote, _ := defaults[RemoteKey].(string)
+	return New(dir, strings.TrimSpace(remote))
+}
+
+func New(dir, remote string) *Archive {
+	return &Archive{dir: dir, remote: remote}
+}
+
+// Record writes the redacted template and its outcome under runs/{yyyy}/{mm}/{dd}/{id}.aws|json,
+// commits them and pushes to the remote if any
+func (a *Archive) Record(r *Run) error {
+	if err := a.init(); err != nil {
+		return err
+	}
+	if r.Date.IsZero() {
+		r.Date = time.Now().UTC()
+	}
+	out := newOutcome(r)
+
+	relDir := filepath.Join("runs", r.Date.Format("2006"), r.Date.Format("01"), r.Date.Format("02"))
+	if err := os.MkdirAll(filepath.Join(a.dir, relDir), 0700); err != nil {
+		return err
+	}
+	script := filepath.Join(relDir, r.ID+".aws")
+	if err := ioutil.WriteFile(filepath.Join(a.dir, script), []byte(out.script()), 0600); err != nil {
+		return err
+	}
+	report, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	result := filepath.Join(relDir, r.ID+".json")
+	if err := ioutil.WriteFile(filepath.Join(a.dir, result), append(report, '\n'), 0600); err != nil {
+		return err
+	}
+
+	if _, err := a.git("add", script, result); err != nil {
+		return err
+	}
+	if _, err := a.git(append(a.committer(), "commit", "-m", out.commitMessage())...); err != nil {
+		return err
+	}
+	if a.remote != "" {
+		if _, err := a.git("push", a.remote, "HEAD"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Archive) init() error {
+	if err := os.MkdirAll(a.dir, 0700); err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(a.dir, ".git")); os.IsNotExist(err) {
+		if _, err := a.git("init"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// committer uses the git identity of the user when set, to know who ran what
+func (a *Archive) committer() []string {
+	if email, _ := a.git("config", "user.email"); strings.TrimSpace(email) != "" {
+		return nil
+	}
+	return []string{"-c", "user.name=awless", "-c", "user.email=git@awless.io"}
+}
+
+func (a *Archive) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = a.dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("archive: git %s: %s: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+func newOutcome(r *Run) *outcome {
+	out := &outcome{ID: r.ID, Date: r.Date, Region: r.Region, Context: r.Context, Status: "succeeded"}
+	for _, st := range r.Template.Statements {
+		var cmd *ast.CommandNode
+		var line string
+		switch n := st.Node.(type) {
+		case *ast.CommandNode:
+			cmd = n
+			line = Redact(n).String()
+		case *ast.DeclarationNode:
+			if c, ok := n.Expr.(*ast.CommandNode); ok {
+				cmd = c
+				line = fmt.Sprintf("%s = %s", n.Ident, Redact(c))
+			}
+		}
+		if cmd == nil {
+			continue
+		}
+		s := statement{Line: line}
+		if cmd.CmdResult != nil {
+			s.Result = fmt.Sprint(cmd.CmdResult)
+		}
+		switch {
+		case cmd.CmdErr != nil:
+			s.Err = cmd.CmdErr.Error()
+			out.Status = "failed"
+		case out.Status == "failed" && cmd.CmdResult == nil:
+			s.Skipped = true
+		}
+		out.Statements = append(out.Statements, s)
+	}
+	if r.Err != nil {
+		out.Status = "failed"
+		out.Err = r.Err.Error()
+	}
+	return out
+}
+
+func (o *outcome) script() string {
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "# run %s %s on %s\n", o.ID, o.Status, o.Date.Format(time.RFC3339))
+	if o.Region != "" {
+		fmt.Fprintf(&buff, "# region %s\n", o.Region)
+	}
+	if o.Context != "" {
+		fmt.Fprintf(&buff, "# context %s\n", o.Context)
+	}
+	for _, s := range o.Statements {
+		buff.WriteString(s.Line)
+		buff.WriteByte('\n')
+	}
+	return buff.String()
+}
+
+func (o *outcome) commitMessage() string {
+	var subject []string
+	for _, s := range o.Statements {
+		fields := strings.Fields(s.Line)
+		if len(fields) > 2 && fields[1] == "=" {
+			fields = fields[2:]
+		}
+		if len(fields) >= 2 {
+			subject = append(subject, fields[0]+" "+fields[1])
+		}
+	}
+	msg := fmt.Sprintf("%s: %s", o.Status, strings.Join(subject, ", "))
+	var details []string
+	for _, s := range o.Statements {
+		switch {
+		case s.Err != "":
+			details = append(details, fmt.Sprintf("KO %s\n   %s", s.Line, s.Err))
+		case s.Skipped:
+			details = append(details, fmt.Sprintf("-- %s (not run)", s.Line))
+		case s.Result != "":
+			details = append(details, fmt.Sprintf("OK %s -> %s", s.Line, s.Result))
+		default:
+			details = append(details, fmt.Sprintf("OK %s", s.Line))
+		}
+	}
+	if o.Err != "" {
+		details = append(details, o.Err)
+	}
+	return fmt.Sprintf("%s\n\nrun %s\n\n%s\n", msg, o.ID, strings.Join(details, "\n"))
+}
+
+// RedactTemplate returns the text of the template with the values of its secret params redacted
+func RedactTemplate(tpl *template.Template) string {
+	redacted := tpl.AST.Clone()
+	for _, st := range redacted.Statements {
+		switch n := st.Node.(type) {
+		case *ast.CommandNode:
+			st.Node = Redact(n)
+		case *ast.DeclarationNode:
+			if c, ok := n.Expr.(*ast.CommandNode); ok {
+				n.Expr = Redact(c)
+			}
+		case *ast.ParamSetNode:
+			n.Params = Redact(n.Params)
+		}
+	}
+	return redacted.String()
+}
+
+// Redact returns a copy of the command with the values of its secret params redacted
+func Redact(cmd *ast.CommandNode) *ast.CommandNode {
+	redacted := &ast.CommandNode{Action: cmd.Action, Entity: cmd.Entity, Refs: cmd.Refs, Aliases: cmd.Aliases, Holes: cmd.Holes, HoleTypes: cmd.HoleTypes, Optionals: cmd.Optionals, Params: make(map[string]interface{})}
+	for k, v := range cmd.Params {
+		if IsSecretParam(k) {
+			v = Redacted
+		}
+		redacted.Params[k] = v
+	}
+	return redacted
+}
+
+// IsSecretParam reports whether the value of the template param should not be archived
+func IsSecretParam(name string) bool {
+	return secretParamRegex.MatchString(name)
+}
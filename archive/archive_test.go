@@ -0,0 +1,122 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/template"
+)
+
+func TestRecordRun(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir, err := ioutil.TempDir("", "awless-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tpl, err := template.Parse("inst = create instance name=web\nstart instance id=$inst\ncreate tag key=env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmds := tpl.CommandNodesIterator()
+	cmds[0].Params["token"] = "s3cr3t"
+	cmds[0].CmdResult = "i-1234"
+	cmds[1].CmdErr = errors.New("insufficient capacity")
+
+	archive := FromConfig(map[string]interface{}{RepoKey: dir})
+	date := time.Date(2017, 3, 14, 10, 0, 0, 0, time.UTC)
+	if err := archive.Record(&Run{ID: "01BAC", Date: date, Region: "eu-west-1", Template: tpl}); err != nil {
+		t.Fatal(err)
+	}
+
+	script, err := ioutil.ReadFile(filepath.Join(dir, "runs", "2017", "03", "14", "01BAC.aws"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(script), "s3cr3t") {
+		t.Fatalf("secret not redacted in %s", script)
+	}
+	if got, want := string(script), `token="`+Redacted+`"`; !strings.Contains(got, want) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := strings.SplitN(string(script), "\n", 2)[0], "# run 01BAC failed on 2017-03-14T10:00:00Z"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "runs", "2017", "03", "14", "01BAC.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out outcome
+	if err := json.Unmarshal(content, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.Status, "failed"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	var results, errs []string
+	var skipped []bool
+	for _, s := range out.Statements {
+		results, errs, skipped = append(results, s.Result), append(errs, s.Err), append(skipped, s.Skipped)
+	}
+	if got, want := results, []string{"i-1234", "", ""}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := errs, []string{"", "insufficient capacity", ""}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := skipped, []bool{false, false, true}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	subject, err := archive.git("log", "-1", "--format=%s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimSpace(subject), "failed: create instance, start instance, create tag"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestIsSecretParam(t *testing.T) {
+	for _, secret := range []string{"password", "DBPassword", "secret-key", "token", "apikey"} {
+		if !IsSecretParam(secret) {
+			t.Fatalf("%s: expected secret", secret)
+		}
+	}
+	for _, notSecret := range []string{"name", "keypair", "key", "userdata"} {
+		if IsSecretParam(notSecret) {
+			t.Fatalf("%s: expected not secret", notSecret)
+		}
+	}
+	if got := FromConfig(map[string]interface{}{}); got != nil {
+		t.Fatalf("got %v, want nil", got)
+	}
+}
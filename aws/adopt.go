@@ -0,0 +1,229 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wallix/awless/graph"
+)
+
+// AdoptTemplate returns the create statements of a live resource and of the resources it depends on
+// in the graph (ex: the subnet and vpc of an instance), ordered with dependencies first and referenced
+// through variables, so that existing infrastructure can be managed by templates. Dependencies that
+// templates cannot create (default vpcs, subnets and security groups, keypairs, ...) are referenced by id
+func AdoptTemplate(g *graph.Graph, res *graph.Resource) (string, error) {
+	a := &adopter{g: g, vars: make(map[string]string), used: make(map[string]bool)}
+	fmt.Fprintf(&a.buff, "# Adopted from %s\n", res)
+	v, err := a.adopt(res)
+	if err != nil {
+		return "", err
+	}
+	if v == "" {
+		return "", fmt.Errorf("cannot adopt %s: not creatable by templates", res)
+	}
+	return a.buff.String(), nil
+}
+
+type adopter struct {
+	g    *graph.Graph
+	buff bytes.Buffer
+	vars map[string]string // variables of the adopted resources per id
+	used map[string]bool
+}
+
+// ref returns the reference to the dependency of the given type, adopting it first.
+// The id is returned as is when the dependency is not in the graph or cannot be adopted
+func (a *adopter) ref(t graph.ResourceType, id interface{}) (string, error) {
+	if id == nil || fmt.Sprint(id) == "" {
+		return "", nil
+	}
+	str := fmt.Sprint(id)
+	if v, ok := a.vars[str]; ok {
+		return "$" + v, nil
+	}
+	dep, err := a.g.GetResource(t, str)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := dep.Properties["Id"]; !ok {
+		return str, nil
+	}
+	v, err := a.adopt(dep)
+	if err != nil || v == "" {
+		return str, err
+	}
+	return "$" + v, nil
+}
+
+// adopt writes the statements creating the resource after those of its dependencies,
+// returning the variable holding the created resource, or "" if it cannot be created
+func (a *adopter) adopt(res *graph.Resource) (string, error) {
+	props := res.Properties
+	stmt := newCloneStatement("create", res.Type().String())
+	nameTag := true
+	var attachments []*cloneStatement
+
+	switch res.Type() {
+	case graph.Vpc:
+		if isDefault, _ := props["IsDefault"].(bool); isDefault {
+			return "", nil
+		}
+		stmt.param("cidr", props["CidrBlock"])
+	case graph.Subnet:
+		if isDefault, _ := props["DefaultForAz"].(bool); isDefault {
+			return "", nil
+		}
+		vpc, err := a.ref(graph.Vpc, props["VpcId"])
+		if err != nil {
+			return "", err
+		}
+		stmt.param("cidr", props["CidrBlock"])
+		stmt.param("vpc", vpc)
+		stmt.param("zone", props["AvailabilityZone"])
+	case graph.SecurityGroup:
+		if props["Name"] == "default" {
+			return "", nil
+		}
+		vpc, err := a.ref(graph.Vpc, props["VpcId"])
+		if err != nil {
+			return "", err
+		}
+		stmt.quotedParam("name", props["Name"])
+		stmt.param("vpc", vpc)
+		stmt.quotedParam("description", props["Description"])
+		nameTag = false
+	case graph.Instance:
+		subnet, err := a.ref(graph.Subnet, props["SubnetId"])
+		if err != nil {
+			return "", err
+		}
+		var group string
+		if groups := stringValues(props["SecurityGroups"]); len(groups) > 0 {
+			if group, err = a.ref(graph.SecurityGroup, groups[0]); err != nil {
+				return "", err
+			}
+			if len(groups) > 1 {
+				fmt.Fprintf(&a.buff, "# Only the first security group can be given at creation. Original groups of %s: %s\n", res, strings.Join(groups, ", "))
+			}
+		}
+		stmt.param("image", props["ImageId"])
+		stmt.param("type", props["Type"])
+		stmt.rawParam("count", "1")
+		stmt.param("subnet", subnet)
+		stmt.param("key", props["KeyName"])
+		stmt.param("group", group)
+		stmt.quotedParam("name", props["Name"])
+		nameTag = false
+	case graph.Volume:
+		stmt.param("zone", props["AvailabilityZone"])
+		stmt.param("size", props["Size"])
+	case graph.InternetGateway:
+		for _, id := range stringValues(props["Vpcs"]) {
+			vpc, err := a.ref(graph.Vpc, id)
+			if err != nil {
+				return "", err
+			}
+			attach := newCloneStatement("attach", "internetgateway")
+			attach.param("vpc", vpc)
+			attachments = append(attachments, attach)
+		}
+	case graph.RouteTable:
+		if main, _ := props["Main"].(bool); main {
+			return "", nil
+		}
+		vpc, err := a.ref(graph.Vpc, props["VpcId"])
+		if err != nil {
+			return "", err
+		}
+		stmt.param("vpc", vpc)
+		fmt.Fprintf(&a.buff, "# Routes of %s are not adopted\n", res)
+	case graph.Bucket, graph.Queue, graph.Topic, graph.User, graph.Group:
+		stmt.quotedParam("name", props["Name"])
+		nameTag = false
+	default:
+		return "", nil
+	}
+
+	v := a.variable(res)
+	fmt.Fprintf(&a.buff, "%s = %s", v, stmt)
+	for _, attach := range attachments {
+		attach.params = append([]string{"id=$" + v}, attach.params...)
+		a.buff.WriteString(attach.String())
+	}
+	if res.Type() == graph.SecurityGroup {
+		writeRulesStatements(&a.buff, v, "inbound", props["InboundRules"])
+		writeRulesStatements(&a.buff, v, "outbound", props["OutboundRules"])
+	}
+	tags := stringValues(props["Tags"])
+	if name, ok := props["Name"]; ok && nameTag && len(tags) == 0 {
+		tags = []string{fmt.Sprintf("Name=%v", name)}
+	}
+	for _, tag := range tags {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) != 2 || strings.HasPrefix(kv[0], "aws:") || (kv[0] == "Name" && !nameTag) {
+			continue
+		}
+		stmt := newCloneStatement("create", "tag")
+		stmt.rawParam("resource", "$"+v)
+		stmt.quotedParam("key", kv[0])
+		stmt.quotedParam("value", kv[1])
+		a.buff.WriteString(stmt.String())
+	}
+	return v, nil
+}
+
+// variable returns a new template variable for the resource, named after its type and name
+func (a *adopter) variable(res *graph.Resource) string {
+	v := res.Type().String()
+	if name, ok := res.Properties["Name"]; ok {
+		var b bytes.Buffer
+		for _, r := range fmt.Sprint(name) {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-' || r == '_' {
+				b.WriteRune(r)
+			}
+		}
+		if b.Len() > 0 {
+			v = v + "_" + b.String()
+		}
+	}
+	for unique, suffix := v, 'b'; ; suffix++ {
+		if !a.used[unique] {
+			v = unique
+			break
+		}
+		unique = fmt.Sprintf("%s_%c", v, suffix)
+	}
+	a.used[v] = true
+	a.vars[res.Id()] = v
+	return v
+}
+
+// quotedParam adds the param, quoted if needed to be parsed
+func (s *cloneStatement) quotedParam(key string, value interface{}) {
+	if value == nil || fmt.Sprint(value) == "" {
+		return
+	}
+	str := fmt.Sprint(value)
+	if !templateStringValueRegex.MatchString(str) {
+		str = strconv.Quote(str)
+	}
+	s.rawParam(key, str)
+}
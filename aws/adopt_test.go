@@ -0,0 +1,115 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"net"
+	"testing"
+
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template"
+)
+
+func TestAdoptTemplate(t *testing.T) {
+	g := graph.NewGraph()
+	vpc := graph.InitResource("vpc_1", graph.Vpc)
+	vpc.Properties["Id"] = "vpc_1"
+	vpc.Properties["Name"] = "main 2"
+	vpc.Properties["IsDefault"] = false
+	vpc.Properties["CidrBlock"] = "10.0.0.0/16"
+	subnet := graph.InitResource("sub_1", graph.Subnet)
+	subnet.Properties["Id"] = "sub_1"
+	subnet.Properties["VpcId"] = "vpc_1"
+	subnet.Properties["CidrBlock"] = "10.0.1.0/24"
+	subnet.Properties["AvailabilityZone"] = "eu-west-1a"
+	_, anywhere, _ := net.ParseCIDR("0.0.0.0/0")
+	sg := graph.InitResource("sg_1", graph.SecurityGroup)
+	sg.Properties["Id"] = "sg_1"
+	sg.Properties["Name"] = "web"
+	sg.Properties["VpcId"] = "vpc_1"
+	sg.Properties["Description"] = "web servers"
+	sg.Properties["InboundRules"] = []*graph.FirewallRule{
+		{Protocol: "tcp", PortRange: graph.PortRange{FromPort: 443, ToPort: 443}, IPRanges: []*net.IPNet{anywhere}},
+	}
+	defaultSg := graph.InitResource("sg_2", graph.SecurityGroup)
+	defaultSg.Properties["Id"] = "sg_2"
+	defaultSg.Properties["Name"] = "default"
+	inst := graph.InitResource("inst_1", graph.Instance)
+	inst.Properties["Id"] = "inst_1"
+	inst.Properties["Name"] = "web"
+	inst.Properties["ImageId"] = "ami-123"
+	inst.Properties["Type"] = "t2.micro"
+	inst.Properties["SubnetId"] = "sub_1"
+	inst.Properties["KeyName"] = "my-key"
+	inst.Properties["SecurityGroups"] = []interface{}{"sg_1", "sg_2"}
+	inst.Properties["Tags"] = []interface{}{"Name=web", "Env=prod", "aws:cloudformation:stack-name=web"}
+	defaultVpc := graph.InitResource("vpc_2", graph.Vpc)
+	defaultVpc.Properties["Id"] = "vpc_2"
+	defaultVpc.Properties["IsDefault"] = true
+	g.AddResource(vpc, subnet, sg, defaultSg, inst, defaultVpc)
+
+	tpl, err := AdoptTemplate(g, inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# Adopted from @web[instance]
+vpc_main = create vpc cidr=10.0.0.0/16
+create tag resource=$vpc_main key=Name value="main 2"
+subnet = create subnet cidr=10.0.1.0/24 vpc=$vpc_main zone=eu-west-1a
+securitygroup_web = create securitygroup name=web vpc=$vpc_main description="web servers"
+update securitygroup id=$securitygroup_web inbound=authorize protocol=tcp cidr=0.0.0.0/0 portrange=443
+# Only the first security group can be given at creation. Original groups of @web[instance]: sg_1, sg_2
+instance_web = create instance image=ami-123 type=t2.micro count=1 subnet=$subnet key=my-key group=$securitygroup_web name=web
+create tag resource=$instance_web key=Env value=prod
+`
+	if got, want := tpl, expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+	if _, err = template.Parse(tpl); err != nil {
+		t.Fatal(err)
+	}
+
+	tpl, err = AdoptTemplate(g, sg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `# Adopted from @web[securitygroup]
+vpc_main = create vpc cidr=10.0.0.0/16
+create tag resource=$vpc_main key=Name value="main 2"
+securitygroup_web = create securitygroup name=web vpc=$vpc_main description="web servers"
+update securitygroup id=$securitygroup_web inbound=authorize protocol=tcp cidr=0.0.0.0/0 portrange=443
+`
+	if got, want := tpl, expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+
+	subnet.Properties["VpcId"] = "vpc_2"
+	tpl, err = AdoptTemplate(g, subnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `# Adopted from sub_1[subnet]
+subnet = create subnet cidr=10.0.1.0/24 vpc=vpc_2 zone=eu-west-1a
+`
+	if got, want := tpl, expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err = AdoptTemplate(g, defaultVpc); err == nil {
+		t.Fatal("expected error when adopting a default vpc")
+	}
+}
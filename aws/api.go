@@ -21,9 +21,11 @@ import (
 	"regexp"
 	"sort"
 	"sync"
+	"time"
 
 	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
@@ -60,6 +62,8 @@ type Security interface {
 	stsiface.STSAPI
 	GetUserId() (string, error)
 	GetAccountId() (string, error)
+	CredentialsSource() (string, error)
+	SessionExpiry() (time.Time, bool)
 }
 
 type oncer struct {
@@ -70,10 +74,12 @@ type oncer struct {
 
 type security struct {
 	stsiface.STSAPI
+	creds       *credentials.Credentials
+	retrievedAt time.Time
 }
 
 func NewSecu(sess *session.Session) Security {
-	return &security{sts.New(sess)}
+	return &security{STSAPI: sts.New(sess), creds: sess.Config.Credentials, retrievedAt: time.Now()}
 }
 
 func (s *security) GetUserId() (string, error) {
@@ -92,10 +98,7 @@ func (s *security) GetAccountId() (string, error) {
 	return awssdk.StringValue(output.Account), nil
 }
 
-func (s *Access) fetch_all_user_graph() (*graph.Graph, []*iam.UserDetail, error) {
-	g := graph.NewGraph()
-	var userDetails []*iam.UserDetail
-
+func (s *Access) fetch_all_user_graph(g *graph.Graph, each func(interface{}) error) error {
 	var wg sync.WaitGroup
 	errc := make(chan error)
 
@@ -110,13 +113,9 @@ func (s *Access) fetch_all_user_graph() (*graph.Graph, []*iam.UserDetail, error)
 		},
 			func(out *iam.GetAccountAuthorizationDetailsOutput, lastPage bool) (shouldContinue bool) {
 				for _, output := range out.UserDetailList {
-					userDetails = append(userDetails, output)
-					var res *graph.Resource
-					res, badResErr = newResource(output)
-					if badResErr != nil {
+					if badResErr = addFetchedResource(g, output, each); badResErr != nil {
 						return false
 					}
-					g.AddResource(res)
 				}
 				return out.Marker != nil
 			})
@@ -125,7 +124,7 @@ func (s *Access) fetch_all_user_graph() (*graph.Graph, []*iam.UserDetail, error)
 			return
 		}
 		if badResErr != nil {
-			errc <- err
+			errc <- badResErr
 			return
 		}
 	}()
@@ -136,11 +135,9 @@ func (s *Access) fetch_all_user_graph() (*graph.Graph, []*iam.UserDetail, error)
 
 		err := s.ListUsersPages(&iam.ListUsersInput{}, func(page *iam.ListUsersOutput, lastPage bool) bool {
 			for _, user := range page.Users {
-				res, badResErr := newResource(user)
-				if badResErr != nil {
+				if badResErr := addFetchedResource(g, user, nil); badResErr != nil {
 					return false
 				}
-				g.AddResource(res)
 			}
 			return page.Marker != nil
 		})
@@ -156,66 +153,57 @@ func (s *Access) fetch_all_user_graph() (*graph.Graph, []*iam.UserDetail, error)
 
 	for err := range errc {
 		if err != nil {
-			return g, userDetails, err
+			return err
 		}
 	}
 
-	return g, userDetails, nil
+	return nil
 }
 
 // STORAGE
 
-func (s *Storage) fetch_all_bucket_graph() (*graph.Graph, []*s3.Bucket, error) {
-	g := graph.NewGraph()
-	var buckets []*s3.Bucket
-	bucketM := &sync.Mutex{}
-
-	err := s.foreach_bucket_parallel(func(b *s3.Bucket) error {
-		bucketM.Lock()
-		buckets = append(buckets, b)
-		bucketM.Unlock()
-		res, err := newResource(b)
-		g.AddResource(res)
-		if err != nil {
+func (s *Storage) fetch_all_bucket_graph(g *graph.Graph, each func(interface{}) error) error {
+	return s.foreach_bucket_parallel(func(b *s3.Bucket) error {
+		if err := addFetchedResource(g, b, each); err != nil {
 			return fmt.Errorf("build resource for bucket `%s`: %s", awssdk.StringValue(b.Name), err)
 		}
 		return nil
 	})
-	return g, buckets, err
 }
 
-func (s *Storage) fetch_all_storageobject_graph() (*graph.Graph, []*s3.Object, error) {
-	g := graph.NewGraph()
-	var cloudResources []*s3.Object
-
-	err := s.foreach_bucket_parallel(func(b *s3.Bucket) error {
-		return s.fetchObjectsForBucket(b, g)
+func (s *Storage) fetch_all_storageobject_graph(g *graph.Graph, each func(interface{}) error) error {
+	return s.foreach_bucket_parallel(func(b *s3.Bucket) error {
+		return s.fetchObjectsForBucket(b, g, each)
 	})
-
-	return g, cloudResources, err
 }
 
-func (s *Storage) fetchObjectsForBucket(bucket *s3.Bucket, g *graph.Graph) error {
-	out, err := s.ListObjects(&s3.ListObjectsInput{Bucket: bucket.Name})
+func (s *Storage) fetchObjectsForBucket(bucket *s3.Bucket, g *graph.Graph, each func(interface{}) error) error {
+	parent, err := initResource(bucket)
 	if err != nil {
 		return err
 	}
-
-	for _, output := range out.Contents {
-		res, err := newResource(output)
-		if err != nil {
-			return err
-		}
-		res.Properties["BucketName"] = awssdk.StringValue(bucket.Name)
-		g.AddResource(res)
-		parent, err := initResource(bucket)
-		if err != nil {
-			return err
+	var badResErr error
+	err = s.ListObjectsPages(&s3.ListObjectsInput{Bucket: bucket.Name}, func(out *s3.ListObjectsOutput, lastPage bool) bool {
+		for _, output := range out.Contents {
+			var res *graph.Resource
+			if res, badResErr = newResource(output); badResErr != nil {
+				return false
+			}
+			res.Properties["BucketName"] = awssdk.StringValue(bucket.Name)
+			g.AddResource(res)
+			g.AddParentRelation(parent, res)
+			if each != nil {
+				if badResErr = each(output); badResErr != nil {
+					return false
+				}
+			}
 		}
-		g.AddParentRelation(parent, res)
+		return !lastPage
+	})
+	if err != nil {
+		return err
 	}
-
-	return nil
+	return badResErr
 }
 
 func (s *Storage) getBucketsPerRegion() ([]*s3.Bucket, error) {
@@ -229,11 +217,14 @@ func (s *Storage) getBucketsPerRegion() ([]*s3.Bucket, error) {
 	errc := make(chan error)
 
 	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentRequests)
 
 	for _, bucket := range out.Buckets {
 		wg.Add(1)
 		go func(b *s3.Bucket) {
 			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 			loc, err := s.GetBucketLocation(&s3.GetBucketLocationInput{Bucket: b.Name})
 			if err != nil {
 				errc <- err
@@ -280,11 +271,14 @@ func (s *Storage) foreach_bucket_parallel(f func(b *s3.Bucket) error) error {
 
 	errc := make(chan error)
 	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentRequests)
 
 	for _, output := range buckets {
 		wg.Add(1)
 		go func(b *s3.Bucket) {
 			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 			if err := f(b); err != nil {
 				errc <- err
 			}
@@ -306,21 +300,21 @@ func (s *Storage) foreach_bucket_parallel(f func(b *s3.Bucket) error) error {
 
 // QUEUE
 
-func (s *Queue) fetch_all_queue_graph() (*graph.Graph, []*string, error) {
-	g := graph.NewGraph()
-	var cloudResources []*string
+func (s *Queue) fetch_all_queue_graph(g *graph.Graph, each func(interface{}) error) error {
 	out, err := s.ListQueues(&sqs.ListQueuesInput{})
 	if err != nil {
-		return nil, cloudResources, err
+		return err
 	}
 	errc := make(chan error)
 	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentRequests)
 
 	for _, output := range out.QueueUrls {
-		cloudResources = append(cloudResources, output)
 		wg.Add(1)
 		go func(url *string) {
 			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 			res := graph.InitResource(awssdk.StringValue(url), graph.Queue)
 			res.Properties["Id"] = awssdk.StringValue(url)
 			attrs, err := s.GetQueueAttributes(&sqs.GetQueueAttributesInput{AttributeNames: []*string{awssdk.String("All")}, QueueUrl: url})
@@ -335,6 +329,11 @@ func (s *Queue) fetch_all_queue_graph() (*graph.Graph, []*string, error) {
 				res.Properties[k] = awssdk.StringValue(v)
 			}
 			g.AddResource(res)
+			if each != nil {
+				if err := each(url); err != nil {
+					errc <- err
+				}
+			}
 		}(output)
 
 	}
@@ -346,10 +345,9 @@ func (s *Queue) fetch_all_queue_graph() (*graph.Graph, []*string, error) {
 
 	for err := range errc {
 		if err != nil {
-			return g, cloudResources, err
+			return err
 		}
 	}
 
-	return g, cloudResources, nil
-
+	return nil
 }
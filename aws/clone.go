@@ -0,0 +1,183 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/wallix/awless/graph"
+)
+
+var templateStringValueRegex = regexp.MustCompile("^[a-zA-Z0-9-._:/]+$")
+
+// TemplateFrom returns the template statements needed to create a resource
+// configured like the given one. Values that are specific to the original
+// resource (names, ...) or that cannot be expressed in a template are left as holes
+func TemplateFrom(res *graph.Resource) (string, error) {
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "# Cloned from %s\n", res)
+
+	props := res.Properties
+	entity := res.Type().String()
+	switch res.Type() {
+	case graph.Instance:
+		stmt := newCloneStatement("create", entity)
+		stmt.param("image", props["ImageId"])
+		stmt.param("type", props["Type"])
+		stmt.rawParam("count", "1")
+		stmt.param("subnet", props["SubnetId"])
+		stmt.param("key", props["KeyName"])
+		groups := stringValues(props["SecurityGroups"])
+		if len(groups) > 0 {
+			stmt.param("group", groups[0])
+		}
+		stmt.hole("name")
+		if len(groups) > 1 {
+			fmt.Fprintf(&buff, "# Only the first security group can be given at creation. Original groups: %s\n", strings.Join(groups, ", "))
+		}
+		if profile, ok := props["Profile"]; ok {
+			fmt.Fprintf(&buff, "# Original instance profile (not set by this template): %v\n", profile)
+		}
+		writeTagsComment(&buff, props["Tags"])
+		buff.WriteString(stmt.String())
+	case graph.SecurityGroup:
+		stmt := newCloneStatement("create", entity)
+		stmt.hole("name")
+		stmt.param("vpc", props["VpcId"])
+		stmt.paramOrHole("description", props["Description"])
+		fmt.Fprintf(&buff, "%s = %s", entity, stmt)
+		writeRulesStatements(&buff, entity, "inbound", props["InboundRules"])
+		writeRulesStatements(&buff, entity, "outbound", props["OutboundRules"])
+	case graph.Volume:
+		stmt := newCloneStatement("create", entity)
+		stmt.param("zone", props["AvailabilityZone"])
+		stmt.param("size", props["Size"])
+		writeTagsComment(&buff, props["Tags"])
+		buff.WriteString(stmt.String())
+	case graph.Subnet:
+		stmt := newCloneStatement("create", entity)
+		stmt.hole("cidr")
+		stmt.param("vpc", props["VpcId"])
+		stmt.param("zone", props["AvailabilityZone"])
+		fmt.Fprintf(&buff, "# Original cidr: %v\n", props["CidrBlock"])
+		buff.WriteString(stmt.String())
+	case graph.Vpc:
+		stmt := newCloneStatement("create", entity)
+		stmt.param("cidr", props["CidrBlock"])
+		buff.WriteString(stmt.String())
+	case graph.Bucket, graph.Queue, graph.Topic, graph.User, graph.Group:
+		stmt := newCloneStatement("create", entity)
+		stmt.hole("name")
+		buff.WriteString(stmt.String())
+	default:
+		return "", fmt.Errorf("cannot generate a template from a %s", entity)
+	}
+
+	return buff.String(), nil
+}
+
+type cloneStatement struct {
+	action, entity string
+	params         []string
+}
+
+func newCloneStatement(action, entity string) *cloneStatement {
+	return &cloneStatement{action: action, entity: entity}
+}
+
+func (s *cloneStatement) rawParam(key, value string) {
+	s.params = append(s.params, fmt.Sprintf("%s=%s", key, value))
+}
+
+func (s *cloneStatement) param(key string, value interface{}) {
+	if value == nil || fmt.Sprint(value) == "" {
+		return
+	}
+	s.rawParam(key, fmt.Sprint(value))
+}
+
+func (s *cloneStatement) hole(key string) {
+	s.rawParam(key, fmt.Sprintf("{%s.%s}", s.entity, key))
+}
+
+func (s *cloneStatement) paramOrHole(key string, value interface{}) {
+	if value != nil && templateStringValueRegex.MatchString(fmt.Sprint(value)) {
+		s.param(key, value)
+	} else {
+		s.hole(key)
+	}
+}
+
+func (s *cloneStatement) String() string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", s.action, s.entity, strings.Join(s.params, " "))) + "\n"
+}
+
+func writeRulesStatements(buff *bytes.Buffer, ref, direction string, prop interface{}) {
+	rules, _ := prop.([]*graph.FirewallRule)
+	for _, rule := range rules {
+		if len(rule.IPRanges) == 0 {
+			fmt.Fprintf(buff, "# Skipped %s rule without ip range (referencing other groups?): %s\n", direction, rule)
+			continue
+		}
+		portrange := "any"
+		if !rule.PortRange.Any {
+			portrange = fmt.Sprint(rule.PortRange.FromPort)
+			if rule.PortRange.ToPort != rule.PortRange.FromPort {
+				portrange = fmt.Sprintf("%d-%d", rule.PortRange.FromPort, rule.PortRange.ToPort)
+			}
+		}
+		for _, r := range rule.IPRanges {
+			if direction == "outbound" && rule.Protocol == "any" && isAllTrafficRange(r.String()) {
+				continue // created by default with any security group
+			}
+			stmt := newCloneStatement("update", "securitygroup")
+			stmt.rawParam("id", "$"+ref)
+			stmt.rawParam(direction, "authorize")
+			stmt.param("protocol", rule.Protocol)
+			stmt.param("cidr", r.String())
+			stmt.rawParam("portrange", portrange)
+			buff.WriteString(stmt.String())
+		}
+	}
+}
+
+func isAllTrafficRange(cidr string) bool {
+	return cidr == "0.0.0.0/0" || cidr == "::/0"
+}
+
+func writeTagsComment(buff *bytes.Buffer, prop interface{}) {
+	if tags := stringValues(prop); len(tags) > 0 {
+		fmt.Fprintf(buff, "# Original tags: %s\n", strings.Join(tags, ", "))
+	}
+}
+
+func stringValues(prop interface{}) []string {
+	switch v := prop.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		var values []string
+		for _, e := range v {
+			values = append(values, fmt.Sprint(e))
+		}
+		return values
+	}
+	return nil
+}
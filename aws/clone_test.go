@@ -0,0 +1,87 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"net"
+	"testing"
+
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template"
+)
+
+func TestTemplateFrom(t *testing.T) {
+	inst := graph.InitResource("inst_1", graph.Instance)
+	inst.Properties["Id"] = "inst_1"
+	inst.Properties["Name"] = "web"
+	inst.Properties["ImageId"] = "ami-123"
+	inst.Properties["Type"] = "t2.micro"
+	inst.Properties["SubnetId"] = "sub_1"
+	inst.Properties["KeyName"] = "my-key"
+	inst.Properties["SecurityGroups"] = []interface{}{"sg_1", "sg_2"}
+	inst.Properties["Tags"] = []interface{}{"Name=web", "Env=prod"}
+
+	tpl, err := TemplateFrom(inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# Cloned from @web[instance]
+# Only the first security group can be given at creation. Original groups: sg_1, sg_2
+# Original tags: Name=web, Env=prod
+create instance image=ami-123 type=t2.micro count=1 subnet=sub_1 key=my-key group=sg_1 name={instance.name}
+`
+	if got, want := tpl, expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+
+	_, anywhere, _ := net.ParseCIDR("0.0.0.0/0")
+	_, office, _ := net.ParseCIDR("10.0.0.0/24")
+	sg := graph.InitResource("sg_1", graph.SecurityGroup)
+	sg.Properties["Id"] = "sg_1"
+	sg.Properties["VpcId"] = "vpc_1"
+	sg.Properties["Description"] = "web servers"
+	sg.Properties["InboundRules"] = []*graph.FirewallRule{
+		{Protocol: "tcp", PortRange: graph.PortRange{FromPort: 443, ToPort: 443}, IPRanges: []*net.IPNet{anywhere}},
+		{Protocol: "tcp", PortRange: graph.PortRange{FromPort: 8000, ToPort: 8080}, IPRanges: []*net.IPNet{office}},
+		{Protocol: "tcp", PortRange: graph.PortRange{FromPort: 22, ToPort: 22}},
+	}
+	sg.Properties["OutboundRules"] = []*graph.FirewallRule{
+		{Protocol: "any", PortRange: graph.PortRange{Any: true}, IPRanges: []*net.IPNet{anywhere}},
+	}
+
+	tpl, err = TemplateFrom(sg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `# Cloned from sg_1[securitygroup]
+securitygroup = create securitygroup name={securitygroup.name} vpc=vpc_1 description={securitygroup.description}
+update securitygroup id=$securitygroup inbound=authorize protocol=tcp cidr=0.0.0.0/0 portrange=443
+update securitygroup id=$securitygroup inbound=authorize protocol=tcp cidr=10.0.0.0/24 portrange=8000-8080
+# Skipped inbound rule without ip range (referencing other groups?): PortRange:{FromPort:22 ToPort:22 Any:false}; Protocol:tcp; IPRanges:[]
+`
+	if got, want := tpl, expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err = template.Parse(tpl); err != nil {
+		t.Fatalf("generated template should parse: %s", err)
+	}
+
+	if _, err = TemplateFrom(graph.InitResource("az_1", graph.AvailabilityZone)); err == nil {
+		t.Fatal("expected error for unsupported type")
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// The CloudTrail SDK package is not vendored: LookupEvents is declared here
+// on top of the JSON RPC protocol (see jsonrpc.go)
+
+const (
+	cloudtrailServiceName = "cloudtrail"
+	cloudtrailTarget      = "com.amazonaws.cloudtrail.v20131101.CloudTrail_20131101"
+
+	// maxTrailPages bounds the events scanned to resolve actors (50 events per page)
+	maxTrailPages = 20
+)
+
+type CloudTrail struct {
+	api *trailClient
+}
+
+func NewCloudTrail(sess *session.Session) *CloudTrail {
+	return &CloudTrail{api: newTrailClient(sess)}
+}
+
+// Actors returns, per resource name (usually its id), the user name of the last
+// write event recorded by CloudTrail on the resource since the given time
+func (t *CloudTrail) Actors(since time.Time) (map[string]string, error) {
+	actors := make(map[string]string)
+	input := &lookupEventsInput{
+		LookupAttributes: []*trailLookupAttribute{{AttributeKey: "ReadOnly", AttributeValue: "false"}},
+		StartTime:        since.Unix(),
+		MaxResults:       50,
+	}
+	for page := 0; page < maxTrailPages; page++ {
+		out, err := t.api.LookupEvents(input)
+		if err != nil {
+			return actors, err
+		}
+		// events are listed from the most recent
+		for _, event := range out.Events {
+			for _, res := range event.Resources {
+				if _, done := actors[res.ResourceName]; !done && res.ResourceName != "" && event.Username != "" {
+					actors[res.ResourceName] = event.Username
+				}
+			}
+		}
+		if out.NextToken == "" {
+			break
+		}
+		input.NextToken = out.NextToken
+	}
+	return actors, nil
+}
+
+type trailClient struct {
+	*JSONRPCClient
+}
+
+func newTrailClient(sess *session.Session) *trailClient {
+	return &trailClient{NewJSONRPCClient(sess, cloudtrailServiceName, "2013-11-01", "1.1", cloudtrailTarget)}
+}
+
+func (c *trailClient) LookupEvents(input *lookupEventsInput) (*lookupEventsOutput, error) {
+	output := &lookupEventsOutput{}
+	return output, c.Call("LookupEvents", input, output)
+}
+
+type lookupEventsInput struct {
+	LookupAttributes []*trailLookupAttribute `json:",omitempty"`
+	StartTime        int64                   `json:",omitempty"`
+	MaxResults       int64                   `json:",omitempty"`
+	NextToken        string                  `json:",omitempty"`
+}
+
+type trailLookupAttribute struct {
+	AttributeKey   string
+	AttributeValue string
+}
+
+type lookupEventsOutput struct {
+	Events    []*trailEvent
+	NextToken string
+}
+
+type trailEvent struct {
+	EventId   string
+	EventName string
+	EventTime float64
+	Username  string
+	Resources []*trailResource
+}
+
+type trailResource struct {
+	ResourceType string
+	ResourceName string
+}
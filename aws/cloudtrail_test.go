@@ -0,0 +1,98 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestCloudTrailActors(t *testing.T) {
+	since := time.Date(2017, 7, 14, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("X-Amz-Target"), "com.amazonaws.cloudtrail.v20131101.CloudTrail_20131101.LookupEvents"; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		var input lookupEventsInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := input.StartTime, since.Unix(); got != want {
+			t.Errorf("got %d, want %d", got, want)
+		}
+		if got, want := input.LookupAttributes[0].AttributeKey, "ReadOnly"; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		if input.NextToken == "" {
+			w.Write([]byte(`{"NextToken":"page2","Events":[
+				{"EventName":"ModifyInstanceAttribute","EventTime":1.500001E9,"Username":"bob","Resources":[{"ResourceType":"AWS::EC2::Instance","ResourceName":"i-1"}]},
+				{"EventName":"RunInstances","EventTime":1.5E9,"Username":"alice","Resources":[{"ResourceType":"AWS::EC2::Instance","ResourceName":"i-1"},{"ResourceType":"AWS::EC2::Subnet","ResourceName":"sub-1"}]}
+			]}`))
+		} else {
+			w.Write([]byte(`{"Events":[{"EventName":"DeleteUser","Username":"root","Resources":[{"ResourceType":"AWS::IAM::User","ResourceName":"john"}]}]}`))
+		}
+	}))
+	defer server.Close()
+
+	trail := NewCloudTrail(testSession(server.URL))
+	actors, err := trail.Actors(since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := actors, map[string]string{"i-1": "bob", "sub-1": "alice", "john": "root"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestCloudTrailError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"com.amazonaws.cloudtrail#InvalidLookupAttributesException","message":"invalid attribute"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewCloudTrail(testSession(server.URL)).Actors(time.Now())
+	aerr, ok := err.(awserr.RequestFailure)
+	if !ok {
+		t.Fatalf("expected request failure, got %#v", err)
+	}
+	if got, want := aerr.Code(), "InvalidLookupAttributesException"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if !strings.Contains(aerr.Message(), "invalid attribute") {
+		t.Fatalf("unexpected message %s", aerr.Message())
+	}
+}
+
+func testSession(endpoint string) *session.Session {
+	return session.Must(session.NewSession(&awssdk.Config{
+		Region:      awssdk.String("eu-west-1"),
+		Endpoint:    awssdk.String(endpoint),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+		MaxRetries:  awssdk.Int(0),
+	}))
+}
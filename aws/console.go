@@ -0,0 +1,104 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/wallix/awless/graph"
+)
+
+const consoleBaseURL = "https://console.aws.amazon.com"
+
+// consoleURLFor returns the console of the partition of a region (China and GovCloud have their own)
+func consoleURLFor(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "https://console.amazonaws.cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "https://console.amazonaws-us-gov.com"
+	default:
+		return consoleBaseURL
+	}
+}
+
+// ConsoleHomeURL returns the AWS web console home page for a region
+func ConsoleHomeURL(region string) string {
+	return fmt.Sprintf("%s/console/home?region=%s", consoleURLFor(region), region)
+}
+
+// ConsoleURL returns the AWS web console page of a resource
+func ConsoleURL(res *graph.Resource, region string) (string, error) {
+	id := url.QueryEscape(res.Id())
+	name := id
+	if n, ok := res.Properties["Name"]; ok && fmt.Sprint(n) != "" {
+		name = url.QueryEscape(fmt.Sprint(n))
+	}
+	base := consoleURLFor(region)
+	ec2 := fmt.Sprintf("%s/ec2/v2/home?region=%s", base, region)
+	vpc := fmt.Sprintf("%s/vpc/home?region=%s", base, region)
+	iam := fmt.Sprintf("%s/iam/home", base)
+
+	switch res.Type() {
+	case graph.Instance:
+		return fmt.Sprintf("%s#Instances:instanceId=%s", ec2, id), nil
+	case graph.SecurityGroup:
+		return fmt.Sprintf("%s#SecurityGroups:groupId=%s", ec2, id), nil
+	case graph.Volume:
+		return fmt.Sprintf("%s#Volumes:volumeId=%s", ec2, id), nil
+	case graph.Keypair:
+		return fmt.Sprintf("%s#KeyPairs:keyName=%s", ec2, id), nil
+	case graph.LoadBalancer:
+		return fmt.Sprintf("%s#LoadBalancers:search=%s", ec2, name), nil
+	case graph.TargetGroup:
+		return fmt.Sprintf("%s#TargetGroups:search=%s", ec2, name), nil
+	case graph.Vpc:
+		return fmt.Sprintf("%s#vpcs:filter=%s", vpc, id), nil
+	case graph.Subnet:
+		return fmt.Sprintf("%s#subnets:filter=%s", vpc, id), nil
+	case graph.InternetGateway:
+		return fmt.Sprintf("%s#igws:filter=%s", vpc, id), nil
+	case graph.RouteTable:
+		return fmt.Sprintf("%s#routetables:filter=%s", vpc, id), nil
+	case graph.User:
+		return fmt.Sprintf("%s#/users/%s", iam, name), nil
+	case graph.Group:
+		return fmt.Sprintf("%s#/groups/%s", iam, name), nil
+	case graph.Role:
+		return fmt.Sprintf("%s#/roles/%s", iam, name), nil
+	case graph.Policy:
+		if arn, ok := res.Properties["Arn"]; ok {
+			return fmt.Sprintf("%s#/policies/%s", iam, fmt.Sprint(arn)), nil
+		}
+		return fmt.Sprintf("%s#/policies", iam), nil
+	case graph.Bucket:
+		if base != consoleBaseURL {
+			return fmt.Sprintf("%s/s3/buckets/%s/?region=%s", base, id, region), nil
+		}
+		return fmt.Sprintf("https://s3.console.aws.amazon.com/s3/buckets/%s/?region=%s", id, region), nil
+	case graph.Topic:
+		return fmt.Sprintf("%s/sns/v2/home?region=%s#/topics/%s", base, region, res.Id()), nil
+	case graph.Subscription:
+		return fmt.Sprintf("%s/sns/v2/home?region=%s#/subscriptions", base, region), nil
+	case graph.Queue:
+		return fmt.Sprintf("%s/sqs/home?region=%s#queue-browser:selected=%s;prefix=", base, region, res.Id()), nil
+	default:
+		return "", fmt.Errorf("no console page for %s resources", res.Type())
+	}
+}
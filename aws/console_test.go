@@ -0,0 +1,62 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/wallix/awless/graph"
+)
+
+func TestConsoleURL(t *testing.T) {
+	inst := graph.InitResource("i-0abc123", graph.Instance)
+	user := graph.InitResource("AIDA123", graph.User)
+	user.Properties["Name"] = "john"
+	bucket := graph.InitResource("my-bucket", graph.Bucket)
+	zone := graph.InitResource("eu-west-1a", graph.AvailabilityZone)
+
+	tcases := []struct {
+		res  *graph.Resource
+		want string
+	}{
+		{inst, "https://console.aws.amazon.com/ec2/v2/home?region=eu-west-1#Instances:instanceId=i-0abc123"},
+		{user, "https://console.aws.amazon.com/iam/home#/users/john"},
+		{bucket, "https://s3.console.aws.amazon.com/s3/buckets/my-bucket/?region=eu-west-1"},
+	}
+	for _, tcase := range tcases {
+		got, err := ConsoleURL(tcase.res, "eu-west-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := tcase.want; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	}
+
+	if _, err := ConsoleURL(zone, "eu-west-1"); err == nil {
+		t.Fatal("expected error got none")
+	}
+
+	if got, err := ConsoleURL(bucket, "cn-north-1"); err != nil {
+		t.Fatal(err)
+	} else if want := "https://console.amazonaws.cn/s3/buckets/my-bucket/?region=cn-north-1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := ConsoleHomeURL("us-gov-west-1"), "https://console.amazonaws-us-gov.com/console/home?region=us-gov-west-1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
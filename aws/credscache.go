@@ -0,0 +1,139 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	gosync "sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/wallix/awless/statecrypt"
+)
+
+// CredentialsCacheKey is the config key to disable the cache of assumed roles credentials
+const CredentialsCacheKey = "aws.credentials.cache"
+
+// CredentialsCacheDir holds the credentials of assumed roles across invocations.
+// Empty disables the cache
+var CredentialsCacheDir string
+
+// credentials are renewed this long before they expire
+const cacheExpiryWindow = 5 * time.Minute
+
+var (
+	expirationsMu gosync.Mutex
+	// expiration of the temporary credentials retrieved, per access key
+	expirations = make(map[string]time.Time)
+)
+
+type cachedCredentials struct {
+	Profile, RoleArn                           string
+	AccessKeyID, SecretAccessKey, SessionToken string
+	Expiration                                 time.Time
+}
+
+// cachedProvider serves the credentials of a role from the disk cache while they are valid,
+// so that consecutive commands neither call STS nor prompt for MFA again
+type cachedProvider struct {
+	credentials.Provider
+	dir, profile, roleArn, scope string
+	duration                     time.Duration
+
+	expiration time.Time
+}
+
+func newCachedProvider(provider credentials.Provider, dir, profile, scope string, role *AssumeRole) *cachedProvider {
+	duration := role.Duration
+	if duration == 0 {
+		duration = stscreds.DefaultDuration
+	}
+	return &cachedProvider{Provider: provider, dir: dir, profile: profile, roleArn: role.Arn, scope: scope, duration: duration}
+}
+
+func (p *cachedProvider) Retrieve() (credentials.Value, error) {
+	path := p.path()
+	if data, err := statecrypt.ReadFile(path); err == nil {
+		var cached cachedCredentials
+		if err := json.Unmarshal(data, &cached); err == nil && time.Now().Add(cacheExpiryWindow).Before(cached.Expiration) {
+			p.expiration = cached.Expiration
+			setExpiration(cached.AccessKeyID, cached.Expiration)
+			return credentials.Value{
+				AccessKeyID:     cached.AccessKeyID,
+				SecretAccessKey: cached.SecretAccessKey,
+				SessionToken:    cached.SessionToken,
+				ProviderName:    stscreds.ProviderName,
+			}, nil
+		}
+	}
+
+	start := time.Now()
+	val, err := p.Provider.Retrieve()
+	if err != nil {
+		return val, err
+	}
+	p.expiration = start.Add(p.duration)
+	setExpiration(val.AccessKeyID, p.expiration)
+
+	cached := cachedCredentials{
+		Profile: p.profile, RoleArn: p.roleArn,
+		AccessKeyID: val.AccessKeyID, SecretAccessKey: val.SecretAccessKey, SessionToken: val.SessionToken,
+		Expiration: p.expiration,
+	}
+	if data, err := json.Marshal(cached); err == nil {
+		if err := os.MkdirAll(p.dir, 0700); err == nil {
+			statecrypt.WriteFile(path, data, 0600)
+		}
+	}
+	return val, nil
+}
+
+func (p *cachedProvider) IsExpired() bool {
+	return time.Now().Add(cacheExpiryWindow).After(p.expiration)
+}
+
+// path is unique per profile and chain of roles (with their external ID and MFA device)
+func (p *cachedProvider) path() string {
+	sum := sha256.Sum256([]byte(p.profile + "\n" + p.scope))
+	return filepath.Join(p.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// ClearCredentialsCache removes all the cached credentials
+func ClearCredentialsCache() error {
+	if CredentialsCacheDir == "" {
+		return nil
+	}
+	return os.RemoveAll(CredentialsCacheDir)
+}
+
+func setExpiration(accessKey string, expiration time.Time) {
+	expirationsMu.Lock()
+	defer expirationsMu.Unlock()
+	expirations[accessKey] = expiration
+}
+
+func credentialsExpiration(accessKey string) (time.Time, bool) {
+	expirationsMu.Lock()
+	defer expirationsMu.Unlock()
+	exp, ok := expirations[accessKey]
+	return exp, ok
+}
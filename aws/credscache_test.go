@@ -0,0 +1,91 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+type countingProvider struct {
+	calls int
+}
+
+func (p *countingProvider) Retrieve() (credentials.Value, error) {
+	p.calls++
+	return credentials.Value{AccessKeyID: fmt.Sprintf("ASIA%d", p.calls), SecretAccessKey: "secret", SessionToken: "token", ProviderName: "AssumeRoleProvider"}, nil
+}
+
+func (p *countingProvider) IsExpired() bool { return true }
+
+func TestCachedProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-credscache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sts := &countingProvider{}
+	role := &AssumeRole{Arn: "arn:aws:iam::123456789012:role/admin", Duration: time.Hour}
+	newCreds := func(profile string, role *AssumeRole) *credentials.Credentials {
+		return credentials.NewCredentials(newCachedProvider(sts, dir, profile, role.scope(), role))
+	}
+
+	first, err := newCreds("prod", role).Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// next invocation of awless
+	second, err := newCreds("prod", role).Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sts.calls, 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := second.AccessKeyID, first.AccessKeyID; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := second.ProviderName, "AssumeRoleProvider"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if exp, ok := credentialsExpiration(second.AccessKeyID); !ok || exp.Before(time.Now().Add(50*time.Minute)) {
+		t.Fatalf("got expiration %s (%t), want about one hour", exp, ok)
+	}
+
+	if _, err := newCreds("staging", role).Get(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newCreds("prod", &AssumeRole{Arn: role.Arn, ExternalID: "other", Duration: time.Hour}).Get(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sts.calls, 3; got != want {
+		t.Fatalf("credentials of other profiles or roles: got %d calls, want %d", got, want)
+	}
+
+	short := &AssumeRole{Arn: "arn:aws:iam::123456789012:role/short", Duration: 2 * time.Minute}
+	newCreds("prod", short).Get()
+	newCreds("prod", short).Get()
+	if got, want := sts.calls, 5; got != want {
+		t.Fatalf("credentials expiring soon: got %d calls, want %d", got, want)
+	}
+}
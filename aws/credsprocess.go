@@ -0,0 +1,107 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+const ProcessProviderName = "ProcessProvider"
+
+// ProcessProvider retrieves credentials from the JSON output of the `credential_process`
+// of a profile, as external helpers (vault based brokers, SSO tools, ...) print them
+type ProcessProvider struct {
+	Command string
+
+	retrieved  bool
+	expiration time.Time
+	run        func(command string) ([]byte, error)
+}
+
+func NewProcessCredentials(command string) *credentials.Credentials {
+	return credentials.NewCredentials(&ProcessProvider{Command: command, run: runCredentialProcess})
+}
+
+type processOutput struct {
+	Version         int
+	AccessKeyId     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      *time.Time
+}
+
+func (p *ProcessProvider) Retrieve() (credentials.Value, error) {
+	value := credentials.Value{ProviderName: ProcessProviderName}
+	out, err := p.run(p.Command)
+	if err != nil {
+		return value, fmt.Errorf("credential_process '%s': %s", p.Command, err)
+	}
+	var creds processOutput
+	if err := json.Unmarshal(out, &creds); err != nil {
+		return value, fmt.Errorf("credential_process '%s': invalid output: %s", p.Command, err)
+	}
+	if creds.Version != 1 {
+		return value, fmt.Errorf("credential_process '%s': unsupported version %d, expecting 1", p.Command, creds.Version)
+	}
+	if creds.AccessKeyId == "" || creds.SecretAccessKey == "" {
+		return value, fmt.Errorf("credential_process '%s': missing AccessKeyId or SecretAccessKey", p.Command)
+	}
+	p.retrieved = true
+	p.expiration = time.Time{}
+	if creds.Expiration != nil {
+		p.expiration = *creds.Expiration
+	}
+	value.AccessKeyID, value.SecretAccessKey, value.SessionToken = creds.AccessKeyId, creds.SecretAccessKey, creds.SessionToken
+	return value, nil
+}
+
+// IsExpired reports credentials without expiration as always valid once retrieved.
+// Others are refreshed a minute before they expire
+func (p *ProcessProvider) IsExpired() bool {
+	if !p.retrieved {
+		return true
+	}
+	if p.expiration.IsZero() {
+		return false
+	}
+	return time.Now().After(p.expiration.Add(-time.Minute))
+}
+
+// runCredentialProcess runs the command through the shell as the AWS CLI does. The stderr
+// and stdin of the helper are the ones of awless so that it can prompt (ex: for MFA)
+func runCredentialProcess(command string) ([]byte, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	var stdout bytes.Buffer
+	cmd.Stdout, cmd.Stderr, cmd.Stdin = &stdout, os.Stderr, os.Stdin
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(stdout.Bytes()), nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+func TestProcessProvider(t *testing.T) {
+	var runs int
+	output := `{"Version": 1, "AccessKeyId": "AKID", "SecretAccessKey": "secret", "SessionToken": "token", "Expiration": "%s"}`
+	expiration := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	provider := &ProcessProvider{Command: "broker", run: func(command string) ([]byte, error) {
+		runs++
+		if got, want := command, "broker"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		return []byte(strings.Replace(output, "%s", expiration, 1)), nil
+	}}
+	creds := credentials.NewCredentials(provider)
+
+	val, err := creds.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := val, (credentials.Value{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "token", ProviderName: ProcessProviderName}); got != want {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+	if _, err = creds.Get(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := runs, 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+
+	expiration = time.Now().Add(30 * time.Second).UTC().Format(time.RFC3339)
+	creds.Expire()
+	if _, err = creds.Get(); err != nil {
+		t.Fatal(err)
+	}
+	if !provider.IsExpired() {
+		t.Fatal("expected credentials expiring within a minute to be refreshed")
+	}
+
+	tcases := []struct {
+		out string
+		err error
+		msg string
+	}{
+		{err: errors.New("exit status 1"), msg: "exit status 1"},
+		{out: "not json", msg: "invalid output"},
+		{out: `{"Version": 2, "AccessKeyId": "AKID", "SecretAccessKey": "secret"}`, msg: "unsupported version 2"},
+		{out: `{"Version": 1, "AccessKeyId": "AKID"}`, msg: "missing"},
+	}
+	for _, tcase := range tcases {
+		p := &ProcessProvider{Command: "broker", run: func(string) ([]byte, error) { return []byte(tcase.out), tcase.err }}
+		if _, err := p.Retrieve(); err == nil || !strings.Contains(err.Error(), tcase.msg) {
+			t.Fatalf("got %v, want error containing %q", err, tcase.msg)
+		}
+	}
+}
+
+func TestRunCredentialProcess(t *testing.T) {
+	out, err := runCredentialProcess(`echo '{"Version": 1}'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), `{"Version": 1}`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if _, err := runCredentialProcess("exit 3"); err == nil {
+		t.Fatal("expected error")
+	}
+}
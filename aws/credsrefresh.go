@@ -0,0 +1,40 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/wallix/awless/logger"
+)
+
+// RefreshExpiredCredentialsHandler handles the requests rejected because their temporary credentials
+// expired meanwhile (ex: long template runs waiting for databases or copying images outliving an STS session).
+// The SDK retries them after expiring the credentials, so that new ones are retrieved from their provider
+// (assuming roles again, running the credential_process, ...) and the request signed again.
+// As credentials rejected again cannot be refreshed (ex: exported session tokens), the request then
+// fails right away instead of being retried until exhaustion
+var RefreshExpiredCredentialsHandler = request.NamedHandler{Name: "awless.RefreshExpiredCredentials", Fn: func(r *request.Request) {
+	if r.Retryable != nil || !r.IsErrorExpired() {
+		return
+	}
+	if r.RetryCount > 0 {
+		r.Retryable = awssdk.Bool(false)
+		return
+	}
+	logger.Verbosef("%s/%s: credentials expired, refreshing them", r.ClientInfo.ServiceName, r.Operation.Name)
+}}
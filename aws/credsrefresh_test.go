@@ -0,0 +1,104 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+type rotatingProvider struct {
+	retrieved int
+}
+
+func (p *rotatingProvider) Retrieve() (credentials.Value, error) {
+	p.retrieved++
+	return credentials.Value{AccessKeyID: fmt.Sprintf("AKID%d", p.retrieved), SecretAccessKey: "SECRET", SessionToken: "TOKEN"}, nil
+}
+
+func (p *rotatingProvider) IsExpired() bool { return false }
+
+func TestRefreshExpiredCredentials(t *testing.T) {
+	var signedWith []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		signedWith = append(signedWith, auth[strings.Index(auth, "Credential=")+11:strings.Index(auth, "/")])
+		if strings.Contains(auth, "Credential=AKID1/") || strings.Contains(auth, "Credential=EXPIRED") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"com.amazonaws.kms#ExpiredTokenException","message":"The security token included in the request is expired"}`))
+			return
+		}
+		w.Write([]byte(`{"Plaintext":"c2VjcmV0"}`))
+	}))
+	defer server.Close()
+
+	newSession := func(provider credentials.Provider) *session.Session {
+		sess := session.Must(session.NewSession(&awssdk.Config{
+			Region:      awssdk.String("eu-west-1"),
+			Endpoint:    awssdk.String(server.URL),
+			Credentials: credentials.NewCredentials(provider),
+			MaxRetries:  awssdk.Int(3),
+		}))
+		sess.Handlers.Retry.PushBackNamed(RefreshExpiredCredentialsHandler)
+		return sess
+	}
+
+	provider := &rotatingProvider{}
+	plain, err := NewKMS(newSession(provider)).Decrypt([]byte("ciphered"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(plain), "secret"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := provider.retrieved, 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := strings.Join(signedWith, ","), "AKID1,AKID2"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	signedWith = nil
+	_, err = NewKMS(newSession(&credentials.StaticProvider{Value: credentials.Value{AccessKeyID: "EXPIRED", SecretAccessKey: "SECRET"}})).Decrypt([]byte("ciphered"))
+	if err == nil {
+		t.Fatal("expected error got none")
+	}
+	if got, want := len(signedWith), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
+
+func TestCredentialsErrorHandler(t *testing.T) {
+	sess := session.Must(session.NewSession(&awssdk.Config{
+		Region:      awssdk.String("eu-west-1"),
+		Endpoint:    awssdk.String("http://localhost:1"),
+		Credentials: credentials.NewStaticCredentials("", "", ""),
+	}))
+	sess.Handlers.Validate.PushBackNamed(credentialsErrorHandler([]*AssumeRole{{Arn: "arn:aws:iam::123456789012:role/admin"}}, false))
+
+	_, err := NewKMS(sess).Decrypt([]byte("blob"))
+	if err == nil || !strings.HasPrefix(err.Error(), "cannot assume role arn:aws:iam::123456789012:role/admin:") {
+		t.Fatalf("got %v, want role error", err)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// ec2MaxBatchIDs is the maximum number of ids sent in a single EC2 call
+	ec2MaxBatchIDs = 1000
+	// batchWindow is how long a batch waits for concurrent calls to join it
+	batchWindow = 20 * time.Millisecond
+)
+
+// batchFunc calls the API once for all the given ids and returns
+// the result of each id
+type batchFunc func(ids []string) (map[string]interface{}, error)
+
+// batcher coalesces the ids requested by concurrent driver calls
+// (e.g. when reverting in parallel) into calls of at most max ids.
+// When a batched call fails, each id is retried on its own
+// so that the error is reported for the faulty ids only.
+type batcher struct {
+	key    batcherKey
+	fn     batchFunc
+	max    int
+	window time.Duration
+
+	mu      sync.Mutex
+	pending []*batchRequest
+}
+
+type batchRequest struct {
+	id   string
+	done chan batchResult
+}
+
+type batchResult struct {
+	value interface{}
+	err   error
+}
+
+func newBatcher(key batcherKey, fn batchFunc, max int, window time.Duration) *batcher {
+	return &batcher{key: key, fn: fn, max: max, window: window}
+}
+
+func (b *batcher) do(id string) (interface{}, error) {
+	req := &batchRequest{id: id, done: make(chan batchResult, 1)}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, req)
+	switch len(b.pending) {
+	case b.max:
+		reqs := b.pending
+		b.pending = nil
+		go b.run(reqs)
+	case 1:
+		time.AfterFunc(b.window, b.flush)
+	}
+	b.mu.Unlock()
+
+	res := <-req.done
+	return res.value, res.err
+}
+
+func (b *batcher) flush() {
+	b.mu.Lock()
+	reqs := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	if len(reqs) > 0 {
+		b.run(reqs)
+	}
+}
+
+func (b *batcher) run(reqs []*batchRequest) {
+	var ids []string
+	unique := make(map[string]bool)
+	for _, req := range reqs {
+		if !unique[req.id] {
+			unique[req.id] = true
+			ids = append(ids, req.id)
+		}
+	}
+
+	results := make(map[string]batchResult)
+	values, err := b.fn(ids)
+	switch {
+	case err == nil:
+		for _, id := range ids {
+			results[id] = batchResult{value: values[id]}
+		}
+	case len(ids) == 1:
+		results[ids[0]] = batchResult{err: err}
+	default:
+		for _, id := range ids {
+			values, err := b.fn([]string{id})
+			results[id] = batchResult{value: values[id], err: err}
+		}
+	}
+
+	for _, req := range reqs {
+		req.done <- results[req.id]
+	}
+	b.evictIfIdle()
+}
+
+// evictIfIdle releases the batcher once it has no pending calls,
+// later calls getting a new batcher from batcherFor
+func (b *batcher) evictIfIdle() {
+	batchers.Lock()
+	defer batchers.Unlock()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.pending) == 0 && batchers.all[b.key] == b {
+		delete(batchers.all, b.key)
+	}
+}
+
+// batcherKey identifies the calls that can be batched together: the same
+// operation on the same api with the same args (ex: the tags to create)
+type batcherKey struct {
+	api  interface{}
+	op   string
+	args string
+}
+
+var batchers = struct {
+	sync.Mutex
+	all map[batcherKey]*batcher
+}{all: make(map[batcherKey]*batcher)}
+
+// batcherFor returns the batcher shared by all the drivers calling the operation op
+// on the same api with the same args, args being a canonical encoding of the params
+// given to fn (ex: JSON), so that fn is the same for all these calls
+func batcherFor(api interface{}, op, args string, fn batchFunc) *batcher {
+	batchers.Lock()
+	defer batchers.Unlock()
+	key := batcherKey{api: api, op: op, args: args}
+	b, ok := batchers.all[key]
+	if !ok {
+		b = newBatcher(key, fn, ec2MaxBatchIDs, batchWindow)
+		batchers.all[key] = b
+	}
+	return b
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+func TestBatchedDeleteInstances(t *testing.T) {
+	t.Run("concurrent calls", func(t *testing.T) {
+		mock := &terminateMock{}
+		driv := NewEc2Driver(mock).(*Ec2Driver)
+
+		errs := deleteInstancesConcurrently(driv, "inst_1", "inst_2", "inst_3", "inst_2")
+		for id, err := range errs {
+			if err != nil {
+				t.Fatalf("%s: %s", id, err)
+			}
+		}
+		if got, want := mock.calls, [][]string{{"inst_1", "inst_2", "inst_3"}}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("failing batch", func(t *testing.T) {
+		mock := &terminateMock{}
+		driv := NewEc2Driver(mock).(*Ec2Driver)
+
+		errs := deleteInstancesConcurrently(driv, "inst_1", "unknown", "inst_3")
+		if errs["inst_1"] != nil || errs["inst_3"] != nil {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		if errs["unknown"] == nil {
+			t.Fatal("expected error for unknown instance")
+		}
+		if got, want := len(mock.calls), 4; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+	})
+}
+
+func TestBatchedCreateTags(t *testing.T) {
+	mock := &createTagsMock{calls: make(map[string][]string)}
+	driv := NewEc2Driver(mock).(*Ec2Driver)
+
+	params := []map[string]interface{}{
+		{"resource": "inst_1", "tags": map[string]interface{}{"a": "1,b=2"}},
+		{"resource": "inst_2", "tags": map[string]interface{}{"a": "1", "b": "2"}},
+		{"resource": "inst_3", "key": "a", "value": "1,b=2"},
+	}
+	var wg sync.WaitGroup
+	for _, p := range params {
+		wg.Add(1)
+		go func(p map[string]interface{}) {
+			defer wg.Done()
+			if _, err := driv.Create_Tag(p); err != nil {
+				t.Error(err)
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	expected := map[string][]string{
+		"a=1,b=2": {"inst_1", "inst_3"},
+		"a=1 b=2": {"inst_2"},
+	}
+	if got, want := mock.calls, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	batchers.Lock()
+	defer batchers.Unlock()
+	if got, want := len(batchers.all), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
+
+func deleteInstancesConcurrently(driv *Ec2Driver, ids ...string) map[string]error {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(map[string]error)
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			_, err := driv.Delete_Instance(map[string]interface{}{"id": id})
+			mu.Lock()
+			errs[id] = err
+			mu.Unlock()
+		}(id)
+	}
+	wg.Wait()
+	return errs
+}
+
+type terminateMock struct {
+	ec2iface.EC2API
+	mu    sync.Mutex
+	calls [][]string
+}
+
+func (m *terminateMock) TerminateInstances(input *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
+	ids := aws.StringValueSlice(input.InstanceIds)
+	sort.Strings(ids)
+	m.mu.Lock()
+	m.calls = append(m.calls, ids)
+	m.mu.Unlock()
+
+	output := &ec2.TerminateInstancesOutput{}
+	for _, id := range ids {
+		if id == "unknown" {
+			return nil, fmt.Errorf("instance %s not found", id)
+		}
+		output.TerminatingInstances = append(output.TerminatingInstances, &ec2.InstanceStateChange{InstanceId: aws.String(id)})
+	}
+	return output, nil
+}
+
+type createTagsMock struct {
+	ec2iface.EC2API
+	mu    sync.Mutex
+	calls map[string][]string // resources by tags
+}
+
+func (m *createTagsMock) CreateTags(input *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error) {
+	var tags []string
+	for _, tag := range input.Tags {
+		tags = append(tags, aws.StringValue(tag.Key)+"="+aws.StringValue(tag.Value))
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.Join(tags, " ")
+	m.calls[key] = append(m.calls[key], aws.StringValueSlice(input.Resources)...)
+	sort.Strings(m.calls[key])
+	return &ec2.CreateTagsOutput{}, nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package aws
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -24,6 +25,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -35,6 +37,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/wallix/awless/console"
 	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template"
 )
 
 func (d *IamDriver) Attach_Policy_DryRun(params map[string]interface{}) (interface{}, error) {
@@ -187,13 +190,10 @@ func (d *Ec2Driver) Check_Instance_DryRun(params map[string]interface{}) (interf
 }
 
 func (d *Ec2Driver) Check_Instance(params map[string]interface{}) (interface{}, error) {
-	input := &ec2.DescribeInstancesInput{}
-
-	// Required params
-	err := setFieldWithType(params["id"], input, "InstanceIds", awsstringslice)
-	if err != nil {
-		return nil, err
+	if _, ok := params["id"]; !ok {
+		return nil, errors.New("check instance: missing required params 'id'")
 	}
+	id := fmt.Sprint(params["id"])
 
 	timeout := time.Duration(params["timeout"].(int)) * time.Second
 	timer := time.NewTimer(timeout)
@@ -201,26 +201,20 @@ func (d *Ec2Driver) Check_Instance(params map[string]interface{}) (interface{},
 	for {
 		select {
 		case <-time.After(retry):
-			output, err := d.DescribeInstances(input)
+			state, err := batcherFor(d.EC2API, "describeinstances", "", d.describeInstanceStates).do(id)
 			if err != nil {
 				d.logger.Errorf("check instance error: %s", err)
 				return nil, err
 			}
 
-			if res := output.Reservations; len(res) > 0 {
-				if instances := output.Reservations[0].Instances; len(instances) > 0 {
-					for _, inst := range instances {
-						if aws.StringValue(inst.InstanceId) == params["id"] {
-							currentStatus := aws.StringValue(inst.State.Name)
-							if currentStatus == params["state"] {
-								d.logger.Verbosef("check instance status '%s' done", params["state"])
-								timer.Stop()
-								return nil, nil
-							}
-							d.logger.Infof("instance status '%s', expect '%s', retry in %s (timeout %s).", currentStatus, params["state"], retry, timeout)
-						}
-					}
+			if state != nil {
+				currentStatus := state.(string)
+				if currentStatus == params["state"] {
+					d.logger.Verbosef("check instance status '%s' done", params["state"])
+					timer.Stop()
+					return nil, nil
 				}
+				d.logger.Infof("instance status '%s', expect '%s', retry in %s (timeout %s).", currentStatus, params["state"], retry, timeout)
 			}
 
 		case <-timer.C:
@@ -231,6 +225,79 @@ func (d *Ec2Driver) Check_Instance(params map[string]interface{}) (interface{},
 	}
 }
 
+// describeInstanceStates returns the state name of each instance
+func (d *Ec2Driver) describeInstanceStates(ids []string) (map[string]interface{}, error) {
+	states := make(map[string]interface{})
+	err := d.DescribeInstancesPages(&ec2.DescribeInstancesInput{InstanceIds: aws.StringSlice(ids)}, func(output *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, res := range output.Reservations {
+			for _, inst := range res.Instances {
+				states[aws.StringValue(inst.InstanceId)] = aws.StringValue(inst.State.Name)
+			}
+		}
+		return true
+	})
+	return states, err
+}
+
+func (d *Ec2Driver) Delete_Instance_DryRun(params map[string]interface{}) (interface{}, error) {
+	input := &ec2.TerminateInstancesInput{}
+	input.DryRun = aws.Bool(true)
+	var err error
+
+	// Required params
+	err = setFieldWithType(params["id"], input, "InstanceIds", awsstringslice)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = d.TerminateInstances(input)
+	if awsErr, ok := err.(awserr.Error); ok {
+		switch code := awsErr.Code(); {
+		case code == dryRunOperation, strings.HasSuffix(code, notFound):
+			id := fakeDryRunId("instance")
+			d.logger.Verbose("full dry run: delete instance ok")
+			return id, nil
+		}
+	}
+
+	d.logger.Errorf("dry run: delete instance error: %s", err)
+	return nil, err
+}
+
+func (d *Ec2Driver) Delete_Instance(params map[string]interface{}) (interface{}, error) {
+	if _, ok := params["id"]; !ok {
+		return nil, errors.New("delete instance: missing required params 'id'")
+	}
+
+	start := time.Now()
+	change, err := batcherFor(d.EC2API, "terminateinstances", "", d.terminateInstances).do(fmt.Sprint(params["id"]))
+	if err != nil {
+		d.logger.Errorf("delete instance error: %s", err)
+		return nil, err
+	}
+	d.logger.ExtraVerbosef("ec2.TerminateInstances call took %s", time.Since(start))
+	d.logger.Verbose("delete instance done")
+
+	output := &ec2.TerminateInstancesOutput{}
+	if change != nil {
+		output.TerminatingInstances = []*ec2.InstanceStateChange{change.(*ec2.InstanceStateChange)}
+	}
+	return output, nil
+}
+
+// terminateInstances returns the state change of each terminated instance
+func (d *Ec2Driver) terminateInstances(ids []string) (map[string]interface{}, error) {
+	output, err := d.TerminateInstances(&ec2.TerminateInstancesInput{InstanceIds: aws.StringSlice(ids)})
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[string]interface{})
+	for _, change := range output.TerminatingInstances {
+		changes[aws.StringValue(change.InstanceId)] = change
+	}
+	return changes, nil
+}
+
 func (d *Ec2Driver) Create_Tags_DryRun(params map[string]interface{}) (interface{}, error) {
 	input := &ec2.CreateTagsInput{}
 
@@ -289,7 +356,9 @@ func (d *Ec2Driver) Create_Tag_DryRun(params map[string]interface{}) (interface{
 	if err != nil {
 		return nil, err
 	}
-	input.Tags = []*ec2.Tag{{Key: aws.String(fmt.Sprint(params["key"])), Value: aws.String(fmt.Sprint(params["value"]))}}
+	if input.Tags, err = tagsFromParams(params); err != nil {
+		return nil, err
+	}
 
 	_, err = d.CreateTags(input)
 	if awsErr, ok := err.(awserr.Error); ok {
@@ -306,26 +375,67 @@ func (d *Ec2Driver) Create_Tag_DryRun(params map[string]interface{}) (interface{
 }
 
 func (d *Ec2Driver) Create_Tag(params map[string]interface{}) (interface{}, error) {
-	input := &ec2.CreateTagsInput{}
-	var err error
-
-	// Required params
-	err = setFieldWithType(params["resource"], input, "Resources", awsstringslice)
+	if _, ok := params["resource"]; !ok {
+		return nil, errors.New("create tag: missing required params 'resource'")
+	}
+	tags, err := tagsFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+	args, err := json.Marshal(tags)
 	if err != nil {
 		return nil, err
 	}
-	input.Tags = []*ec2.Tag{{Key: aws.String(fmt.Sprint(params["key"])), Value: aws.String(fmt.Sprint(params["value"]))}}
+
+	tagResources := func(ids []string) (map[string]interface{}, error) {
+		_, err := d.CreateTags(&ec2.CreateTagsInput{
+			Resources: aws.StringSlice(ids),
+			Tags:      tags,
+		})
+		return nil, err
+	}
 
 	start := time.Now()
-	var output *ec2.CreateTagsOutput
-	output, err = d.CreateTags(input)
+	_, err = batcherFor(d.EC2API, "createtags", string(args), tagResources).do(fmt.Sprint(params["resource"]))
 	if err != nil {
 		d.logger.Errorf("create tag error: %s", err)
 		return nil, err
 	}
 	d.logger.ExtraVerbosef("ec2.CreateTags call took %s", time.Since(start))
 	d.logger.Verbose("create tag done")
-	return output, nil
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+// tagsFromParams returns the tags set by a create tag statement, sorted by key:
+// the key and value params and the entries of the tags map param
+func tagsFromParams(params map[string]interface{}) ([]*ec2.Tag, error) {
+	values := make(map[string]string)
+	if tags, ok := params["tags"]; ok {
+		m, isMap := tags.(map[string]interface{})
+		if !isMap {
+			return nil, fmt.Errorf("create tag: expecting a map for 'tags' (ex: tags={Name:web,Env:prod}), got %v", tags)
+		}
+		for k, v := range m {
+			values[k] = fmt.Sprint(v)
+		}
+	}
+	if key, ok := params["key"]; ok {
+		values[fmt.Sprint(key)] = fmt.Sprint(params["value"])
+	}
+	if len(values) == 0 {
+		return nil, errors.New("create tag: missing 'key' and 'value' or 'tags' params")
+	}
+
+	var keys []string
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var tags []*ec2.Tag
+	for _, k := range keys {
+		tags = append(tags, &ec2.Tag{Key: aws.String(k), Value: aws.String(values[k])})
+	}
+	return tags, nil
 }
 
 func (d *Ec2Driver) Create_Keypair_DryRun(params map[string]interface{}) (interface{}, error) {
@@ -574,17 +684,99 @@ func (d *S3Driver) Create_Storageobject(params map[string]interface{}) (interfac
 	return output, nil
 }
 
+func (d *S3Driver) Delete_Storageobject_DryRun(params map[string]interface{}) (interface{}, error) {
+	if _, ok := params["bucket"]; !ok {
+		return nil, errors.New("delete storageobject: missing required params 'bucket'")
+	}
+
+	if _, ok := params["key"]; !ok {
+		return nil, errors.New("delete storageobject: missing required params 'key'")
+	}
+
+	d.logger.Verbose("params dry run: delete storageobject ok")
+	return nil, nil
+}
+
+// Delete_Storageobject deletes the object of the key or, with a glob key (ex: key=logs/2016-*),
+// the objects of the bucket matching it, listed by the prefix of the glob
+func (d *S3Driver) Delete_Storageobject(params map[string]interface{}) (interface{}, error) {
+	bucket, key := fmt.Sprint(params["bucket"]), fmt.Sprint(params["key"])
+	if !template.IsGlob(key) {
+		start := time.Now()
+		output, err := d.DeleteObject(&s3.DeleteObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+		if err != nil {
+			d.logger.Errorf("delete storageobject error: %s", err)
+			return nil, err
+		}
+		d.logger.ExtraVerbosef("s3.DeleteObject call took %s", time.Since(start))
+		d.logger.Verbose("delete storageobject done")
+		return output, nil
+	}
+
+	var objects []*s3.ObjectIdentifier
+	err := d.ListObjectsPages(&s3.ListObjectsInput{Bucket: aws.String(bucket), Prefix: aws.String(template.GlobPrefix(key))}, func(out *s3.ListObjectsOutput, lastPage bool) bool {
+		for _, obj := range out.Contents {
+			if template.MatchGlob(key, aws.StringValue(obj.Key)) {
+				objects = append(objects, &s3.ObjectIdentifier{Key: obj.Key})
+			}
+		}
+		return true
+	})
+	if err != nil {
+		d.logger.Errorf("delete storageobject error: %s", err)
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("delete storageobject: no object matching key '%s' in bucket '%s'", key, bucket)
+	}
+
+	output := &s3.DeleteObjectsOutput{}
+	// at most 1000 objects are deleted per call
+	for i := 0; i < len(objects); i += 1000 {
+		end := i + 1000
+		if end > len(objects) {
+			end = len(objects)
+		}
+		start := time.Now()
+		out, err := d.DeleteObjects(&s3.DeleteObjectsInput{Bucket: aws.String(bucket), Delete: &s3.Delete{Objects: objects[i:end]}})
+		if err != nil {
+			d.logger.Errorf("delete storageobject error: %s", err)
+			return output, err
+		}
+		d.logger.ExtraVerbosef("s3.DeleteObjects call took %s", time.Since(start))
+		output.Deleted = append(output.Deleted, out.Deleted...)
+		output.Errors = append(output.Errors, out.Errors...)
+	}
+	if len(output.Errors) > 0 {
+		failed := output.Errors[0]
+		err := fmt.Errorf("delete storageobject: %d objects not deleted, '%s': %s", len(output.Errors), aws.StringValue(failed.Key), aws.StringValue(failed.Message))
+		d.logger.Error(err)
+		return output, err
+	}
+	d.logger.Verbosef("delete storageobject done: %d objects matching '%s'", len(output.Deleted), key)
+	return output, nil
+}
+
 func buildIpPermissionsFromParams(params map[string]interface{}) ([]*ec2.IpPermission, error) {
-	if _, ok := params["cidr"].(string); !ok {
+	cidr, ok := params["cidr"].(string)
+	if !ok {
 		return nil, fmt.Errorf("invalid cidr '%v'", params["cidr"])
 	}
-	ipPerm := &ec2.IpPermission{
-		IpRanges: []*ec2.IpRange{{CidrIp: aws.String(params["cidr"].(string))}},
+	ipPerm := &ec2.IpPermission{}
+	if strings.Contains(cidr, ":") {
+		ipPerm.Ipv6Ranges = []*ec2.Ipv6Range{{CidrIpv6: aws.String(cidr)}}
+	} else {
+		ipPerm.IpRanges = []*ec2.IpRange{{CidrIp: aws.String(cidr)}}
 	}
-	if _, ok := params["protocol"].(string); !ok {
+	var p string
+	switch proto := params["protocol"].(type) {
+	case string:
+		p = proto
+	case int: // protocol numbers, ex: -1 for all protocols
+		p = strconv.Itoa(proto)
+	default:
 		return nil, fmt.Errorf("invalid protocol '%v'", params["protocol"])
 	}
-	p := params["protocol"].(string)
 	if strings.Contains("any", p) {
 		ipPerm.FromPort = aws.Int64(int64(-1))
 		ipPerm.ToPort = aws.Int64(int64(-1))
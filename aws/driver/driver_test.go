@@ -20,12 +20,14 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
@@ -156,6 +158,54 @@ func TestDriver(t *testing.T) {
 	})
 }
 
+func TestDeleteStorageobject(t *testing.T) {
+	awsMock := &mockS3{keys: []string{"2015-12/a.log", "2016-01/a.log", "2016-01/b.txt", "2016-02/c.log"}}
+	driv := NewS3Driver(awsMock).(*S3Driver)
+
+	if _, err := driv.Delete_Storageobject(map[string]interface{}{"bucket": "logs", "key": "2016-01/b.txt"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := awsMock.deletedKeys, []string{"2016-01/b.txt"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	awsMock.deletedKeys = nil
+	if _, err := driv.Delete_Storageobject(map[string]interface{}{"bucket": "logs", "key": "2016-*.log"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := awsMock.listPrefix, "2016-"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := awsMock.deletedKeys, []string{"2016-01/a.log", "2016-02/c.log"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if _, err := driv.Delete_Storageobject(map[string]interface{}{"bucket": "logs", "key": "2017-*"}); err == nil {
+		t.Fatal("expected error got none")
+	}
+}
+
+func TestTagsFromParams(t *testing.T) {
+	tags, err := tagsFromParams(map[string]interface{}{"resource": "i-1", "key": "Owner", "value": "bob", "tags": map[string]interface{}{"Name": "web", "Env": "prod"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, tag := range tags {
+		got = append(got, aws.StringValue(tag.Key)+"="+aws.StringValue(tag.Value))
+	}
+	if want := []string{"Env=prod", "Name=web", "Owner=bob"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if _, err := tagsFromParams(map[string]interface{}{"resource": "i-1"}); err == nil {
+		t.Fatal("expected error without tags")
+	}
+	if _, err := tagsFromParams(map[string]interface{}{"resource": "i-1", "tags": "Name"}); err == nil {
+		t.Fatal("expected error when tags is not a map")
+	}
+}
+
 func TestBuildIpPermissionsFromParams(t *testing.T) {
 	params := map[string]interface{}{
 		"protocol":  "tcp",
@@ -219,6 +269,48 @@ func TestBuildIpPermissionsFromParams(t *testing.T) {
 		t.Fatalf("got %+v, want %+v", got, want)
 	}
 
+	params = map[string]interface{}{
+		"protocol":  -1,
+		"cidr":      "10.0.0.0/16",
+		"portrange": -1,
+	}
+	expected = []*ec2.IpPermission{
+		{
+			IpProtocol: aws.String("-1"),
+			IpRanges:   []*ec2.IpRange{{CidrIp: aws.String("10.0.0.0/16")}},
+			FromPort:   aws.Int64(int64(-1)),
+			ToPort:     aws.Int64(int64(-1)),
+		},
+	}
+	ipPermissions, err = buildIpPermissionsFromParams(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ipPermissions, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	params = map[string]interface{}{
+		"protocol":  "tcp",
+		"cidr":      "2001:db8::/32",
+		"portrange": 443,
+	}
+	expected = []*ec2.IpPermission{
+		{
+			IpProtocol: aws.String("tcp"),
+			Ipv6Ranges: []*ec2.Ipv6Range{{CidrIpv6: aws.String("2001:db8::/32")}},
+			FromPort:   aws.Int64(int64(443)),
+			ToPort:     aws.Int64(int64(443)),
+		},
+	}
+	ipPermissions, err = buildIpPermissionsFromParams(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ipPermissions, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
 	params = map[string]interface{}{
 		"protocol":  "icmp",
 		"cidr":      "10.0.0.0/16",
@@ -247,6 +339,35 @@ type mockIam struct {
 
 type mockS3 struct {
 	s3iface.S3API
+	keys        []string
+	listPrefix  string
+	deletedKeys []string
+}
+
+func (m *mockS3) ListObjectsPages(input *s3.ListObjectsInput, fn func(p *s3.ListObjectsOutput, lastPage bool) (shouldContinue bool)) error {
+	m.listPrefix = aws.StringValue(input.Prefix)
+	out := &s3.ListObjectsOutput{}
+	for _, key := range m.keys {
+		if strings.HasPrefix(key, m.listPrefix) {
+			out.Contents = append(out.Contents, &s3.Object{Key: aws.String(key)})
+		}
+	}
+	fn(out, true)
+	return nil
+}
+
+func (m *mockS3) DeleteObject(input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+	m.deletedKeys = append(m.deletedKeys, aws.StringValue(input.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (m *mockS3) DeleteObjects(input *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+	out := &s3.DeleteObjectsOutput{}
+	for _, obj := range input.Delete.Objects {
+		m.deletedKeys = append(m.deletedKeys, aws.StringValue(obj.Key))
+		out.Deleted = append(out.Deleted, &s3.DeletedObject{Key: obj.Key})
+	}
+	return out, nil
 }
 
 type mockSNS struct {
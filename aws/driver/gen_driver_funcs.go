@@ -347,7 +347,7 @@ func (d *Ec2Driver) Create_Instance_DryRun(params map[string]interface{}) (inter
 		}
 	}
 	if _, ok := params["userdata"]; ok {
-		err = setFieldWithType(params["userdata"], input, "UserData", awsstr)
+		err = setFieldWithType(params["userdata"], input, "UserData", awsbase64str)
 		if err != nil {
 			return nil, err
 		}
@@ -431,7 +431,7 @@ func (d *Ec2Driver) Create_Instance(params map[string]interface{}) (interface{},
 		}
 	}
 	if _, ok := params["userdata"]; ok {
-		err = setFieldWithType(params["userdata"], input, "UserData", awsstr)
+		err = setFieldWithType(params["userdata"], input, "UserData", awsbase64str)
 		if err != nil {
 			return nil, err
 		}
@@ -564,56 +564,6 @@ func (d *Ec2Driver) Update_Instance(params map[string]interface{}) (interface{},
 	return output, nil
 }
 
-// This function was auto generated
-func (d *Ec2Driver) Delete_Instance_DryRun(params map[string]interface{}) (interface{}, error) {
-	input := &ec2.TerminateInstancesInput{}
-	input.DryRun = aws.Bool(true)
-	var err error
-
-	// Required params
-	err = setFieldWithType(params["id"], input, "InstanceIds", awsstringslice)
-	if err != nil {
-		return nil, err
-	}
-
-	_, err = d.TerminateInstances(input)
-	if awsErr, ok := err.(awserr.Error); ok {
-		switch code := awsErr.Code(); {
-		case code == dryRunOperation, strings.HasSuffix(code, notFound):
-			id := fakeDryRunId("instance")
-			d.logger.Verbose("full dry run: delete instance ok")
-			return id, nil
-		}
-	}
-
-	d.logger.Errorf("dry run: delete instance error: %s", err)
-	return nil, err
-}
-
-// This function was auto generated
-func (d *Ec2Driver) Delete_Instance(params map[string]interface{}) (interface{}, error) {
-	input := &ec2.TerminateInstancesInput{}
-	var err error
-
-	// Required params
-	err = setFieldWithType(params["id"], input, "InstanceIds", awsstringslice)
-	if err != nil {
-		return nil, err
-	}
-
-	start := time.Now()
-	var output *ec2.TerminateInstancesOutput
-	output, err = d.TerminateInstances(input)
-	output = output
-	if err != nil {
-		d.logger.Errorf("delete instance error: %s", err)
-		return nil, err
-	}
-	d.logger.ExtraVerbosef("ec2.TerminateInstances call took %s", time.Since(start))
-	d.logger.Verbose("delete instance done")
-	return output, nil
-}
-
 // This function was auto generated
 func (d *Ec2Driver) Start_Instance_DryRun(params map[string]interface{}) (interface{}, error) {
 	input := &ec2.StartInstancesInput{}
@@ -1923,48 +1873,6 @@ func (d *S3Driver) Delete_Bucket(params map[string]interface{}) (interface{}, er
 	return output, nil
 }
 
-// This function was auto generated
-func (d *S3Driver) Delete_Storageobject_DryRun(params map[string]interface{}) (interface{}, error) {
-	if _, ok := params["bucket"]; !ok {
-		return nil, errors.New("delete storageobject: missing required params 'bucket'")
-	}
-
-	if _, ok := params["key"]; !ok {
-		return nil, errors.New("delete storageobject: missing required params 'key'")
-	}
-
-	d.logger.Verbose("params dry run: delete storageobject ok")
-	return nil, nil
-}
-
-// This function was auto generated
-func (d *S3Driver) Delete_Storageobject(params map[string]interface{}) (interface{}, error) {
-	input := &s3.DeleteObjectInput{}
-	var err error
-
-	// Required params
-	err = setFieldWithType(params["bucket"], input, "Bucket", awsstr)
-	if err != nil {
-		return nil, err
-	}
-	err = setFieldWithType(params["key"], input, "Key", awsstr)
-	if err != nil {
-		return nil, err
-	}
-
-	start := time.Now()
-	var output *s3.DeleteObjectOutput
-	output, err = d.DeleteObject(input)
-	output = output
-	if err != nil {
-		d.logger.Errorf("delete storageobject error: %s", err)
-		return nil, err
-	}
-	d.logger.ExtraVerbosef("s3.DeleteObject call took %s", time.Since(start))
-	d.logger.Verbose("delete storageobject done")
-	return output, nil
-}
-
 // This function was auto generated
 func (d *SnsDriver) Create_Topic_DryRun(params map[string]interface{}) (interface{}, error) {
 	if _, ok := params["name"]; !ok {
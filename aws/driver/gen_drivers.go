@@ -112,6 +112,12 @@ func (d *Ec2Driver) Lookup(lookups ...string) (driverFn driver.DriverFn, err err
 		}
 		return d.Check_Instance, nil
 
+	case "updateinstances":
+		if d.dryRun {
+			return d.Update_Instances_DryRun, nil
+		}
+		return d.Update_Instances, nil
+
 	case "createsecuritygroup":
 		if d.dryRun {
 			return d.Create_Securitygroup_DryRun, nil
@@ -0,0 +1,72 @@
+/* Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// DO NOT EDIT
+// This file was automatically generated with go generate
+package aws
+
+// DriverPermissions lists the IAM actions called by each driver function, indexed by action and entity
+var DriverPermissions = map[string][]string{
+	"createvpc":             {"ec2:CreateVpc"},
+	"deletevpc":             {"ec2:DeleteVpc"},
+	"createsubnet":          {"ec2:CreateSubnet"},
+	"updatesubnet":          {"ec2:ModifySubnetAttribute"},
+	"deletesubnet":          {"ec2:DeleteSubnet"},
+	"createinstance":        {"ec2:RunInstances", "ec2:CreateTags"},
+	"updateinstance":        {"ec2:ModifyInstanceAttribute"},
+	"deleteinstance":        {"ec2:TerminateInstances"},
+	"startinstance":         {"ec2:StartInstances"},
+	"stopinstance":          {"ec2:StopInstances"},
+	"checkinstance":         {"ec2:DescribeInstances"},
+	"updateinstances":       {"ec2:DescribeInstances", "ec2:DescribeInstanceAttribute", "ec2:RunInstances", "ec2:CreateTags", "ec2:DescribeInstanceStatus", "ec2:TerminateInstances", "iam:PassRole"},
+	"createsecuritygroup":   {"ec2:CreateSecurityGroup"},
+	"updatesecuritygroup":   {"ec2:AuthorizeSecurityGroupIngress", "ec2:RevokeSecurityGroupIngress", "ec2:AuthorizeSecurityGroupEgress", "ec2:RevokeSecurityGroupEgress"},
+	"deletesecuritygroup":   {"ec2:DeleteSecurityGroup"},
+	"createvolume":          {"ec2:CreateVolume"},
+	"deletevolume":          {"ec2:DeleteVolume"},
+	"attachvolume":          {"ec2:AttachVolume"},
+	"createinternetgateway": {"ec2:CreateInternetGateway"},
+	"deleteinternetgateway": {"ec2:DeleteInternetGateway"},
+	"attachinternetgateway": {"ec2:AttachInternetGateway"},
+	"detachinternetgateway": {"ec2:DetachInternetGateway"},
+	"createroutetable":      {"ec2:CreateRouteTable"},
+	"deleteroutetable":      {"ec2:DeleteRouteTable"},
+	"attachroutetable":      {"ec2:AssociateRouteTable"},
+	"detachroutetable":      {"ec2:DisassociateRouteTable"},
+	"createroute":           {"ec2:CreateRoute"},
+	"deleteroute":           {"ec2:DeleteRoute"},
+	"createtag":             {"ec2:CreateTags"},
+	"createkeypair":         {"ec2:ImportKeyPair"},
+	"deletekeypair":         {"ec2:DeleteKeyPair"},
+	"deleteloadbalancer":    {"elasticloadbalancing:DeleteLoadBalancer"},
+	"createuser":            {"iam:CreateUser"},
+	"deleteuser":            {"iam:DeleteUser"},
+	"attachuser":            {"iam:AddUserToGroup"},
+	"detachuser":            {"iam:RemoveUserFromGroup"},
+	"creategroup":           {"iam:CreateGroup"},
+	"deletegroup":           {"iam:DeleteGroup"},
+	"attachpolicy":          {"iam:AttachUserPolicy", "iam:AttachGroupPolicy"},
+	"detachpolicy":          {"iam:DetachUserPolicy", "iam:DetachGroupPolicy"},
+	"createbucket":          {"s3:CreateBucket"},
+	"deletebucket":          {"s3:DeleteBucket"},
+	"createstorageobject":   {"s3:PutObject"},
+	"deletestorageobject":   {"s3:DeleteObject", "s3:ListBucket"},
+	"createtopic":           {"sns:CreateTopic"},
+	"deletetopic":           {"sns:DeleteTopic"},
+	"createsubscription":    {"sns:Subscribe"},
+	"deletesubscription":    {"sns:Unsubscribe"},
+	"createqueue":           {"sqs:CreateQueue"},
+	"deletequeue":           {"sqs:DeleteQueue"},
+}
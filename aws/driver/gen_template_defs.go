@@ -29,6 +29,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"cidr"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"cidr": "string"},
 	},
 	"deletevpc": {
 		Action:         "delete",
@@ -37,6 +38,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string"},
 	},
 	"createsubnet": {
 		Action:         "create",
@@ -45,6 +47,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"cidr", "vpc"},
 		ExtraParams:    []string{"zone"},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"cidr": "string", "vpc": "string", "zone": "string"},
 	},
 	"updatesubnet": {
 		Action:         "update",
@@ -53,6 +56,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{"public"},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string", "public": "boolean"},
 	},
 	"deletesubnet": {
 		Action:         "delete",
@@ -61,6 +65,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string"},
 	},
 	"createinstance": {
 		Action:         "create",
@@ -69,6 +74,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"image", "count", "count", "type", "subnet"},
 		ExtraParams:    []string{"key", "ip", "userdata", "group", "lock"},
 		TagsMapping:    []string{"name"},
+		ParamTypes:     map[string]string{"count": "integer", "group": "string", "image": "string", "ip": "string", "key": "string", "lock": "boolean", "subnet": "string", "type": "string", "userdata": "text"},
 	},
 	"updateinstance": {
 		Action:         "update",
@@ -77,6 +83,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{"type", "group", "lock"},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"group": "string", "id": "string", "lock": "boolean", "type": "string"},
 	},
 	"deleteinstance": {
 		Action:         "delete",
@@ -85,6 +92,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string"},
 	},
 	"startinstance": {
 		Action:         "start",
@@ -93,6 +101,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string"},
 	},
 	"stopinstance": {
 		Action:         "stop",
@@ -101,6 +110,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string"},
 	},
 	"checkinstance": {
 		Action:         "check",
@@ -109,6 +119,16 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id", "state", "timeout"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string", "state": "string", "timeout": "integer"},
+	},
+	"updateinstances": {
+		Action:         "update",
+		Entity:         "instances",
+		Api:            "ec2",
+		RequiredParams: []string{"name", "image"},
+		ExtraParams:    []string{"type", "userdata", "batch", "healthcheck", "timeout"},
+		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"batch": "integer", "healthcheck": "string", "image": "string", "name": "string", "timeout": "integer", "type": "string", "userdata": "text"},
 	},
 	"createsecuritygroup": {
 		Action:         "create",
@@ -117,6 +137,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"name", "vpc", "description"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"description": "string", "name": "string", "vpc": "string"},
 	},
 	"updatesecuritygroup": {
 		Action:         "update",
@@ -125,6 +146,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id", "cidr", "protocol"},
 		ExtraParams:    []string{"inbound", "outbound", "portrange"},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"cidr": "string", "id": "string", "inbound": "string", "outbound": "string", "portrange": "string", "protocol": "string"},
 	},
 	"deletesecuritygroup": {
 		Action:         "delete",
@@ -133,6 +155,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string"},
 	},
 	"createvolume": {
 		Action:         "create",
@@ -141,6 +164,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"zone", "size"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"size": "integer", "zone": "string"},
 	},
 	"deletevolume": {
 		Action:         "delete",
@@ -149,6 +173,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string"},
 	},
 	"attachvolume": {
 		Action:         "attach",
@@ -157,6 +182,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"device", "id", "instance"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"device": "string", "id": "string", "instance": "string"},
 	},
 	"createinternetgateway": {
 		Action:         "create",
@@ -165,6 +191,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{},
 	},
 	"deleteinternetgateway": {
 		Action:         "delete",
@@ -173,6 +200,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string"},
 	},
 	"attachinternetgateway": {
 		Action:         "attach",
@@ -181,6 +209,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id", "vpc"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string", "vpc": "string"},
 	},
 	"detachinternetgateway": {
 		Action:         "detach",
@@ -189,6 +218,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id", "vpc"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string", "vpc": "string"},
 	},
 	"createroutetable": {
 		Action:         "create",
@@ -197,6 +227,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"vpc"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"vpc": "string"},
 	},
 	"deleteroutetable": {
 		Action:         "delete",
@@ -205,6 +236,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string"},
 	},
 	"attachroutetable": {
 		Action:         "attach",
@@ -213,6 +245,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id", "subnet"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string", "subnet": "string"},
 	},
 	"detachroutetable": {
 		Action:         "detach",
@@ -221,6 +254,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"association"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"association": "string"},
 	},
 	"createroute": {
 		Action:         "create",
@@ -229,6 +263,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"table", "cidr", "gateway"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"cidr": "string", "gateway": "string", "table": "string"},
 	},
 	"deleteroute": {
 		Action:         "delete",
@@ -237,14 +272,16 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"table", "cidr"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"cidr": "string", "table": "string"},
 	},
 	"createtag": {
 		Action:         "create",
 		Entity:         "tag",
 		Api:            "ec2",
-		RequiredParams: []string{"resource", "key", "value"},
-		ExtraParams:    []string{},
+		RequiredParams: []string{"resource"},
+		ExtraParams:    []string{"key", "value", "tags"},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"key": "string", "resource": "string", "tags": "map", "value": "string"},
 	},
 	"createkeypair": {
 		Action:         "create",
@@ -253,6 +290,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"name"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"name": "string"},
 	},
 	"deletekeypair": {
 		Action:         "delete",
@@ -261,6 +299,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"id": "string"},
 	},
 	"deleteloadbalancer": {
 		Action:         "delete",
@@ -269,6 +308,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"arn"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"arn": "string"},
 	},
 	"createuser": {
 		Action:         "create",
@@ -277,6 +317,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"name"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"name": "string"},
 	},
 	"deleteuser": {
 		Action:         "delete",
@@ -285,6 +326,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"name"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"name": "string"},
 	},
 	"attachuser": {
 		Action:         "attach",
@@ -293,6 +335,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"group", "name"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"group": "string", "name": "string"},
 	},
 	"detachuser": {
 		Action:         "detach",
@@ -301,6 +344,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"group", "name"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"group": "string", "name": "string"},
 	},
 	"creategroup": {
 		Action:         "create",
@@ -309,6 +353,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"name"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"name": "string"},
 	},
 	"deletegroup": {
 		Action:         "delete",
@@ -317,6 +362,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"name"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"name": "string"},
 	},
 	"attachpolicy": {
 		Action:         "attach",
@@ -325,6 +371,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"arn"},
 		ExtraParams:    []string{"user", "group"},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"arn": "string", "group": "string", "user": "string"},
 	},
 	"detachpolicy": {
 		Action:         "detach",
@@ -333,6 +380,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"arn"},
 		ExtraParams:    []string{"user", "group"},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"arn": "string", "group": "string", "user": "string"},
 	},
 	"createbucket": {
 		Action:         "create",
@@ -341,6 +389,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"name"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"name": "string"},
 	},
 	"deletebucket": {
 		Action:         "delete",
@@ -349,6 +398,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"name"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"name": "string"},
 	},
 	"createstorageobject": {
 		Action:         "create",
@@ -357,6 +407,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"bucket", "file"},
 		ExtraParams:    []string{"name"},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"bucket": "string", "file": "string", "name": "string"},
 	},
 	"deletestorageobject": {
 		Action:         "delete",
@@ -365,6 +416,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"bucket", "key"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"bucket": "string", "key": "string"},
 	},
 	"createtopic": {
 		Action:         "create",
@@ -373,6 +425,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"name"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"name": "string"},
 	},
 	"deletetopic": {
 		Action:         "delete",
@@ -381,6 +434,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"arn"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"arn": "string"},
 	},
 	"createsubscription": {
 		Action:         "create",
@@ -389,6 +443,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"topic", "endpoint", "protocol"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"endpoint": "string", "protocol": "string", "topic": "string"},
 	},
 	"deletesubscription": {
 		Action:         "delete",
@@ -397,6 +452,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"arn"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"arn": "string"},
 	},
 	"createqueue": {
 		Action:         "create",
@@ -405,6 +461,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"name"},
 		ExtraParams:    []string{"delay", "maxMsgSize", "retentionPeriod", "policy", "msgWait", "redrivePolicy", "visibilityTimeout"},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"delay": "string", "maxMsgSize": "string", "msgWait": "string", "name": "string", "policy": "string", "redrivePolicy": "string", "retentionPeriod": "string", "visibilityTimeout": "string"},
 	},
 	"deletequeue": {
 		Action:         "delete",
@@ -413,6 +470,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 		RequiredParams: []string{"url"},
 		ExtraParams:    []string{},
 		TagsMapping:    []string{},
+		ParamTypes:     map[string]string{"url": "string"},
 	},
 }
 
@@ -429,6 +487,7 @@ func DriverSupportedActions() map[string][]string {
 	supported["start"] = append(supported["start"], "instance")
 	supported["stop"] = append(supported["stop"], "instance")
 	supported["check"] = append(supported["check"], "instance")
+	supported["update"] = append(supported["update"], "instances")
 	supported["create"] = append(supported["create"], "securitygroup")
 	supported["update"] = append(supported["update"], "securitygroup")
 	supported["delete"] = append(supported["delete"], "securitygroup")
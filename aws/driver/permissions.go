@@ -0,0 +1,45 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StatementPermissions returns the IAM actions needed to run the statement of given action and entity.
+// The keys of the statement params narrow the actions of drivers calling different APIs depending on them
+func StatementPermissions(action, entity string, paramKeys ...string) ([]string, error) {
+	perms, ok := DriverPermissions[action+entity]
+	if !ok {
+		return nil, fmt.Errorf("%s %s: unsupported statement", action, entity)
+	}
+	if entity == "policy" {
+		var narrowed []string
+		for _, perm := range perms {
+			for _, k := range paramKeys {
+				if (k == "user" && strings.HasSuffix(perm, "UserPolicy")) || (k == "group" && strings.HasSuffix(perm, "GroupPolicy")) {
+					narrowed = append(narrowed, perm)
+				}
+			}
+		}
+		if len(narrowed) > 0 {
+			return narrowed, nil
+		}
+	}
+	return perms, nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package aws
 
 import (
+	"encoding/base64"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -28,6 +29,7 @@ import (
 
 const (
 	awsstr = iota
+	awsbase64str
 	awsint
 	awsint64
 	awsbool
@@ -50,6 +52,8 @@ func setFieldWithType(v, i interface{}, fieldPath string, destType int) error {
 	switch destType {
 	case awsstr:
 		v = fmt.Sprint(v)
+	case awsbase64str:
+		v = base64Encoded(fmt.Sprint(v))
 	case awsint64:
 		v, err = castInt64(v)
 		if err != nil {
@@ -66,14 +70,22 @@ func setFieldWithType(v, i interface{}, fieldPath string, destType int) error {
 			return err
 		}
 	case awsstringslice:
-		str := fmt.Sprint(v)
-		v = []*string{&str}
+		var strs []*string
+		for _, e := range sliceValues(v) {
+			str := fmt.Sprint(e)
+			strs = append(strs, &str)
+		}
+		v = strs
 	case awsint64slice:
-		awsint, err := castInt64(v)
-		if err != nil {
-			return err
+		var ints []*int64
+		for _, e := range sliceValues(v) {
+			awsint, err := castInt64(e)
+			if err != nil {
+				return err
+			}
+			ints = append(ints, &awsint)
 		}
-		v = []*int64{&awsint}
+		v = ints
 	case awsboolattribute:
 		b, err := castBool(v)
 		if err != nil {
@@ -107,6 +119,15 @@ func setFieldWithType(v, i interface{}, fieldPath string, destType int) error {
 	return nil
 }
 
+// base64Encoded encodes the string in base64 unless it is already encoded,
+// so that user data can be given either as text (ex: loaded from a file) or in base64
+func base64Encoded(s string) string {
+	if _, err := base64.StdEncoding.DecodeString(s); err == nil {
+		return s
+	}
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
 func castInt(v interface{}) (int, error) {
 	switch vv := v.(type) {
 	case string:
@@ -131,6 +152,22 @@ func castBool(v interface{}) (bool, error) {
 	}
 }
 
+// sliceValues returns the elements of a list param, or the param itself as a single element
+func sliceValues(v interface{}) []interface{} {
+	switch vv := v.(type) {
+	case []interface{}:
+		return vv
+	case []string:
+		var values []interface{}
+		for _, e := range vv {
+			values = append(values, e)
+		}
+		return values
+	default:
+		return []interface{}{v}
+	}
+}
+
 func castInt64(v interface{}) (int64, error) {
 	switch vv := v.(type) {
 	case string:
@@ -17,6 +17,7 @@ limitations under the License.
 package aws
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -42,6 +43,10 @@ func TestSetFieldsOnAwsStruct(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	err = setFieldWithType("#!/bin/sh\necho ok\n", awsparams, "UserData", awsbase64str)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if got, want := aws.StringValue(awsparams.ImageId), "ami"; got != want {
 		t.Fatalf("got %s, want %s", got, want)
@@ -55,6 +60,12 @@ func TestSetFieldsOnAwsStruct(t *testing.T) {
 	if got, want := aws.Int64Value(awsparams.MinCount), int64(3); got != want {
 		t.Fatalf("got %d, want %d", got, want)
 	}
+	if got, want := aws.StringValue(awsparams.UserData), "IyEvYmluL3NoCmVjaG8gb2sK"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := base64Encoded("IyEvYmluL3NoCmVjaG8gb2sK"), "IyEvYmluL3NoCmVjaG8gb2sK"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
 }
 
 func TestSetFieldWithMultiType(t *testing.T) {
@@ -137,6 +148,22 @@ func TestSetFieldWithMultiType(t *testing.T) {
 		t.Fatalf("got %v, want %v", got, want)
 	}
 
+	err = setFieldWithType([]interface{}{"sg-1", "sg-2"}, &any, "StringArrayField", awsstringslice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := aws.StringValueSlice(any.StringArrayField), []string{"sg-1", "sg-2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	err = setFieldWithType([]interface{}{80, "443"}, &any, "Int64ArrayField", awsint64slice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := aws.Int64ValueSlice(any.Int64ArrayField), []int64{80, 443}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
 	err = setFieldWithType("any", nil, "IntField", awsint)
 	if err != nil {
 		t.Fatal(err)
@@ -0,0 +1,328 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+const (
+	// healthy when the instance is running
+	runningHealthCheck = "running"
+	// healthy when the instance is running and passes its EC2 system and instance status checks
+	statusHealthCheck = "status"
+
+	defaultRollingBatch   = 1
+	defaultRollingTimeout = 600
+)
+
+// rollingCheckInterval is the interval between health checks of replacement instances
+var rollingCheckInterval = 5 * time.Second
+
+// rollingUpdate holds the params of an update of instances
+type rollingUpdate struct {
+	name, image, typ, userdata string
+	batch                      int
+	healthcheck                string
+	timeout                    time.Duration
+}
+
+func newRollingUpdate(params map[string]interface{}) (*rollingUpdate, error) {
+	for _, required := range []string{"name", "image"} {
+		if _, ok := params[required]; !ok {
+			return nil, fmt.Errorf("update instances: missing required params '%s'", required)
+		}
+	}
+	up := &rollingUpdate{
+		name:        fmt.Sprint(params["name"]),
+		image:       fmt.Sprint(params["image"]),
+		batch:       defaultRollingBatch,
+		healthcheck: statusHealthCheck,
+		timeout:     defaultRollingTimeout * time.Second,
+	}
+	if typ, ok := params["type"]; ok {
+		up.typ = fmt.Sprint(typ)
+	}
+	if userdata, ok := params["userdata"]; ok {
+		up.userdata = fmt.Sprint(userdata)
+	}
+	if batch, ok := params["batch"]; ok {
+		n, err := castInt(batch)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("update instances: invalid batch '%v': expecting a positive number of instances", batch)
+		}
+		up.batch = n
+	}
+	if check, ok := params["healthcheck"]; ok {
+		up.healthcheck = fmt.Sprint(check)
+		if up.healthcheck != runningHealthCheck && up.healthcheck != statusHealthCheck {
+			return nil, fmt.Errorf("update instances: invalid healthcheck '%s': expecting %s or %s", up.healthcheck, runningHealthCheck, statusHealthCheck)
+		}
+	}
+	if timeout, ok := params["timeout"]; ok {
+		n, err := castInt(timeout)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("update instances: invalid timeout '%v': expecting a positive number of seconds", timeout)
+		}
+		up.timeout = time.Duration(n) * time.Second
+	}
+	return up, nil
+}
+
+func (d *Ec2Driver) Update_Instances_DryRun(params map[string]interface{}) (interface{}, error) {
+	up, err := newRollingUpdate(params)
+	if err != nil {
+		return nil, err
+	}
+	fleet, err := d.fleetInstances(up.name)
+	if err != nil {
+		return nil, err
+	}
+	if len(fleet) == 0 {
+		return nil, fmt.Errorf("dry run: update instances: no running instance named '%s'", up.name)
+	}
+
+	input, err := d.replacementInput(up, fleet[0])
+	if err != nil {
+		return nil, err
+	}
+	input.DryRun = aws.Bool(true)
+	_, err = d.RunInstances(input)
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == dryRunOperation {
+		d.logger.Verbosef("full dry run: update instances ok (%d instances, by %d)", len(fleet), up.batch)
+		return nil, nil
+	}
+
+	d.logger.Errorf("dry run: update instances error: %s", err)
+	return nil, err
+}
+
+// Update_Instances replaces the running instances named after the 'name' param by
+// instances of a new image, batch by batch: the replacements of a batch are created
+// with the subnet, security groups, key, type, profile, user data and tags of the instances
+// they replace, then the batch is terminated once its replacements are healthy.
+// When replacements are not healthy in time, they are terminated and the update stops.
+// Returns the ids of the replacements, comma separated
+func (d *Ec2Driver) Update_Instances(params map[string]interface{}) (interface{}, error) {
+	up, err := newRollingUpdate(params)
+	if err != nil {
+		return nil, err
+	}
+	fleet, err := d.fleetInstances(up.name)
+	if err != nil {
+		d.logger.Errorf("update instances error: %s", err)
+		return nil, err
+	}
+	if len(fleet) == 0 {
+		err := fmt.Errorf("update instances: no running instance named '%s'", up.name)
+		d.logger.Errorf("%s", err)
+		return nil, err
+	}
+
+	var replacements []string
+	for start := 0; start < len(fleet); start += up.batch {
+		end := start + up.batch
+		if end > len(fleet) {
+			end = len(fleet)
+		}
+		batch := fleet[start:end]
+		d.logger.Infof("update instances: replacing %s (%d/%d)", strings.Join(instanceIds(batch), ", "), end, len(fleet))
+
+		created, err := d.replaceBatch(up, batch)
+		if err != nil {
+			d.logger.Errorf("update instances error: %s", err)
+			return strings.Join(replacements, ","), err
+		}
+		replacements = append(replacements, created...)
+	}
+
+	d.logger.Verbosef("update instances done: %d instances replaced", len(fleet))
+	return strings.Join(replacements, ","), nil
+}
+
+func (d *Ec2Driver) replaceBatch(up *rollingUpdate, batch []*ec2.Instance) ([]string, error) {
+	var created []string
+	rollback := func(cause error) error {
+		if len(created) == 0 {
+			return cause
+		}
+		d.logger.Warningf("update instances: terminating replacements %s", strings.Join(created, ", "))
+		if _, err := d.TerminateInstances(&ec2.TerminateInstancesInput{InstanceIds: aws.StringSlice(created)}); err != nil {
+			return fmt.Errorf("%s (terminating replacements %s: %s)", cause, strings.Join(created, ", "), err)
+		}
+		return cause
+	}
+
+	for _, old := range batch {
+		input, err := d.replacementInput(up, old)
+		if err != nil {
+			return nil, rollback(err)
+		}
+		reservation, err := d.RunInstances(input)
+		if err != nil {
+			return nil, rollback(fmt.Errorf("replacing %s: %s", aws.StringValue(old.InstanceId), err))
+		}
+		ids := instanceIds(reservation.Instances)
+		created = append(created, ids...)
+		if tags := replacementTags(old); len(tags) > 0 {
+			if _, err := d.CreateTags(&ec2.CreateTagsInput{Resources: aws.StringSlice(ids), Tags: tags}); err != nil {
+				return nil, rollback(fmt.Errorf("tagging %s: %s", strings.Join(ids, ", "), err))
+			}
+		}
+	}
+
+	if err := d.waitHealthy(up, created); err != nil {
+		return nil, rollback(err)
+	}
+	if _, err := d.TerminateInstances(&ec2.TerminateInstancesInput{InstanceIds: aws.StringSlice(instanceIds(batch))}); err != nil {
+		return created, fmt.Errorf("terminating %s: %s", strings.Join(instanceIds(batch), ", "), err)
+	}
+	return created, nil
+}
+
+// fleetInstances returns the running instances named name
+func (d *Ec2Driver) fleetInstances(name string) ([]*ec2.Instance, error) {
+	var fleet []*ec2.Instance
+	err := d.DescribeInstancesPages(&ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("tag:Name"), Values: []*string{aws.String(name)}},
+			{Name: aws.String("instance-state-name"), Values: []*string{aws.String(ec2.InstanceStateNameRunning)}},
+		},
+	}, func(output *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, res := range output.Reservations {
+			fleet = append(fleet, res.Instances...)
+		}
+		return true
+	})
+	return fleet, err
+}
+
+// replacementInput returns the input launching an instance replacing old
+func (d *Ec2Driver) replacementInput(up *rollingUpdate, old *ec2.Instance) (*ec2.RunInstancesInput, error) {
+	input := &ec2.RunInstancesInput{
+		ImageId:      aws.String(up.image),
+		InstanceType: old.InstanceType,
+		SubnetId:     old.SubnetId,
+		KeyName:      old.KeyName,
+		MinCount:     aws.Int64(1),
+		MaxCount:     aws.Int64(1),
+	}
+	if up.typ != "" {
+		input.InstanceType = aws.String(up.typ)
+	}
+	for _, group := range old.SecurityGroups {
+		input.SecurityGroupIds = append(input.SecurityGroupIds, group.GroupId)
+	}
+	if profile := old.IamInstanceProfile; profile != nil {
+		input.IamInstanceProfile = &ec2.IamInstanceProfileSpecification{Arn: profile.Arn}
+	}
+
+	if up.userdata != "" {
+		input.UserData = aws.String(base64Encoded(up.userdata))
+		return input, nil
+	}
+	attr, err := d.DescribeInstanceAttribute(&ec2.DescribeInstanceAttributeInput{
+		InstanceId: old.InstanceId,
+		Attribute:  aws.String(ec2.InstanceAttributeNameUserData),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("user data of %s: %s", aws.StringValue(old.InstanceId), err)
+	}
+	if attr.UserData != nil && aws.StringValue(attr.UserData.Value) != "" {
+		input.UserData = attr.UserData.Value
+	}
+	return input, nil
+}
+
+// replacementTags returns the tags of old, without the tags reserved to AWS
+func replacementTags(old *ec2.Instance) (tags []*ec2.Tag) {
+	for _, tag := range old.Tags {
+		if !strings.HasPrefix(aws.StringValue(tag.Key), "aws:") {
+			tags = append(tags, &ec2.Tag{Key: tag.Key, Value: tag.Value})
+		}
+	}
+	return
+}
+
+// waitHealthy waits for the instances to pass the health check of the update
+func (d *Ec2Driver) waitHealthy(up *rollingUpdate, ids []string) error {
+	timer := time.NewTimer(up.timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-time.After(rollingCheckInterval):
+			unhealthy, err := d.unhealthyInstances(up.healthcheck, ids)
+			if err != nil {
+				return err
+			}
+			if len(unhealthy) == 0 {
+				d.logger.Verbosef("update instances: %s healthy", strings.Join(ids, ", "))
+				return nil
+			}
+			d.logger.Infof("update instances: waiting for %s to be healthy (%s check, timeout %s)", strings.Join(unhealthy, ", "), up.healthcheck, up.timeout)
+		case <-timer.C:
+			return fmt.Errorf("replacements %s not healthy after %s", strings.Join(ids, ", "), up.timeout)
+		}
+	}
+}
+
+func (d *Ec2Driver) unhealthyInstances(healthcheck string, ids []string) ([]string, error) {
+	output, err := d.DescribeInstanceStatus(&ec2.DescribeInstanceStatusInput{
+		InstanceIds:         aws.StringSlice(ids),
+		IncludeAllInstances: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	healthy := make(map[string]bool)
+	for _, status := range output.InstanceStatuses {
+		state := aws.StringValue(status.InstanceState.Name)
+		switch state {
+		case ec2.InstanceStateNameShuttingDown, ec2.InstanceStateNameTerminated, ec2.InstanceStateNameStopping, ec2.InstanceStateNameStopped:
+			return nil, fmt.Errorf("replacement %s is %s", aws.StringValue(status.InstanceId), state)
+		case ec2.InstanceStateNameRunning:
+			healthy[aws.StringValue(status.InstanceId)] = healthcheck == runningHealthCheck ||
+				(statusOk(status.SystemStatus) && statusOk(status.InstanceStatus))
+		}
+	}
+
+	var unhealthy []string
+	for _, id := range ids {
+		if !healthy[id] {
+			unhealthy = append(unhealthy, id)
+		}
+	}
+	return unhealthy, nil
+}
+
+func statusOk(summary *ec2.InstanceStatusSummary) bool {
+	return summary != nil && aws.StringValue(summary.Status) == ec2.SummaryStatusOk
+}
+
+func instanceIds(instances []*ec2.Instance) (ids []string) {
+	for _, inst := range instances {
+		ids = append(ids, aws.StringValue(inst.InstanceId))
+	}
+	return
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+func TestRollingUpdateInstances(t *testing.T) {
+	defer func(interval time.Duration) { rollingCheckInterval = interval }(rollingCheckInterval)
+	rollingCheckInterval = time.Millisecond
+
+	t.Run("replace batch by batch", func(t *testing.T) {
+		mock := newFleetMock("inst_1", "inst_2", "inst_3")
+		driv := NewEc2Driver(mock).(*Ec2Driver)
+
+		ids, err := driv.Update_Instances(map[string]interface{}{"name": "web", "image": "ami-new", "batch": 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := ids, "new_1,new_2,new_3"; got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got, want := mock.terminated, [][]string{{"inst_1", "inst_2"}, {"inst_3"}}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		for _, input := range mock.launched {
+			if got, want := aws.StringValue(input.ImageId), "ami-new"; got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+			if got, want := aws.StringValue(input.SubnetId), "sub_1"; got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+			if got, want := aws.StringValueSlice(input.SecurityGroupIds), []string{"sg_1"}; !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+			if got, want := aws.StringValue(input.UserData), "IyEvYmluL3No"; got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		}
+		if got, want := mock.tagged["new_3"], "web"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("unhealthy replacements", func(t *testing.T) {
+		mock := newFleetMock("inst_1", "inst_2")
+		mock.unhealthy = true
+		driv := NewEc2Driver(mock).(*Ec2Driver)
+
+		_, err := driv.Update_Instances(map[string]interface{}{"name": "web", "image": "ami-new", "timeout": 1})
+		if err == nil || !strings.Contains(err.Error(), "not healthy") {
+			t.Fatalf("got %v, want not healthy error", err)
+		}
+		if got, want := mock.terminated, [][]string{{"new_1"}}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		driv := NewEc2Driver(newFleetMock()).(*Ec2Driver)
+		for _, params := range []map[string]interface{}{
+			{"name": "web"},
+			{"name": "web", "image": "ami-new", "batch": 0},
+			{"name": "web", "image": "ami-new", "healthcheck": "ping"},
+			{"name": "web", "image": "ami-new"},
+		} {
+			if _, err := driv.Update_Instances(params); err == nil {
+				t.Fatalf("%v: expected error", params)
+			}
+		}
+	})
+}
+
+type fleetMock struct {
+	ec2iface.EC2API
+	mu         sync.Mutex
+	fleet      []*ec2.Instance
+	unhealthy  bool
+	launched   []*ec2.RunInstancesInput
+	terminated [][]string
+	tagged     map[string]string
+}
+
+func newFleetMock(ids ...string) *fleetMock {
+	m := &fleetMock{tagged: make(map[string]string)}
+	for _, id := range ids {
+		m.fleet = append(m.fleet, &ec2.Instance{
+			InstanceId:     aws.String(id),
+			InstanceType:   aws.String("t2.micro"),
+			SubnetId:       aws.String("sub_1"),
+			SecurityGroups: []*ec2.GroupIdentifier{{GroupId: aws.String("sg_1")}},
+			Tags:           []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("web")}, {Key: aws.String("aws:cloudformation:stack-name"), Value: aws.String("stack")}},
+		})
+	}
+	return m
+}
+
+func (m *fleetMock) DescribeInstancesPages(input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+	fn(&ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: m.fleet}}}, true)
+	return nil
+}
+
+func (m *fleetMock) DescribeInstanceAttribute(input *ec2.DescribeInstanceAttributeInput) (*ec2.DescribeInstanceAttributeOutput, error) {
+	return &ec2.DescribeInstanceAttributeOutput{UserData: &ec2.AttributeValue{Value: aws.String("IyEvYmluL3No")}}, nil
+}
+
+func (m *fleetMock) RunInstances(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.launched = append(m.launched, input)
+	id := fmt.Sprintf("new_%d", len(m.launched))
+	return &ec2.Reservation{Instances: []*ec2.Instance{{InstanceId: aws.String(id)}}}, nil
+}
+
+func (m *fleetMock) CreateTags(input *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error) {
+	for _, tag := range input.Tags {
+		if strings.HasPrefix(aws.StringValue(tag.Key), "aws:") {
+			return nil, fmt.Errorf("reserved tag %s", aws.StringValue(tag.Key))
+		}
+	}
+	for _, id := range input.Resources {
+		m.tagged[aws.StringValue(id)] = aws.StringValue(input.Tags[0].Value)
+	}
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+func (m *fleetMock) DescribeInstanceStatus(input *ec2.DescribeInstanceStatusInput) (*ec2.DescribeInstanceStatusOutput, error) {
+	status := ec2.SummaryStatusOk
+	if m.unhealthy {
+		status = ec2.SummaryStatusInitializing
+	}
+	output := &ec2.DescribeInstanceStatusOutput{}
+	for _, id := range input.InstanceIds {
+		output.InstanceStatuses = append(output.InstanceStatuses, &ec2.InstanceStatus{
+			InstanceId:     id,
+			InstanceState:  &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameRunning)},
+			SystemStatus:   &ec2.InstanceStatusSummary{Status: aws.String(status)},
+			InstanceStatus: &ec2.InstanceStatusSummary{Status: aws.String(status)},
+		})
+	}
+	return output, nil
+}
+
+func (m *fleetMock) TerminateInstances(input *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
+	m.terminated = append(m.terminated, aws.StringValueSlice(input.InstanceIds))
+	return &ec2.TerminateInstancesOutput{}, nil
+}
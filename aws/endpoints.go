@@ -0,0 +1,281 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+const (
+	// EndpointKey is the config key of an endpoint URL used by all services. Ex: http://localhost:4566 for LocalStack
+	EndpointKey = "aws.endpoint"
+
+	// EndpointKeyPrefix prefixes the config keys of per service endpoint URLs, by endpoint ID.
+	// Ex: aws.endpoint.s3, aws.endpoint.ec2, aws.endpoint.sts
+	EndpointKeyPrefix = "aws.endpoint."
+
+	// ProxyKey is the config key of the proxy URL of AWS requests.
+	// Otherwise the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables are used
+	ProxyKey = "aws.proxy"
+
+	// FIPSKey is the config key preferring the FIPS 140-2 endpoints of services, where they exist
+	FIPSKey = "aws.fips"
+
+	// CABundleKey is the config key of a PEM file of certificates trusted in addition to the system ones,
+	// for TLS intercepting proxies. Otherwise the AWS_CA_BUNDLE environment variable is used
+	CABundleKey = "aws.ca.bundle"
+)
+
+// fipsServices are the services used by awless having FIPS endpoints in the US, Canada and GovCloud regions
+var fipsServices = map[string]bool{
+	"acm": true, "autoscaling": true, "cloudformation": true, "cloudtrail": true, "dynamodb": true,
+	"ec2": true, "ecr": true, "ecs": true, "elasticloadbalancing": true, "iam": true, "kms": true,
+	"lambda": true, "monitoring": true, "rds": true, "s3": true, "sns": true, "sqs": true, "sts": true,
+}
+
+// Endpoints configures the endpoints and the HTTP transport of every session
+var Endpoints = &EndpointsConfig{}
+
+// EndpointsConfig overrides the endpoints resolved by the SDK, which already knows
+// the standard, China and GovCloud partitions
+type EndpointsConfig struct {
+	Default  string
+	Services map[string]string
+	Proxy    *url.URL
+	FIPS     bool
+	CABundle string
+
+	caOnce  sync.Once
+	rootCAs *x509.CertPool
+	caErr   error
+}
+
+// ConfigureEndpoints loads the endpoints and proxy settings from config
+func ConfigureEndpoints(defaults map[string]interface{}) error {
+	conf := &EndpointsConfig{Services: make(map[string]string)}
+	for k, v := range defaults {
+		value := strings.TrimSpace(fmt.Sprint(v))
+		switch {
+		case value == "":
+		case k == EndpointKey:
+			conf.Default = value
+		case strings.HasPrefix(k, EndpointKeyPrefix):
+			conf.Services[strings.TrimPrefix(k, EndpointKeyPrefix)] = value
+		case k == ProxyKey:
+			proxy, err := ParseEndpointURL(value)
+			if err != nil {
+				return fmt.Errorf("%s: %s", ProxyKey, err)
+			}
+			conf.Proxy = proxy
+		case k == FIPSKey:
+			conf.FIPS = value == "true"
+		}
+	}
+	bundle, _ := defaults[CABundleKey].(string)
+	if bundle == "" {
+		bundle = os.Getenv("AWS_CA_BUNDLE")
+	}
+	conf.CABundle = bundle
+	if conf.Default != "" {
+		if _, err := ParseEndpointURL(conf.Default); err != nil {
+			return fmt.Errorf("%s: %s", EndpointKey, err)
+		}
+	}
+	for service, endpoint := range conf.Services {
+		if _, err := ParseEndpointURL(endpoint); err != nil {
+			return fmt.Errorf("%s%s: %s", EndpointKeyPrefix, service, err)
+		}
+	}
+	Endpoints = conf
+	return nil
+}
+
+// ParseEndpointURL parses an absolute http(s) URL
+func ParseEndpointURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL '%s': expecting http(s)://host[:port]", s)
+	}
+	return u, nil
+}
+
+// LoadCABundle returns the system certificates with the ones of the given PEM file
+func LoadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificate found in %s", path)
+	}
+	return pool, nil
+}
+
+// EndpointFor resolves the endpoint of a service, as an endpoints.Resolver
+func (e *EndpointsConfig) EndpointFor(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+	if endpoint, ok := e.Services[service]; ok {
+		return endpoints.ResolvedEndpoint{URL: endpoint, SigningRegion: region}, nil
+	}
+	if e.Default != "" {
+		return endpoints.ResolvedEndpoint{URL: e.Default, SigningRegion: region}, nil
+	}
+	resolved, err := endpoints.DefaultResolver().EndpointFor(service, region, opts...)
+	if err != nil || !e.FIPS {
+		return resolved, err
+	}
+	if fips, ok := fipsEndpoint(service, region, resolved.URL); ok {
+		if resolved.SigningRegion == "" {
+			resolved.SigningRegion = region
+		}
+		resolved.URL = fips
+	}
+	return resolved, nil
+}
+
+// fipsEndpoint returns the FIPS endpoint of a service: the service hostname suffixed with -fips
+// (ex: ec2-fips.us-east-1.amazonaws.com). Global endpoints but IAM become regional (ex: sts-fips.us-east-1.amazonaws.com)
+func fipsEndpoint(service, region, endpoint string) (string, bool) {
+	if !fipsServices[service] || !(strings.HasPrefix(region, "us-") || strings.HasPrefix(region, "ca-")) {
+		return endpoint, false
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint, false
+	}
+	labels := strings.SplitN(u.Host, ".", 2)
+	if len(labels) != 2 || strings.HasSuffix(labels[0], "-fips") {
+		return endpoint, false
+	}
+	switch {
+	case strings.Contains(labels[1], region), service == "iam":
+		u.Host = labels[0] + "-fips." + labels[1]
+	default:
+		u.Host = fmt.Sprintf("%s-fips.%s.%s", labels[0], region, labels[1])
+	}
+	return u.String(), true
+}
+
+// IsCustom returns whether a service has a custom endpoint
+func (e *EndpointsConfig) IsCustom(service string) bool {
+	_, ok := e.Services[service]
+	return ok || e.Default != ""
+}
+
+// String describes the custom endpoints, for verbose output
+func (e *EndpointsConfig) String() string {
+	var desc []string
+	if e.Default != "" {
+		desc = append(desc, e.Default)
+	}
+	desc = append(desc, e.list()...)
+	if e.FIPS {
+		desc = append(desc, "FIPS")
+	}
+	return strings.Join(desc, ", ")
+}
+
+func (e *EndpointsConfig) list() []string {
+	var services []string
+	for s := range e.Services {
+		services = append(services, s)
+	}
+	sort.Strings(services)
+	var list []string
+	for _, s := range services {
+		list = append(list, fmt.Sprintf("%s=%s", s, e.Services[s]))
+	}
+	return list
+}
+
+func (e *EndpointsConfig) sessionConfig(region string, timeout time.Duration) awssdk.Config {
+	conf := awssdk.Config{
+		Region:           awssdk.String(region),
+		EndpointResolver: e,
+		HTTPClient:       e.HTTPClient(timeout),
+	}
+	// S3 emulators (ex: LocalStack, minio) do not serve virtual hosted buckets
+	if e.IsCustom("s3") {
+		conf.S3ForcePathStyle = awssdk.Bool(true)
+	}
+	return conf
+}
+
+// HTTPClient returns a client using the proxy and the CA bundle of the config
+func (e *EndpointsConfig) HTTPClient(timeout time.Duration) *http.Client {
+	if e.Proxy == nil && e.CABundle == "" {
+		return &http.Client{Timeout: timeout}
+	}
+	transport := &http.Transport{}
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = t.Clone()
+	}
+	if e.Proxy != nil {
+		transport.Proxy = http.ProxyURL(e.Proxy)
+	}
+	if e.CABundle == "" {
+		return &http.Client{Timeout: timeout, Transport: transport}
+	}
+	return &http.Client{Timeout: timeout, Transport: &caBundleTransport{conf: e, base: transport}}
+}
+
+// caBundleTransport trusts the CA bundle of the config, loaded on the first request
+// since parsing the system and bundle certificates slows down commands not calling AWS
+type caBundleTransport struct {
+	conf *EndpointsConfig
+	base *http.Transport
+	once sync.Once
+}
+
+func (t *caBundleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	pool, err := t.conf.loadRootCAs()
+	if err != nil {
+		return nil, err
+	}
+	t.once.Do(func() {
+		t.base.TLSClientConfig = &tls.Config{RootCAs: pool}
+	})
+	return t.base.RoundTrip(req)
+}
+
+func (e *EndpointsConfig) loadRootCAs() (*x509.CertPool, error) {
+	e.caOnce.Do(func() {
+		if e.rootCAs, e.caErr = LoadCABundle(e.CABundle); e.caErr != nil {
+			e.caErr = fmt.Errorf("%s: %s", CABundleKey, e.caErr)
+		}
+	})
+	return e.rootCAs, e.caErr
+}
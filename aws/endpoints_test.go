@@ -0,0 +1,131 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigureEndpoints(t *testing.T) {
+	defer func(e *EndpointsConfig) { Endpoints = e }(Endpoints)
+
+	err := ConfigureEndpoints(map[string]interface{}{
+		"region":           "us-east-1",
+		"aws.endpoint":     "http://localhost:4566",
+		"aws.endpoint.sts": "https://sts.us-east-1.amazonaws.com",
+		"aws.proxy":        "http://proxy.corp:3128",
+		"aws.endpoint.iam": "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tcases := []struct {
+		service, want string
+	}{
+		{"ec2", "http://localhost:4566"},
+		{"s3", "http://localhost:4566"},
+		{"sts", "https://sts.us-east-1.amazonaws.com"},
+	}
+	for _, tcase := range tcases {
+		resolved, err := Endpoints.EndpointFor(tcase.service, "us-east-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := resolved.URL, tcase.want; got != want {
+			t.Fatalf("%s: got %s, want %s", tcase.service, got, want)
+		}
+		if got, want := resolved.SigningRegion, "us-east-1"; got != want {
+			t.Fatalf("%s: got %s, want %s", tcase.service, got, want)
+		}
+	}
+	if got, want := Endpoints.Proxy.Host, "proxy.corp:3128"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if conf := Endpoints.sessionConfig("us-east-1", 0); conf.S3ForcePathStyle == nil || !*conf.S3ForcePathStyle {
+		t.Fatal("expected path style S3 requests with a custom endpoint")
+	}
+
+	if err := ConfigureEndpoints(map[string]interface{}{"aws.endpoint.ec2": "localhost:4566"}); err == nil {
+		t.Fatal("expected error got none")
+	}
+
+	if err := ConfigureEndpoints(nil); err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := Endpoints.EndpointFor("ec2", "cn-north-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resolved.URL, "https://ec2.cn-north-1.amazonaws.com.cn"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestFIPSEndpoints(t *testing.T) {
+	conf := &EndpointsConfig{FIPS: true}
+	tcases := []struct {
+		service, region, want string
+	}{
+		{"ec2", "us-east-1", "https://ec2-fips.us-east-1.amazonaws.com"},
+		{"sts", "us-west-2", "https://sts-fips.us-west-2.amazonaws.com"},
+		{"iam", "us-east-1", "https://iam-fips.amazonaws.com"},
+		{"ec2", "eu-west-1", "https://ec2.eu-west-1.amazonaws.com"},
+		{"route53", "us-east-1", "https://route53.amazonaws.com"},
+	}
+	for _, tcase := range tcases {
+		resolved, err := conf.EndpointFor(tcase.service, tcase.region)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := resolved.URL, tcase.want; got != want {
+			t.Fatalf("%s in %s: got %s, want %s", tcase.service, tcase.region, got, want)
+		}
+	}
+}
+
+func TestLoadCABundle(t *testing.T) {
+	if _, err := LoadCABundle("testdata/none.pem"); err == nil {
+		t.Fatal("expected error got none")
+	}
+	f, err := ioutil.TempFile("", "awless-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("not a certificate")
+	f.Close()
+	if _, err := LoadCABundle(f.Name()); err == nil {
+		t.Fatal("expected error got none")
+	}
+}
+
+func TestCABundleLoadedOnFirstRequest(t *testing.T) {
+	conf := &EndpointsConfig{CABundle: "testdata/none.pem"}
+	client := conf.HTTPClient(time.Second)
+	if conf.rootCAs != nil || conf.caErr != nil {
+		t.Fatal("expected CA bundle not loaded before any request")
+	}
+	_, err := client.Get("https://localhost:1")
+	if err == nil || !strings.Contains(err.Error(), CABundleKey) {
+		t.Fatalf("got %v, want CA bundle error", err)
+	}
+}
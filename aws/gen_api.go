@@ -156,151 +156,86 @@ func (s *Infra) FetchResources() (*graph.Graph, error) {
 	g := graph.NewGraph()
 	regionN := graph.InitResource(s.region, graph.Region)
 	g.AddResource(regionN)
-	var instanceList []*ec2.Instance
-	var subnetList []*ec2.Subnet
-	var vpcList []*ec2.Vpc
-	var keypairList []*ec2.KeyPairInfo
-	var securitygroupList []*ec2.SecurityGroup
-	var volumeList []*ec2.Volume
-	var internetgatewayList []*ec2.InternetGateway
-	var routetableList []*ec2.RouteTable
-	var availabilityzoneList []*ec2.AvailabilityZone
-	var loadbalancerList []*elbv2.LoadBalancer
-	var targetgroupList []*elbv2.TargetGroup
+	deferred := &deferredRelations{}
 
 	errc := make(chan error)
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, instanceList, err = s.fetch_all_instance_graph()
-		if err != nil {
+		if err := s.fetch_all_instance_graph(g, streamRelations(g, "instance", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, subnetList, err = s.fetch_all_subnet_graph()
-		if err != nil {
+		if err := s.fetch_all_subnet_graph(g, streamRelations(g, "subnet", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, vpcList, err = s.fetch_all_vpc_graph()
-		if err != nil {
+		if err := s.fetch_all_vpc_graph(g, streamRelations(g, "vpc", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, keypairList, err = s.fetch_all_keypair_graph()
-		if err != nil {
+		if err := s.fetch_all_keypair_graph(g, streamRelations(g, "keypair", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, securitygroupList, err = s.fetch_all_securitygroup_graph()
-		if err != nil {
+		if err := s.fetch_all_securitygroup_graph(g, streamRelations(g, "securitygroup", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, volumeList, err = s.fetch_all_volume_graph()
-		if err != nil {
+		if err := s.fetch_all_volume_graph(g, streamRelations(g, "volume", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, internetgatewayList, err = s.fetch_all_internetgateway_graph()
-		if err != nil {
+		if err := s.fetch_all_internetgateway_graph(g, streamRelations(g, "internetgateway", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, routetableList, err = s.fetch_all_routetable_graph()
-		if err != nil {
+		if err := s.fetch_all_routetable_graph(g, streamRelations(g, "routetable", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, availabilityzoneList, err = s.fetch_all_availabilityzone_graph()
-		if err != nil {
+		if err := s.fetch_all_availabilityzone_graph(g, streamRelations(g, "availabilityzone", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, loadbalancerList, err = s.fetch_all_loadbalancer_graph()
-		if err != nil {
+		if err := s.fetch_all_loadbalancer_graph(g, streamRelations(g, "loadbalancer", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, targetgroupList, err = s.fetch_all_targetgroup_graph()
-		if err != nil {
+		if err := s.fetch_all_targetgroup_graph(g, streamRelations(g, "targetgroup", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 
 	go func() {
@@ -324,444 +259,224 @@ func (s *Infra) FetchResources() (*graph.Graph, error) {
 		}
 	}
 
-	errc = make(chan error)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range instanceList {
-			for _, fn := range addParentsFns["instance"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range subnetList {
-			for _, fn := range addParentsFns["subnet"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range vpcList {
-			for _, fn := range addParentsFns["vpc"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range keypairList {
-			for _, fn := range addParentsFns["keypair"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range securitygroupList {
-			for _, fn := range addParentsFns["securitygroup"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range volumeList {
-			for _, fn := range addParentsFns["volume"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range internetgatewayList {
-			for _, fn := range addParentsFns["internetgateway"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range routetableList {
-			for _, fn := range addParentsFns["routetable"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range availabilityzoneList {
-			for _, fn := range addParentsFns["availabilityzone"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range loadbalancerList {
-			for _, fn := range addParentsFns["loadbalancer"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range targetgroupList {
-			for _, fn := range addParentsFns["targetgroup"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-
-	go func() {
-		wg.Wait()
-		close(errc)
-	}()
-
-	for err := range errc {
-		if err != nil {
-			return g, err
-		}
-	}
-
-	return g, nil
+	return g, deferred.apply(g)
 }
 
 func (s *Infra) FetchByType(t string) (*graph.Graph, error) {
+	g := graph.NewGraph()
+	return g, s.fetchByType(g, t, nil)
+}
+
+func (s *Infra) fetchByType(g *graph.Graph, t string, each func(interface{}) error) error {
 	switch t {
 	case "instance":
-		graph, _, err := s.fetch_all_instance_graph()
-		return graph, err
+		return s.fetch_all_instance_graph(g, each)
 	case "subnet":
-		graph, _, err := s.fetch_all_subnet_graph()
-		return graph, err
+		return s.fetch_all_subnet_graph(g, each)
 	case "vpc":
-		graph, _, err := s.fetch_all_vpc_graph()
-		return graph, err
+		return s.fetch_all_vpc_graph(g, each)
 	case "keypair":
-		graph, _, err := s.fetch_all_keypair_graph()
-		return graph, err
+		return s.fetch_all_keypair_graph(g, each)
 	case "securitygroup":
-		graph, _, err := s.fetch_all_securitygroup_graph()
-		return graph, err
+		return s.fetch_all_securitygroup_graph(g, each)
 	case "volume":
-		graph, _, err := s.fetch_all_volume_graph()
-		return graph, err
+		return s.fetch_all_volume_graph(g, each)
 	case "internetgateway":
-		graph, _, err := s.fetch_all_internetgateway_graph()
-		return graph, err
+		return s.fetch_all_internetgateway_graph(g, each)
 	case "routetable":
-		graph, _, err := s.fetch_all_routetable_graph()
-		return graph, err
+		return s.fetch_all_routetable_graph(g, each)
 	case "availabilityzone":
-		graph, _, err := s.fetch_all_availabilityzone_graph()
-		return graph, err
+		return s.fetch_all_availabilityzone_graph(g, each)
 	case "loadbalancer":
-		graph, _, err := s.fetch_all_loadbalancer_graph()
-		return graph, err
+		return s.fetch_all_loadbalancer_graph(g, each)
 	case "targetgroup":
-		graph, _, err := s.fetch_all_targetgroup_graph()
-		return graph, err
+		return s.fetch_all_targetgroup_graph(g, each)
 	default:
-		return nil, fmt.Errorf("aws infra: unsupported fetch for type %s", t)
+		return fmt.Errorf("aws infra: unsupported fetch for type %s", t)
 	}
 }
 
-func (s *Infra) fetch_all_instance_graph() (*graph.Graph, []*ec2.Instance, error) {
-	g := graph.NewGraph()
-	var cloudResources []*ec2.Instance
+func (s *Infra) fetch_all_instance_graph(g *graph.Graph, each func(interface{}) error) error {
 	var badResErr error
 	err := s.DescribeInstancesPages(&ec2.DescribeInstancesInput{},
 		func(out *ec2.DescribeInstancesOutput, lastPage bool) (shouldContinue bool) {
 			for _, all := range out.Reservations {
 				for _, output := range all.Instances {
-					cloudResources = append(cloudResources, output)
-					var res *graph.Resource
-					res, badResErr = newResource(output)
-					if badResErr != nil {
+					if badResErr = addFetchedResource(g, output, each); badResErr != nil {
 						return false
 					}
-					g.AddResource(res)
 				}
 			}
 			return out.NextToken != nil
 		})
 	if err != nil {
-		return g, cloudResources, err
+		return err
 	}
 
-	return g, cloudResources, badResErr
+	return badResErr
 }
 
-func (s *Infra) fetch_all_subnet_graph() (*graph.Graph, []*ec2.Subnet, error) {
-	g := graph.NewGraph()
-	var cloudResources []*ec2.Subnet
+func (s *Infra) fetch_all_subnet_graph(g *graph.Graph, each func(interface{}) error) error {
 	out, err := s.DescribeSubnets(&ec2.DescribeSubnetsInput{})
 	if err != nil {
-		return nil, cloudResources, err
+		return err
 	}
 
 	for _, output := range out.Subnets {
-		cloudResources = append(cloudResources, output)
-		res, err := newResource(output)
-		if err != nil {
-			return g, cloudResources, err
+		if err := addFetchedResource(g, output, each); err != nil {
+			return err
 		}
-		g.AddResource(res)
 	}
 
-	return g, cloudResources, nil
+	return nil
 
 }
 
-func (s *Infra) fetch_all_vpc_graph() (*graph.Graph, []*ec2.Vpc, error) {
-	g := graph.NewGraph()
-	var cloudResources []*ec2.Vpc
+func (s *Infra) fetch_all_vpc_graph(g *graph.Graph, each func(interface{}) error) error {
 	out, err := s.DescribeVpcs(&ec2.DescribeVpcsInput{})
 	if err != nil {
-		return nil, cloudResources, err
+		return err
 	}
 
 	for _, output := range out.Vpcs {
-		cloudResources = append(cloudResources, output)
-		res, err := newResource(output)
-		if err != nil {
-			return g, cloudResources, err
+		if err := addFetchedResource(g, output, each); err != nil {
+			return err
 		}
-		g.AddResource(res)
 	}
 
-	return g, cloudResources, nil
+	return nil
 
 }
 
-func (s *Infra) fetch_all_keypair_graph() (*graph.Graph, []*ec2.KeyPairInfo, error) {
-	g := graph.NewGraph()
-	var cloudResources []*ec2.KeyPairInfo
+func (s *Infra) fetch_all_keypair_graph(g *graph.Graph, each func(interface{}) error) error {
 	out, err := s.DescribeKeyPairs(&ec2.DescribeKeyPairsInput{})
 	if err != nil {
-		return nil, cloudResources, err
+		return err
 	}
 
 	for _, output := range out.KeyPairs {
-		cloudResources = append(cloudResources, output)
-		res, err := newResource(output)
-		if err != nil {
-			return g, cloudResources, err
+		if err := addFetchedResource(g, output, each); err != nil {
+			return err
 		}
-		g.AddResource(res)
 	}
 
-	return g, cloudResources, nil
+	return nil
 
 }
 
-func (s *Infra) fetch_all_securitygroup_graph() (*graph.Graph, []*ec2.SecurityGroup, error) {
-	g := graph.NewGraph()
-	var cloudResources []*ec2.SecurityGroup
+func (s *Infra) fetch_all_securitygroup_graph(g *graph.Graph, each func(interface{}) error) error {
 	out, err := s.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{})
 	if err != nil {
-		return nil, cloudResources, err
+		return err
 	}
 
 	for _, output := range out.SecurityGroups {
-		cloudResources = append(cloudResources, output)
-		res, err := newResource(output)
-		if err != nil {
-			return g, cloudResources, err
+		if err := addFetchedResource(g, output, each); err != nil {
+			return err
 		}
-		g.AddResource(res)
 	}
 
-	return g, cloudResources, nil
+	return nil
 
 }
 
-func (s *Infra) fetch_all_volume_graph() (*graph.Graph, []*ec2.Volume, error) {
-	g := graph.NewGraph()
-	var cloudResources []*ec2.Volume
+func (s *Infra) fetch_all_volume_graph(g *graph.Graph, each func(interface{}) error) error {
 	var badResErr error
 	err := s.DescribeVolumesPages(&ec2.DescribeVolumesInput{},
 		func(out *ec2.DescribeVolumesOutput, lastPage bool) (shouldContinue bool) {
 			for _, output := range out.Volumes {
-				cloudResources = append(cloudResources, output)
-				var res *graph.Resource
-				res, badResErr = newResource(output)
-				if badResErr != nil {
+				if badResErr = addFetchedResource(g, output, each); badResErr != nil {
 					return false
 				}
-				g.AddResource(res)
 			}
 			return out.NextToken != nil
 		})
 	if err != nil {
-		return g, cloudResources, err
+		return err
 	}
 
-	return g, cloudResources, badResErr
+	return badResErr
 }
 
-func (s *Infra) fetch_all_internetgateway_graph() (*graph.Graph, []*ec2.InternetGateway, error) {
-	g := graph.NewGraph()
-	var cloudResources []*ec2.InternetGateway
+func (s *Infra) fetch_all_internetgateway_graph(g *graph.Graph, each func(interface{}) error) error {
 	out, err := s.DescribeInternetGateways(&ec2.DescribeInternetGatewaysInput{})
 	if err != nil {
-		return nil, cloudResources, err
+		return err
 	}
 
 	for _, output := range out.InternetGateways {
-		cloudResources = append(cloudResources, output)
-		res, err := newResource(output)
-		if err != nil {
-			return g, cloudResources, err
+		if err := addFetchedResource(g, output, each); err != nil {
+			return err
 		}
-		g.AddResource(res)
 	}
 
-	return g, cloudResources, nil
+	return nil
 
 }
 
-func (s *Infra) fetch_all_routetable_graph() (*graph.Graph, []*ec2.RouteTable, error) {
-	g := graph.NewGraph()
-	var cloudResources []*ec2.RouteTable
+func (s *Infra) fetch_all_routetable_graph(g *graph.Graph, each func(interface{}) error) error {
 	out, err := s.DescribeRouteTables(&ec2.DescribeRouteTablesInput{})
 	if err != nil {
-		return nil, cloudResources, err
+		return err
 	}
 
 	for _, output := range out.RouteTables {
-		cloudResources = append(cloudResources, output)
-		res, err := newResource(output)
-		if err != nil {
-			return g, cloudResources, err
+		if err := addFetchedResource(g, output, each); err != nil {
+			return err
 		}
-		g.AddResource(res)
 	}
 
-	return g, cloudResources, nil
+	return nil
 
 }
 
-func (s *Infra) fetch_all_availabilityzone_graph() (*graph.Graph, []*ec2.AvailabilityZone, error) {
-	g := graph.NewGraph()
-	var cloudResources []*ec2.AvailabilityZone
+func (s *Infra) fetch_all_availabilityzone_graph(g *graph.Graph, each func(interface{}) error) error {
 	out, err := s.DescribeAvailabilityZones(&ec2.DescribeAvailabilityZonesInput{})
 	if err != nil {
-		return nil, cloudResources, err
+		return err
 	}
 
 	for _, output := range out.AvailabilityZones {
-		cloudResources = append(cloudResources, output)
-		res, err := newResource(output)
-		if err != nil {
-			return g, cloudResources, err
+		if err := addFetchedResource(g, output, each); err != nil {
+			return err
 		}
-		g.AddResource(res)
 	}
 
-	return g, cloudResources, nil
+	return nil
 
 }
 
-func (s *Infra) fetch_all_loadbalancer_graph() (*graph.Graph, []*elbv2.LoadBalancer, error) {
-	g := graph.NewGraph()
-	var cloudResources []*elbv2.LoadBalancer
+func (s *Infra) fetch_all_loadbalancer_graph(g *graph.Graph, each func(interface{}) error) error {
 	var badResErr error
 	err := s.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{},
 		func(out *elbv2.DescribeLoadBalancersOutput, lastPage bool) (shouldContinue bool) {
 			for _, output := range out.LoadBalancers {
-				cloudResources = append(cloudResources, output)
-				var res *graph.Resource
-				res, badResErr = newResource(output)
-				if badResErr != nil {
+				if badResErr = addFetchedResource(g, output, each); badResErr != nil {
 					return false
 				}
-				g.AddResource(res)
 			}
 			return out.NextMarker != nil
 		})
 	if err != nil {
-		return g, cloudResources, err
+		return err
 	}
 
-	return g, cloudResources, badResErr
+	return badResErr
 }
 
-func (s *Infra) fetch_all_targetgroup_graph() (*graph.Graph, []*elbv2.TargetGroup, error) {
-	g := graph.NewGraph()
-	var cloudResources []*elbv2.TargetGroup
+func (s *Infra) fetch_all_targetgroup_graph(g *graph.Graph, each func(interface{}) error) error {
 	out, err := s.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{})
 	if err != nil {
-		return nil, cloudResources, err
+		return err
 	}
 
 	for _, output := range out.TargetGroups {
-		cloudResources = append(cloudResources, output)
-		res, err := newResource(output)
-		if err != nil {
-			return g, cloudResources, err
+		if err := addFetchedResource(g, output, each); err != nil {
+			return err
 		}
-		g.AddResource(res)
 	}
 
-	return g, cloudResources, nil
+	return nil
 
 }
 
@@ -801,60 +516,37 @@ func (s *Access) FetchResources() (*graph.Graph, error) {
 	g := graph.NewGraph()
 	regionN := graph.InitResource(s.region, graph.Region)
 	g.AddResource(regionN)
-	var userList []*iam.UserDetail
-	var groupList []*iam.GroupDetail
-	var roleList []*iam.RoleDetail
-	var policyList []*iam.Policy
+	deferred := &deferredRelations{}
 
 	errc := make(chan error)
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, userList, err = s.fetch_all_user_graph()
-		if err != nil {
+		if err := s.fetch_all_user_graph(g, streamRelations(g, "user", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, groupList, err = s.fetch_all_group_graph()
-		if err != nil {
+		if err := s.fetch_all_group_graph(g, streamRelations(g, "group", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, roleList, err = s.fetch_all_role_graph()
-		if err != nil {
+		if err := s.fetch_all_role_graph(g, streamRelations(g, "role", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, policyList, err = s.fetch_all_policy_graph()
-		if err != nil {
+		if err := s.fetch_all_policy_graph(g, streamRelations(g, "policy", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 
 	go func() {
@@ -878,163 +570,81 @@ func (s *Access) FetchResources() (*graph.Graph, error) {
 		}
 	}
 
-	errc = make(chan error)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range userList {
-			for _, fn := range addParentsFns["user"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range groupList {
-			for _, fn := range addParentsFns["group"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range roleList {
-			for _, fn := range addParentsFns["role"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range policyList {
-			for _, fn := range addParentsFns["policy"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-
-	go func() {
-		wg.Wait()
-		close(errc)
-	}()
-
-	for err := range errc {
-		if err != nil {
-			return g, err
-		}
-	}
-
-	return g, nil
+	return g, deferred.apply(g)
 }
 
 func (s *Access) FetchByType(t string) (*graph.Graph, error) {
+	g := graph.NewGraph()
+	return g, s.fetchByType(g, t, nil)
+}
+
+func (s *Access) fetchByType(g *graph.Graph, t string, each func(interface{}) error) error {
 	switch t {
 	case "user":
-		graph, _, err := s.fetch_all_user_graph()
-		return graph, err
+		return s.fetch_all_user_graph(g, each)
 	case "group":
-		graph, _, err := s.fetch_all_group_graph()
-		return graph, err
+		return s.fetch_all_group_graph(g, each)
 	case "role":
-		graph, _, err := s.fetch_all_role_graph()
-		return graph, err
+		return s.fetch_all_role_graph(g, each)
 	case "policy":
-		graph, _, err := s.fetch_all_policy_graph()
-		return graph, err
+		return s.fetch_all_policy_graph(g, each)
 	default:
-		return nil, fmt.Errorf("aws access: unsupported fetch for type %s", t)
+		return fmt.Errorf("aws access: unsupported fetch for type %s", t)
 	}
 }
 
-func (s *Access) fetch_all_group_graph() (*graph.Graph, []*iam.GroupDetail, error) {
-	g := graph.NewGraph()
-	var cloudResources []*iam.GroupDetail
+func (s *Access) fetch_all_group_graph(g *graph.Graph, each func(interface{}) error) error {
 	var badResErr error
 	err := s.GetAccountAuthorizationDetailsPages(&iam.GetAccountAuthorizationDetailsInput{Filter: []*string{awssdk.String(iam.EntityTypeGroup)}},
 		func(out *iam.GetAccountAuthorizationDetailsOutput, lastPage bool) (shouldContinue bool) {
 			for _, output := range out.GroupDetailList {
-				cloudResources = append(cloudResources, output)
-				var res *graph.Resource
-				res, badResErr = newResource(output)
-				if badResErr != nil {
+				if badResErr = addFetchedResource(g, output, each); badResErr != nil {
 					return false
 				}
-				g.AddResource(res)
 			}
 			return out.Marker != nil
 		})
 	if err != nil {
-		return g, cloudResources, err
+		return err
 	}
 
-	return g, cloudResources, badResErr
+	return badResErr
 }
 
-func (s *Access) fetch_all_role_graph() (*graph.Graph, []*iam.RoleDetail, error) {
-	g := graph.NewGraph()
-	var cloudResources []*iam.RoleDetail
+func (s *Access) fetch_all_role_graph(g *graph.Graph, each func(interface{}) error) error {
 	var badResErr error
 	err := s.GetAccountAuthorizationDetailsPages(&iam.GetAccountAuthorizationDetailsInput{Filter: []*string{awssdk.String(iam.EntityTypeRole)}},
 		func(out *iam.GetAccountAuthorizationDetailsOutput, lastPage bool) (shouldContinue bool) {
 			for _, output := range out.RoleDetailList {
-				cloudResources = append(cloudResources, output)
-				var res *graph.Resource
-				res, badResErr = newResource(output)
-				if badResErr != nil {
+				if badResErr = addFetchedResource(g, output, each); badResErr != nil {
 					return false
 				}
-				g.AddResource(res)
 			}
 			return out.Marker != nil
 		})
 	if err != nil {
-		return g, cloudResources, err
+		return err
 	}
 
-	return g, cloudResources, badResErr
+	return badResErr
 }
 
-func (s *Access) fetch_all_policy_graph() (*graph.Graph, []*iam.Policy, error) {
-	g := graph.NewGraph()
-	var cloudResources []*iam.Policy
+func (s *Access) fetch_all_policy_graph(g *graph.Graph, each func(interface{}) error) error {
 	var badResErr error
 	err := s.ListPoliciesPages(&iam.ListPoliciesInput{OnlyAttached: awssdk.Bool(true)},
 		func(out *iam.ListPoliciesOutput, lastPage bool) (shouldContinue bool) {
 			for _, output := range out.Policies {
-				cloudResources = append(cloudResources, output)
-				var res *graph.Resource
-				res, badResErr = newResource(output)
-				if badResErr != nil {
+				if badResErr = addFetchedResource(g, output, each); badResErr != nil {
 					return false
 				}
-				g.AddResource(res)
 			}
 			return out.Marker != nil
 		})
 	if err != nil {
-		return g, cloudResources, err
+		return err
 	}
 
-	return g, cloudResources, badResErr
+	return badResErr
 }
 
 type Storage struct {
@@ -1071,34 +681,23 @@ func (s *Storage) FetchResources() (*graph.Graph, error) {
 	g := graph.NewGraph()
 	regionN := graph.InitResource(s.region, graph.Region)
 	g.AddResource(regionN)
-	var bucketList []*s3.Bucket
-	var storageobjectList []*s3.Object
+	deferred := &deferredRelations{}
 
 	errc := make(chan error)
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, bucketList, err = s.fetch_all_bucket_graph()
-		if err != nil {
+		if err := s.fetch_all_bucket_graph(g, streamRelations(g, "bucket", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, storageobjectList, err = s.fetch_all_storageobject_graph()
-		if err != nil {
+		if err := s.fetch_all_storageobject_graph(g, streamRelations(g, "storageobject", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 
 	go func() {
@@ -1122,58 +721,22 @@ func (s *Storage) FetchResources() (*graph.Graph, error) {
 		}
 	}
 
-	errc = make(chan error)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range bucketList {
-			for _, fn := range addParentsFns["bucket"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range storageobjectList {
-			for _, fn := range addParentsFns["storageobject"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-
-	go func() {
-		wg.Wait()
-		close(errc)
-	}()
-
-	for err := range errc {
-		if err != nil {
-			return g, err
-		}
-	}
-
-	return g, nil
+	return g, deferred.apply(g)
 }
 
 func (s *Storage) FetchByType(t string) (*graph.Graph, error) {
+	g := graph.NewGraph()
+	return g, s.fetchByType(g, t, nil)
+}
+
+func (s *Storage) fetchByType(g *graph.Graph, t string, each func(interface{}) error) error {
 	switch t {
 	case "bucket":
-		graph, _, err := s.fetch_all_bucket_graph()
-		return graph, err
+		return s.fetch_all_bucket_graph(g, each)
 	case "storageobject":
-		graph, _, err := s.fetch_all_storageobject_graph()
-		return graph, err
+		return s.fetch_all_storageobject_graph(g, each)
 	default:
-		return nil, fmt.Errorf("aws storage: unsupported fetch for type %s", t)
+		return fmt.Errorf("aws storage: unsupported fetch for type %s", t)
 	}
 }
 
@@ -1211,34 +774,23 @@ func (s *Notification) FetchResources() (*graph.Graph, error) {
 	g := graph.NewGraph()
 	regionN := graph.InitResource(s.region, graph.Region)
 	g.AddResource(regionN)
-	var subscriptionList []*sns.Subscription
-	var topicList []*sns.Topic
+	deferred := &deferredRelations{}
 
 	errc := make(chan error)
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, subscriptionList, err = s.fetch_all_subscription_graph()
-		if err != nil {
+		if err := s.fetch_all_subscription_graph(g, streamRelations(g, "subscription", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, topicList, err = s.fetch_all_topic_graph()
-		if err != nil {
+		if err := s.fetch_all_topic_graph(g, streamRelations(g, "topic", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 
 	go func() {
@@ -1262,107 +814,59 @@ func (s *Notification) FetchResources() (*graph.Graph, error) {
 		}
 	}
 
-	errc = make(chan error)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range subscriptionList {
-			for _, fn := range addParentsFns["subscription"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range topicList {
-			for _, fn := range addParentsFns["topic"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-
-	go func() {
-		wg.Wait()
-		close(errc)
-	}()
-
-	for err := range errc {
-		if err != nil {
-			return g, err
-		}
-	}
-
-	return g, nil
+	return g, deferred.apply(g)
 }
 
 func (s *Notification) FetchByType(t string) (*graph.Graph, error) {
+	g := graph.NewGraph()
+	return g, s.fetchByType(g, t, nil)
+}
+
+func (s *Notification) fetchByType(g *graph.Graph, t string, each func(interface{}) error) error {
 	switch t {
 	case "subscription":
-		graph, _, err := s.fetch_all_subscription_graph()
-		return graph, err
+		return s.fetch_all_subscription_graph(g, each)
 	case "topic":
-		graph, _, err := s.fetch_all_topic_graph()
-		return graph, err
+		return s.fetch_all_topic_graph(g, each)
 	default:
-		return nil, fmt.Errorf("aws notification: unsupported fetch for type %s", t)
+		return fmt.Errorf("aws notification: unsupported fetch for type %s", t)
 	}
 }
 
-func (s *Notification) fetch_all_subscription_graph() (*graph.Graph, []*sns.Subscription, error) {
-	g := graph.NewGraph()
-	var cloudResources []*sns.Subscription
+func (s *Notification) fetch_all_subscription_graph(g *graph.Graph, each func(interface{}) error) error {
 	var badResErr error
 	err := s.ListSubscriptionsPages(&sns.ListSubscriptionsInput{},
 		func(out *sns.ListSubscriptionsOutput, lastPage bool) (shouldContinue bool) {
 			for _, output := range out.Subscriptions {
-				cloudResources = append(cloudResources, output)
-				var res *graph.Resource
-				res, badResErr = newResource(output)
-				if badResErr != nil {
+				if badResErr = addFetchedResource(g, output, each); badResErr != nil {
 					return false
 				}
-				g.AddResource(res)
 			}
 			return out.NextToken != nil
 		})
 	if err != nil {
-		return g, cloudResources, err
+		return err
 	}
 
-	return g, cloudResources, badResErr
+	return badResErr
 }
 
-func (s *Notification) fetch_all_topic_graph() (*graph.Graph, []*sns.Topic, error) {
-	g := graph.NewGraph()
-	var cloudResources []*sns.Topic
+func (s *Notification) fetch_all_topic_graph(g *graph.Graph, each func(interface{}) error) error {
 	var badResErr error
 	err := s.ListTopicsPages(&sns.ListTopicsInput{},
 		func(out *sns.ListTopicsOutput, lastPage bool) (shouldContinue bool) {
 			for _, output := range out.Topics {
-				cloudResources = append(cloudResources, output)
-				var res *graph.Resource
-				res, badResErr = newResource(output)
-				if badResErr != nil {
+				if badResErr = addFetchedResource(g, output, each); badResErr != nil {
 					return false
 				}
-				g.AddResource(res)
 			}
 			return out.NextToken != nil
 		})
 	if err != nil {
-		return g, cloudResources, err
+		return err
 	}
 
-	return g, cloudResources, badResErr
+	return badResErr
 }
 
 type Queue struct {
@@ -1398,21 +902,16 @@ func (s *Queue) FetchResources() (*graph.Graph, error) {
 	g := graph.NewGraph()
 	regionN := graph.InitResource(s.region, graph.Region)
 	g.AddResource(regionN)
-	var queueList []*string
+	deferred := &deferredRelations{}
 
 	errc := make(chan error)
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, queueList, err = s.fetch_all_queue_graph()
-		if err != nil {
+		if err := s.fetch_all_queue_graph(g, streamRelations(g, "queue", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
 
 	go func() {
@@ -1436,41 +935,19 @@ func (s *Queue) FetchResources() (*graph.Graph, error) {
 		}
 	}
 
-	errc = make(chan error)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range queueList {
-			for _, fn := range addParentsFns["queue"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-
-	go func() {
-		wg.Wait()
-		close(errc)
-	}()
-
-	for err := range errc {
-		if err != nil {
-			return g, err
-		}
-	}
-
-	return g, nil
+	return g, deferred.apply(g)
 }
 
 func (s *Queue) FetchByType(t string) (*graph.Graph, error) {
+	g := graph.NewGraph()
+	return g, s.fetchByType(g, t, nil)
+}
+
+func (s *Queue) fetchByType(g *graph.Graph, t string, each func(interface{}) error) error {
 	switch t {
 	case "queue":
-		graph, _, err := s.fetch_all_queue_graph()
-		return graph, err
+		return s.fetch_all_queue_graph(g, each)
 	default:
-		return nil, fmt.Errorf("aws queue: unsupported fetch for type %s", t)
+		return fmt.Errorf("aws queue: unsupported fetch for type %s", t)
 	}
 }
@@ -0,0 +1,207 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/endpointcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+const (
+	CallerUser        = "user"
+	CallerAssumedRole = "assumed-role"
+	CallerRoot        = "root"
+	CallerFederated   = "federated-user"
+)
+
+// CallerDetails gathers what IAM knows about the current caller.
+// Lookups failing (usually for lack of permissions) are collected in Errors
+// so that the details found so far can still be displayed
+type CallerDetails struct {
+	Type, Name, Session string
+	AccountAliases      []string
+	AttachedPolicies    []string
+	InlinePolicies      []string
+	GroupPolicies       map[string][]string
+	MFADevices          []string
+	Errors              []error
+}
+
+// ParseCallerArn extracts the kind of caller, its name and session name (for assumed roles)
+// from an ARN as returned by sts:GetCallerIdentity
+func ParseCallerArn(arn string) (kind, name, session string) {
+	splits := strings.SplitN(arn, ":", 6)
+	if len(splits) < 6 {
+		return
+	}
+	resource := splits[5]
+	if resource == "root" {
+		return CallerRoot, "", ""
+	}
+	parts := strings.Split(resource, "/")
+	kind = parts[0]
+	switch kind {
+	case CallerUser, CallerFederated:
+		name = parts[len(parts)-1]
+	case CallerAssumedRole:
+		if len(parts) > 1 {
+			name = parts[1]
+		}
+		if len(parts) > 2 {
+			session = parts[2]
+		}
+	}
+	return
+}
+
+func (s *Access) GetCallerDetails(arn string) *CallerDetails {
+	details := &CallerDetails{GroupPolicies: make(map[string][]string)}
+	details.Type, details.Name, details.Session = ParseCallerArn(arn)
+
+	addErr := func(what string, err error) {
+		details.Errors = append(details.Errors, fmt.Errorf("%s: %s", what, err))
+	}
+
+	if out, err := s.ListAccountAliases(&iam.ListAccountAliasesInput{}); err != nil {
+		addErr("account alias", err)
+	} else {
+		details.AccountAliases = awssdk.StringValueSlice(out.AccountAliases)
+	}
+
+	switch details.Type {
+	case CallerUser:
+		user := awssdk.String(details.Name)
+		if out, err := s.ListAttachedUserPolicies(&iam.ListAttachedUserPoliciesInput{UserName: user}); err != nil {
+			addErr("attached policies", err)
+		} else {
+			for _, pol := range out.AttachedPolicies {
+				details.AttachedPolicies = append(details.AttachedPolicies, awssdk.StringValue(pol.PolicyName))
+			}
+		}
+		if out, err := s.ListUserPolicies(&iam.ListUserPoliciesInput{UserName: user}); err != nil {
+			addErr("inline policies", err)
+		} else {
+			details.InlinePolicies = awssdk.StringValueSlice(out.PolicyNames)
+		}
+		if out, err := s.ListGroupsForUser(&iam.ListGroupsForUserInput{UserName: user}); err != nil {
+			addErr("groups", err)
+		} else {
+			for _, group := range out.Groups {
+				name := awssdk.StringValue(group.GroupName)
+				details.GroupPolicies[name] = []string{}
+				if attached, err := s.ListAttachedGroupPolicies(&iam.ListAttachedGroupPoliciesInput{GroupName: group.GroupName}); err != nil {
+					addErr(fmt.Sprintf("group %s attached policies", name), err)
+				} else {
+					for _, pol := range attached.AttachedPolicies {
+						details.GroupPolicies[name] = append(details.GroupPolicies[name], awssdk.StringValue(pol.PolicyName))
+					}
+				}
+				if inline, err := s.ListGroupPolicies(&iam.ListGroupPoliciesInput{GroupName: group.GroupName}); err != nil {
+					addErr(fmt.Sprintf("group %s inline policies", name), err)
+				} else {
+					details.GroupPolicies[name] = append(details.GroupPolicies[name], awssdk.StringValueSlice(inline.PolicyNames)...)
+				}
+			}
+		}
+		if out, err := s.ListMFADevices(&iam.ListMFADevicesInput{UserName: user}); err != nil {
+			addErr("mfa devices", err)
+		} else {
+			for _, dev := range out.MFADevices {
+				details.MFADevices = append(details.MFADevices, awssdk.StringValue(dev.SerialNumber))
+			}
+		}
+	case CallerAssumedRole:
+		role := awssdk.String(details.Name)
+		if out, err := s.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{RoleName: role}); err != nil {
+			addErr("attached policies", err)
+		} else {
+			for _, pol := range out.AttachedPolicies {
+				details.AttachedPolicies = append(details.AttachedPolicies, awssdk.StringValue(pol.PolicyName))
+			}
+		}
+		if out, err := s.ListRolePolicies(&iam.ListRolePoliciesInput{RoleName: role}); err != nil {
+			addErr("inline policies", err)
+		} else {
+			details.InlinePolicies = awssdk.StringValueSlice(out.PolicyNames)
+		}
+	}
+
+	return details
+}
+
+// CredentialsSource returns a human readable origin (env, file, role, ...) of the given credentials provider name
+func CredentialsSource(providerName string) string {
+	switch {
+	case providerName == credentials.EnvProviderName, providerName == "EnvConfigCredentials":
+		return "env"
+	case providerName == credentials.SharedCredsProviderName:
+		return "file"
+	case strings.HasPrefix(providerName, "SharedConfigCredentials: "):
+		return fmt.Sprintf("file (%s)", strings.TrimPrefix(providerName, "SharedConfigCredentials: "))
+	case providerName == stscreds.ProviderName:
+		return "role"
+	case providerName == ec2rolecreds.ProviderName:
+		return "instance role"
+	case providerName == endpointcreds.ProviderName:
+		return "container role"
+	case providerName == ProcessProviderName:
+		return "process"
+	case providerName == credentials.StaticProviderName:
+		return "static"
+	case providerName == "":
+		return "unknown"
+	default:
+		return providerName
+	}
+}
+
+func (s *security) CredentialsSource() (string, error) {
+	if s.creds == nil {
+		return "", fmt.Errorf("no credentials")
+	}
+	val, err := s.creds.Get()
+	if err != nil {
+		return "", err
+	}
+	return CredentialsSource(val.ProviderName), nil
+}
+
+// SessionExpiry estimates when the credentials of an assumed role expire.
+// The SDK does not expose the expiration: it is known for roles assumed by awless,
+// otherwise credentials are retrieved when the session is created and are valid
+// for the provider default duration
+func (s *security) SessionExpiry() (time.Time, bool) {
+	if s.creds == nil {
+		return time.Time{}, false
+	}
+	val, err := s.creds.Get()
+	if err != nil || val.ProviderName != stscreds.ProviderName {
+		return time.Time{}, false
+	}
+	if exp, ok := credentialsExpiration(val.AccessKeyID); ok {
+		return exp, true
+	}
+	return s.retrievedAt.Add(stscreds.DefaultDuration), true
+}
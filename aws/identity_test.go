@@ -0,0 +1,91 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import "testing"
+
+func TestParseCallerArn(t *testing.T) {
+	tcases := []struct {
+		arn                 string
+		kind, name, session string
+	}{
+		{arn: "arn:aws:iam::123456789012:user/john", kind: "user", name: "john"},
+		{arn: "arn:aws:iam::123456789012:user/division/team/john", kind: "user", name: "john"},
+		{arn: "arn:aws:sts::123456789012:assumed-role/admin/awless-session", kind: "assumed-role", name: "admin", session: "awless-session"},
+		{arn: "arn:aws:iam::123456789012:root", kind: "root"},
+		{arn: "arn:aws:sts::123456789012:federated-user/bob", kind: "federated-user", name: "bob"},
+		{arn: "invalid"},
+	}
+	for _, tcase := range tcases {
+		kind, name, session := ParseCallerArn(tcase.arn)
+		if got, want := kind, tcase.kind; got != want {
+			t.Fatalf("%s: got %s, want %s", tcase.arn, got, want)
+		}
+		if got, want := name, tcase.name; got != want {
+			t.Fatalf("%s: got %s, want %s", tcase.arn, got, want)
+		}
+		if got, want := session, tcase.session; got != want {
+			t.Fatalf("%s: got %s, want %s", tcase.arn, got, want)
+		}
+	}
+}
+
+func TestCredentialsSource(t *testing.T) {
+	tcases := map[string]string{
+		"EnvConfigCredentials": "env",
+		"EnvProvider":          "env",
+		"SharedConfigCredentials: /home/john/.aws/cfg": "file (/home/john/.aws/cfg)",
+		"SharedCredentialsProvider":                    "file",
+		"AssumeRoleProvider":                           "role",
+		"EC2RoleProvider":                              "instance role",
+		"ProcessProvider":                              "process",
+		"":                                             "unknown",
+	}
+	for name, want := range tcases {
+		if got := CredentialsSource(name); got != want {
+			t.Fatalf("%q: got %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestPrincipalArn(t *testing.T) {
+	tcases := []struct {
+		caller, exp string
+		err         bool
+	}{
+		{caller: "arn:aws:iam::123456789012:user/john", exp: "arn:aws:iam::123456789012:user/john"},
+		{caller: "arn:aws:sts::123456789012:assumed-role/admin/session", exp: "arn:aws:iam::123456789012:role/admin"},
+		{caller: "arn:aws-cn:sts::123456789012:assumed-role/ci/build", exp: "arn:aws-cn:iam::123456789012:role/ci"},
+		{caller: "arn:aws:iam::123456789012:root", err: true},
+		{caller: "arn:aws:sts::123456789012:federated-user/bob", err: true},
+	}
+	for i, tcase := range tcases {
+		arn, err := PrincipalArn(tcase.caller)
+		if tcase.err {
+			if err == nil {
+				t.Fatalf("%d: expected error got none", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := arn, tcase.exp; got != want {
+			t.Fatalf("%d: got %s, want %s", i+1, got, want)
+		}
+	}
+}
@@ -18,38 +18,124 @@ package aws
 
 import (
 	"errors"
-	"net/http"
+	"fmt"
+	"strings"
 	"time"
 
 	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/tracing"
 )
 
 var (
-	AccessService, InfraService, StorageService, NotificationService, QueueService cloud.Service
+	AccessService, InfraService, StorageService, NotificationService, QueueService, CloudFormationService cloud.Service
 
 	SecuAPI Security
+
+	TrailAPI *CloudTrail
+
+	KMSAPI *KMS
+
+	PricingAPI cloud.PriceSource
 )
 
+func init() {
+	cloud.RegisterProvider(provider{})
+}
+
+type provider struct{}
+
+func (provider) Name() string { return "aws" }
+
+// InitServices registers the AWS services for the region and profile of the given config values
+func (provider) InitServices(defaults map[string]interface{}) error {
+	region, _ := defaults[database.RegionKey].(string)
+	if region == "" {
+		return errors.New("missing region. Set it with `awless config set region`")
+	}
+	profile, _ := defaults[database.ProfileKey].(string)
+	return InitServices(region, profile)
+}
+
 func InitSession(region, profile string) (*session.Session, error) {
-	session, err := session.NewSessionWithOptions(session.Options{
-		Config:                  awssdk.Config{Region: awssdk.String(region), HTTPClient: &http.Client{Timeout: 2 * time.Second}},
-		SharedConfigState:       session.SharedConfigEnable,
-		AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
-		Profile:                 profile,
-	})
+	source, roles, err := profileRoles(profile)
 	if err != nil {
 		return nil, err
 	}
+	if RoleOverride != nil {
+		roles = append(roles, RoleOverride)
+	}
 
-	if _, err = session.Config.Credentials.Get(); err != nil {
-		return nil, errors.New("Your AWS credentials seem undefined! AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY need to be exported in your CLI environment\nInstallation documentation is at https://github.com/wallix/awless/wiki/Installation")
+	var sess *session.Session
+	if source == nil {
+		sess, err = session.NewSessionWithOptions(session.Options{
+			Config:                  Endpoints.sessionConfig(region, 2*time.Second),
+			SharedConfigState:       session.SharedConfigEnable,
+			AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+			Profile:                 profile,
+		})
+	} else {
+		// the SDK neither chains roles nor runs credential processes
+		sess, err = session.NewSessionWithOptions(session.Options{
+			Config:            Endpoints.sessionConfig(region, 2*time.Second),
+			SharedConfigState: session.SharedConfigDisable,
+		})
+		if err == nil {
+			sess = sess.Copy(&awssdk.Config{Credentials: source.sourceCredentials(sess)})
+		}
 	}
-	session.Config.HTTPClient = http.DefaultClient
+	if err != nil {
+		return nil, err
+	}
+	var scope string
+	for _, role := range roles {
+		scope += role.scope()
+		sess = sess.Copy(&awssdk.Config{Credentials: role.credentials(sess, profile, scope)})
+	}
+
+	sess.Config.HTTPClient = Endpoints.HTTPClient(0)
+	tracing.InstrumentAWS(&sess.Handlers)
+	sess.Handlers.Validate.PushBackNamed(credentialsErrorHandler(roles, source != nil))
+	sess.Handlers.Retry.PushBackNamed(RefreshExpiredCredentialsHandler)
+	sess.Handlers.Send.PushFrontNamed(RateLimitWaitHandler)
+	sess.Handlers.ValidateResponse.PushBackNamed(RateLimitSuccessHandler)
+	sess.Handlers.Retry.PushBackNamed(RateLimitThrottleHandler)
+	sess.Config.Retryer = Retries.Retryer()
+	sess.Handlers.Validate.PushBackNamed(Retries.CircuitBreakerHandler())
+	sess.Handlers.AfterRetry.PushFrontNamed(Retries.RetryBudgetHandler())
+	sess.Handlers.AfterRetry.PushBackNamed(Retries.RetryFailureHandler())
+	sess.Handlers.ValidateResponse.PushBackNamed(Retries.RetrySuccessHandler())
 
-	return session, nil
+	return sess, nil
+}
+
+// credentialsErrorHandler explains why the credentials of a session cannot be retrieved.
+// They are only retrieved for the first request, so that commands not calling AWS start fast
+func credentialsErrorHandler(roles []*AssumeRole, hasSource bool) request.NamedHandler {
+	return request.NamedHandler{Name: "awless.CredentialsErrorHandler", Fn: func(r *request.Request) {
+		if r.Error != nil || r.Config.Credentials == credentials.AnonymousCredentials {
+			return
+		}
+		_, err := r.Config.Credentials.Get()
+		switch {
+		case err == nil:
+		case len(roles) > 0:
+			var arns []string
+			for _, role := range roles {
+				arns = append(arns, role.Arn)
+			}
+			r.Error = fmt.Errorf("cannot assume role %s: %s", strings.Join(arns, " -> "), err)
+		case hasSource:
+			r.Error = err
+		default:
+			r.Error = errors.New("Your AWS credentials seem undefined! AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY need to be exported in your CLI environment\nInstallation documentation is at https://github.com/wallix/awless/wiki/Installation")
+		}
+	}}
 }
 
 func InitServices(region, profile string) error {
@@ -61,14 +147,43 @@ func InitServices(region, profile string) error {
 	InfraService = NewInfra(sess)
 	StorageService = NewStorage(sess)
 	SecuAPI = NewSecu(sess)
+	TrailAPI = NewCloudTrail(sess)
+	KMSAPI = NewKMS(sess)
+	PricingAPI = NewPricing(sess)
 	NotificationService = NewNotification(sess)
 	QueueService = NewQueue(sess)
+	CloudFormationService = NewCloudFormation(sess)
 
 	cloud.ServiceRegistry[InfraService.Name()] = InfraService
 	cloud.ServiceRegistry[AccessService.Name()] = AccessService
 	cloud.ServiceRegistry[StorageService.Name()] = StorageService
 	cloud.ServiceRegistry[NotificationService.Name()] = NotificationService
 	cloud.ServiceRegistry[QueueService.Name()] = QueueService
+	cloud.ServiceRegistry[CloudFormationService.Name()] = CloudFormationService
 
 	return nil
 }
+
+// NewServiceForRegion returns a new instance of the named service bound to the given region
+func NewServiceForRegion(srvName, region, profile string) (cloud.Service, error) {
+	sess, err := InitSession(region, profile)
+	if err != nil {
+		return nil, err
+	}
+	switch srvName {
+	case "infra":
+		return NewInfra(sess), nil
+	case "access":
+		return NewAccess(sess), nil
+	case "storage":
+		return NewStorage(sess), nil
+	case "notification":
+		return NewNotification(sess), nil
+	case "queue":
+		return NewQueue(sess), nil
+	case cloudformationServiceName:
+		return NewCloudFormation(sess), nil
+	default:
+		return nil, fmt.Errorf("unknown service '%s'", srvName)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+// JSONRPCClient calls the AWS APIs using the JSON RPC protocol (ex: CloudTrail, DynamoDB)
+// whose SDK packages are not vendored. Inputs and outputs are plain JSON structs
+type JSONRPCClient struct {
+	*client.Client
+}
+
+// NewJSONRPCClient returns a client signing its requests for the given service.
+// Ex: NewJSONRPCClient(sess, "dynamodb", "2012-08-10", "1.0", "DynamoDB_20120810")
+func NewJSONRPCClient(p client.ConfigProvider, serviceName, apiVersion, jsonVersion, targetPrefix string) *JSONRPCClient {
+	c := p.ClientConfig(serviceName)
+	svc := &JSONRPCClient{
+		Client: client.New(
+			*c.Config,
+			metadata.ClientInfo{
+				ServiceName:   serviceName,
+				SigningName:   c.SigningName,
+				SigningRegion: c.SigningRegion,
+				Endpoint:      c.Endpoint,
+				APIVersion:    apiVersion,
+				JSONVersion:   jsonVersion,
+				TargetPrefix:  targetPrefix,
+			},
+			c.Handlers,
+		),
+	}
+	svc.Handlers.Sign.PushBackNamed(v4.SignRequestHandler)
+	svc.Handlers.Build.PushBackNamed(request.NamedHandler{Name: "awless.jsonrpc.Build", Fn: buildJSONRPC})
+	svc.Handlers.Unmarshal.PushBackNamed(request.NamedHandler{Name: "awless.jsonrpc.Unmarshal", Fn: unmarshalJSONRPC})
+	svc.Handlers.UnmarshalMeta.PushBackNamed(request.NamedHandler{Name: "awless.jsonrpc.UnmarshalMeta", Fn: unmarshalMetaJSONRPC})
+	svc.Handlers.UnmarshalError.PushBackNamed(request.NamedHandler{Name: "awless.jsonrpc.UnmarshalError", Fn: unmarshalErrorJSONRPC})
+	return svc
+}
+
+// Call sends the operation with the input marshalled in JSON and decodes the response in output
+func (c *JSONRPCClient) Call(operation string, input, output interface{}) error {
+	op := &request.Operation{Name: operation, HTTPMethod: "POST", HTTPPath: "/"}
+	return c.NewRequest(op, input, output).Send()
+}
+
+func buildJSONRPC(r *request.Request) {
+	body, err := json.Marshal(r.Params)
+	if err != nil {
+		r.Error = awserr.New("SerializationError", "failed encoding JSON RPC request", err)
+		return
+	}
+	r.SetBufferBody(body)
+	r.HTTPRequest.Header.Set("X-Amz-Target", r.ClientInfo.TargetPrefix+"."+r.Operation.Name)
+	r.HTTPRequest.Header.Set("Content-Type", "application/x-amz-json-"+r.ClientInfo.JSONVersion)
+}
+
+func unmarshalJSONRPC(r *request.Request) {
+	defer r.HTTPResponse.Body.Close()
+	if r.DataFilled() {
+		if err := json.NewDecoder(r.HTTPResponse.Body).Decode(r.Data); err != nil {
+			r.Error = awserr.New("SerializationError", "failed decoding JSON RPC response", err)
+		}
+	}
+}
+
+func unmarshalMetaJSONRPC(r *request.Request) {
+	r.RequestID = r.HTTPResponse.Header.Get("X-Amzn-Requestid")
+}
+
+func unmarshalErrorJSONRPC(r *request.Request) {
+	defer r.HTTPResponse.Body.Close()
+	body, err := ioutil.ReadAll(r.HTTPResponse.Body)
+	if err != nil {
+		r.Error = awserr.New("SerializationError", "failed reading JSON RPC error response", err)
+		return
+	}
+	var jsonErr struct {
+		Type    string `json:"__type"`
+		Message string `json:"message"`
+	}
+	json.Unmarshal(body, &jsonErr)
+	// ex: com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException
+	code := jsonErr.Type
+	if i := strings.LastIndex(code, "#"); i >= 0 {
+		code = code[i+1:]
+	}
+	if code == "" {
+		code = "UnknownError"
+	}
+	r.Error = awserr.NewRequestFailure(awserr.New(code, jsonErr.Message, nil), r.HTTPResponse.StatusCode, r.RequestID)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import "github.com/aws/aws-sdk-go/aws/session"
+
+// KMS decrypts data encrypted with AWS KMS keys, ex: params files of templates.
+// Implemented on top of the JSON RPC protocol (see jsonrpc.go)
+
+const (
+	kmsServiceName = "kms"
+	kmsTarget      = "TrentService"
+)
+
+type KMS struct {
+	api *JSONRPCClient
+}
+
+func NewKMS(sess *session.Session) *KMS {
+	return &KMS{api: NewJSONRPCClient(sess, kmsServiceName, "2014-11-01", "1.1", kmsTarget)}
+}
+
+// Decrypt returns the plaintext of a KMS ciphertext blob, as output by
+// 'aws kms encrypt'. The key is identified from the blob itself
+func (k *KMS) Decrypt(ciphertext []byte) ([]byte, error) {
+	input := &kmsDecryptInput{CiphertextBlob: ciphertext}
+	output := &kmsDecryptOutput{}
+	if err := k.api.Call("Decrypt", input, output); err != nil {
+		return nil, err
+	}
+	return output.Plaintext, nil
+}
+
+// blobs are base64 encoded in JSON, as done by encoding/json for []byte
+type kmsDecryptInput struct {
+	CiphertextBlob []byte
+}
+
+type kmsDecryptOutput struct {
+	KeyId     string
+	Plaintext []byte
+}
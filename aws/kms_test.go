@@ -0,0 +1,49 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestKMSDecrypt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("X-Amz-Target"), "TrentService.Decrypt"; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		var input kmsDecryptInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := string(input.CiphertextBlob), "ciphered"; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		json.NewEncoder(w).Encode(&kmsDecryptOutput{KeyId: "arn:aws:kms:eu-west-1:123456789012:key/1234", Plaintext: []byte("db.password: secret\n")})
+	}))
+	defer server.Close()
+
+	plain, err := NewKMS(testSession(server.URL)).Decrypt([]byte("ciphered"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(plain), "db.password: secret\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/wallix/awless/graph"
+)
+
+// Properties needing an API call per resource (ex: bucket ACLs) make syncing large accounts slow.
+// They are not synced but loaded lazily when resources are first shown, listed or inspected
+var lazyProperties = map[graph.ResourceType]map[string]lazyFetchFn{
+	graph.Bucket: {
+		"Grants": func(res *graph.Resource) (interface{}, error) {
+			return fetchAndExtractGrantsFn(&s3.Bucket{Name: awssdk.String(res.Id())})
+		},
+		"Policy": fetchBucketPolicy,
+	},
+}
+
+type lazyFetchFn func(res *graph.Resource) (interface{}, error)
+
+// LazyResourceTypes returns the types of resources having lazily loaded properties
+func LazyResourceTypes() []graph.ResourceType {
+	var types []graph.ResourceType
+	for t := range lazyProperties {
+		types = append(types, t)
+	}
+	return types
+}
+
+// LazyProperties returns the sorted names of the lazily loaded properties of a resource type
+func LazyProperties(t graph.ResourceType) []string {
+	var names []string
+	for name := range lazyProperties[t] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadLazyProperties fetches the lazy properties missing from the given resources,
+// returning the resources updated
+func LoadLazyProperties(resources ...*graph.Resource) ([]*graph.Resource, error) {
+	var (
+		mu      sync.Mutex
+		updated []*graph.Resource
+		wg      sync.WaitGroup
+	)
+	sem := make(chan struct{}, maxConcurrentRequests)
+	errc := make(chan error, len(resources))
+
+	for _, res := range resources {
+		fetchers := lazyProperties[res.Type()]
+		var missing []string
+		for name := range fetchers {
+			if _, ok := res.Properties[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(res *graph.Resource, missing []string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			props := make(map[string]interface{})
+			for _, name := range missing {
+				val, err := fetchers[name](res)
+				if err != nil {
+					errc <- fmt.Errorf("loading %s of %s: %s", name, res, err)
+					return
+				}
+				props[name] = val
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for k, v := range props {
+				res.Properties[k] = v
+			}
+			updated = append(updated, res)
+		}(res, missing)
+	}
+	wg.Wait()
+	close(errc)
+
+	return updated, <-errc
+}
+
+func fetchBucketPolicy(res *graph.Resource) (interface{}, error) {
+	out, err := StorageService.(s3iface.S3API).GetBucketPolicy(&s3.GetBucketPolicyInput{Bucket: awssdk.String(res.Id())})
+	if e, ok := err.(awserr.Error); ok && e.Code() == "NoSuchBucketPolicy" {
+		return "", nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return awssdk.StringValue(out.Policy), nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/wallix/awless/graph"
+)
+
+func TestLoadLazyProperties(t *testing.T) {
+	StorageService = &mockS3{
+		bucketsACL: map[string][]*s3.Grant{
+			"public": {{Permission: awssdk.String("READ"), Grantee: &s3.Grantee{Type: awssdk.String("Group"), URI: awssdk.String("http://acs.amazonaws.com/groups/global/AllUsers")}}},
+		},
+		bucketsPolicy: map[string]string{"public": `{"Statement":[]}`},
+	}
+
+	public := graph.InitResource("public", graph.Bucket)
+	private := graph.InitResource("private", graph.Bucket)
+	loaded := graph.InitResource("loaded", graph.Bucket)
+	loaded.Properties["Grants"] = []*graph.Grant{}
+	loaded.Properties["Policy"] = ""
+	instance := graph.InitResource("i-1", graph.Instance)
+
+	updated, err := LoadLazyProperties(public, private, loaded, instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(updated), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	grants := public.Properties["Grants"].([]*graph.Grant)
+	if got, want := grants[0].GranteeID, "http://acs.amazonaws.com/groups/global/AllUsers"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := public.Properties["Policy"], `{"Statement":[]}`; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := private.Properties["Policy"], ""; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := LazyProperties(graph.Bucket), []string{"Grants", "Policy"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/elbv2"
@@ -145,11 +146,19 @@ type mockS3 struct {
 	bucketsACL       map[string][]*s3.Grant
 	bucketsPerRegion map[string][]*s3.Bucket
 	objectsPerBucket map[string][]*s3.Object
+	bucketsPolicy    map[string]string
 }
 
 func (m *mockS3) GetBucketAcl(input *s3.GetBucketAclInput) (*s3.GetBucketAclOutput, error) {
 	return &s3.GetBucketAclOutput{Grants: m.bucketsACL[awssdk.StringValue(input.Bucket)]}, nil
 }
+func (m *mockS3) GetBucketPolicy(input *s3.GetBucketPolicyInput) (*s3.GetBucketPolicyOutput, error) {
+	policy, ok := m.bucketsPolicy[awssdk.StringValue(input.Bucket)]
+	if !ok {
+		return nil, awserr.New("NoSuchBucketPolicy", "The bucket policy does not exist", nil)
+	}
+	return &s3.GetBucketPolicyOutput{Policy: awssdk.String(policy)}, nil
+}
 func (m *mockS3) Name() string {
 	return ""
 }
@@ -181,6 +190,15 @@ func (m *mockS3) ListBuckets(input *s3.ListBucketsInput) (*s3.ListBucketsOutput,
 func (m *mockS3) ListObjects(input *s3.ListObjectsInput) (*s3.ListObjectsOutput, error) {
 	return &s3.ListObjectsOutput{Contents: m.objectsPerBucket[awssdk.StringValue(input.Bucket)]}, nil
 }
+func (m *mockS3) ListObjectsPages(input *s3.ListObjectsInput, fn func(p *s3.ListObjectsOutput, lastPage bool) (shouldContinue bool)) error {
+	objects := m.objectsPerBucket[awssdk.StringValue(input.Bucket)]
+	for i, obj := range objects {
+		if !fn(&s3.ListObjectsOutput{Contents: []*s3.Object{obj}}, i == len(objects)-1) {
+			break
+		}
+	}
+	return nil
+}
 func (m *mockS3) GetBucketLocation(input *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error) {
 	for region, buckets := range m.bucketsPerRegion {
 		for _, bucket := range buckets {
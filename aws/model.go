@@ -34,6 +34,10 @@ var awsResourcesDef = map[graph.ResourceType]map[string]*propertyTransform{
 		"State":          {name: "State", transform: extractFieldFn("Name")},
 		"KeyName":        {name: "KeyName", transform: extractValueFn},
 		"SecurityGroups": {name: "SecurityGroups", transform: extractSliceValues("GroupId")},
+		"Profile":        {name: "IamInstanceProfile", transform: extractFieldFn("Arn")},
+		"Lifecycle":      {name: "InstanceLifecycle", transform: extractValueFn},
+		"Zone":           {name: "Placement", transform: extractFieldFn("AvailabilityZone")},
+		"Tags":           {name: "Tags", transform: extractTagsFn},
 	},
 	graph.Vpc: {
 		"Id":        {name: "VpcId", transform: extractValueFn},
@@ -75,6 +79,7 @@ var awsResourcesDef = map[graph.ResourceType]map[string]*propertyTransform{
 		"Encrypted":        {name: "Encrypted", transform: extractValueFn},
 		"CreateTime":       {name: "CreateTime", transform: extractTimeFn},
 		"AvailabilityZone": {name: "AvailabilityZone", transform: extractValueFn},
+		"Tags":             {name: "Tags", transform: extractTagsFn},
 	},
 	graph.InternetGateway: {
 		"Id":   {name: "InternetGatewayId", transform: extractValueFn},
@@ -109,6 +114,7 @@ var awsResourcesDef = map[graph.ResourceType]map[string]*propertyTransform{
 		"State":                 {name: "State", transform: extractFieldFn("Code")},
 		"Type":                  {name: "Type", transform: extractValueFn},
 		"VpcId":                 {name: "VpcId", transform: extractValueFn},
+		"SecurityGroups":        {name: "SecurityGroups", transform: extractStringSliceValues},
 	},
 	graph.TargetGroup: {
 		"Id":   {name: "TargetGroupArn", transform: extractValueFn},
@@ -166,7 +172,6 @@ var awsResourcesDef = map[graph.ResourceType]map[string]*propertyTransform{
 		"Id":         {name: "Name", transform: extractValueFn},
 		"Name":       {name: "Name", transform: extractValueFn},
 		"CreateDate": {name: "CreationDate", transform: extractTimeFn},
-		"Grants":     {fetch: fetchAndExtractGrantsFn},
 	},
 	graph.Object: {
 		"Id":           {name: "Key", transform: extractValueFn},
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"sync"
 
 	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/iam"
@@ -69,13 +70,100 @@ var addParentsFns = map[string][]addParentFn{
 	graph.Vpc.String():              {addRegionParent},
 	graph.AvailabilityZone.String(): {addRegionParent},
 	graph.Keypair.String():          {addRegionParent},
-	graph.User.String():             {addRegionParent, userAddGroupsRelations, addManagedPoliciesRelations},
-	graph.Role.String():             {addRegionParent, addManagedPoliciesRelations},
-	graph.Group.String():            {addRegionParent, addManagedPoliciesRelations},
+	graph.User.String():             {addRegionParent, userAddGroupsRelations},
+	graph.Role.String():             {addRegionParent},
+	graph.Group.String():            {addRegionParent},
 	graph.Policy.String():           {addRegionParent},
 	graph.Bucket.String():           {addRegionParent},
 }
 
+// addDeferredParentsFns add relations resolving other resources by name,
+// which can only be added once all resources are fetched
+var addDeferredParentsFns = map[string][]addParentFn{
+	graph.User.String():  {addManagedPoliciesRelations},
+	graph.Role.String():  {addManagedPoliciesRelations},
+	graph.Group.String(): {addManagedPoliciesRelations},
+}
+
+// relatedTypes lists per resource type the types of the resources that its
+// relations functions above link to. Keep both in sync
+var relatedTypes = map[string][]graph.ResourceType{
+	graph.Subnet.String():           {graph.Vpc},
+	graph.Instance.String():         {graph.Subnet, graph.SecurityGroup, graph.Keypair},
+	graph.SecurityGroup.String():    {graph.Vpc},
+	graph.InternetGateway.String():  {graph.Region, graph.Vpc},
+	graph.RouteTable.String():       {graph.Subnet, graph.Vpc},
+	graph.Volume.String():           {graph.AvailabilityZone, graph.Instance},
+	graph.Vpc.String():              {graph.Region},
+	graph.AvailabilityZone.String(): {graph.Region},
+	graph.Keypair.String():          {graph.Region},
+	graph.User.String():             {graph.Region, graph.Group, graph.Policy},
+	graph.Role.String():             {graph.Region, graph.Policy},
+	graph.Group.String():            {graph.Region, graph.Policy},
+	graph.Policy.String():           {graph.Region},
+	graph.Bucket.String():           {graph.Region},
+}
+
+// addFetchedResource adds a resource to the graph as soon as its page is fetched,
+// passing its API struct to each (if any) instead of keeping all of them in memory
+func addFetchedResource(g *graph.Graph, output interface{}, each func(interface{}) error) error {
+	res, err := newResource(output)
+	if err != nil {
+		return err
+	}
+	if err = g.AddResource(res); err != nil {
+		return err
+	}
+	if each != nil {
+		return each(output)
+	}
+	return nil
+}
+
+// streamRelations returns the function adding the relations of fetched resources of a type. Parents are
+// referenced by id, whether already fetched or not. Only the resources having deferred relations are kept
+func streamRelations(g *graph.Graph, resType string, deferred *deferredRelations) func(interface{}) error {
+	return func(output interface{}) error {
+		for _, fn := range addParentsFns[resType] {
+			if err := fn(g, output); err != nil {
+				return err
+			}
+		}
+		if fns, ok := addDeferredParentsFns[resType]; ok {
+			deferred.add(fns, output)
+		}
+		return nil
+	}
+}
+
+type deferredRelation struct {
+	fns    []addParentFn
+	output interface{}
+}
+
+type deferredRelations struct {
+	mu        sync.Mutex
+	relations []deferredRelation
+}
+
+func (d *deferredRelations) add(fns []addParentFn, output interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.relations = append(d.relations, deferredRelation{fns: fns, output: output})
+}
+
+func (d *deferredRelations) apply(g *graph.Graph) error {
+	for _, r := range d.relations {
+		for _, fn := range r.fns {
+			if err := fn(g, r.output); err != nil {
+				return err
+			}
+		}
+	}
+	d.relations = nil
+	return nil
+}
+
 func (fb funcBuilder) build() addParentFn {
 	if fb.listName != "" {
 		return fb.addRelationListWithField()
@@ -105,11 +193,7 @@ func (fb funcBuilder) addRelationWithField() addParentFn {
 			return nil
 		}
 
-		parent, err := g.GetResource(fb.parent, awssdk.StringValue(str))
-		if err != nil {
-			return err
-		}
-
+		parent := graph.InitResource(awssdk.StringValue(str), fb.parent)
 		return addRelation(g, parent, res, fb.relation)
 	}
 }
@@ -151,11 +235,7 @@ func (fb funcBuilder) addRelationListWithField() addParentFn {
 			if awssdk.StringValue(str) == "" {
 				continue
 			}
-			parent, err := g.GetResource(fb.parent, awssdk.StringValue(str))
-			if err != nil {
-				return err
-			}
-
+			parent := graph.InitResource(awssdk.StringValue(str), fb.parent)
 			if err = addRelation(g, parent, res, fb.relation); err != nil {
 				return err
 			}
@@ -257,17 +337,9 @@ func userAddGroupsRelations(g *graph.Graph, i interface{}) error {
 	if !ok {
 		return fmt.Errorf("aws fetch: not a user, but a %T", i)
 	}
-	n, err := g.GetResource(graph.User, awssdk.StringValue(user.UserId))
-	if err != nil {
-		return err
-	}
-
+	n := graph.InitResource(awssdk.StringValue(user.UserId), graph.User)
 	for _, group := range user.GroupList {
-		parent, err := g.GetResource(graph.Group, awssdk.StringValue(group))
-		if err != nil {
-			return err
-		}
-		g.AddAppliesOnRelation(parent, n)
+		g.AddAppliesOnRelation(graph.InitResource(awssdk.StringValue(group), graph.Group), n)
 	}
 	return nil
 }
@@ -0,0 +1,84 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/wallix/awless/graph"
+)
+
+func TestStreamRelations(t *testing.T) {
+	g := graph.NewGraph()
+	g.AddResource(graph.InitResource("eu-west-1", graph.Region))
+	deferred := &deferredRelations{}
+
+	// the instance is fetched before its subnet and the role before its policy
+	instance := &ec2.Instance{InstanceId: awssdk.String("inst_1"), SubnetId: awssdk.String("sub_1")}
+	if err := addFetchedResource(g, instance, streamRelations(g, "instance", deferred)); err != nil {
+		t.Fatal(err)
+	}
+	role := &iam.RoleDetail{RoleId: awssdk.String("role_1"), RoleName: awssdk.String("admin"),
+		AttachedManagedPolicies: []*iam.AttachedPolicy{{PolicyName: awssdk.String("AdministratorAccess")}},
+	}
+	if err := addFetchedResource(g, role, streamRelations(g, "role", deferred)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(deferred.relations), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	subnet := &ec2.Subnet{SubnetId: awssdk.String("sub_1"), VpcId: awssdk.String("vpc_1")}
+	if err := addFetchedResource(g, subnet, streamRelations(g, "subnet", deferred)); err != nil {
+		t.Fatal(err)
+	}
+	policy := &iam.Policy{PolicyId: awssdk.String("pol_1"), PolicyName: awssdk.String("AdministratorAccess")}
+	if err := addFetchedResource(g, policy, streamRelations(g, "policy", deferred)); err != nil {
+		t.Fatal(err)
+	}
+	if err := deferred.apply(g); err != nil {
+		t.Fatal(err)
+	}
+
+	inst, _ := g.GetResource(graph.Instance, "inst_1")
+	var parents []*graph.Resource
+	if err := g.Accept(&graph.ParentsVisitor{From: inst, Each: graph.VisitorCollectFunc(&parents)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(parents), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := parents[0].Id(), "sub_1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := parents[1].Id(), "vpc_1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	r, _ := g.GetResource(graph.Role, "role_1")
+	appliedOn, err := g.ListResourcesAppliedOn(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pol, _ := g.GetResource(graph.Policy, "pol_1")
+	policyApplied, _ := g.ListResourcesAppliedOn(pol)
+	if got, want := len(appliedOn)+len(policyApplied), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
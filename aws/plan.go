@@ -0,0 +1,126 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wallix/awless/graph"
+)
+
+// updatedProperties maps the params of update statements to the properties they set
+var updatedProperties = map[string]map[string]string{
+	"instance": {"type": "Type", "group": "SecurityGroups"},
+	"subnet":   {"public": "MapPublicIpOnLaunch"},
+}
+
+// PlannedChanges returns the existing resource of the graph a statement acts on, with the
+// changes of properties it would make: the values set by update and tag statements.
+// Delete statements return the resource without changes. The resource is nil when
+// the statement does not act on a resource of the graph
+func PlannedChanges(g *graph.Graph, action, entity string, params map[string]interface{}) (*graph.Resource, []graph.PropertyChange, error) {
+	var id interface{}
+	switch {
+	case action == "create" && entity == "tag":
+		id = params["resource"]
+	case action == "update", action == "delete":
+		id = params["id"]
+	default:
+		return nil, nil, nil
+	}
+	if id == nil {
+		return nil, nil, nil
+	}
+	res, err := g.FindResource(fmt.Sprint(id))
+	if err != nil || res == nil {
+		return nil, nil, err
+	}
+	if entity != "tag" && res.Type().String() != entity {
+		return nil, nil, nil
+	}
+
+	updated := make(graph.Properties)
+	for k, v := range res.Properties {
+		updated[k] = v
+	}
+	switch action {
+	case "delete":
+		return res, nil, nil
+	case "create":
+		set := make(map[string]string)
+		if key, ok := params["key"]; ok {
+			set[fmt.Sprint(key)] = fmt.Sprint(params["value"])
+		}
+		if tags, ok := params["tags"].(map[string]interface{}); ok {
+			for k, v := range tags {
+				set[k] = fmt.Sprint(v)
+			}
+		}
+		var tags []interface{}
+		for _, t := range stringValues(res.Properties["Tags"]) {
+			if _, ok := set[strings.SplitN(t, "=", 2)[0]]; !ok {
+				tags = append(tags, t)
+			}
+		}
+		for k, v := range set {
+			tags = append(tags, k+"="+v)
+		}
+		updated["Tags"] = tags
+		if name, ok := set["Name"]; ok {
+			updated["Name"] = name
+		}
+	case "update":
+		for param, prop := range updatedProperties[entity] {
+			v, ok := params[param]
+			if !ok {
+				continue
+			}
+			switch prop {
+			case "SecurityGroups":
+				updated[prop] = []interface{}{fmt.Sprint(v)}
+			case "MapPublicIpOnLaunch":
+				updated[prop] = fmt.Sprint(v) == "true"
+			default:
+				updated[prop] = fmt.Sprint(v)
+			}
+		}
+	}
+
+	changes := graph.PropertiesDiff(res.Properties, updated)
+	if action == "update" && entity == "securitygroup" {
+		changes = append(changes, ruleChanges(params)...)
+	}
+	return res, changes, nil
+}
+
+// ruleChanges returns the rule authorized or revoked by an update securitygroup statement
+func ruleChanges(params map[string]interface{}) (changes []graph.PropertyChange) {
+	rule := fmt.Sprintf("%v %v", params["protocol"], params["cidr"])
+	if ports, ok := params["portrange"]; ok {
+		rule += fmt.Sprintf(" ports %v", ports)
+	}
+	for param, prop := range map[string]string{"inbound": "InboundRules", "outbound": "OutboundRules"} {
+		switch fmt.Sprint(params[param]) {
+		case "authorize":
+			changes = append(changes, graph.PropertyChange{Name: prop, New: rule})
+		case "revoke":
+			changes = append(changes, graph.PropertyChange{Name: prop, Old: rule})
+		}
+	}
+	return
+}
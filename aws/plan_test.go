@@ -0,0 +1,62 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wallix/awless/graph"
+)
+
+func TestPlannedChanges(t *testing.T) {
+	g := graph.NewGraph()
+	inst := graph.InitResource("inst_1", graph.Instance)
+	inst.Properties["Id"] = "inst_1"
+	inst.Properties["Name"] = "web"
+	inst.Properties["Type"] = "t2.micro"
+	inst.Properties["Tags"] = []interface{}{"Name=web", "Env=dev"}
+	g.AddResource(inst)
+
+	tcases := []struct {
+		action, entity string
+		params         map[string]interface{}
+		expectRes      bool
+		expect         []graph.PropertyChange
+	}{
+		{"update", "instance", map[string]interface{}{"id": "inst_1", "type": "t2.large"}, true, []graph.PropertyChange{{Name: "Type", Old: "t2.micro", New: "t2.large"}}},
+		{"create", "tag", map[string]interface{}{"resource": "inst_1", "key": "Env", "value": "prod"}, true, []graph.PropertyChange{{Name: "Tags.Env", Old: "dev", New: "prod"}}},
+		{"create", "tag", map[string]interface{}{"resource": "inst_1", "key": "Team", "value": "ops"}, true, []graph.PropertyChange{{Name: "Tags.Team", New: "ops"}}},
+		{"create", "tag", map[string]interface{}{"resource": "inst_1", "tags": map[string]interface{}{"Name": "front", "Env": "dev"}}, true, []graph.PropertyChange{{Name: "Name", Old: "web", New: "front"}, {Name: "Tags.Name", Old: "web", New: "front"}}},
+		{"update", "securitygroup", map[string]interface{}{"id": "inst_1", "inbound": "authorize"}, false, nil},
+		{"delete", "instance", map[string]interface{}{"id": "inst_1"}, true, nil},
+		{"delete", "instance", map[string]interface{}{"id": "unknown"}, false, nil},
+		{"create", "instance", map[string]interface{}{"name": "web"}, false, nil},
+	}
+	for i, tcase := range tcases {
+		res, changes, err := PlannedChanges(g, tcase.action, tcase.entity, tcase.params)
+		if err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+		if got, want := res != nil, tcase.expectRes; got != want {
+			t.Fatalf("%d: got %t, want %t", i, got, want)
+		}
+		if got, want := changes, tcase.expect; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %#v, want %#v", i, got, want)
+		}
+	}
+}
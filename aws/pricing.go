@@ -0,0 +1,257 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/wallix/awless/cloud"
+)
+
+// The Price List Query API is only served from us-east-1 (and ap-south-1)
+const (
+	priceListEndpoint = "https://api.pricing.us-east-1.amazonaws.com/"
+	priceListRegion   = "us-east-1"
+)
+
+var errNoPrice = errors.New("no price found")
+
+// Pricing is a cloud.PriceSource querying the AWS Price List API for on-demand
+// prices, the EC2 spot price history and the reserved instances of the account
+type Pricing struct {
+	ec2iface.EC2API
+	region string
+	creds  *credentials.Credentials
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]float64
+}
+
+func NewPricing(sess *session.Session) *Pricing {
+	return &Pricing{
+		EC2API: ec2.New(sess),
+		region: awssdk.StringValue(sess.Config.Region),
+		creds:  sess.Config.Credentials,
+		client: Endpoints.HTTPClient(0),
+		cache:  make(map[string]float64),
+	}
+}
+
+func (p *Pricing) OnDemandHourly(instanceType string) (float64, error) {
+	return p.cachedPrice("instance/"+instanceType, func() (float64, error) {
+		return p.getProductPrice("AmazonEC2", map[string]string{
+			"instanceType":    instanceType,
+			"regionCode":      p.region,
+			"operatingSystem": "Linux",
+			"tenancy":         "Shared",
+			"preInstalledSw":  "NA",
+			"capacitystatus":  "Used",
+		})
+	})
+}
+
+func (p *Pricing) VolumeMonthlyPerGB(volumeType string) (float64, error) {
+	return p.cachedPrice("volume/"+volumeType, func() (float64, error) {
+		return p.getProductPrice("AmazonEC2", map[string]string{
+			"productFamily": "Storage",
+			"volumeApiName": volumeType,
+			"regionCode":    p.region,
+		})
+	})
+}
+
+func (p *Pricing) SpotHourly(instanceType, zone string) (float64, error) {
+	return p.cachedPrice("spot/"+instanceType+"/"+zone, func() (float64, error) {
+		input := &ec2.DescribeSpotPriceHistoryInput{
+			InstanceTypes:       []*string{awssdk.String(instanceType)},
+			ProductDescriptions: []*string{awssdk.String("Linux/UNIX")},
+			StartTime:           awssdk.Time(time.Now()),
+		}
+		if zone != "" {
+			input.AvailabilityZone = awssdk.String(zone)
+		}
+		out, err := p.DescribeSpotPriceHistory(input)
+		if err != nil {
+			return 0, err
+		}
+		if len(out.SpotPriceHistory) == 0 {
+			return 0, errNoPrice
+		}
+		latest := out.SpotPriceHistory[0]
+		for _, h := range out.SpotPriceHistory {
+			if awssdk.TimeValue(h.Timestamp).After(awssdk.TimeValue(latest.Timestamp)) {
+				latest = h
+			}
+		}
+		return strconv.ParseFloat(awssdk.StringValue(latest.SpotPrice), 64)
+	})
+}
+
+func (p *Pricing) Reservations() ([]*cloud.Reservation, error) {
+	out, err := p.DescribeReservedInstances(&ec2.DescribeReservedInstancesInput{
+		Filters: []*ec2.Filter{{Name: awssdk.String("state"), Values: []*string{awssdk.String("active")}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var reservations []*cloud.Reservation
+	for _, ri := range out.ReservedInstances {
+		reservations = append(reservations, &cloud.Reservation{
+			InstanceType: awssdk.StringValue(ri.InstanceType),
+			Zone:         awssdk.StringValue(ri.AvailabilityZone),
+			Count:        int(awssdk.Int64Value(ri.InstanceCount)),
+			Hourly:       reservedHourly(ri),
+		})
+	}
+	return reservations, nil
+}
+
+// reservedHourly spreads the upfront price of a reservation over its duration
+func reservedHourly(ri *ec2.ReservedInstances) float64 {
+	hourly := awssdk.Float64Value(ri.UsagePrice)
+	if hours := float64(awssdk.Int64Value(ri.Duration)) / 3600; hours > 0 {
+		hourly += awssdk.Float64Value(ri.FixedPrice) / hours
+	}
+	for _, charge := range ri.RecurringCharges {
+		if awssdk.StringValue(charge.Frequency) == ec2.RecurringChargeFrequencyHourly {
+			hourly += awssdk.Float64Value(charge.Amount)
+		}
+	}
+	return hourly
+}
+
+func (p *Pricing) cachedPrice(key string, fetch func() (float64, error)) (float64, error) {
+	p.mu.Lock()
+	price, ok := p.cache[key]
+	p.mu.Unlock()
+	if ok {
+		return price, nil
+	}
+	price, err := fetch()
+	if err != nil {
+		return 0, err
+	}
+	p.mu.Lock()
+	p.cache[key] = price
+	p.mu.Unlock()
+	return price, nil
+}
+
+func (p *Pricing) getProductPrice(serviceCode string, filters map[string]string) (float64, error) {
+	type filter struct {
+		Type, Field, Value string
+	}
+	input := struct {
+		ServiceCode   string
+		Filters       []filter
+		FormatVersion string
+		MaxResults    int
+	}{ServiceCode: serviceCode, FormatVersion: "aws_v1", MaxResults: 1}
+
+	var fields []string
+	for field := range filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		input.Filters = append(input.Filters, filter{Type: "TERM_MATCH", Field: field, Value: filters[field]})
+	}
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequest("POST", priceListEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AWSPriceListService.GetProducts")
+	if _, err = v4.NewSigner(p.creds).Sign(req, bytes.NewReader(body), "pricing", priceListRegion, time.Now()); err != nil {
+		return 0, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Type    string `json:"__type"`
+			Message string
+		}
+		json.Unmarshal(content, &apiErr)
+		return 0, fmt.Errorf("price list: %s: %s %s", resp.Status, apiErr.Type, apiErr.Message)
+	}
+
+	var output struct {
+		PriceList []string
+	}
+	if err = json.Unmarshal(content, &output); err != nil {
+		return 0, err
+	}
+	if len(output.PriceList) == 0 {
+		return 0, errNoPrice
+	}
+	return parseOnDemandPrice([]byte(output.PriceList[0]))
+}
+
+// parseOnDemandPrice extracts the on-demand USD unit price of a Price List product
+func parseOnDemandPrice(product []byte) (float64, error) {
+	var item struct {
+		Terms struct {
+			OnDemand map[string]struct {
+				PriceDimensions map[string]struct {
+					PricePerUnit map[string]string
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(product, &item); err != nil {
+		return 0, err
+	}
+	for _, term := range item.Terms.OnDemand {
+		for _, dim := range term.PriceDimensions {
+			if usd, ok := dim.PricePerUnit["USD"]; ok {
+				return strconv.ParseFloat(usd, 64)
+			}
+		}
+	}
+	return 0, errNoPrice
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import "testing"
+
+func TestParseOnDemandPrice(t *testing.T) {
+	product := `{"product":{"sku":"ABC","attributes":{"instanceType":"t2.micro"}},
+"terms":{"OnDemand":{"ABC.JRTCKXETXF":{"priceDimensions":{"ABC.JRTCKXETXF.6YS6EN2CT7":{"unit":"Hrs","pricePerUnit":{"USD":"0.0126000000"}}}}},
+"Reserved":{"ABC.4NA7Y494T4":{"priceDimensions":{"ABC.4NA7Y494T4.6YS6EN2CT7":{"unit":"Hrs","pricePerUnit":{"USD":"0.0090000000"}}}}}}}`
+
+	price, err := parseOnDemandPrice([]byte(product))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := price, 0.0126; got != want {
+		t.Fatalf("got %f, want %f", got, want)
+	}
+
+	if _, err = parseOnDemandPrice([]byte(`{"terms":{}}`)); err == nil {
+		t.Fatal("expected error got none")
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/endpointcreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/go-ini/ini"
+)
+
+// RoleOverride, when set, is assumed on top of the profile credentials of every session
+var RoleOverride *AssumeRole
+
+const (
+	// AccountRoleKey is the config key of the role assumed in the account given with --account
+	AccountRoleKey = "aws.account.role"
+
+	// DefaultAccountRole is the role created by AWS Organizations in member accounts
+	DefaultAccountRole = "OrganizationAccountAccessRole"
+)
+
+// AccountRoleArn returns the ARN of the role to assume in the given account, defaulting to DefaultAccountRole
+func AccountRoleArn(account, role string) string {
+	if role == "" {
+		role = DefaultAccountRole
+	}
+	return fmt.Sprintf("arn:aws:iam::%s:role/%s", account, strings.TrimPrefix(role, "/"))
+}
+
+// AssumeRole is a role assumed with the credentials of a profile or of a previous role
+type AssumeRole struct {
+	Arn, ExternalID, MFASerial, SessionName string
+	Duration                                time.Duration
+}
+
+// credentials assumes the role with the credentials of the given session. Scope identifies
+// the chain of roles leading to this one, to cache the credentials of each profile separately
+func (r *AssumeRole) credentials(p client.ConfigProvider, profile, scope string) *credentials.Credentials {
+	provider := &stscreds.AssumeRoleProvider{
+		Client:          sts.New(p),
+		RoleARN:         r.Arn,
+		RoleSessionName: r.SessionName,
+		Duration:        stscreds.DefaultDuration,
+		ExternalID:      stringOrNil(r.ExternalID),
+		// renewed before expiry so that requests are not signed with credentials about to expire
+		ExpiryWindow: cacheExpiryWindow,
+	}
+	if r.MFASerial != "" {
+		provider.SerialNumber = stringOrNil(r.MFASerial)
+		provider.TokenProvider = stscreds.StdinTokenProvider
+	}
+	if provider.RoleSessionName == "" {
+		provider.RoleSessionName = fmt.Sprintf("awless-%d", time.Now().UTC().UnixNano())
+	}
+	if r.Duration > 0 {
+		provider.Duration = r.Duration
+	}
+	if CredentialsCacheDir == "" {
+		return credentials.NewCredentials(provider)
+	}
+	return credentials.NewCredentials(newCachedProvider(provider, CredentialsCacheDir, profile, scope, r))
+}
+
+func (r *AssumeRole) scope() string {
+	return fmt.Sprintf("%s|%s|%s\n", r.Arn, r.ExternalID, r.MFASerial)
+}
+
+// sharedProfile holds the credentials settings of a profile from the AWS shared config and credentials files
+type sharedProfile struct {
+	name                                       string
+	accessKeyID, secretAccessKey, sessionToken string
+	credentialProcess                          string
+	roleArn, sourceProfile, credentialSource   string
+	externalID, mfaSerial, roleSessionName     string
+	durationSeconds                            int
+}
+
+func (p *sharedProfile) hasStaticCredentials() bool {
+	return p.accessKeyID != "" && p.secretAccessKey != ""
+}
+
+func (p *sharedProfile) hasCredentials() bool {
+	return p.hasStaticCredentials() || p.credentialProcess != ""
+}
+
+// sourceCredentials returns the credentials from which the roles of the chain ending with this profile are assumed.
+// As for the AWS CLI, static keys prevail over the credential_process
+func (p *sharedProfile) sourceCredentials(sess *session.Session) *credentials.Credentials {
+	if p.roleArn != "" && p.credentialSource != "" {
+		switch p.credentialSource {
+		case "Environment":
+			return credentials.NewEnvCredentials()
+		case "Ec2InstanceMetadata":
+			return ec2rolecreds.NewCredentials(sess)
+		case "EcsContainer":
+			return endpointcreds.NewCredentialsClient(*sess.Config, sess.Handlers, "http://169.254.170.2"+os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"))
+		}
+	}
+	if p.hasStaticCredentials() {
+		return credentials.NewStaticCredentials(p.accessKeyID, p.secretAccessKey, p.sessionToken)
+	}
+	return NewProcessCredentials(p.credentialProcess)
+}
+
+func (p *sharedProfile) assumeRole() *AssumeRole {
+	return &AssumeRole{
+		Arn:         p.roleArn,
+		ExternalID:  p.externalID,
+		MFASerial:   p.mfaSerial,
+		SessionName: p.roleSessionName,
+		Duration:    time.Duration(p.durationSeconds) * time.Second,
+	}
+}
+
+func sharedFilenames() (configFile, credentialsFile string) {
+	home := os.Getenv("HOME")
+	if home == "" {
+		home = os.Getenv("USERPROFILE")
+	}
+	configFile, credentialsFile = os.Getenv("AWS_CONFIG_FILE"), os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if configFile == "" {
+		configFile = filepath.Join(home, ".aws", "config")
+	}
+	if credentialsFile == "" {
+		credentialsFile = filepath.Join(home, ".aws", "credentials")
+	}
+	return
+}
+
+// loadSharedProfiles reads the profiles of the given shared files. As for the SDK,
+// values of the latter files override the former ones and missing files are ignored
+func loadSharedProfiles(files ...string) (map[string]*sharedProfile, error) {
+	profiles := make(map[string]*sharedProfile)
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			continue
+		}
+		data, err := ini.Load(file)
+		if err != nil {
+			return profiles, fmt.Errorf("reading %s: %s", file, err)
+		}
+		for _, section := range data.Sections() {
+			if section.Name() == ini.DEFAULT_SECTION {
+				continue
+			}
+			name := strings.TrimSpace(strings.TrimPrefix(section.Name(), "profile "))
+			p, ok := profiles[name]
+			if !ok {
+				p = &sharedProfile{name: name}
+				profiles[name] = p
+			}
+			setIfPresent := func(dst *string, key string) {
+				if v := section.Key(key).String(); v != "" {
+					*dst = v
+				}
+			}
+			setIfPresent(&p.accessKeyID, "aws_access_key_id")
+			setIfPresent(&p.secretAccessKey, "aws_secret_access_key")
+			setIfPresent(&p.sessionToken, "aws_session_token")
+			setIfPresent(&p.credentialProcess, "credential_process")
+			setIfPresent(&p.roleArn, "role_arn")
+			setIfPresent(&p.sourceProfile, "source_profile")
+			setIfPresent(&p.credentialSource, "credential_source")
+			setIfPresent(&p.externalID, "external_id")
+			setIfPresent(&p.mfaSerial, "mfa_serial")
+			setIfPresent(&p.roleSessionName, "role_session_name")
+			if d := section.Key("duration_seconds").MustInt(0); d > 0 {
+				p.durationSeconds = d
+			}
+		}
+	}
+	return profiles, nil
+}
+
+// resolveRoleChain follows the source_profile of a profile assuming a role up to the profile with
+// credentials (static keys, credential_process or credential_source). It returns that profile and the roles to
+// assume from it, in order. A nil source profile means the SDK resolves the credentials of the profile by itself
+func resolveRoleChain(profiles map[string]*sharedProfile, name string) (*sharedProfile, []*AssumeRole, error) {
+	var roles []*AssumeRole
+	visited := make(map[string]bool)
+	current := name
+	for {
+		p, ok := profiles[current]
+		if !ok {
+			if current == name {
+				return nil, nil, nil
+			}
+			return nil, nil, fmt.Errorf("profile '%s': source profile '%s' not found", name, current)
+		}
+		if p.roleArn == "" {
+			switch {
+			case len(roles) == 0 && (p.hasStaticCredentials() || p.credentialProcess == ""):
+				return nil, nil, nil
+			case !p.hasCredentials():
+				return nil, nil, fmt.Errorf("profile '%s': source profile '%s' has no credentials", name, current)
+			}
+			return p, reverseRoles(roles), nil
+		}
+		if visited[current] {
+			return nil, nil, fmt.Errorf("profile '%s': cycle in source profiles through '%s'", name, current)
+		}
+		visited[current] = true
+		roles = append(roles, p.assumeRole())
+
+		switch {
+		case p.credentialSource != "":
+			switch p.credentialSource {
+			case "Environment", "Ec2InstanceMetadata", "EcsContainer":
+				return p, reverseRoles(roles), nil
+			default:
+				return nil, nil, fmt.Errorf("profile '%s': unsupported credential_source '%s' (expecting Environment, Ec2InstanceMetadata or EcsContainer)", current, p.credentialSource)
+			}
+		case p.sourceProfile == "":
+			return nil, nil, fmt.Errorf("profile '%s': role_arn of profile '%s' without source_profile nor credential_source", name, current)
+		case p.sourceProfile == current:
+			// the role is assumed with the credentials of its own profile
+			if !p.hasCredentials() {
+				return nil, nil, fmt.Errorf("profile '%s': source profile '%s' has no credentials", name, current)
+			}
+			return p, reverseRoles(roles), nil
+		}
+		current = p.sourceProfile
+	}
+}
+
+// profileRoles returns the profile holding the credentials from which the roles of a profile are assumed.
+// A nil profile means the SDK resolves the credentials by itself
+func profileRoles(profile string) (*sharedProfile, []*AssumeRole, error) {
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	profiles, err := loadSharedProfiles(sharedFilenames())
+	if err != nil {
+		return nil, nil, err
+	}
+	return resolveRoleChain(profiles, profile)
+}
+
+func reverseRoles(roles []*AssumeRole) []*AssumeRole {
+	reversed := make([]*AssumeRole, len(roles))
+	for i, r := range roles {
+		reversed[len(roles)-1-i] = r
+	}
+	return reversed
+}
+
+func stringOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
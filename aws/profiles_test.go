@@ -0,0 +1,175 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveRoleChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-profiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	configFile, credsFile := filepath.Join(dir, "config"), filepath.Join(dir, "credentials")
+	configContent := `[default]
+region = eu-west-1
+
+[profile consultant]
+role_arn = arn:aws:iam::111111111111:role/consultant
+source_profile = default
+external_id = acme-42
+duration_seconds = 1800
+
+[profile client-prod]
+role_arn = arn:aws:iam::222222222222:role/admin
+source_profile = consultant
+role_session_name = awless-prod
+
+[profile self]
+role_arn = arn:aws:iam::333333333333:role/self
+source_profile = self
+
+[profile loop-a]
+role_arn = arn:aws:iam::444444444444:role/a
+source_profile = loop-b
+
+[profile loop-b]
+role_arn = arn:aws:iam::444444444444:role/b
+source_profile = loop-a
+
+[profile orphan]
+role_arn = arn:aws:iam::555555555555:role/orphan
+source_profile = missing
+
+[profile broker]
+credential_process = vault-broker aws --account prod
+
+[profile via-broker]
+role_arn = arn:aws:iam::666666666666:role/deploy
+source_profile = broker
+
+[profile ci]
+role_arn = arn:aws:iam::777777777777:role/ci
+credential_source = Environment
+
+[profile unknown-source]
+role_arn = arn:aws:iam::777777777777:role/ci
+credential_source = Keychain
+
+[profile static-first]
+credential_process = never-run
+`
+	credsContent := `[default]
+aws_access_key_id = AKIDDEFAULT
+aws_secret_access_key = secret
+
+[self]
+aws_access_key_id = AKIDSELF
+aws_secret_access_key = secret
+
+[static-first]
+aws_access_key_id = AKIDSTATIC
+aws_secret_access_key = secret
+`
+	if err := ioutil.WriteFile(configFile, []byte(configContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(credsFile, []byte(credsContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := loadSharedProfiles(configFile, credsFile, filepath.Join(dir, "nonexistent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tcases := []struct {
+		profile string
+		source  string
+		roles   []string
+		err     string
+	}{
+		{profile: "default"},
+		{profile: "unknown"},
+		{profile: "consultant", source: "default", roles: []string{"arn:aws:iam::111111111111:role/consultant"}},
+		{profile: "client-prod", source: "default", roles: []string{"arn:aws:iam::111111111111:role/consultant", "arn:aws:iam::222222222222:role/admin"}},
+		{profile: "self", source: "self", roles: []string{"arn:aws:iam::333333333333:role/self"}},
+		{profile: "loop-a", err: "cycle"},
+		{profile: "orphan", err: "'missing' not found"},
+		{profile: "broker", source: "broker"},
+		{profile: "via-broker", source: "broker", roles: []string{"arn:aws:iam::666666666666:role/deploy"}},
+		{profile: "ci", source: "ci", roles: []string{"arn:aws:iam::777777777777:role/ci"}},
+		{profile: "unknown-source", err: "unsupported credential_source 'Keychain'"},
+		{profile: "static-first"},
+	}
+	for _, tcase := range tcases {
+		source, roles, err := resolveRoleChain(profiles, tcase.profile)
+		if tcase.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tcase.err) {
+				t.Fatalf("%s: got %v, want error containing %q", tcase.profile, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %s", tcase.profile, err)
+		}
+		if tcase.source == "" {
+			if source != nil {
+				t.Fatalf("%s: got source %s, want none", tcase.profile, source.name)
+			}
+			continue
+		}
+		if got, want := source.name, tcase.source; got != want {
+			t.Fatalf("%s: got %s, want %s", tcase.profile, got, want)
+		}
+		var arns []string
+		for _, r := range roles {
+			arns = append(arns, r.Arn)
+		}
+		if got, want := strings.Join(arns, ","), strings.Join(tcase.roles, ","); got != want {
+			t.Fatalf("%s: got %s, want %s", tcase.profile, got, want)
+		}
+	}
+
+	_, roles, _ := resolveRoleChain(profiles, "client-prod")
+	if got, want := roles[0].ExternalID, "acme-42"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := roles[0].Duration, 30*time.Minute; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := roles[1].SessionName, "awless-prod"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestAccountRoleArn(t *testing.T) {
+	if got, want := AccountRoleArn("123456789012", ""), "arn:aws:iam::123456789012:role/OrganizationAccountAccessRole"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := AccountRoleArn("123456789012", "ops/admin"), "arn:aws:iam::123456789012:role/ops/admin"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
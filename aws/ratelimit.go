@@ -0,0 +1,119 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/wallix/awless/logger"
+)
+
+// Requests of a service in a region share an adaptive rate limit, as the APIs throttle accounts per service and region.
+// The rate increases additively while requests succeed and is halved on throttling errors, which the SDK retries
+const (
+	initialRequestRate = 20.0
+	minRequestRate     = 1.0
+	maxRequestRate     = 100.0
+	requestRateStep    = 0.5
+)
+
+// maxConcurrentRequests bounds the requests issued in parallel for each resource of a kind (ex: for each bucket)
+const maxConcurrentRequests = 16
+
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]*adaptiveLimiter)
+)
+
+// RateLimitWaitHandler delays requests to respect the rate limit of their service
+var RateLimitWaitHandler = request.NamedHandler{Name: "awless.RateLimitWait", Fn: func(r *request.Request) {
+	limiterFor(r).wait()
+}}
+
+// RateLimitSuccessHandler increases the rate limit of a service after a successful request
+var RateLimitSuccessHandler = request.NamedHandler{Name: "awless.RateLimitSuccess", Fn: func(r *request.Request) {
+	if r.Error == nil {
+		limiterFor(r).succeeded()
+	}
+}}
+
+// RateLimitThrottleHandler backs off the rate limit of a service on throttling errors
+var RateLimitThrottleHandler = request.NamedHandler{Name: "awless.RateLimitThrottle", Fn: func(r *request.Request) {
+	if r.IsErrorThrottle() {
+		rate := limiterFor(r).throttled()
+		logger.ExtraVerbosef("%s/%s: throttled, slowing down to %.1f requests/s", r.ClientInfo.ServiceName, r.Operation.Name, rate)
+	}
+}}
+
+func limiterFor(r *request.Request) *adaptiveLimiter {
+	key := r.ClientInfo.ServiceName + "/" + r.ClientInfo.SigningRegion
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	l, ok := limiters[key]
+	if !ok {
+		l = newAdaptiveLimiter(initialRequestRate)
+		limiters[key] = l
+	}
+	return l
+}
+
+type adaptiveLimiter struct {
+	mu   sync.Mutex
+	rate float64
+	next time.Time
+	now  func() time.Time
+}
+
+func newAdaptiveLimiter(rate float64) *adaptiveLimiter {
+	return &adaptiveLimiter{rate: rate, now: time.Now}
+}
+
+func (l *adaptiveLimiter) wait() {
+	time.Sleep(l.reserve())
+}
+
+// reserve returns the delay before the next request slot, evenly spaced at the current rate
+func (l *adaptiveLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(float64(time.Second) / l.rate))
+	return delay
+}
+
+func (l *adaptiveLimiter) succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate += requestRateStep; l.rate > maxRequestRate {
+		l.rate = maxRequestRate
+	}
+}
+
+func (l *adaptiveLimiter) throttled() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate /= 2; l.rate < minRequestRate {
+		l.rate = minRequestRate
+	}
+	return l.rate
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveLimiter(t *testing.T) {
+	now := time.Now()
+	l := newAdaptiveLimiter(10)
+	l.now = func() time.Time { return now }
+
+	for i, want := range []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond} {
+		if got := l.reserve(); got != want {
+			t.Fatalf("%d: got %s, want %s", i, got, want)
+		}
+	}
+
+	if got, want := l.throttled(), 5.0; got != want {
+		t.Fatalf("got %f, want %f", got, want)
+	}
+	now = now.Add(time.Second)
+	l.reserve()
+	if got, want := l.reserve(), 200*time.Millisecond; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	for i := 0; i < 10; i++ {
+		l.throttled()
+	}
+	if got, want := l.rate, minRequestRate; got != want {
+		t.Fatalf("got %f, want %f", got, want)
+	}
+	for i := 0; i < 1000; i++ {
+		l.succeeded()
+	}
+	if got, want := l.rate, maxRequestRate; got != want {
+		t.Fatalf("got %f, want %f", got, want)
+	}
+}
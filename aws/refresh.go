@@ -0,0 +1,80 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/graph"
+)
+
+type typeFetcher interface {
+	fetchByType(g *graph.Graph, t string, each func(interface{}) error) error
+}
+
+// RefreshResources fetches the resources of the given types of a service and replaces
+// them in its graph g, with their relations. Removed resources are deleted with all their
+// relations, so that a local graph reflects resources created or deleted by a template
+// without syncing the whole service
+func RefreshResources(g *graph.Graph, srv cloud.Service, types ...string) error {
+	fetcher, ok := srv.(typeFetcher)
+	if !ok {
+		return fmt.Errorf("aws %s: unsupported refresh of resources", srv.Name())
+	}
+	regions, err := g.GetAllResources(graph.Region)
+	if err != nil {
+		return err
+	}
+
+	fresh := graph.NewGraph()
+	fresh.AddResource(regions...)
+	deferred := &deferredRelations{}
+	for _, t := range types {
+		if err := fetcher.fetchByType(fresh, t, streamRelations(fresh, t, deferred)); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range types {
+		fetched, err := fresh.GetAllResources(graph.ResourceType(t))
+		if err != nil {
+			return err
+		}
+		ids := make(map[string]bool)
+		for _, res := range fetched {
+			ids[res.Id()] = true
+		}
+		local, err := g.GetAllResources(graph.ResourceType(t))
+		if err != nil {
+			return err
+		}
+		for _, res := range local {
+			if ids[res.Id()] {
+				err = g.ClearResource(res, relatedTypes[t]...)
+			} else {
+				err = g.RemoveResource(res)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	g.AddGraph(fresh)
+	return deferred.apply(g)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/wallix/awless/graph"
+)
+
+func TestRefreshResources(t *testing.T) {
+	mock := &mockEc2{
+		vpcs:    []*ec2.Vpc{{VpcId: awssdk.String("vpc_1")}},
+		subnets: []*ec2.Subnet{{SubnetId: awssdk.String("sub_1"), VpcId: awssdk.String("vpc_1")}, {SubnetId: awssdk.String("sub_2"), VpcId: awssdk.String("vpc_1")}},
+		instances: []*ec2.Instance{
+			{InstanceId: awssdk.String("inst_1"), SubnetId: awssdk.String("sub_1")},
+			{InstanceId: awssdk.String("inst_2"), SubnetId: awssdk.String("sub_1")},
+		},
+	}
+	infra := &Infra{EC2API: mock, ELBV2API: &mockELB{}, region: "eu-west-1"}
+	g, err := infra.FetchResources()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.instances = []*ec2.Instance{
+		{InstanceId: awssdk.String("inst_1"), SubnetId: awssdk.String("sub_2"), Tags: []*ec2.Tag{{Key: awssdk.String("Name"), Value: awssdk.String("moved")}}},
+		{InstanceId: awssdk.String("inst_3"), SubnetId: awssdk.String("sub_2")},
+	}
+	if err = RefreshResources(g, infra, "instance"); err != nil {
+		t.Fatal(err)
+	}
+
+	instances, err := g.GetAllResources(graph.Instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, inst := range instances {
+		ids = append(ids, inst.Id())
+	}
+	if got, want := len(ids), 2; got != want {
+		t.Fatalf("got %d (%v), want %d", got, ids, want)
+	}
+	inst, _ := g.GetResource(graph.Instance, "inst_1")
+	if got, want := inst.Properties["Name"], "moved"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	var parents []*graph.Resource
+	if err = g.Accept(&graph.ParentsVisitor{From: inst, Each: graph.VisitorCollectFunc(&parents)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := parents[0].Id(), "sub_2"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := parents[1].Id(), "vpc_1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	sub1, _ := g.GetResource(graph.Subnet, "sub_1")
+	var children []*graph.Resource
+	if err = g.Accept(&graph.ChildrenVisitor{From: sub1, Each: graph.VisitorCollectFunc(&children)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(children), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	parents = nil
+	if err = g.Accept(&graph.ParentsVisitor{From: sub1, Each: graph.VisitorCollectFunc(&parents)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(parents), 2; got != want {
+		t.Fatalf("got %d, want %d (vpc, region)", got, want)
+	}
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/wallix/awless/logger"
+)
+
+const (
+	// RetryBudgetKey is the config key of the number of retries allowed per service and region.
+	// Retries consume the budget, successful requests refill it
+	RetryBudgetKey = "aws.retry.budget"
+
+	// RetryBudgetKeyPrefix prefixes the config keys of per service retry budgets, by endpoint ID.
+	// Ex: aws.retry.budget.ec2, aws.retry.budget.s3
+	RetryBudgetKeyPrefix = "aws.retry.budget."
+
+	// MaxRetriesKey is the config key of the maximum retries of a request
+	MaxRetriesKey = "aws.retry.max"
+)
+
+// Throttling, server and network errors of a service in a region are retried with an exponential
+// backoff with jitter, as long as the retry budget of the service allows it. After consecutive
+// requests failing on such errors, the circuit of the service opens: requests fail right away
+// until the cooldown elapsed
+const (
+	defaultRetryBudget = 100
+	defaultMaxRetries  = 5
+	retryRefill        = 0.1 // retries earned back per successful request
+	retryBaseDelay     = 50 * time.Millisecond
+	throttleBaseDelay  = 500 * time.Millisecond
+	maxRetryDelay      = 20 * time.Second
+	circuitFailures    = 5
+	circuitCooldown    = 30 * time.Second
+)
+
+// Retries configures the retries of every session
+var Retries = &RetryConfig{Budget: defaultRetryBudget, MaxRetries: defaultMaxRetries}
+
+type RetryConfig struct {
+	Budget     int
+	Services   map[string]int
+	MaxRetries int
+
+	mu       sync.Mutex
+	breakers map[string]*retryBreaker
+}
+
+// ConfigureRetries loads the retry budgets from config
+func ConfigureRetries(defaults map[string]interface{}) error {
+	conf := &RetryConfig{Budget: defaultRetryBudget, MaxRetries: defaultMaxRetries, Services: make(map[string]int)}
+	for k, v := range defaults {
+		value := strings.TrimSpace(fmt.Sprint(v))
+		if value == "" || (k != RetryBudgetKey && k != MaxRetriesKey && !strings.HasPrefix(k, RetryBudgetKeyPrefix)) {
+			continue
+		}
+		n, err := ParseRetryCount(value)
+		if err != nil {
+			return fmt.Errorf("%s: %s", k, err)
+		}
+		switch {
+		case k == RetryBudgetKey:
+			conf.Budget = n
+		case k == MaxRetriesKey:
+			conf.MaxRetries = n
+		default:
+			conf.Services[strings.TrimPrefix(k, RetryBudgetKeyPrefix)] = n
+		}
+	}
+	Retries = conf
+	return nil
+}
+
+// ParseRetryCount parses a retry budget or a maximum of retries
+func ParseRetryCount(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid '%s', expecting a positive number", s)
+	}
+	return n, nil
+}
+
+// Retryer returns the retryer of sessions, sharing the retry budgets of services
+func (c *RetryConfig) Retryer() request.Retryer {
+	return &budgetRetryer{conf: c}
+}
+
+func (c *RetryConfig) breakerFor(r *request.Request) *retryBreaker {
+	service, region := r.ClientInfo.ServiceName, r.ClientInfo.SigningRegion
+	key := service + "/" + region
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.breakers == nil {
+		c.breakers = make(map[string]*retryBreaker)
+	}
+	b, ok := c.breakers[key]
+	if !ok {
+		budget := c.Budget
+		if n, ok := c.Services[service]; ok {
+			budget = n
+		}
+		b = newRetryBreaker(fmt.Sprintf("%s in %s", service, region), budget)
+		c.breakers[key] = b
+	}
+	return b
+}
+
+// RetryBudgetHandler consumes the retry budget of the service of a request about to be retried,
+// failing the request once the budget is exhausted
+func (c *RetryConfig) RetryBudgetHandler() request.NamedHandler {
+	return request.NamedHandler{Name: "awless.RetryBudget", Fn: func(r *request.Request) {
+		if r.Error == nil {
+			return
+		}
+		if r.Retryable == nil {
+			r.Retryable = awssdk.Bool(r.ShouldRetry(r))
+		}
+		if !*r.Retryable || r.RetryCount >= r.MaxRetries() {
+			return
+		}
+		if b := c.breakerFor(r); !b.consume() {
+			r.Retryable = awssdk.Bool(false)
+			r.Error = withMessage(r.Error, fmt.Sprintf("retry budget of %s exhausted (%d retries)", b.name, b.budget))
+		}
+	}}
+}
+
+// CircuitBreakerHandler fails the requests of a service while its circuit is open.
+// It validates requests, as other handlers run even after an error until Validate ones
+func (c *RetryConfig) CircuitBreakerHandler() request.NamedHandler {
+	return request.NamedHandler{Name: "awless.CircuitBreaker", Fn: func(r *request.Request) {
+		if err := c.breakerFor(r).allow(); err != nil {
+			r.Error = err
+			r.Retryable = awssdk.Bool(false)
+		}
+	}}
+}
+
+// RetrySuccessHandler refills the retry budget of the service of a successful request and closes its circuit
+func (c *RetryConfig) RetrySuccessHandler() request.NamedHandler {
+	return request.NamedHandler{Name: "awless.RetrySuccess", Fn: func(r *request.Request) {
+		if r.Error == nil {
+			c.breakerFor(r).succeeded()
+		}
+	}}
+}
+
+// RetryFailureHandler counts the requests failed on transient errors after their retries,
+// opening the circuit of their service after consecutive failures
+func (c *RetryConfig) RetryFailureHandler() request.NamedHandler {
+	return request.NamedHandler{Name: "awless.RetryFailure", Fn: func(r *request.Request) {
+		if r.Error == nil || !isTransientError(r) {
+			return
+		}
+		if b := c.breakerFor(r); b.failed(r.Error) {
+			logger.Warningf("%s: at least %d consecutive requests failed, failing requests for %s: %s", b.name, circuitFailures, circuitCooldown, r.Error)
+		}
+	}}
+}
+
+type budgetRetryer struct {
+	conf *RetryConfig
+}
+
+func (r *budgetRetryer) MaxRetries() int {
+	return r.conf.MaxRetries
+}
+
+func (r *budgetRetryer) ShouldRetry(req *request.Request) bool {
+	return isTransientError(req)
+}
+
+// RetryRules returns an exponential delay with jitter: between half and the full delay
+func (r *budgetRetryer) RetryRules(req *request.Request) time.Duration {
+	base := retryBaseDelay
+	if isThrottleError(req) {
+		base = throttleBaseDelay
+	}
+	return retryDelay(base, req.RetryCount)
+}
+
+func retryDelay(base time.Duration, retryCount int) time.Duration {
+	delay := maxRetryDelay
+	if retryCount < 16 {
+		if d := base << uint(retryCount); d < maxRetryDelay {
+			delay = d
+		}
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func isThrottleError(r *request.Request) bool {
+	if r.HTTPResponse != nil {
+		switch r.HTTPResponse.StatusCode {
+		case 429, 502, 503, 504:
+			return true
+		}
+	}
+	return r.IsErrorThrottle()
+}
+
+func isTransientError(r *request.Request) bool {
+	if err, ok := r.Error.(awserr.Error); ok && err.Code() == circuitOpenCode {
+		return false
+	}
+	if r.HTTPResponse != nil && r.HTTPResponse.StatusCode >= 500 {
+		return true
+	}
+	return r.IsErrorRetryable() || isThrottleError(r)
+}
+
+const circuitOpenCode = "CircuitOpen"
+
+// retryBreaker holds the retry budget and the circuit of a service in a region
+type retryBreaker struct {
+	name   string
+	budget int
+
+	mu        sync.Mutex
+	tokens    float64
+	exhausted bool
+	failures  int
+	openUntil time.Time
+	lastErr   error
+	now       func() time.Time
+}
+
+func newRetryBreaker(name string, budget int) *retryBreaker {
+	return &retryBreaker{name: name, budget: budget, tokens: float64(budget), now: time.Now}
+}
+
+// consume takes a retry from the budget, if any left
+func (b *retryBreaker) consume() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		if !b.exhausted {
+			b.exhausted = true
+			logger.Warningf("%s: retry budget exhausted (%d retries), failing requests without retry until requests succeed again", b.name, b.budget)
+		}
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *retryBreaker) succeeded() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+	if b.tokens += retryRefill; b.tokens > float64(b.budget) {
+		b.tokens = float64(b.budget)
+	}
+	if b.tokens >= 1 {
+		b.exhausted = false
+	}
+}
+
+// failed counts a request failed on a transient error, and returns whether it opened the circuit.
+// Once the cooldown elapsed, a failure opens the circuit again right away
+func (b *retryBreaker) failed(err error) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastErr = err
+	if b.failures++; b.failures < circuitFailures {
+		return false
+	}
+	now := b.now()
+	opening := !now.Before(b.openUntil)
+	b.openUntil = now.Add(circuitCooldown)
+	return opening
+}
+
+// allow returns an error while the circuit is open
+func (b *retryBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openUntil.IsZero() || !b.now().Before(b.openUntil) {
+		return nil
+	}
+	msg := fmt.Sprintf("%s: failing requests for %s after %d consecutive failures (last: %s)", b.name, b.openUntil.Sub(b.now()).Round(time.Second), b.failures, b.lastErr)
+	return awserr.New(circuitOpenCode, msg, b.lastErr)
+}
+
+// withMessage prefixes the message of an AWS error, keeping its code
+func withMessage(err error, msg string) error {
+	if aerr, ok := err.(awserr.Error); ok {
+		return awserr.New(aerr.Code(), msg+": "+aerr.Message(), aerr.OrigErr())
+	}
+	return fmt.Errorf("%s: %s", msg, err)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func TestRetryBudgetAndCircuitBreaker(t *testing.T) {
+	var requests int
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"ThrottlingException","message":"Rate exceeded"}`))
+			return
+		}
+		w.Write([]byte(`{"Plaintext":"c2VjcmV0"}`))
+	}))
+	defer server.Close()
+
+	conf := &RetryConfig{Budget: 2, MaxRetries: 3}
+	sess := session.Must(session.NewSession(&awssdk.Config{
+		Region:      awssdk.String("eu-west-1"),
+		Endpoint:    awssdk.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+		Retryer:     conf.Retryer(),
+		SleepDelay:  func(time.Duration) {},
+	}))
+	sess.Handlers.Validate.PushBackNamed(conf.CircuitBreakerHandler())
+	sess.Handlers.AfterRetry.PushFrontNamed(conf.RetryBudgetHandler())
+	sess.Handlers.AfterRetry.PushBackNamed(conf.RetryFailureHandler())
+	sess.Handlers.ValidateResponse.PushBackNamed(conf.RetrySuccessHandler())
+	kms := NewKMS(sess)
+
+	_, err := kms.Decrypt([]byte("ciphered"))
+	if err == nil || !strings.Contains(err.Error(), "retry budget of kms in eu-west-1 exhausted (2 retries)") {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got, want := requests, 3; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+
+	for i := 1; i < circuitFailures; i++ {
+		kms.Decrypt([]byte("ciphered"))
+	}
+	if got, want := requests, 3+circuitFailures-1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	_, err = kms.Decrypt([]byte("ciphered"))
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != circuitOpenCode {
+		t.Fatalf("expected circuit open error, got %v", err)
+	}
+	if got, want := requests, 3+circuitFailures-1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+
+	breaker := conf.breakers["kms/eu-west-1"]
+	breaker.now = func() time.Time { return time.Now().Add(circuitCooldown) }
+	failing = false
+	if _, err = kms.Decrypt([]byte("ciphered")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := breaker.failures, 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	for retry, max := range []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond} {
+		if got := retryDelay(retryBaseDelay, retry); got < max/2 || got > max {
+			t.Fatalf("%d: got %s, want between %s and %s", retry, got, max/2, max)
+		}
+	}
+	if got := retryDelay(throttleBaseDelay, 30); got < maxRetryDelay/2 || got > maxRetryDelay {
+		t.Fatalf("got %s, want at most %s", got, maxRetryDelay)
+	}
+}
+
+func TestConfigureRetries(t *testing.T) {
+	err := ConfigureRetries(map[string]interface{}{"aws.retry.budget": 50, "aws.retry.budget.s3": "200", "aws.retry.max": "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { Retries = &RetryConfig{Budget: defaultRetryBudget, MaxRetries: defaultMaxRetries} }()
+	if got, want := Retries.Budget, 50; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := Retries.Services["s3"], 200; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := Retries.MaxRetries, 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if err := ConfigureRetries(map[string]interface{}{"aws.retry.max": "-1"}); err == nil {
+		t.Fatal("expected error got none")
+	}
+}
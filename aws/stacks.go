@@ -0,0 +1,293 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/private/protocol/query"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template/driver"
+)
+
+// The CloudFormation SDK package is not vendored: the few read only calls
+// needed are declared here on top of the SDK query protocol
+
+const cloudformationServiceName = "cloudformation"
+
+// StackResourceTypes maps the CloudFormation types whose physical id is the awless resource id
+// or, for IAM users, groups and roles, its name
+var StackResourceTypes = map[string]graph.ResourceType{
+	"AWS::EC2::Instance":                        graph.Instance,
+	"AWS::EC2::VPC":                             graph.Vpc,
+	"AWS::EC2::Subnet":                          graph.Subnet,
+	"AWS::EC2::SecurityGroup":                   graph.SecurityGroup,
+	"AWS::EC2::KeyPair":                         graph.Keypair,
+	"AWS::EC2::Volume":                          graph.Volume,
+	"AWS::EC2::InternetGateway":                 graph.InternetGateway,
+	"AWS::EC2::RouteTable":                      graph.RouteTable,
+	"AWS::ElasticLoadBalancingV2::LoadBalancer": graph.LoadBalancer,
+	"AWS::ElasticLoadBalancingV2::TargetGroup":  graph.TargetGroup,
+	"AWS::IAM::User":                            graph.User,
+	"AWS::IAM::Group":                           graph.Group,
+	"AWS::IAM::Role":                            graph.Role,
+	"AWS::IAM::ManagedPolicy":                   graph.Policy,
+	"AWS::S3::Bucket":                           graph.Bucket,
+	"AWS::SNS::Topic":                           graph.Topic,
+	"AWS::SQS::Queue":                           graph.Queue,
+}
+
+// The cloudformation service is registered after the generated ones (see gen_api.go) so that
+// resources are first looked up in the graph of their own service
+func init() {
+	ServiceNames = append(ServiceNames, cloudformationServiceName)
+	ResourceTypes = append(ResourceTypes, graph.Stack.String())
+	ServicePerResourceType[graph.Stack.String()] = cloudformationServiceName
+}
+
+type cfnAPI interface {
+	DescribeStacks(*describeStacksInput) (*describeStacksOutput, error)
+	ListStackResources(*listStackResourcesInput) (*listStackResourcesOutput, error)
+}
+
+type CloudFormation struct {
+	region string
+	api    cfnAPI
+}
+
+func NewCloudFormation(sess *session.Session) *CloudFormation {
+	return &CloudFormation{region: awssdk.StringValue(sess.Config.Region), api: newCfnClient(sess)}
+}
+
+func (s *CloudFormation) Name() string {
+	return cloudformationServiceName
+}
+
+func (s *CloudFormation) Drivers() []driver.Driver {
+	return nil
+}
+
+func (s *CloudFormation) ResourceTypes() []string {
+	return []string{graph.Stack.String()}
+}
+
+// FetchResources returns the stacks applying on the resources they manage.
+// Managed resources are added with their ManagedBy and LogicalId properties
+func (s *CloudFormation) FetchResources() (*graph.Graph, error) {
+	g, stacks, err := s.fetch_all_stack_graph()
+	if err != nil {
+		return g, err
+	}
+	for _, stack := range stacks {
+		stackN := graph.InitResource(awssdk.StringValue(stack.StackId), graph.Stack)
+		input := &listStackResourcesInput{StackName: stack.StackId}
+		for {
+			out, err := s.api.ListStackResources(input)
+			if err != nil {
+				return g, fmt.Errorf("stack %s: %s", awssdk.StringValue(stack.StackName), err)
+			}
+			for _, r := range out.StackResourceSummaries {
+				t, ok := StackResourceTypes[awssdk.StringValue(r.ResourceType)]
+				if !ok || awssdk.StringValue(r.PhysicalResourceId) == "" {
+					continue
+				}
+				res := graph.InitResource(awssdk.StringValue(r.PhysicalResourceId), t)
+				res.Properties["ManagedBy"] = awssdk.StringValue(stack.StackName)
+				res.Properties["LogicalId"] = awssdk.StringValue(r.LogicalResourceId)
+				g.AddResource(res)
+				g.AddAppliesOnRelation(stackN, res)
+			}
+			if awssdk.StringValue(out.NextToken) == "" {
+				break
+			}
+			input.NextToken = out.NextToken
+		}
+	}
+	return g, nil
+}
+
+func (s *CloudFormation) FetchByType(t string) (*graph.Graph, error) {
+	switch t {
+	case graph.Stack.String():
+		g, _, err := s.fetch_all_stack_graph()
+		return g, err
+	default:
+		return nil, fmt.Errorf("aws cloudformation: unsupported fetch for type %s", t)
+	}
+}
+
+func (s *CloudFormation) fetch_all_stack_graph() (*graph.Graph, []*cfnStack, error) {
+	g := graph.NewGraph()
+	var stacks []*cfnStack
+	input := &describeStacksInput{}
+	for {
+		out, err := s.api.DescribeStacks(input)
+		if err != nil {
+			return g, stacks, err
+		}
+		for _, stack := range out.Stacks {
+			if awssdk.StringValue(stack.StackStatus) == "DELETE_COMPLETE" {
+				continue
+			}
+			stacks = append(stacks, stack)
+			g.AddResource(stackResource(stack))
+		}
+		if awssdk.StringValue(out.NextToken) == "" {
+			break
+		}
+		input.NextToken = out.NextToken
+	}
+	return g, stacks, nil
+}
+
+// StackManagedResources returns the stack names per id of the resources they manage in the cloudformation graph
+func StackManagedResources(g *graph.Graph) (map[string]string, error) {
+	managed := make(map[string]string)
+	for _, t := range StackResourceTypes {
+		resources, err := g.GetAllResources(t)
+		if err != nil {
+			return managed, err
+		}
+		for _, res := range resources {
+			if stack, ok := res.Properties["ManagedBy"].(string); ok && stack != "" {
+				managed[res.Id()] = stack
+			}
+		}
+	}
+	return managed, nil
+}
+
+func stackResource(stack *cfnStack) *graph.Resource {
+	res := graph.InitResource(awssdk.StringValue(stack.StackId), graph.Stack)
+	res.Properties["Id"] = awssdk.StringValue(stack.StackId)
+	res.Properties["Name"] = awssdk.StringValue(stack.StackName)
+	res.Properties["State"] = awssdk.StringValue(stack.StackStatus)
+	if v := awssdk.StringValue(stack.Description); v != "" {
+		res.Properties["Description"] = v
+	}
+	if stack.CreationTime != nil {
+		res.Properties["CreationTime"] = awssdk.TimeValue(stack.CreationTime)
+	}
+	if stack.LastUpdatedTime != nil {
+		res.Properties["LastUpdatedTime"] = awssdk.TimeValue(stack.LastUpdatedTime)
+	}
+	var tags []string
+	for _, t := range stack.Tags {
+		tags = append(tags, fmt.Sprintf("%s=%s", awssdk.StringValue(t.Key), awssdk.StringValue(t.Value)))
+	}
+	if len(tags) > 0 {
+		res.Properties["Tags"] = tags
+	}
+	return res
+}
+
+type cfnClient struct {
+	*client.Client
+}
+
+func newCfnClient(p client.ConfigProvider) *cfnClient {
+	c := p.ClientConfig(cloudformationServiceName)
+	svc := &cfnClient{
+		Client: client.New(
+			*c.Config,
+			metadata.ClientInfo{
+				ServiceName:   cloudformationServiceName,
+				SigningName:   c.SigningName,
+				SigningRegion: c.SigningRegion,
+				Endpoint:      c.Endpoint,
+				APIVersion:    "2010-05-15",
+			},
+			c.Handlers,
+		),
+	}
+	svc.Handlers.Sign.PushBackNamed(v4.SignRequestHandler)
+	svc.Handlers.Build.PushBackNamed(query.BuildHandler)
+	svc.Handlers.Unmarshal.PushBackNamed(query.UnmarshalHandler)
+	svc.Handlers.UnmarshalMeta.PushBackNamed(query.UnmarshalMetaHandler)
+	svc.Handlers.UnmarshalError.PushBackNamed(query.UnmarshalErrorHandler)
+	return svc
+}
+
+func (c *cfnClient) send(action string, input, output interface{}) error {
+	op := &request.Operation{Name: action, HTTPMethod: "POST", HTTPPath: "/"}
+	return c.NewRequest(op, input, output).Send()
+}
+
+func (c *cfnClient) DescribeStacks(input *describeStacksInput) (*describeStacksOutput, error) {
+	output := &describeStacksOutput{}
+	return output, c.send("DescribeStacks", input, output)
+}
+
+func (c *cfnClient) ListStackResources(input *listStackResourcesInput) (*listStackResourcesOutput, error) {
+	output := &listStackResourcesOutput{}
+	return output, c.send("ListStackResources", input, output)
+}
+
+type describeStacksInput struct {
+	_         struct{} `type:"structure"`
+	NextToken *string  `min:"1" type:"string"`
+}
+
+type describeStacksOutput struct {
+	_         struct{}    `type:"structure"`
+	NextToken *string     `min:"1" type:"string"`
+	Stacks    []*cfnStack `type:"list"`
+}
+
+type cfnStack struct {
+	_               struct{}   `type:"structure"`
+	StackId         *string    `type:"string"`
+	StackName       *string    `type:"string"`
+	StackStatus     *string    `type:"string"`
+	Description     *string    `min:"1" type:"string"`
+	CreationTime    *time.Time `type:"timestamp" timestampFormat:"iso8601"`
+	LastUpdatedTime *time.Time `type:"timestamp" timestampFormat:"iso8601"`
+	Tags            []*cfnTag  `type:"list"`
+}
+
+type cfnTag struct {
+	_     struct{} `type:"structure"`
+	Key   *string  `type:"string"`
+	Value *string  `type:"string"`
+}
+
+type listStackResourcesInput struct {
+	_         struct{} `type:"structure"`
+	NextToken *string  `min:"1" type:"string"`
+	StackName *string  `type:"string" required:"true"`
+}
+
+type listStackResourcesOutput struct {
+	_                      struct{}              `type:"structure"`
+	NextToken              *string               `min:"1" type:"string"`
+	StackResourceSummaries []*cfnResourceSummary `type:"list"`
+}
+
+type cfnResourceSummary struct {
+	_                  struct{} `type:"structure"`
+	LogicalResourceId  *string  `type:"string"`
+	PhysicalResourceId *string  `type:"string"`
+	ResourceType       *string  `type:"string"`
+	ResourceStatus     *string  `type:"string"`
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/wallix/awless/graph"
+)
+
+func TestFetchStacks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.Form.Get("Action") {
+		case "DescribeStacks":
+			if r.Form.Get("NextToken") == "" {
+				w.Write([]byte(describeStacksPage1))
+			} else {
+				w.Write([]byte(describeStacksPage2))
+			}
+		case "ListStackResources":
+			if got, want := r.Form.Get("StackName"), "arn:aws:cloudformation:eu-west-1:123456789012:stack/web/1"; got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+			w.Write([]byte(listStackResources))
+		default:
+			t.Errorf("unexpected action %s", r.Form.Get("Action"))
+		}
+	}))
+	defer server.Close()
+
+	sess := session.Must(session.NewSession(&awssdk.Config{
+		Region:      awssdk.String("eu-west-1"),
+		Endpoint:    awssdk.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+	}))
+	g, err := NewCloudFormation(sess).FetchResources()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stacks, err := g.GetAllResources(graph.Stack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(stacks), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	stack := stacks[0]
+	expected := map[string]interface{}{
+		"Id":           "arn:aws:cloudformation:eu-west-1:123456789012:stack/web/1",
+		"Name":         "web",
+		"State":        "UPDATE_COMPLETE",
+		"CreationTime": time.Date(2017, 3, 1, 10, 0, 0, 0, time.UTC),
+		"Tags":         []interface{}{"env=prod"},
+	}
+	for k, want := range expected {
+		if got := stack.Properties[k]; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %#v, want %#v", k, got, want)
+		}
+	}
+
+	managed, err := g.ListResourcesAppliedOn(stack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, res := range managed {
+		ids = append(ids, res.Type().String()+":"+res.Id())
+	}
+	sort.Strings(ids)
+	if got, want := ids, []string{"instance:i-1234", "role:web-role"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	byId, err := StackManagedResources(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := byId, map[string]string{"i-1234": "web", "web-role": "web"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+const describeStacksPage1 = `<DescribeStacksResponse xmlns="http://cloudformation.amazonaws.com/doc/2010-05-15/">
+  <DescribeStacksResult>
+    <NextToken>page2</NextToken>
+    <Stacks>
+      <member>
+        <StackId>arn:aws:cloudformation:eu-west-1:123456789012:stack/web/1</StackId>
+        <StackName>web</StackName>
+        <StackStatus>UPDATE_COMPLETE</StackStatus>
+        <CreationTime>2017-03-01T10:00:00Z</CreationTime>
+        <Tags>
+          <member><Key>env</Key><Value>prod</Value></member>
+        </Tags>
+      </member>
+    </Stacks>
+  </DescribeStacksResult>
+</DescribeStacksResponse>`
+
+const describeStacksPage2 = `<DescribeStacksResponse xmlns="http://cloudformation.amazonaws.com/doc/2010-05-15/">
+  <DescribeStacksResult>
+    <Stacks>
+      <member>
+        <StackId>arn:aws:cloudformation:eu-west-1:123456789012:stack/old/2</StackId>
+        <StackName>old</StackName>
+        <StackStatus>DELETE_COMPLETE</StackStatus>
+        <CreationTime>2016-03-01T10:00:00Z</CreationTime>
+      </member>
+    </Stacks>
+  </DescribeStacksResult>
+</DescribeStacksResponse>`
+
+const listStackResources = `<ListStackResourcesResponse xmlns="http://cloudformation.amazonaws.com/doc/2010-05-15/">
+  <ListStackResourcesResult>
+    <StackResourceSummaries>
+      <member>
+        <LogicalResourceId>WebServer</LogicalResourceId>
+        <PhysicalResourceId>i-1234</PhysicalResourceId>
+        <ResourceType>AWS::EC2::Instance</ResourceType>
+        <ResourceStatus>CREATE_COMPLETE</ResourceStatus>
+      </member>
+      <member>
+        <LogicalResourceId>WebRole</LogicalResourceId>
+        <PhysicalResourceId>web-role</PhysicalResourceId>
+        <ResourceType>AWS::IAM::Role</ResourceType>
+        <ResourceStatus>CREATE_COMPLETE</ResourceStatus>
+      </member>
+      <member>
+        <LogicalResourceId>WaitHandle</LogicalResourceId>
+        <PhysicalResourceId>https://cloudformation-waitcondition</PhysicalResourceId>
+        <ResourceType>AWS::CloudFormation::WaitConditionHandle</ResourceType>
+        <ResourceStatus>CREATE_COMPLETE</ResourceStatus>
+      </member>
+    </StackResourceSummaries>
+  </ListStackResourcesResult>
+</ListStackResourcesResponse>`
@@ -0,0 +1,80 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// SyncPermissions lists, per service, the IAM actions called to sync its resources
+var SyncPermissions = map[string][]string{
+	"infra": {
+		"ec2:DescribeInstances", "ec2:DescribeSubnets", "ec2:DescribeVpcs", "ec2:DescribeKeyPairs",
+		"ec2:DescribeSecurityGroups", "ec2:DescribeVolumes", "ec2:DescribeInternetGateways",
+		"ec2:DescribeRouteTables", "ec2:DescribeAvailabilityZones",
+		"elasticloadbalancing:DescribeLoadBalancers", "elasticloadbalancing:DescribeTargetGroups",
+	},
+	"access":                  {"iam:GetAccountAuthorizationDetails", "iam:ListPolicies", "iam:ListUsers"},
+	"storage":                 {"s3:ListAllMyBuckets", "s3:GetBucketLocation", "s3:ListBucket"},
+	"notification":            {"sns:ListSubscriptions", "sns:ListTopics"},
+	"queue":                   {"sqs:ListQueues", "sqs:GetQueueAttributes"},
+	cloudformationServiceName: {"cloudformation:DescribeStacks", "cloudformation:ListStackResources"},
+}
+
+// PrincipalArn returns the ARN of the IAM principal whose policies apply to the caller
+// of given ARN (as returned by sts:GetCallerIdentity). Assumed roles resolve to their role,
+// assuming it has no path
+func PrincipalArn(callerArn string) (string, error) {
+	kind, name, _ := ParseCallerArn(callerArn)
+	switch kind {
+	case CallerUser:
+		return callerArn, nil
+	case CallerAssumedRole:
+		splits := strings.SplitN(callerArn, ":", 6)
+		return fmt.Sprintf("arn:%s:iam::%s:role/%s", splits[1], splits[4], name), nil
+	case CallerRoot:
+		return "", errors.New("root account: all actions allowed")
+	default:
+		return "", fmt.Errorf("cannot simulate the policies of %s", callerArn)
+	}
+}
+
+// MissingPermissions simulates the policies of the caller on the given actions and returns those denied
+func (s *Access) MissingPermissions(callerArn string, actions []string) ([]string, error) {
+	principal, err := PrincipalArn(callerArn)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	input := &iam.SimulatePrincipalPolicyInput{PolicySourceArn: awssdk.String(principal), ActionNames: awssdk.StringSlice(actions)}
+	err = s.SimulatePrincipalPolicyPages(input, func(out *iam.SimulatePolicyResponse, last bool) bool {
+		for _, res := range out.EvaluationResults {
+			if awssdk.StringValue(res.EvalDecision) != iam.PolicyEvaluationDecisionTypeAllowed {
+				missing = append(missing, awssdk.StringValue(res.EvalActionName))
+			}
+		}
+		return true
+	})
+	sort.Strings(missing)
+	return missing, err
+}
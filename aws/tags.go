@@ -0,0 +1,225 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/wallix/awless/graph"
+)
+
+const (
+	maxEC2TaggedResources   = 1000
+	maxELBV2TaggedResources = 20
+)
+
+// TaggableResourceTypes lists the resource types that can be tagged through TagResources
+var TaggableResourceTypes = []string{"instance", "vpc", "subnet", "securitygroup", "volume", "internetgateway", "routetable", "loadbalancer", "targetgroup", "bucket"}
+
+func IsTaggable(resType string) bool {
+	for _, t := range TaggableResourceTypes {
+		if t == resType {
+			return true
+		}
+	}
+	return false
+}
+
+// TagResources sets the tags on EC2 resources, in batches
+func (s *Infra) TagResources(ids []string, tags map[string]string) error {
+	var ec2Tags []*ec2.Tag
+	for _, k := range sortedKeys(tags) {
+		ec2Tags = append(ec2Tags, &ec2.Tag{Key: awssdk.String(k), Value: awssdk.String(tags[k])})
+	}
+	for _, batch := range batchesOf(ids, maxEC2TaggedResources) {
+		if _, err := s.CreateTags(&ec2.CreateTagsInput{Resources: awssdk.StringSlice(batch), Tags: ec2Tags}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UntagResources removes the tags with given keys from EC2 resources, in batches
+func (s *Infra) UntagResources(ids []string, keys []string) error {
+	var ec2Tags []*ec2.Tag
+	for _, k := range keys {
+		ec2Tags = append(ec2Tags, &ec2.Tag{Key: awssdk.String(k)})
+	}
+	for _, batch := range batchesOf(ids, maxEC2TaggedResources) {
+		if _, err := s.DeleteTags(&ec2.DeleteTagsInput{Resources: awssdk.StringSlice(batch), Tags: ec2Tags}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TagLoadBalancingResources sets the tags on load balancers or target groups given their ARNs, in batches
+func (s *Infra) TagLoadBalancingResources(arns []string, tags map[string]string) error {
+	var elbTags []*elbv2.Tag
+	for _, k := range sortedKeys(tags) {
+		elbTags = append(elbTags, &elbv2.Tag{Key: awssdk.String(k), Value: awssdk.String(tags[k])})
+	}
+	for _, batch := range batchesOf(arns, maxELBV2TaggedResources) {
+		if _, err := s.AddTags(&elbv2.AddTagsInput{ResourceArns: awssdk.StringSlice(batch), Tags: elbTags}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UntagLoadBalancingResources removes the tags with given keys from load balancers or target groups, in batches
+func (s *Infra) UntagLoadBalancingResources(arns []string, keys []string) error {
+	for _, batch := range batchesOf(arns, maxELBV2TaggedResources) {
+		if _, err := s.RemoveTags(&elbv2.RemoveTagsInput{ResourceArns: awssdk.StringSlice(batch), TagKeys: awssdk.StringSlice(keys)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TagBuckets merges the tags with the existing ones of each bucket
+// (S3 only allows to replace the whole tag set of a bucket)
+func (s *Storage) TagBuckets(names []string, tags map[string]string) error {
+	return s.updateBucketsTags(names, func(existing map[string]string) {
+		for k, v := range tags {
+			existing[k] = v
+		}
+	})
+}
+
+// UntagBuckets removes the tags with given keys from each bucket
+func (s *Storage) UntagBuckets(names []string, keys []string) error {
+	return s.updateBucketsTags(names, func(existing map[string]string) {
+		for _, k := range keys {
+			delete(existing, k)
+		}
+	})
+}
+
+func (s *Storage) updateBucketsTags(names []string, update func(map[string]string)) error {
+	for _, name := range names {
+		existing := make(map[string]string)
+		out, err := s.GetBucketTagging(&s3.GetBucketTaggingInput{Bucket: awssdk.String(name)})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "NoSuchTagSet" {
+				return err
+			}
+		} else {
+			for _, t := range out.TagSet {
+				existing[awssdk.StringValue(t.Key)] = awssdk.StringValue(t.Value)
+			}
+		}
+
+		update(existing)
+
+		if len(existing) == 0 {
+			if _, err = s.DeleteBucketTagging(&s3.DeleteBucketTaggingInput{Bucket: awssdk.String(name)}); err != nil {
+				return err
+			}
+			continue
+		}
+		var tagSet []*s3.Tag
+		for _, k := range sortedKeys(existing) {
+			tagSet = append(tagSet, &s3.Tag{Key: awssdk.String(k), Value: awssdk.String(existing[k])})
+		}
+		if _, err = s.PutBucketTagging(&s3.PutBucketTaggingInput{Bucket: awssdk.String(name), Tagging: &s3.Tagging{TagSet: tagSet}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TagsPropertiesUpdate returns the properties of a resource changed by setting or removing tags,
+// so that the local graph reflects the change without a sync. A nil value means the property is removed
+func TagsPropertiesUpdate(res *graph.Resource, set map[string]string, removed []string) map[string]interface{} {
+	def := awsResourcesDef[res.Type()]
+	updates := make(map[string]interface{})
+
+	if nameDef, ok := def["Name"]; ok && nameDef.name == "Tags" {
+		if name, ok := set["Name"]; ok {
+			updates["Name"] = name
+		}
+		for _, k := range removed {
+			if k == "Name" {
+				updates["Name"] = nil
+			}
+		}
+	}
+
+	if _, ok := def["Tags"]; ok {
+		tags := make(map[string]string)
+		var existing []string
+		switch v := res.Properties["Tags"].(type) {
+		case []string:
+			existing = v
+		case []interface{}:
+			for _, t := range v {
+				existing = append(existing, fmt.Sprint(t))
+			}
+		}
+		for _, t := range existing {
+			splits := strings.SplitN(t, "=", 2)
+			if len(splits) == 2 {
+				tags[splits[0]] = splits[1]
+			}
+		}
+		for k, v := range set {
+			tags[k] = v
+		}
+		for _, k := range removed {
+			delete(tags, k)
+		}
+		if len(tags) == 0 {
+			updates["Tags"] = nil
+		} else {
+			var list []interface{}
+			for _, k := range sortedKeys(tags) {
+				list = append(list, fmt.Sprintf("%s=%s", k, tags[k]))
+			}
+			updates["Tags"] = list
+		}
+	}
+
+	return updates
+}
+
+func batchesOf(values []string, size int) (batches [][]string) {
+	for len(values) > size {
+		batches = append(batches, values[:size])
+		values = values[size:]
+	}
+	if len(values) > 0 {
+		batches = append(batches, values)
+	}
+	return
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/wallix/awless/graph"
+)
+
+type mockTaggingEc2 struct {
+	ec2iface.EC2API
+	createTagsCalls []*ec2.CreateTagsInput
+}
+
+func (m *mockTaggingEc2) CreateTags(input *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error) {
+	m.createTagsCalls = append(m.createTagsCalls, input)
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+func TestTagResourcesInBatches(t *testing.T) {
+	var ids []string
+	for i := 0; i < 2500; i++ {
+		ids = append(ids, fmt.Sprintf("i-%d", i))
+	}
+	mock := &mockTaggingEc2{}
+	infra := &Infra{EC2API: mock}
+
+	if err := infra.TagResources(ids, map[string]string{"env": "prod", "app": "web"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(mock.createTagsCalls), 3; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	var sizes []int
+	for _, call := range mock.createTagsCalls {
+		sizes = append(sizes, len(call.Resources))
+	}
+	if got, want := sizes, []int{1000, 1000, 500}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	expected := []*ec2.Tag{{Key: awssdk.String("app"), Value: awssdk.String("web")}, {Key: awssdk.String("env"), Value: awssdk.String("prod")}}
+	if got, want := mock.createTagsCalls[0].Tags, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestTagsPropertiesUpdate(t *testing.T) {
+	inst := graph.InitResource("inst_1", graph.Instance)
+	inst.Properties["Name"] = "web"
+	inst.Properties["Tags"] = []interface{}{"Name=web", "env=dev"}
+
+	updates := TagsPropertiesUpdate(inst, map[string]string{"env": "prod", "Name": "front"}, nil)
+	expected := map[string]interface{}{"Name": "front", "Tags": []interface{}{"Name=front", "env=prod"}}
+	if got, want := updates, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	updates = TagsPropertiesUpdate(inst, nil, []string{"Name", "env"})
+	expected = map[string]interface{}{"Name": nil, "Tags": nil}
+	if got, want := updates, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	bucket := graph.InitResource("my-bucket", graph.Bucket)
+	bucket.Properties["Name"] = "my-bucket"
+	if got := TagsPropertiesUpdate(bucket, map[string]string{"Name": "other"}, nil); len(got) != 0 {
+		t.Fatalf("expected no update of bucket properties, got %#v", got)
+	}
+}
@@ -124,14 +124,6 @@ func newResource(source interface{}) (*graph.Resource, error) {
 					resultc <- p
 				}
 			}
-			if t.fetch != nil {
-				val, err := t.fetch(source)
-				if err != nil {
-					errc <- err
-				}
-				p := graph.Property{Key: p, Value: val}
-				resultc <- p
-			}
 		}(prop, trans)
 	}
 
@@ -163,11 +155,9 @@ var ErrFieldNotFound = errors.New("aws struct field not found")
 type propertyTransform struct {
 	name      string
 	transform transformFn
-	fetch     fetchFn
 }
 
 type transformFn func(i interface{}) (interface{}, error)
-type fetchFn func(i interface{}) (interface{}, error)
 
 var extractValueFn = func(i interface{}) (interface{}, error) {
 	iv := reflect.ValueOf(i)
@@ -271,6 +261,22 @@ var extractTagFn = func(key string) transformFn {
 	}
 }
 
+// extractTagsFn extracts all the tags as 'key=value' strings
+var extractTagsFn = func(i interface{}) (interface{}, error) {
+	tags, ok := i.([]*ec2.Tag)
+	if !ok {
+		return nil, fmt.Errorf("aws model: unexpected type %T", i)
+	}
+	if len(tags) == 0 {
+		return nil, ErrTagNotFound
+	}
+	var res []interface{}
+	for _, t := range tags {
+		res = append(res, fmt.Sprintf("%s=%s", awssdk.StringValue(t.Key), awssdk.StringValue(t.Value)))
+	}
+	return res, nil
+}
+
 var extractSliceValues = func(key string) transformFn {
 	return func(i interface{}) (interface{}, error) {
 		var res []interface{}
@@ -290,6 +296,18 @@ var extractSliceValues = func(key string) transformFn {
 	}
 }
 
+var extractStringSliceValues = func(i interface{}) (interface{}, error) {
+	values, ok := i.([]*string)
+	if !ok {
+		return nil, fmt.Errorf("aws type unknown: %T", i)
+	}
+	var res []interface{}
+	for _, v := range values {
+		res = append(res, awssdk.StringValue(v))
+	}
+	return res, nil
+}
+
 var extractRoutesSliceFn = func(i interface{}) (interface{}, error) {
 	if _, ok := i.([]*ec2.Route); !ok {
 		return nil, fmt.Errorf("aws type unknown: %T", i)
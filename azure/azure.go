@@ -0,0 +1,102 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package azure provides a Microsoft Azure provider mapping virtual machines, networks,
+// security groups, storage accounts and managed identities onto the awless graph model.
+package azure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/wallix/awless/cloud"
+)
+
+const (
+	// SubscriptionKey is the config key of the Azure subscription to work with
+	SubscriptionKey = "azure.subscription"
+
+	tokenEnv        = "AZURE_ACCESS_TOKEN"
+	subscriptionEnv = "AZURE_SUBSCRIPTION_ID"
+)
+
+var ComputeService, StorageService, AccessService cloud.Service
+
+func init() {
+	cloud.RegisterProvider(provider{})
+}
+
+type provider struct{}
+
+func (provider) Name() string { return "azure" }
+
+// InitServices registers the Azure services for the subscription of the given config values
+func (provider) InitServices(defaults map[string]interface{}) error {
+	subscription, err := resolveSubscription(defaults)
+	if err != nil {
+		return err
+	}
+	token, err := accessToken()
+	if err != nil {
+		return err
+	}
+	api := newClient(subscription, token)
+
+	ComputeService = NewCompute(api)
+	StorageService = NewStorage(api)
+	AccessService = NewAccess(api)
+
+	cloud.ServiceRegistry[ComputeService.Name()] = ComputeService
+	cloud.ServiceRegistry[StorageService.Name()] = StorageService
+	cloud.ServiceRegistry[AccessService.Name()] = AccessService
+
+	return nil
+}
+
+func resolveSubscription(defaults map[string]interface{}) (string, error) {
+	if sub, ok := defaults[SubscriptionKey].(string); ok && sub != "" {
+		return sub, nil
+	}
+	if sub := os.Getenv(subscriptionEnv); sub != "" {
+		return sub, nil
+	}
+	if sub, err := az("account", "show", "--query", "id", "-o", "tsv"); err == nil && sub != "" {
+		return sub, nil
+	}
+	return "", fmt.Errorf("missing Azure subscription. Set it with `awless config set %s my-subscription-id` or export %s", SubscriptionKey, subscriptionEnv)
+}
+
+func accessToken() (string, error) {
+	if token := os.Getenv(tokenEnv); token != "" {
+		return token, nil
+	}
+	token, err := az("account", "get-access-token", "--query", "accessToken", "-o", "tsv")
+	if err != nil || token == "" {
+		return "", errors.New("Your Azure credentials seem undefined! Export " + tokenEnv + " or log in with `az login`")
+	}
+	return token, nil
+}
+
+func az(args ...string) (string, error) {
+	out, err := exec.Command("az", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
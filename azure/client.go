@@ -0,0 +1,129 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const managementURL = "https://management.azure.com"
+
+// client performs authenticated calls to the Azure Resource Manager API of a subscription
+type client struct {
+	httpClient   *http.Client
+	token        string
+	subscription string
+	baseURL      string
+}
+
+func newClient(subscription, token string) *client {
+	return &client{
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		token:        token,
+		subscription: subscription,
+		baseURL:      managementURL,
+	}
+}
+
+// apiError is the error body returned by the Resource Manager API
+type apiError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type httpError struct {
+	status int
+	msg    string
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("azure: %d %s", e.status, e.msg)
+}
+
+// providerURL returns the URL listing the resources of a provider type in the subscription
+func (c *client) providerURL(resourceType, apiVersion string, query ...string) string {
+	u := fmt.Sprintf("%s/subscriptions/%s/providers/%s?api-version=%s", c.baseURL, c.subscription, resourceType, apiVersion)
+	for _, q := range query {
+		u += "&" + q
+	}
+	return u
+}
+
+// resourceURL returns the URL of an action on a resource given its id
+func (c *client) resourceURL(id, action, apiVersion string) string {
+	u := c.baseURL + id
+	if action != "" {
+		u += "/" + action
+	}
+	return u + "?api-version=" + url.QueryEscape(apiVersion)
+}
+
+func (c *client) do(method, rawurl string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, rawurl, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr apiError
+		if json.Unmarshal(content, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, &httpError{status: resp.StatusCode, msg: apiErr.Error.Message}
+		}
+		return nil, &httpError{status: resp.StatusCode, msg: http.StatusText(resp.StatusCode)}
+	}
+	return content, nil
+}
+
+// getPages calls the given listing URL then follows the next links returned
+func (c *client) getPages(rawurl string, onPage func(body []byte) error) error {
+	for rawurl != "" {
+		body, err := c.do("GET", rawurl, nil)
+		if err != nil {
+			return err
+		}
+		if err = onPage(body); err != nil {
+			return err
+		}
+		var page struct {
+			NextLink string `json:"nextLink"`
+		}
+		if err = json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		rawurl = page.NextLink
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import "github.com/wallix/awless/template"
+
+// TemplatesDefinitions holds the definitions of the statements run by the Azure driver.
+// Resources are given by their Resource Manager id, except the ones to create
+var TemplatesDefinitions = map[string]template.TemplateDefinition{
+	"createinstance": {
+		Action:         "create",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"name", "resourcegroup", "location", "type", "image", "subnet", "username"},
+		ExtraParams:    []string{"password", "publickey", "tags"},
+		ParamTypes:     map[string]string{"image": "string", "location": "string", "name": "string", "password": "string", "publickey": "string", "resourcegroup": "string", "subnet": "string", "tags": "map", "type": "string", "username": "string"},
+	},
+	"createvpc": {
+		Action:         "create",
+		Entity:         "vpc",
+		Api:            "compute",
+		RequiredParams: []string{"name", "resourcegroup", "location", "cidr"},
+		ExtraParams:    []string{"tags"},
+		ParamTypes:     map[string]string{"cidr": "string", "location": "string", "name": "string", "resourcegroup": "string", "tags": "map"},
+	},
+	"createsubnet": {
+		Action:         "create",
+		Entity:         "subnet",
+		Api:            "compute",
+		RequiredParams: []string{"name", "vpc", "cidr"},
+		ExtraParams:    []string{"securitygroup"},
+		ParamTypes:     map[string]string{"cidr": "string", "name": "string", "securitygroup": "string", "vpc": "string"},
+	},
+	"createsecuritygroup": {
+		Action:         "create",
+		Entity:         "securitygroup",
+		Api:            "compute",
+		RequiredParams: []string{"name", "resourcegroup", "location"},
+		ExtraParams:    []string{"tags"},
+		ParamTypes:     map[string]string{"location": "string", "name": "string", "resourcegroup": "string", "tags": "map"},
+	},
+	"createbucket": {
+		Action:         "create",
+		Entity:         "bucket",
+		Api:            "storageaccounts",
+		RequiredParams: []string{"name", "resourcegroup", "location"},
+		ExtraParams:    []string{"sku", "kind", "tags"},
+		ParamTypes:     map[string]string{"kind": "string", "location": "string", "name": "string", "resourcegroup": "string", "sku": "string", "tags": "map"},
+	},
+	"createrole": {
+		Action:         "create",
+		Entity:         "role",
+		Api:            "identities",
+		RequiredParams: []string{"name", "resourcegroup", "location"},
+		ExtraParams:    []string{"tags"},
+		ParamTypes:     map[string]string{"location": "string", "name": "string", "resourcegroup": "string", "tags": "map"},
+	},
+	"updateinstance": {
+		Action:         "update",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ExtraParams:    []string{"type", "tags"},
+		ParamTypes:     map[string]string{"id": "string", "tags": "map", "type": "string"},
+	},
+	"updatevpc": {
+		Action:         "update",
+		Entity:         "vpc",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ExtraParams:    []string{"tags"},
+		ParamTypes:     map[string]string{"id": "string", "tags": "map"},
+	},
+	"updatesecuritygroup": {
+		Action:         "update",
+		Entity:         "securitygroup",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ExtraParams:    []string{"tags"},
+		ParamTypes:     map[string]string{"id": "string", "tags": "map"},
+	},
+	"updaterole": {
+		Action:         "update",
+		Entity:         "role",
+		Api:            "identities",
+		RequiredParams: []string{"id"},
+		ExtraParams:    []string{"tags"},
+		ParamTypes:     map[string]string{"id": "string", "tags": "map"},
+	},
+	"updatebucket": {
+		Action:         "update",
+		Entity:         "bucket",
+		Api:            "storageaccounts",
+		RequiredParams: []string{"id"},
+		ExtraParams:    []string{"sku", "tags"},
+		ParamTypes:     map[string]string{"id": "string", "sku": "string", "tags": "map"},
+	},
+	"deleteinstance": {
+		Action:         "delete",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"deletevpc": {
+		Action:         "delete",
+		Entity:         "vpc",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"deletesubnet": {
+		Action:         "delete",
+		Entity:         "subnet",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"deletesecuritygroup": {
+		Action:         "delete",
+		Entity:         "securitygroup",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"deletebucket": {
+		Action:         "delete",
+		Entity:         "bucket",
+		Api:            "storageaccounts",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"deleterole": {
+		Action:         "delete",
+		Entity:         "role",
+		Api:            "identities",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"startinstance": {
+		Action:         "start",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"stopinstance": {
+		Action:         "stop",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+}
@@ -0,0 +1,378 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template/driver"
+)
+
+// apiVersions are the Resource Manager API versions used to act on resources per type
+var apiVersions = map[string]string{
+	graph.Instance.String():      computeAPIVersion,
+	graph.Vpc.String():           networkAPIVersion,
+	graph.Subnet.String():        networkAPIVersion,
+	graph.SecurityGroup.String(): networkAPIVersion,
+	graph.Bucket.String():        storageAPIVersion,
+	graph.Role.String():          identityAPIVersion,
+}
+
+// resourceProviders are the Resource Manager types of the resources created per type
+var resourceProviders = map[string]string{
+	graph.Instance.String():      "Microsoft.Compute/virtualMachines",
+	graph.Vpc.String():           "Microsoft.Network/virtualNetworks",
+	graph.SecurityGroup.String(): "Microsoft.Network/networkSecurityGroups",
+	graph.Bucket.String():        "Microsoft.Storage/storageAccounts",
+	graph.Role.String():          "Microsoft.ManagedIdentity/userAssignedIdentities",
+}
+
+// provisioningPoll is the interval and number of checks of the provisioning state of created resources
+var provisioningPoll = struct {
+	interval time.Duration
+	attempts int
+}{5 * time.Second, 120}
+
+// Driver runs template commands against Resource Manager. Resources are identified
+// by their Resource Manager id. It supports creating, updating and deleting resources,
+// and starting and stopping (deallocating) virtual machines. Resources are created
+// with the 'name', 'resourcegroup' and 'location' params, except subnets created
+// in the 'vpc' virtual network given by id.
+type Driver struct {
+	api      *client
+	entities []string
+	dryRun   bool
+	logger   *logger.Logger
+}
+
+func NewDriver(api *client, entities ...string) driver.Driver {
+	return &Driver{api: api, entities: entities, logger: logger.DiscardLogger}
+}
+
+func (d *Driver) SetDryRun(dry bool)         { d.dryRun = dry }
+func (d *Driver) SetLogger(l *logger.Logger) { d.logger = l }
+
+func (d *Driver) Lookup(lookups ...string) (driver.DriverFn, error) {
+	if len(lookups) != 2 || !contains(d.entities, lookups[1]) {
+		return nil, driver.ErrDriverFnNotFound
+	}
+	action, entity := lookups[0], lookups[1]
+	switch {
+	case action == "create" && entity == graph.Subnet.String():
+		return d.createFn(action, entity, d.subnetRequest), nil
+	case action == "create" && resourceProviders[entity] != "":
+		return d.createFn(action, entity, d.resourceRequest), nil
+	case action == "update":
+		return d.updateFn(action, entity), nil
+	case action == "delete":
+		return d.actionFn("DELETE", action, entity, ""), nil
+	case action == "start" && entity == graph.Instance.String():
+		return d.actionFn("POST", action, entity, "start"), nil
+	case action == "stop" && entity == graph.Instance.String():
+		return d.actionFn("POST", action, entity, "deallocate"), nil
+	}
+	return nil, driver.ErrDriverFnNotFound
+}
+
+func (d *Driver) actionFn(method, action, entity, operation string) driver.DriverFn {
+	return func(params map[string]interface{}) (interface{}, error) {
+		id, ok := params["id"].(string)
+		if !ok || !strings.HasPrefix(id, "/subscriptions/") {
+			return nil, fmt.Errorf("%s %s: expecting a Resource Manager id, got '%v'", action, entity, params["id"])
+		}
+		version := apiVersions[entity]
+		if d.dryRun {
+			if _, err := d.api.do("GET", d.api.resourceURL(id, "", version), nil); err != nil {
+				d.logger.Errorf("dry run: %s %s error: %s", action, entity, err)
+				return nil, err
+			}
+			d.logger.Verbosef("full dry run: %s %s ok", action, entity)
+			return id, nil
+		}
+		if _, err := d.api.do(method, d.api.resourceURL(id, operation, version), nil); err != nil {
+			d.logger.Errorf("%s %s error: %s", action, entity, err)
+			return nil, err
+		}
+		d.logger.Verbosef("%s %s '%s' done", action, entity, id)
+		return id, nil
+	}
+}
+
+// createRequest returns the id and the body of the resource to create from the params
+type createRequest func(entity string, params map[string]interface{}) (string, map[string]interface{}, error)
+
+func (d *Driver) createFn(action, entity string, request createRequest) driver.DriverFn {
+	return func(params map[string]interface{}) (interface{}, error) {
+		id, body, err := request(entity, params)
+		if err != nil {
+			err = fmt.Errorf("%s %s: %s", action, entity, err)
+			d.logger.Errorf("%s", err)
+			return nil, err
+		}
+		if d.dryRun {
+			d.logger.Verbosef("full dry run: %s %s ok", action, entity)
+			return nil, nil
+		}
+		if entity == graph.Instance.String() {
+			if err = d.createNetworkInterface(id, body, params); err != nil {
+				d.logger.Errorf("%s %s error: %s", action, entity, err)
+				return nil, err
+			}
+		}
+		if err = d.put(id, apiVersions[entity], body); err != nil {
+			d.logger.Errorf("%s %s error: %s", action, entity, err)
+			return nil, err
+		}
+		d.logger.Verbosef("%s %s '%s' done", action, entity, id)
+		return id, nil
+	}
+}
+
+// resourceRequest builds the resources created in a resource group
+func (d *Driver) resourceRequest(entity string, params map[string]interface{}) (string, map[string]interface{}, error) {
+	name, group, location := stringParam(params, "name"), stringParam(params, "resourcegroup"), stringParam(params, "location")
+	if name == "" || group == "" || location == "" {
+		return "", nil, errors.New("missing 'name', 'resourcegroup' or 'location' param")
+	}
+	id := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/%s/%s", d.api.subscription, group, resourceProviders[entity], name)
+	body := map[string]interface{}{"location": location}
+	tags, err := tagsParam(params)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(tags) > 0 {
+		body["tags"] = tags
+	}
+
+	switch entity {
+	case graph.Vpc.String():
+		cidr := stringParam(params, "cidr")
+		if cidr == "" {
+			return "", nil, errors.New("missing 'cidr' param")
+		}
+		body["properties"] = map[string]interface{}{"addressSpace": map[string]interface{}{"addressPrefixes": []string{cidr}}}
+	case graph.Bucket.String():
+		sku, kind := stringParam(params, "sku"), stringParam(params, "kind")
+		if sku == "" {
+			sku = "Standard_LRS"
+		}
+		if kind == "" {
+			kind = "Storage"
+		}
+		body["sku"], body["kind"] = map[string]interface{}{"name": sku}, kind
+	case graph.Instance.String():
+		properties, err := virtualMachineProperties(name, params)
+		if err != nil {
+			return "", nil, err
+		}
+		body["properties"] = properties
+	}
+	return id, body, nil
+}
+
+// subnetRequest builds the subnets created in the virtual network given by the 'vpc' param
+func (d *Driver) subnetRequest(entity string, params map[string]interface{}) (string, map[string]interface{}, error) {
+	name, vpc, cidr := stringParam(params, "name"), stringParam(params, "vpc"), stringParam(params, "cidr")
+	if name == "" || cidr == "" || !strings.HasPrefix(vpc, "/subscriptions/") {
+		return "", nil, fmt.Errorf("expecting 'name', 'cidr' and a Resource Manager id as 'vpc' params, got '%s', '%s' and '%s'", name, cidr, vpc)
+	}
+	properties := map[string]interface{}{"addressPrefix": cidr}
+	if sg := stringParam(params, "securitygroup"); sg != "" {
+		properties["networkSecurityGroup"] = map[string]interface{}{"id": sg}
+	}
+	return vpc + "/subnets/" + name, map[string]interface{}{"properties": properties}, nil
+}
+
+// virtualMachineProperties returns the size, image, OS and network profiles of a virtual machine.
+// The image is given as 'publisher:offer:sku:version' and the administrator credentials
+// as the 'username' param with the 'password' or 'publickey' (OpenSSH format) param
+func virtualMachineProperties(name string, params map[string]interface{}) (map[string]interface{}, error) {
+	size, image, subnet := stringParam(params, "type"), stringParam(params, "image"), stringParam(params, "subnet")
+	if size == "" || subnet == "" {
+		return nil, errors.New("missing 'type' or 'subnet' param")
+	}
+	urn := strings.Split(image, ":")
+	if len(urn) != 4 {
+		return nil, fmt.Errorf("expecting 'image' as publisher:offer:sku:version, got '%s'", image)
+	}
+	username := stringParam(params, "username")
+	if username == "" {
+		return nil, errors.New("missing 'username' param")
+	}
+	osProfile := map[string]interface{}{"computerName": name, "adminUsername": username}
+	switch password, key := stringParam(params, "password"), stringParam(params, "publickey"); {
+	case key != "":
+		osProfile["linuxConfiguration"] = map[string]interface{}{
+			"disablePasswordAuthentication": true,
+			"ssh": map[string]interface{}{"publicKeys": []interface{}{map[string]interface{}{
+				"path": "/home/" + username + "/.ssh/authorized_keys", "keyData": key,
+			}}},
+		}
+	case password != "":
+		osProfile["adminPassword"] = password
+	default:
+		return nil, errors.New("missing 'password' or 'publickey' param")
+	}
+	return map[string]interface{}{
+		"hardwareProfile": map[string]interface{}{"vmSize": size},
+		"storageProfile": map[string]interface{}{
+			"imageReference": map[string]interface{}{"publisher": urn[0], "offer": urn[1], "sku": urn[2], "version": urn[3]},
+			"osDisk":         map[string]interface{}{"createOption": "FromImage"},
+		},
+		"osProfile": osProfile,
+	}, nil
+}
+
+// createNetworkInterface creates the network interface of a virtual machine in the 'subnet' param,
+// named after the machine, and sets it as the machine network profile
+func (d *Driver) createNetworkInterface(vmId string, vm map[string]interface{}, params map[string]interface{}) error {
+	nicId := strings.Replace(vmId, "/Microsoft.Compute/virtualMachines/", "/Microsoft.Network/networkInterfaces/", 1) + "-nic"
+	nic := map[string]interface{}{
+		"location": vm["location"],
+		"properties": map[string]interface{}{"ipConfigurations": []interface{}{map[string]interface{}{
+			"name":       "ipconfig1",
+			"properties": map[string]interface{}{"subnet": map[string]interface{}{"id": stringParam(params, "subnet")}},
+		}}},
+	}
+	if err := d.put(nicId, networkAPIVersion, nic); err != nil {
+		return err
+	}
+	vm["properties"].(map[string]interface{})["networkProfile"] = map[string]interface{}{
+		"networkInterfaces": []interface{}{map[string]interface{}{"id": nicId}},
+	}
+	return nil
+}
+
+// put creates or replaces a resource then waits for it to be provisioned
+func (d *Driver) put(id, version string, body map[string]interface{}) error {
+	content, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	if _, err = d.api.do("PUT", d.api.resourceURL(id, "", version), bytes.NewReader(content)); err != nil {
+		return err
+	}
+	return d.waitProvisioned(id, version)
+}
+
+// waitProvisioned checks the provisioning state of a resource until it succeeds or fails.
+// Resources without provisioning state are provisioned once they can be read
+func (d *Driver) waitProvisioned(id, version string) error {
+	for i := 0; i < provisioningPoll.attempts; i++ {
+		if i > 0 {
+			time.Sleep(provisioningPoll.interval)
+		}
+		body, err := d.api.do("GET", d.api.resourceURL(id, "", version), nil)
+		if e, ok := err.(*httpError); ok && e.status == 404 {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		var res struct {
+			Properties struct {
+				ProvisioningState string `json:"provisioningState"`
+			} `json:"properties"`
+		}
+		if err = json.Unmarshal(body, &res); err != nil {
+			return err
+		}
+		switch state := res.Properties.ProvisioningState; state {
+		case "", "Succeeded":
+			return nil
+		case "Failed", "Canceled":
+			return fmt.Errorf("provisioning of %s %s", id, strings.ToLower(state))
+		}
+	}
+	return fmt.Errorf("provisioning of %s not done after %s", id, time.Duration(provisioningPoll.attempts)*provisioningPoll.interval)
+}
+
+// updateFn patches the tags of resources given by id, the size of virtual machines
+// with the 'type' param and the sku of storage accounts with the 'sku' param
+func (d *Driver) updateFn(action, entity string) driver.DriverFn {
+	return func(params map[string]interface{}) (interface{}, error) {
+		id := stringParam(params, "id")
+		if !strings.HasPrefix(id, "/subscriptions/") {
+			return nil, fmt.Errorf("%s %s: expecting a Resource Manager id, got '%v'", action, entity, params["id"])
+		}
+		body := make(map[string]interface{})
+		tags, err := tagsParam(params)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %s", action, entity, err)
+		}
+		if len(tags) > 0 {
+			body["tags"] = tags
+		}
+		if size := stringParam(params, "type"); size != "" && entity == graph.Instance.String() {
+			body["properties"] = map[string]interface{}{"hardwareProfile": map[string]interface{}{"vmSize": size}}
+		}
+		if sku := stringParam(params, "sku"); sku != "" && entity == graph.Bucket.String() {
+			body["sku"] = map[string]interface{}{"name": sku}
+		}
+		if len(body) == 0 {
+			return nil, fmt.Errorf("%s %s: nothing to update", action, entity)
+		}
+		version := apiVersions[entity]
+		if d.dryRun {
+			if _, err := d.api.do("GET", d.api.resourceURL(id, "", version), nil); err != nil {
+				d.logger.Errorf("dry run: %s %s error: %s", action, entity, err)
+				return nil, err
+			}
+			d.logger.Verbosef("full dry run: %s %s ok", action, entity)
+			return id, nil
+		}
+		content, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = d.api.do("PATCH", d.api.resourceURL(id, "", version), bytes.NewReader(content)); err != nil {
+			d.logger.Errorf("%s %s error: %s", action, entity, err)
+			return nil, err
+		}
+		d.logger.Verbosef("%s %s '%s' done", action, entity, id)
+		return id, nil
+	}
+}
+
+func stringParam(params map[string]interface{}, key string) string {
+	s, _ := params[key].(string)
+	return s
+}
+
+// tagsParam converts the 'tags' map param into Azure tags
+func tagsParam(params map[string]interface{}) (map[string]string, error) {
+	tags, ok := params["tags"]
+	if !ok {
+		return nil, nil
+	}
+	m, isMap := tags.(map[string]interface{})
+	if !isMap {
+		return nil, fmt.Errorf("expecting a map for 'tags' (ex: tags={Env:prod}), got %v", tags)
+	}
+	res := make(map[string]string)
+	for k, v := range m {
+		res[k] = fmt.Sprint(v)
+	}
+	return res, nil
+}
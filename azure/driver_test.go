@@ -0,0 +1,178 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/template/driver"
+)
+
+func TestDriver(t *testing.T) {
+	var calls []string
+	api, closeFn := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+	})
+	defer closeFn()
+
+	d := NewDriver(api, "instance", "subnet")
+	if _, err := d.Lookup("start", "subnet"); err != driver.ErrDriverFnNotFound {
+		t.Fatalf("got %v, want %v", err, driver.ErrDriverFnNotFound)
+	}
+	if _, err := d.Lookup("delete", "bucket"); err != driver.ErrDriverFnNotFound {
+		t.Fatalf("got %v, want %v", err, driver.ErrDriverFnNotFound)
+	}
+
+	stop, err := d.Lookup("stop", "instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = stop(map[string]interface{}{"id": "i-12345"}); err == nil {
+		t.Fatal("expected error for non Resource Manager id")
+	}
+	id, err := stop(map[string]interface{}{"id": vmId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := id, vmId; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	d.SetDryRun(true)
+	del, _ := d.Lookup("delete", "instance")
+	if _, err = del(map[string]interface{}{"id": vmId}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"POST " + vmId + "/deallocate?api-version=" + computeAPIVersion,
+		"GET " + vmId + "?api-version=" + computeAPIVersion,
+	}
+	if got, want := len(calls), len(expected); got != want {
+		t.Fatalf("got %d calls %v, want %d", got, calls, want)
+	}
+	for i := range expected {
+		if got, want := calls[i], expected[i]; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	}
+}
+
+func TestDriverCreateAndUpdate(t *testing.T) {
+	provisioningPoll.interval = time.Millisecond
+	var calls []string
+	bodies := make(map[string]interface{})
+	states := []string{"Creating", "Succeeded"}
+	api, closeFn := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.Path
+		calls = append(calls, call)
+		if content, _ := ioutil.ReadAll(r.Body); len(content) > 0 {
+			var body interface{}
+			if err := json.Unmarshal(content, &body); err != nil {
+				t.Fatal(err)
+			}
+			bodies[call] = body
+		}
+		if r.Method == "GET" {
+			state := states[0]
+			if len(states) > 1 {
+				states = states[1:]
+			}
+			w.Write([]byte(`{"properties": {"provisioningState": "` + state + `"}}`))
+		}
+	})
+	defer closeFn()
+
+	d := NewDriver(api, "instance", "vpc", "subnet", "bucket")
+	create, err := d.Lookup("create", "instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = create(map[string]interface{}{"name": "web1", "resourcegroup": "prod", "location": "westeurope", "type": "Standard_B1s",
+		"subnet": subnetId, "image": "Canonical:UbuntuServer", "username": "admin", "password": "secret"}); err == nil {
+		t.Fatal("expected error for invalid image")
+	}
+	id, err := create(map[string]interface{}{"name": "web1", "resourcegroup": "prod", "location": "westeurope", "type": "Standard_B1s",
+		"subnet": subnetId, "image": "Canonical:UbuntuServer:18.04-LTS:latest", "username": "admin", "password": "secret", "tags": map[string]interface{}{"env": "prod"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := id, vmId; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	nicId := "/subscriptions/sub1/resourceGroups/prod/providers/Microsoft.Network/networkInterfaces/web1-nic"
+	expected := map[string]interface{}{
+		"location": "westeurope",
+		"tags":     map[string]interface{}{"env": "prod"},
+		"properties": map[string]interface{}{
+			"hardwareProfile": map[string]interface{}{"vmSize": "Standard_B1s"},
+			"storageProfile": map[string]interface{}{
+				"imageReference": map[string]interface{}{"publisher": "Canonical", "offer": "UbuntuServer", "sku": "18.04-LTS", "version": "latest"},
+				"osDisk":         map[string]interface{}{"createOption": "FromImage"},
+			},
+			"osProfile":      map[string]interface{}{"computerName": "web1", "adminUsername": "admin", "adminPassword": "secret"},
+			"networkProfile": map[string]interface{}{"networkInterfaces": []interface{}{map[string]interface{}{"id": nicId}}},
+		},
+	}
+	if got, want := bodies["PUT "+vmId], expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	subnet, _ := d.Lookup("create", "subnet")
+	if id, err = subnet(map[string]interface{}{"name": "web", "vpc": vnetId, "cidr": "10.0.1.0/24"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := id, subnetId; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	update, _ := d.Lookup("update", "bucket")
+	if _, err = update(map[string]interface{}{"id": "/subscriptions/sub1/resourceGroups/prod/providers/Microsoft.Storage/storageAccounts/assets", "type": "Standard_B2s"}); err == nil {
+		t.Fatal("expected error for nothing to update")
+	}
+	if _, err = update(map[string]interface{}{"id": "/subscriptions/sub1/resourceGroups/prod/providers/Microsoft.Storage/storageAccounts/assets", "sku": "Standard_GRS"}); err != nil {
+		t.Fatal(err)
+	}
+
+	d.SetDryRun(true)
+	vpc, _ := d.Lookup("create", "vpc")
+	if _, err = vpc(map[string]interface{}{"name": "vnet1", "resourcegroup": "prod", "location": "westeurope"}); err == nil {
+		t.Fatal("expected error for missing cidr")
+	}
+	if _, err = vpc(map[string]interface{}{"name": "vnet1", "resourcegroup": "prod", "location": "westeurope", "cidr": "10.0.0.0/16"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedCalls := []string{
+		"PUT " + nicId,
+		"GET " + nicId,
+		"GET " + nicId,
+		"PUT " + vmId,
+		"GET " + vmId,
+		"PUT " + subnetId,
+		"GET " + subnetId,
+		"PATCH /subscriptions/sub1/resourceGroups/prod/providers/Microsoft.Storage/storageAccounts/assets",
+	}
+	if got, want := calls, expectedCalls; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
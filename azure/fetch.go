@@ -0,0 +1,360 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wallix/awless/graph"
+)
+
+const (
+	computeAPIVersion  = "2019-07-01"
+	networkAPIVersion  = "2017-03-01"
+	storageAPIVersion  = "2016-12-01"
+	identityAPIVersion = "2018-11-30"
+)
+
+// armResource holds the fields common to all Resource Manager resources
+type armResource struct {
+	Id       string            `json:"id"`
+	Name     string            `json:"name"`
+	Location string            `json:"location"`
+	Tags     map[string]string `json:"tags"`
+}
+
+type virtualNetwork struct {
+	armResource
+	Properties struct {
+		AddressSpace struct {
+			AddressPrefixes []string `json:"addressPrefixes"`
+		} `json:"addressSpace"`
+		ProvisioningState string `json:"provisioningState"`
+		Subnets           []struct {
+			Id         string `json:"id"`
+			Name       string `json:"name"`
+			Properties struct {
+				AddressPrefix        string `json:"addressPrefix"`
+				ProvisioningState    string `json:"provisioningState"`
+				NetworkSecurityGroup *struct {
+					Id string `json:"id"`
+				} `json:"networkSecurityGroup"`
+			} `json:"properties"`
+		} `json:"subnets"`
+	} `json:"properties"`
+}
+
+type securityRule struct {
+	Properties struct {
+		Protocol             string `json:"protocol"`
+		DestinationPortRange string `json:"destinationPortRange"`
+		SourceAddressPrefix  string `json:"sourceAddressPrefix"`
+		Access               string `json:"access"`
+		Direction            string `json:"direction"`
+	} `json:"properties"`
+}
+
+type networkSecurityGroup struct {
+	armResource
+	Properties struct {
+		SecurityRules []securityRule `json:"securityRules"`
+	} `json:"properties"`
+}
+
+type virtualMachine struct {
+	armResource
+	Zones      []string `json:"zones"`
+	Properties struct {
+		VmId            string `json:"vmId"`
+		HardwareProfile struct {
+			VmSize string `json:"vmSize"`
+		} `json:"hardwareProfile"`
+		OsProfile struct {
+			AdminUsername string `json:"adminUsername"`
+		} `json:"osProfile"`
+		NetworkProfile struct {
+			NetworkInterfaces []struct {
+				Id string `json:"id"`
+			} `json:"networkInterfaces"`
+		} `json:"networkProfile"`
+		InstanceView struct {
+			Statuses []struct {
+				Code string `json:"code"`
+			} `json:"statuses"`
+		} `json:"instanceView"`
+	} `json:"properties"`
+}
+
+type networkInterface struct {
+	armResource
+	Properties struct {
+		IpConfigurations []struct {
+			Properties struct {
+				PrivateIPAddress string `json:"privateIPAddress"`
+				Subnet           struct {
+					Id string `json:"id"`
+				} `json:"subnet"`
+			} `json:"properties"`
+		} `json:"ipConfigurations"`
+	} `json:"properties"`
+}
+
+type storageAccount struct {
+	armResource
+	Kind string `json:"kind"`
+	Sku  struct {
+		Name string `json:"name"`
+	} `json:"sku"`
+	Properties struct {
+		CreationTime string `json:"creationTime"`
+	} `json:"properties"`
+}
+
+type managedIdentity struct {
+	armResource
+	Properties struct {
+		PrincipalId string `json:"principalId"`
+		ClientId    string `json:"clientId"`
+	} `json:"properties"`
+}
+
+func initResource(r armResource, t graph.ResourceType) *graph.Resource {
+	res := graph.InitResource(r.Id, t)
+	res.Properties["Id"] = r.Id
+	res.Properties["Name"] = r.Name
+	setIfNotEmpty(res, "Location", r.Location)
+	setIfNotEmpty(res, "ResourceGroup", resourceGroup(r.Id))
+	if tags := tagsToStrings(r.Tags); len(tags) > 0 {
+		res.Properties["Tags"] = tags
+	}
+	return res
+}
+
+func fetchVirtualNetworks(api *client) ([]*graph.Resource, error) {
+	var resources []*graph.Resource
+	err := api.getPages(api.providerURL("Microsoft.Network/virtualNetworks", networkAPIVersion), func(body []byte) error {
+		var page struct {
+			Value []virtualNetwork `json:"value"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, vnet := range page.Value {
+			res := initResource(vnet.armResource, graph.Vpc)
+			if prefixes := vnet.Properties.AddressSpace.AddressPrefixes; len(prefixes) > 0 {
+				res.Properties["CidrBlock"] = prefixes[0]
+			}
+			setIfNotEmpty(res, "State", strings.ToLower(vnet.Properties.ProvisioningState))
+			resources = append(resources, res)
+
+			for _, s := range vnet.Properties.Subnets {
+				sub := graph.InitResource(s.Id, graph.Subnet)
+				sub.Properties["Id"] = s.Id
+				sub.Properties["Name"] = s.Name
+				sub.Properties["VpcId"] = vnet.Id
+				setIfNotEmpty(sub, "CidrBlock", s.Properties.AddressPrefix)
+				setIfNotEmpty(sub, "State", strings.ToLower(s.Properties.ProvisioningState))
+				setIfNotEmpty(sub, "Location", vnet.Location)
+				if nsg := s.Properties.NetworkSecurityGroup; nsg != nil {
+					sub.Properties["SecurityGroups"] = []string{nsg.Id}
+				}
+				resources = append(resources, sub)
+			}
+		}
+		return nil
+	})
+	return resources, err
+}
+
+func fetchSecurityGroups(api *client) ([]*graph.Resource, error) {
+	var resources []*graph.Resource
+	err := api.getPages(api.providerURL("Microsoft.Network/networkSecurityGroups", networkAPIVersion), func(body []byte) error {
+		var page struct {
+			Value []networkSecurityGroup `json:"value"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, nsg := range page.Value {
+			res := initResource(nsg.armResource, graph.SecurityGroup)
+			var inbound, outbound []*graph.FirewallRule
+			for _, r := range nsg.Properties.SecurityRules {
+				if !strings.EqualFold(r.Properties.Access, "Allow") {
+					continue
+				}
+				rule := toFirewallRule(r)
+				if strings.EqualFold(r.Properties.Direction, "Inbound") {
+					inbound = append(inbound, rule)
+				} else {
+					outbound = append(outbound, rule)
+				}
+			}
+			if len(inbound) > 0 {
+				res.Properties["InboundRules"] = inbound
+			}
+			if len(outbound) > 0 {
+				res.Properties["OutboundRules"] = outbound
+			}
+			resources = append(resources, res)
+		}
+		return nil
+	})
+	return resources, err
+}
+
+// toFirewallRule converts an allowing security rule, ignoring service tags sources
+// (ex: VirtualNetwork) which are not IP ranges
+func toFirewallRule(r securityRule) *graph.FirewallRule {
+	rule := &graph.FirewallRule{Protocol: strings.ToLower(r.Properties.Protocol)}
+	if rule.Protocol == "*" {
+		rule.Protocol = "any"
+	}
+
+	ports := r.Properties.DestinationPortRange
+	if ports == "*" || ports == "" {
+		rule.PortRange = graph.PortRange{Any: true}
+	} else {
+		bounds := strings.SplitN(ports, "-", 2)
+		from, _ := strconv.ParseInt(bounds[0], 10, 64)
+		to := from
+		if len(bounds) == 2 {
+			to, _ = strconv.ParseInt(bounds[1], 10, 64)
+		}
+		rule.PortRange = graph.PortRange{FromPort: from, ToPort: to}
+	}
+
+	source := r.Properties.SourceAddressPrefix
+	switch {
+	case source == "*" || strings.EqualFold(source, "Internet"):
+		source = "0.0.0.0/0"
+	case !strings.Contains(source, "/") && net.ParseIP(source) != nil:
+		source = source + "/32"
+	}
+	if _, ipnet, err := net.ParseCIDR(source); err == nil {
+		rule.IPRanges = append(rule.IPRanges, ipnet)
+	}
+	return rule
+}
+
+func fetchVirtualMachines(api *client) ([]*graph.Resource, error) {
+	type nicInfo struct{ subnetId, privateIp string }
+	nics := make(map[string]nicInfo)
+	err := api.getPages(api.providerURL("Microsoft.Network/networkInterfaces", networkAPIVersion), func(body []byte) error {
+		var page struct {
+			Value []networkInterface `json:"value"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, nic := range page.Value {
+			if configs := nic.Properties.IpConfigurations; len(configs) > 0 {
+				nics[strings.ToLower(nic.Id)] = nicInfo{subnetId: configs[0].Properties.Subnet.Id, privateIp: configs[0].Properties.PrivateIPAddress}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*graph.Resource
+	err = api.getPages(api.providerURL("Microsoft.Compute/virtualMachines", computeAPIVersion, "statusOnly=true"), func(body []byte) error {
+		var page struct {
+			Value []virtualMachine `json:"value"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, vm := range page.Value {
+			res := initResource(vm.armResource, graph.Instance)
+			setIfNotEmpty(res, "Type", vm.Properties.HardwareProfile.VmSize)
+			setIfNotEmpty(res, "KeyName", vm.Properties.OsProfile.AdminUsername)
+			zone := vm.Location
+			if len(vm.Zones) > 0 {
+				zone = vm.Location + "-" + vm.Zones[0]
+			}
+			setIfNotEmpty(res, "AvailabilityZone", zone)
+			for _, status := range vm.Properties.InstanceView.Statuses {
+				if strings.HasPrefix(status.Code, "PowerState/") {
+					res.Properties["State"] = strings.TrimPrefix(status.Code, "PowerState/")
+				}
+			}
+			if ifaces := vm.Properties.NetworkProfile.NetworkInterfaces; len(ifaces) > 0 {
+				if nic, ok := nics[strings.ToLower(ifaces[0].Id)]; ok {
+					setIfNotEmpty(res, "SubnetId", nic.subnetId)
+					setIfNotEmpty(res, "PrivateIp", nic.privateIp)
+				}
+			}
+			resources = append(resources, res)
+		}
+		return nil
+	})
+	return resources, err
+}
+
+func fetchStorageAccounts(api *client) ([]*graph.Resource, error) {
+	var resources []*graph.Resource
+	err := api.getPages(api.providerURL("Microsoft.Storage/storageAccounts", storageAPIVersion), func(body []byte) error {
+		var page struct {
+			Value []storageAccount `json:"value"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, sa := range page.Value {
+			res := initResource(sa.armResource, graph.Bucket)
+			setIfNotEmpty(res, "Kind", sa.Kind)
+			setIfNotEmpty(res, "Class", sa.Sku.Name)
+			if t, err := time.Parse(time.RFC3339, sa.Properties.CreationTime); err == nil {
+				res.Properties["CreateDate"] = t
+			}
+			resources = append(resources, res)
+		}
+		return nil
+	})
+	return resources, err
+}
+
+func fetchManagedIdentities(api *client) ([]*graph.Resource, error) {
+	var resources []*graph.Resource
+	err := api.getPages(api.providerURL("Microsoft.ManagedIdentity/userAssignedIdentities", identityAPIVersion), func(body []byte) error {
+		var page struct {
+			Value []managedIdentity `json:"value"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, mi := range page.Value {
+			res := initResource(mi.armResource, graph.Role)
+			setIfNotEmpty(res, "PrincipalId", mi.Properties.PrincipalId)
+			setIfNotEmpty(res, "ClientId", mi.Properties.ClientId)
+			resources = append(resources, res)
+		}
+		return nil
+	})
+	return resources, err
+}
+
+func setIfNotEmpty(res *graph.Resource, key, value string) {
+	if value != "" {
+		res.Properties[key] = value
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template/driver"
+)
+
+type fetchFunc func(api *client) ([]*graph.Resource, error)
+
+// fetcher lists resources of one or more types in a single API call
+type fetcher struct {
+	types []string
+	fetch fetchFunc
+}
+
+type service struct {
+	name     string
+	api      *client
+	fetchers []fetcher
+}
+
+func NewCompute(api *client) cloud.Service {
+	return &service{name: "compute", api: api, fetchers: []fetcher{
+		{types: []string{graph.Vpc.String(), graph.Subnet.String()}, fetch: fetchVirtualNetworks},
+		{types: []string{graph.SecurityGroup.String()}, fetch: fetchSecurityGroups},
+		{types: []string{graph.Instance.String()}, fetch: fetchVirtualMachines},
+	}}
+}
+
+func NewStorage(api *client) cloud.Service {
+	return &service{name: "storageaccounts", api: api, fetchers: []fetcher{
+		{types: []string{graph.Bucket.String()}, fetch: fetchStorageAccounts},
+	}}
+}
+
+func NewAccess(api *client) cloud.Service {
+	return &service{name: "identities", api: api, fetchers: []fetcher{
+		{types: []string{graph.Role.String()}, fetch: fetchManagedIdentities},
+	}}
+}
+
+func (s *service) Name() string { return s.name }
+
+func (s *service) Drivers() []driver.Driver {
+	return []driver.Driver{NewDriver(s.api, s.ResourceTypes()...)}
+}
+
+func (s *service) ResourceTypes() (types []string) {
+	for _, f := range s.fetchers {
+		types = append(types, f.types...)
+	}
+	return
+}
+
+func (s *service) FetchResources() (*graph.Graph, error) {
+	var all []*graph.Resource
+	for _, f := range s.fetchers {
+		resources, err := f.fetch(s.api)
+		if err != nil {
+			return graph.NewGraph(), convertError(err)
+		}
+		all = append(all, resources...)
+	}
+	return buildGraph(all)
+}
+
+func (s *service) FetchByType(t string) (*graph.Graph, error) {
+	for _, f := range s.fetchers {
+		if !contains(f.types, t) {
+			continue
+		}
+		resources, err := f.fetch(s.api)
+		if err != nil {
+			return graph.NewGraph(), convertError(err)
+		}
+		var typed []*graph.Resource
+		for _, res := range resources {
+			if res.Type().String() == t {
+				typed = append(typed, res)
+			}
+		}
+		return buildGraph(typed)
+	}
+	return nil, fmt.Errorf("azure %s: unsupported fetch for type %s", s.name, t)
+}
+
+// buildGraph adds the resources to a new graph, relating subnets to their network
+// and virtual machines to their subnet when fetched together.
+// Resource Manager ids are case insensitive.
+func buildGraph(resources []*graph.Resource) (*graph.Graph, error) {
+	g := graph.NewGraph()
+	byId := make(map[string]*graph.Resource)
+	for _, res := range resources {
+		byId[strings.ToLower(res.Id())] = res
+	}
+	if err := g.AddResource(resources...); err != nil {
+		return g, err
+	}
+	for _, res := range resources {
+		var parentId string
+		switch res.Type() {
+		case graph.Instance:
+			parentId = stringProp(res, "SubnetId")
+		case graph.Subnet:
+			parentId = stringProp(res, "VpcId")
+		}
+		if parent, ok := byId[strings.ToLower(parentId)]; ok && parentId != "" {
+			if err := g.AddParentRelation(parent, res); err != nil {
+				return g, err
+			}
+		}
+	}
+	return g, nil
+}
+
+func convertError(err error) error {
+	if e, ok := err.(*httpError); ok && (e.status == http.StatusForbidden || e.status == http.StatusUnauthorized) {
+		return cloud.ErrFetchAccessDenied
+	}
+	return err
+}
+
+func contains(arr []string, s string) bool {
+	for _, a := range arr {
+		if a == s {
+			return true
+		}
+	}
+	return false
+}
+
+func stringProp(res *graph.Resource, key string) string {
+	s, _ := res.Properties[key].(string)
+	return s
+}
+
+// resourceGroup extracts the resource group from a Resource Manager id
+func resourceGroup(id string) string {
+	parts := strings.Split(id, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if strings.EqualFold(parts[i], "resourceGroups") {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// tagsToStrings converts Azure tags into awless 'key=value' tags
+func tagsToStrings(tags map[string]string) []string {
+	var res []string
+	for k, v := range tags {
+		res = append(res, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(res)
+	return res
+}
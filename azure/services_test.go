@@ -0,0 +1,125 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wallix/awless/graph"
+)
+
+const (
+	vnetId   = "/subscriptions/sub1/resourceGroups/prod/providers/Microsoft.Network/virtualNetworks/vnet1"
+	subnetId = vnetId + "/subnets/web"
+	vmId     = "/subscriptions/sub1/resourceGroups/prod/providers/Microsoft.Compute/virtualMachines/web1"
+)
+
+var apiResponses = map[string]string{
+	"/subscriptions/sub1/providers/Microsoft.Network/virtualNetworks": `{"value": [{"id": "` + vnetId + `", "name": "vnet1", "location": "westeurope",
+		"properties": {"addressSpace": {"addressPrefixes": ["10.0.0.0/16"]}, "provisioningState": "Succeeded",
+		"subnets": [{"id": "` + subnetId + `", "name": "web", "properties": {"addressPrefix": "10.0.1.0/24"}}]}}]}`,
+	"/subscriptions/sub1/providers/Microsoft.Network/networkInterfaces": `{"value": [{"id": "/subscriptions/sub1/resourceGroups/PROD/providers/Microsoft.Network/networkInterfaces/nic1",
+		"properties": {"ipConfigurations": [{"properties": {"privateIPAddress": "10.0.1.4", "subnet": {"id": "` + strings.ToUpper(subnetId) + `"}}}]}}]}`,
+	"/subscriptions/sub1/providers/Microsoft.Compute/virtualMachines": `{"value": [{"id": "` + vmId + `", "name": "web1", "location": "westeurope", "zones": ["2"], "tags": {"env": "prod"},
+		"properties": {"hardwareProfile": {"vmSize": "Standard_B1s"}, "networkProfile": {"networkInterfaces": [{"id": "/subscriptions/sub1/resourceGroups/prod/providers/Microsoft.Network/networkInterfaces/nic1"}]},
+		"instanceView": {"statuses": [{"code": "ProvisioningState/succeeded"}, {"code": "PowerState/running"}]}}}]}`,
+	"/subscriptions/sub1/providers/Microsoft.Network/networkSecurityGroups": `{"value": [{"id": "/subscriptions/sub1/resourceGroups/prod/providers/Microsoft.Network/networkSecurityGroups/web",
+		"name": "web", "properties": {"securityRules": [
+		{"properties": {"protocol": "Tcp", "destinationPortRange": "443", "sourceAddressPrefix": "*", "access": "Allow", "direction": "Inbound"}},
+		{"properties": {"protocol": "*", "destinationPortRange": "*", "sourceAddressPrefix": "*", "access": "Deny", "direction": "Inbound"}}]}}]}`,
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*client, func()) {
+	server := httptest.NewServer(handler)
+	api := newClient("sub1", "mytoken")
+	api.baseURL = server.URL
+	return api, server.Close
+}
+
+func listingHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Authorization"), "Bearer mytoken"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		body, ok := apiResponses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}
+}
+
+func TestFetchCompute(t *testing.T) {
+	api, closeFn := newTestClient(t, listingHandler(t))
+	defer closeFn()
+
+	g, err := NewCompute(api).FetchResources()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm, err := g.GetResource(graph.Instance, vmId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"Name":             "web1",
+		"Type":             "Standard_B1s",
+		"State":            "running",
+		"AvailabilityZone": "westeurope-2",
+		"ResourceGroup":    "prod",
+		"PrivateIp":        "10.0.1.4",
+		"SubnetId":         strings.ToUpper(subnetId),
+		"Tags":             []interface{}{"env=prod"},
+	}
+	for k, want := range expected {
+		if got := vm.Properties[k]; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %#v, want %#v", k, got, want)
+		}
+	}
+
+	var parents []*graph.Resource
+	if err = g.Accept(&graph.ParentsVisitor{From: vm, Each: graph.VisitorCollectFunc(&parents)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(parents), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := parents[0].Id(), subnetId; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := parents[1].Id(), vnetId; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	sgs, _ := g.GetAllResources(graph.SecurityGroup)
+	if got, want := len(sgs), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	rules, ok := sgs[0].Properties["InboundRules"].([]*graph.FirewallRule)
+	if !ok || len(rules) != 1 {
+		t.Fatalf("got %#v, want 1 inbound rule", sgs[0].Properties["InboundRules"])
+	}
+	if got, want := rules[0].String(), "PortRange:{FromPort:443 ToPort:443 Any:false}; Protocol:tcp; IPRanges:[0.0.0.0/0]"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
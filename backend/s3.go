@@ -0,0 +1,244 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backend shares graph snapshots and template executions through an S3 bucket,
+// so that a team works on the same inventory history and can revert each other's runs
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template"
+)
+
+const (
+	// BucketKey is the config key of the S3 bucket used as shared backend
+	BucketKey = "backend.s3.bucket"
+	// PrefixKey is the config key of the prefix of the keys written in the bucket (default: awless/, none with /)
+	PrefixKey = "backend.s3.prefix"
+	// RegionKey is the config key of the region of the bucket (default: the current region)
+	RegionKey = "backend.s3.region"
+	// KMSKeyIDKey is the config key of the KMS key encrypting the objects. Without it, objects
+	// are encrypted with S3 managed keys (SSE-S3)
+	KMSKeyIDKey = "backend.s3.kmskeyid"
+
+	defaultPrefix = "awless/"
+)
+
+// S3 stores in a bucket:
+//
+//	{prefix}{region}/graphs/{service}.rdf: the last synced graph of a service
+//	{prefix}{region}/snapshots/{yyyy}/{mm}/{dd}/{time}/{service}.rdf: the history of synced graphs
+//	{prefix}executions/{id}.json: the template executions, for logs and reverts
+type S3 struct {
+	bucket, prefix, kmsKeyID string
+	region, profile          string
+	api                      s3iface.S3API
+}
+
+// FromConfig returns the backend set in the given config values, or nil
+func FromConfig(defaults map[string]interface{}) *S3 {
+	bucket := configString(defaults, BucketKey)
+	if bucket == "" {
+		return nil
+	}
+	region := configString(defaults, RegionKey)
+	if region == "" {
+		region = configString(defaults, database.RegionKey)
+	}
+	prefix := defaultPrefix
+	if _, ok := defaults[PrefixKey]; ok {
+		prefix = configString(defaults, PrefixKey)
+	}
+	b := New(nil, bucket, prefix, configString(defaults, KMSKeyIDKey))
+	b.region, b.profile = region, configString(defaults, database.ProfileKey)
+	return b
+}
+
+// New returns a backend on the given bucket. A nil S3 API is initialized on first use
+func New(api s3iface.S3API, bucket, prefix, kmsKeyID string) *S3 {
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		prefix += "/"
+	}
+	return &S3{api: api, bucket: bucket, prefix: prefix, kmsKeyID: kmsKeyID}
+}
+
+func (b *S3) String() string {
+	return fmt.Sprintf("s3://%s/%s", b.bucket, b.prefix)
+}
+
+// PutGraphs uploads the graphs synced in a region as the last ones and in the snapshots history
+func (b *S3) PutGraphs(region string, graphs map[string]*graph.Graph, at time.Time) error {
+	snapshot := path.Join(b.prefix+region, "snapshots", at.UTC().Format("2006/01/02/20060102T150405Z"))
+	var names []string
+	for name := range graphs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := graphs[name].Marshal()
+		if err != nil {
+			return fmt.Errorf("marshal %s graph: %s", name, err)
+		}
+		for _, key := range []string{b.graphKey(region, name), path.Join(snapshot, name+".rdf")} {
+			if err := b.put(key, data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetGraph downloads the last graph synced for a service in a region
+func (b *S3) GetGraph(region, service string) (*graph.Graph, error) {
+	data, err := b.get(b.graphKey(region, service))
+	if err != nil {
+		return nil, err
+	}
+	g := graph.NewGraph()
+	return g, g.Unmarshal(data)
+}
+
+// PutExecution uploads a template execution
+func (b *S3) PutExecution(executed *template.TemplateExecution) error {
+	data, err := json.Marshal(executed)
+	if err != nil {
+		return err
+	}
+	return b.put(b.executionKey(executed.ID), data)
+}
+
+// GetExecution downloads a template execution given its id
+func (b *S3) GetExecution(id string) (*template.TemplateExecution, error) {
+	data, err := b.get(b.executionKey(id))
+	if err != nil {
+		return nil, err
+	}
+	executed := &template.TemplateExecution{}
+	return executed, json.Unmarshal(data, executed)
+}
+
+// ListExecutions downloads the last template executions, at most max of them, in chronological order
+func (b *S3) ListExecutions(max int) ([]*template.TemplateExecution, error) {
+	if err := b.init(); err != nil {
+		return nil, err
+	}
+	prefix := b.prefix + "executions/"
+	var ids []string
+	err := b.api.ListObjectsPages(&s3.ListObjectsInput{Bucket: awssdk.String(b.bucket), Prefix: awssdk.String(prefix)},
+		func(out *s3.ListObjectsOutput, lastPage bool) bool {
+			for _, obj := range out.Contents {
+				key := awssdk.StringValue(obj.Key)
+				if strings.HasSuffix(key, ".json") {
+					ids = append(ids, strings.TrimSuffix(strings.TrimPrefix(key, prefix), ".json"))
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+	// execution ids are ULIDs: sorting them sorts by date
+	sort.Strings(ids)
+	if max > 0 && len(ids) > max {
+		ids = ids[len(ids)-max:]
+	}
+	var all []*template.TemplateExecution
+	for _, id := range ids {
+		executed, err := b.GetExecution(id)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, executed)
+	}
+	return all, nil
+}
+
+func (b *S3) graphKey(region, service string) string {
+	return path.Join(b.prefix+region, "graphs", service+".rdf")
+}
+
+func (b *S3) executionKey(id string) string {
+	return b.prefix + "executions/" + id + ".json"
+}
+
+func (b *S3) put(key string, data []byte) error {
+	if err := b.init(); err != nil {
+		return err
+	}
+	input := &s3.PutObjectInput{
+		Bucket:               awssdk.String(b.bucket),
+		Key:                  awssdk.String(key),
+		Body:                 bytes.NewReader(data),
+		ServerSideEncryption: awssdk.String(s3.ServerSideEncryptionAes256),
+	}
+	if b.kmsKeyID != "" {
+		input.ServerSideEncryption = awssdk.String(s3.ServerSideEncryptionAwsKms)
+		input.SSEKMSKeyId = awssdk.String(b.kmsKeyID)
+	}
+	if _, err := b.api.PutObject(input); err != nil {
+		return fmt.Errorf("put s3://%s/%s: %s", b.bucket, key, err)
+	}
+	return nil
+}
+
+func (b *S3) get(key string) ([]byte, error) {
+	if err := b.init(); err != nil {
+		return nil, err
+	}
+	out, err := b.api.GetObject(&s3.GetObjectInput{Bucket: awssdk.String(b.bucket), Key: awssdk.String(key)})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, fmt.Errorf("s3://%s/%s not found", b.bucket, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get s3://%s/%s: %s", b.bucket, key, err)
+	}
+	defer out.Body.Close()
+	return ioutil.ReadAll(out.Body)
+}
+
+func (b *S3) init() error {
+	if b.api != nil {
+		return nil
+	}
+	sess, err := aws.InitSession(b.region, b.profile)
+	if err != nil {
+		return err
+	}
+	b.api = s3.New(sess)
+	return nil
+}
+
+func configString(defaults map[string]interface{}, key string) string {
+	if v, ok := defaults[key]; ok && v != nil {
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+	return ""
+}
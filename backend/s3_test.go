@@ -0,0 +1,161 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template"
+)
+
+type mockS3 struct {
+	s3iface.S3API
+	objects map[string][]byte
+	inputs  []*s3.PutObjectInput
+}
+
+func (m *mockS3) PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	data, _ := ioutil.ReadAll(input.Body)
+	m.objects[awssdk.StringValue(input.Key)] = data
+	m.inputs = append(m.inputs, input)
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (m *mockS3) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	data, ok := m.objects[awssdk.StringValue(input.Key)]
+	if !ok {
+		return nil, awserr.New(s3.ErrCodeNoSuchKey, "not found", nil)
+	}
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(data))}, nil
+}
+
+func (m *mockS3) ListObjectsPages(input *s3.ListObjectsInput, fn func(*s3.ListObjectsOutput, bool) bool) error {
+	var contents []*s3.Object
+	for k := range m.objects {
+		if strings.HasPrefix(k, awssdk.StringValue(input.Prefix)) {
+			contents = append(contents, &s3.Object{Key: awssdk.String(k)})
+		}
+	}
+	fn(&s3.ListObjectsOutput{Contents: contents}, true)
+	return nil
+}
+
+func TestGraphs(t *testing.T) {
+	mock := &mockS3{objects: make(map[string][]byte)}
+	b := New(mock, "team", "infra", "")
+
+	g := graph.NewGraph()
+	g.AddResource(graph.InitResource("i-1", graph.Instance))
+	at := time.Date(2017, 7, 14, 6, 30, 0, 0, time.UTC)
+	if err := b.PutGraphs("eu-west-1", map[string]*graph.Graph{"infra": g}, at); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for k := range mock.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if got, want := keys, []string{"infra/eu-west-1/graphs/infra.rdf", "infra/eu-west-1/snapshots/2017/07/14/20170714T063000Z/infra.rdf"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for _, input := range mock.inputs {
+		if got, want := awssdk.StringValue(input.ServerSideEncryption), "AES256"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	}
+
+	pulled, err := b.GetGraph("eu-west-1", "infra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pulled.GetResource(graph.Instance, "i-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.GetGraph("us-east-1", "infra"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestExecutions(t *testing.T) {
+	mock := &mockS3{objects: make(map[string][]byte)}
+	b := New(mock, "team", defaultPrefix, "arn:aws:kms:eu-west-1:123456789012:key/1")
+
+	for _, id := range []string{"01BN2", "01BN1", "01BN3"} {
+		executed := &template.TemplateExecution{ID: id, Executed: []*template.ExecutedStatement{{Line: "create instance name=web", Result: "i-" + id}}}
+		if err := b.PutExecution(executed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := awssdk.StringValue(mock.inputs[0].ServerSideEncryption), "aws:kms"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := awssdk.StringValue(mock.inputs[0].SSEKMSKeyId), "arn:aws:kms:eu-west-1:123456789012:key/1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	executed, err := b.GetExecution("01BN1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := executed.Executed[0].Result, "i-01BN1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	all, err := b.ListExecutions(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, e := range all {
+		ids = append(ids, e.ID)
+	}
+	if got, want := ids, []string{"01BN2", "01BN3"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	if b := FromConfig(map[string]interface{}{"region": "eu-west-1"}); b != nil {
+		t.Fatal("expected no backend")
+	}
+	b := FromConfig(map[string]interface{}{"region": "eu-west-1", BucketKey: "team"})
+	if got, want := b.String(), "s3://team/awless/"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := b.region, "eu-west-1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	b = FromConfig(map[string]interface{}{"region": "eu-west-1", BucketKey: "team", PrefixKey: "/", RegionKey: "us-east-1"})
+	if got, want := b.String(), "s3://team/"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := b.region, "us-east-1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+// HoursPerMonth is the number of hours used to convert hourly prices to monthly ones
+const HoursPerMonth = 730
+
+// A PriceSource gives the live prices in USD of cloud resources in the current region
+type PriceSource interface {
+	OnDemandHourly(instanceType string) (float64, error)
+	SpotHourly(instanceType, zone string) (float64, error)
+	VolumeMonthlyPerGB(volumeType string) (float64, error)
+	Reservations() ([]*Reservation, error)
+}
+
+// A Reservation is a set of reserved instances of the account. Zone is empty for regional reservations
+type Reservation struct {
+	InstanceType, Zone string
+	Count              int
+	Hourly             float64
+}
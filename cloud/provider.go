@@ -0,0 +1,65 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ProviderKey is the config key selecting the cloud provider
+// Ex: awless config set provider gcp
+const ProviderKey = "provider"
+
+// DefaultProvider is used when no provider is set in config
+const DefaultProvider = "aws"
+
+// Provider initializes and registers the services of a cloud platform
+type Provider interface {
+	Name() string
+	InitServices(defaults map[string]interface{}) error
+}
+
+var providers = make(map[string]Provider)
+
+// RegisterProvider makes a provider selectable by its name
+func RegisterProvider(p Provider) {
+	providers[p.Name()] = p
+}
+
+// GetProvider returns the named provider or the default one if name is empty
+func GetProvider(name string) (Provider, error) {
+	if name == "" {
+		name = DefaultProvider
+	}
+	p, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown cloud provider '%s': expecting one of %s", name, strings.Join(ProviderNames(), ", "))
+	}
+	return p, nil
+}
+
+// ProviderNames returns the sorted names of the registered providers
+func ProviderNames() []string {
+	var names []string
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import "testing"
+
+type stubProvider string
+
+func (p stubProvider) Name() string                                       { return string(p) }
+func (p stubProvider) InitServices(defaults map[string]interface{}) error { return nil }
+
+func TestGetProvider(t *testing.T) {
+	defer func(saved map[string]Provider) { providers = saved }(providers)
+	providers = make(map[string]Provider)
+	RegisterProvider(stubProvider("aws"))
+	RegisterProvider(stubProvider("gcp"))
+
+	p, err := GetProvider("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.Name(), "aws"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if p, _ = GetProvider("gcp"); p.Name() != "gcp" {
+		t.Fatalf("got %s, want gcp", p.Name())
+	}
+	_, err = GetProvider("azure")
+	if err == nil {
+		t.Fatal("expected error for unknown provider")
+	}
+	if got, want := err.Error(), "unknown cloud provider 'azure': expecting one of aws, gcp"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
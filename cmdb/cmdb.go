@@ -0,0 +1,270 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmdb converts awless graphs into configuration items and relationships
+// that ServiceNow (or any CMDB accepting its import formats) can ingest.
+package cmdb
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/wallix/awless/graph"
+)
+
+const (
+	// ClassKeyPrefix prefixes config keys overriding the CI class of an awless type (ex: cmdb.class.instance)
+	ClassKeyPrefix = "cmdb.class."
+
+	ContainsRelation  = "Contains::Contained by"
+	DependsOnRelation = "Depends on::Used by"
+
+	discoverySource = "awless"
+)
+
+// DefaultClasses maps awless resource types to ServiceNow CI classes.
+// Resources whose type is not mapped are not exported.
+var DefaultClasses = map[string]string{
+	graph.Region.String():           "cmdb_ci_aws_datacenter",
+	graph.AvailabilityZone.String(): "cmdb_ci_availability_zone",
+	graph.Vpc.String():              "cmdb_ci_network",
+	graph.Subnet.String():           "cmdb_ci_cloud_subnet",
+	graph.SecurityGroup.String():    "cmdb_ci_compute_security_group",
+	graph.Instance.String():         "cmdb_ci_vm_instance",
+	graph.Volume.String():           "cmdb_ci_storage_volume",
+	graph.Keypair.String():          "cmdb_ci_cloud_key_pair",
+	graph.InternetGateway.String():  "cmdb_ci_cloud_gateway",
+	graph.LoadBalancer.String():     "cmdb_ci_cloud_load_balancer",
+	graph.TargetGroup.String():      "cmdb_ci_lb_pool",
+	graph.Bucket.String():           "cmdb_ci_cloud_object_storage",
+	graph.Queue.String():            "cmdb_ci_cloud_queue",
+	graph.Topic.String():            "cmdb_ci_cloud_topic",
+	graph.Stack.String():            "cmdb_ci_cloud_stack",
+	graph.KubeCluster.String():      "cmdb_ci_kubernetes_cluster",
+	graph.KubeNode.String():         "cmdb_ci_kubernetes_node",
+	graph.KubeService.String():      "cmdb_ci_kubernetes_service",
+}
+
+// ClassesFromConfig returns the default classes with the overrides found in config.
+// An override with an empty value removes the type from the export.
+func ClassesFromConfig(defaults map[string]interface{}) map[string]string {
+	classes := make(map[string]string)
+	for t, class := range DefaultClasses {
+		classes[t] = class
+	}
+	for k, v := range defaults {
+		if !strings.HasPrefix(k, ClassKeyPrefix) {
+			continue
+		}
+		t := strings.TrimPrefix(k, ClassKeyPrefix)
+		if class := strings.TrimSpace(fmt.Sprint(v)); class != "" {
+			classes[t] = class
+		} else {
+			delete(classes, t)
+		}
+	}
+	return classes
+}
+
+type Item struct {
+	Class  string            `json:"className"`
+	Values map[string]string `json:"values"`
+}
+
+// Relation references items by their index in the export, as expected by the ServiceNow IRE payload
+type Relation struct {
+	Parent int    `json:"parent"`
+	Child  int    `json:"child"`
+	Type   string `json:"type"`
+}
+
+type Export struct {
+	Items     []*Item     `json:"items"`
+	Relations []*Relation `json:"relations"`
+}
+
+// New builds an export of the resources (and the relations between them) of the given graph
+func New(g *graph.Graph, classes map[string]string) (*Export, error) {
+	var types []string
+	for t := range classes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	var resources []*graph.Resource
+	for _, t := range types {
+		res, err := g.GetAllResources(graph.ResourceType(t))
+		if err != nil {
+			return nil, err
+		}
+		sort.Sort(graph.ResourceById(res))
+		resources = append(resources, res...)
+	}
+
+	export := &Export{Items: []*Item{}, Relations: []*Relation{}}
+	index := make(map[string]int)
+	for i, res := range resources {
+		index[key(res)] = i
+		export.Items = append(export.Items, &Item{Class: classes[res.Type().String()], Values: values(res)})
+	}
+
+	for i, res := range resources {
+		var parents []*graph.Resource
+		err := g.Accept(&graph.ParentsVisitor{From: res, Each: func(p *graph.Resource, depth int) error {
+			if depth == 1 {
+				parents = append(parents, p)
+			}
+			return nil
+		}})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range parents {
+			if parent, ok := index[key(p)]; ok {
+				export.Relations = append(export.Relations, &Relation{Parent: parent, Child: i, Type: ContainsRelation})
+			}
+		}
+
+		// a resource applying on another one (ex: a security group on an instance) is used by it
+		appliedOn, err := g.ListResourcesAppliedOn(res)
+		if err != nil {
+			return nil, err
+		}
+		sort.Sort(graph.ResourceById(appliedOn))
+		for _, dependent := range appliedOn {
+			if parent, ok := index[key(dependent)]; ok {
+				export.Relations = append(export.Relations, &Relation{Parent: parent, Child: i, Type: DependsOnRelation})
+			}
+		}
+	}
+
+	return export, nil
+}
+
+// WriteJSON writes the export as a ServiceNow Identification and Reconciliation (IRE) payload
+func (e *Export) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
+
+var itemColumns = []string{"sys_class_name", "object_id", "name", "operational_status", "state", "ip_address", "cidr", "location", "short_description", "discovery_source"}
+
+// WriteItemsCSV writes one line per configuration item, suitable for an Import Set
+func (e *Export) WriteItemsCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(itemColumns); err != nil {
+		return err
+	}
+	for _, item := range e.Items {
+		line := []string{item.Class}
+		for _, col := range itemColumns[1:] {
+			line = append(line, item.Values[col])
+		}
+		if err := cw.Write(line); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteRelationsCSV writes one line per relationship, referencing items by class and object id
+func (e *Export) WriteRelationsCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"parent_class", "parent", "type", "child_class", "child"}); err != nil {
+		return err
+	}
+	for _, rel := range e.Relations {
+		parent, child := e.Items[rel.Parent], e.Items[rel.Child]
+		if err := cw.Write([]string{parent.Class, parent.Values["object_id"], rel.Type, child.Class, child.Values["object_id"]}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func key(res *graph.Resource) string {
+	return res.Type().String() + "/" + res.Id()
+}
+
+func values(res *graph.Resource) map[string]string {
+	props := res.Properties
+	v := map[string]string{
+		"object_id":        res.Id(),
+		"name":             res.Id(),
+		"discovery_source": discoverySource,
+	}
+	if arn := stringProp(props, "Arn"); arn != "" {
+		v["object_id"] = arn
+	}
+	if name := stringProp(props, "Name"); name != "" {
+		v["name"] = name
+	}
+	if state := stringProp(props, "State"); state != "" {
+		v["state"] = state
+		v["operational_status"] = operationalStatus(state)
+	}
+	if ip := stringProp(props, "PublicIp"); ip != "" {
+		v["ip_address"] = ip
+	} else if ip := stringProp(props, "PrivateIp"); ip != "" {
+		v["ip_address"] = ip
+	}
+	if cidr := stringProp(props, "CidrBlock"); cidr != "" {
+		v["cidr"] = cidr
+	}
+	if zone := stringProp(props, "AvailabilityZone"); zone != "" {
+		v["location"] = zone
+	} else if region := stringProp(props, "Region"); region != "" {
+		v["location"] = region
+	}
+	if tags, ok := props["Tags"].([]interface{}); ok && len(tags) > 0 {
+		var all []string
+		for _, t := range tags {
+			all = append(all, fmt.Sprint(t))
+		}
+		v["short_description"] = strings.Join(all, ";")
+	}
+	return v
+}
+
+// operationalStatus maps a cloud state to ServiceNow operational_status choices
+// (1: Operational, 2: Non-Operational, 7: Retired)
+func operationalStatus(state string) string {
+	switch strings.ToLower(state) {
+	case "running", "available", "active", "in-use", "attached", "ok":
+		return "1"
+	case "terminated", "deleted", "shutting-down", "deleting":
+		return "7"
+	default:
+		if strings.HasSuffix(state, "_COMPLETE") && !strings.Contains(state, "DELETE") && !strings.Contains(state, "ROLLBACK") {
+			return "1"
+		}
+		return "2"
+	}
+}
+
+func stringProp(props graph.Properties, key string) string {
+	if v, ok := props[key]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wallix/awless/graph"
+)
+
+func TestExport(t *testing.T) {
+	g := graph.NewGraph()
+	region := graph.InitResource("eu-west-1", graph.Region)
+	vpc := graph.InitResource("vpc_1", graph.Vpc)
+	vpc.Properties["CidrBlock"] = "10.0.0.0/16"
+	sub := graph.InitResource("sub_1", graph.Subnet)
+	inst := graph.InitResource("inst_1", graph.Instance)
+	inst.Properties["Name"] = "web"
+	inst.Properties["State"] = "running"
+	inst.Properties["PrivateIp"] = "10.0.0.12"
+	inst.Properties["Tags"] = []interface{}{"Name=web", "Env=prod"}
+	secgroup := graph.InitResource("sg_1", graph.SecurityGroup)
+	user := graph.InitResource("usr_1", graph.User)
+	g.AddResource(region, vpc, sub, inst, secgroup, user)
+	g.AddParentRelation(region, vpc)
+	g.AddParentRelation(vpc, sub)
+	g.AddParentRelation(sub, inst)
+	g.AddParentRelation(vpc, secgroup)
+	g.AddAppliesOnRelation(secgroup, inst)
+
+	export, err := New(g, DefaultClasses)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var classes []string
+	for _, item := range export.Items {
+		classes = append(classes, item.Class+":"+item.Values["object_id"])
+	}
+	if got, want := classes, []string{"cmdb_ci_vm_instance:inst_1", "cmdb_ci_aws_datacenter:eu-west-1", "cmdb_ci_compute_security_group:sg_1", "cmdb_ci_cloud_subnet:sub_1", "cmdb_ci_network:vpc_1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := export.Items[0].Values, map[string]string{
+		"object_id": "inst_1", "name": "web", "state": "running", "operational_status": "1", "ip_address": "10.0.0.12", "short_description": "Name=web;Env=prod", "discovery_source": "awless",
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	var relations []string
+	for _, rel := range export.Relations {
+		relations = append(relations, export.Items[rel.Parent].Values["object_id"]+" "+rel.Type+" "+export.Items[rel.Child].Values["object_id"])
+	}
+	if got, want := relations, []string{
+		"sub_1 Contains::Contained by inst_1",
+		"vpc_1 Contains::Contained by sg_1",
+		"inst_1 Depends on::Used by sg_1",
+		"vpc_1 Contains::Contained by sub_1",
+		"eu-west-1 Contains::Contained by vpc_1",
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var payload struct {
+		Items []struct {
+			ClassName string `json:"className"`
+		} `json:"items"`
+		Relations []map[string]interface{} `json:"relations"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := payload.Items[0].ClassName, "cmdb_ci_vm_instance"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := payload.Relations[0], map[string]interface{}{"parent": float64(3), "child": float64(0), "type": ContainsRelation}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	buf.Reset()
+	if err := export.WriteRelationsCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if got, want := lines[1], "cmdb_ci_cloud_subnet,sub_1,Contains::Contained by,cmdb_ci_vm_instance,inst_1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	buf.Reset()
+	if err := export.WriteItemsCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if got, want := len(lines), 6; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := lines[1], "cmdb_ci_vm_instance,inst_1,web,1,running,10.0.0.12,,,Name=web;Env=prod,awless"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestClassesFromConfig(t *testing.T) {
+	classes := ClassesFromConfig(map[string]interface{}{
+		"cmdb.class.instance": "u_aws_instance",
+		"cmdb.class.region":   "",
+		"cmdb.class.user":     "cmdb_ci_cloud_user",
+		"instance.type":       "t2.micro",
+	})
+	if got, want := classes["instance"], "u_aws_instance"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if _, ok := classes["region"]; ok {
+		t.Fatal("expected region to be removed")
+	}
+	if got, want := classes["user"], "cmdb_ci_cloud_user"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := DefaultClasses["region"], "cmdb_ci_aws_datacenter"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
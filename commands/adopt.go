@@ -0,0 +1,82 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/sync"
+)
+
+func init() {
+	RootCmd.AddCommand(adoptCmd)
+}
+
+var adoptCmd = &cobra.Command{
+	Use:                "adopt {id or alias}",
+	Short:              "Print the create statements of a live resource and of its dependencies, to manage existing infrastructure with templates. Ex: awless adopt sg-0abc >> infra.aws",
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook, initSyncerHook, verifyNewVersionHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("resource id or alias required")
+		}
+		// the template is printed on stdout to be redirected to a file
+		logger.DefaultLogger.SetOutput(os.Stderr)
+
+		id := args[0]
+		if resolved, ok := resolveUserAlias(id); ok {
+			id = resolved
+		}
+		resource, gph := findResourceInLocalGraphs(id)
+		if resource == nil && !localFlag {
+			runFullSync()
+			resource, gph = findResourceInLocalGraphs(id)
+		}
+		if resource == nil {
+			return fmt.Errorf("resource %s not found", id)
+		}
+
+		if !localFlag {
+			srv, err := cloud.GetServiceForType(resource.Type().String())
+			exitOn(err)
+			logger.Verbosef("syncing service %s to adopt live resources", srv.Name())
+			graphs, err := sync.DefaultSyncer.Sync(srv)
+			exitOn(err)
+			if g, ok := graphs[srv.Name()]; ok {
+				live, err := g.GetResource(resource.Type(), resource.Id())
+				exitOn(err)
+				if _, ok := live.Properties["Id"]; !ok {
+					return fmt.Errorf("%s not found live", resource)
+				}
+				resource, gph = live, g
+			}
+		}
+
+		tpl, err := aws.AdoptTemplate(gph, resource)
+		exitOn(err)
+		fmt.Print(tpl)
+		return nil
+	},
+}
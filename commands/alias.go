@@ -0,0 +1,146 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/template"
+)
+
+func init() {
+	RootCmd.AddCommand(aliasCmd)
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasUnsetCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+}
+
+var aliasCmd = &cobra.Command{
+	Use:                "alias",
+	Short:              "Set, unset or list your own aliases for resources ids or values (usable as @alias in templates, ssh, show and one-liners)",
+	PersistentPreRunE:  initAwlessEnvHook,
+	PersistentPostRunE: saveHistoryHook,
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set {alias} {value}",
+	Short: "Set or update an alias. Ex: awless alias set db-prod i-0abc123",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return errors.New("alias name and value required")
+		}
+		name := strings.TrimPrefix(args[0], "@")
+		if name == "" || strings.ContainsAny(name, " \t=") {
+			return fmt.Errorf("invalid alias name '%s'", args[0])
+		}
+
+		db, err, close := database.Current()
+		exitOn(err)
+		defer close()
+		exitOn(db.SetAlias(name, args[1]))
+
+		return nil
+	},
+}
+
+var aliasUnsetCmd = &cobra.Command{
+	Use:   "unset {alias}",
+	Short: "Unset an alias",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("alias name required")
+		}
+
+		db, err, close := database.Current()
+		exitOn(err)
+		defer close()
+		exitOn(db.UnsetAlias(strings.TrimPrefix(args[0], "@")))
+
+		return nil
+	},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List your aliases",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err, close := database.Current()
+		exitOn(err)
+		defer close()
+
+		all, err := db.GetAliases()
+		exitOn(err)
+
+		var names []string
+		for name := range all {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("@%s\t%s\n", name, all[name])
+		}
+		return nil
+	},
+}
+
+// resolveUserAlias returns the value of a user defined alias. The '@' prefix is optional
+func resolveUserAlias(name string) (string, bool) {
+	db, err, close := database.Current()
+	if err != nil {
+		return "", false
+	}
+	defer close()
+	return db.GetAlias(strings.TrimPrefix(name, "@"))
+}
+
+func loadUserAliases() database.Aliases {
+	db, err, close := database.Current()
+	if err != nil {
+		return nil
+	}
+	defer close()
+	aliases, err := db.GetAliases()
+	if err != nil {
+		return nil
+	}
+	return aliases
+}
+
+// resolveUserAliases replaces in the template the aliases found in the
+// given user aliases by their value
+func resolveUserAliases(tpl *template.Template, aliases database.Aliases) {
+	for _, cmd := range tpl.CommandNodesIterator() {
+		for k, v := range cmd.Aliases {
+			if value, ok := aliases[v]; ok {
+				if cmd.Params == nil {
+					cmd.Params = make(map[string]interface{})
+				}
+				cmd.Params[k] = value
+				delete(cmd.Aliases, k)
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/template"
+)
+
+func TestResolveUserAliases(t *testing.T) {
+	tpl, err := template.Parse("create volume id=@my-volume instance=@db-prod\nstart instance id=@web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolveUserAliases(tpl, database.Aliases{"db-prod": "i-0abc123", "web": "i-0def456"})
+
+	cmds := tpl.CommandNodesIterator()
+	if got, want := cmds[0].Params, map[string]interface{}{"instance": "i-0abc123"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+	if got, want := cmds[0].Aliases, map[string]string{"id": "my-volume"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+	if got, want := cmds[1].Params, map[string]interface{}{"id": "i-0def456"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+}
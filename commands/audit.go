@@ -0,0 +1,82 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	gosync "sync"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template"
+)
+
+// checkIPURL returns the public IP of the caller, as seen by AWS
+var checkIPURL = "https://checkip.amazonaws.com"
+
+var (
+	callerOnce gosync.Once
+	caller     *template.Caller
+)
+
+// runCaller identifies who runs templates: the STS caller, its public IP and the awless version.
+// It is recorded with each execution so that shared backends provide an audit trail
+func runCaller() *template.Caller {
+	callerOnce.Do(func() {
+		caller = &template.Caller{Version: config.Version}
+		if aws.SecuAPI != nil {
+			out, err := aws.SecuAPI.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+			if err != nil {
+				logger.Verbosef("cannot resolve caller identity: %s", err)
+			} else {
+				caller.Arn, caller.Account = awssdk.StringValue(out.Arn), awssdk.StringValue(out.Account)
+				accountID = caller.Account
+			}
+		}
+		ip, err := publicIP()
+		if err != nil {
+			logger.Verbosef("cannot resolve public IP: %s", err)
+		}
+		caller.SourceIP = ip
+	})
+	return caller
+}
+
+func publicIP() (string, error) {
+	client := aws.Endpoints.HTTPClient(2 * time.Second)
+	resp, err := client.Get(checkIPURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	ip := strings.TrimSpace(string(body))
+	if net.ParseIP(ip) == nil {
+		return "", fmt.Errorf("invalid IP '%s' from %s", ip, checkIPURL)
+	}
+	return ip, nil
+}
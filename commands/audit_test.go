@@ -0,0 +1,51 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPublicIP(t *testing.T) {
+	defer func(url string) { checkIPURL = url }(checkIPURL)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("1.2.3.4\n"))
+	}))
+	defer ts.Close()
+	checkIPURL = ts.URL
+
+	ip, err := publicIP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ip, "1.2.3.4"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	invalid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>"))
+	}))
+	defer invalid.Close()
+	checkIPURL = invalid.URL
+
+	if _, err := publicIP(); err == nil {
+		t.Fatal("expected error on invalid IP")
+	}
+}
@@ -19,25 +19,44 @@ package commands
 import (
 	"bufio"
 	"bytes"
+	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/sync"
+)
+
+var (
+	completeAsAliasFlag bool
+	completeParamFlag   string
 )
 
 func init() {
 	autocompleteCmd.AddCommand(bashAutocompleteCmd)
 	autocompleteCmd.AddCommand(zshAutocompleteCmd)
+	autocompleteCmd.AddCommand(fishAutocompleteCmd)
+	autocompleteCmd.AddCommand(resourcesAutocompleteCmd)
+
+	resourcesAutocompleteCmd.Flags().BoolVar(&completeAsAliasFlag, "aliases", false, "Prefix resources names with '@'")
+	resourcesAutocompleteCmd.Flags().StringVar(&completeParamFlag, "param", "", "Output candidates as 'param=value'")
 
 	RootCmd.AddCommand(autocompleteCmd)
 }
 
 var autocompleteCmd = &cobra.Command{
 	Use:   "completion",
-	Short: "Output shell completion code for the given shell (bash or zsh)",
+	Short: "Output shell completion code for the given shell (bash, zsh or fish)",
 	Long: `
-Output shell completion code for bash or zsh
+Output shell completion code for bash, zsh or fish
 This command prints shell code which must be evaluated to provide interactive
-completion of awless commands.
+completion of awless commands. Resources ids and names are completed from
+your locally synced resources.
 
 Bash
 	$ source <(awless completion bash)
@@ -53,7 +72,12 @@ completion, e.g. on macOS:
 Zsh
 	$ source <(awless completion zsh)
 	(or, if you want to preserve completion within new terminal sessions)
-	$ echo 'source <(awless completion zsh)\n' >> ~/.zshrc`,
+	$ echo 'source <(awless completion zsh)\n' >> ~/.zshrc
+
+Fish
+	$ awless completion fish | source
+	(or, if you want to preserve completion within new terminal sessions)
+	$ awless completion fish > ~/.config/fish/completions/awless.fish`,
 }
 
 var bashAutocompleteCmd = &cobra.Command{
@@ -77,7 +101,7 @@ completion, e.g. on macOS:
 
 var zshAutocompleteCmd = &cobra.Command{
 	Use:   "zsh",
-	Short: "List users",
+	Short: "Output shell completion code for zsh",
 	Long: `
 Output shell completion code for zsh.
 This command prints shell code which must be evaluated to provide interactive
@@ -89,12 +113,228 @@ zsh completions are only supported in versions of zsh >= 5.2`,
 	RunE: runCompletionZsh,
 }
 
+var fishAutocompleteCmd = &cobra.Command{
+	Use:   "fish",
+	Short: "Output shell completion code for fish",
+	Long: `
+Output shell completion code for fish.
+This command prints shell code which must be evaluated to provide interactive
+completion of awless commands.
+	$ awless completion fish | source
+	(or, if you want to preserve completion within new terminal sessions)
+	$ awless completion fish > ~/.config/fish/completions/awless.fish`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := bufio.NewWriter(os.Stdout)
+		defer out.Flush()
+		return genFishCompletion(RootCmd, out)
+	},
+}
+
+var resourcesAutocompleteCmd = &cobra.Command{
+	Use:    "resources [type...]",
+	Short:  "List ids and names of locally synced resources (all types when none given)",
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		types := args
+		if len(types) == 0 {
+			types = aws.ResourceTypes
+		}
+		for _, t := range types {
+			srvName, ok := aws.ServicePerResourceType[t]
+			if !ok {
+				continue
+			}
+			g := sync.LoadCurrentLocalGraph(srvName)
+			for _, candidate := range completionCandidates(g, graph.ResourceType(t), completeAsAliasFlag) {
+				if completeParamFlag != "" {
+					candidate = completeParamFlag + "=" + candidate
+				}
+				fmt.Println(candidate)
+			}
+		}
+		return nil
+	},
+}
+
+// completionCandidates returns the ids and names of all resources of a given type in the graph.
+// Names containing spaces are skipped as they cannot be completed as a single word
+func completionCandidates(g *graph.Graph, t graph.ResourceType, asAlias bool) []string {
+	resources, err := g.GetAllResources(t)
+	if err != nil {
+		return nil
+	}
+
+	unique := make(map[string]bool)
+	for _, res := range resources {
+		unique[res.Id()] = true
+		if name, ok := res.Properties["Name"]; ok {
+			str := fmt.Sprint(name)
+			if str == "" || str == res.Id() || strings.ContainsAny(str, " \t") {
+				continue
+			}
+			if asAlias {
+				str = "@" + str
+			}
+			unique[str] = true
+		}
+	}
+
+	var candidates []string
+	for c := range unique {
+		candidates = append(candidates, c)
+	}
+	sort.Strings(candidates)
+	return candidates
+}
+
+// completedResourceArgs returns the resource types the arguments of a command complete to.
+// An empty but non nil slice means all resource types
+func completedResourceArgs(cmd *cobra.Command) ([]string, bool) {
+	switch cmd.CommandPath() {
+	case "awless ssh", "awless cp":
+		return []string{graph.Instance.String()}, true
+	case "awless show":
+		return []string{}, true
+	}
+
+	if cmd.HasParent() && IsCmdAnnotatedOneliner(cmd.Parent().Annotations) && cmd.Parent().Name() != "create" {
+		if _, ok := aws.ServicePerResourceType[cmd.Name()]; ok {
+			return []string{cmd.Name()}, true
+		}
+	}
+
+	return nil, false
+}
+
+func walkCommands(cmd *cobra.Command, fn func(*cobra.Command)) {
+	fn(cmd)
+	for _, child := range cmd.Commands() {
+		if child.IsAvailableCommand() {
+			walkCommands(child, fn)
+		}
+	}
+}
+
+func genBashCompletionFunction(root *cobra.Command) string {
+	var buff bytes.Buffer
+	buff.WriteString(`
+__awless_get_resources()
+{
+	local resources_output
+	if resources_output=$(awless completion resources "$@" 2>/dev/null); then
+		COMPREPLY=( $( compgen -W "${resources_output[*]}" -- "$cur" ) )
+		__awless_ltrim_param_completions
+	fi
+}
+__awless_ltrim_param_completions()
+{
+	# readline only replaces what follows the last '=' when it is a word break
+	if [[ "$cur" == *=* && "$COMP_WORDBREAKS" == *=* ]]; then
+		local param_prefix=${cur%"${cur##*=}"}
+		local i=${#COMPREPLY[*]}
+		while [[ $((--i)) -ge 0 ]]; do
+			COMPREPLY[$i]=${COMPREPLY[$i]#"$param_prefix"}
+		done
+	fi
+}
+__awless_get_conf_keys()
+{
+	local all_keys_output
+	if all_keys_output=$(awless config list --keys 2>/dev/null); then
+		COMPREPLY=( $( compgen -W "${all_keys_output[*]}" -- "$cur" ) )
+	fi
+}
+
+__custom_func() {
+	case ${last_command} in
+		awless_config_set | awless_config_get | awless_config_unset )
+			__awless_get_conf_keys
+			return
+			;;
+`)
+	walkCommands(root, func(cmd *cobra.Command) {
+		types, ok := completedResourceArgs(cmd)
+		if !ok {
+			return
+		}
+		lastCommand := strings.Replace(cmd.CommandPath(), " ", "_", -1)
+		var extra string
+		if IsCmdAnnotatedOneliner(cmd.Parent().Annotations) {
+			extra = "--aliases --param id "
+		}
+		call := strings.TrimSpace(fmt.Sprintf("__awless_get_resources %s%s", extra, strings.Join(types, " ")))
+		buff.WriteString(fmt.Sprintf("\t\t%s )\n\t\t\t%s\n\t\t\treturn\n\t\t\t;;\n", lastCommand, call))
+	})
+	buff.WriteString(`		*)
+			;;
+	esac
+}`)
+	return buff.String()
+}
+
 func runCompletionBash(cmd *cobra.Command, args []string) error {
 	out := bufio.NewWriter(os.Stdout)
 	defer out.Flush()
+	RootCmd.BashCompletionFunction = genBashCompletionFunction(RootCmd)
 	return RootCmd.GenBashCompletion(out)
 }
 
+func genFishCompletion(root *cobra.Command, w io.Writer) error {
+	name := root.Name()
+	fmt.Fprintf(w, `function __%[1]s_using_command
+	set -l words
+	for w in (commandline -opc)
+		if not string match -q -- '-*' $w
+			set words $words $w
+		end
+	end
+	test "$words" = "$argv"
+end
+
+complete -c %[1]s -f
+`, name)
+
+	walkCommands(root, func(cmd *cobra.Command) {
+		condition := fmt.Sprintf("__%s_using_command %s", name, cmd.CommandPath())
+		for _, child := range cmd.Commands() {
+			if child.IsAvailableCommand() {
+				fmt.Fprintf(w, "complete -c %s -n '%s' -a '%s' -d '%s'\n", name, condition, child.Name(), fishEscape(child.Short))
+			}
+		}
+		cmd.NonInheritedFlags().VisitAll(func(f *pflag.Flag) {
+			if f.Hidden {
+				return
+			}
+			var short string
+			if f.Shorthand != "" {
+				short = " -s " + f.Shorthand
+			}
+			fmt.Fprintf(w, "complete -c %s -n '%s' -l %s%s -d '%s'\n", name, condition, f.Name, short, fishEscape(f.Usage))
+		})
+		if types, ok := completedResourceArgs(cmd); ok {
+			args := strings.Join(types, " ")
+			if IsCmdAnnotatedOneliner(cmd.Parent().Annotations) {
+				args = "--aliases --param id " + args
+			}
+			fmt.Fprintf(w, "complete -c %s -n '%s' -a '(%s completion resources %s 2>/dev/null)'\n", name, condition, name, strings.TrimSpace(args))
+		}
+	})
+
+	root.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		var short string
+		if f.Shorthand != "" {
+			short = " -s " + f.Shorthand
+		}
+		fmt.Fprintf(w, "complete -c %s -l %s%s -d '%s'\n", name, f.Name, short, fishEscape(f.Usage))
+	})
+
+	return nil
+}
+
+func fishEscape(s string) string {
+	return strings.Replace(s, "'", "\\'", -1)
+}
+
 // Copyright 2016 The Kubernetes Authors.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -245,6 +485,7 @@ __awless_convert_bash_to_zsh() {
 	out.Write([]byte(zshInitialization))
 
 	buf := new(bytes.Buffer)
+	RootCmd.BashCompletionFunction = genBashCompletionFunction(RootCmd)
 	RootCmd.GenBashCompletion(buf)
 	out.Write(buf.Bytes())
 
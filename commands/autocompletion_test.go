@@ -0,0 +1,76 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wallix/awless/graph"
+)
+
+func TestCompletionCandidates(t *testing.T) {
+	g, err := graph.NewGraphFromFile(filepath.Join("testdata", "infra.rdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := completionCandidates(g, graph.Instance, false), []string{"inst_1", "inst_2", "inst_3", "instance1-name"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := completionCandidates(g, graph.Instance, true), []string{"@instance1-name", "inst_1", "inst_2", "inst_3"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got := completionCandidates(g, graph.Bucket, false); len(got) != 0 {
+		t.Fatalf("got %v, want none", got)
+	}
+}
+
+func TestGenerateCompletions(t *testing.T) {
+	bash := genBashCompletionFunction(RootCmd)
+	for _, expected := range []string{
+		"awless_ssh )\n\t\t\t__awless_get_resources instance\n",
+		"awless_show )\n\t\t\t__awless_get_resources\n",
+		"awless_delete_instance )\n\t\t\t__awless_get_resources --aliases --param id instance\n",
+		"awless_stop_instance )\n\t\t\t__awless_get_resources --aliases --param id instance\n",
+	} {
+		if !strings.Contains(bash, expected) {
+			t.Fatalf("expected bash completion to contain %q", expected)
+		}
+	}
+	if strings.Contains(bash, "awless_create_instance") {
+		t.Fatal("unexpected resource completion for creation")
+	}
+
+	var fish bytes.Buffer
+	if err := genFishCompletion(RootCmd, &fish); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"complete -c awless -n '__awless_using_command awless' -a 'ssh' -d 'Launch a SSH (Secure Shell) session connecting to an instance given an id or alias'\n",
+		"complete -c awless -n '__awless_using_command awless ssh' -a '(awless completion resources instance 2>/dev/null)'\n",
+		"complete -c awless -n '__awless_using_command awless delete bucket' -a '(awless completion resources --aliases --param id bucket 2>/dev/null)'\n",
+		"complete -c awless -l verbose -s v -d 'Turn on verbose mode for all commands'\n",
+	} {
+		if !strings.Contains(fish.String(), expected) {
+			t.Fatalf("expected fish completion to contain %q", expected)
+		}
+	}
+}
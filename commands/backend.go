@@ -0,0 +1,159 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/backend"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/sync"
+	"github.com/wallix/awless/template"
+)
+
+const maxBackendExecutions = 100
+
+func init() {
+	RootCmd.AddCommand(backendCmd)
+	backendCmd.AddCommand(backendPullCmd)
+}
+
+var backendCmd = &cobra.Command{
+	Use:   "backend",
+	Short: fmt.Sprintf("Share synced graphs and template executions in a S3 bucket (ex: awless config set %s my-team-bucket)", backend.BucketKey),
+}
+
+var backendPullCmd = &cobra.Command{
+	Use:                "pull",
+	Short:              "Replace the local graphs of the current region with the last ones synced in the backend by your team",
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initConfigStruct, initSyncerHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b := backend.FromConfig(config.Config.Defaults)
+		if b == nil {
+			return fmt.Errorf("no backend configured: set %s", backend.BucketKey)
+		}
+		region := configRegion()
+		var filenames []string
+		for _, name := range aws.ServiceNames {
+			g, err := b.GetGraph(region, name)
+			if err != nil {
+				logger.Verbosef("backend: %s", err)
+				continue
+			}
+			exitOn(sync.SaveLocalGraph(name, g))
+			filenames = append(filenames, name+".rdf")
+			logger.Infof("pulled %s graph from %s", name, b)
+		}
+		if len(filenames) == 0 {
+			return fmt.Errorf("no graph found in %s for region %s", b, region)
+		}
+		exitOn(sync.DefaultSyncer.Commit(filenames...))
+		return nil
+	},
+}
+
+// backendSyncer uploads the synced graphs to the backend
+type backendSyncer struct {
+	sync.Syncer
+	backend *backend.S3
+	region  string
+}
+
+func (s *backendSyncer) Sync(services ...cloud.Service) (map[string]*graph.Graph, error) {
+	graphs, err := s.Syncer.Sync(services...)
+	if len(graphs) > 0 {
+		if perr := s.backend.PutGraphs(s.region, graphs, time.Now()); perr != nil {
+			logger.Warningf("backend: cannot upload synced graphs: %s", perr)
+		} else {
+			logger.Verbosef("uploaded synced graphs to %s", s.backend)
+		}
+	}
+	return graphs, err
+}
+
+func configuredBackend() *backend.S3 {
+	if config.Config == nil {
+		if err := config.LoadConfig(); err != nil {
+			return nil
+		}
+	}
+	return backend.FromConfig(config.Config.Defaults)
+}
+
+// uploadExecution shares the template execution in the backend, if any
+func uploadExecution(executed *template.TemplateExecution) {
+	if b := configuredBackend(); b != nil {
+		if err := b.PutExecution(executed); err != nil {
+			logger.Warningf("backend: cannot upload execution %s: %s", executed.ID, err)
+		}
+	}
+}
+
+// loadExecution returns the template execution from the local database or else from the backend
+func loadExecution(id string) (*template.TemplateExecution, error) {
+	db, err, dbclose := database.Current()
+	if err != nil {
+		return nil, err
+	}
+	executed, err := db.GetTemplateExecution(id)
+	dbclose()
+	if err == nil {
+		return executed, nil
+	}
+	if b := configuredBackend(); b != nil && !localFlag {
+		if remote, rerr := b.GetExecution(id); rerr == nil {
+			return remote, nil
+		} else {
+			logger.Verbosef("backend: %s", rerr)
+		}
+	}
+	return nil, err
+}
+
+// listExecutions merges the local template executions with the last ones shared in the backend
+func listExecutions(local []*template.TemplateExecution) []*template.TemplateExecution {
+	b := configuredBackend()
+	if b == nil || localFlag {
+		return local
+	}
+	remote, err := b.ListExecutions(maxBackendExecutions)
+	if err != nil {
+		logger.Warningf("backend: cannot list executions: %s", err)
+	}
+	seen := make(map[string]bool)
+	for _, executed := range local {
+		seen[executed.ID] = true
+	}
+	all := local
+	for _, executed := range remote {
+		if !seen[executed.ID] {
+			all = append(all, executed)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	return all
+}
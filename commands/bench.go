@@ -0,0 +1,266 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template"
+	"github.com/wallix/awless/tracing"
+)
+
+var (
+	benchFormatFlag     string
+	benchIterationsFlag int
+)
+
+func init() {
+	RootCmd.AddCommand(benchCmd)
+	benchCmd.AddCommand(benchSyncCmd)
+	benchCmd.AddCommand(benchParseCmd)
+
+	benchCmd.PersistentFlags().StringVar(&benchFormatFlag, "format", "table", "Format of the results: table or json (to compare releases)")
+	benchParseCmd.Flags().IntVar(&benchIterationsFlag, "iterations", 1000, "Number of parses of each template")
+}
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure the performance of sync and template parsing. Combine with --profile-cpu and --profile-mem for pprof files",
+}
+
+var benchSyncCmd = &cobra.Command{
+	Use:                "sync [service ...]",
+	Short:              "Time the fetch of each resource type with its API calls, without updating the local graphs",
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var names []string
+		for name := range cloud.ServiceRegistry {
+			if len(args) == 0 || containsString(args, name) {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("no service to bench among %v", args)
+		}
+		sort.Strings(names)
+
+		report := newBenchReport("sync")
+		for _, name := range names {
+			srv := cloud.ServiceRegistry[name]
+			for _, resType := range srv.ResourceTypes() {
+				report.Results = append(report.Results, benchFetch(srv, resType))
+			}
+		}
+		report.APICalls = apiCallsBreakdown(tracing.DefaultTracer.Spans())
+		return report.print()
+	},
+}
+
+var benchParseCmd = &cobra.Command{
+	Use:                "parse [template filepath ...]",
+	Short:              "Time the parsing of templates and count its allocations (sample templates by default)",
+	PersistentPreRunE:  initAwlessEnvHook,
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if benchIterationsFlag < 1 {
+			return fmt.Errorf("invalid iterations %d", benchIterationsFlag)
+		}
+		templates := make(map[string]string)
+		for _, path := range args {
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			templates[path] = string(content)
+		}
+		if len(templates) == 0 {
+			templates = benchSampleTemplates
+		}
+		var names []string
+		for name := range templates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		report := newBenchReport("parse")
+		for _, name := range names {
+			if _, err := template.Parse(templates[name]); err != nil {
+				return fmt.Errorf("%s: %s", name, err)
+			}
+			report.Results = append(report.Results, benchParse(name, templates[name], benchIterationsFlag))
+		}
+		return report.print()
+	},
+}
+
+var benchSampleTemplates = map[string]string{
+	"one-liner": "create instance subnet=@my-subnet image=ami-12345678 type=t2.micro count=1 keypair=my-key name=my-instance",
+	"multiline": `vpc = create vpc cidr=10.0.0.0/16
+subnet = create subnet cidr=10.0.1.0/24 vpc=$vpc availabilityzone=eu-west-1a
+update subnet id=$subnet public=true
+gateway = create internetgateway
+attach internetgateway id=$gateway vpc=$vpc
+rtable = create routetable vpc=$vpc
+attach routetable id=$rtable subnet=$subnet
+create route cidr=0.0.0.0/0 gateway=$gateway table=$rtable
+# the instance
+inst = create instance subnet=$subnet image={instance.image} type=t2.micro count=1 name={instance.name}
+create tag resource=$inst key=Env value=test`,
+}
+
+// benchResult is the measure of a fetcher or a template. Durations are in nanoseconds
+type benchResult struct {
+	Name        string        `json:"name"`
+	Duration    time.Duration `json:"duration"`
+	Count       int           `json:"count"`
+	Calls       int           `json:"apiCalls,omitempty"`
+	AllocsPerOp uint64        `json:"allocsPerOp,omitempty"`
+	BytesPerOp  uint64        `json:"bytesPerOp,omitempty"`
+	Err         string        `json:"error,omitempty"`
+}
+
+type benchReport struct {
+	Benchmark string         `json:"benchmark"`
+	Version   string         `json:"version"`
+	Date      time.Time      `json:"date"`
+	GoVersion string         `json:"goVersion"`
+	Results   []*benchResult `json:"results"`
+	APICalls  []*benchResult `json:"apiCalls,omitempty"`
+}
+
+func newBenchReport(name string) *benchReport {
+	return &benchReport{Benchmark: name, Version: config.Version, Date: time.Now().UTC(), GoVersion: runtime.Version()}
+}
+
+// benchFetch fetches a resource type as the active span, parent of its API calls
+func benchFetch(srv cloud.Service, resType string) *benchResult {
+	res := &benchResult{Name: fmt.Sprintf("%s/%s", srv.Name(), resType)}
+	span, finish := tracing.StartActive("bench fetch " + res.Name)
+	start := time.Now()
+	g, err := srv.FetchByType(resType)
+	res.Duration = time.Since(start)
+	finish(err)
+	if err != nil {
+		res.Err = err.Error()
+	} else if resources, err := g.GetAllResources(graph.ResourceType(resType)); err == nil {
+		res.Count = len(resources)
+	}
+	for _, s := range tracing.DefaultTracer.Spans() {
+		if s.ParentID == span.SpanID && s.Kind == tracing.KindClient {
+			res.Calls++
+		}
+	}
+	return res
+}
+
+// benchParse parses a template the given times, measuring the mean duration and allocations
+func benchParse(name, text string, iterations int) *benchResult {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for i := 0; i < iterations; i++ {
+		template.Parse(text)
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return &benchResult{
+		Name:        name,
+		Duration:    elapsed / time.Duration(iterations),
+		Count:       iterations,
+		AllocsPerOp: (after.Mallocs - before.Mallocs) / uint64(iterations),
+		BytesPerOp:  (after.TotalAlloc - before.TotalAlloc) / uint64(iterations),
+	}
+}
+
+// apiCallsBreakdown sums the calls and durations of each API operation, the longest first
+func apiCallsBreakdown(spans []*tracing.Span) (breakdown []*benchResult) {
+	perOperation := make(map[string]*benchResult)
+	for _, s := range spans {
+		if s.Kind != tracing.KindClient {
+			continue
+		}
+		op, ok := perOperation[s.Name]
+		if !ok {
+			op = &benchResult{Name: s.Name}
+			perOperation[s.Name] = op
+			breakdown = append(breakdown, op)
+		}
+		op.Calls++
+		op.Duration += s.End.Sub(s.Start)
+		if s.Err != "" {
+			op.Err = s.Err
+		}
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Duration != breakdown[j].Duration {
+			return breakdown[i].Duration > breakdown[j].Duration
+		}
+		return breakdown[i].Name < breakdown[j].Name
+	})
+	return
+}
+
+func (r *benchReport) print() error {
+	switch benchFormatFlag {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case "table":
+	default:
+		return fmt.Errorf("unknown format '%s': expecting table or json", benchFormatFlag)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	switch r.Benchmark {
+	case "parse":
+		fmt.Fprintln(w, "TEMPLATE\tTIME/OP\tALLOCS/OP\tBYTES/OP")
+		for _, res := range r.Results {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", res.Name, res.Duration, res.AllocsPerOp, res.BytesPerOp)
+		}
+	default:
+		fmt.Fprintln(w, "FETCHER\tTIME\tRESOURCES\tAPI CALLS\tERROR")
+		var total time.Duration
+		for _, res := range r.Results {
+			total += res.Duration
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", res.Name, res.Duration.Round(time.Millisecond), res.Count, res.Calls, res.Err)
+		}
+		fmt.Fprintf(w, "total\t%s\t\t\t\n", total.Round(time.Millisecond))
+		if len(r.APICalls) > 0 {
+			fmt.Fprintln(w, "\nAPI OPERATION\tTIME\tCALLS\t\t")
+			for _, op := range r.APICalls {
+				fmt.Fprintf(w, "%s\t%s\t%d\t\t\n", op.Name, op.Duration.Round(time.Millisecond), op.Calls)
+			}
+		}
+	}
+	return w.Flush()
+}
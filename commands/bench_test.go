@@ -0,0 +1,51 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/tracing"
+)
+
+func TestAPICallsBreakdown(t *testing.T) {
+	now := time.Now()
+	spans := []*tracing.Span{
+		{Name: "ec2.DescribeInstances", Kind: tracing.KindClient, Start: now, End: now.Add(100 * time.Millisecond)},
+		{Name: "bench fetch infra/instance", Kind: tracing.KindInternal, Start: now, End: now.Add(time.Second)},
+		{Name: "ec2.DescribeVpcs", Kind: tracing.KindClient, Start: now, End: now.Add(300 * time.Millisecond)},
+		{Name: "ec2.DescribeInstances", Kind: tracing.KindClient, Start: now, End: now.Add(150 * time.Millisecond), Err: "Throttling"},
+	}
+
+	breakdown := apiCallsBreakdown(spans)
+	if got, want := len(breakdown), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := breakdown[0].Name, "ec2.DescribeVpcs"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := breakdown[1].Calls, 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := breakdown[1].Duration, 250*time.Millisecond; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := breakdown[1].Err, "Throttling"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	awscloud "github.com/wallix/awless/aws"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template"
+)
+
+// createBulkDeleteCommand returns the command deleting all the resources of the definition entity
+// matching filters (ex: awless delete instances --filter tag:env==sandbox), or nil if the entity
+// cannot be deleted in bulk
+func createBulkDeleteCommand(def template.TemplateDefinition) *cobra.Command {
+	if !isBulkDeletable(def) {
+		return nil
+	}
+
+	var filters []string
+	var olderThan time.Duration
+
+	cmd := &cobra.Command{
+		Use:                cloud.PluralizeResource(def.Entity),
+		PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initConfigStruct, initCloudServicesHook, initSyncerHook, verifyNewVersionHook),
+		PersistentPostRunE: saveHistoryHook,
+		Short:              fmt.Sprintf("Delete all %s matching filters through a generated template shown for confirmation", cloud.PluralizeResource(def.Entity)),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(filters) == 0 && olderThan == 0 {
+				return errors.New("at least one --filter or --older-than is required to delete resources in bulk")
+			}
+			fns, err := parseBulkFilters(filters)
+			exitOn(err)
+			if olderThan > 0 {
+				fns = append(fns, graph.BuildOlderThanFilterFunc(olderThan, time.Now()))
+			}
+
+			srv, err := cloud.GetServiceForType(def.Entity)
+			exitOn(err)
+			g, err := srv.FetchByType(def.Entity)
+			exitOn(err)
+
+			text, count, err := bulkDeleteTemplate(g, def, fns...)
+			exitOn(err)
+			if count == 0 {
+				logger.Infof("no %s matching", def.Entity)
+				return nil
+			}
+
+			templ, err := template.Parse(text)
+			exitOn(err)
+			exitOn(runTemplate(templ))
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&filters, "filter", []string{}, "Filter resources on properties (ex: --filter state=stopped) or exact tags (ex: --filter 'tag:env==sandbox')")
+	cmd.Flags().DurationVar(&olderThan, "older-than", 0, "Only delete resources created before this duration (ex: 720h)")
+
+	return cmd
+}
+
+func isBulkDeletable(def template.TemplateDefinition) bool {
+	if len(def.Required()) != 1 {
+		return false
+	}
+	for _, t := range awscloud.ResourceTypes {
+		if t == def.Entity {
+			return true
+		}
+	}
+	return false
+}
+
+// bulkDeleteProperty returns the resource property holding the value of the delete required param
+func bulkDeleteProperty(entity, param string) string {
+	switch {
+	case param == "name":
+		return "Name"
+	case entity == "subscription":
+		return "SubscriptionArn"
+	default:
+		return "Id"
+	}
+}
+
+func parseBulkFilters(filters []string) ([]graph.FilterFn, error) {
+	var fns []graph.FilterFn
+	for _, f := range filters {
+		if strings.HasPrefix(f, "tag:") {
+			splits := strings.SplitN(strings.TrimPrefix(f, "tag:"), "=", 2)
+			if len(splits) != 2 || splits[0] == "" {
+				return fns, fmt.Errorf("invalid tag filter '%s', expecting tag:key==value", f)
+			}
+			fns = append(fns, graph.BuildTagFilterFunc(splits[0], strings.TrimPrefix(splits[1], "=")))
+			continue
+		}
+		splits := strings.SplitN(f, "=", 2)
+		if len(splits) != 2 || strings.TrimSpace(splits[0]) == "" {
+			return fns, fmt.Errorf("invalid filter '%s', expecting key=value", f)
+		}
+		key, val := strings.Title(strings.TrimSpace(splits[0])), strings.TrimSpace(splits[1])
+		if strings.HasPrefix(val, "=") {
+			exact := strings.TrimPrefix(val, "=")
+			fns = append(fns, func(r *graph.Resource) bool { return fmt.Sprint(r.Properties[key]) == exact })
+		} else {
+			fns = append(fns, graph.BuildPropertyFilterFunc(key, val))
+		}
+	}
+	return fns, nil
+}
+
+func bulkDeleteTemplate(g *graph.Graph, def template.TemplateDefinition, filters ...graph.FilterFn) (string, int, error) {
+	filtered, err := g.Filter(graph.ResourceType(def.Entity), filters...)
+	if err != nil {
+		return "", 0, err
+	}
+	resources, err := filtered.GetAllResources(graph.ResourceType(def.Entity))
+	if err != nil {
+		return "", 0, err
+	}
+	sort.Sort(graph.ResourceById(resources))
+
+	param := def.Required()[0]
+	prop := bulkDeleteProperty(def.Entity, param)
+	var buff bytes.Buffer
+	var count int
+	for _, res := range resources {
+		val, ok := res.Properties[prop]
+		if !ok || fmt.Sprint(val) == "" {
+			logger.Verbosef("skipping %s: no %s", res, strings.ToLower(prop))
+			continue
+		}
+		fmt.Fprintf(&buff, "%s %s %s=%v\n", def.Action, def.Entity, param, val)
+		count++
+	}
+	return buff.String(), count, nil
+}
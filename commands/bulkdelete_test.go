@@ -0,0 +1,83 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/aws/driver"
+	"github.com/wallix/awless/graph"
+)
+
+func TestBulkDeleteTemplate(t *testing.T) {
+	now := time.Now()
+	g := graph.NewGraph()
+	inst1 := graph.InitResource("inst_1", graph.Instance)
+	inst1.Properties["Id"] = "inst_1"
+	inst1.Properties["State"] = "running"
+	inst1.Properties["Tags"] = []interface{}{"env=sandbox"}
+	inst1.Properties["LaunchTime"] = now.Add(-800 * time.Hour)
+	inst2 := graph.InitResource("inst_2", graph.Instance)
+	inst2.Properties["Id"] = "inst_2"
+	inst2.Properties["State"] = "stopped"
+	inst2.Properties["Tags"] = []interface{}{"env=sandbox"}
+	inst2.Properties["LaunchTime"] = now.Add(-1 * time.Hour)
+	inst3 := graph.InitResource("inst_3", graph.Instance)
+	inst3.Properties["Id"] = "inst_3"
+	inst3.Properties["State"] = "stopped"
+	inst3.Properties["Tags"] = []interface{}{"env=prod"}
+	inst3.Properties["LaunchTime"] = now.Add(-800 * time.Hour)
+	g.AddResource(inst1, inst2, inst3)
+
+	def := aws.AWSTemplatesDefinitions["deleteinstance"]
+
+	tcases := []struct {
+		filters   []string
+		olderThan time.Duration
+		expect    string
+	}{
+		{filters: []string{"tag:env==sandbox"}, expect: "delete instance id=inst_1\ndelete instance id=inst_2\n"},
+		{filters: []string{"tag:env==sandbox"}, olderThan: 720 * time.Hour, expect: "delete instance id=inst_1\n"},
+		{filters: []string{"state=stop"}, expect: "delete instance id=inst_2\ndelete instance id=inst_3\n"},
+		{filters: []string{"state==stop"}, expect: ""},
+		{filters: []string{"tag:env==staging"}, expect: ""},
+	}
+	for i, tcase := range tcases {
+		fns, err := parseBulkFilters(tcase.filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tcase.olderThan > 0 {
+			fns = append(fns, graph.BuildOlderThanFilterFunc(tcase.olderThan, now))
+		}
+		text, _, err := bulkDeleteTemplate(g, def, fns...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := text, tcase.expect; got != want {
+			t.Fatalf("%d: got %q, want %q", i+1, got, want)
+		}
+	}
+
+	if _, err := parseBulkFilters([]string{"tag:=sandbox"}); err == nil {
+		t.Fatal("expected error for invalid tag filter")
+	}
+	if isBulkDeletable(aws.AWSTemplatesDefinitions["deletestorageobject"]) {
+		t.Fatal("storage objects should not be bulk deletable")
+	}
+}
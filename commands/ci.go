@@ -0,0 +1,144 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/wallix/awless/template"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Exit codes of template runs, distinguishing failures for CI pipelines
+const (
+	exitFailure        = 1
+	exitPlanDiff       = 2
+	exitPartialFailure = 3
+)
+
+type ciStatement struct {
+	Line   string `json:"line"`
+	Result string `json:"result,omitempty"`
+	Err    string `json:"error,omitempty"`
+}
+
+type ciReport struct {
+	ID         string        `json:"id,omitempty"`
+	Status     string        `json:"status"`
+	Revertible bool          `json:"revertible,omitempty"`
+	Err        string        `json:"error,omitempty"`
+	Statements []ciStatement `json:"statements"`
+}
+
+// runExitCode returns 0 on success, exitPartialFailure when some statements succeeded before a failure
+// and exitFailure otherwise
+func runExitCode(executed *template.TemplateExecution, runErr error) int {
+	if runErr == nil && !executed.HasErrors() {
+		return 0
+	}
+	for _, st := range executed.Executed {
+		if st.Err == "" && st.Result != "" {
+			return exitPartialFailure
+		}
+	}
+	return exitFailure
+}
+
+func newCIReport(executed *template.TemplateExecution, runErr error) *ciReport {
+	report := &ciReport{ID: executed.ID, Revertible: executed.IsRevertible()}
+	switch runExitCode(executed, runErr) {
+	case 0:
+		report.Status = "succeeded"
+	case exitPartialFailure:
+		report.Status = "partial_failure"
+	default:
+		report.Status = "failed"
+	}
+	if runErr != nil {
+		report.Err = runErr.Error()
+	}
+	for _, st := range executed.Executed {
+		report.Statements = append(report.Statements, ciStatement{Line: st.Line, Result: st.Result, Err: st.Err})
+	}
+	return report
+}
+
+func newCIPlanReport(templ *template.Template) *ciReport {
+	report := &ciReport{Status: "plan", Statements: []ciStatement{}}
+	for _, cmd := range templ.CommandNodesIterator() {
+		report.Statements = append(report.Statements, ciStatement{Line: cmd.String()})
+	}
+	return report
+}
+
+func printCIReport(report *ciReport) {
+	b, err := json.MarshalIndent(report, "", "  ")
+	exitOn(err)
+	fmt.Println(string(b))
+}
+
+// templateParams collects the values of template holes given as key=value args
+// and in a YAML or JSON params file (possibly encrypted), args taking precedence
+func templateParams(args []string, paramsFile string) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	if paramsFile != "" {
+		content, err := ioutil.ReadFile(paramsFile)
+		if err != nil {
+			return params, err
+		}
+		if content, err = decryptParamsFile(paramsFile, content); err != nil {
+			return params, fmt.Errorf("params file %s: %s", paramsFile, err)
+		}
+		fromFile := make(map[string]interface{})
+		if err := yaml.Unmarshal(content, &fromFile); err != nil {
+			return params, fmt.Errorf("params file %s: %s", paramsFile, err)
+		}
+		for k, v := range fromFile {
+			params[k] = v
+		}
+	}
+	for _, arg := range args {
+		splits := strings.SplitN(arg, "=", 2)
+		if len(splits) != 2 || strings.TrimSpace(splits[0]) == "" {
+			return params, fmt.Errorf("invalid param '%s': expecting key=value", arg)
+		}
+		params[strings.TrimSpace(splits[0])] = splits[1]
+	}
+	return params, nil
+}
+
+// checkNoHoles fails in CI mode as holes cannot be asked interactively
+func checkNoHoles(templ *template.Template) error {
+	holes := templ.GetHolesValuesSet()
+	if len(holes) == 0 {
+		return nil
+	}
+	sort.Strings(holes)
+	return fmt.Errorf("CI mode: missing values for %s. Give them as key=value arguments or in a --params-file", strings.Join(holes, ", "))
+}
+
+func exitCI(code int) {
+	if code != 0 {
+		exportTraces(fmt.Errorf("exit code %d", code))
+		os.Exit(code)
+	}
+}
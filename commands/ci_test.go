@@ -0,0 +1,155 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wallix/awless/template"
+)
+
+func TestRunExitCode(t *testing.T) {
+	tcases := []struct {
+		executed []*template.ExecutedStatement
+		runErr   error
+		exp      int
+	}{
+		{[]*template.ExecutedStatement{{Line: "create vpc", Result: "vpc-1"}}, nil, 0},
+		{[]*template.ExecutedStatement{{Line: "create vpc", Err: "denied"}}, errors.New("denied"), exitFailure},
+		{[]*template.ExecutedStatement{{Line: "create vpc", Result: "vpc-1"}, {Line: "create subnet", Err: "denied"}, {Line: "create instance"}}, errors.New("denied"), exitPartialFailure},
+		{[]*template.ExecutedStatement{}, errors.New("driver not found"), exitFailure},
+	}
+	for i, tcase := range tcases {
+		executed := &template.TemplateExecution{ID: "01B", Executed: tcase.executed}
+		if got, want := runExitCode(executed, tcase.runErr), tcase.exp; got != want {
+			t.Fatalf("%d: got %d, want %d", i, got, want)
+		}
+	}
+
+	report := newCIReport(&template.TemplateExecution{ID: "01B", Executed: tcases[2].executed}, tcases[2].runErr)
+	if got, want := report.Status, "partial_failure"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := len(report.Statements), 3; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
+
+func TestTemplateParams(t *testing.T) {
+	f, err := ioutil.TempFile("", "awless-params")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("instance.name: web\ninstance.count: 2\n")
+	f.Close()
+
+	params, err := templateParams([]string{"instance.name=api", "instance.userdata=a=b"}, f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{"instance.name": "api", "instance.count": 2, "instance.userdata": "a=b"}
+	if got, want := params, exp; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	if _, err := templateParams([]string{"instance.name"}, ""); err == nil {
+		t.Fatal("expected error got none")
+	}
+	if _, err := templateParams(nil, "/nonexistent/params.yml"); err == nil {
+		t.Fatal("expected error got none")
+	}
+}
+
+func TestEncryptedParamsFile(t *testing.T) {
+	defer func(fn func([]byte) ([]byte, error)) { kmsDecrypt = fn }(kmsDecrypt)
+	// KMS ciphertext blobs are binary, starting with version bytes
+	blob := "\x01\x02\x02\x00ciphered"
+	kmsDecrypt = func(b []byte) ([]byte, error) {
+		if string(b) != blob {
+			return nil, fmt.Errorf("invalid ciphertext %q", b)
+		}
+		return []byte("db.password: secret\n"), nil
+	}
+
+	dir, err := ioutil.TempDir("", "awless-params")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, content := range []string{blob, base64.StdEncoding.EncodeToString([]byte(blob)) + "\n"} {
+		path := filepath.Join(dir, "prod.yaml.encrypted")
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		params, err := templateParams(nil, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := params, map[string]interface{}{"db.password": "secret"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %#v, want %#v", got, want)
+		}
+	}
+
+	plain, err := decryptParamsFile("prod.yaml", []byte("ciphered"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(plain), "ciphered"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	defer os.Setenv(ageIdentityEnv, os.Getenv(ageIdentityEnv))
+	os.Setenv(ageIdentityEnv, "")
+	if _, err := decryptParamsFile("prod.yaml", []byte("age-encryption.org/v1\n")); err == nil {
+		t.Fatal("expected error got none")
+	}
+}
+
+func TestCheckNoHolesAndPlan(t *testing.T) {
+	templ, err := template.Parse("create vpc cidr={vpc.cidr}\ncreate subnet cidr={subnet.cidr} vpc=vpc-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = checkNoHoles(templ)
+	if err == nil {
+		t.Fatal("expected error got none")
+	}
+	if got, want := err.Error(), "CI mode: missing values for subnet.cidr, vpc.cidr. Give them as key=value arguments or in a --params-file"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	templ.ResolveHoles(map[string]interface{}{"vpc.cidr": "10.0.0.0/16", "subnet.cidr": "10.0.1.0/24"})
+	if err := checkNoHoles(templ); err != nil {
+		t.Fatal(err)
+	}
+	report := newCIPlanReport(templ)
+	if got, want := report.Status, "plan"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := report.Statements[0].Line, "create vpc cidr=10.0.0.0/16"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
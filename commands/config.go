@@ -18,12 +18,20 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/config"
 	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/lock"
+	"github.com/wallix/awless/notify"
+	"github.com/wallix/awless/statecrypt"
+	"github.com/wallix/awless/sync"
 )
 
 var keysOnly bool
@@ -38,8 +46,13 @@ func init() {
 }
 
 var configCmd = &cobra.Command{
-	Use:                "config",
-	Short:              "get, set, unset or list configuration values",
+	Use:   "config",
+	Short: "get, set, unset or list configuration values",
+	Long: `Get, set, unset or list configuration values.
+
+Any config key can be overridden with an AWLESS_ environment variable: the key in upper case with dots and dashes replaced by underscores (ex: AWLESS_REGION, AWLESS_AWS_PROFILE, AWLESS_SYNC_AUTO, AWLESS_COLOR).
+
+Values are resolved with this precedence, from the highest: command flags, environment variables, project config (.awless.yml), current context, awless config.`,
 	PersistentPreRunE:  initAwlessEnvHook,
 	PersistentPostRunE: saveHistoryHook,
 }
@@ -61,6 +74,23 @@ var configListCmd = &cobra.Command{
 				fmt.Printf("%s: %v\t(%[2]T)\n", k, v)
 			}
 		}
+		if p := config.Project; p != nil && !keysOnly {
+			overrides := make(map[string]interface{})
+			p.ApplyTo(overrides)
+			fmt.Printf("\nOverridden by project config %s:\n", p.Path)
+			for k, v := range overrides {
+				fmt.Printf("%s: %v\t(%[2]T)\n", k, v)
+			}
+			if len(p.RequiredTags) > 0 {
+				fmt.Printf("required tags: %s\n", strings.Join(p.RequiredTags, ", "))
+			}
+		}
+		if overrides := config.EnvOverrides(os.Environ(), d); len(overrides) > 0 && !keysOnly {
+			fmt.Println("\nOverridden by environment variables:")
+			for k, v := range overrides {
+				fmt.Printf("%s=%v\t(%s)\n", config.EnvName(k), v, k)
+			}
+		}
 	},
 }
 
@@ -96,6 +126,9 @@ var configSetCmd = &cobra.Command{
 		key := strings.TrimSpace(args[0])
 		var value string
 		if len(args) == 1 {
+			if ciFlag {
+				return fmt.Errorf("CI mode: missing value for %s", key)
+			}
 			switch key {
 			case "region":
 				value = askRegion()
@@ -105,37 +138,138 @@ var configSetCmd = &cobra.Command{
 			}
 		} else {
 			value = args[1]
-			switch key {
-			case "region":
-				if !aws.IsValidRegion(value) {
-					fmt.Println("Invalid region!")
-					value = askRegion()
+			if key == statecrypt.ModeKey {
+				return fmt.Errorf("use `awless state encrypt %s` to also encrypt the existing local state", value)
+			}
+			var err error
+			if value, err = validateConfigValue(key, value); err != nil {
+				if key != database.RegionKey || ciFlag {
+					return err
 				}
+				fmt.Println("Invalid region!")
+				value = askRegion()
 			}
 		}
 		if value == "" {
 			return fmt.Errorf("invalid empty value")
 		}
 
-		var i interface{}
-
-		if num, nerr := strconv.Atoi(value); nerr == nil {
-			i = num
-		} else if b, berr := strconv.ParseBool(value); berr == nil {
-			i = b
-		} else {
-			i = value
-		}
-
 		db, err, close := database.Current()
 		exitOn(err)
 		defer close()
-		exitOn(db.SetDefault(key, i))
+		exitOn(db.SetDefault(key, config.ParseValue(value)))
 
 		return nil
 	},
 }
 
+// validateConfigValue checks the value of a config key, returning it normalized (ex: absolute paths)
+func validateConfigValue(key, value string) (string, error) {
+	switch {
+	case key == database.RegionKey:
+		if !aws.IsValidRegion(value) {
+			return value, fmt.Errorf("invalid region '%s'", value)
+		}
+	case key == database.SyncAuto, key == config.ReadOnlyKey, key == aws.CredentialsCacheKey, key == notify.PagerDutyInteractiveKey, key == config.ColorKey, key == aws.FIPSKey:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return value, fmt.Errorf("invalid %s value '%s': expecting true or false", key, value)
+		}
+	case key == database.InstanceCountKey:
+		if n, err := strconv.Atoi(value); err != nil || n < 1 {
+			return value, fmt.Errorf("invalid %s value '%s': expecting a positive number", key, value)
+		}
+	case key == statecrypt.ModeKey:
+		if value != statecrypt.KeychainMode && value != statecrypt.PassphraseMode {
+			return value, fmt.Errorf("invalid %s value '%s': expecting %s or %s", key, value, statecrypt.KeychainMode, statecrypt.PassphraseMode)
+		}
+	case key == aws.EndpointKey, key == aws.ProxyKey, strings.HasPrefix(key, aws.EndpointKeyPrefix):
+		if _, err := aws.ParseEndpointURL(value); err != nil {
+			return value, err
+		}
+	case key == cloud.ProviderKey:
+		if _, err := cloud.GetProvider(value); err != nil {
+			return value, err
+		}
+	case key == notify.SlackWebhookKey:
+		if err := notify.ValidateURL(value); err != nil {
+			return value, err
+		}
+	case key == notify.WebhookURLsKey:
+		for _, u := range strings.Split(value, ",") {
+			if err := notify.ValidateURL(strings.TrimSpace(u)); err != nil {
+				return value, err
+			}
+		}
+	case key == notify.SNSTopicKey:
+		if _, err := notify.TopicRegion(value); err != nil {
+			return value, err
+		}
+	case key == notify.EmailToKey, key == notify.EmailFromKey:
+		if err := notify.ValidateAddresses(value); err != nil {
+			return value, err
+		}
+	case key == lock.TimeoutKey:
+		if _, err := lock.Timeout(map[string]interface{}{key: value}); err != nil {
+			return value, err
+		}
+	case key == config.GuardrailsKey:
+		abs, err := filepath.Abs(value)
+		if err != nil {
+			return value, err
+		}
+		if _, err := config.LoadGuardrails(abs); err != nil {
+			return value, err
+		}
+		value = abs
+	case key == config.RegoPolicyKey:
+		abs, err := filepath.Abs(value)
+		if err != nil {
+			return value, err
+		}
+		if _, err := os.Stat(abs); err != nil {
+			return value, fmt.Errorf("rego policy: %s", err)
+		}
+		value = abs
+	case key == aws.CABundleKey:
+		abs, err := filepath.Abs(value)
+		if err != nil {
+			return value, err
+		}
+		if _, err := aws.LoadCABundle(abs); err != nil {
+			return value, fmt.Errorf("CA bundle: %s", err)
+		}
+		value = abs
+	case key == aws.RetryBudgetKey, key == aws.MaxRetriesKey, strings.HasPrefix(key, aws.RetryBudgetKeyPrefix):
+		if _, err := aws.ParseRetryCount(value); err != nil {
+			return value, err
+		}
+	case key == sync.GraphFormatKey:
+		if err := sync.CheckGraphFormat(value); err != nil {
+			return value, err
+		}
+	case key == database.StatsModeKey:
+		if value != database.StatsOff && value != database.StatsLocal {
+			return value, fmt.Errorf("invalid stats mode '%s': expecting %s or %s", value, database.StatsOff, database.StatsLocal)
+		}
+	case strings.HasPrefix(key, config.RegionGroupKeyPrefix):
+		if _, err := config.ParseRegionGroup(value); err != nil {
+			return value, err
+		}
+	case strings.HasPrefix(key, config.ShortcutKeyPrefix):
+		if name := strings.TrimPrefix(key, config.ShortcutKeyPrefix); isBuiltinCommand(RootCmd, name) {
+			return value, fmt.Errorf("invalid shortcut '%s': already an awless command", name)
+		}
+		if _, err := config.ParseShortcut(value); err != nil {
+			return value, err
+		}
+	case strings.HasPrefix(key, config.TableMaxWidthKeyPrefix):
+		if _, err := config.ParseColumnMaxWidth(value); err != nil {
+			return value, err
+		}
+	}
+	return value, nil
+}
+
 var configUnsetCmd = &cobra.Command{
 	Use:   "unset {key}",
 	Short: "Unset a configuration value",
@@ -150,6 +284,8 @@ var configUnsetCmd = &cobra.Command{
 		_, ok := db.GetDefault(args[0])
 		if !ok {
 			fmt.Println("this parameter has not been set")
+		} else if args[0] == statecrypt.ModeKey {
+			return fmt.Errorf("use `awless state decrypt` to also decrypt the existing local state")
 		} else {
 			db.UnsetDefault(args[0])
 		}
@@ -0,0 +1,101 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/wallix/awless/archive"
+	"github.com/wallix/awless/aws"
+	awsdriver "github.com/wallix/awless/aws/driver"
+	"github.com/wallix/awless/azure"
+	"github.com/wallix/awless/backend"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/cmdb"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/gcp"
+	"github.com/wallix/awless/kubernetes"
+	"github.com/wallix/awless/lock"
+	"github.com/wallix/awless/notify"
+	"github.com/wallix/awless/openstack"
+	"github.com/wallix/awless/statecrypt"
+	"github.com/wallix/awless/sync"
+	"github.com/wallix/awless/tracing"
+)
+
+func init() {
+	config.EnvKeys = func() []string { return append(templateParamKeys(), configKeys...) }
+	config.EnvKeyPrefixes = configKeyPrefixes
+}
+
+// configKeys are the config keys known by awless. Defaults of template params
+// (ex: instance.subnet) and keys with a known prefix (ex: shortcut.ls) are also valid
+var configKeys = []string{
+	database.RegionKey, database.ProfileKey, database.SyncAuto, database.StatsModeKey,
+	database.InstanceTypeKey, database.InstanceImageKey, database.InstanceCountKey,
+	cloud.ProviderKey, gcp.ProjectKey, azure.SubscriptionKey, openstack.RegionKey,
+	kubernetes.ContextsKey, kubernetes.KubeconfigKey,
+	config.ReadOnlyKey, config.GuardrailsKey, config.RegoPolicyKey,
+	aws.CredentialsCacheKey, aws.AccountRoleKey, aws.EndpointKey, aws.ProxyKey,
+	aws.FIPSKey, aws.CABundleKey, aws.RetryBudgetKey, aws.MaxRetriesKey, statecrypt.ModeKey, sync.GraphFormatKey, serveTokenKey, config.ColorKey,
+	lock.TableKey, lock.RegionKey, lock.TimeoutKey,
+	backend.BucketKey, backend.PrefixKey, backend.RegionKey, backend.KMSKeyIDKey,
+	archive.RepoKey, archive.RemoteKey, tracing.EndpointKey,
+	notify.SlackWebhookKey, notify.SlackChannelKey, notify.PagerDutyRoutingKeyKey, notify.PagerDutyInteractiveKey,
+	notify.DatadogAPIKeyKey, notify.DatadogSiteKey, notify.DatadogTagsKey,
+	notify.EmailToKey, notify.EmailFromKey, notify.EmailSMTPKey, notify.EmailUsernameKey, notify.EmailPasswordKey, notify.EmailEventsKey,
+	notify.WebhookURLsKey, notify.SecretKey, notify.SNSTopicKey,
+}
+
+var configKeyPrefixes = []string{aws.EndpointKeyPrefix, aws.RetryBudgetKeyPrefix, config.RegionGroupKeyPrefix, config.ShortcutKeyPrefix, config.TableMaxWidthKeyPrefix, cmdb.ClassKeyPrefix}
+
+func isKnownConfigKey(key string) bool {
+	for _, k := range configKeys {
+		if k == key {
+			return true
+		}
+	}
+	for _, prefix := range configKeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	for _, k := range templateParamKeys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// templateParamKeys returns the keys of the default values of template params (ex: instance.subnet)
+func templateParamKeys() []string {
+	unique := make(map[string]struct{})
+	for _, def := range awsdriver.AWSTemplatesDefinitions {
+		for _, p := range append(def.RequiredParams, def.ExtraParams...) {
+			unique[def.Entity+"."+p] = struct{}{}
+		}
+	}
+	var keys []string
+	for k := range unique {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/logger"
+)
+
+var consolePrintOnlyFlag bool
+
+func init() {
+	RootCmd.AddCommand(consoleCmd)
+
+	consoleCmd.Flags().BoolVar(&consolePrintOnlyFlag, "print", false, "Print the console URL instead of opening it in a browser")
+}
+
+var consoleCmd = &cobra.Command{
+	Use:                "console [id or alias]",
+	Short:              "Open the AWS web console, on the page of the given resource if any. Ex: awless console @db-prod",
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook, initSyncerHook, verifyNewVersionHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		region, _, err := currentRegionAndProfile()
+		exitOn(err)
+
+		link := aws.ConsoleHomeURL(region)
+		if len(args) > 0 {
+			id := args[0]
+			if resolved, ok := resolveUserAlias(id); ok {
+				id = resolved
+			}
+			resource, _ := findResourceInLocalGraphs(id)
+			if resource == nil {
+				exitOn(fmt.Errorf("resource %s not found in local snapshot. You might want to perform an `awless sync`", id))
+			}
+			link, err = aws.ConsoleURL(resource, region)
+			exitOn(err)
+		}
+
+		if consolePrintOnlyFlag {
+			fmt.Println(link)
+			return nil
+		}
+		if err = openBrowser(link); err != nil {
+			logger.Errorf("cannot open browser: %s", err)
+			fmt.Println(link)
+		}
+		return nil
+	},
+}
+
+func openBrowser(link string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", link).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", link).Start()
+	default:
+		return exec.Command("xdg-open", link).Start()
+	}
+}
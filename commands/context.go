@@ -0,0 +1,208 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/openstack"
+)
+
+var (
+	contextProfileFlag  string
+	contextRegionFlag   string
+	contextProviderFlag string
+	contextSetFlag      []string
+)
+
+func init() {
+	RootCmd.AddCommand(contextCmd)
+	contextCmd.AddCommand(contextCreateCmd)
+	contextCmd.AddCommand(contextUseCmd)
+	contextCmd.AddCommand(contextListCmd)
+	contextCmd.AddCommand(contextDeleteCmd)
+
+	contextCreateCmd.Flags().StringVar(&contextProfileFlag, "profile", "", "AWS profile of the context")
+	contextCreateCmd.Flags().StringVar(&contextRegionFlag, "region", "", "Region of the context: AWS region, or OpenStack region with --provider openstack")
+	contextCreateCmd.Flags().StringVar(&contextProviderFlag, "provider", "", "Cloud provider of the context. Ex: --provider openstack")
+	contextCreateCmd.Flags().StringSliceVar(&contextSetFlag, "set", []string{}, "Config values of the context. Ex: --set instance.type=t2.small")
+}
+
+var contextCmd = &cobra.Command{
+	Use:                "context",
+	Short:              "Create, list and switch between named contexts bundling a profile, a region and config values",
+	PersistentPreRunE:  initAwlessEnvHook,
+	PersistentPostRunE: saveHistoryHook,
+}
+
+var contextCreateCmd = &cobra.Command{
+	Use:   "create {name}",
+	Short: "Create or update a context. Ex: awless context create prod --profile prod --region eu-west-1",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("context name required")
+		}
+		if contextProviderFlag != "" {
+			if _, err := cloud.GetProvider(contextProviderFlag); err != nil {
+				return err
+			}
+		}
+		isAWS := contextProviderFlag == "" || contextProviderFlag == cloud.DefaultProvider
+		if isAWS && contextRegionFlag != "" && !aws.IsValidRegion(contextRegionFlag) {
+			return fmt.Errorf("invalid region '%s'", contextRegionFlag)
+		}
+
+		ctx := &database.Context{Name: args[0], Profile: contextProfileFlag, Defaults: make(map[string]interface{})}
+		switch {
+		case isAWS:
+			ctx.Region = contextRegionFlag
+		case contextRegionFlag == "":
+		case contextProviderFlag == "openstack":
+			ctx.Defaults[openstack.RegionKey] = contextRegionFlag
+		default:
+			return fmt.Errorf("--region is not supported for %s contexts: set the location params of your templates with --set", contextProviderFlag)
+		}
+		for _, set := range contextSetFlag {
+			splits := strings.SplitN(set, "=", 2)
+			if len(splits) != 2 || splits[0] == "" {
+				return fmt.Errorf("invalid config value '%s', expecting key=value", set)
+			}
+			ctx.Defaults[splits[0]] = config.ParseValue(splits[1])
+		}
+		if contextProviderFlag != "" {
+			ctx.Defaults[cloud.ProviderKey] = contextProviderFlag
+		}
+
+		db, err, close := database.Current()
+		exitOn(err)
+		defer close()
+		exitOn(db.SetContext(ctx))
+
+		fmt.Printf("context '%s' saved. Switch to it with `awless context use %[1]s`\n", ctx.Name)
+		return nil
+	},
+}
+
+var contextUseCmd = &cobra.Command{
+	Use:   "use {name}",
+	Short: "Switch to a context (use 'none' to deactivate the current context)",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("context name required")
+		}
+		name := args[0]
+		if name == "none" {
+			name = ""
+		}
+
+		db, err, close := database.Current()
+		exitOn(err)
+		defer close()
+		exitOn(db.UseContext(name))
+
+		return nil
+	},
+}
+
+var contextListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List contexts (the active one is marked with '*')",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err, close := database.Current()
+		exitOn(err)
+		defer close()
+
+		all, err := db.GetContexts()
+		exitOn(err)
+
+		var currentName string
+		if current, ok := db.GetCurrentContext(); ok {
+			currentName = current.Name
+		}
+
+		for _, ctx := range all {
+			mark := " "
+			if ctx.Name == currentName {
+				mark = "*"
+			}
+			fmt.Printf("%s %s\n", mark, describeContext(ctx))
+		}
+		return nil
+	},
+}
+
+var contextDeleteCmd = &cobra.Command{
+	Use:   "delete {name}",
+	Short: "Delete a context",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("context name required")
+		}
+
+		db, err, close := database.Current()
+		exitOn(err)
+		defer close()
+		exitOn(db.DeleteContext(args[0]))
+
+		return nil
+	},
+}
+
+func describeContext(ctx *database.Context) string {
+	details := []string{ctx.Name}
+	if ctx.Profile != "" {
+		details = append(details, fmt.Sprintf("profile=%s", ctx.Profile))
+	}
+	if ctx.Region != "" {
+		details = append(details, fmt.Sprintf("region=%s", ctx.Region))
+	}
+	var keys []string
+	for k := range ctx.Defaults {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		details = append(details, fmt.Sprintf("%s=%v", k, ctx.Defaults[k]))
+	}
+	return strings.Join(details, " ")
+}
+
+// currentContextName returns the name of the active context or an empty string
+func currentContextName() string {
+	db, err, close := database.Current()
+	if err != nil {
+		return ""
+	}
+	defer close()
+	if ctx, ok := db.GetCurrentContext(); ok {
+		return ctx.Name
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/console"
+)
+
+var recursiveCopyFlag bool
+
+func init() {
+	RootCmd.AddCommand(cpCmd)
+
+	cpCmd.Flags().BoolVarP(&recursiveCopyFlag, "recursive", "r", false, "Recursively copy entire directories")
+	cpCmd.Flags().StringVarP(&keyPathFlag, "identity", "i", "", "Private key file to use instead of the instance keypair found in awless keys folder")
+}
+
+var cpCmd = &cobra.Command{
+	Use:                "cp source destination",
+	Short:              "Copy files to or from an instance (given an id or alias) through SCP. Ex: awless cp ./file.tgz myinstance:/tmp/",
+	Example:            "  awless cp ./file.tgz myinstance:/tmp/\n  awless cp -r ubuntu@myinstance:/var/log ./logs",
+	PersistentPreRun:   applyHooks(initAwlessEnvHook, initCloudServicesHook, verifyNewVersionHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return errors.New("source and destination required")
+		}
+
+		src, dest := parseCopyLocation(args[0]), parseCopyLocation(args[1])
+
+		switch {
+		case src.isRemote() && dest.isRemote():
+			return errors.New("copy between two instances is not supported")
+		case !src.isRemote() && !dest.isRemote():
+			return errors.New("either source or destination has to be an instance. Ex: myinstance:/tmp/")
+		case dest.isRemote():
+			client, err := instanceSSHClient(dest.target)
+			exitOn(err)
+			defer client.Close()
+			exitOn(console.CopyToRemote(client, src.path, dest.remotePath(), recursiveCopyFlag))
+		default:
+			client, err := instanceSSHClient(src.target)
+			exitOn(err)
+			defer client.Close()
+			exitOn(console.CopyFromRemote(client, src.remotePath(), dest.path, recursiveCopyFlag))
+		}
+
+		return nil
+	},
+}
+
+type copyLocation struct {
+	target, path string
+}
+
+func (l copyLocation) isRemote() bool {
+	return l.target != ""
+}
+
+func (l copyLocation) remotePath() string {
+	if l.path == "" {
+		return "."
+	}
+	return l.path
+}
+
+// parseCopyLocation splits a '[user@]instance:path' location.
+// Paths without colon or starting with '.' or '/' are considered local
+func parseCopyLocation(arg string) copyLocation {
+	if strings.HasPrefix(arg, ".") || strings.HasPrefix(arg, "/") {
+		return copyLocation{path: arg}
+	}
+	splits := strings.SplitN(arg, ":", 2)
+	if len(splits) != 2 || splits[0] == "" {
+		return copyLocation{path: arg}
+	}
+	return copyLocation{target: splits[0], path: splits[1]}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import "testing"
+
+func TestParseCopyLocation(t *testing.T) {
+	tcases := []struct {
+		arg          string
+		target, path string
+	}{
+		{arg: "./file.tgz", path: "./file.tgz"},
+		{arg: "/tmp/file:with:colons", path: "/tmp/file:with:colons"},
+		{arg: "file.tgz", path: "file.tgz"},
+		{arg: "myinstance:/tmp/", target: "myinstance", path: "/tmp/"},
+		{arg: "ubuntu@i-12345:", target: "ubuntu@i-12345", path: ""},
+		{arg: ":/tmp", path: ":/tmp"},
+	}
+
+	for _, tcase := range tcases {
+		loc := parseCopyLocation(tcase.arg)
+		if got, want := loc.target, tcase.target; got != want {
+			t.Fatalf("%s: target: got %s, want %s", tcase.arg, got, want)
+		}
+		if got, want := loc.path, tcase.path; got != want {
+			t.Fatalf("%s: path: got %s, want %s", tcase.arg, got, want)
+		}
+	}
+
+	if got, want := parseCopyLocation("myinstance:").remotePath(), "."; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
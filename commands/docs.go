@@ -0,0 +1,419 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/wallix/awless/aws/driver"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/template"
+	"github.com/wallix/awless/template/local"
+)
+
+var (
+	docsDirFlag    string
+	docsFormatFlag string
+)
+
+func init() {
+	RootCmd.AddCommand(docsCmd)
+	docsCmd.AddCommand(docsGenerateCmd)
+
+	docsGenerateCmd.Flags().StringVar(&docsDirFlag, "dir", "docs", "Directory where to write the documentation")
+	docsGenerateCmd.Flags().StringVar(&docsFormatFlag, "format", "all", "Documentation format: man, markdown or all")
+}
+
+var docsCmd = &cobra.Command{
+	Use:     "docs [ACTION [ENTITY]]",
+	Aliases: []string{"doc"},
+	Short:   "Show the reference of template statements (ex: awless doc create instance) or generate awless documentation",
+	Long:    "Reference of template actions, entities and params, introspected from the drivers definitions.\nWithout args, list the actions. With an action, list its entities. With an action and an entity, show params, examples and revert behavior.",
+	Example: "  awless doc\n  awless doc create\n  awless doc create instance",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		defs := allTemplateDefinitions()
+		switch len(args) {
+		case 0:
+			writeDocActions(os.Stdout, defs)
+		case 1:
+			exitOn(writeDocEntities(os.Stdout, defs, args[0]))
+		case 2:
+			def, ok := defs[args[0]+args[1]]
+			if !ok {
+				exitOn(fmt.Errorf("unknown statement '%s %s'. See 'awless doc %s'", args[0], args[1], args[0]))
+			}
+			writeDocDefinition(os.Stdout, def, defs)
+		default:
+			return cmd.Usage()
+		}
+		return nil
+	},
+}
+
+var docsGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate man pages and markdown docs for every command, including one-liner template params",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var generators []func(*cobra.Command, io.Writer) error
+		var exts []string
+		switch docsFormatFlag {
+		case "man":
+			generators, exts = append(generators, genManPage), append(exts, ".1")
+		case "markdown":
+			generators, exts = append(generators, genMarkdownDoc), append(exts, ".md")
+		case "all":
+			generators, exts = append(generators, genManPage, genMarkdownDoc), append(exts, ".1", ".md")
+		default:
+			return fmt.Errorf("unknown docs format '%s'", docsFormatFlag)
+		}
+
+		exitOn(os.MkdirAll(docsDirFlag, 0755))
+
+		var count int
+		walkCommands(RootCmd, func(c *cobra.Command) {
+			for i, gen := range generators {
+				var buff bytes.Buffer
+				exitOn(gen(c, &buff))
+				exitOn(ioutil.WriteFile(filepath.Join(docsDirFlag, docFilename(c)+exts[i]), buff.Bytes(), 0644))
+			}
+			count++
+		})
+
+		fmt.Printf("documentation of %d commands written in %s\n", count, docsDirFlag)
+		return nil
+	},
+}
+
+func docFilename(cmd *cobra.Command) string {
+	return strings.Replace(cmd.CommandPath(), " ", "-", -1)
+}
+
+// templateDefinitionOf returns the template definition behind a one-liner command, if any
+func templateDefinitionOf(cmd *cobra.Command) (template.TemplateDefinition, bool) {
+	if !cmd.HasParent() || !IsCmdAnnotatedOneliner(cmd.Parent().Annotations) {
+		return template.TemplateDefinition{}, false
+	}
+	def, ok := aws.AWSTemplatesDefinitions[cmd.Parent().Name()+cmd.Name()]
+	return def, ok
+}
+
+func visibleFlags(flags *pflag.FlagSet) (visible []*pflag.Flag) {
+	flags.VisitAll(func(f *pflag.Flag) {
+		if !f.Hidden && f.Deprecated == "" {
+			visible = append(visible, f)
+		}
+	})
+	return
+}
+
+func flagSignature(f *pflag.Flag) string {
+	sig := "--" + f.Name
+	if f.Shorthand != "" {
+		sig = "-" + f.Shorthand + ", " + sig
+	}
+	if f.Value.Type() != "bool" {
+		sig += " " + f.Value.Type()
+	}
+	return sig
+}
+
+func docDescription(cmd *cobra.Command) string {
+	if cmd.Long != "" {
+		return cmd.Long
+	}
+	return cmd.Short
+}
+
+func genMarkdownDoc(cmd *cobra.Command, w io.Writer) error {
+	var buff bytes.Buffer
+
+	def, isOneliner := templateDefinitionOf(cmd)
+
+	fmt.Fprintf(&buff, "## %s\n\n%s\n\n", cmd.CommandPath(), cmd.Short)
+	if desc := docDescription(cmd); desc != cmd.Short && !isOneliner {
+		fmt.Fprintf(&buff, "### Synopsis\n\n%s\n\n", desc)
+	}
+	if cmd.Runnable() {
+		fmt.Fprintf(&buff, "```\n%s\n```\n\n", cmd.UseLine())
+	}
+	if len(cmd.Aliases) > 0 {
+		fmt.Fprintf(&buff, "Aliases: %s\n\n", strings.Join(cmd.Aliases, ", "))
+	}
+
+	if isOneliner {
+		fmt.Fprint(&buff, "### Template params\n\n")
+		for _, p := range uniqueStrings(def.Required()) {
+			fmt.Fprintf(&buff, "* `%s` (required)\n", p)
+		}
+		for _, p := range uniqueStrings(def.Extra()) {
+			fmt.Fprintf(&buff, "* `%s`\n", p)
+		}
+		fmt.Fprintf(&buff, "\nEx: `awless %s %s %s`\n\n", def.Action, def.Entity, exampleParams(def))
+	}
+
+	writeMarkdownFlags := func(title string, flags []*pflag.Flag) {
+		if len(flags) == 0 {
+			return
+		}
+		fmt.Fprintf(&buff, "### %s\n\n```\n", title)
+		for _, f := range flags {
+			fmt.Fprintf(&buff, "  %-30s %s", flagSignature(f), f.Usage)
+			if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "[]" {
+				fmt.Fprintf(&buff, " (default %q)", f.DefValue)
+			}
+			fmt.Fprintln(&buff)
+		}
+		fmt.Fprint(&buff, "```\n\n")
+	}
+	writeMarkdownFlags("Options", visibleFlags(cmd.NonInheritedFlags()))
+	writeMarkdownFlags("Options inherited from parent commands", visibleFlags(cmd.InheritedFlags()))
+
+	var seeAlso []string
+	if cmd.HasParent() {
+		parent := cmd.Parent()
+		seeAlso = append(seeAlso, fmt.Sprintf("* [%s](%s.md) - %s", parent.CommandPath(), docFilename(parent), parent.Short))
+	}
+	for _, child := range cmd.Commands() {
+		if child.IsAvailableCommand() {
+			seeAlso = append(seeAlso, fmt.Sprintf("* [%s](%s.md) - %s", child.CommandPath(), docFilename(child), child.Short))
+		}
+	}
+	if len(seeAlso) > 0 {
+		fmt.Fprintf(&buff, "### See also\n\n%s\n", strings.Join(seeAlso, "\n"))
+	}
+
+	_, err := buff.WriteTo(w)
+	return err
+}
+
+func genManPage(cmd *cobra.Command, w io.Writer) error {
+	var buff bytes.Buffer
+
+	title := strings.ToUpper(docFilename(cmd))
+	fmt.Fprintf(&buff, ".TH \"%s\" \"1\" \"%s\" \"awless %s\" \"Awless Manual\"\n", title, time.Now().Format("Jan 2006"), config.Version)
+	fmt.Fprintf(&buff, ".SH NAME\n%s \\- %s\n", manEscape(docFilename(cmd)), manEscape(cmd.Short))
+	fmt.Fprintf(&buff, ".SH SYNOPSIS\n\\fB%s\\fP\n", manEscape(cmd.UseLine()))
+	def, isOneliner := templateDefinitionOf(cmd)
+	if isOneliner {
+		fmt.Fprintf(&buff, ".SH DESCRIPTION\n%s\n", manEscape(cmd.Short))
+		fmt.Fprint(&buff, ".SH TEMPLATE PARAMS\n")
+		for _, p := range uniqueStrings(def.Required()) {
+			fmt.Fprintf(&buff, ".TP\n\\fB%s\\fP\nrequired\n", manEscape(p))
+		}
+		for _, p := range uniqueStrings(def.Extra()) {
+			fmt.Fprintf(&buff, ".TP\n\\fB%s\\fP\noptional\n", manEscape(p))
+		}
+		fmt.Fprintf(&buff, ".SH EXAMPLE\n.nf\nawless %s %s %s\n.fi\n", def.Action, def.Entity, manEscape(exampleParams(def)))
+	} else {
+		fmt.Fprintf(&buff, ".SH DESCRIPTION\n%s\n", manEscape(docDescription(cmd)))
+	}
+
+	writeManFlags := func(title string, flags []*pflag.Flag) {
+		if len(flags) == 0 {
+			return
+		}
+		fmt.Fprintf(&buff, ".SH %s\n", title)
+		for _, f := range flags {
+			fmt.Fprintf(&buff, ".TP\n\\fB%s\\fP\n%s\n", manEscape(flagSignature(f)), manEscape(f.Usage))
+		}
+	}
+	writeManFlags("OPTIONS", visibleFlags(cmd.NonInheritedFlags()))
+	writeManFlags("OPTIONS INHERITED FROM PARENT COMMANDS", visibleFlags(cmd.InheritedFlags()))
+
+	var seeAlso []string
+	if cmd.HasParent() {
+		seeAlso = append(seeAlso, fmt.Sprintf("\\fB%s\\fP(1)", manEscape(docFilename(cmd.Parent()))))
+	}
+	for _, child := range cmd.Commands() {
+		if child.IsAvailableCommand() {
+			seeAlso = append(seeAlso, fmt.Sprintf("\\fB%s\\fP(1)", manEscape(docFilename(child))))
+		}
+	}
+	if len(seeAlso) > 0 {
+		fmt.Fprintf(&buff, ".SH SEE ALSO\n%s\n", strings.Join(seeAlso, ", "))
+	}
+
+	_, err := buff.WriteTo(w)
+	return err
+}
+
+func exampleParams(def template.TemplateDefinition) string {
+	var params []string
+	for _, p := range uniqueStrings(def.Required()) {
+		params = append(params, fmt.Sprintf("%s=%s", p, exampleValue(def.ParamType(p))))
+	}
+	return strings.Join(params, " ")
+}
+
+func exampleValue(paramType string) string {
+	switch paramType {
+	case "integer":
+		return "1"
+	case "boolean":
+		return "true"
+	case "text":
+		return "@path/to/file"
+	case "map":
+		return "{key:value}"
+	default:
+		return "..."
+	}
+}
+
+// allTemplateDefinitions returns the definitions of all the statements the drivers can run
+func allTemplateDefinitions() map[string]template.TemplateDefinition {
+	defs := make(map[string]template.TemplateDefinition)
+	for key, def := range aws.AWSTemplatesDefinitions {
+		defs[key] = def
+	}
+	for key, def := range local.TemplatesDefinitions {
+		defs[key] = def
+	}
+	return defs
+}
+
+func writeDocActions(w io.Writer, defs map[string]template.TemplateDefinition) {
+	entities := make(map[string][]string)
+	for _, def := range defs {
+		entities[def.Action] = append(entities[def.Action], def.Entity)
+	}
+	var actions []string
+	for action := range entities {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	fmt.Fprintln(w, "Actions:")
+	for _, action := range actions {
+		sort.Strings(entities[action])
+		fmt.Fprintf(w, "  %-10s %s\n", action, strings.Join(entities[action], ", "))
+	}
+	fmt.Fprintln(w, "\nRun 'awless doc ACTION ENTITY' for the reference of a statement")
+}
+
+func writeDocEntities(w io.Writer, defs map[string]template.TemplateDefinition, action string) error {
+	var entities []string
+	for _, def := range defs {
+		if def.Action == action {
+			entities = append(entities, def.Entity)
+		}
+	}
+	if len(entities) == 0 {
+		return fmt.Errorf("unknown action '%s'. See 'awless doc'", action)
+	}
+	sort.Strings(entities)
+	fmt.Fprintf(w, "Entities for action '%s':\n", action)
+	for _, entity := range entities {
+		fmt.Fprintf(w, "  %s\n", entity)
+	}
+	return nil
+}
+
+func writeDocDefinition(w io.Writer, def template.TemplateDefinition, defs map[string]template.TemplateDefinition) {
+	fmt.Fprintf(w, "%s %s\n", def.Action, def.Entity)
+	if def.Api != "" {
+		fmt.Fprintf(w, "  api: %s\n", def.Api)
+	} else {
+		fmt.Fprintln(w, "  run locally")
+	}
+
+	fmt.Fprintln(w, "\nParams:")
+	required, tags := make(map[string]bool), make(map[string]bool)
+	for _, p := range def.Required() {
+		required[p] = true
+	}
+	for _, p := range def.TagsMapping {
+		tags[p] = true
+	}
+	for _, p := range uniqueStrings(append(def.Required(), def.Extra()...)) {
+		status := "optional"
+		if required[p] {
+			status = "required"
+		} else if tags[p] {
+			status = "optional, set as tag"
+		}
+		fmt.Fprintf(w, "  %-15s %-8s %s\n", p, def.ParamType(p), status)
+	}
+
+	fmt.Fprintln(w, "\nExamples:")
+	fmt.Fprintf(w, "  awless %s %s %s\n", def.Action, def.Entity, exampleParams(def))
+	var holes []string
+	for _, p := range uniqueStrings(def.Required()) {
+		holes = append(holes, fmt.Sprintf("%s={%s.%s}", p, def.Entity, p))
+	}
+	fmt.Fprintf(w, "\nIn a template:\n  %s %s %s\n", def.Action, def.Entity, strings.Join(holes, " "))
+
+	fmt.Fprintf(w, "\nRevert:\n  %s\n", docRevert(def, defs))
+}
+
+// docRevert describes the statements undoing the given one, as run by 'awless revert'
+func docRevert(def template.TemplateDefinition, defs map[string]template.TemplateDefinition) string {
+	revertAction, ok := template.RevertAction(def.Action)
+	if _, isLocal := local.TemplatesDefinitions[def.Name()]; !ok || isLocal {
+		return "not revertible"
+	}
+	if _, exists := defs[revertAction+def.Entity]; !exists {
+		return "not revertible"
+	}
+	switch def.Action {
+	case "create":
+		revert := fmt.Sprintf("%s %s id=<created id>", revertAction, def.Entity)
+		if def.Entity == "instance" {
+			revert += fmt.Sprintf(", then check %s id=<created id> state=terminated", def.Entity)
+		}
+		return revert
+	default:
+		return fmt.Sprintf("%s %s with the same params", revertAction, def.Entity)
+	}
+}
+
+func uniqueStrings(all []string) (unique []string) {
+	seen := make(map[string]bool)
+	for _, s := range all {
+		if !seen[s] {
+			seen[s] = true
+			unique = append(unique, s)
+		}
+	}
+	return
+}
+
+// manEscape escapes text for roff: backslashes, dashes and control characters at line start
+func manEscape(s string) string {
+	s = strings.Replace(s, `\`, `\e`, -1)
+	s = strings.Replace(s, "-", `\-`, -1)
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		l = strings.TrimLeft(l, "\t")
+		if strings.HasPrefix(l, ".") || strings.HasPrefix(l, "'") {
+			l = `\&` + l
+		}
+		lines[i] = l
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func findCommand(t *testing.T, path ...string) *cobra.Command {
+	cmd, _, err := RootCmd.Find(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestGenerateDocs(t *testing.T) {
+	createInstance := findCommand(t, "create", "instance")
+
+	var md bytes.Buffer
+	if err := genMarkdownDoc(createInstance, &md); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"## awless create instance", "### Template params", "* `subnet` (required)", "[awless create](awless-create.md)"} {
+		if !strings.Contains(md.String(), want) {
+			t.Fatalf("expected %q in\n%s", want, md.String())
+		}
+	}
+
+	var man bytes.Buffer
+	if err := genManPage(createInstance, &man); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`.TH "AWLESS-CREATE-INSTANCE" "1"`, ".SH TEMPLATE PARAMS", `\fBsubnet\fP`, `\fBawless\-create\fP(1)`} {
+		if !strings.Contains(man.String(), want) {
+			t.Fatalf("expected %q in\n%s", want, man.String())
+		}
+	}
+}
+
+func TestManEscape(t *testing.T) {
+	if got, want := manEscape(".hidden -flag\\n"), `\&.hidden \-flag\en`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestDocDefinition(t *testing.T) {
+	defs := allTemplateDefinitions()
+
+	var buff bytes.Buffer
+	writeDocDefinition(&buff, defs["createinstance"], defs)
+	for _, want := range []string{"api: ec2", "count           integer  required", "lock            boolean  optional", "name            string   optional, set as tag", "awless create instance image=... count=1", "delete instance id=<created id>"} {
+		if !strings.Contains(buff.String(), want) {
+			t.Fatalf("expected %q in\n%s", want, buff.String())
+		}
+	}
+
+	tcases := []struct {
+		statement, want string
+	}{
+		{"stopinstance", "start instance with the same params"},
+		{"deletevpc", "not revertible"},
+		{"attachvolume", "not revertible"},
+		{"runlocal", "not revertible"},
+	}
+	for _, tcase := range tcases {
+		if got, want := docRevert(defs[tcase.statement], defs), tcase.want; got != want {
+			t.Fatalf("%s: got %s, want %s", tcase.statement, got, want)
+		}
+	}
+
+	if err := writeDocEntities(&buff, defs, "unknown"); err == nil {
+		t.Fatal("expected error for unknown action")
+	}
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/statecrypt"
+	"github.com/wallix/awless/sync"
+)
+
+func init() {
+	RootCmd.AddCommand(doctorCmd)
+	configCmd.AddCommand(configValidateCmd)
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration keys and values, printing how to fix them",
+
+	Run: func(cmd *cobra.Command, args []string) {
+		defaults, err := config.LoadDefaults()
+		exitOn(err)
+		problems := configProblems(defaults)
+		for _, p := range problems {
+			printDoctorResult(p)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+		fmt.Printf("%d config values valid\n", len(defaults))
+	},
+}
+
+var doctorCmd = &cobra.Command{
+	Use:                "doctor",
+	Short:              "Diagnose the configuration, credentials, region reachability, permissions needed to sync and integrity of the local state, printing how to fix problems",
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var failed bool
+		for _, check := range doctorChecks() {
+			for _, res := range check() {
+				printDoctorResult(res)
+				if res.Status == doctorFail {
+					failed = true
+				}
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+type doctorResult struct {
+	Check, Status, Detail, Fix string
+}
+
+func printDoctorResult(res *doctorResult) {
+	var status string
+	switch res.Status {
+	case doctorOK:
+		status = renderGreenFn(res.Status)
+	case doctorWarn:
+		status = color.New(color.FgYellow).SprintFunc()(res.Status)
+	default:
+		status = renderRedFn(res.Status)
+	}
+	fmt.Printf("[%s] %s: %s\n", status, res.Check, res.Detail)
+	if res.Fix != "" {
+		fmt.Printf("\tfix: %s\n", res.Fix)
+	}
+}
+
+// doctorCheck returns the results of a diagnosis, usually one
+type doctorCheck func() []*doctorResult
+
+func single(fn func() *doctorResult) doctorCheck {
+	return func() []*doctorResult { return []*doctorResult{fn()} }
+}
+
+func doctorChecks() []doctorCheck {
+	defaults, err := config.LoadDefaults()
+	if err != nil {
+		return []doctorCheck{single(func() *doctorResult {
+			return &doctorResult{Check: "config", Status: doctorFail, Detail: err.Error(), Fix: "check the permissions of ~/.awless"}
+		})}
+	}
+	region, _ := defaults[database.RegionKey].(string)
+	profile, _ := defaults[database.ProfileKey].(string)
+
+	var callerArn string
+	checks := []doctorCheck{
+		func() []*doctorResult {
+			if problems := configProblems(defaults); len(problems) > 0 {
+				return problems
+			}
+			return []*doctorResult{{Check: "config", Status: doctorOK, Detail: fmt.Sprintf("%d values valid", len(defaults))}}
+		},
+		single(checkLocalState),
+	}
+	if name, _ := defaults[cloud.ProviderKey].(string); name != "" && name != "aws" {
+		return checks
+	}
+	return append(checks,
+		single(func() *doctorResult {
+			res := &doctorResult{Check: "region"}
+			if !aws.IsValidRegion(region) {
+				res.Status, res.Detail, res.Fix = doctorFail, fmt.Sprintf("invalid region '%s'", region), "awless config set region"
+				return res
+			}
+			if err := checkRegionReachable(region); err != nil {
+				res.Status, res.Detail, res.Fix = doctorFail, err.Error(), "check your network connectivity, proxy (aws.proxy or HTTPS_PROXY), endpoints (aws.endpoint), CA bundle (aws.ca.bundle) and firewall"
+				return res
+			}
+			res.Status, res.Detail = doctorOK, fmt.Sprintf("%s reachable", region)
+			return res
+		}),
+		single(func() *doctorResult {
+			res := &doctorResult{Check: "credentials"}
+			if err := aws.InitServices(region, profile); err != nil {
+				res.Status, res.Detail = doctorFail, err.Error()
+				res.Fix = fmt.Sprintf("check the profile '%s' in ~/.aws/credentials and ~/.aws/config, or export AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY", profile)
+				return res
+			}
+			out, err := aws.SecuAPI.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+			if err != nil {
+				res.Status, res.Detail = doctorFail, err.Error()
+				res.Fix = "your credentials are invalid or expired: renew them (cached credentials of assumed roles are in ~/.awless/cache/credentials)"
+				return res
+			}
+			callerArn = awssdk.StringValue(out.Arn)
+			res.Status, res.Detail = doctorOK, fmt.Sprintf("%s (profile %s)", callerArn, profile)
+			if expiry, ok := aws.SecuAPI.SessionExpiry(); ok && time.Until(expiry) < 10*time.Minute {
+				res.Status, res.Fix = doctorWarn, fmt.Sprintf("session expires in %s", time.Until(expiry).Truncate(time.Second))
+			}
+			return res
+		}),
+		single(func() *doctorResult {
+			return checkSyncPermissions(callerArn)
+		}),
+	)
+}
+
+// configProblems validates the config keys and values, suggesting the closest known key for unknown ones
+func configProblems(defaults map[string]interface{}) (problems []*doctorResult) {
+	var keys []string
+	for k := range defaults {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !isKnownConfigKey(key) {
+			res := &doctorResult{Check: "config", Status: doctorWarn, Detail: fmt.Sprintf("unknown key '%s'", key), Fix: fmt.Sprintf("awless config unset %s", key)}
+			if closest := closestConfigKey(key); closest != "" {
+				res.Fix = fmt.Sprintf("did you mean '%s'? awless config unset %s && awless config set %s {value}", closest, key, closest)
+			}
+			problems = append(problems, res)
+			continue
+		}
+		if _, err := validateConfigValue(key, fmt.Sprint(defaults[key])); err != nil {
+			fix := fmt.Sprintf("awless config set %s {value}", key)
+			if key == statecrypt.ModeKey {
+				fix = "awless state encrypt {keychain|passphrase}"
+			}
+			problems = append(problems, &doctorResult{Check: "config", Status: doctorFail, Detail: err.Error(), Fix: fix})
+		}
+	}
+	if region, _ := defaults[database.RegionKey].(string); region == "" {
+		problems = append(problems, &doctorResult{Check: "config", Status: doctorFail, Detail: "missing region", Fix: "awless config set region"})
+	}
+	return
+}
+
+// closestConfigKey returns the known key nearest to a mistyped one, if close enough
+func closestConfigKey(key string) string {
+	var closest string
+	min := len(key)/3 + 1
+	for _, k := range configKeys {
+		if d := levenshtein(key, k); d < min {
+			closest, min = k, d
+		}
+	}
+	return closest
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func minInt(first int, others ...int) int {
+	min := first
+	for _, i := range others {
+		if i < min {
+			min = i
+		}
+	}
+	return min
+}
+
+func checkRegionReachable(region string) error {
+	endpoint, err := aws.Endpoints.EndpointFor("ec2", region)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(endpoint.URL)
+	if err != nil {
+		return err
+	}
+	if aws.Endpoints.Proxy != nil {
+		u = aws.Endpoints.Proxy
+	}
+	host := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "http" {
+			host += ":80"
+		} else {
+			host += ":443"
+		}
+	}
+	conn, err := net.DialTimeout("tcp", host, 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("cannot reach %s: %s", host, err)
+	}
+	conn.Close()
+	return nil
+}
+
+func checkSyncPermissions(callerArn string) *doctorResult {
+	res := &doctorResult{Check: "sync permissions"}
+	if callerArn == "" {
+		res.Status, res.Detail = doctorWarn, "not verified without valid credentials"
+		return res
+	}
+	access, ok := aws.AccessService.(*aws.Access)
+	if !ok {
+		res.Status, res.Detail = doctorWarn, "not verified"
+		return res
+	}
+	var actions []string
+	for _, name := range aws.ServiceNames {
+		actions = append(actions, aws.SyncPermissions[name]...)
+	}
+	missing, err := access.MissingPermissions(callerArn, actions)
+	if err != nil {
+		res.Status, res.Detail = doctorWarn, fmt.Sprintf("not verified: %s", err)
+		res.Fix = "allow iam:SimulatePrincipalPolicy to verify permissions"
+		return res
+	}
+	if len(missing) > 0 {
+		res.Status, res.Detail = doctorFail, fmt.Sprintf("denied %s", strings.Join(missing, ", "))
+		res.Fix = "attach a policy allowing those actions (ex: arn:aws:iam::aws:policy/ReadOnlyAccess), or disable the sync of the services concerned"
+		return res
+	}
+	res.Status, res.Detail = doctorOK, fmt.Sprintf("%d actions allowed", len(actions))
+	return res
+}
+
+func checkLocalState() *doctorResult {
+	res := &doctorResult{Check: "local state"}
+	db, err, closing := database.Current()
+	if err != nil {
+		res.Status, res.Detail = doctorFail, fmt.Sprintf("database: %s", err)
+		res.Fix = "stop the other running awless commands, or move ~/.awless/database.db away to start afresh"
+		return res
+	}
+	_, err = db.ListTemplateExecutions()
+	closing()
+	if err != nil {
+		res.Status, res.Detail = doctorFail, fmt.Sprintf("run history: %s", err)
+		res.Fix = fmt.Sprintf("if encrypted, export %s or check the key in your keychain", statecrypt.PassphraseEnv)
+		return res
+	}
+	var corrupted []string
+	for _, name := range aws.ServiceNames {
+		if err := sync.CheckLocalGraph(name); err != nil {
+			corrupted = append(corrupted, fmt.Sprintf("%s (%s)", name, err))
+		}
+	}
+	if len(corrupted) > 0 {
+		res.Status, res.Detail = doctorFail, fmt.Sprintf("unreadable local graphs: %s", strings.Join(corrupted, ", "))
+		res.Fix = "awless sync"
+		return res
+	}
+	res.Status, res.Detail = doctorOK, "database, run history and local graphs readable"
+	return res
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigProblems(t *testing.T) {
+	defaults := map[string]interface{}{
+		"region":          "eu-west-1",
+		"instance.count":  0,
+		"instance.subnet": "subnet-1",
+		"readonly":        true,
+		"shortcut.web":    "list instances --filter name=web",
+		"sync.autoo":      true,
+		"whatever":        "value",
+	}
+	problems := configProblems(defaults)
+	if got, want := len(problems), 3; got != want {
+		for _, p := range problems {
+			t.Log(p.Detail)
+		}
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := problems[0].Detail, "invalid instance.count value '0'"; !strings.HasPrefix(got, want) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := problems[0].Status, doctorFail; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := problems[1].Fix, "did you mean 'sync.auto'?"; !strings.HasPrefix(got, want) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := problems[2].Fix, "awless config unset whatever"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := problems[2].Status, doctorWarn; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	if problems := configProblems(map[string]interface{}{"instance.type": "t2.micro"}); len(problems) != 1 || problems[0].Detail != "missing region" {
+		t.Fatalf("unexpected %v", problems)
+	}
+}
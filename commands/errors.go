@@ -31,6 +31,8 @@ func exitOn(err error) {
 			db.AddLog(err.Error())
 		}
 		fmt.Fprintln(os.Stderr, "Error:", err)
+		exportTraces(err)
+		stopProfiling()
 		os.Exit(1)
 	}
 }
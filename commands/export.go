@@ -0,0 +1,104 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/cmdb"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/sync"
+)
+
+var (
+	exportFormatFlag string
+	exportOutputFlag string
+)
+
+func init() {
+	RootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportCMDBCmd)
+
+	exportCMDBCmd.Flags().StringVar(&exportFormatFlag, "format", "json", "Export format: json (ServiceNow IRE payload) or csv (items and relations files)")
+	exportCMDBCmd.Flags().StringVarP(&exportOutputFlag, "output", "o", "", "Directory where to write the export files (default: json on stdout, csv in current directory)")
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export your locally synced infrastructure to external systems",
+}
+
+var exportCMDBCmd = &cobra.Command{
+	Use:                "cmdb",
+	Short:              fmt.Sprintf("Export synced resources and their relationships as ServiceNow CMDB configuration items. Override CI classes with config keys %s{type}", cmdb.ClassKeyPrefix),
+	PersistentPreRun:   applyHooks(initAwlessEnvHook, initConfigStruct),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		g := graph.NewGraph()
+		for _, name := range append(aws.ServiceNames, kubernetesServiceName) {
+			g.AddGraph(sync.LoadCurrentLocalGraph(name))
+		}
+
+		export, err := cmdb.New(g, cmdb.ClassesFromConfig(config.Config.Defaults))
+		exitOn(err)
+		if len(export.Items) == 0 {
+			logger.Warning("no resources to export: run `awless sync` first")
+		}
+
+		switch exportFormatFlag {
+		case "json":
+			if exportOutputFlag == "" {
+				exitOn(export.WriteJSON(os.Stdout))
+				return nil
+			}
+			exitOn(writeExportFile(filepath.Join(exportOutputFlag, "cmdb.json"), export.WriteJSON))
+		case "csv":
+			exitOn(writeExportFile(filepath.Join(exportOutputFlag, "cmdb_items.csv"), export.WriteItemsCSV))
+			exitOn(writeExportFile(filepath.Join(exportOutputFlag, "cmdb_relations.csv"), export.WriteRelationsCSV))
+		default:
+			return fmt.Errorf("unknown export format '%s': expecting json or csv", exportFormatFlag)
+		}
+		logger.Infof("exported %d configuration items and %d relationships", len(export.Items), len(export.Relations))
+		return nil
+	},
+}
+
+func writeExportFile(path string, write func(io.Writer) error) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := write(f); err != nil {
+		return err
+	}
+	logger.Verbosef("written %s", path)
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/console"
+	"github.com/wallix/awless/graph"
+)
+
+func init() {
+	RootCmd.AddCommand(graphCmd)
+	graphCmd.AddCommand(graphShowCmd)
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Display your synced resources topology in the terminal",
+}
+
+var graphShowCmd = &cobra.Command{
+	Use:                "show {vpc id or alias}",
+	Short:              "Show the tree of a VPC: internet gateways, route tables, security groups, subnets and instances. Ex: awless graph show @prod",
+	PersistentPreRun:   applyHooks(initAwlessEnvHook, verifyNewVersionHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("vpc id or alias required")
+		}
+		id := args[0]
+		if resolved, ok := resolveUserAlias(id); ok {
+			id = resolved
+		}
+
+		vpc, g := findResourceInLocalGraphs(id)
+		if vpc == nil {
+			exitOn(fmt.Errorf("resource %s not found in local snapshot. You might want to perform an `awless sync`", id))
+		}
+		if vpc.Type() != graph.Vpc {
+			exitOn(fmt.Errorf("%s is a %s, expecting a vpc", id, vpc.Type()))
+		}
+
+		exitOn(console.PrintVpcTopology(os.Stdout, g, vpc))
+		return nil
+	},
+}
@@ -17,15 +17,24 @@ limitations under the License.
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/wallix/awless/aws"
+	_ "github.com/wallix/awless/azure"
+	"github.com/wallix/awless/backend"
+	"github.com/wallix/awless/cloud"
 	"github.com/wallix/awless/config"
 	"github.com/wallix/awless/database"
+	_ "github.com/wallix/awless/gcp"
+	"github.com/wallix/awless/kubernetes"
 	"github.com/wallix/awless/logger"
+	_ "github.com/wallix/awless/openstack"
 	"github.com/wallix/awless/sync"
 )
 
@@ -39,10 +48,55 @@ func applyHooks(funcs ...func(*cobra.Command, []string) error) func(*cobra.Comma
 	}
 }
 
+// activeProvider is the name of the cloud provider set in config
+var activeProvider = cloud.DefaultProvider
+
 func initAwlessEnvHook(cmd *cobra.Command, args []string) error {
 	if err := config.InitAwlessEnv(); err != nil {
 		return fmt.Errorf("cannot init awless environment: %s", err)
 	}
+	if err := config.LoadProjectConfig(); err != nil {
+		return fmt.Errorf("cannot load project config: %s", err)
+	}
+	defaults, err := config.LoadDefaults()
+	if err != nil {
+		return fmt.Errorf("cannot load config: %s", err)
+	}
+	activeProvider = cloud.DefaultProvider
+	if name, ok := defaults[cloud.ProviderKey].(string); ok && name != "" {
+		activeProvider = name
+	}
+	if activeProvider != cloud.DefaultProvider {
+		if err := config.SetProviderRepoDir(activeProvider); err != nil {
+			return fmt.Errorf("cannot init local graphs directory: %s", err)
+		}
+	}
+	if err := initStateEncryption(defaults); err != nil {
+		return fmt.Errorf("cannot init local state encryption: %s", err)
+	}
+	if enabled, ok := defaults[config.ColorKey].(bool); ok && !enabled {
+		color.NoColor = true
+	}
+	if format, ok := defaults[sync.GraphFormatKey].(string); ok && format != "" {
+		sync.GraphFormat = format
+	}
+	if enabled, ok := defaults[aws.CredentialsCacheKey].(bool); !ok || enabled {
+		aws.CredentialsCacheDir = filepath.Join(config.AwlessHome, "cache", "credentials")
+	}
+	if err := aws.ConfigureEndpoints(defaults); err != nil {
+		return fmt.Errorf("cannot configure AWS endpoints: %s", err)
+	}
+	if err := aws.ConfigureRetries(defaults); err != nil {
+		return fmt.Errorf("cannot configure AWS retries: %s", err)
+	}
+	return nil
+}
+
+// requireAWSProviderHook refuses the commands only working with AWS when another cloud provider is set
+func requireAWSProviderHook(cmd *cobra.Command, args []string) error {
+	if activeProvider != cloud.DefaultProvider {
+		return fmt.Errorf("%s is only available with the %s provider (current provider: %s)", cmd.CommandPath(), cloud.DefaultProvider, activeProvider)
+	}
 	return nil
 }
 
@@ -50,21 +104,95 @@ func initCloudServicesHook(cmd *cobra.Command, args []string) error {
 	if localFlag {
 		return nil
 	}
-	db, err, dbclose := database.Current()
+	defaults, err := config.LoadDefaults()
+	if err != nil {
+		return fmt.Errorf("init cloud service: %s", err)
+	}
+	name, _ := defaults[cloud.ProviderKey].(string)
+	provider, err := cloud.GetProvider(name)
 	if err != nil {
-		return fmt.Errorf("init cloud service: database error: %s", err)
+		return fmt.Errorf("init cloud service: %s", err)
+	}
+	if provider.Name() == cloud.DefaultProvider {
+		if accountFlag != "" {
+			role, _ := defaults[aws.AccountRoleKey].(string)
+			aws.RoleOverride = &aws.AssumeRole{Arn: aws.AccountRoleArn(accountFlag, role), ExternalID: externalIDFlag}
+		}
+		logger.Verbosef("target: %s", describeAWSTarget(defaults))
 	}
-	profile, _ := db.GetDefaultString(database.ProfileKey)
-	region := db.MustGetDefaultRegion()
-	dbclose()
 
-	if err := aws.InitServices(region, profile); err != nil {
+	if err := provider.InitServices(defaults); err != nil {
 		return err
 	}
 
+	registerKubernetesService(defaults)
 	return nil
 }
 
+// describeAWSTarget returns the effective profile, region and assumed role of cloud commands
+func describeAWSTarget(defaults map[string]interface{}) string {
+	profile, _ := defaults[database.ProfileKey].(string)
+	if profile == "" {
+		profile = "(default)"
+	}
+	region, _ := defaults[database.RegionKey].(string)
+	target := fmt.Sprintf("profile %s, region %s", profile, region)
+	if aws.RoleOverride != nil {
+		target += fmt.Sprintf(", assuming %s", aws.RoleOverride.Arn)
+	}
+	if endpoints := aws.Endpoints.String(); endpoints != "" {
+		target += fmt.Sprintf(", endpoints %s", endpoints)
+	}
+	return target
+}
+
+const kubernetesServiceName = "kubernetes"
+
+// registerKubernetesService adds the inventory of the kubeconfig contexts listed in config.
+// Kubernetes errors are reported without preventing cloud commands to run
+func registerKubernetesService(defaults map[string]interface{}) {
+	contexts, ok := defaults[kubernetes.ContextsKey]
+	if !ok || fmt.Sprint(contexts) == "" {
+		return
+	}
+	path := kubernetes.DefaultKubeconfigPath()
+	if p, ok := defaults[kubernetes.KubeconfigKey].(string); ok && p != "" {
+		path = p
+	}
+	srv, err := kubernetes.NewService(path, strings.Split(fmt.Sprint(contexts), ","), loadBalancerByDNSName)
+	if err != nil {
+		logger.Errorf("kubernetes: %s", err)
+		return
+	}
+	cloud.ServiceRegistry[srv.Name()] = srv
+}
+
+// loadBalancerByDNSName resolves load balancers in the local infra graph
+func loadBalancerByDNSName(dnsName string) (string, bool) {
+	lbs, err := sync.LoadCurrentLocalGraph("infra").FindResourcesByProperty("DNSName", dnsName)
+	if err != nil || len(lbs) == 0 {
+		return "", false
+	}
+	return lbs[0].Id(), true
+}
+
+// currentRegionAndProfile returns the region and profile from the config,
+// overridden by the current context and the project config if any
+func currentRegionAndProfile() (string, string, error) {
+	defaults, err := config.LoadDefaults()
+	if err != nil {
+		return "", "", err
+	}
+
+	profile, _ := defaults[database.ProfileKey].(string)
+	region, _ := defaults[database.RegionKey].(string)
+	if region == "" {
+		return "", "", errors.New("missing region. Set it with `awless config set region`")
+	}
+
+	return region, profile, nil
+}
+
 func initConfigStruct(cmd *cobra.Command, args []string) error {
 	return config.LoadConfig()
 }
@@ -72,6 +200,14 @@ func initConfigStruct(cmd *cobra.Command, args []string) error {
 func initSyncerHook(cmd *cobra.Command, args []string) error {
 	sync.DefaultSyncer = sync.NewSyncer()
 	sync.DefaultSyncer.SetLogger(logger.DefaultLogger)
+	defaults, err := config.LoadDefaults()
+	if err != nil {
+		return fmt.Errorf("init syncer: %s", err)
+	}
+	if b := backend.FromConfig(defaults); b != nil {
+		region, _ := defaults[database.RegionKey].(string)
+		sync.DefaultSyncer = &backendSyncer{Syncer: sync.DefaultSyncer, backend: b, region: region}
+	}
 	return nil
 }
 
@@ -92,12 +228,18 @@ func saveHistoryHook(cmd *cobra.Command, args []string) error {
 	db, err, close := database.Current()
 	if err == nil && db != nil {
 		db.AddHistoryCommand(append(strings.Split(cmd.CommandPath(), " "), args...))
+		if usageStatsEnabled(db) {
+			db.AddCommandUsage(cmd.CommandPath())
+		}
 		defer close()
 	}
 	return nil
 }
 
 func verifyNewVersionHook(cmd *cobra.Command, args []string) error {
+	if quietFlag {
+		return nil
+	}
 	config.VerifyNewVersionAvailable("https://updates.awless.io", os.Stderr)
 	return nil
 }
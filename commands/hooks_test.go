@@ -0,0 +1,46 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/wallix/awless/cloud"
+)
+
+func TestRequireAWSProviderHook(t *testing.T) {
+	defer func() { activeProvider = cloud.DefaultProvider }()
+
+	for _, cmd := range []string{"tag", "whoami"} {
+		c, _, err := RootCmd.Find([]string{cmd})
+		if err != nil {
+			t.Fatal(err)
+		}
+		activeProvider = cloud.DefaultProvider
+		if err := requireAWSProviderHook(c, nil); err != nil {
+			t.Fatalf("%s: %s", cmd, err)
+		}
+		activeProvider = "gcp"
+		err = requireAWSProviderHook(c, nil)
+		if err == nil {
+			t.Fatalf("%s: expected error", cmd)
+		}
+		if got, want := err.Error(), "awless "+cmd+" is only available with the aws provider (current provider: gcp)"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	}
+}
@@ -19,12 +19,16 @@ package commands
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
 	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/config"
 	"github.com/wallix/awless/graph"
 	"github.com/wallix/awless/inspect"
+	"github.com/wallix/awless/inspect/inspectors"
 	"github.com/wallix/awless/logger"
 	"github.com/wallix/awless/sync"
 )
@@ -42,12 +46,17 @@ func init() {
 var inspectCmd = &cobra.Command{
 	Use: "inspect",
 	Short: fmt.Sprintf(
-		"Inspecting your infrastructure using available inspectors below: %s", allInspectors(),
+		"Inspecting your infrastructure using available inspectors below: %s (and executables in %s)", allInspectors(), config.InspectorsDir,
 	),
 	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook, initSyncerHook, verifyNewVersionHook),
 	PersistentPostRunE: saveHistoryHook,
 
 	RunE: func(c *cobra.Command, args []string) error {
+		if err := inspect.LoadExternalInspectors(config.InspectorsDir, aws.ServiceNames); err != nil {
+			logger.Errorf("loading inspectors from %s: %s", config.InspectorsDir, err)
+		}
+		inspect.Register(&inspectors.Pricer{Prices: aws.PricingAPI})
+
 		inspector, ok := inspect.InspectorsRegister[inspectorFlag]
 		if !ok {
 			return fmt.Errorf("command needs a valid inspector: %s", allInspectors())
@@ -74,7 +83,8 @@ var inspectCmd = &cobra.Command{
 				logger.Error(err)
 			}
 
-			for _, g := range graphPerService {
+			for name, g := range graphPerService {
+				loadAllLazyProperties(g, name)
 				graphs = append(graphs, g)
 			}
 		}
@@ -93,5 +103,6 @@ func allInspectors() string {
 	for name := range inspect.InspectorsRegister {
 		all = append(all, name)
 	}
+	sort.Strings(all)
 	return strings.Join(all, ", ")
 }
@@ -0,0 +1,68 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/sync"
+)
+
+// loadLazyProperties loads the properties not synced as expensive to fetch (ex: bucket ACLs) of the given
+// resources of a graph. When the graph is the local one of a service (i.e. srvName not empty), the properties
+// are cached in it until the next sync. Nothing is fetched offline
+func loadLazyProperties(g *graph.Graph, srvName string, resources ...*graph.Resource) {
+	if localFlag || len(resources) == 0 {
+		return
+	}
+	updated, err := aws.LoadLazyProperties(resources...)
+	if err != nil {
+		logger.Verbosef("lazy properties: %s", err)
+	}
+	if len(updated) == 0 {
+		return
+	}
+	for _, res := range updated {
+		for _, name := range aws.LazyProperties(res.Type()) {
+			if v, ok := res.Properties[name]; ok {
+				if err := g.SetResourceProperty(res, name, v); err != nil {
+					logger.Verbosef("lazy properties: %s", err)
+				}
+			}
+		}
+	}
+	if srvName != "" {
+		if err := sync.SaveLocalGraph(srvName, g); err != nil {
+			logger.Verbosef("cannot cache properties in local %s resources: %s", srvName, err)
+		}
+	}
+}
+
+// loadAllLazyProperties loads the lazy properties of all the resources of a graph
+func loadAllLazyProperties(g *graph.Graph, srvName string) {
+	var resources []*graph.Resource
+	for _, t := range aws.LazyResourceTypes() {
+		res, err := g.GetAllResources(t)
+		if err != nil {
+			logger.Verbosef("lazy properties: %s", err)
+			continue
+		}
+		resources = append(resources, res...)
+	}
+	loadLazyProperties(g, srvName, resources...)
+}
@@ -23,16 +23,22 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/wallix/awless/aws"
 	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/config"
 	"github.com/wallix/awless/console"
 	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/kubernetes"
+	"github.com/wallix/awless/logger"
 	"github.com/wallix/awless/sync"
 )
 
 var (
 	listingFormat      string
 	listingFiltersFlag []string
+	listingTagsFlag    []string
 	listOnlyIDs        bool
 	sortBy             []string
+	listRegionGroup    string
+	listNoTruncate     bool
 )
 
 func init() {
@@ -46,16 +52,23 @@ func init() {
 		listCmd.AddCommand(listSpecificResourceCmd(resType))
 	}
 
+	for _, resType := range kubernetes.ResourceTypes {
+		listCmd.AddCommand(listSpecificResourceCmd(resType))
+	}
+
 	listCmd.PersistentFlags().StringVar(&listingFormat, "format", "table", "Format for the display of resources: table or csv")
 	listCmd.PersistentFlags().StringSliceVar(&listingFiltersFlag, "filter", []string{}, "Filter resources given key/values fields. Ex: --filter type=t2.micro")
+	listCmd.PersistentFlags().StringSliceVar(&listingTagsFlag, "tag", []string{}, "Filter resources having exactly the given tag key=value (or tag key). Ex: --tag Env=prod")
 	listCmd.PersistentFlags().BoolVar(&listOnlyIDs, "ids", false, "List only ids")
 	listCmd.PersistentFlags().StringSliceVar(&sortBy, "sort", []string{"Id"}, "Sort tables by column(s) name(s)")
+	listCmd.PersistentFlags().StringVar(&listRegionGroup, "region-group", "", "List resources across all regions of a group defined in config. Ex: --region-group emea")
+	listCmd.PersistentFlags().BoolVar(&listNoTruncate, "no-trunc", false, "Do not truncate nor hide table columns")
 }
 
 var listCmd = &cobra.Command{
 	Use:                "list",
 	Aliases:            []string{"ls"},
-	PersistentPreRun:   applyHooks(initAwlessEnvHook, initCloudServicesHook, verifyNewVersionHook),
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook, verifyNewVersionHook),
 	PersistentPostRunE: saveHistoryHook,
 	Short:              "List various type of resources",
 }
@@ -63,14 +76,27 @@ var listCmd = &cobra.Command{
 var listSpecificResourceCmd = func(resType string) *cobra.Command {
 	return &cobra.Command{
 		Use:   cloud.PluralizeResource(resType),
-		Short: fmt.Sprintf("List AWS %s", cloud.PluralizeResource(resType)),
+		Short: fmt.Sprintf("List %s %s", platformOf(resType), cloud.PluralizeResource(resType)),
 
 		Run: func(cmd *cobra.Command, args []string) {
 			var g *graph.Graph
 
+			if listRegionGroup != "" {
+				if localFlag {
+					exitOn(fmt.Errorf("--region-group cannot be used with --local"))
+				}
+				var err error
+				g, err = fetchByTypeInRegionGroup(resType, listRegionGroup)
+				exitOn(err)
+				printResources(g, graph.ResourceType(resType), withRegionColumn(console.DefaultsColumnDefinitions[graph.ResourceType(resType)])...)
+				return
+			}
+
 			if localFlag {
 				if srvName, ok := aws.ServicePerResourceType[resType]; ok {
 					g = sync.LoadCurrentLocalGraph(srvName)
+				} else if platformOf(resType) == "Kubernetes" {
+					g = sync.LoadCurrentLocalGraph(kubernetesServiceName)
 				} else {
 					exitOn(fmt.Errorf("cannot find service for resource type %s", resType))
 				}
@@ -79,6 +105,7 @@ var listSpecificResourceCmd = func(resType string) *cobra.Command {
 				exitOn(err)
 				g, err = srv.FetchByType(resType)
 				exitOn(err)
+				loadAllLazyProperties(g, "")
 			}
 
 			printResources(g, graph.ResourceType(resType))
@@ -102,16 +129,40 @@ var listAllResourceInServiceCmd = func(srvName string) *cobra.Command {
 	}
 }
 
-func printResources(g *graph.Graph, resType graph.ResourceType) {
+func platformOf(resType string) string {
+	for _, t := range kubernetes.ResourceTypes {
+		if t == resType {
+			return "Kubernetes"
+		}
+	}
+	return "AWS"
+}
+
+func printResources(g *graph.Graph, resType graph.ResourceType, headers ...console.ColumnDefinition) {
+	if len(headers) == 0 {
+		headers = console.DefaultsColumnDefinitions[resType]
+	}
 	displayer := console.BuildOptions(
 		console.WithRdfType(resType),
-		console.WithHeaders(console.DefaultsColumnDefinitions[resType]),
+		console.WithHeaders(headers),
 		console.WithFilters(listingFiltersFlag),
+		console.WithTagFilters(listingTagsFlag),
 		console.WithMaxWidth(console.GetTerminalWidth()),
 		console.WithFormat(listingFormat),
 		console.WithIDsOnly(listOnlyIDs),
 		console.WithSortBy(sortBy...),
+		console.WithNoTruncate(listNoTruncate),
+		console.WithColumnsMaxWidth(listColumnsMaxWidth()),
 	).SetSource(g).Build()
 
 	exitOn(displayer.Print(os.Stdout))
 }
+
+func listColumnsMaxWidth() map[string]int {
+	defaults, err := config.LoadDefaults()
+	if err != nil {
+		logger.Verbosef("cannot load column widths from config: %s", err)
+		return nil
+	}
+	return config.TableColumnsMaxWidth(defaults)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/lock"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template"
+)
+
+var forceUnlockFlag bool
+
+func init() {
+	RootCmd.AddCommand(lockCmd)
+	lockCmd.AddCommand(lockStatusCmd)
+	lockCmd.AddCommand(lockForceUnlockCmd)
+
+	lockForceUnlockCmd.Flags().BoolVarP(&forceUnlockFlag, "force", "f", false, "Unlock without confirmation")
+}
+
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: fmt.Sprintf("Template runs lock the current account and region when a DynamoDB table is set in config (ex: awless config set %s awless-locks)", lock.TableKey),
+}
+
+var lockStatusCmd = &cobra.Command{
+	Use:                "status",
+	Short:              "Show who holds the lock on the current account and region",
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initConfigStruct, initCloudServicesHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		table, key, err := configuredLock()
+		exitOn(err)
+		l, err := table.Get(key)
+		exitOn(err)
+		if l == nil {
+			fmt.Printf("%s is not locked\n", key)
+			return nil
+		}
+		fmt.Println(l)
+		return nil
+	},
+}
+
+var lockForceUnlockCmd = &cobra.Command{
+	Use:                "force-unlock",
+	Short:              "Release the lock on the current account and region whoever holds it. Ex: after a crashed run",
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initConfigStruct, initCloudServicesHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		table, key, err := configuredLock()
+		exitOn(err)
+		l, err := table.Get(key)
+		exitOn(err)
+		if l == nil {
+			fmt.Printf("%s is not locked\n", key)
+			return nil
+		}
+		if !forceUnlockFlag {
+			if ciFlag {
+				return fmt.Errorf("CI mode: use --force to unlock %s", key)
+			}
+			fmt.Println(l)
+			fmt.Print("Force unlock? (y/n): ")
+			var yesorno string
+			fmt.Scanln(&yesorno)
+			if strings.TrimSpace(yesorno) != "y" {
+				return nil
+			}
+		}
+		exitOn(table.ForceRelease(key))
+		logger.Infof("%s unlocked", key)
+		return nil
+	},
+}
+
+func configuredLock() (*lock.DynamoDB, string, error) {
+	table, err := lock.FromConfig(config.Config.Defaults)
+	if err != nil {
+		return nil, "", err
+	}
+	if table == nil {
+		return nil, "", fmt.Errorf("no lock table configured: set %s", lock.TableKey)
+	}
+	account := currentAccount()
+	if account == "" {
+		return nil, "", fmt.Errorf("cannot resolve the current account to lock")
+	}
+	return table, lock.Key(account, configRegion()), nil
+}
+
+// acquireRunLock locks the current account and region for the template run when a lock
+// table is configured. The returned func releases the lock
+func acquireRunLock(tpl *template.Template) (func(), error) {
+	if _, ok := config.Config.Defaults[lock.TableKey]; !ok {
+		return func() {}, nil
+	}
+	timeout, err := lock.Timeout(config.Config.Defaults)
+	if err != nil {
+		return nil, err
+	}
+	table, key, err := configuredLock()
+	if err != nil {
+		return nil, err
+	}
+
+	var who string
+	if aws.SecuAPI != nil {
+		who, _ = aws.SecuAPI.GetUserId()
+	}
+	l := lock.New(currentAccount(), configRegion(), who, "run "+templateName(tpl), timeout)
+	if err := table.Acquire(l); err != nil {
+		return nil, err
+	}
+	logger.Verbosef("locked %s", key)
+
+	return func() {
+		if err := table.Release(l); err != nil {
+			logger.Warningf("cannot release lock: %s", err)
+		} else {
+			logger.Verbosef("unlocked %s", key)
+		}
+	}, nil
+}
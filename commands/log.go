@@ -43,7 +43,7 @@ func init() {
 var logCmd = &cobra.Command{
 	Use:                "log",
 	Short:              "Logs all executions done against your cloud",
-	PersistentPreRun:   applyHooks(initAwlessEnvHook),
+	PersistentPreRun:   applyHooks(initAwlessEnvHook, initConfigStruct),
 	PersistentPostRunE: saveHistoryHook,
 
 	RunE: func(c *cobra.Command, args []string) error {
@@ -58,6 +58,7 @@ var logCmd = &cobra.Command{
 		all, err := db.ListTemplateExecutions()
 		dbclose()
 		exitOn(err)
+		all = listExecutions(all)
 
 		for _, templ := range all {
 			var buff bytes.Buffer
@@ -85,6 +86,12 @@ func formatForMachine(buff *bytes.Buffer, templ *template.TemplateExecution) {
 	} else {
 		buff.WriteString("<not revertible>")
 	}
+	if c := templ.Caller; c != nil {
+		for _, field := range []string{templ.Reverted, c.Arn, c.Account, c.SourceIP, c.Version} {
+			buff.WriteRune(sep)
+			buff.WriteString(field)
+		}
+	}
 	buff.WriteByte('\n')
 	for _, done := range templ.Executed {
 		if done.Err != "" {
@@ -114,6 +121,12 @@ func formatForHuman(buff *bytes.Buffer, templ *template.TemplateExecution) {
 	}
 
 	fmt.Printf("Date: %s\n", parseULIDDate(templ.ID))
+	if c := templ.Caller; c != nil {
+		fmt.Printf("Caller: %s\n", formatCaller(c))
+	}
+	if templ.Reverted != "" {
+		fmt.Printf("Reverts: %s\n", templ.Reverted)
+	}
 	if templ.IsRevertible() {
 		fmt.Printf("Revert id: %s\n", templ.ID)
 	} else {
@@ -121,6 +134,22 @@ func formatForHuman(buff *bytes.Buffer, templ *template.TemplateExecution) {
 	}
 }
 
+func formatCaller(c *template.Caller) string {
+	var buff bytes.Buffer
+	if c.Arn != "" {
+		buff.WriteString(c.Arn)
+	} else {
+		buff.WriteString("<unknown>")
+	}
+	if c.SourceIP != "" {
+		buff.WriteString(fmt.Sprintf(" from %s", c.SourceIP))
+	}
+	if c.Version != "" {
+		buff.WriteString(fmt.Sprintf(" (awless %s)", c.Version))
+	}
+	return buff.String()
+}
+
 func parseULIDDate(uid string) string {
 	parsed, err := ulid.Parse(uid)
 	exitOn(err)
@@ -0,0 +1,292 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/archive"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/console"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/notify"
+	"github.com/wallix/awless/sync"
+	"github.com/wallix/awless/template"
+)
+
+var driftActorsFlag bool
+
+func init() {
+	RootCmd.AddCommand(notifyCmd)
+	notifyCmd.AddCommand(notifyTestCmd)
+	notifyCmd.AddCommand(notifyDriftCmd)
+
+	notifyDriftCmd.Flags().BoolVar(&driftActorsFlag, "actors", true, "Resolve with CloudTrail who last created, deleted or modified the resources")
+}
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Send awless activity to the Slack, webhook, SNS, Datadog, PagerDuty and email notifiers set in config (ex: awless config set notify.webhook.urls https://example.com/hook)",
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:                "test",
+	Short:              "Send a test message to the configured notifiers",
+	PersistentPreRun:   applyHooks(initAwlessEnvHook, initConfigStruct),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		notifiers, err := configuredNotifiers()
+		exitOn(err)
+		exitOn(notify.Send(notifiers, &notify.Event{Type: notify.RunSucceeded, RunID: "test", Region: configRegion(), Context: currentContextName()}))
+		logger.Infof("test message sent to %d notifier(s)", len(notifiers))
+		return nil
+	},
+}
+
+var notifyDriftCmd = &cobra.Command{
+	Use:                "drift",
+	Short:              "Send a digest of the resources created, deleted and modified between the last two days of syncs. Ex: run daily from cron",
+	PersistentPreRun:   applyHooks(initAwlessEnvHook, initConfigStruct, initSyncerHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		notifiers, err := configuredNotifiers()
+		exitOn(err)
+
+		revs, err := sync.DefaultSyncer.List()
+		exitOn(err)
+		if len(revs) < 2 {
+			return errors.New("not enough sync revisions to compute a drift: at least 2 days of syncs are needed")
+		}
+		from, err := sync.DefaultSyncer.LoadRev(revs[len(revs)-2].Id)
+		exitOn(err)
+		to, err := sync.DefaultSyncer.LoadRev(revs[len(revs)-1].Id)
+		exitOn(err)
+
+		report, err := driftReport(from.Infra, to.Infra, aws.ServicePerResourceType[graph.Instance.String()])
+		exitOn(err)
+		accessReport, err := driftReport(from.Access, to.Access, aws.ServicePerResourceType[graph.User.String()])
+		exitOn(err)
+		report.Created = append(report.Created, accessReport.Created...)
+		report.Deleted = append(report.Deleted, accessReport.Deleted...)
+		report.Modified = append(report.Modified, accessReport.Modified...)
+
+		event := &notify.Event{Type: notify.Drift, Region: configRegion(), Context: currentContextName(), Drift: report}
+		if driftActorsFlag && !report.Empty() {
+			if err := resolveDriftActors(event, revs[len(revs)-2].Date, from.Infra, from.Access, to.Infra, to.Access); err != nil {
+				logger.Warningf("cannot resolve from CloudTrail who changed the resources: %s", err)
+			}
+		}
+		exitOn(notify.Send(notifiers, event))
+		printDriftReport(os.Stdout, event.Drift)
+		logger.Info(event.Summary())
+		return nil
+	},
+}
+
+// resolveDriftActors sets the account of the drift event and, from the CloudTrail write events
+// since the given time, who last changed each drifted resource. IAM events being recorded
+// in us-east-1, it is also looked up for access resources
+func resolveDriftActors(event *notify.Event, since time.Time, graphs ...*graph.Graph) error {
+	profile, _ := config.Config.Defaults[database.ProfileKey].(string)
+	regions := []string{event.Region}
+	for _, list := range [][]notify.Resource{event.Drift.Created, event.Drift.Deleted, event.Drift.Modified} {
+		for _, r := range list {
+			if aws.ServicePerResourceType[r.Type] == aws.ServicePerResourceType[graph.User.String()] && event.Region != "us-east-1" {
+				regions = append(regions, "us-east-1")
+				break
+			}
+		}
+		if len(regions) > 1 {
+			break
+		}
+	}
+
+	actors := make(map[string]string)
+	for i, region := range regions {
+		sess, err := aws.InitSession(region, profile)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			if event.Account, err = aws.NewSecu(sess).GetAccountId(); err != nil {
+				logger.Verbosef("cannot resolve current account: %s", err)
+			}
+		}
+		found, err := aws.NewCloudTrail(sess).Actors(since)
+		if err != nil {
+			return err
+		}
+		for name, user := range found {
+			if _, ok := actors[name]; !ok {
+				actors[name] = user
+			}
+		}
+	}
+
+	for _, list := range [][]notify.Resource{event.Drift.Created, event.Drift.Deleted, event.Drift.Modified} {
+		for i, r := range list {
+			if user, ok := actors[r.Id]; ok {
+				list[i].By = user
+				continue
+			}
+			// CloudTrail references some resources (ex: IAM users, buckets) by name
+			for _, g := range graphs {
+				if res, err := g.GetResource(graph.ResourceType(r.Type), r.Id); err == nil {
+					if name, ok := res.Properties["Name"].(string); ok && actors[name] != "" {
+						list[i].By = actors[name]
+						break
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// printDriftReport writes the drifted resources, with the property diffs of the modified ones
+func printDriftReport(w io.Writer, report *notify.DriftReport) {
+	for _, l := range []struct {
+		marker    string
+		resources []notify.Resource
+	}{{"+", report.Created}, {"-", report.Deleted}, {"~", report.Modified}} {
+		for _, r := range l.resources {
+			label := fmt.Sprintf("%s %s", r.Type, r.Id)
+			if r.By != "" {
+				label += " by " + r.By
+			}
+			console.WriteResourceChanges(w, l.marker, label, r.Changes)
+		}
+	}
+}
+
+func configuredNotifiers() ([]notify.Notifier, error) {
+	notifiers := notify.FromConfig(config.Config.Defaults)
+	if len(notifiers) == 0 {
+		return nil, fmt.Errorf("no notifier configured. Set one of %s, %s, %s, %s, %s or %s", notify.SlackWebhookKey, notify.WebhookURLsKey, notify.SNSTopicKey, notify.DatadogAPIKeyKey, notify.PagerDutyRoutingKeyKey, notify.EmailToKey)
+	}
+	return notifiers, nil
+}
+
+func driftReport(from, to *graph.Graph, serviceName string) (*notify.DriftReport, error) {
+	var types []string
+	for t, srv := range aws.ServicePerResourceType {
+		if srv == serviceName {
+			types = append(types, t)
+		}
+	}
+	return notify.NewDriftReport(from, to, types...)
+}
+
+var runTemplateName string
+
+// templateName is the name of the template file being run, or its statements
+// for inline commands. Ex: create_instance+attach_volume
+func templateName(tpl *template.Template) string {
+	if runTemplateName != "" {
+		return runTemplateName
+	}
+	var names []string
+	seen := make(map[string]bool)
+	for _, cmd := range tpl.CommandNodesIterator() {
+		if name := cmd.Action + "_" + cmd.Entity; !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "+")
+}
+
+var accountID string
+
+// currentAccount returns the account of the current credentials, or an empty string
+// when the cloud services are not initialized
+func currentAccount() string {
+	if accountID == "" && aws.SecuAPI != nil {
+		id, err := aws.SecuAPI.GetAccountId()
+		if err != nil {
+			logger.Verbosef("cannot resolve current account: %s", err)
+		}
+		accountID = id
+	}
+	return accountID
+}
+
+func configRegion() string {
+	region, _ := config.Config.Defaults[database.RegionKey].(string)
+	return region
+}
+
+// notifyRun completes the run lifecycle event with the template execution and sends it
+// to the configured notifiers, logging failures
+func notifyRun(event *notify.Event, tpl *template.Template, runErr error) {
+	notifiers := notify.FromConfig(config.Config.Defaults)
+	if len(notifiers) == 0 {
+		return
+	}
+	region := configRegion()
+	event.Template, event.Region, event.Context = archive.RedactTemplate(tpl), region, currentContextName()
+	event.TemplateName, event.Account = templateName(tpl), currentAccount()
+	event.Unattended = config.NonInteractive
+	if event.Type != notify.RunStarted {
+		cmds := tpl.CommandNodesIterator()
+		for i, done := range template.NewTemplateExecution(tpl).Executed {
+			line := archive.Redact(cmds[i]).String()
+			switch {
+			case done.Err != "":
+				event.Log = append(event.Log, fmt.Sprintf("KO %s: %s", line, done.Err))
+			case done.Result != "":
+				event.Log = append(event.Log, fmt.Sprintf("OK %s <- %s", line, done.Result))
+			default:
+				event.Log = append(event.Log, fmt.Sprintf("OK %s", line))
+			}
+		}
+		for _, cmd := range tpl.CommandNodesIterator() {
+			if cmd.CmdErr != nil {
+				if cmd.CmdErr == runErr {
+					runErr = nil
+				}
+				event.Errors = append(event.Errors, fmt.Sprintf("%s %s: %s", cmd.Action, cmd.Entity, cmd.CmdErr))
+			}
+			id, ok := cmd.CmdResult.(string)
+			if cmd.Action != "create" || !ok || id == "" {
+				continue
+			}
+			created := notify.Resource{Id: id, Type: cmd.Entity}
+			if link, err := aws.ConsoleURL(graph.InitResource(id, graph.ResourceType(cmd.Entity)), region); err == nil {
+				created.Link = link
+			}
+			event.Created = append(event.Created, created)
+		}
+	}
+	if runErr != nil {
+		event.Errors = append(event.Errors, runErr.Error())
+	}
+	if err := notify.Send(notifiers, event); err != nil {
+		logger.Errorf("notify: %s", err)
+	}
+}
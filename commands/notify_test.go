@@ -0,0 +1,78 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/notify"
+	"github.com/wallix/awless/template"
+)
+
+func TestNotifyRunRedactsSecrets(t *testing.T) {
+	var payload string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		payload = string(b)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "awless-notify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer os.Setenv("__AWLESS_HOME", os.Getenv("__AWLESS_HOME"))
+	os.Setenv("__AWLESS_HOME", dir)
+	config.FlagOverrides[notify.WebhookURLsKey] = server.URL
+	defer delete(config.FlagOverrides, notify.WebhookURLsKey)
+	if err = config.LoadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { config.Config = nil }()
+
+	tpl, err := template.Parse("create user name=john password=s3cr3t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl.CommandNodesIterator()[0].CmdResult = "john"
+	notifyRun(&notify.Event{Type: notify.RunSucceeded, RunID: "01B"}, tpl, nil)
+
+	if strings.Contains(payload, "s3cr3t") {
+		t.Fatalf("secret in payload: %s", payload)
+	}
+	var event notify.Event
+	if err = json.Unmarshal([]byte(payload), &event); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := event.Template, `password="<redacted>"`; !strings.Contains(got, want) {
+		t.Fatalf("got %s, want %s in it", got, want)
+	}
+	if got, want := len(event.Log), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := event.Log[0], `password="<redacted>"`; !strings.Contains(got, want) {
+		t.Fatalf("got %s, want %s in it", got, want)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/wallix/awless/aws"
+)
+
+const ageIdentityEnv = "AWLESS_AGE_IDENTITY"
+
+var (
+	ageHeaders = [][]byte{[]byte("age-encryption.org/"), []byte("-----BEGIN AGE ENCRYPTED FILE-----")}
+	pgpHeader  = []byte("-----BEGIN PGP MESSAGE-----")
+)
+
+// kmsDecrypt decrypts KMS ciphertext blobs, overridden in tests
+var kmsDecrypt = func(blob []byte) ([]byte, error) {
+	if aws.KMSAPI == nil {
+		return nil, errors.New("AWS services not initialized")
+	}
+	return aws.KMSAPI.Decrypt(blob)
+}
+
+// decryptParamsFile transparently decrypts params files encrypted with age, GPG or AWS KMS
+// so that secrets filling template holes can live in version control. Plain files are returned as is.
+// age and GPG files are recognized by their headers, KMS ones by their '.encrypted' or '.kms' extension
+// (ex: output of 'aws kms encrypt --plaintext fileb://prod.yaml', raw or base64)
+func decryptParamsFile(path string, content []byte) ([]byte, error) {
+	ext := filepath.Ext(path)
+	for _, header := range ageHeaders {
+		if bytes.HasPrefix(content, header) {
+			identity := os.Getenv(ageIdentityEnv)
+			if identity == "" {
+				return nil, fmt.Errorf("age encrypted params file: export %s with the path of your age identity file", ageIdentityEnv)
+			}
+			return runDecryptCommand(content, "age", "--decrypt", "--identity", identity)
+		}
+	}
+	if bytes.HasPrefix(content, pgpHeader) || ext == ".gpg" {
+		return runDecryptCommand(content, "gpg", "--quiet", "--decrypt")
+	}
+	if ext == ".encrypted" || ext == ".kms" {
+		blob := content
+		if decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(content))); err == nil {
+			blob = decoded
+		}
+		plain, err := kmsDecrypt(blob)
+		if err != nil {
+			return nil, fmt.Errorf("KMS decrypt: %s", err)
+		}
+		return plain, nil
+	}
+	return content, nil
+}
+
+// runDecryptCommand pipes the content to the decryption tool. Stderr is left to the user's terminal
+// for tools prompting for passphrases (ex: GPG pinentry)
+func runDecryptCommand(content []byte, name string, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, fmt.Errorf("cannot decrypt params file: '%s' not found in PATH", name)
+	}
+	var stdout bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s decrypt: %s", name, err)
+	}
+	return stdout.Bytes(), nil
+}
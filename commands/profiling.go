@@ -0,0 +1,69 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"os"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/wallix/awless/logger"
+)
+
+var cpuProfile *os.File
+
+// startProfiling starts the CPU profile of the command if requested with --profile-cpu
+func startProfiling() {
+	if profileCPUFlag == "" {
+		return
+	}
+	f, err := os.Create(profileCPUFlag)
+	if err != nil {
+		exitOn(err)
+	}
+	if err = pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		exitOn(err)
+	}
+	cpuProfile = f
+}
+
+// stopProfiling writes the CPU and memory profiles requested with --profile-cpu and --profile-mem
+func stopProfiling() {
+	if cpuProfile != nil {
+		pprof.StopCPUProfile()
+		cpuProfile.Close()
+		logger.Verbosef("CPU profile written to %s", cpuProfile.Name())
+		cpuProfile = nil
+	}
+	if profileMemFlag == "" {
+		return
+	}
+	f, err := os.Create(profileMemFlag)
+	if err != nil {
+		logger.Errorf("memory profile: %s", err)
+		return
+	}
+	defer f.Close()
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		logger.Errorf("memory profile: %s", err)
+		return
+	}
+	logger.Verbosef("memory profile written to %s", f.Name())
+	profileMemFlag = ""
+}
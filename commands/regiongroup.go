@@ -0,0 +1,113 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	stdsync "sync"
+
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/console"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/graph"
+)
+
+const regionColumn = "Region"
+
+// fetchByTypeInRegionGroup fetches concurrently the resources of a type in every
+// region of a group and merges them in a graph, each resource tagged with its region
+func fetchByTypeInRegionGroup(resType, group string) (*graph.Graph, error) {
+	srvName, ok := aws.ServicePerResourceType[resType]
+	if !ok {
+		return nil, fmt.Errorf("cannot find service for resource type %s", resType)
+	}
+	if srvName == "access" {
+		return nil, fmt.Errorf("%s are global resources: region groups do not apply", resType)
+	}
+
+	defaults, err := config.LoadDefaults()
+	if err != nil {
+		return nil, err
+	}
+	regions, err := config.RegionGroup(defaults, group)
+	if err != nil {
+		return nil, err
+	}
+	profile, _ := defaults[database.ProfileKey].(string)
+
+	var wg stdsync.WaitGroup
+	var mu stdsync.Mutex
+	graphs := make(map[string]*graph.Graph)
+	var errs []string
+
+	for _, region := range regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			g, err := fetchByTypeInRegion(srvName, resType, region, profile)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %s", region, err))
+				return
+			}
+			graphs[region] = g
+		}(region)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return nil, fmt.Errorf("fetching %s in region group '%s':\n\t%s", resType, group, strings.Join(errs, "\n\t"))
+	}
+
+	return mergeRegionGraphs(graphs, graph.ResourceType(resType))
+}
+
+func fetchByTypeInRegion(srvName, resType, region, profile string) (*graph.Graph, error) {
+	srv, err := aws.NewServiceForRegion(srvName, region, profile)
+	if err != nil {
+		return nil, err
+	}
+	return srv.FetchByType(resType)
+}
+
+// mergeRegionGraphs merges the resources of a type found in each region, adding them a region property
+func mergeRegionGraphs(graphs map[string]*graph.Graph, resType graph.ResourceType) (*graph.Graph, error) {
+	merged := graph.NewGraph()
+	for region, g := range graphs {
+		resources, err := g.GetAllResources(resType)
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range resources {
+			res.Properties[regionColumn] = region
+			if err := merged.AddResource(res); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return merged, nil
+}
+
+func withRegionColumn(headers []console.ColumnDefinition) []console.ColumnDefinition {
+	withRegion := append([]console.ColumnDefinition{}, headers...)
+	return append(withRegion, console.StringColumnDefinition{Prop: regionColumn})
+}
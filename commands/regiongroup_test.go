@@ -0,0 +1,61 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/wallix/awless/graph"
+)
+
+func TestMergeRegionGraphs(t *testing.T) {
+	ireland := graph.NewGraph()
+	inst1 := graph.InitResource("inst_1", graph.Instance)
+	inst1.Properties["Id"] = "inst_1"
+	ireland.AddResource(inst1, graph.InitResource("vpc_1", graph.Vpc))
+
+	frankfurt := graph.NewGraph()
+	inst2 := graph.InitResource("inst_2", graph.Instance)
+	inst2.Properties["Id"] = "inst_2"
+	frankfurt.AddResource(inst2)
+
+	merged, err := mergeRegionGraphs(map[string]*graph.Graph{"eu-west-1": ireland, "eu-central-1": frankfurt}, graph.Instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instances, err := merged.GetAllResources(graph.Instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(instances), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	regions := make(map[string]interface{})
+	for _, inst := range instances {
+		regions[inst.Id()] = inst.Properties[regionColumn]
+	}
+	if got, want := regions["inst_1"], "eu-west-1"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := regions["inst_2"], "eu-central-1"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if vpcs, _ := merged.GetAllResources(graph.Vpc); len(vpcs) != 0 {
+		t.Fatalf("expected only instances in merged graph, got %d vpcs", len(vpcs))
+	}
+}
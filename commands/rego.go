@@ -0,0 +1,142 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/template"
+)
+
+// regoQuery is the set of messages denying the template
+const regoQuery = "data.awless.deny"
+
+type regoInput struct {
+	Region     string          `json:"region,omitempty"`
+	Profile    string          `json:"profile,omitempty"`
+	Statements []regoStatement `json:"statements"`
+}
+
+type regoStatement struct {
+	Line   string                 `json:"line"`
+	Action string                 `json:"action"`
+	Entity string                 `json:"entity"`
+	Params map[string]interface{} `json:"params"`
+	Refs   map[string]string      `json:"refs,omitempty"`
+}
+
+// opaEval evaluates the query with the Rego policy against the JSON input, overridden in tests
+var opaEval = func(policy string, input []byte, query string) ([]byte, error) {
+	if _, err := exec.LookPath("opa"); err != nil {
+		return nil, errors.New("'opa' not found in PATH. See https://www.openpolicyagent.org/docs/latest/#running-opa")
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("opa", "eval", "--format", "json", "--data", policy, "--stdin-input", query)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// checkRegoPolicy evaluates the compiled template against the Rego policy set in the config values, if any,
+// and refuses it when denied
+func checkRegoPolicy(tpl *template.Template, defaults map[string]interface{}) error {
+	policy, _ := defaults[config.RegoPolicyKey].(string)
+	if policy == "" {
+		return nil
+	}
+	if _, err := os.Stat(policy); err != nil {
+		return fmt.Errorf("rego policy: %s", err)
+	}
+	input, err := json.Marshal(newRegoInput(tpl, defaults))
+	if err != nil {
+		return err
+	}
+	out, err := opaEval(policy, input, regoQuery)
+	if err != nil {
+		return fmt.Errorf("rego policy %s: %s", policy, err)
+	}
+	denials, err := parseRegoDenials(out)
+	if err != nil {
+		return fmt.Errorf("rego policy %s: %s", policy, err)
+	}
+	if len(denials) > 0 {
+		return fmt.Errorf("denied by rego policy %s:\n\t%s", policy, strings.Join(denials, "\n\t"))
+	}
+	return nil
+}
+
+func newRegoInput(tpl *template.Template, defaults map[string]interface{}) *regoInput {
+	input := &regoInput{Statements: []regoStatement{}}
+	input.Region, _ = defaults[database.RegionKey].(string)
+	input.Profile, _ = defaults[database.ProfileKey].(string)
+	for _, cmd := range tpl.CommandNodesIterator() {
+		st := regoStatement{Line: cmd.String(), Action: cmd.Action, Entity: cmd.Entity, Params: cmd.Params, Refs: cmd.Refs}
+		if st.Params == nil {
+			st.Params = make(map[string]interface{})
+		}
+		input.Statements = append(input.Statements, st)
+	}
+	return input
+}
+
+// parseRegoDenials extracts the messages of the query from the output of 'opa eval --format json'.
+// An undefined query (no deny rule) gives no result
+func parseRegoDenials(out []byte) ([]string, error) {
+	var res struct {
+		Result []struct {
+			Expressions []struct {
+				Value interface{} `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(out, &res); err != nil {
+		return nil, fmt.Errorf("invalid opa output: %s", err)
+	}
+	var denials []string
+	for _, r := range res.Result {
+		for _, exp := range r.Expressions {
+			switch v := exp.Value.(type) {
+			case []interface{}:
+				for _, msg := range v {
+					denials = append(denials, fmt.Sprint(msg))
+				}
+			case map[string]interface{}:
+				for msg := range v {
+					denials = append(denials, msg)
+				}
+			case nil:
+			default:
+				return nil, fmt.Errorf("%s must be a set of messages, got %v", regoQuery, v)
+			}
+		}
+	}
+	sort.Strings(denials)
+	return denials, nil
+}
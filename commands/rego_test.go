@@ -0,0 +1,114 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/template"
+)
+
+func TestCheckRegoPolicy(t *testing.T) {
+	defer func(fn func(string, []byte, string) ([]byte, error)) { opaEval = fn }(opaEval)
+
+	f, err := ioutil.TempFile("", "awless-policy.rego")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+
+	templ, err := template.Parse("create instance type=m4.xlarge subnet=sub-1 image=ami-1 count=1 name=web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaults := map[string]interface{}{config.RegoPolicyKey: f.Name(), database.RegionKey: "eu-west-1"}
+
+	var input regoInput
+	opaEval = func(policy string, in []byte, query string) ([]byte, error) {
+		if got, want := policy, f.Name(); got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		if got, want := query, "data.awless.deny"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		if err := json.Unmarshal(in, &input); err != nil {
+			t.Fatal(err)
+		}
+		return []byte(`{"result":[{"expressions":[{"value":["instance type m4.xlarge forbidden","missing tag Owner"],"text":"data.awless.deny"}]}]}`), nil
+	}
+	err = checkRegoPolicy(templ, defaults)
+	if err == nil {
+		t.Fatal("expected error got none")
+	}
+	if got, want := err.Error(), "instance type m4.xlarge forbidden"; !strings.Contains(got, want) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := input.Region, "eu-west-1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := len(input.Statements), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	st := input.Statements[0]
+	if got, want := st.Action+" "+st.Entity, "create instance"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := st.Params["type"], "m4.xlarge"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	opaEval = func(string, []byte, string) ([]byte, error) {
+		return []byte(`{"result":[{"expressions":[{"value":[],"text":"data.awless.deny"}]}]}`), nil
+	}
+	if err := checkRegoPolicy(templ, defaults); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkRegoPolicy(templ, map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseRegoDenials(t *testing.T) {
+	tcases := []struct {
+		out string
+		exp []string
+	}{
+		{`{}`, nil},
+		{`{"result":[{"expressions":[{"value":["b","a"]}]}]}`, []string{"a", "b"}},
+		{`{"result":[{"expressions":[{"value":{"denied":true}}]}]}`, []string{"denied"}},
+	}
+	for i, tcase := range tcases {
+		denials, err := parseRegoDenials([]byte(tcase.out))
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := denials, tcase.exp; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %v, want %v", i+1, got, want)
+		}
+	}
+	if _, err := parseRegoDenials([]byte(`{"result":[{"expressions":[{"value":true}]}]}`)); err == nil {
+		t.Fatal("expected error got none")
+	}
+}
@@ -21,11 +21,17 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/notify"
 )
 
+var revertParallelFlag int
+
 func init() {
 	RootCmd.AddCommand(revertCmd)
+
+	revertCmd.Flags().IntVar(&revertParallelFlag, "parallel", 8, "Maximum number of statements run in parallel, each once the resources depending on it are reverted (API calls share the rate limit of runs)")
 }
 
 var revertCmd = &cobra.Command{
@@ -41,19 +47,37 @@ var revertCmd = &cobra.Command{
 
 		revertId := args[0]
 
-		db, err, dbclose := database.Current()
-		exitOn(err)
-		tplExec, err := db.GetTemplateExecution(revertId)
-		dbclose()
+		tplExec, err := loadExecution(revertId)
 		exitOn(err)
 
 		reverted, err := tplExec.Revert()
+		if err != nil {
+			notifyRevertFailure(revertId, err)
+		}
 		exitOn(err)
 
 		fmt.Printf("%s\n", reverted)
 
-		exitOn(runTemplate(reverted))
+		deps, err := tplExec.RevertDependencies()
+		exitOn(err)
+
+		exitOn(runRevertTemplate(reverted, revertId, deps))
 
 		return nil
 	},
 }
+
+// notifyRevertFailure notifies a revert that cannot even start (ex: the execution is not revertible)
+func notifyRevertFailure(revertId string, err error) {
+	notifiers := notify.FromConfig(config.Config.Defaults)
+	if len(notifiers) == 0 {
+		return
+	}
+	event := &notify.Event{
+		Type: notify.RunFailed, Reverted: revertId, Errors: []string{err.Error()},
+		Account: currentAccount(), Region: configRegion(), Context: currentContextName(), Unattended: config.NonInteractive,
+	}
+	if err := notify.Send(notifiers, event); err != nil {
+		logger.Errorf("notify: %s", err)
+	}
+}
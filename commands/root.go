@@ -17,8 +17,16 @@ limitations under the License.
 package commands
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/config"
 	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/logger"
 )
 
 var (
@@ -26,14 +34,39 @@ var (
 	extraVerboseFlag bool
 	localFlag        bool
 	versionFlag      bool
+	noColorFlag      bool
+	quietFlag        bool
+	logFormatFlag    string
+	ciFlag           bool
+	roleFlag         string
+	externalIDFlag   string
+	profileFlag      string
+	regionFlag       string
+	accountFlag      string
+	profileCPUFlag   string
+	profileMemFlag   string
 )
 
 func init() {
 	RootCmd.PersistentFlags().BoolVarP(&verboseFlag, "verbose", "v", false, "Turn on verbose mode for all commands")
 	RootCmd.PersistentFlags().BoolVarP(&extraVerboseFlag, "extra-verbose", "e", false, "Turn on extra verbose mode (i.e: debug) for all commands")
 	RootCmd.PersistentFlags().BoolVar(&localFlag, "local", false, "Work offline only with synced/local resources")
+	RootCmd.PersistentFlags().BoolVar(&noColorFlag, "no-color", false, "Disable colored output (also disabled when NO_COLOR is set)")
+	RootCmd.PersistentFlags().BoolVarP(&quietFlag, "quiet", "q", false, "Print only results and errors")
+	RootCmd.PersistentFlags().StringVar(&logFormatFlag, "log-format", logger.TextFormat, "Format of logs: text or json")
+	RootCmd.PersistentFlags().BoolVar(&ciFlag, "ci", false, "Non interactive mode for CI: no prompts nor confirmations, JSON logs on stderr and JSON reports on stdout")
+	RootCmd.PersistentFlags().BoolVar(&config.ReadOnly, "readonly", false, "Refuse any action changing resources (or set config readonly to true)")
+	RootCmd.PersistentFlags().StringVar(&roleFlag, "role", "", "ARN of a role to assume on top of the profile credentials for this command")
+	RootCmd.PersistentFlags().StringVar(&externalIDFlag, "external-id", "", "External ID required to assume the role given with --role or --account")
+	RootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "AWS profile to use for this command, overriding config, context and environment")
+	RootCmd.PersistentFlags().StringVar(&regionFlag, "region", "", "Region to use for this command, overriding config, context and environment")
+	RootCmd.PersistentFlags().StringVar(&accountFlag, "account", "", "ID of an account to work in, assuming its role (config aws.account.role, default OrganizationAccountAccessRole)")
+	RootCmd.PersistentFlags().StringVar(&profileCPUFlag, "profile-cpu", "", "Write a CPU profile of the command to this file (pprof format)")
+	RootCmd.PersistentFlags().StringVar(&profileMemFlag, "profile-mem", "", "Write a memory profile at the end of the command to this file (pprof format)")
 	RootCmd.Flags().BoolVar(&versionFlag, "version", false, "Print awless version")
 
+	cobra.OnInitialize(initOutputControls, initTargetOverrides, startProfiling)
+
 	cobra.AddTemplateFunc("IsCmdAnnotatedOneliner", IsCmdAnnotatedOneliner)
 	cobra.AddTemplateFunc("HasCmdOnelinerChilds", HasCmdOnelinerChilds)
 
@@ -44,7 +77,6 @@ var RootCmd = &cobra.Command{
 	Use:   "awless",
 	Short: "Manage your cloud",
 	Long:  "Awless is a powerful command line tool to inspect, sync and manage your infrastructure",
-	BashCompletionFunction: bash_completion_func,
 	RunE: func(c *cobra.Command, args []string) error {
 		if versionFlag {
 			printVersion(c, args)
@@ -54,7 +86,72 @@ var RootCmd = &cobra.Command{
 	},
 }
 
+func initOutputControls() {
+	if noColorFlag || os.Getenv("NO_COLOR") != "" {
+		color.NoColor = true
+	}
+	logger.DefaultLogger.SetQuiet(quietFlag)
+	if ciFlag {
+		color.NoColor = true
+		config.NonInteractive = true
+		logFormatFlag = logger.JSONFormat
+		logger.DefaultLogger.SetOutput(os.Stderr)
+	}
+	exitOn(logger.DefaultLogger.SetFormat(logFormatFlag))
+}
+
+// initTargetOverrides applies the --profile, --region, --role and --account flags.
+// The role of --account is resolved with the config once loaded (see initCloudServicesHook)
+func initTargetOverrides() {
+	if profileFlag != "" {
+		config.FlagOverrides[database.ProfileKey] = profileFlag
+	}
+	if regionFlag != "" {
+		if !aws.IsValidRegion(regionFlag) {
+			exitOn(fmt.Errorf("invalid region '%s'", regionFlag))
+		}
+		config.FlagOverrides[database.RegionKey] = regionFlag
+	}
+	if accountFlag != "" {
+		if roleFlag != "" {
+			exitOn(fmt.Errorf("--account and --role cannot be used together"))
+		}
+		if !isAccountID(accountFlag) {
+			exitOn(fmt.Errorf("invalid account '%s': expecting a 12 digits account ID", accountFlag))
+		}
+		return
+	}
+	if roleFlag == "" {
+		if externalIDFlag != "" {
+			exitOn(fmt.Errorf("--external-id needs a role to assume with --role or --account"))
+		}
+		return
+	}
+	if !strings.HasPrefix(roleFlag, "arn:") {
+		exitOn(fmt.Errorf("invalid role '%s': expecting a role ARN (ex: arn:aws:iam::123456789012:role/admin)", roleFlag))
+	}
+	aws.RoleOverride = &aws.AssumeRole{Arn: roleFlag, ExternalID: externalIDFlag}
+}
+
+func isAccountID(s string) bool {
+	if len(s) != 12 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 func ExecuteRoot() error {
+	args := os.Args[1:]
+	if expanded, ok := expandShortcut(RootCmd, args); ok {
+		args = expanded
+		RootCmd.SetArgs(args)
+	}
+	startCommandSpan(args)
 	err := RootCmd.Execute()
 
 	if err != nil {
@@ -64,6 +161,8 @@ func ExecuteRoot() error {
 			dbclose()
 		}
 	}
+	exportTraces(err)
+	stopProfiling()
 
 	return err
 }
@@ -114,55 +213,3 @@ func HasCmdOnelinerChilds(cmd *cobra.Command) bool {
 
 	return false
 }
-
-const (
-	bash_completion_func = `
-__awless_get_all_ids()
-{
-		local all_ids_output
-		if all_ids_output=$(awless list infra --local --ids 2>/dev/null; awless list access --local --ids 2>/dev/null); then
-		COMPREPLY=( $( compgen -W "${all_ids_output[*]}" -- "$cur" ) )
-		fi
-}
-__awless_get_instances_ids()
-{
-		local all_ids_output
-		if all_ids_output=$(awless list instances --local --ids 2>/dev/null); then
-		COMPREPLY=( $( compgen -W "${all_ids_output[*]}" -- "$cur" ) )
-		fi
-}
-__awless_get_conf_keys()
-{
-		local all_keys_output
-		if all_keys_output=$(awless config list --keys 2>/dev/null); then
-		COMPREPLY=( $( compgen -W "${all_keys_output[*]}" -- "$cur" ) )
-		fi
-}
-
-__custom_func() {
-    case ${last_command} in
-				awless_ssh )
-            __awless_get_instances_ids
-            return
-            ;;
-				awless_show )
-            __awless_get_all_ids
-            return
-            ;;
-				awless_config_set )
-						__awless_get_conf_keys
-						return
-						;;
-				awless_config_get )
-						__awless_get_conf_keys
-						return
-						;;
-				awless_config_unset )
-						__awless_get_conf_keys
-						return
-						;;
-        *)
-            ;;
-    esac
-}`
-)
@@ -20,28 +20,47 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/wallix/awless/archive"
 	awscloud "github.com/wallix/awless/aws"
 	"github.com/wallix/awless/aws/driver"
+	"github.com/wallix/awless/azure"
 	"github.com/wallix/awless/cloud"
 	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/console"
 	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/gcp"
 	"github.com/wallix/awless/graph"
 	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/notify"
+	"github.com/wallix/awless/openstack"
 	"github.com/wallix/awless/sync"
 	"github.com/wallix/awless/template"
+	"github.com/wallix/awless/template/ast"
 	"github.com/wallix/awless/template/driver"
+	"github.com/wallix/awless/template/local"
+	"github.com/wallix/awless/tracing"
 )
 
 var renderGreenFn = color.New(color.FgGreen).SprintFunc()
 var renderRedFn = color.New(color.FgRed).SprintFunc()
 
+var (
+	runParamsFile string
+	runPlanFlag   bool
+)
+
 func init() {
+	runCmd.Flags().StringVar(&runParamsFile, "params-file", "", "YAML or JSON file of values for the template holes, possibly encrypted with KMS (.encrypted), age or GPG. Ex: instance.name: web")
+	runCmd.Flags().BoolVar(&runPlanFlag, "plan", false, "Display the resolved template without running it (with --ci, exit code 2 when there are statements to run)")
 	RootCmd.AddCommand(runCmd)
 	for action, entities := range aws.DriverSupportedActions() {
 		RootCmd.AddCommand(
@@ -51,13 +70,13 @@ func init() {
 }
 
 var runCmd = &cobra.Command{
-	Use:                "run",
-	Short:              "Run a template given a filepath",
+	Use:                "run {filepath} [key=value ...]",
+	Short:              "Run a template given a filepath, with optional values for its holes",
 	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initConfigStruct, initCloudServicesHook, initSyncerHook, verifyNewVersionHook),
 	PersistentPostRunE: saveHistoryHook,
 
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 1 {
+		if len(args) < 1 {
 			return errors.New("missing awless template file path")
 		}
 
@@ -65,8 +84,15 @@ var runCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		runTemplateName = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+
+		templ, err := parsedTemplates().Parse(string(content))
+		exitOn(err)
+		exitOn(templ.LoadFiles(filepath.Dir(args[0])))
 
-		templ, err := template.Parse(string(content))
+		params, err := templateParams(args[1:], runParamsFile)
+		exitOn(err)
+		_, err = templ.ResolveHoles(params)
 		exitOn(err)
 
 		exitOn(runTemplate(templ))
@@ -76,6 +102,14 @@ var runCmd = &cobra.Command{
 }
 
 func runTemplate(templ *template.Template) error {
+	return runRevertTemplate(templ, "", nil)
+}
+
+// runRevertTemplate runs the template undoing the given template execution if any.
+// With dependencies between its commands, independent commands are run concurrently
+func runRevertTemplate(templ *template.Template, revertedID string, deps [][]int) error {
+	resolveUserAliases(templ, loadUserAliases())
+
 	validateTemplate(templ)
 
 	resolved, err := templ.ResolveHoles(config.Config.Defaults)
@@ -84,16 +118,34 @@ func runTemplate(templ *template.Template) error {
 	if len(resolved) > 0 {
 		logger.Verbosef("used default params: %s", sprintProcessedParams(resolved))
 	}
+	if dropped := templ.DropUnfilledOptionals(); len(dropped) > 0 {
+		logger.Verbosef("optional params without value: %s", strings.Join(dropped, ", "))
+	}
+
+	if ciFlag {
+		exitOn(checkNoHoles(templ))
+	}
 
 	fills := make(map[string]interface{})
 	if holes := templ.GetHolesValuesSet(); len(holes) > 0 {
+		types := templ.GetHolesTypes()
 		fmt.Println("Please specify (Ctrl+C to quit):")
 		for _, hole := range holes {
 			var resp string
 			ask := func() error {
-				fmt.Printf("%s ? ", hole)
-				_, err := fmt.Scanln(&resp)
-				return err
+				if typ, ok := types[hole]; ok {
+					fmt.Printf("%s (%s) ? ", hole, typ)
+				} else {
+					fmt.Printf("%s ? ", hole)
+				}
+				if _, err := fmt.Scanln(&resp); err != nil {
+					return err
+				}
+				if typ, ok := types[hole]; ok {
+					_, err := ast.CastHoleValue(typ, resp)
+					return err
+				}
+				return nil
 			}
 			for err := ask(); err != nil; err = ask() {
 				logger.Errorf("invalid value: %s", err)
@@ -103,67 +155,252 @@ func runTemplate(templ *template.Template) error {
 	}
 
 	if len(fills) > 0 {
-		templ.ResolveHoles(fills)
+		_, err = templ.ResolveHoles(fills)
+		exitOn(err)
 	}
+	exitOn(templ.EvaluateFuncs())
+	exitOn(templ.ExpandGlobs(lookupLocalGraph))
 
 	validateTemplate(templ)
+	warnStackManagedResources(templ)
 
-	var drivers []driver.Driver
-	for _, s := range cloud.ServiceRegistry {
-		drivers = append(drivers, s.Drivers()...)
-	}
-	awsDriver := driver.NewMultiDriver(drivers...)
-
-	awsDriver.SetLogger(logger.DefaultLogger)
+	awsDriver := templateDriver()
 
 	_, err = templ.Compile(awsDriver)
 	exitOn(err)
+	exitOn(checkRegoPolicy(templ, config.Config.Defaults))
+
+	if runPlanFlag {
+		return planTemplate(templ)
+	}
+	exitOn(checkReadOnly(templ))
 
-	fmt.Println()
-	fmt.Printf("%s\n", renderGreenFn(templ))
-	fmt.Println()
-	fmt.Print("Confirm? (y/n): ")
 	var yesorno string
-	_, err = fmt.Scanln(&yesorno)
+	if ciFlag {
+		logger.Infof("CI mode: running without confirmation\n%s", templ)
+		yesorno = "y"
+	} else {
+		fmt.Println()
+		fmt.Printf("%s\n", renderGreenFn(templ))
+		fmt.Println()
+		if name := currentContextName(); name != "" {
+			fmt.Printf("Confirm on context '%s'? (y/n): ", name)
+		} else {
+			fmt.Print("Confirm? (y/n): ")
+		}
+		_, err = fmt.Scanln(&yesorno)
+	}
 
 	if strings.TrimSpace(yesorno) == "y" {
-		newTempl, err := templ.Run(awsDriver)
+		unlock, err := acquireRunLock(templ)
+		exitOn(err)
+		newTempl, executed, runErr := execTemplate(templ, awsDriver, revertedID, deps)
+		unlock()
 
-		executed := template.NewTemplateExecution(newTempl)
+		if ciFlag {
+			printCIReport(newCIReport(executed, runErr))
+		} else {
+			fmt.Println()
+			printReport(executed)
+		}
 
-		fmt.Println()
-		printReport(executed)
+		autoSyncAfterRun(newTempl, executed, runErr)
 
-		db, err, close := database.Current()
-		exitOn(err)
-		defer close()
+		if ciFlag {
+			exitCI(runExitCode(executed, runErr))
+		}
+	}
 
-		db.AddTemplateExecution(executed)
+	return nil
+}
 
-		if err == nil && !executed.HasErrors() {
-			if autoSync, ok := config.Config.Defaults[database.SyncAuto]; ok && autoSync.(bool) {
-				runSyncFor(newTempl)
-			}
+// templateDriver returns the driver running templates against the registered cloud services
+// and the operator's machine for local statements
+func templateDriver() driver.Driver {
+	var drivers []driver.Driver
+	for _, s := range cloud.ServiceRegistry {
+		drivers = append(drivers, s.Drivers()...)
+	}
+	drivers = append(drivers, local.NewDriver())
+	d := tracing.WrapDriver(driver.NewMultiDriver(drivers...))
+	if readOnlyMode() {
+		d = driver.NewReadOnlyDriver(d)
+	}
+	d.SetLogger(logger.DefaultLogger)
+	return d
+}
+
+// readOnlyMode tells whether the --readonly flag or the config forbid actions changing resources
+func readOnlyMode() bool {
+	if config.Config != nil {
+		return config.IsReadOnly(config.Config.Defaults)
+	}
+	defaults, _ := config.LoadDefaults()
+	return config.IsReadOnly(defaults)
+}
+
+// checkReadOnly refuses templates changing resources in read-only mode before asking for confirmation
+func checkReadOnly(tpl *template.Template) error {
+	if !readOnlyMode() {
+		return nil
+	}
+	for _, cmd := range tpl.CommandNodesIterator() {
+		if !driver.IsReadOnlyAction(cmd.Action) {
+			return &driver.ReadOnlyError{Statement: cmd.Action + " " + cmd.Entity}
+		}
+	}
+	return nil
+}
+
+// execTemplate runs the compiled template, notifying its lifecycle and saving its execution
+func execTemplate(templ *template.Template, d driver.Driver, revertedID string, deps [][]int) (*template.Template, *template.TemplateExecution, error) {
+	notifyRun(&notify.Event{Type: notify.RunStarted, Reverted: revertedID}, templ, nil)
+	var newTempl *template.Template
+	var runErr error
+	if deps != nil {
+		newTempl, runErr = templ.RunConcurrently(d, deps, revertParallelFlag)
+	} else {
+		newTempl, runErr = templ.Run(d)
+	}
+
+	executed := template.NewTemplateExecution(newTempl)
+	executed.Reverted, executed.Caller = revertedID, runCaller()
+	switch {
+	case runErr != nil || executed.HasErrors():
+		notifyRun(&notify.Event{Type: notify.RunFailed, RunID: executed.ID, Reverted: revertedID}, newTempl, runErr)
+	case revertedID != "":
+		notifyRun(&notify.Event{Type: notify.RunReverted, RunID: executed.ID, Reverted: revertedID}, newTempl, nil)
+	default:
+		notifyRun(&notify.Event{Type: notify.RunSucceeded, RunID: executed.ID}, newTempl, nil)
+	}
+
+	saveExecution(executed, newTempl, runErr)
+	return newTempl, executed, runErr
+}
+
+func autoSyncAfterRun(tpl *template.Template, executed *template.TemplateExecution, runErr error) {
+	if runErr == nil && !executed.HasErrors() {
+		if autoSync, ok := config.Config.Defaults[database.SyncAuto]; ok && autoSync.(bool) {
+			runSyncFor(tpl)
+		}
+	}
+}
+
+func saveExecution(executed *template.TemplateExecution, tpl *template.Template, runErr error) {
+	db, err, close := database.Current()
+	exitOn(err)
+	defer close()
+
+	db.AddTemplateExecution(executed)
+	uploadExecution(executed)
+	archiveRun(executed.ID, tpl, runErr)
+	if usageStatsEnabled(db) {
+		for _, cmd := range tpl.CommandNodesIterator() {
+			db.AddTemplateUsage(fmt.Sprintf("%s %s", cmd.Action, cmd.Entity))
 		}
 	}
+}
 
+// planTemplate displays the compiled template without running it.
+// In CI mode, it exits with exitPlanDiff when the template has statements to run
+func planTemplate(templ *template.Template) error {
+	if !ciFlag {
+		fmt.Printf("%s\n", renderGreenFn(templ))
+		printPlannedChanges(os.Stdout, templ)
+		logger.Info("plan only: nothing has been run")
+		return nil
+	}
+	report := newCIPlanReport(templ)
+	printCIReport(report)
+	if len(report.Statements) > 0 {
+		exitCI(exitPlanDiff)
+	}
 	return nil
 }
 
-func validateTemplate(tpl *template.Template) {
-	validDefinitionsRule := &template.DefinitionValidator{func(key string) (t template.TemplateDefinition, ok bool) {
-		t, ok = aws.AWSTemplatesDefinitions[key]
+// printPlannedChanges writes the property diffs of the existing resources
+// of the local graphs the template would update, tag or delete
+func printPlannedChanges(w io.Writer, templ *template.Template) {
+	graphs := make(map[string]*graph.Graph)
+	var found bool
+	for _, cmd := range templ.CommandNodesIterator() {
+		service := awscloud.ServicePerResourceType[cmd.Entity]
+		if cmd.Entity == "tag" {
+			service = awscloud.ServicePerResourceType[graph.Instance.String()]
+		}
+		if service == "" {
+			continue
+		}
+		if _, ok := graphs[service]; !ok {
+			graphs[service] = sync.LoadCurrentLocalGraph(service)
+		}
+		res, changes, err := awscloud.PlannedChanges(graphs[service], cmd.Action, cmd.Entity, cmd.Params)
+		if err != nil {
+			logger.Verbosef("cannot compute changes of '%s %s': %s", cmd.Action, cmd.Entity, err)
+			continue
+		}
+		if res == nil || (cmd.Action != "delete" && len(changes) == 0) {
+			continue
+		}
+		if !found {
+			fmt.Fprintln(w, "\nChanges of existing resources:")
+			found = true
+		}
+		marker := "~"
+		if cmd.Action == "delete" {
+			marker = "-"
+		}
+		console.WriteResourceChanges(w, marker, res.String(), changes)
+	}
+}
+
+// archiveRun commits the executed template to the git archive set in config if any
+func archiveRun(id string, tpl *template.Template, runErr error) {
+	repo := archive.FromConfig(config.Config.Defaults)
+	if repo == nil {
 		return
-	}}
+	}
+	if err := repo.Record(&archive.Run{ID: id, Region: configRegion(), Context: currentContextName(), Template: tpl, Err: runErr}); err != nil {
+		logger.Errorf("cannot archive run %s: %s", id, err)
+	}
+}
 
-	unicityRule := &template.UniqueNameValidator{func(key string) (*graph.Graph, bool) {
-		g := sync.LoadCurrentLocalGraph(awscloud.ServicePerResourceType[key])
-		return g, true
-	}}
+// lookupLocalGraph returns the local graph of the service of the resource type
+func lookupLocalGraph(key string) (*graph.Graph, bool) {
+	g := sync.LoadCurrentLocalGraph(serviceForResourceType(key))
+	return g, true
+}
 
-	errs := tpl.Validate(validDefinitionsRule, unicityRule)
+// serviceForResourceType returns the name of the service of the active provider holding a resource type
+func serviceForResourceType(t string) string {
+	if activeProvider == cloud.DefaultProvider {
+		return awscloud.ServicePerResourceType[t]
+	}
+	if srv, err := cloud.GetServiceForType(t); err == nil {
+		return srv.Name()
+	}
+	return ""
+}
+
+// templatesDefinitionsPerProvider holds the definitions of the statements run by the driver of each provider
+var templatesDefinitionsPerProvider = map[string]map[string]template.TemplateDefinition{
+	cloud.DefaultProvider: aws.AWSTemplatesDefinitions,
+	"gcp":                 gcp.TemplatesDefinitions,
+	"azure":               azure.TemplatesDefinitions,
+	"openstack":           openstack.TemplatesDefinitions,
+}
+
+// lookupTemplateDefinition returns the definition of a statement for the active provider,
+// falling back on the statements run locally
+func lookupTemplateDefinition(key string) (t template.TemplateDefinition, ok bool) {
+	if t, ok = templatesDefinitionsPerProvider[activeProvider][key]; !ok {
+		t, ok = local.TemplatesDefinitions[key]
+	}
+	return
+}
 
-	if len(errs) > 0 {
+func validateTemplate(tpl *template.Template) {
+	if errs := templateErrors(tpl); len(errs) > 0 {
 		for _, err := range errs {
 			logger.Error(err)
 		}
@@ -171,6 +408,69 @@ func validateTemplate(tpl *template.Template) {
 	}
 }
 
+func templateErrors(tpl *template.Template) []error {
+	validDefinitionsRule := &template.DefinitionValidator{LookupDef: lookupTemplateDefinition}
+
+	unicityRule := &template.UniqueNameValidator{LookupGraph: lookupLocalGraph}
+
+	rules := []template.Validator{validDefinitionsRule, unicityRule}
+
+	if p := config.Project; p != nil && len(p.RequiredTags) > 0 {
+		rules = append(rules, &template.RequiredTagsValidator{Keys: p.RequiredTags, Taggable: isTaggableEntity})
+	}
+
+	if path := configGuardrailsPath(); path != "" {
+		policy, err := config.LoadGuardrails(path)
+		if err != nil {
+			return []error{err}
+		}
+		rules = append(rules, &template.GuardrailsValidator{Deny: policy.Deny, Allow: policy.Allow, Values: policy.Values})
+	}
+
+	return tpl.Validate(rules...)
+}
+
+// configGuardrailsPath returns the guardrails policy file set in config if any
+func configGuardrailsPath() string {
+	if config.Config == nil {
+		return ""
+	}
+	path, _ := config.Config.Defaults[config.GuardrailsKey].(string)
+	return path
+}
+
+// warnStackManagedResources warns when a template changes resources managed by CloudFormation stacks,
+// as those changes will drift from the stacks
+func warnStackManagedResources(tpl *template.Template) {
+	managed, err := awscloud.StackManagedResources(sync.LoadCurrentLocalGraph(awscloud.ServicePerResourceType[graph.Stack.String()]))
+	if err != nil || len(managed) == 0 {
+		return
+	}
+	for _, cmd := range tpl.CommandNodesIterator() {
+		if cmd.Action == "create" {
+			continue
+		}
+		var keys []string
+		for k := range cmd.Params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if stack, ok := managed[fmt.Sprint(cmd.Params[k])]; ok {
+				logger.Warningf("%s %s: %s=%v is managed by CloudFormation stack '%s', this change will drift from the stack", cmd.Action, cmd.Entity, k, cmd.Params[k], stack)
+			}
+		}
+	}
+}
+
+func isTaggableEntity(entity string) bool {
+	switch entity {
+	case "vpc", "subnet", "instance", "securitygroup", "volume", "internetgateway", "routetable":
+		return true
+	}
+	return false
+}
+
 func createDriverCommands(action string, entities []string) *cobra.Command {
 	actionCmd := &cobra.Command{
 		Use:         action,
@@ -187,25 +487,8 @@ func createDriverCommands(action string, entities []string) *cobra.Command {
 
 		run := func(def template.TemplateDefinition) func(cmd *cobra.Command, args []string) error {
 			return func(cmd *cobra.Command, args []string) error {
-				text := fmt.Sprintf("%s %s %s", def.Action, def.Entity, strings.Join(args, " "))
-
-				cliTpl, err := template.Parse(text)
-				exitOn(err)
-
-				templ, err := template.Parse(def.String())
-				if err != nil {
-					exitOn(fmt.Errorf("internal error parsing template definition\n`%s`\n%s", def, err))
-				}
-				logger.ExtraVerbosef("template definition: %s", def)
-
-				_, err = templ.ResolveHoles(
-					cliTpl.GetNormalizedParams(),
-					resolveAlias(cliTpl.GetNormalizedAliases(), def.Entity),
-				)
+				templ, err := oneLinerTemplate(def, args)
 				exitOn(err)
-
-				templ.MergeParams(cliTpl.GetNormalizedParams())
-
 				exitOn(runTemplate(templ))
 				return nil
 			}
@@ -221,31 +504,104 @@ func createDriverCommands(action string, entities []string) *cobra.Command {
 				RunE:               run(templDef),
 			},
 		)
+
+		if action == "delete" {
+			if bulkCmd := createBulkDeleteCommand(templDef); bulkCmd != nil {
+				actionCmd.AddCommand(bulkCmd)
+			}
+		}
 	}
 
 	return actionCmd
 }
 
-func runSyncFor(tpl *template.Template) {
-	lookup := func(key string) (t template.TemplateDefinition, ok bool) {
-		t, ok = aws.AWSTemplatesDefinitions[key]
-		return
+// oneLinerTemplate returns the template of a one-liner command, with the statement of the active provider
+// matching the command definition and its holes resolved with the key=value args
+func oneLinerTemplate(cmdDef template.TemplateDefinition, args []string) (*template.Template, error) {
+	def, ok := lookupTemplateDefinition(cmdDef.Name())
+	if !ok {
+		return nil, fmt.Errorf("%s %s: unsupported by provider %s", cmdDef.Action, cmdDef.Entity, activeProvider)
+	}
+	text := fmt.Sprintf("%s %s %s", def.Action, def.Entity, strings.Join(args, " "))
+
+	cliTpl, err := template.Parse(text)
+	if err != nil {
+		return nil, err
 	}
-	collector := &template.CollectDefinitions{L: lookup}
-	tpl.Visit(collector)
 
-	uniqueNames := make(map[string]bool)
-	for _, def := range collector.C {
+	templ, err := template.Parse(def.String())
+	if err != nil {
+		return nil, fmt.Errorf("internal error parsing template definition\n`%s`\n%s", def, err)
+	}
+	logger.ExtraVerbosef("template definition: %s", def)
+
+	_, err = templ.ResolveHoles(
+		cliTpl.GetNormalizedParams(),
+		resolveAlias(cliTpl.GetNormalizedAliases(), def.Entity),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	templ.MergeParams(cliTpl.GetNormalizedParams())
+	return templ, templ.LoadFiles(".")
+}
+
+// runSyncFor updates the local graphs with the resources of the types changed by the template,
+// so that created resources are listable and aliasable without a full sync. Services whose
+// changes cannot be refreshed by type are synced
+func runSyncFor(tpl *template.Template) {
+	typesPerService := make(map[string][]string)
+	toSync := make(map[string]bool)
+	for _, cmd := range tpl.CommandNodesIterator() {
+		if activeProvider != cloud.DefaultProvider {
+			if name := serviceForResourceType(cmd.Entity); name != "" {
+				toSync[name] = true
+			}
+			continue
+		}
+		def, ok := aws.AWSTemplatesDefinitions[cmd.Action+cmd.Entity]
+		if !ok {
+			continue
+		}
 		name, ok := awscloud.ServicePerAPI[def.Api]
-		if ok {
-			uniqueNames[name] = true
+		if !ok {
+			continue
+		}
+		types := typesChangedBy(cmd.Action, cmd.Entity, cmd.Params, name)
+		if len(types) == 0 {
+			toSync[name] = true
+		}
+		for _, t := range types {
+			if !containsString(typesPerService[name], t) {
+				typesPerService[name] = append(typesPerService[name], t)
+			}
 		}
 	}
 
+	for name, types := range typesPerService {
+		srv, ok := cloud.ServiceRegistry[name]
+		if toSync[name] || !ok {
+			continue
+		}
+		g := sync.LoadCurrentLocalGraph(name)
+		if err := awscloud.RefreshResources(g, srv, types...); err != nil {
+			logger.Verbosef("cannot refresh %s of local %s graph, syncing it: %s", strings.Join(types, ", "), name, err)
+			toSync[name] = true
+			continue
+		}
+		if err := sync.SaveLocalGraph(name, g); err != nil {
+			logger.Errorf("saving local %s graph: %s", name, err)
+			continue
+		}
+		logger.Verbosef("refreshed %s in local %s graph", strings.Join(types, ", "), name)
+	}
+
 	var srvNames []string
-	for name := range uniqueNames {
+	for name := range toSync {
 		srvNames = append(srvNames, name)
 	}
+	sort.Strings(srvNames)
 
 	var services []cloud.Service
 	for _, name := range srvNames {
@@ -256,6 +612,9 @@ func runSyncFor(tpl *template.Template) {
 			services = append(services, srv)
 		}
 	}
+	if len(services) == 0 {
+		return
+	}
 
 	if _, err := sync.DefaultSyncer.Sync(services...); err != nil {
 		logger.Error(err.Error())
@@ -264,6 +623,32 @@ func runSyncFor(tpl *template.Template) {
 	}
 }
 
+// typesChangedBy returns the resource types of a service to refresh after a command: its entity and,
+// when attaching or detaching, the entities given as params whose relations may have changed
+func typesChangedBy(action, entity string, params map[string]interface{}, service string) (types []string) {
+	if entity == "instances" { // rolling update of an instance fleet
+		entity = "instance"
+	}
+	if awscloud.ServicePerResourceType[entity] != service {
+		return
+	}
+	types = append(types, entity)
+	if action != "attach" && action != "detach" {
+		return
+	}
+	var keys []string
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if k != entity && awscloud.ServicePerResourceType[k] == service {
+			types = append(types, k)
+		}
+	}
+	return
+}
+
 func printReport(t *template.TemplateExecution) {
 	for _, done := range t.Executed {
 		var line bytes.Buffer
@@ -292,8 +677,13 @@ func resolveAlias(aliases map[string]string, entity string) map[string]interface
 	graphForResource := sync.LoadCurrentLocalGraph(awscloud.ServicePerResourceType[entity])
 
 	resolved := make(map[string]interface{})
+	userAliases := loadUserAliases()
 
 	for k, v := range aliases {
+		if value, ok := userAliases[v]; ok {
+			resolved[k] = value
+			continue
+		}
 		var t string
 		if strings.Split(k, ".")[1] == "id" {
 			t = strings.Split(k, ".")[0]
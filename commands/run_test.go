@@ -0,0 +1,105 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/wallix/awless/aws/driver"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/template"
+)
+
+func TestTemplateErrorsPerProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	repoDir := config.RepoDir
+	config.RepoDir = dir
+	defer func() { config.RepoDir = repoDir }()
+	defer func() { activeProvider = cloud.DefaultProvider }()
+
+	tcases := []struct {
+		provider, text string
+	}{
+		{"gcp", "create instance name=vm zone=europe-west1-b type=e2-small image=debian-11\ndelete instance id=vm zone=europe-west1-b\ncreate vpc name=net"},
+		{"openstack", "create instance name=vm image=cirros type=m1.tiny vpc=net-1 keypair=ops\nupdate instance id=vm-1 tags={env:prod}"},
+	}
+	for i, tcase := range tcases {
+		tpl, err := template.Parse(tcase.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		activeProvider = tcase.provider
+		if errs := templateErrors(tpl); len(errs) > 0 {
+			t.Fatalf("%d: unexpected errors: %v", i, errs)
+		}
+		activeProvider = cloud.DefaultProvider
+		if errs := templateErrors(tpl); len(errs) == 0 {
+			t.Fatalf("%d: expected errors validating %s params with AWS definitions", i, tcase.provider)
+		}
+	}
+}
+
+func TestAzureOneLinerRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	repoDir := config.RepoDir
+	config.RepoDir = dir
+	defer func() { config.RepoDir = repoDir }()
+
+	registry := cloud.ServiceRegistry
+	cloud.ServiceRegistry = make(map[string]cloud.Service)
+	defer func() { cloud.ServiceRegistry = registry }()
+	os.Setenv("AZURE_ACCESS_TOKEN", "token")
+	defer os.Unsetenv("AZURE_ACCESS_TOKEN")
+	provider, err := cloud.GetProvider("azure")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = provider.InitServices(map[string]interface{}{"azure.subscription": "sub"}); err != nil {
+		t.Fatal(err)
+	}
+	activeProvider = "azure"
+	defer func() { activeProvider = cloud.DefaultProvider }()
+
+	templ, err := oneLinerTemplate(aws.AWSTemplatesDefinitions["createinstance"], []string{
+		"name=vm", "resourcegroup=rg", "location=westeurope", "type=Standard_B1s", "image=Canonical:UbuntuServer:18.04-LTS:latest",
+		"subnet=/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/net/subnets/default",
+		"username=admin", "password=s3cr3t",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := templ.GetHolesValuesSet(); len(got) > 0 {
+		t.Fatalf("unexpected holes: %v", got)
+	}
+	if errs := templateErrors(templ); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if _, err = templ.Compile(templateDriver()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/sync"
+)
+
+func init() {
+	RootCmd.AddCommand(searchCmd)
+}
+
+var searchCmd = &cobra.Command{
+	Use:                "search {term}",
+	Short:              "Search your local resources by name, id, tags, IPs or ARNs. Ex: awless search 10.0.2.12",
+	PersistentPreRun:   applyHooks(initAwlessEnvHook, verifyNewVersionHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 || strings.TrimSpace(args[0]) == "" {
+			return errors.New("search term required")
+		}
+
+		var matches []searchMatch
+		for _, srvName := range aws.ServiceNames {
+			found, err := searchGraph(sync.LoadCurrentLocalGraph(srvName), args[0])
+			exitOn(err)
+			matches = append(matches, found...)
+		}
+
+		if len(matches) == 0 {
+			fmt.Printf("No resource matching '%s' found in your local resources (run `awless sync` to refresh them)\n", args[0])
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tID\tNAME\tFIELD\tMATCH")
+		for _, m := range matches {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", m.res.Type(), m.res.Id(), m.name(), m.field, highlightMatch(m.value, args[0]))
+		}
+		return w.Flush()
+	},
+}
+
+type searchMatch struct {
+	res          *graph.Resource
+	field, value string
+}
+
+func (m searchMatch) name() string {
+	if name, ok := m.res.Properties["Name"].(string); ok {
+		return name
+	}
+	return ""
+}
+
+func isSearchableProperty(key string) bool {
+	switch key {
+	case "Id", "Name", "Tags", "PublicIp", "PrivateIp", "DNSName":
+		return true
+	}
+	return strings.HasSuffix(key, "Arn")
+}
+
+// searchGraph returns the resources of the graph having a searchable property
+// containing the term (case insensitive), one match per matching property
+func searchGraph(g *graph.Graph, term string) ([]searchMatch, error) {
+	term = strings.ToLower(term)
+	var matches []searchMatch
+	for _, resType := range aws.ResourceTypes {
+		resources, err := g.GetAllResources(graph.ResourceType(resType))
+		if err != nil {
+			return matches, err
+		}
+		sort.Sort(graph.ResourceById(resources))
+		for _, res := range resources {
+			var keys []string
+			for k := range res.Properties {
+				if isSearchableProperty(k) {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				for _, val := range searchableValues(res.Properties[k]) {
+					if strings.Contains(strings.ToLower(val), term) {
+						matches = append(matches, searchMatch{res: res, field: k, value: val})
+					}
+				}
+			}
+		}
+	}
+	return matches, nil
+}
+
+func searchableValues(prop interface{}) []string {
+	switch v := prop.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		var values []string
+		for _, e := range v {
+			values = append(values, fmt.Sprint(e))
+		}
+		return values
+	case nil:
+		return nil
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+var highlightFn = color.New(color.FgYellow, color.Bold).SprintFunc()
+
+func highlightMatch(value, term string) string {
+	idx := strings.Index(strings.ToLower(value), strings.ToLower(term))
+	if idx < 0 {
+		return value
+	}
+	end := idx + len(term)
+	return value[:idx] + highlightFn(value[idx:end]) + value[end:]
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/wallix/awless/graph"
+)
+
+func TestSearchGraph(t *testing.T) {
+	g := graph.NewGraph()
+	inst1 := graph.InitResource("inst_1", graph.Instance)
+	inst1.Properties["Id"] = "inst_1"
+	inst1.Properties["Name"] = "redis"
+	inst1.Properties["PrivateIp"] = "10.0.2.12"
+	inst1.Properties["Tags"] = []interface{}{"Env=Prod", "Team=data"}
+	inst2 := graph.InitResource("inst_2", graph.Instance)
+	inst2.Properties["Id"] = "inst_2"
+	inst2.Properties["Name"] = "web"
+	inst2.Properties["PrivateIp"] = "10.0.3.5"
+	inst2.Properties["State"] = "prod"
+	role := graph.InitResource("role_1", graph.Role)
+	role.Properties["Id"] = "role_1"
+	role.Properties["Arn"] = "arn:aws:iam::123456789012:role/prod-admin"
+	g.AddResource(inst1, inst2, role)
+
+	tcases := []struct {
+		term   string
+		expect []string
+	}{
+		{term: "10.0.2", expect: []string{"inst_1:PrivateIp"}},
+		{term: "PROD", expect: []string{"inst_1:Tags", "role_1:Arn"}},
+		{term: "inst_", expect: []string{"inst_1:Id", "inst_2:Id"}},
+		{term: "nothing", expect: nil},
+	}
+	for _, tcase := range tcases {
+		matches, err := searchGraph(g, tcase.term)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, m := range matches {
+			got = append(got, m.res.Id()+":"+m.field)
+		}
+		if len(got) != len(tcase.expect) {
+			t.Fatalf("%s: got %v, want %v", tcase.term, got, tcase.expect)
+		}
+		for i := range got {
+			if got[i] != tcase.expect[i] {
+				t.Fatalf("%s: got %v, want %v", tcase.term, got, tcase.expect)
+			}
+		}
+	}
+}
+
+func TestHighlightMatch(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	if got, want := highlightMatch("Env=Prod", "prod"), "Env="+highlightFn("Prod"); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := highlightMatch("redis", "web"), "redis"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,421 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/sync"
+	"github.com/wallix/awless/template"
+	"github.com/wallix/awless/template/local"
+)
+
+const (
+	serveTokenKey = "serve.token"
+	serveTokenEnv = "AWLESS_SERVE_TOKEN"
+
+	maxRunBodySize = 1 << 20
+)
+
+var (
+	serveListenFlag   string
+	serveReadOnlyFlag bool
+	serveTLSCertFlag  string
+	serveTLSKeyFlag   string
+)
+
+func init() {
+	RootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveListenFlag, "listen", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().BoolVar(&serveReadOnlyFlag, "read-only", false, "Only expose the resources queries: no sync nor template runs")
+	serveCmd.Flags().StringVar(&serveTLSCertFlag, "tls-cert", "", "TLS certificate file to serve HTTPS")
+	serveCmd.Flags().StringVar(&serveTLSKeyFlag, "tls-key", "", "TLS private key file to serve HTTPS")
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: fmt.Sprintf("Serve a JSON API to query your resources and run templates. Requests need the bearer token set in %s (or config %s)", serveTokenEnv, serveTokenKey),
+	Long: `Serve a JSON API to query your synced resources and run templates, for dashboards and bots:
+
+  GET  /v1/health                   (no authentication)
+  GET  /v1/resources/{type}         ex: /v1/resources/instances
+  GET  /v1/resources/{type}/{id}    properties, parents and children of a resource
+  GET  /v1/search?q={term}
+  POST /v1/sync                     sync all services
+  GET  /v1/runs                     template executions
+  GET  /v1/runs/{id}
+  POST /v1/runs                     {"template": "create instance ...", "params": {"instance.name": "web"}, "plan": false}
+
+Requests are authenticated with the header 'Authorization: Bearer {token}'.`,
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initConfigStruct, initCloudServicesHook, initSyncerHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token := os.Getenv(serveTokenEnv)
+		if token == "" {
+			token, _ = config.Config.Defaults[serveTokenKey].(string)
+		}
+		if token == "" {
+			return fmt.Errorf("missing API token: export %s or set %s in config", serveTokenEnv, serveTokenKey)
+		}
+		// runs can not prompt for missing values or confirmation
+		config.NonInteractive = true
+
+		server := &http.Server{
+			Addr:              serveListenFlag,
+			Handler:           newAPIServer(token, serveReadOnlyFlag),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		logger.Infof("serving awless API on %s (read-only: %t)", serveListenFlag, serveReadOnlyFlag)
+		if serveTLSCertFlag != "" || serveTLSKeyFlag != "" {
+			return server.ListenAndServeTLS(serveTLSCertFlag, serveTLSKeyFlag)
+		}
+		return server.ListenAndServe()
+	},
+}
+
+type apiServer struct {
+	token    string
+	readOnly bool
+	mux      *http.ServeMux
+	// templates are run one at a time
+	runMu gosync.Mutex
+}
+
+func newAPIServer(token string, readOnly bool) *apiServer {
+	s := &apiServer{token: token, readOnly: readOnly, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/v1/health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	s.mux.HandleFunc("/v1/resources/", s.authenticated(s.handleResources))
+	s.mux.HandleFunc("/v1/search", s.authenticated(s.handleSearch))
+	s.mux.HandleFunc("/v1/sync", s.authenticated(s.handleSync))
+	s.mux.HandleFunc("/v1/runs", s.authenticated(s.handleRuns))
+	s.mux.HandleFunc("/v1/runs/", s.authenticated(s.handleRuns))
+	return s
+}
+
+func (s *apiServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	s.mux.ServeHTTP(w, r)
+	logger.Verbosef("api: %s %s (%s)", r.Method, r.URL.Path, time.Since(start))
+}
+
+func (s *apiServer) authenticated(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("invalid or missing bearer token"))
+			return
+		}
+		h(w, r)
+	}
+}
+
+type apiRef struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type apiResource struct {
+	apiRef
+	Properties map[string]interface{} `json:"properties"`
+	Parents    []apiRef               `json:"parents,omitempty"`
+	Children   []apiRef               `json:"children,omitempty"`
+}
+
+func (s *apiServer) handleResources(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	splits := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/resources/"), "/"), "/")
+	resType, ok := apiResourceType(splits[0])
+	if !ok || len(splits) > 2 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown resource type '%s'", splits[0]))
+		return
+	}
+	g := sync.LoadCurrentLocalGraph(aws.ServicePerResourceType[resType])
+
+	if len(splits) == 2 {
+		res, err := g.GetResource(graph.ResourceType(resType), splits[1])
+		if err != nil || len(res.Properties) == 0 {
+			writeError(w, http.StatusNotFound, fmt.Errorf("%s '%s' not found", resType, splits[1]))
+			return
+		}
+		detailed, err := apiResourceWithRelations(g, res)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, detailed)
+		return
+	}
+
+	resources, err := g.GetAllResources(graph.ResourceType(resType))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	sort.Sort(graph.ResourceById(resources))
+	out := []*apiResource{}
+	for _, res := range resources {
+		out = append(out, &apiResource{apiRef: apiRef{Id: res.Id(), Type: resType}, Properties: res.Properties})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *apiServer) handleSearch(w http.ResponseWriter, r *http.Request) {
+	term := strings.TrimSpace(r.URL.Query().Get("q"))
+	if term == "" {
+		writeError(w, http.StatusBadRequest, errors.New("missing search term: ?q={term}"))
+		return
+	}
+	type match struct {
+		apiRef
+		Field string `json:"field"`
+		Value string `json:"value"`
+	}
+	out := []match{}
+	for _, srvName := range aws.ServiceNames {
+		found, err := searchGraph(sync.LoadCurrentLocalGraph(srvName), term)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		for _, m := range found {
+			out = append(out, match{apiRef: apiRef{Id: m.res.Id(), Type: m.res.Type().String()}, Field: m.field, Value: m.value})
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *apiServer) handleSync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	if s.readOnly {
+		writeError(w, http.StatusForbidden, errors.New("read-only server"))
+		return
+	}
+	var services []cloud.Service
+	for _, srv := range cloud.ServiceRegistry {
+		services = append(services, srv)
+	}
+	graphs, err := sync.DefaultSyncer.Sync(services...)
+	counts := make(map[string]int)
+	for name, g := range graphs {
+		srv, ok := cloud.ServiceRegistry[name]
+		if !ok {
+			continue
+		}
+		for _, t := range srv.ResourceTypes() {
+			if res, gerr := g.GetAllResources(graph.ResourceType(t)); gerr == nil && len(res) > 0 {
+				counts[t] = len(res)
+			}
+		}
+	}
+	out := map[string]interface{}{"resources": counts}
+	if err != nil {
+		out["error"] = err.Error()
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+type apiRunRequest struct {
+	Template string                 `json:"template"`
+	Params   map[string]interface{} `json:"params"`
+	Plan     bool                   `json:"plan"`
+}
+
+func (s *apiServer) handleRuns(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/runs"), "/")
+	switch {
+	case r.Method == "GET" && id == "":
+		db, err, dbclose := database.Current()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		all, err := db.ListTemplateExecutions()
+		dbclose()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		all = listExecutions(all)
+		if all == nil {
+			all = []*template.TemplateExecution{}
+		}
+		writeJSON(w, http.StatusOK, all)
+	case r.Method == "GET":
+		executed, err := loadExecution(id)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, executed)
+	case r.Method == "POST" && id == "":
+		if s.readOnly {
+			writeError(w, http.StatusForbidden, errors.New("read-only server"))
+			return
+		}
+		var req apiRunRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRunBodySize)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid run request: %s", err))
+			return
+		}
+		s.runMu.Lock()
+		defer s.runMu.Unlock()
+		status, report, err := serveRun(&req)
+		if err != nil {
+			writeError(w, status, err)
+			return
+		}
+		writeJSON(w, status, report)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	}
+}
+
+// serveRun runs the template of a request as `awless run` does in CI mode,
+// returning the HTTP status of the response
+func serveRun(req *apiRunRequest) (int, *ciReport, error) {
+	templ, err := template.Parse(req.Template)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+	for _, cmd := range templ.CommandNodesIterator() {
+		if _, isLocal := local.TemplatesDefinitions[cmd.Action+cmd.Entity]; isLocal {
+			return http.StatusForbidden, nil, fmt.Errorf("%s %s: statements running on the server are not allowed", cmd.Action, cmd.Entity)
+		}
+	}
+	if files := templ.FileValues(); len(files) > 0 {
+		return http.StatusForbidden, nil, fmt.Errorf("reading files on the server is not allowed: %s", strings.Join(files, ", "))
+	}
+	if _, err := templ.ResolveHoles(req.Params); err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+	resolveUserAliases(templ, loadUserAliases())
+	if _, err := templ.ResolveHoles(config.Config.Defaults); err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+	templ.DropUnfilledOptionals()
+	if holes := templ.GetHolesValuesSet(); len(holes) > 0 {
+		sort.Strings(holes)
+		return http.StatusBadRequest, nil, fmt.Errorf("missing params %s", strings.Join(holes, ", "))
+	}
+	if err := templ.ExpandGlobs(lookupLocalGraph); err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+	if errs := templateErrors(templ); len(errs) > 0 {
+		var msgs []string
+		for _, e := range errs {
+			msgs = append(msgs, e.Error())
+		}
+		return http.StatusBadRequest, nil, errors.New(strings.Join(msgs, "; "))
+	}
+
+	d := templateDriver()
+	if _, err := templ.Compile(d); err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+	if err := checkRegoPolicy(templ, config.Config.Defaults); err != nil {
+		return http.StatusForbidden, nil, err
+	}
+	if req.Plan {
+		return http.StatusOK, newCIPlanReport(templ), nil
+	}
+	if err := checkReadOnly(templ); err != nil {
+		return http.StatusForbidden, nil, err
+	}
+
+	unlock, err := acquireRunLock(templ)
+	if err != nil {
+		return http.StatusConflict, nil, err
+	}
+	newTempl, executed, runErr := execTemplate(templ, d, "", nil)
+	unlock()
+	autoSyncAfterRun(newTempl, executed, runErr)
+
+	status := http.StatusOK
+	if runExitCode(executed, runErr) != 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	return status, newCIReport(executed, runErr), nil
+}
+
+func apiResourceType(name string) (string, bool) {
+	for _, t := range aws.ResourceTypes {
+		if name == t || name == cloud.PluralizeResource(t) {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+func apiResourceWithRelations(g *graph.Graph, res *graph.Resource) (*apiResource, error) {
+	out := &apiResource{apiRef: apiRef{Id: res.Id(), Type: res.Type().String()}, Properties: res.Properties}
+	collect := func(refs *[]apiRef) func(*graph.Resource, int) error {
+		return func(r *graph.Resource, depth int) error {
+			if depth == 1 {
+				*refs = append(*refs, apiRef{Id: r.Id(), Type: r.Type().String()})
+			}
+			return nil
+		}
+	}
+	if err := g.Accept(&graph.ParentsVisitor{From: res, Each: collect(&out.Parents)}); err != nil {
+		return nil, err
+	}
+	if err := g.Accept(&graph.ChildrenVisitor{From: res, Each: collect(&out.Children)}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		logger.Errorf("api: encoding response: %s", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
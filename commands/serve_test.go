@@ -0,0 +1,157 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/sync"
+)
+
+func TestAPIServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-serve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	repoDir := config.RepoDir
+	config.RepoDir = dir
+	defer func() { config.RepoDir = repoDir }()
+
+	g := graph.NewGraph()
+	vpc := graph.InitResource("vpc_1", graph.Vpc)
+	vpc.Properties["Id"] = "vpc_1"
+	subnet := graph.InitResource("sub_1", graph.Subnet)
+	subnet.Properties["Id"] = "sub_1"
+	inst1 := graph.InitResource("inst_1", graph.Instance)
+	inst1.Properties["Id"] = "inst_1"
+	inst1.Properties["Name"] = "redis"
+	inst2 := graph.InitResource("inst_2", graph.Instance)
+	inst2.Properties["Id"] = "inst_2"
+	g.AddResource(vpc, subnet, inst1, inst2)
+	g.AddParentRelation(vpc, subnet)
+	g.AddParentRelation(subnet, inst1)
+	if err := sync.SaveLocalGraph("infra", g); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(newAPIServer("s3cr3t", true))
+	defer server.Close()
+
+	call := func(method, path, token string) (int, string) {
+		req, err := http.NewRequest(method, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(b)
+	}
+
+	t.Run("authentication", func(t *testing.T) {
+		if got, _ := call("GET", "/v1/health", ""); got != http.StatusOK {
+			t.Fatalf("got %d, want %d", got, http.StatusOK)
+		}
+		if got, _ := call("GET", "/v1/resources/instances", ""); got != http.StatusUnauthorized {
+			t.Fatalf("got %d, want %d", got, http.StatusUnauthorized)
+		}
+		if got, _ := call("GET", "/v1/resources/instances", "wrong"); got != http.StatusUnauthorized {
+			t.Fatalf("got %d, want %d", got, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("list resources", func(t *testing.T) {
+		status, body := call("GET", "/v1/resources/instances", "s3cr3t")
+		if got, want := status, http.StatusOK; got != want {
+			t.Fatalf("got %d, want %d: %s", got, want, body)
+		}
+		var resources []apiResource
+		if err := json.Unmarshal([]byte(body), &resources); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(resources), 2; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		if got, want := resources[0].Properties["Name"], "redis"; got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got, _ := call("GET", "/v1/resources/unknown", "s3cr3t"); got != http.StatusNotFound {
+			t.Fatalf("got %d, want %d", got, http.StatusNotFound)
+		}
+	})
+
+	t.Run("resource with relations", func(t *testing.T) {
+		status, body := call("GET", "/v1/resources/subnet/sub_1", "s3cr3t")
+		if got, want := status, http.StatusOK; got != want {
+			t.Fatalf("got %d, want %d: %s", got, want, body)
+		}
+		var res apiResource
+		if err := json.Unmarshal([]byte(body), &res); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := res.Parents, []apiRef{{Id: "vpc_1", Type: "vpc"}}; len(got) != 1 || got[0] != want[0] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got, want := res.Children, []apiRef{{Id: "inst_1", Type: "instance"}}; len(got) != 1 || got[0] != want[0] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got, _ := call("GET", "/v1/resources/subnet/sub_2", "s3cr3t"); got != http.StatusNotFound {
+			t.Fatalf("got %d, want %d", got, http.StatusNotFound)
+		}
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		status, body := call("POST", "/v1/runs", "s3cr3t")
+		if got, want := status, http.StatusForbidden; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		if !strings.Contains(body, "read-only") {
+			t.Fatalf("got %s", body)
+		}
+		if got, _ := call("POST", "/v1/sync", "s3cr3t"); got != http.StatusForbidden {
+			t.Fatalf("got %d, want %d", got, http.StatusForbidden)
+		}
+	})
+
+	t.Run("local statements", func(t *testing.T) {
+		status, _, err := serveRun(&apiRunRequest{Template: `run local cmd="echo hello"`})
+		if got, want := status, http.StatusForbidden; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
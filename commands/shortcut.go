@@ -0,0 +1,54 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/config"
+)
+
+// expandShortcut resolves a user defined shortcut given as first arg before flags get parsed.
+// Builtin commands always take precedence over shortcuts.
+func expandShortcut(root *cobra.Command, args []string) ([]string, bool) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || isBuiltinCommand(root, args[0]) {
+		return args, false
+	}
+	if _, err := os.Stat(config.AwlessHome); err != nil {
+		return args, false
+	}
+	os.Setenv("__AWLESS_HOME", config.AwlessHome)
+	if err := config.LoadProjectConfig(); err != nil {
+		return args, false
+	}
+	defaults, err := config.LoadDefaults()
+	if err != nil {
+		return args, false
+	}
+	return config.ExpandShortcut(defaults, args)
+}
+
+func isBuiltinCommand(root *cobra.Command, name string) bool {
+	for _, cmd := range root.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestIsBuiltinCommand(t *testing.T) {
+	root := &cobra.Command{Use: "awless"}
+	root.AddCommand(&cobra.Command{Use: "list", Aliases: []string{"ls"}})
+
+	for name, want := range map[string]bool{"list": true, "ls": true, "lsr": false} {
+		if got := isBuiltinCommand(root, name); got != want {
+			t.Fatalf("%s: got %t, want %t", name, got, want)
+		}
+	}
+	if _, ok := expandShortcut(root, []string{"ls", "instances"}); ok {
+		t.Fatal("expected builtin command not to be expanded")
+	}
+}
@@ -48,6 +48,9 @@ var showCmd = &cobra.Command{
 		}
 
 		id := args[0]
+		if resolved, ok := resolveUserAlias(id); ok {
+			id = resolved
+		}
 		notFound := fmt.Sprintf("resource with id %s not found", id)
 
 		var resource *graph.Resource
@@ -71,10 +74,16 @@ var showCmd = &cobra.Command{
 			srv, err := cloud.GetServiceForType(resource.Type().String())
 			exitOn(err)
 			logger.Verbosef("syncing service for %s type", resource.Type())
-			_, err = sync.DefaultSyncer.Sync(srv)
+			graphs, err := sync.DefaultSyncer.Sync(srv)
 			if err != nil {
 				logger.Error(err)
 			}
+			if g, ok := graphs[srv.Name()]; ok {
+				if synced, err := g.GetResource(resource.Type(), resource.Id()); err == nil && len(synced.Properties) > 0 {
+					resource, gph = synced, g
+				}
+			}
+			loadLazyProperties(gph, srv.Name(), resource)
 		}
 
 		if resource != nil {
@@ -30,70 +30,158 @@ import (
 	"github.com/wallix/awless/graph"
 )
 
+var (
+	ssmFlag          bool
+	keyPathFlag      string
+	forwardAgentFlag bool
+)
+
 func init() {
 	RootCmd.AddCommand(sshCmd)
+
+	sshCmd.Flags().BoolVar(&ssmFlag, "ssm", false, "Open a SSM Session Manager session instead of SSH (i.e. no public IP or port 22 closed)")
+	sshCmd.Flags().StringVarP(&keyPathFlag, "identity", "i", "", "Private key file to use instead of the instance keypair found in awless keys folder")
+	sshCmd.Flags().BoolVarP(&forwardAgentFlag, "forward-agent", "A", false, "Forward your local ssh-agent to the instance")
 }
 
 var sshCmd = &cobra.Command{
 	Use:                "ssh [user@]instance",
 	Short:              "Launch a SSH (Secure Shell) session connecting to an instance given an id or alias",
-	PersistentPreRun:   applyHooks(initAwlessEnvHook, initCloudServicesHook, verifyNewVersionHook),
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook, verifyNewVersionHook),
 	PersistentPostRunE: saveHistoryHook,
 
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
 			return fmt.Errorf("instance required")
 		}
-		var instanceID string
-		var user string
-		if strings.Contains(args[0], "@") {
-			user = strings.Split(args[0], "@")[0]
-			instanceID = strings.Split(args[0], "@")[1]
-		} else {
-			instanceID = args[0]
+
+		if ssmFlag {
+			exitOn(instanceSSMSession(args[0]))
+			return nil
 		}
 
-		instancesGraph, err := aws.InfraService.FetchByType(graph.Instance.String())
+		client, err := instanceSSHClient(args[0])
 		exitOn(err)
+		defer client.Close()
+
+		exitOn(console.InteractiveTerminal(client, forwardAgentFlag))
+		return nil
+	},
+}
+
+// instanceSSHClient resolves a '[user@]instance' target (id or alias) to its
+// credentials and returns a connected SSH client. When no user is given,
+// default AMI users are tried in turn.
+func instanceSSHClient(target string) (*ssh.Client, error) {
+	user, instanceID, instancesGraph, err := resolveInstanceTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := instanceCredentialsFromGraph(instancesGraph, instanceID, keyPathFlag)
+	if err != nil {
+		return nil, err
+	}
 
-		a := graph.Alias(instanceID)
-		if id, ok := a.ResolveToId(instancesGraph, graph.Instance); ok {
-			instanceID = id
+	if user != "" {
+		cred.User = user
+		client, err := console.NewSSHClient(config.KeysDir, cred)
+		if err != nil {
+			return nil, withSSMHint(err)
 		}
+		if verboseFlag {
+			log.Printf("Login as '%s' on '%s', using %s", user, cred.IP, describeSSHKey(cred))
+		}
+		return client, nil
+	}
 
-		cred, err := instanceCredentialsFromGraph(instancesGraph, instanceID)
-		exitOn(err)
-		var client *ssh.Client
-		if user != "" {
-			cred.User = user
-			client, err = console.NewSSHClient(config.KeysDir, cred)
-			exitOn(err)
-			if verboseFlag {
-				log.Printf("Login as '%s' on '%s', using key '%s'", user, cred.IP, cred.KeyName)
-			}
-			if err = console.InteractiveTerminal(client); err != nil {
-				exitOn(err)
-			}
-			return nil
+	for _, user := range aws.DefaultAMIUsers {
+		cred.User = user
+		client, err := console.NewSSHClient(config.KeysDir, cred)
+		if err != nil && strings.Contains(err.Error(), "unable to authenticate") {
+			continue
 		}
-		for _, user := range aws.DefaultAMIUsers {
-			cred.User = user
-			client, err = console.NewSSHClient(config.KeysDir, cred)
-			if err != nil && strings.Contains(err.Error(), "unable to authenticate") {
-				continue
-			}
-			exitOn(err)
-			log.Printf("Login as '%s' on '%s', using key '%s'", user, cred.IP, cred.KeyName)
-			if err = console.InteractiveTerminal(client); err != nil {
-				exitOn(err)
-			}
-			return nil
+		if err != nil {
+			return nil, withSSMHint(err)
 		}
+		log.Printf("Login as '%s' on '%s', using %s", user, cred.IP, describeSSHKey(cred))
+		return client, nil
+	}
+
+	return nil, fmt.Errorf("unable to authenticate on instance %s with any of the default users: %s", instanceID, strings.Join(aws.DefaultAMIUsers, ", "))
+}
+
+// instanceSSMSession opens a SSM Session Manager session on a '[user@]instance' target,
+// for instances not reachable through SSH
+func instanceSSMSession(target string) error {
+	_, instanceID, instancesGraph, err := resolveInstanceTarget(target)
+	if err != nil {
 		return err
-	},
+	}
+
+	if err = checkInstanceSSMCapable(instancesGraph, instanceID); err != nil {
+		return err
+	}
+
+	region, profile, err := currentRegionAndProfile()
+	if err != nil {
+		return fmt.Errorf("ssm: %s", err)
+	}
+
+	if verboseFlag {
+		log.Printf("Starting SSM session on '%s' with `aws %s`", instanceID, strings.Join(console.SSMSessionArgs(instanceID, region, profile), " "))
+	}
+	return console.InteractiveSSMSession(instanceID, region, profile)
+}
+
+func withSSMHint(err error) error {
+	if msg := err.Error(); strings.Contains(msg, "timeout") || strings.Contains(msg, "connection refused") {
+		return fmt.Errorf("%s (port 22 might be closed, try with `--ssm`)", msg)
+	}
+	return err
 }
 
-func instanceCredentialsFromGraph(g *graph.Graph, instanceID string) (*console.Credentials, error) {
+func resolveInstanceTarget(target string) (user, instanceID string, instancesGraph *graph.Graph, err error) {
+	if strings.Contains(target, "@") {
+		user = strings.Split(target, "@")[0]
+		instanceID = strings.Split(target, "@")[1]
+	} else {
+		instanceID = target
+	}
+	if id, ok := resolveUserAlias(instanceID); ok {
+		instanceID = id
+	}
+
+	instancesGraph, err = aws.InfraService.FetchByType(graph.Instance.String())
+	if err != nil {
+		return
+	}
+
+	a := graph.Alias(instanceID)
+	if id, ok := a.ResolveToId(instancesGraph, graph.Instance); ok {
+		instanceID = id
+	}
+	return
+}
+
+func checkInstanceSSMCapable(g *graph.Graph, instanceID string) error {
+	inst, err := g.GetResource(graph.Instance, instanceID)
+	if err != nil {
+		return err
+	}
+	if _, ok := inst.Properties["Id"]; !ok {
+		return fmt.Errorf("instance %s not found", instanceID)
+	}
+	if state, ok := inst.Properties["State"]; ok && fmt.Sprint(state) != "running" {
+		return fmt.Errorf("instance %s is not running (state: %v)", instanceID, state)
+	}
+	if _, ok := inst.Properties["Profile"]; !ok {
+		return fmt.Errorf("no instance profile for instance %s: the SSM agent needs one to register with SSM", instanceID)
+	}
+	return nil
+}
+
+func instanceCredentialsFromGraph(g *graph.Graph, instanceID, keyPath string) (*console.Credentials, error) {
 	inst, err := g.GetResource(graph.Instance, instanceID)
 	if err != nil {
 		return nil, err
@@ -101,12 +189,28 @@ func instanceCredentialsFromGraph(g *graph.Graph, instanceID string) (*console.C
 
 	ip, ok := inst.Properties["PublicIp"]
 	if !ok {
-		return nil, fmt.Errorf("no public IP address for instance %s", instanceID)
+		return nil, fmt.Errorf("no public IP address for instance %s (try with `--ssm`)", instanceID)
 	}
 
-	key, ok := inst.Properties["KeyName"]
-	if !ok {
-		return nil, fmt.Errorf("no access key set for instance %s", instanceID)
+	cred := &console.Credentials{IP: fmt.Sprint(ip), User: "", KeyPath: keyPath}
+	if key, ok := inst.Properties["KeyName"]; ok {
+		cred.KeyName = fmt.Sprint(key)
+	} else if keyPath == "" && !console.HasSSHAgent() {
+		return nil, fmt.Errorf("no access key set for instance %s (give one with `-i` or load it in your ssh-agent)", instanceID)
+	}
+	return cred, nil
+}
+
+func describeSSHKey(cred *console.Credentials) string {
+	var keys []string
+	switch {
+	case cred.KeyPath != "":
+		keys = append(keys, fmt.Sprintf("key '%s'", cred.KeyPath))
+	case cred.KeyName != "":
+		keys = append(keys, fmt.Sprintf("key '%s'", cred.KeyName))
+	}
+	if console.HasSSHAgent() {
+		keys = append(keys, "ssh-agent keys")
 	}
-	return &console.Credentials{IP: fmt.Sprint(ip), User: "", KeyName: fmt.Sprint(key)}, nil
+	return strings.Join(keys, " and ")
 }
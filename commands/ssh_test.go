@@ -17,6 +17,7 @@ limitations under the License.
 package commands
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -24,12 +25,15 @@ import (
 )
 
 func TestInstanceCredentialsFromName(t *testing.T) {
+	defer os.Setenv("SSH_AUTH_SOCK", os.Getenv("SSH_AUTH_SOCK"))
+	os.Unsetenv("SSH_AUTH_SOCK")
+
 	g, err := graph.NewGraphFromFile(filepath.Join("testdata", "infra.rdf"))
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	cred, err := instanceCredentialsFromGraph(g, "inst_1")
+	cred, err := instanceCredentialsFromGraph(g, "inst_1", "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -42,14 +46,50 @@ func TestInstanceCredentialsFromName(t *testing.T) {
 	if got, want := cred.User, ""; got != want {
 		t.Fatalf("got %s, want %s", got, want)
 	}
-	_, err = instanceCredentialsFromGraph(g, "inst_12")
+	_, err = instanceCredentialsFromGraph(g, "inst_12", "")
 	if err == nil {
 		t.Fatal("expected error got none")
 	}
-	if _, err := instanceCredentialsFromGraph(g, "inst_3"); err == nil {
+	if _, err := instanceCredentialsFromGraph(g, "inst_3", ""); err == nil {
 		t.Fatal("expected error got none")
 	}
-	if _, err := instanceCredentialsFromGraph(g, "inst_2"); err == nil {
+	if _, err := instanceCredentialsFromGraph(g, "inst_2", ""); err == nil {
 		t.Fatal("expected error got none")
 	}
+
+	cred, err = instanceCredentialsFromGraph(g, "inst_2", "/path/to/id_rsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cred.KeyPath, "/path/to/id_rsa"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := cred.KeyName, ""; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestCheckInstanceSSMCapable(t *testing.T) {
+	g := graph.NewGraph()
+	running := graph.InitResource("inst_1", graph.Instance)
+	running.Properties["Id"] = "inst_1"
+	running.Properties["State"] = "running"
+	running.Properties["Profile"] = "arn:aws:iam::0123456789:instance-profile/ssm"
+	noProfile := graph.InitResource("inst_2", graph.Instance)
+	noProfile.Properties["Id"] = "inst_2"
+	noProfile.Properties["State"] = "running"
+	stopped := graph.InitResource("inst_3", graph.Instance)
+	stopped.Properties["Id"] = "inst_3"
+	stopped.Properties["State"] = "stopped"
+	stopped.Properties["Profile"] = "arn:aws:iam::0123456789:instance-profile/ssm"
+	g.AddResource(running, noProfile, stopped)
+
+	if err := checkInstanceSSMCapable(g, "inst_1"); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"inst_2", "inst_3", "inst_4"} {
+		if err := checkInstanceSSMCapable(g, id); err == nil {
+			t.Fatalf("%s: expected error got none", id)
+		}
+	}
 }
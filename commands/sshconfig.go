@@ -0,0 +1,143 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/sync"
+)
+
+var sshConfigUserFlag string
+
+func init() {
+	RootCmd.AddCommand(sshConfigCmd)
+
+	sshConfigCmd.Flags().StringVar(&sshConfigUserFlag, "user", "", "Default user to login as on instances")
+}
+
+var sshConfigCmd = &cobra.Command{
+	Use:                "ssh-config",
+	Short:              "Generate a SSH config with Host entries for every running instance. Ex: awless ssh-config > ~/.ssh/awless_config",
+	PersistentPreRun:   applyHooks(initAwlessEnvHook, initCloudServicesHook, verifyNewVersionHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var g *graph.Graph
+		if localFlag {
+			g = sync.LoadCurrentLocalGraph(aws.ServicePerResourceType[graph.Instance.String()])
+		} else {
+			var err error
+			g, err = aws.InfraService.FetchByType(graph.Instance.String())
+			exitOn(err)
+		}
+
+		exitOn(writeSSHConfig(os.Stdout, g, config.KeysDir, sshConfigUserFlag))
+		return nil
+	},
+}
+
+type sshHost struct {
+	alias, ip, keyName, vpc string
+	public                  bool
+}
+
+// writeSSHConfig writes a ssh_config Host block for each running instance.
+// Instances without public IP are reached through a public instance of the same VPC
+func writeSSHConfig(w io.Writer, g *graph.Graph, keysDir, user string) error {
+	instances, err := g.GetAllResources(graph.Instance)
+	if err != nil {
+		return err
+	}
+	sort.Sort(graph.ResourceById(instances))
+
+	var hosts []*sshHost
+	bastions := make(map[string]*sshHost)
+	aliases := make(map[string]bool)
+	for _, inst := range instances {
+		if state, ok := inst.Properties["State"]; ok && fmt.Sprint(state) != "running" {
+			continue
+		}
+		h := &sshHost{alias: sshHostAlias(inst)}
+		if aliases[h.alias] {
+			h.alias = fmt.Sprintf("%s-%s", h.alias, inst.Id())
+		}
+		aliases[h.alias] = true
+		if ip, ok := inst.Properties["PublicIp"]; ok {
+			h.ip, h.public = fmt.Sprint(ip), true
+		} else if ip, ok := inst.Properties["PrivateIp"]; ok {
+			h.ip = fmt.Sprint(ip)
+		} else {
+			continue
+		}
+		if key, ok := inst.Properties["KeyName"]; ok {
+			h.keyName = fmt.Sprint(key)
+		}
+		if vpc, ok := inst.Properties["VpcId"]; ok {
+			h.vpc = fmt.Sprint(vpc)
+		}
+		if _, exists := bastions[h.vpc]; h.public && !exists {
+			bastions[h.vpc] = h
+		}
+		hosts = append(hosts, h)
+	}
+
+	fmt.Fprintln(w, "# Generated by awless ssh-config. Do not edit: regenerate it after your instances change.")
+	for _, h := range hosts {
+		fmt.Fprintf(w, "\nHost %s\n", h.alias)
+		fmt.Fprintf(w, "  HostName %s\n", h.ip)
+		if user != "" {
+			fmt.Fprintf(w, "  User %s\n", user)
+		}
+		if h.keyName != "" {
+			fmt.Fprintf(w, "  IdentityFile %s\n", keyFilePath(keysDir, h.keyName))
+		}
+		if !h.public {
+			if bastion, ok := bastions[h.vpc]; ok {
+				fmt.Fprintf(w, "  ProxyCommand ssh -W %%h:%%p %s\n", bastion.alias)
+			} else {
+				fmt.Fprintf(w, "  # no public instance found in %s to proxy through\n", h.vpc)
+			}
+		}
+	}
+
+	return nil
+}
+
+func sshHostAlias(inst *graph.Resource) string {
+	if name, ok := inst.Properties["Name"]; ok && fmt.Sprint(name) != "" {
+		return strings.Join(strings.Fields(fmt.Sprint(name)), "-")
+	}
+	return inst.Id()
+}
+
+func keyFilePath(keysDir, keyName string) string {
+	path := filepath.Join(keysDir, keyName)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return path + ".pem"
+	}
+	return path
+}
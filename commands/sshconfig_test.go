@@ -0,0 +1,75 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/wallix/awless/graph"
+)
+
+func TestWriteSSHConfig(t *testing.T) {
+	g := graph.NewGraph()
+	newInstance := func(id string, props map[string]interface{}) *graph.Resource {
+		inst := graph.InitResource(id, graph.Instance)
+		inst.Properties["Id"] = id
+		for k, v := range props {
+			inst.Properties[k] = v
+		}
+		return inst
+	}
+	g.AddResource(
+		newInstance("inst_1", map[string]interface{}{"Name": "my bastion", "State": "running", "PublicIp": "1.2.3.4", "KeyName": "my-key", "VpcId": "vpc_1"}),
+		newInstance("inst_2", map[string]interface{}{"Name": "backend", "State": "running", "PrivateIp": "10.0.0.2", "KeyName": "my-key", "VpcId": "vpc_1"}),
+		newInstance("inst_3", map[string]interface{}{"Name": "backend", "State": "running", "PrivateIp": "10.0.0.3", "VpcId": "vpc_1"}),
+		newInstance("inst_4", map[string]interface{}{"State": "running", "PrivateIp": "10.1.0.4", "VpcId": "vpc_2"}),
+		newInstance("inst_5", map[string]interface{}{"Name": "stopped", "State": "stopped", "PublicIp": "5.6.7.8"}),
+	)
+
+	var buff bytes.Buffer
+	if err := writeSSHConfig(&buff, g, "/keys", "ec2-user"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# Generated by awless ssh-config. Do not edit: regenerate it after your instances change.
+
+Host my-bastion
+  HostName 1.2.3.4
+  User ec2-user
+  IdentityFile /keys/my-key.pem
+
+Host backend
+  HostName 10.0.0.2
+  User ec2-user
+  IdentityFile /keys/my-key.pem
+  ProxyCommand ssh -W %h:%p my-bastion
+
+Host backend-inst_3
+  HostName 10.0.0.3
+  User ec2-user
+  ProxyCommand ssh -W %h:%p my-bastion
+
+Host inst_4
+  HostName 10.1.0.4
+  User ec2-user
+  # no public instance found in vpc_2 to proxy through
+`
+	if got, want := buff.String(), expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
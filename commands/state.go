@@ -0,0 +1,206 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/statecrypt"
+	"github.com/wallix/awless/sync"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const stateSaltFilename = "state.salt"
+
+func init() {
+	RootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateEncryptCmd)
+	stateCmd.AddCommand(stateDecryptCmd)
+	stateCmd.AddCommand(stateExportCmd)
+}
+
+var stateCmd = &cobra.Command{
+	Use:                "state",
+	Short:              "Encrypt or decrypt the local state (run history, synced graphs and cached credentials), or export synced graphs",
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook),
+	PersistentPostRunE: saveHistoryHook,
+}
+
+var stateEncryptCmd = &cobra.Command{
+	Use:   "encrypt {keychain|passphrase}",
+	Short: "Encrypt the local state with a key stored in the OS keychain or derived from a passphrase",
+	Long: fmt.Sprintf(`Encrypt the local state with a key stored in the OS keychain (macOS keychain, or libsecret on Linux)
+or derived from a passphrase, read from %s or prompted.
+
+The existing run history and graphs are encrypted right away. Snapshots of the graphs
+committed before are left as they were: delete %s to drop them.`, statecrypt.PassphraseEnv, filepath.Join(config.RepoDir, ".git")),
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("expecting the encryption mode: keychain or passphrase")
+		}
+		mode := args[0]
+		return rewriteLocalState(func() error {
+			return statecrypt.Enable(mode, stateSaltFile(), func() (string, error) { return promptStatePassphrase(true) })
+		}, func(db *database.DB) error {
+			return db.SetDefault(statecrypt.ModeKey, mode)
+		})
+	},
+}
+
+var stateDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Decrypt the local state and disable its encryption",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !statecrypt.Enabled() {
+			return errors.New("local state is not encrypted")
+		}
+		return rewriteLocalState(func() error {
+			statecrypt.Disable()
+			return nil
+		}, func(db *database.DB) error {
+			return db.UnsetDefault(statecrypt.ModeKey)
+		})
+	},
+}
+
+var stateExportCmd = &cobra.Command{
+	Use:   "export SERVICE",
+	Short: "Print the local graph of a service (ex: infra, access) as text triples, whatever its format",
+	Long: fmt.Sprintf(`Print the local graph of a service (ex: infra, access) as text triples, whatever its format.
+
+Local graphs are written as binary snapshots, faster to load. Text triples remain available to
+other tools with this command, or for all graphs with: awless config set %s %s`, sync.GraphFormatKey, sync.TextGraphFormat),
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("expecting the service of the graph to export (ex: infra, access)")
+		}
+		service := args[0]
+		if _, err := os.Stat(filepath.Join(config.RepoDir, service+".rdf")); err != nil {
+			return fmt.Errorf("no local graph for %s: %s", service, err)
+		}
+		if err := sync.CheckLocalGraph(service); err != nil {
+			return fmt.Errorf("reading local %s graph: %s", service, err)
+		}
+		data, err := sync.LoadCurrentLocalGraph(service).Marshal()
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	},
+}
+
+// initStateEncryption enables the encryption of the local state as set in config.
+// The key is only retrieved when state is read or written
+func initStateEncryption(defaults map[string]interface{}) error {
+	mode, _ := defaults[statecrypt.ModeKey].(string)
+	if mode == "" {
+		return nil
+	}
+	return statecrypt.Enable(mode, stateSaltFile(), func() (string, error) { return promptStatePassphrase(false) })
+}
+
+func stateSaltFile() string {
+	return filepath.Join(config.AwlessHome, stateSaltFilename)
+}
+
+func promptStatePassphrase(confirm bool) (string, error) {
+	if config.NonInteractive || !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("missing passphrase of the local state: export %s", statecrypt.PassphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, "Local state passphrase: ")
+	pass, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil || !confirm {
+		return string(pass), err
+	}
+	fmt.Fprint(os.Stderr, "Confirm passphrase: ")
+	again, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if string(again) != string(pass) {
+		return "", errors.New("passphrases do not match")
+	}
+	return string(pass), nil
+}
+
+// rewriteLocalState reads the whole local state with the current encryption,
+// switches the encryption, then writes the state back
+func rewriteLocalState(switchEncryption func() error, saveConfig func(*database.DB) error) error {
+	db, err, dbclose := database.Current()
+	if err != nil {
+		return err
+	}
+	defer dbclose()
+
+	executions, err := db.ListTemplateExecutions()
+	if err != nil {
+		return fmt.Errorf("reading run history: %s", err)
+	}
+	graphFiles, err := filepath.Glob(filepath.Join(config.RepoDir, "*.rdf"))
+	if err != nil {
+		return err
+	}
+	graphs := make(map[string][]byte)
+	for _, path := range graphFiles {
+		if graphs[path], err = statecrypt.ReadFile(path); err != nil {
+			return fmt.Errorf("reading %s: %s", path, err)
+		}
+	}
+
+	if err := switchEncryption(); err != nil {
+		return err
+	}
+
+	for _, exec := range executions {
+		if err := db.AddTemplateExecution(exec); err != nil {
+			return fmt.Errorf("writing run %s: %s", exec.ID, err)
+		}
+	}
+	for path, data := range graphs {
+		if err := statecrypt.WriteFile(path, data, 0600); err != nil {
+			return fmt.Errorf("writing %s: %s", path, err)
+		}
+	}
+	if err := saveConfig(db); err != nil {
+		return err
+	}
+	// cached credentials are short lived: drop them rather than rewriting them
+	if err := aws.ClearCredentialsCache(); err != nil {
+		return err
+	}
+
+	action := "decrypted"
+	if statecrypt.Enabled() {
+		action = "encrypted"
+	}
+	logger.Infof("local state %s: %d runs, %d graphs", action, len(executions), len(graphs))
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/database"
+)
+
+var localStatsFlag bool
+
+func init() {
+	RootCmd.AddCommand(statsCmd)
+	statsCmd.AddCommand(statsUsageCmd)
+	statsCmd.AddCommand(statsResetCmd)
+
+	RootCmd.PersistentFlags().BoolVar(&localStatsFlag, "local-stats", false, "Count usage of this command in local stats only (never sent anywhere)")
+}
+
+var statsCmd = &cobra.Command{
+	Use:                "stats",
+	Short:              fmt.Sprintf("Show usage stats kept locally. Enable them with `awless config set %s %s`", database.StatsModeKey, database.StatsLocal),
+	PersistentPreRunE:  initAwlessEnvHook,
+	PersistentPostRunE: saveHistoryHook,
+}
+
+var statsUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show the local usage counters of commands and templates",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err, close := database.Current()
+		exitOn(err)
+		defer close()
+
+		stats, err := db.GetUsageStats()
+		exitOn(err)
+
+		if !usageStatsEnabled(db) {
+			fmt.Printf("Local stats are disabled. Enable them with `awless config set %s %s` or per command with `--local-stats`\n\n", database.StatsModeKey, database.StatsLocal)
+		}
+		if stats.Since.IsZero() {
+			fmt.Println("No usage recorded")
+			return nil
+		}
+
+		fmt.Printf("Usage since %s\n\n", stats.Since.Format("2006-01-02 15:04"))
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+		printCounters(w, "Command", stats.Commands)
+		if len(stats.Templates) > 0 {
+			fmt.Fprintln(w)
+			printCounters(w, "Template", stats.Templates)
+		}
+		return w.Flush()
+	},
+}
+
+var statsResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Delete the local usage counters",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err, close := database.Current()
+		exitOn(err)
+		defer close()
+		exitOn(db.DeleteUsageStats())
+		return nil
+	},
+}
+
+func printCounters(w *tabwriter.Writer, title string, counters map[string]int) {
+	if len(counters) == 0 {
+		return
+	}
+	var names []string
+	for name := range counters {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counters[names[i]] != counters[names[j]] {
+			return counters[names[i]] > counters[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	fmt.Fprintf(w, "%s\tCount\t\n", title)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\t%d\t\n", name, counters[name])
+	}
+}
+
+// usageStatsEnabled tells whether usage is counted: only on demand and only locally
+func usageStatsEnabled(db *database.DB) bool {
+	if localStatsFlag {
+		return true
+	}
+	mode, _ := db.GetDefaultString(database.StatsModeKey)
+	return mode == database.StatsLocal
+}
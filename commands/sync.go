@@ -18,12 +18,14 @@ package commands
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/wallix/awless/aws"
 	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/config"
 	"github.com/wallix/awless/graph"
 	"github.com/wallix/awless/logger"
 	"github.com/wallix/awless/sync"
@@ -31,6 +33,7 @@ import (
 
 var (
 	servicesToSyncFlags map[string]*bool
+	fullSyncFlag        bool
 )
 
 func init() {
@@ -41,30 +44,42 @@ func init() {
 		servicesToSyncFlags[service] = new(bool)
 		syncCmd.Flags().BoolVar(servicesToSyncFlags[service], service, false, fmt.Sprintf("Sync '%s' service only", service))
 	}
+	syncCmd.Flags().BoolVar(&fullSyncFlag, "full", false, "Sync all services, instead of resuming with the services left by a failed sync")
 }
 
 var syncCmd = &cobra.Command{
 	Use:                "sync",
 	Short:              "Manual sync of your remote resources to your local rdf store. For example when auto sync unset",
+	Long:               "Manual sync of your remote resources to your local rdf store. For example when auto sync unset.\n\nWhen a sync fails halfway (throttling, network failure, ...), the services not synced completely are recorded and the next sync of the same target resumes with them only. Use --full to sync all services again.",
 	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook, initSyncerHook, verifyNewVersionHook),
 	PersistentPostRunE: saveHistoryHook,
 
 	RunE: func(cmd *cobra.Command, args []string) error {
+		target := describeAWSTarget(config.Config.Defaults)
+		progress, err := sync.LoadProgress(target)
+		if err != nil {
+			logger.Warningf("cannot load progress of last sync: %s", err)
+		}
+		resume := progress != nil && !fullSyncFlag
+
 		var services []cloud.Service
 		displayAllServices := true
 		for _, srv := range cloud.ServiceRegistry {
-			if *servicesToSyncFlags[srv.Name()] {
+			if isServiceToSync(srv.Name()) {
 				displayAllServices = false
 			}
 		}
 		for _, srv := range cloud.ServiceRegistry {
-			if displayAllServices || *servicesToSyncFlags[srv.Name()] {
+			if resume && displayAllServices {
+				if containsString(progress.Pending, srv.Name()) {
+					services = append(services, srv)
+				}
+			} else if displayAllServices || isServiceToSync(srv.Name()) {
 				services = append(services, srv)
 			}
 		}
-		localGraphs := make(map[string]*graph.Graph)
-		for _, service := range services {
-			localGraphs[service.Name()] = sync.LoadCurrentLocalGraph(service.Name())
+		if resume && displayAllServices {
+			logger.Infof("resuming sync failed on %s: syncing %s only (use --full to sync all services)", progress.FailedAt.Local().Format("Mon Jan 2 15:04:05"), strings.Join(progress.Pending, ", "))
 		}
 		logger.Info("running sync: fetching remote resources for local store")
 		start := time.Now()
@@ -73,6 +88,17 @@ var syncCmd = &cobra.Command{
 		if err != nil {
 			logger.Verbose(err)
 		}
+		var pending []string
+		if progress != nil {
+			pending = progress.Pending
+		}
+		pending = pendingServices(pending, services, sync.FailedServices(err))
+		if perr := sync.SaveProgress(target, pending); perr != nil {
+			logger.Warningf("cannot save progress of sync: %s", perr)
+		}
+		if len(pending) > 0 {
+			logger.Warningf("services not synced completely: %s. Run `awless sync` again to resume with them", strings.Join(pending, ", "))
+		}
 
 		for k, g := range graphs {
 			displaySyncStats(k, g)
@@ -83,21 +109,59 @@ var syncCmd = &cobra.Command{
 	},
 }
 
+// isServiceToSync returns true if the service was selected with its flag.
+// Services of other providers than AWS have no flag.
+func isServiceToSync(name string) bool {
+	flag, ok := servicesToSyncFlags[name]
+	return ok && *flag
+}
+
+// pendingServices returns the services left to sync: the previously pending ones that were
+// not synced again, and the ones that just failed
+func pendingServices(previous []string, synced []cloud.Service, failed []string) []string {
+	var pending []string
+	for _, name := range previous {
+		var done bool
+		for _, srv := range synced {
+			if srv.Name() == name {
+				done = true
+			}
+		}
+		if !done && !containsString(failed, name) {
+			pending = append(pending, name)
+		}
+	}
+	pending = append(pending, failed...)
+	sort.Strings(pending)
+	return pending
+}
+
 func displaySyncStats(serviceName string, g *graph.Graph) {
 	var strs []string
-	for rt, service := range aws.ServicePerResourceType {
-		if service == serviceName {
-			res, err := g.GetAllResources(graph.ResourceType(rt))
-			if err != nil {
-				continue
-			}
-			nbRes := len(res)
-			if nbRes > 1 {
-				strs = append(strs, fmt.Sprintf("%d %s", nbRes, cloud.PluralizeResource(rt)))
-			} else {
-				strs = append(strs, fmt.Sprintf("%d %s", nbRes, rt))
-			}
+	srv, ok := cloud.ServiceRegistry[serviceName]
+	if !ok {
+		return
+	}
+	for _, rt := range srv.ResourceTypes() {
+		res, err := g.GetAllResources(graph.ResourceType(rt))
+		if err != nil {
+			continue
+		}
+		nbRes := len(res)
+		if nbRes > 1 {
+			strs = append(strs, fmt.Sprintf("%d %s", nbRes, cloud.PluralizeResource(rt)))
+		} else {
+			strs = append(strs, fmt.Sprintf("%d %s", nbRes, rt))
 		}
 	}
 	logger.Infof("-> %s: %s", serviceName, strings.Join(strs, ", "))
 }
+
+func containsString(arr []string, s string) bool {
+	for _, e := range arr {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
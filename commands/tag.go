@@ -0,0 +1,189 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/sync"
+	"github.com/wallix/awless/template/driver"
+)
+
+var tagOnFlag []string
+
+func init() {
+	RootCmd.AddCommand(tagCmd)
+	tagCmd.AddCommand(tagSetCmd)
+	tagCmd.AddCommand(tagRmCmd)
+
+	tagCmd.PersistentFlags().StringSliceVar(&tagOnFlag, "on", []string{}, "Ids or aliases of the resources to tag. Ex: --on i-1,i-2,vol-3")
+}
+
+var tagCmd = &cobra.Command{
+	Use:                "tag",
+	Short:              fmt.Sprintf("Set or remove tags on many resources at once (%s)", strings.Join(aws.TaggableResourceTypes, ", ")),
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, requireAWSProviderHook, initCloudServicesHook, verifyNewVersionHook),
+	PersistentPostRunE: saveHistoryHook,
+}
+
+var tagSetCmd = &cobra.Command{
+	Use:   "set {key=value}...",
+	Short: "Set tags on resources. Ex: awless tag set env=prod --on i-1,i-2,vol-3",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("at least one tag key=value required")
+		}
+		tags := make(map[string]string)
+		for _, arg := range args {
+			splits := strings.SplitN(arg, "=", 2)
+			if len(splits) != 2 || splits[0] == "" {
+				return fmt.Errorf("invalid tag '%s', expecting key=value", arg)
+			}
+			tags[splits[0]] = splits[1]
+		}
+		exitOn(applyTagging(tagOnFlag, tags, nil))
+		return nil
+	},
+}
+
+var tagRmCmd = &cobra.Command{
+	Use:   "rm {key}...",
+	Short: "Remove tags from resources given their keys. Ex: awless tag rm env --on i-1,i-2",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("at least one tag key required")
+		}
+		exitOn(applyTagging(tagOnFlag, nil, args))
+		return nil
+	},
+}
+
+func applyTagging(targets []string, set map[string]string, removed []string) error {
+	if readOnlyMode() {
+		return &driver.ReadOnlyError{Statement: "tag"}
+	}
+	if len(targets) == 0 {
+		return errors.New("resources to tag required with --on")
+	}
+	perType, err := groupTagTargetsByType(targets)
+	if err != nil {
+		return err
+	}
+
+	infra, storage := aws.InfraService.(*aws.Infra), aws.StorageService.(*aws.Storage)
+
+	var types []string
+	for t := range perType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	for _, resType := range types {
+		ids := perType[resType]
+		switch resType {
+		case "loadbalancer", "targetgroup":
+			if set != nil {
+				err = infra.TagLoadBalancingResources(ids, set)
+			} else {
+				err = infra.UntagLoadBalancingResources(ids, removed)
+			}
+		case "bucket":
+			if set != nil {
+				err = storage.TagBuckets(ids, set)
+			} else {
+				err = storage.UntagBuckets(ids, removed)
+			}
+		default:
+			if set != nil {
+				err = infra.TagResources(ids, set)
+			} else {
+				err = infra.UntagResources(ids, removed)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("tagging %s: %s", cloud.PluralizeResource(resType), err)
+		}
+		if set != nil {
+			logger.Infof("tagged %s %s", cloud.PluralizeResource(resType), strings.Join(ids, ", "))
+		} else {
+			logger.Infof("untagged %s %s", cloud.PluralizeResource(resType), strings.Join(ids, ", "))
+		}
+	}
+
+	updateLocalTags(perType, set, removed)
+	return nil
+}
+
+func groupTagTargetsByType(targets []string) (map[string][]string, error) {
+	perType := make(map[string][]string)
+	for _, target := range targets {
+		id := target
+		if resolved, ok := resolveUserAlias(id); ok {
+			id = resolved
+		}
+		var resType string
+		if res, _ := findResourceInLocalGraphs(id); res != nil {
+			id, resType = res.Id(), res.Type().String()
+		} else if resType = resourceTypeFromIdPrefix(id); resType == "" {
+			return perType, fmt.Errorf("cannot find type of '%s' in local resources. You might want to perform an `awless sync`", target)
+		}
+		if !aws.IsTaggable(resType) {
+			return perType, fmt.Errorf("%s: cannot tag a %s", target, resType)
+		}
+		perType[resType] = append(perType[resType], id)
+	}
+	return perType, nil
+}
+
+// updateLocalTags reflects the tagging in the local graphs, without waiting for the next sync
+func updateLocalTags(perType map[string][]string, set map[string]string, removed []string) {
+	graphs := make(map[string]*graph.Graph)
+	for resType, ids := range perType {
+		srvName := aws.ServicePerResourceType[resType]
+		g, ok := graphs[srvName]
+		if !ok {
+			g = sync.LoadCurrentLocalGraph(srvName)
+			graphs[srvName] = g
+		}
+		for _, id := range ids {
+			res, err := g.GetResource(graph.ResourceType(resType), id)
+			if err != nil || len(res.Properties) == 0 {
+				continue
+			}
+			for k, v := range aws.TagsPropertiesUpdate(res, set, removed) {
+				if err = g.SetResourceProperty(res, k, v); err != nil {
+					logger.Verbosef("cannot update local %s: %s", res, err)
+				}
+			}
+		}
+	}
+	for srvName, g := range graphs {
+		if err := sync.SaveLocalGraph(srvName, g); err != nil {
+			logger.Verbosef("cannot save local %s resources: %s", srvName, err)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws"
+	awsdriver "github.com/wallix/awless/aws/driver"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template"
+	"github.com/wallix/awless/template/ast"
+	"github.com/wallix/awless/template/local"
+)
+
+func init() {
+	RootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateFromCmd)
+	templateCmd.AddCommand(templatePolicyCmd)
+	templateCmd.AddCommand(templateCacheCmd)
+	templateCmd.AddCommand(templateFmtCmd)
+
+	templateCacheCmd.Flags().BoolVar(&templateCacheClearFlag, "clear", false, "Remove the cached templates and reset the stats")
+}
+
+var templateCacheClearFlag bool
+
+// parsedTemplates caches the templates run from files, so that repeated runs skip parsing
+func parsedTemplates() *template.Cache {
+	return template.NewCache(filepath.Join(config.AwlessHome, "cache", "templates"))
+}
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Generate templates from your existing infrastructure, or the IAM policy needed to run them",
+}
+
+var templateFromCmd = &cobra.Command{
+	Use:                "from {id or alias}",
+	Short:              "Print the template statements creating a copy of a live resource (instance, securitygroup, volume, subnet, vpc, bucket, ...). Ex: awless template from i-0abc > web.awls",
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook, verifyNewVersionHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("resource id or alias required")
+		}
+		id := args[0]
+		if resolved, ok := resolveUserAlias(id); ok {
+			id = resolved
+		}
+
+		var resType string
+		if local, _ := findResourceInLocalGraphs(id); local != nil {
+			id, resType = local.Id(), local.Type().String()
+		} else if resType = resourceTypeFromIdPrefix(id); resType == "" {
+			exitOn(fmt.Errorf("resource %s not found in local snapshot. You might want to perform an `awless sync`", id))
+		}
+
+		srv, err := cloud.GetServiceForType(resType)
+		exitOn(err)
+		g, err := srv.FetchByType(resType)
+		exitOn(err)
+		live, err := g.GetResource(graph.ResourceType(resType), id)
+		exitOn(err)
+		if _, ok := live.Properties["Id"]; !ok {
+			exitOn(fmt.Errorf("%s[%s] not found", id, resType))
+		}
+
+		tpl, err := aws.TemplateFrom(live)
+		exitOn(err)
+		fmt.Print(tpl)
+		return nil
+	},
+}
+
+var templatePolicyCmd = &cobra.Command{
+	Use:                "policy {template filepath}",
+	Short:              "Print the least-privilege IAM policy allowing to run a template and to revert it, for scoped CI roles. Ex: awless template policy infra.awls",
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("missing awless template file path")
+		}
+		content, err := ioutil.ReadFile(args[0])
+		exitOn(err)
+		templ, err := parsedTemplates().Parse(string(content))
+		exitOn(err)
+
+		policy, err := templatePolicy(templ)
+		exitOn(err)
+		b, err := json.MarshalIndent(policy, "", "  ")
+		exitOn(err)
+		fmt.Println(string(b))
+		return nil
+	},
+}
+
+var templateCacheCmd = &cobra.Command{
+	Use:                "cache",
+	Short:              "Show the stats of the cache of parsed template files (ex: repeated runs in CI), or clear it",
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache := parsedTemplates()
+		if templateCacheClearFlag {
+			exitOn(cache.Clear())
+			logger.Info("template cache cleared")
+			return nil
+		}
+		stats, err := cache.Stats()
+		exitOn(err)
+		fmt.Printf("Entries: %d (%d bytes)\n", stats.Entries, stats.Size)
+		fmt.Printf("Hits: %d, misses: %d (hit ratio %.0f%%)\n", stats.Hits, stats.Misses, 100*stats.HitRatio())
+		return nil
+	},
+}
+
+var templateFmtCmd = &cobra.Command{
+	Use:                "fmt {template filepaths...}",
+	Short:              "Rewrite template files in their canonical format (spacing, params sorted, declarations and comments aligned). Ex: awless template fmt infra.awls",
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("missing awless template file paths")
+		}
+		for _, path := range args {
+			changed, err := formatTemplateFile(path)
+			exitOn(err)
+			if changed {
+				logger.Infof("formatted %s", path)
+			}
+		}
+		return nil
+	},
+}
+
+// formatTemplateFile rewrites the template file in its canonical format, returning whether it changed
+func formatTemplateFile(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	templ, err := template.Parse(string(content))
+	if err != nil {
+		return false, fmt.Errorf("%s: %s", path, err)
+	}
+	formatted, err := templ.Format()
+	if err != nil {
+		return false, fmt.Errorf("%s: %s", path, err)
+	}
+	if formatted == string(content) {
+		return false, nil
+	}
+	return true, ioutil.WriteFile(path, []byte(formatted), info.Mode())
+}
+
+type iamPolicy struct {
+	Version   string
+	Statement []iamPolicyStatement
+}
+
+type iamPolicyStatement struct {
+	Effect   string
+	Action   []string
+	Resource string
+}
+
+// templatePolicy returns the IAM policy allowing every API call of the template statements
+// and of the statements reverting them. Resources are not known before the run: they are all allowed
+func templatePolicy(templ *template.Template) (*iamPolicy, error) {
+	unique := make(map[string]struct{})
+	add := func(action, entity string, node *ast.CommandNode) error {
+		perms, err := awsdriver.StatementPermissions(action, entity, statementKeys(node)...)
+		if err != nil {
+			return err
+		}
+		for _, perm := range perms {
+			unique[perm] = struct{}{}
+		}
+		return nil
+	}
+	for _, node := range templ.CommandNodesIterator() {
+		if _, isLocal := local.TemplatesDefinitions[node.Action+node.Entity]; isLocal {
+			continue
+		}
+		if err := add(node.Action, node.Entity, node); err != nil {
+			return nil, err
+		}
+		revert, ok := template.RevertAction(node.Action)
+		if !ok {
+			continue
+		}
+		if err := add(revert, node.Entity, node); err != nil {
+			return nil, fmt.Errorf("revert of %s", err)
+		}
+		if node.Action == "create" && node.Entity == "instance" {
+			if err := add("check", "instance", node); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(unique) == 0 {
+		return nil, errors.New("no statement found in template")
+	}
+	var actions []string
+	for perm := range unique {
+		actions = append(actions, perm)
+	}
+	sort.Strings(actions)
+	return &iamPolicy{
+		Version:   "2012-10-17",
+		Statement: []iamPolicyStatement{{Effect: "Allow", Action: actions, Resource: "*"}},
+	}, nil
+}
+
+// statementKeys returns the keys of the params given to the statement, whatever their kind
+func statementKeys(node *ast.CommandNode) (keys []string) {
+	for k := range node.Params {
+		keys = append(keys, k)
+	}
+	for k := range node.Refs {
+		keys = append(keys, k)
+	}
+	for k := range node.Aliases {
+		keys = append(keys, k)
+	}
+	for k := range node.Holes {
+		keys = append(keys, k)
+	}
+	return
+}
+
+func resourceTypeFromIdPrefix(id string) string {
+	prefixes := map[string]string{
+		"i-":      "instance",
+		"sg-":     "securitygroup",
+		"vol-":    "volume",
+		"subnet-": "subnet",
+		"vpc-":    "vpc",
+		"igw-":    "internetgateway",
+		"rtb-":    "routetable",
+	}
+	for prefix, typ := range prefixes {
+		if strings.HasPrefix(id, prefix) {
+			return typ
+		}
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/wallix/awless/template"
+)
+
+func TestTemplatePolicy(t *testing.T) {
+	templ, err := template.Parse(`subnet = create subnet cidr=10.0.0.0/24 vpc={vpc.id}
+create instance subnet=$subnet image=ami-1 type=t2.micro count=1 name=web
+attach policy arn=arn:aws:iam::aws:policy/ReadOnlyAccess user=bob
+update securitygroup id=sg-1 inbound=authorize protocol=tcp cidr=0.0.0.0/0 portrange=443`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, err := templatePolicy(templ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(policy.Statement), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	exp := []string{
+		"ec2:AuthorizeSecurityGroupEgress", "ec2:AuthorizeSecurityGroupIngress", "ec2:CreateSubnet", "ec2:CreateTags",
+		"ec2:DeleteSubnet", "ec2:DescribeInstances", "ec2:RevokeSecurityGroupEgress", "ec2:RevokeSecurityGroupIngress",
+		"ec2:RunInstances", "ec2:TerminateInstances",
+		"iam:AttachUserPolicy", "iam:DetachUserPolicy",
+	}
+	if got, want := policy.Statement[0].Action, exp; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := policy.Statement[0].Resource, "*"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	templ, err = template.Parse("create role name=any")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := templatePolicy(templ); err == nil {
+		t.Fatal("expected error got none")
+	}
+}
+
+func TestFormatTemplateFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "awless-fmt-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("create  vpc name=main cidr=10.0.0.0/16\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	changed, err := formatTemplateFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := changed, true; got != want {
+		t.Fatalf("got %t, want %t", got, want)
+	}
+	content, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(content), "create vpc cidr=10.0.0.0/16 name=main\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if changed, err = formatTemplateFile(f.Name()); err != nil || changed {
+		t.Fatalf("got %t, %v, want unchanged", changed, err)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"strings"
+
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/tracing"
+)
+
+// startCommandSpan starts the root span named after the command path.
+// Other args are not recorded as they may hold secrets
+func startCommandSpan(args []string) {
+	name := "awless"
+	if cmd, _, err := RootCmd.Find(args); err == nil && cmd != nil {
+		name = cmd.CommandPath()
+	}
+	span := tracing.DefaultTracer.StartRoot(name)
+	span.SetAttribute("awless.command", strings.TrimPrefix(name, "awless "))
+}
+
+// exportTraces finishes the command span and sends the trace to the OTLP endpoint if configured
+func exportTraces(err error) {
+	root := tracing.DefaultTracer.Root()
+	if root == nil {
+		return
+	}
+	root.Finish(err)
+	var defaults map[string]interface{}
+	if config.Config != nil {
+		defaults = config.Config.Defaults
+	}
+	url := tracing.Endpoint(defaults)
+	if url == "" {
+		return
+	}
+	if err := tracing.DefaultTracer.Export(url, config.Version); err != nil {
+		logger.Verbosef("tracing: %s", err)
+	}
+}
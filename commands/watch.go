@@ -0,0 +1,143 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/graph"
+)
+
+const (
+	watchDeletedState = "deleted"
+	watchExistsState  = "exists"
+)
+
+var (
+	watchUntilFlag    string
+	watchTimeoutFlag  time.Duration
+	watchIntervalFlag time.Duration
+)
+
+func init() {
+	RootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().StringVar(&watchUntilFlag, "until", "", fmt.Sprintf("State to wait for (ex: running, available, '%s' for resources without state, '%s' to wait for the resource to disappear)", watchExistsState, watchDeletedState))
+	watchCmd.Flags().DurationVar(&watchTimeoutFlag, "timeout", 5*time.Minute, "Give up (with a non zero exit code) after this duration")
+	watchCmd.Flags().DurationVar(&watchIntervalFlag, "interval", 5*time.Second, "Duration between two polls of the resource state")
+}
+
+var watchCmd = &cobra.Command{
+	Use:                "watch {type} {id or alias}",
+	Short:              "Watch a resource state until it reaches the given one. Ex: awless watch instance i-0abc --until running --timeout 5m",
+	PersistentPreRun:   applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook, verifyNewVersionHook),
+	PersistentPostRunE: saveHistoryHook,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return errors.New("resource type and id required")
+		}
+		if watchUntilFlag == "" {
+			return errors.New("state to wait for required with --until")
+		}
+		if watchIntervalFlag <= 0 {
+			return errors.New("--interval must be positive")
+		}
+
+		resType := args[0]
+		srv, err := cloud.GetServiceForType(resType)
+		exitOn(err)
+
+		id := resolveWatchedId(args[1])
+		fetchState := func() (string, error) {
+			g, err := srv.FetchByType(resType)
+			if err != nil {
+				return "", err
+			}
+			return resourceState(g, resType, id)
+		}
+
+		start := time.Now()
+		printState := func(state string) {
+			fmt.Printf("[%s] %s[%s]: %s\n", time.Since(start).Truncate(time.Second), id, resType, state)
+		}
+		exitOn(watchState(fetchState, watchUntilFlag, watchTimeoutFlag, watchIntervalFlag, printState))
+		return nil
+	},
+}
+
+func resolveWatchedId(id string) string {
+	if resolved, ok := resolveUserAlias(id); ok {
+		return resolved
+	}
+	if strings.HasPrefix(id, "@") {
+		if resources := findResourcesByNameInLocalGraphs(id[1:]); len(resources) == 1 {
+			return resources[0].Id()
+		}
+	}
+	return id
+}
+
+// resourceState returns the lowercased state of the resource in the graph,
+// watchExistsState for resources without state or watchDeletedState when it cannot be found
+func resourceState(g *graph.Graph, resType, id string) (string, error) {
+	res, err := g.GetResource(graph.ResourceType(resType), id)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := res.Properties["Id"]; !ok {
+		return watchDeletedState, nil
+	}
+	state, ok := res.Properties["State"]
+	if !ok {
+		return watchExistsState, nil
+	}
+	return strings.ToLower(fmt.Sprint(state)), nil
+}
+
+// watchState polls the state until it equals the wanted one, printing every state change.
+// It errors on timeout or when the resource disappeared while waiting for another state
+func watchState(fetch func() (string, error), until string, timeout, interval time.Duration, onChange func(string)) error {
+	until = strings.ToLower(until)
+	deadline := time.Now().Add(timeout)
+	var last string
+	for {
+		state, err := fetch()
+		if err != nil {
+			return err
+		}
+		if state != last {
+			onChange(state)
+			last = state
+		}
+		if state == until {
+			return nil
+		}
+		if state == watchDeletedState {
+			return fmt.Errorf("resource not found while waiting for state '%s'", until)
+		}
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("timeout after %s: state is '%s', wanted '%s'", timeout, state, until)
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/graph"
+)
+
+func TestResourceState(t *testing.T) {
+	g := graph.NewGraph()
+	inst := graph.InitResource("inst_1", graph.Instance)
+	inst.Properties["Id"] = "inst_1"
+	inst.Properties["State"] = "Running"
+	keypair := graph.InitResource("my-key", graph.Keypair)
+	keypair.Properties["Id"] = "my-key"
+	g.AddResource(inst, keypair)
+
+	tcases := []struct {
+		resType, id, state string
+	}{
+		{"instance", "inst_1", "running"},
+		{"instance", "inst_2", "deleted"},
+		{"keypair", "my-key", "exists"},
+	}
+	for _, tcase := range tcases {
+		state, err := resourceState(g, tcase.resType, tcase.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := state, tcase.state; got != want {
+			t.Fatalf("%s: got %s, want %s", tcase.id, got, want)
+		}
+	}
+}
+
+func TestWatchState(t *testing.T) {
+	fetcher := func(states ...string) func() (string, error) {
+		var i int
+		return func() (string, error) {
+			state := states[i]
+			if i < len(states)-1 {
+				i++
+			}
+			return state, nil
+		}
+	}
+
+	var changes []string
+	onChange := func(s string) { changes = append(changes, s) }
+	if err := watchState(fetcher("pending", "pending", "running"), "RUNNING", time.Second, time.Millisecond, onChange); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := changes, []string{"pending", "running"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if err := watchState(fetcher("pending"), "running", 5*time.Millisecond, time.Millisecond, func(string) {}); err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if err := watchState(fetcher("running", "deleted"), "stopped", time.Second, time.Millisecond, func(string) {}); err == nil {
+		t.Fatal("expected error when resource disappears")
+	}
+	if err := watchState(fetcher("running", "deleted"), "deleted", time.Second, time.Millisecond, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+}
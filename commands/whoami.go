@@ -18,9 +18,14 @@ package commands
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/spf13/cobra"
 	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/logger"
 )
 
 func init() {
@@ -30,13 +35,79 @@ func init() {
 var whoamiCmd = &cobra.Command{
 	Use:                "whoami",
 	Aliases:            []string{"who"},
-	PersistentPreRun:   applyHooks(initAwlessEnvHook, initCloudServicesHook, verifyNewVersionHook),
+	PersistentPreRun:   applyHooks(initAwlessEnvHook, requireAWSProviderHook, initCloudServicesHook, verifyNewVersionHook),
 	PersistentPostRunE: saveHistoryHook,
-	Short:              "Show your identity",
+	Short:              "Show your identity: account, policies, MFA status and credentials source",
 
 	Run: func(cmd *cobra.Command, args []string) {
 		resp, err := aws.SecuAPI.GetCallerIdentity(nil)
 		exitOn(err)
-		fmt.Println(resp)
+
+		arn := awssdk.StringValue(resp.Arn)
+		details := aws.AccessService.(*aws.Access).GetCallerDetails(arn)
+		for _, e := range details.Errors {
+			logger.Verbosef("whoami: cannot resolve %s", e)
+		}
+
+		fmt.Printf("Account: %s", awssdk.StringValue(resp.Account))
+		if len(details.AccountAliases) > 0 {
+			fmt.Printf(" (%s)", strings.Join(details.AccountAliases, ", "))
+		}
+		fmt.Println()
+		fmt.Printf("Arn: %s\n", arn)
+		fmt.Printf("UserId: %s\n", awssdk.StringValue(resp.UserId))
+
+		switch details.Type {
+		case aws.CallerAssumedRole:
+			fmt.Printf("Type: assumed role %s (session %s)\n", details.Name, details.Session)
+		case aws.CallerRoot:
+			fmt.Println("Type: root account")
+		case "":
+		default:
+			fmt.Printf("Type: %s %s\n", details.Type, details.Name)
+		}
+
+		if source, err := aws.SecuAPI.CredentialsSource(); err == nil {
+			fmt.Printf("Credentials: %s\n", source)
+		}
+		if expiry, ok := aws.SecuAPI.SessionExpiry(); ok {
+			fmt.Printf("Session expiry: %s (in %s)\n", expiry.Format(time.RFC1123), time.Until(expiry).Truncate(time.Second))
+		}
+		if details.Type == aws.CallerUser {
+			fmt.Printf("MFA: %s\n", mfaStatus(details.MFADevices))
+		}
+
+		printPolicies("Attached policies", details.AttachedPolicies)
+		printPolicies("Inline policies", details.InlinePolicies)
+		var groups []string
+		for g := range details.GroupPolicies {
+			groups = append(groups, g)
+		}
+		sort.Strings(groups)
+		for _, g := range groups {
+			printPolicies(fmt.Sprintf("Group %s policies", g), details.GroupPolicies[g])
+		}
+
+		if name := currentContextName(); name != "" {
+			fmt.Printf("Context: %s\n", name)
+		}
 	},
 }
+
+func mfaStatus(devices []string) string {
+	switch len(devices) {
+	case 0:
+		return "disabled"
+	case 1:
+		return fmt.Sprintf("enabled (%s)", devices[0])
+	default:
+		return fmt.Sprintf("enabled (%d devices: %s)", len(devices), strings.Join(devices, ", "))
+	}
+}
+
+func printPolicies(title string, policies []string) {
+	if len(policies) == 0 {
+		return
+	}
+	fmt.Printf("%s: %s\n", title, strings.Join(policies, ", "))
+}
@@ -2,29 +2,56 @@ package config
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/wallix/awless/database"
 )
 
 var Config *config
 
+// FlagOverrides are the config values given with command flags (ex: --region, --profile),
+// overriding any other source
+var FlagOverrides = make(map[string]interface{})
+
 type config struct {
 	Defaults map[string]interface{}
 }
 
 func LoadConfig() error {
+	defaults, err := LoadDefaults()
+	if err != nil {
+		return err
+	}
+
+	Config = &config{defaults}
+
+	return nil
+}
+
+// LoadDefaults returns the config values overridden by the current context,
+// then by the project config, then by environment variables (see EnvPrefix) and finally by command flags
+func LoadDefaults() (map[string]interface{}, error) {
 	db, err, dbclose := database.Current()
 	if err != nil {
-		return fmt.Errorf("load config: %s", err)
+		return nil, fmt.Errorf("load config: %s", err)
 	}
 	defer dbclose()
 
 	defaults, err := db.GetDefaults()
 	if err != nil {
-		return fmt.Errorf("config: load defaults: %s", err)
+		return nil, fmt.Errorf("config: load defaults: %s", err)
 	}
 
-	Config = &config{defaults}
+	if ctx, ok := db.GetCurrentContext(); ok {
+		ctx.ApplyTo(defaults)
+	}
+	Project.ApplyTo(defaults)
+	for k, v := range EnvOverrides(os.Environ(), defaults) {
+		defaults[k] = v
+	}
+	for k, v := range FlagOverrides {
+		defaults[k] = v
+	}
 
-	return nil
+	return defaults, nil
 }
@@ -0,0 +1,88 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix prefixes the environment variables overriding config values, for container and CI usage.
+// The variable of a key is the key in upper case, dots and dashes replaced by underscores.
+// Ex: AWLESS_REGION, AWLESS_AWS_PROFILE, AWLESS_SYNC_AUTO, AWLESS_INSTANCE_TYPE, AWLESS_SHORTCUT_WEB
+//
+// Config values are resolved with this precedence, from the highest:
+// command flags, environment variables, project config (.awless.yml), current context, awless config
+const EnvPrefix = "AWLESS_"
+
+// ColorKey is the config key disabling colored output when false
+const ColorKey = "color"
+
+var (
+	// EnvKeys returns the config keys that environment variables can override,
+	// in addition to the keys already set. Registered by commands, which know all of them
+	EnvKeys = func() []string { return nil }
+
+	// EnvKeyPrefixes are the prefixes of families of keys (ex: shortcut.) that environment variables can override
+	EnvKeyPrefixes []string
+)
+
+// EnvName returns the environment variable overriding a config key. Ex: aws.profile -> AWLESS_AWS_PROFILE
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// EnvOverrides returns the config values set by the given environment (as from os.Environ),
+// indexed by config key. Unrelated AWLESS_ variables (ex: AWLESS_STATE_PASSPHRASE) are ignored
+func EnvOverrides(environ []string, defaults map[string]interface{}) map[string]interface{} {
+	names := make(map[string]string)
+	for _, k := range EnvKeys() {
+		names[EnvName(k)] = k
+	}
+	for k := range defaults {
+		names[EnvName(k)] = k
+	}
+
+	overrides := make(map[string]interface{})
+	for _, env := range environ {
+		splits := strings.SplitN(env, "=", 2)
+		if len(splits) != 2 || !strings.HasPrefix(splits[0], EnvPrefix) {
+			continue
+		}
+		name, value := splits[0], splits[1]
+		if key, ok := names[name]; ok {
+			overrides[key] = ParseValue(value)
+			continue
+		}
+		for _, prefix := range EnvKeyPrefixes {
+			if p := EnvName(prefix); strings.HasPrefix(name, p) && len(name) > len(p) {
+				overrides[prefix+strings.ToLower(strings.TrimPrefix(name, p))] = ParseValue(value)
+			}
+		}
+	}
+	return overrides
+}
+
+// ParseValue types a config value given as string: number, boolean or string
+func ParseValue(value string) interface{} {
+	if num, err := strconv.Atoi(value); err == nil {
+		return num
+	} else if b, err := strconv.ParseBool(value); err == nil {
+		return b
+	}
+	return value
+}
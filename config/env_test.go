@@ -0,0 +1,86 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+	tcases := map[string]string{
+		"region":           "AWLESS_REGION",
+		"aws.profile":      "AWLESS_AWS_PROFILE",
+		"sync.auto":        "AWLESS_SYNC_AUTO",
+		"shortcut.my-list": "AWLESS_SHORTCUT_MY_LIST",
+	}
+	for key, want := range tcases {
+		if got := EnvName(key); got != want {
+			t.Fatalf("%s: got %s, want %s", key, got, want)
+		}
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+	defer func(keys func() []string, prefixes []string) {
+		EnvKeys, EnvKeyPrefixes = keys, prefixes
+	}(EnvKeys, EnvKeyPrefixes)
+	EnvKeys = func() []string { return []string{"color", "instance.type"} }
+	EnvKeyPrefixes = []string{"shortcut."}
+
+	defaults := map[string]interface{}{"region": "us-east-1", "sync.auto": true}
+	environ := []string{
+		"HOME=/home/user",
+		"AWLESS_REGION=eu-west-1",
+		"AWLESS_SYNC_AUTO=false",
+		"AWLESS_COLOR=false",
+		"AWLESS_INSTANCE_TYPE=t2.nano",
+		"AWLESS_SHORTCUT_WEB=ls instances --filter name=web",
+		"AWLESS_STATE_PASSPHRASE=secret",
+		"AWLESS_INSTANCE_COUNT=2",
+	}
+
+	got := EnvOverrides(environ, defaults)
+	want := map[string]interface{}{
+		"region":        "eu-west-1",
+		"sync.auto":     false,
+		"color":         false,
+		"instance.type": "t2.nano",
+		"shortcut.web":  "ls instances --filter name=web",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tcases := []struct {
+		value string
+		want  interface{}
+	}{
+		{"3", 3},
+		{"true", true},
+		{"false", false},
+		{"eu-west-1", "eu-west-1"},
+		{"", ""},
+	}
+	for _, tcase := range tcases {
+		if got := ParseValue(tcase.value); got != tcase.want {
+			t.Fatalf("%q: got %#v, want %#v", tcase.value, got, tcase.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// GuardrailsKey is the config key of the path of the guardrails policy file enforced on templates
+const GuardrailsKey = "guardrails.policy"
+
+// Guardrails restrict the template statements that can be run. Example of policy file:
+//
+//	deny:
+//	  - delete vpc
+//	  - delete *gateway
+//	allow:
+//	  - create instance
+//	  - create tag
+//	  - check *
+//	values:
+//	  instance.type:
+//	    - t2.micro
+//	    - t2.small
+//
+// Denied statements are refused. When allow is set, only the statements matching one
+// of its rules are accepted. Values restrict the params of the statements of an entity
+type Guardrails struct {
+	Path   string
+	Deny   []string
+	Allow  []string
+	Values map[string][]string
+}
+
+// LoadGuardrails reads the policy file at the given path
+func LoadGuardrails(path string) (*Guardrails, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("guardrails policy: %s", err)
+	}
+	g, err := parseGuardrails(content)
+	if err != nil {
+		return nil, fmt.Errorf("guardrails policy %s: %s", path, err)
+	}
+	g.Path = path
+	return g, nil
+}
+
+func parseGuardrails(content []byte) (*Guardrails, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	g := &Guardrails{Values: make(map[string][]string)}
+	for key, value := range raw {
+		switch key {
+		case "deny", "allow":
+			rules, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("'%s' must be a list of statements (ex: delete vpc)", key)
+			}
+			for _, r := range rules {
+				rule := strings.Join(strings.Fields(fmt.Sprint(r)), " ")
+				if len(strings.Fields(rule)) != 2 {
+					return nil, fmt.Errorf("invalid %s rule '%s': expecting an action and an entity (ex: delete vpc, create *)", key, r)
+				}
+				if key == "deny" {
+					g.Deny = append(g.Deny, rule)
+				} else {
+					g.Allow = append(g.Allow, rule)
+				}
+			}
+		case "values":
+			values, ok := value.(map[interface{}]interface{})
+			if !ok {
+				return nil, fmt.Errorf("'values' must be a mapping of params (ex: instance.type) to lists of allowed values")
+			}
+			lists := make(map[string]interface{})
+			var params []string
+			for param, list := range values {
+				lists[fmt.Sprint(param)] = list
+				params = append(params, fmt.Sprint(param))
+			}
+			sort.Strings(params)
+			for _, param := range params {
+				if !strings.Contains(param, ".") {
+					return nil, fmt.Errorf("invalid param '%s' in values: expecting entity.param (ex: instance.type)", param)
+				}
+				list, ok := lists[param].([]interface{})
+				if !ok {
+					return nil, fmt.Errorf("values of '%s' must be a list", param)
+				}
+				for _, v := range list {
+					g.Values[param] = append(g.Values[param], fmt.Sprint(v))
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unknown key '%s'", key)
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGuardrails(t *testing.T) {
+	content := `# company policy
+deny:
+  - delete vpc
+  - delete  *gateway
+allow:
+  - create instance
+  - check *
+values:
+  instance.type:
+    - t2.micro
+    - t2.small
+`
+	g, err := parseGuardrails([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.Deny, []string{"delete vpc", "delete *gateway"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := g.Allow, []string{"create instance", "check *"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := g.Values, map[string][]string{"instance.type": {"t2.micro", "t2.small"}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	tcases := []struct {
+		content, err string
+	}{
+		{content: "deny: delete vpc", err: "'deny' must be a list"},
+		{content: "deny:\n  - delete", err: "invalid deny rule 'delete'"},
+		{content: "values:\n  type:\n    - t2.micro", err: "expecting entity.param"},
+		{content: "ban:\n  - delete vpc", err: "unknown key 'ban'"},
+	}
+	for _, tcase := range tcases {
+		if _, err := parseGuardrails([]byte(tcase.content)); err == nil || !strings.Contains(err.Error(), tcase.err) {
+			t.Fatalf("%q: got %v, want error containing %q", tcase.content, err, tcase.err)
+		}
+	}
+}
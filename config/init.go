@@ -17,6 +17,7 @@ limitations under the License.
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -33,9 +34,13 @@ var (
 	RepoDir                             = filepath.Join(AwlessHome, "aws", "rdf")
 	Dir                                 = filepath.Join(AwlessHome, "aws")
 	KeysDir                             = filepath.Join(AwlessHome, "keys")
+	InspectorsDir                       = filepath.Join(AwlessHome, "inspectors")
 	InfraFilename                       = "infra.rdf"
 	AccessFilename                      = "access.rdf"
 	AwlessFirstInstall, AwlessFirstSync bool
+
+	// NonInteractive forbids prompting the user (ex: in CI)
+	NonInteractive bool
 )
 
 func InitAwlessEnv() error {
@@ -62,6 +67,13 @@ func InitAwlessEnv() error {
 	return nil
 }
 
+// SetProviderRepoDir stores the local graphs of a cloud provider other than AWS in their own directory,
+// as providers name their services alike (ex: compute)
+func SetProviderRepoDir(provider string) error {
+	RepoDir = filepath.Join(AwlessHome, provider, "rdf")
+	return os.MkdirAll(RepoDir, 0700)
+}
+
 func resolveAndSetDefaults() (string, error) {
 	var region, ami string
 
@@ -71,6 +83,8 @@ func resolveAndSetDefaults() (string, error) {
 
 	if aws.IsValidRegion(region) {
 		fmt.Printf("Found existing AWS region '%s'. Setting it as your default region.\n", region)
+	} else if NonInteractive {
+		return region, errors.New("could not find any AWS region in your environment: export AWS_REGION")
 	} else {
 		fmt.Println("Could not find any AWS region in your environment. Please choose one region:")
 		region = askRegion()
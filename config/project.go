@@ -0,0 +1,146 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/database"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const ProjectFilename = ".awless.yaml"
+
+// Project is the project configuration found from the working directory, if any
+var Project *ProjectConfig
+
+// A ProjectConfig overrides the global config for a project. Example of .awless.yaml:
+//
+//	region: eu-west-1
+//	profile: prod
+//	defaults:
+//	  instance.type: t2.small
+//	required-tags:
+//	  - Team
+type ProjectConfig struct {
+	Path         string
+	Region       string
+	Profile      string
+	Defaults     map[string]interface{}
+	RequiredTags []string
+}
+
+// LoadProjectConfig looks for a project configuration file in the working
+// directory then in its parents and sets the current Project
+func LoadProjectConfig() error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	path, found := findProjectFile(wd)
+	if !found {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	Project, err = parseProjectConfig(content)
+	if err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+	Project.Path = path
+
+	return nil
+}
+
+// ApplyTo overrides the given defaults with the project ones
+func (p *ProjectConfig) ApplyTo(defaults map[string]interface{}) {
+	if p == nil {
+		return
+	}
+	for k, v := range p.Defaults {
+		defaults[k] = v
+	}
+	if p.Region != "" {
+		defaults[database.RegionKey] = p.Region
+	}
+	if p.Profile != "" {
+		defaults[database.ProfileKey] = p.Profile
+	}
+}
+
+func findProjectFile(dir string) (string, bool) {
+	for {
+		path := filepath.Join(dir, ProjectFilename)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+func parseProjectConfig(content []byte) (*ProjectConfig, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	conf := &ProjectConfig{Defaults: make(map[string]interface{})}
+	for key, value := range raw {
+		switch key {
+		case "region":
+			conf.Region = fmt.Sprint(value)
+			if !aws.IsValidRegion(conf.Region) {
+				return nil, fmt.Errorf("invalid region '%s'", conf.Region)
+			}
+		case "profile":
+			conf.Profile = fmt.Sprint(value)
+		case "defaults":
+			defaults, ok := value.(map[interface{}]interface{})
+			if !ok {
+				return nil, fmt.Errorf("'defaults' must be a mapping of template params")
+			}
+			for k, v := range defaults {
+				conf.Defaults[fmt.Sprint(k)] = v
+			}
+		case "required-tags":
+			tags, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("'required-tags' must be a list of tag keys")
+			}
+			for _, t := range tags {
+				conf.RequiredTags = append(conf.RequiredTags, fmt.Sprint(t))
+			}
+		default:
+			return nil, fmt.Errorf("unknown key '%s'", key)
+		}
+	}
+
+	return conf, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wallix/awless/database"
+)
+
+func TestParseProjectConfig(t *testing.T) {
+	content := `# my project
+region: eu-west-1
+profile: "prod"
+defaults:
+  instance.type: t2.small # smaller is cheaper
+  instance.count: 2
+  sync.auto: false
+required-tags:
+  - Team
+  - Env
+`
+	conf, err := parseProjectConfig([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conf.Region, "eu-west-1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := conf.Profile, "prod"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := conf.RequiredTags, []string{"Team", "Env"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	defaults := map[string]interface{}{database.RegionKey: "us-east-1", database.InstanceTypeKey: "t2.micro", database.InstanceImageKey: "ami-123"}
+	conf.ApplyTo(defaults)
+	expected := map[string]interface{}{
+		database.RegionKey:        "eu-west-1",
+		database.ProfileKey:       "prod",
+		database.InstanceTypeKey:  "t2.small",
+		database.InstanceCountKey: 2,
+		database.InstanceImageKey: "ami-123",
+		database.SyncAuto:         false,
+	}
+	if got, want := defaults, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	for _, invalid := range []string{"region: moon-1", "unknown: key", "defaults: value", "required-tags:\n  key: value", "region eu-west-1", "defaults:\n  a: 1\n    b: 2"} {
+		if _, err := parseProjectConfig([]byte(invalid)); err == nil {
+			t.Fatalf("%q: expected error got none", invalid)
+		}
+	}
+}
+
+func TestLoadProjectConfigFromParentDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sub := filepath.Join(dir, "infra", "templates")
+	os.MkdirAll(sub, 0700)
+	ioutil.WriteFile(filepath.Join(dir, ProjectFilename), []byte("region: us-west-2\n"), 0600)
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(sub)
+	defer func() { Project = nil }()
+
+	if err = LoadProjectConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if Project == nil {
+		t.Fatal("expected project config got none")
+	}
+	if got, want := Project.Region, "us-west-2"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// ReadOnlyKey forbids actions changing resources (create, delete, update, attach, start, stop, ...)
+const ReadOnlyKey = "readonly"
+
+// ReadOnly is set by the --readonly flag
+var ReadOnly bool
+
+// IsReadOnly tells whether the flag or the given config values forbid actions changing resources
+func IsReadOnly(defaults map[string]interface{}) bool {
+	if ReadOnly {
+		return true
+	}
+	switch v := defaults[ReadOnlyKey].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
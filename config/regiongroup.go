@@ -0,0 +1,58 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wallix/awless/aws"
+)
+
+// RegionGroupKeyPrefix prefixes the config keys defining region groups.
+// Ex: awless config set region-group.emea eu-west-1,eu-central-1
+const RegionGroupKeyPrefix = "region-group."
+
+// RegionGroup returns the regions of the group named in the given config values
+func RegionGroup(defaults map[string]interface{}, name string) ([]string, error) {
+	value, ok := defaults[RegionGroupKeyPrefix+name]
+	if !ok {
+		return nil, fmt.Errorf("unknown region group '%s'. Define it with `awless config set %s%[1]s region1,region2`", name, RegionGroupKeyPrefix)
+	}
+	return ParseRegionGroup(fmt.Sprint(value))
+}
+
+// ParseRegionGroup parses a comma separated list of regions, ignoring duplicates
+func ParseRegionGroup(value string) ([]string, error) {
+	var regions []string
+	seen := make(map[string]bool)
+	for _, r := range strings.Split(value, ",") {
+		r = strings.TrimSpace(r)
+		if r == "" || seen[r] {
+			continue
+		}
+		if !aws.IsValidRegion(r) {
+			return nil, fmt.Errorf("invalid region '%s' in region group", r)
+		}
+		seen[r] = true
+		regions = append(regions, r)
+	}
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("empty region group")
+	}
+	return regions, nil
+}
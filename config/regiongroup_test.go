@@ -0,0 +1,44 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegionGroup(t *testing.T) {
+	defaults := map[string]interface{}{
+		"region-group.emea":    "eu-west-1, eu-central-1,eu-west-1",
+		"region-group.invalid": "eu-west-1,mars-north-1",
+		"region-group.empty":   " , ",
+	}
+
+	regions, err := RegionGroup(defaults, "emea")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := regions, []string{"eu-west-1", "eu-central-1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	for _, name := range []string{"invalid", "empty", "unknown"} {
+		if _, err := RegionGroup(defaults, name); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
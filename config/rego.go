@@ -0,0 +1,32 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// RegoPolicyKey is the config key of the path of the Rego policy file (or directory of files)
+// evaluated with OPA against compiled templates. Runs are blocked when the 'data.awless.deny'
+// set is not empty, ex:
+//
+//	package awless
+//
+//	deny[msg] {
+//	  st := input.statements[_]
+//	  st.action == "create"
+//	  st.entity == "instance"
+//	  not startswith(st.params.type, "t2.")
+//	  msg := sprintf("instance type %s forbidden", [st.params.type])
+//	}
+const RegoPolicyKey = "policy.rego"
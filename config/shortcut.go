@@ -0,0 +1,52 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ShortcutKeyPrefix prefixes the config keys defining command shortcuts.
+// Ex: awless config set shortcut.lsr "list instances --filter state=running --sort launched"
+const ShortcutKeyPrefix = "shortcut."
+
+// ExpandShortcut replaces the first of the given args with the command line of the shortcut
+// it names in the config values. Shortcuts are not expanded recursively.
+func ExpandShortcut(defaults map[string]interface{}, args []string) ([]string, bool) {
+	if len(args) == 0 {
+		return args, false
+	}
+	value, ok := defaults[ShortcutKeyPrefix+args[0]]
+	if !ok {
+		return args, false
+	}
+	expanded, err := ParseShortcut(fmt.Sprint(value))
+	if err != nil {
+		return args, false
+	}
+	return append(expanded, args[1:]...), true
+}
+
+// ParseShortcut splits the command line of a shortcut into args
+func ParseShortcut(value string) ([]string, error) {
+	args := strings.Fields(value)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty shortcut")
+	}
+	return args, nil
+}
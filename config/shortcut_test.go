@@ -0,0 +1,49 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandShortcut(t *testing.T) {
+	defaults := map[string]interface{}{
+		"shortcut.lsr":   "list instances --filter state=running  --sort launched",
+		"shortcut.empty": " ",
+	}
+	tcases := []struct {
+		args     []string
+		expected []string
+		expanded bool
+	}{
+		{args: []string{"lsr"}, expected: []string{"list", "instances", "--filter", "state=running", "--sort", "launched"}, expanded: true},
+		{args: []string{"lsr", "--local", "--ids"}, expected: []string{"list", "instances", "--filter", "state=running", "--sort", "launched", "--local", "--ids"}, expanded: true},
+		{args: []string{"list", "instances"}, expected: []string{"list", "instances"}},
+		{args: []string{"empty"}, expected: []string{"empty"}},
+		{args: nil, expected: nil},
+	}
+	for i, tcase := range tcases {
+		got, expanded := ExpandShortcut(defaults, tcase.args)
+		if want := tcase.expected; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %q, want %q", i+1, got, want)
+		}
+		if got, want := expanded, tcase.expanded; got != want {
+			t.Fatalf("%d: got %t, want %t", i+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TableMaxWidthKeyPrefix prefixes the config keys limiting the width of a table column.
+// Ex: awless config set table.maxwidth.arn 60
+const TableMaxWidthKeyPrefix = "table.maxwidth."
+
+// TableColumnsMaxWidth returns the column max widths defined in the given config values,
+// indexed by lowercased column name
+func TableColumnsMaxWidth(defaults map[string]interface{}) map[string]int {
+	widths := make(map[string]int)
+	for key, value := range defaults {
+		if !strings.HasPrefix(key, TableMaxWidthKeyPrefix) {
+			continue
+		}
+		width, err := ParseColumnMaxWidth(fmt.Sprint(value))
+		if err != nil {
+			continue
+		}
+		widths[strings.ToLower(strings.TrimPrefix(key, TableMaxWidthKeyPrefix))] = width
+	}
+	return widths
+}
+
+// ParseColumnMaxWidth parses a strictly positive column width
+func ParseColumnMaxWidth(value string) (int, error) {
+	width, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || width <= 0 {
+		return 0, fmt.Errorf("invalid column max width '%s': expecting a positive integer", value)
+	}
+	return width, nil
+}
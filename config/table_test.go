@@ -0,0 +1,34 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTableColumnsMaxWidth(t *testing.T) {
+	defaults := map[string]interface{}{
+		"table.maxwidth.Arn":  60,
+		"table.maxwidth.name": "25",
+		"table.maxwidth.id":   "-3",
+		"instance.type":       "t2.micro",
+	}
+	if got, want := TableColumnsMaxWidth(defaults), map[string]int{"arn": 60, "name": 25}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/wallix/awless/graph"
+)
+
+var (
+	addedFn    = color.New(color.FgGreen).SprintFunc()
+	removedFn  = color.New(color.FgRed).SprintFunc()
+	modifiedFn = color.New(color.FgYellow).SprintFunc()
+)
+
+// WriteResourceChanges writes a unified diff of a resource: a header line with its marker
+// ('+' created, '-' deleted, '~' modified) then a line per changed property. Without colors
+// (ex: --no-color), the markers keep the output readable in CI logs
+func WriteResourceChanges(w io.Writer, marker, label string, changes []graph.PropertyChange) {
+	switch marker {
+	case "+":
+		fmt.Fprintln(w, addedFn("+ "+label))
+	case "-":
+		fmt.Fprintln(w, removedFn("- "+label))
+	default:
+		fmt.Fprintln(w, modifiedFn(marker+" "+label))
+	}
+	for _, c := range changes {
+		switch {
+		case c.IsAdded():
+			fmt.Fprintf(w, "    %s\n", addedFn(fmt.Sprintf("+ %s: %s", c.Name, changeValue(c.New))))
+		case c.IsRemoved():
+			fmt.Fprintf(w, "    %s\n", removedFn(fmt.Sprintf("- %s: %s", c.Name, changeValue(c.Old))))
+		default:
+			fmt.Fprintf(w, "    ~ %s: %s → %s\n", c.Name, removedFn(changeValue(c.Old)), addedFn(changeValue(c.New)))
+		}
+	}
+}
+
+func changeValue(v interface{}) string {
+	switch vv := v.(type) {
+	case []string:
+		return "[" + strings.Join(vv, ", ") + "]"
+	case []interface{}:
+		var values []string
+		for _, e := range vv {
+			values = append(values, fmt.Sprint(e))
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/wallix/awless/graph"
+)
+
+func TestWriteResourceChanges(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	var buff bytes.Buffer
+	WriteResourceChanges(&buff, "~", "instance inst_1", []graph.PropertyChange{
+		{Name: "SecurityGroups", Old: []interface{}{"sg_1"}, New: []interface{}{"sg_1", "sg_2"}},
+		{Name: "Tags.Env", New: "prod"},
+		{Name: "Tags.Team", Old: "ops"},
+	})
+	WriteResourceChanges(&buff, "-", "volume vol_1", nil)
+
+	expected := `~ instance inst_1
+    ~ SecurityGroups: [sg_1] → [sg_1, sg_2]
+    + Tags.Env: prod
+    - Tags.Team: ops
+- volume vol_1
+`
+	if got, want := buff.String(), expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
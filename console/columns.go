@@ -0,0 +1,151 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const minAutoColumnWidth = 10
+
+// sizeColumns returns the headers resized to display the values: configured max widths first,
+// then columns with default truncation take their full width, shrunk (widest first) to fit the terminal.
+// Columns are only auto sized when they fit the terminal with at least minAutoColumnWidth, narrower
+// terminals keeping the default truncation. autoSized reports whether lines are displayed unwrapped
+func (d *fromGraphDisplayer) sizeColumns(values table) (headers []ColumnDefinition, autoSized bool) {
+	headers = make([]ColumnDefinition, len(d.headers))
+	copy(headers, d.headers)
+
+	if d.noTruncate {
+		for j, h := range headers {
+			headers[j] = resizeColumn(h, 0)
+		}
+		return headers, true
+	}
+
+	auto := make(map[int]bool)
+	for j, h := range headers {
+		if width, ok := d.configuredWidth(h); ok {
+			headers[j] = resizeColumn(h, width)
+		} else if d.maxwidth != 0 && isAutoSizable(h) {
+			auto[j] = true
+			headers[j] = resizeColumn(h, 0)
+		}
+	}
+	if len(auto) == 0 {
+		return headers, false
+	}
+
+	widths := make([]int, len(headers))
+	total := 1 // table left border
+	for j, h := range headers {
+		widths[j] = linesWidth(j, values, h)
+		if titleW := tablewriter.DisplayWidth(h.title(false)); titleW > widths[j] {
+			widths[j] = titleW
+		}
+		total += widths[j] + 3 // +3 (tables margin)
+	}
+
+	natural := make([]int, len(widths))
+	copy(natural, widths)
+	for total > d.maxwidth {
+		widest := -1
+		for j := range auto {
+			if widths[j] > minAutoColumnWidth && (widest < 0 || widths[j] > widths[widest] || (widths[j] == widths[widest] && j < widest)) {
+				widest = j
+			}
+		}
+		if widest < 0 {
+			for j := range auto {
+				headers[j] = d.headers[j]
+			}
+			return headers, false
+		}
+		widths[widest]--
+		total--
+	}
+
+	for j := range auto {
+		if widths[j] < natural[j] {
+			headers[j] = resizeColumn(headers[j], widths[j])
+		}
+	}
+	return headers, true
+}
+
+func (d *fromGraphDisplayer) configuredWidth(h ColumnDefinition) (int, bool) {
+	if len(d.colWidths) == 0 {
+		return 0, false
+	}
+	if w, ok := d.colWidths[strings.ToLower(h.propKey())]; ok && w > 0 {
+		return w, true
+	}
+	if w, ok := d.colWidths[strings.ToLower(h.title(false))]; ok && w > 0 {
+		return w, true
+	}
+	return 0, false
+}
+
+func isAutoSizable(h ColumnDefinition) bool {
+	switch c := h.(type) {
+	case StringColumnDefinition:
+		return !c.DisableTruncate && c.TruncateSize == 0
+	case *StringColumnDefinition:
+		return !c.DisableTruncate && c.TruncateSize == 0
+	case ColoredValueColumnDefinition:
+		return !c.DisableTruncate && c.TruncateSize == 0
+	case *ColoredValueColumnDefinition:
+		return !c.DisableTruncate && c.TruncateSize == 0
+	}
+	return false
+}
+
+// resizeColumn returns a copy of the column truncating its values at the given size.
+// A size of 0 disables truncation. Columns that never truncate are returned as is
+func resizeColumn(h ColumnDefinition, size int) ColumnDefinition {
+	resize := func(c StringColumnDefinition) StringColumnDefinition {
+		c.DisableTruncate = size == 0
+		c.TruncateSize = size
+		return c
+	}
+	switch c := h.(type) {
+	case StringColumnDefinition:
+		return resize(c)
+	case *StringColumnDefinition:
+		return resize(*c)
+	case ColoredValueColumnDefinition:
+		c.StringColumnDefinition = resize(c.StringColumnDefinition)
+		return c
+	case *ColoredValueColumnDefinition:
+		return ColoredValueColumnDefinition{StringColumnDefinition: resize(c.StringColumnDefinition), ColoredValues: c.ColoredValues}
+	}
+	return h
+}
+
+func linesWidth(j int, t table, h ColumnDefinition) int {
+	w := 0
+	for i := range t {
+		for _, line := range strings.Split(h.format(t[i][j]), "\n") {
+			if c := tablewriter.DisplayWidth(line); c > w {
+				w = c
+			}
+		}
+	}
+	return w
+}
@@ -179,4 +179,39 @@ var DefaultsColumnDefinitions = map[graph.ResourceType][]ColumnDefinition{
 		TimeColumnDefinition{StringColumnDefinition: StringColumnDefinition{Prop: "LastModifiedTimestamp", Friendly: "LastModif"}},
 		StringColumnDefinition{Prop: "DelaySeconds", Friendly: "Delay(s)"},
 	},
+	//CloudFormation
+	graph.Stack: {
+		StringColumnDefinition{Prop: "Name", DisableTruncate: true},
+		StringColumnDefinition{Prop: "State"},
+		StringColumnDefinition{Prop: "Description"},
+		TimeColumnDefinition{StringColumnDefinition: StringColumnDefinition{Prop: "CreationTime", Friendly: "Created"}},
+		TimeColumnDefinition{StringColumnDefinition: StringColumnDefinition{Prop: "LastUpdatedTime", Friendly: "Updated"}},
+	},
+	//Kubernetes
+	graph.KubeCluster: {
+		StringColumnDefinition{Prop: "Name", DisableTruncate: true},
+		StringColumnDefinition{Prop: "Server"},
+		StringColumnDefinition{Prop: "Version"},
+	},
+	graph.KubeNode: {
+		StringColumnDefinition{Prop: "Name"},
+		StringColumnDefinition{Prop: "Cluster"},
+		ColoredValueColumnDefinition{
+			StringColumnDefinition: StringColumnDefinition{Prop: "State"},
+			ColoredValues:          map[string]color.Attribute{"ready": color.FgGreen, "notready": color.FgRed},
+		},
+		StringColumnDefinition{Prop: "InstanceId", Friendly: "Instance"},
+		StringColumnDefinition{Prop: "Type"},
+		StringColumnDefinition{Prop: "PrivateIp", Friendly: "Private IP"},
+		StringColumnDefinition{Prop: "KubeletVersion", Friendly: "Version"},
+	},
+	graph.KubeService: {
+		StringColumnDefinition{Prop: "Name"},
+		StringColumnDefinition{Prop: "Namespace"},
+		StringColumnDefinition{Prop: "Cluster"},
+		StringColumnDefinition{Prop: "Type"},
+		StringColumnDefinition{Prop: "ClusterIp", Friendly: "Cluster IP"},
+		StringColumnDefinition{Prop: "LoadBalancerDNS", Friendly: "External"},
+		StringColumnDefinition{Prop: "Ports"},
+	},
 }
@@ -46,11 +46,14 @@ type sorter interface {
 
 type Builder struct {
 	filters    []string
+	tagFilters []string
 	headers    []ColumnDefinition
 	format     string
 	rdfType    graph.ResourceType
 	sort       []int
 	maxwidth   int
+	noTruncate bool
+	colWidths  map[string]int
 	dataSource interface{}
 	root       *graph.Resource
 }
@@ -77,12 +80,12 @@ func (b *Builder) buildGraphFilters() (funcs []graph.FilterFn) {
 }
 
 func (b *Builder) Build() Displayer {
-	base := fromGraphDisplayer{sorter: &defaultSorter{sortBy: b.sort}, rdfType: b.rdfType, headers: b.headers, maxwidth: b.maxwidth}
+	base := fromGraphDisplayer{sorter: &defaultSorter{sortBy: b.sort}, rdfType: b.rdfType, headers: b.headers, maxwidth: b.maxwidth, noTruncate: b.noTruncate, colWidths: b.colWidths}
 
 	switch b.dataSource.(type) {
 	case *graph.Graph:
 		gph := b.dataSource.(*graph.Graph)
-		filteredGraph, _ := gph.Filter(b.rdfType, b.buildGraphFilters()...)
+		filteredGraph, _ := gph.FilterByTags(b.rdfType, b.tagFilters, b.buildGraphFilters()...)
 
 		if b.rdfType == "" {
 			switch b.format {
@@ -194,6 +197,14 @@ func WithFilters(fs []string) optsFn {
 	}
 }
 
+// WithTagFilters keeps resources having all the given 'key=value' (or 'key') tags
+func WithTagFilters(tags []string) optsFn {
+	return func(b *Builder) *Builder {
+		b.tagFilters = tags
+		return b
+	}
+}
+
 func WithIDsOnly(only bool) optsFn {
 	return func(b *Builder) *Builder {
 		if only {
@@ -228,6 +239,22 @@ func WithMaxWidth(maxwidth int) optsFn {
 	}
 }
 
+// WithNoTruncate displays full values in tables, whatever the terminal width
+func WithNoTruncate(noTruncate bool) optsFn {
+	return func(b *Builder) *Builder {
+		b.noTruncate = noTruncate
+		return b
+	}
+}
+
+// WithColumnsMaxWidth sets the max width of table columns given their lowercased name
+func WithColumnsMaxWidth(widths map[string]int) optsFn {
+	return func(b *Builder) *Builder {
+		b.colWidths = widths
+		return b
+	}
+}
+
 func WithRdfType(rdfType graph.ResourceType) optsFn {
 	return func(b *Builder) *Builder {
 		b.rdfType = rdfType
@@ -246,10 +273,12 @@ type table [][]interface{}
 
 type fromGraphDisplayer struct {
 	sorter
-	g        *graph.Graph
-	rdfType  graph.ResourceType
-	headers  []ColumnDefinition
-	maxwidth int
+	g          *graph.Graph
+	rdfType    graph.ResourceType
+	headers    []ColumnDefinition
+	maxwidth   int
+	noTruncate bool
+	colWidths  map[string]int
 }
 
 func (d *fromGraphDisplayer) setGraph(g *graph.Graph) {
@@ -355,12 +384,17 @@ func (d *tableDisplayer) Print(w io.Writer) error {
 	}
 
 	d.sorter.sort(values)
-	columnsToDisplay := d.headers
-	if d.maxwidth != 0 {
+	headers, autoSized := d.sizeColumns(values)
+	columnsToDisplay := headers
+	if d.maxwidth != 0 && !d.noTruncate {
 		columnsToDisplay = []ColumnDefinition{}
 		currentWidth := 0
-		for j, h := range d.headers {
-			colW := t(j, values, h) + 3 // +3 (tables margin)
+		width := t
+		if autoSized {
+			width = linesWidth
+		}
+		for j, h := range headers {
+			colW := width(j, values, h) + 3 // +3 (tables margin)
 			if currentWidth+colW > d.maxwidth {
 				break
 			}
@@ -375,6 +409,7 @@ func (d *tableDisplayer) Print(w io.Writer) error {
 	}
 
 	table := tablewriter.NewWriter(w)
+	table.SetAutoWrapText(!autoSized)
 	var displayHeaders []string
 	for i, h := range columnsToDisplay {
 		displayHeaders = append(displayHeaders, h.title(i == markColumnAsc))
@@ -390,10 +425,10 @@ func (d *tableDisplayer) Print(w io.Writer) error {
 	}
 
 	table.Render()
-	if len(columnsToDisplay) < len(d.headers) {
+	if len(columnsToDisplay) < len(headers) {
 		var hiddenColumns []string
-		for i := len(columnsToDisplay); i < len(d.headers); i++ {
-			hiddenColumns = append(hiddenColumns, "'"+d.headers[i].title(false)+"'")
+		for i := len(columnsToDisplay); i < len(headers); i++ {
+			hiddenColumns = append(hiddenColumns, "'"+headers[i].title(false)+"'")
 		}
 		if len(hiddenColumns) == 1 {
 			fmt.Fprint(w, color.New(color.FgRed).SprintfFunc()("Column truncated to fit terminal: %s\n", hiddenColumns[0]))
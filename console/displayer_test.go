@@ -678,3 +678,89 @@ func TestEmotyDisplays(t *testing.T) {
 		t.Fatalf("got \n[%q]\n\nwant\n\n[%q]\n", got, want)
 	}
 }
+
+func TestColumnsAutoWidth(t *testing.T) {
+	g := graph.NewGraph()
+	role := graph.InitResource("role_1", graph.Role)
+	role.Properties["Id"] = "role_1"
+	role.Properties["Arn"] = "arn:aws:iam::123456789012:role/my-long-role-name"
+	g.AddResource(role)
+
+	headers := []ColumnDefinition{
+		StringColumnDefinition{Prop: "Id"},
+		StringColumnDefinition{Prop: "Arn"},
+	}
+
+	tcases := []struct {
+		opts     []optsFn
+		expected string
+	}{
+		{
+			opts: []optsFn{},
+			expected: `+--------+---------------------------+
+|  ID ▲  |            ARN            |
++--------+---------------------------+
+| role_1 | ...role/my-long-role-name |
++--------+---------------------------+
+`,
+		},
+		{
+			opts: []optsFn{WithMaxWidth(80)},
+			expected: `+--------+--------------------------------------------------+
+|  ID ▲  |                       ARN                        |
++--------+--------------------------------------------------+
+| role_1 | arn:aws:iam::123456789012:role/my-long-role-name |
++--------+--------------------------------------------------+
+`,
+		},
+		{
+			opts: []optsFn{WithMaxWidth(30)},
+			expected: `+--------+-------------------+
+|  ID ▲  |        ARN        |
++--------+-------------------+
+| role_1 | ...long-role-name |
++--------+-------------------+
+`,
+		},
+		{
+			opts: []optsFn{WithMaxWidth(20)},
+			expected: `+--------+
+|  ID ▲  |
++--------+
+| role_1 |
++--------+
+Column truncated to fit terminal: 'Arn'
+`,
+		},
+		{
+			opts: []optsFn{WithMaxWidth(30), WithNoTruncate(true)},
+			expected: `+--------+--------------------------------------------------+
+|  ID ▲  |                       ARN                        |
++--------+--------------------------------------------------+
+| role_1 | arn:aws:iam::123456789012:role/my-long-role-name |
++--------+--------------------------------------------------+
+`,
+		},
+		{
+			opts: []optsFn{WithMaxWidth(80), WithColumnsMaxWidth(map[string]int{"arn": 12})},
+			expected: `+--------+--------------+
+|  ID ▲  |     ARN      |
++--------+--------------+
+| role_1 | ...role-name |
++--------+--------------+
+`,
+		},
+	}
+
+	for i, tcase := range tcases {
+		opts := append([]optsFn{WithHeaders(headers), WithRdfType(graph.Role)}, tcase.opts...)
+		displayer := BuildOptions(opts...).SetSource(g).Build()
+		var w bytes.Buffer
+		if err := displayer.Print(&w); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := w.String(), tcase.expected; got != want {
+			t.Fatalf("%d: got \n%s\n\nwant\n\n%s\n", i+1, got, want)
+		}
+	}
+}
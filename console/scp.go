@@ -0,0 +1,268 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// CopyToRemote copies a local file (or directory when recursive) onto the remote host
+// using the SCP protocol over an established SSH client
+func CopyToRemote(client *ssh.Client, local, remote string, recursive bool) error {
+	info, err := os.Stat(local)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() && !recursive {
+		return fmt.Errorf("%s is a directory (use recursive copy)", local)
+	}
+
+	return runSCP(client, scpCommand("-t", remote, recursive), func(w io.Writer, r *bufio.Reader) error {
+		if err := readSCPAck(r); err != nil {
+			return err
+		}
+		return sendSCP(w, r, local)
+	})
+}
+
+// CopyFromRemote copies a remote file (or directory when recursive) to the local filesystem
+// using the SCP protocol over an established SSH client
+func CopyFromRemote(client *ssh.Client, remote, local string, recursive bool) error {
+	return runSCP(client, scpCommand("-f", remote, recursive), func(w io.Writer, r *bufio.Reader) error {
+		return receiveSCP(w, r, local)
+	})
+}
+
+func scpCommand(mode, path string, recursive bool) string {
+	cmd := []string{"scp", "-q"}
+	if recursive {
+		cmd = append(cmd, "-r")
+	}
+	cmd = append(cmd, mode, quoteShellArg(path))
+	return strings.Join(cmd, " ")
+}
+
+func runSCP(client *ssh.Client, cmd string, transfer func(io.Writer, *bufio.Reader) error) error {
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err = session.Start(cmd); err != nil {
+		return err
+	}
+
+	if err = transfer(stdin, bufio.NewReader(stdout)); err != nil {
+		stdin.Close()
+		return err
+	}
+	stdin.Close()
+
+	return session.Wait()
+}
+
+func sendSCP(w io.Writer, r *bufio.Reader, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return sendSCPFile(w, r, info, f)
+	}
+
+	if _, err = fmt.Fprintf(w, "D%04o 0 %s\n", info.Mode().Perm(), info.Name()); err != nil {
+		return err
+	}
+	if err = readSCPAck(r); err != nil {
+		return err
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err = sendSCP(w, r, filepath.Join(path, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	if _, err = fmt.Fprint(w, "E\n"); err != nil {
+		return err
+	}
+	return readSCPAck(r)
+}
+
+func sendSCPFile(w io.Writer, r *bufio.Reader, info os.FileInfo, content io.Reader) error {
+	if _, err := fmt.Fprintf(w, "C%04o %d %s\n", info.Mode().Perm(), info.Size(), info.Name()); err != nil {
+		return err
+	}
+	if err := readSCPAck(r); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(w, content, info.Size()); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{0}); err != nil {
+		return err
+	}
+	return readSCPAck(r)
+}
+
+func receiveSCP(w io.Writer, r *bufio.Reader, local string) error {
+	dirs := []string{}
+	current := func() string {
+		if len(dirs) == 0 {
+			return local
+		}
+		return dirs[len(dirs)-1]
+	}
+	destination := func(name string) string {
+		dest := current()
+		if info, err := os.Stat(dest); err == nil && info.IsDir() {
+			return filepath.Join(dest, name)
+		}
+		return dest
+	}
+
+	if _, err := w.Write([]byte{0}); err != nil {
+		return err
+	}
+
+	for {
+		line, err := r.ReadString('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch line[0] {
+		case 1, 2:
+			return fmt.Errorf("scp: %s", strings.TrimSpace(line[1:]))
+		case 'C':
+			mode, size, name, err := parseSCPHeader(line)
+			if err != nil {
+				return err
+			}
+			f, err := os.OpenFile(destination(name), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+			if err != nil {
+				return err
+			}
+			if _, err = w.Write([]byte{0}); err != nil {
+				f.Close()
+				return err
+			}
+			_, err = io.CopyN(f, r, size)
+			f.Close()
+			if err != nil {
+				return err
+			}
+			if err = readSCPAck(r); err != nil {
+				return err
+			}
+		case 'D':
+			mode, _, name, err := parseSCPHeader(line)
+			if err != nil {
+				return err
+			}
+			dir := destination(name)
+			if err = os.MkdirAll(dir, mode); err != nil {
+				return err
+			}
+			dirs = append(dirs, dir)
+		case 'E':
+			if len(dirs) == 0 {
+				return errors.New("scp: unexpected end of directory")
+			}
+			dirs = dirs[:len(dirs)-1]
+		case 'T':
+		default:
+			return fmt.Errorf("scp: unexpected protocol message %q", line)
+		}
+
+		if _, err = w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+}
+
+func parseSCPHeader(line string) (os.FileMode, int64, string, error) {
+	parts := strings.SplitN(strings.TrimSpace(line[1:]), " ", 3)
+	if len(parts) != 3 {
+		return 0, 0, "", fmt.Errorf("scp: invalid protocol header %q", line)
+	}
+	mode, err := strconv.ParseUint(parts[0], 8, 32)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("scp: invalid mode in %q", line)
+	}
+	size, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("scp: invalid size in %q", line)
+	}
+	if parts[2] == "" || strings.ContainsAny(parts[2], "/\\") || parts[2] == ".." {
+		return 0, 0, "", fmt.Errorf("scp: invalid name in %q", line)
+	}
+	return os.FileMode(mode), size, parts[2], nil
+}
+
+func readSCPAck(r *bufio.Reader) error {
+	code, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 0:
+		return nil
+	case 1, 2:
+		msg, _ := r.ReadString('\n')
+		return fmt.Errorf("scp: %s", strings.TrimSpace(msg))
+	default:
+		return fmt.Errorf("scp: unexpected response code %d", code)
+	}
+}
+
+func quoteShellArg(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
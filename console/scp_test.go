@@ -0,0 +1,101 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSendSCP(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-scp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tree := filepath.Join(dir, "tree")
+	os.MkdirAll(filepath.Join(tree, "sub"), 0755)
+	ioutil.WriteFile(filepath.Join(tree, "a.txt"), []byte("hello"), 0644)
+	ioutil.WriteFile(filepath.Join(tree, "sub", "b.txt"), []byte("world!"), 0600)
+
+	acks := bufio.NewReader(bytes.NewReader(make([]byte, 20)))
+	var out bytes.Buffer
+	if err = sendSCP(&out, acks, tree); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "D0755 0 tree\nC0644 5 a.txt\nhello\x00D0755 0 sub\nC0600 6 b.txt\nworld!\x00E\nE\n"
+	if got, want := out.String(), expected; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	errAck := bufio.NewReader(strings.NewReader("\x01scp: /tmp/nope: Permission denied\n"))
+	err = sendSCP(&out, errAck, filepath.Join(tree, "a.txt"))
+	if err == nil {
+		t.Fatal("expected error got none")
+	}
+	if got, want := err.Error(), "scp: scp: /tmp/nope: Permission denied"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestReceiveSCP(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-scp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	remote := "D0755 0 logs\nC0644 5 a.txt\nhello\x00D0700 0 sub\nC0600 6 b.txt\nworld!\x00E\nE\n"
+	var out bytes.Buffer
+	if err = receiveSCP(&out, bufio.NewReader(strings.NewReader(remote)), dir); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "logs", "a.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(content), "hello"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	content, err = ioutil.ReadFile(filepath.Join(dir, "logs", "sub", "b.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(content), "world!"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	single := filepath.Join(dir, "renamed.txt")
+	if err = receiveSCP(&out, bufio.NewReader(strings.NewReader("C0644 2 c.txt\nok\x00")), single); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ = ioutil.ReadFile(single); string(content) != "ok" {
+		t.Fatalf("got %s, want ok", content)
+	}
+
+	if err = receiveSCP(&out, bufio.NewReader(strings.NewReader("C0644 2 ../evil\nok\x00")), dir); err == nil {
+		t.Fatal("expected error got none")
+	}
+}
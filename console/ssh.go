@@ -17,58 +17,119 @@ limitations under the License.
 package console
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"time"
 
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
 )
 
 type Credentials struct {
 	IP      string
 	User    string
 	KeyName string
+	KeyPath string // explicit private key file, takes precedence over KeyName
 }
 
+// HasSSHAgent returns true if a ssh-agent is reachable through SSH_AUTH_SOCK
+func HasSSHAgent() bool {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return false
+	}
+	_, err := os.Stat(sock)
+	return err == nil
+}
+
+// NewSSHClient connects to the host using the private key given explicitly in credentials,
+// or else the key named after the keypair in the key directory. Keys loaded in ssh-agent are tried too.
 func NewSSHClient(keyDirectory string, cred *Credentials) (*ssh.Client, error) {
-	keyPath := filepath.Join(keyDirectory, cred.KeyName)
-	privateKey, err := ioutil.ReadFile(keyPath)
-	if os.IsNotExist(err) {
-		privateKey, err = ioutil.ReadFile(keyPath + ".pem")
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("You don't have the '%s' key in your awless key folder '%s'.", cred.KeyName, keyDirectory)
-		}
+	var auths []ssh.AuthMethod
+
+	signer, keyErr := loadPrivateKey(keyDirectory, cred)
+	switch {
+	case keyErr == nil:
+		auths = append(auths, ssh.PublicKeys(signer))
+	case cred.KeyPath != "":
+		return nil, keyErr
 	}
-	if err != nil {
-		return nil, err
+
+	if HasSSHAgent() {
+		conn, err := net.Dial("unix", os.Getenv("SSH_AUTH_SOCK"))
+		if err != nil {
+			return nil, fmt.Errorf("cannot connect to ssh-agent: %s", err)
+		}
+		defer conn.Close()
+		auths = append(auths, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
 	}
 
-	signer, err := ssh.ParsePrivateKey(privateKey)
-	if err != nil {
-		return nil, err
+	if len(auths) == 0 {
+		return nil, keyErr
 	}
 
 	config := &ssh.ClientConfig{
-		User: cred.User,
-		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(signer),
-		},
+		User:    cred.User,
+		Auth:    auths,
 		Timeout: 2 * time.Second,
 	}
 
 	return ssh.Dial("tcp", cred.IP+":22", config)
 }
 
-func InteractiveTerminal(client *ssh.Client) error {
+func loadPrivateKey(keyDirectory string, cred *Credentials) (ssh.Signer, error) {
+	var privateKey []byte
+	var err error
+	switch {
+	case cred.KeyPath != "":
+		if privateKey, err = ioutil.ReadFile(cred.KeyPath); err != nil {
+			return nil, err
+		}
+	case cred.KeyName != "":
+		keyPath := filepath.Join(keyDirectory, cred.KeyName)
+		privateKey, err = ioutil.ReadFile(keyPath)
+		if os.IsNotExist(err) {
+			privateKey, err = ioutil.ReadFile(keyPath + ".pem")
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("You don't have the '%s' key in your awless key folder '%s'.", cred.KeyName, keyDirectory)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("no private key given and no ssh-agent available")
+	}
+
+	return ssh.ParsePrivateKey(privateKey)
+}
+
+// InteractiveTerminal starts a remote shell attached to the current terminal.
+// With forwardAgent, the local ssh-agent is made available on the remote host.
+func InteractiveTerminal(client *ssh.Client, forwardAgent bool) error {
 	session, err := client.NewSession()
 	if err != nil {
 		return err
 	}
 	defer session.Close()
 
+	if forwardAgent {
+		if !HasSSHAgent() {
+			return errors.New("cannot forward agent: no ssh-agent available (SSH_AUTH_SOCK)")
+		}
+		if err = agent.ForwardToRemote(client, os.Getenv("SSH_AUTH_SOCK")); err != nil {
+			return err
+		}
+		if err = agent.RequestAgentForwarding(session); err != nil {
+			return err
+		}
+	}
+
 	stdin, err := session.StdinPipe()
 	if err != nil {
 		return err
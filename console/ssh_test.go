@@ -0,0 +1,55 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPrivateKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, private, err := GenerateSSHKeyPair(1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(dir, "mykeypair.pem"), private, 0600)
+	ioutil.WriteFile(filepath.Join(dir, "id_rsa"), private, 0600)
+
+	if _, err = loadPrivateKey(dir, &Credentials{KeyName: "mykeypair"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = loadPrivateKey("/nowhere", &Credentials{KeyName: "mykeypair", KeyPath: filepath.Join(dir, "id_rsa")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = loadPrivateKey(dir, &Credentials{KeyName: "unknown"}); err == nil {
+		t.Fatal("expected error got none")
+	}
+	if _, err = loadPrivateKey(dir, &Credentials{KeyName: "mykeypair", KeyPath: filepath.Join(dir, "missing")}); err == nil {
+		t.Fatal("expected error got none")
+	}
+	if _, err = loadPrivateKey(dir, &Credentials{}); err == nil {
+		t.Fatal("expected error got none")
+	}
+}
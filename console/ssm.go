@@ -0,0 +1,54 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// SSMSessionArgs returns the AWS CLI arguments starting a SSM Session Manager session on an instance
+func SSMSessionArgs(instanceID, region, profile string) []string {
+	args := []string{"ssm", "start-session", "--target", instanceID}
+	if region != "" {
+		args = append(args, "--region", region)
+	}
+	if profile != "" && profile != "default" {
+		args = append(args, "--profile", profile)
+	}
+	return args
+}
+
+// InteractiveSSMSession opens a SSM Session Manager session on an instance, attached to
+// the current terminal. It delegates to the AWS CLI and its session manager plugin
+func InteractiveSSMSession(instanceID, region, profile string) error {
+	awsCLI, err := exec.LookPath("aws")
+	if err != nil {
+		return errors.New("SSM sessions need the AWS CLI ('aws') in your PATH")
+	}
+	if _, err = exec.LookPath("session-manager-plugin"); err != nil {
+		return errors.New("SSM sessions need the AWS 'session-manager-plugin' in your PATH")
+	}
+
+	cmd := exec.Command(awsCLI, SSMSessionArgs(instanceID, region, profile)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
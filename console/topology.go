@@ -0,0 +1,231 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/wallix/awless/graph"
+)
+
+// PrintVpcTopology renders in the terminal the tree of a VPC with its internet gateways,
+// route tables, security groups, subnets and their instances
+func PrintVpcTopology(w io.Writer, g *graph.Graph, vpc *graph.Resource) error {
+	vpcId := vpc.Id()
+	root := &treeNode{label: topologyLabel(vpc, fmt.Sprint(vpc.Properties["CidrBlock"]))}
+
+	igws, err := resourcesWhere(g, graph.InternetGateway, func(r *graph.Resource) bool {
+		for _, id := range stringList(r.Properties["Vpcs"]) {
+			if id == vpcId {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+	root.addGroup("internet gateways", igws, func(r *graph.Resource) *treeNode {
+		return &treeNode{label: topologyLabel(r)}
+	})
+
+	inVpc := func(r *graph.Resource) bool { return fmt.Sprint(r.Properties["VpcId"]) == vpcId }
+
+	routeTables, err := resourcesWhere(g, graph.RouteTable, inVpc)
+	if err != nil {
+		return err
+	}
+	routeTablePerSubnet := make(map[string]*graph.Resource)
+	var mainRouteTable *graph.Resource
+	for _, rt := range routeTables {
+		if main, _ := rt.Properties["Main"].(bool); main {
+			mainRouteTable = rt
+		}
+		associated, err := g.ListResourcesAppliedOn(rt)
+		if err != nil {
+			return err
+		}
+		for _, sub := range associated {
+			routeTablePerSubnet[sub.Id()] = rt
+		}
+	}
+	root.addGroup("route tables", routeTables, func(r *graph.Resource) *treeNode {
+		n := &treeNode{label: topologyLabel(r)}
+		if main, _ := r.Properties["Main"].(bool); main {
+			n.label += " (main)"
+		}
+		routes, _ := r.Properties["Routes"].([]*graph.Route)
+		for _, route := range routes {
+			n.children = append(n.children, &treeNode{label: routeLabel(route)})
+		}
+		return n
+	})
+
+	securityGroups, err := resourcesWhere(g, graph.SecurityGroup, inVpc)
+	if err != nil {
+		return err
+	}
+	root.addGroup("security groups", securityGroups, func(r *graph.Resource) *treeNode {
+		return &treeNode{label: topologyLabel(r)}
+	})
+
+	subnets, err := resourcesWhere(g, graph.Subnet, inVpc)
+	if err != nil {
+		return err
+	}
+	var buildErr error
+	root.addGroup("subnets", subnets, func(sub *graph.Resource) *treeNode {
+		n := &treeNode{label: topologyLabel(sub, fmt.Sprint(sub.Properties["CidrBlock"]), fmt.Sprint(sub.Properties["AvailabilityZone"]))}
+		rt, ok := routeTablePerSubnet[sub.Id()]
+		if !ok {
+			rt = mainRouteTable
+		}
+		if rt != nil {
+			n.label += fmt.Sprintf(" routed by %s", rt.Id())
+		}
+		instances, err := resourcesWhere(g, graph.Instance, func(r *graph.Resource) bool { return fmt.Sprint(r.Properties["SubnetId"]) == sub.Id() })
+		if err != nil {
+			buildErr = err
+		}
+		for _, inst := range instances {
+			n.children = append(n.children, &treeNode{label: instanceLabel(inst)})
+		}
+		return n
+	})
+	if buildErr != nil {
+		return buildErr
+	}
+
+	root.print(w, "", true, true)
+	return nil
+}
+
+type treeNode struct {
+	label    string
+	children []*treeNode
+}
+
+func (n *treeNode) addGroup(title string, resources []*graph.Resource, build func(*graph.Resource) *treeNode) {
+	if len(resources) == 0 {
+		return
+	}
+	group := &treeNode{label: color.New(color.Bold).SprintFunc()(fmt.Sprintf("%s (%d)", title, len(resources)))}
+	for _, r := range resources {
+		group.children = append(group.children, build(r))
+	}
+	n.children = append(n.children, group)
+}
+
+func (n *treeNode) print(w io.Writer, prefix string, last, root bool) {
+	switch {
+	case root:
+		fmt.Fprintln(w, n.label)
+	case last:
+		fmt.Fprintf(w, "%s└── %s\n", prefix, n.label)
+		prefix += "    "
+	default:
+		fmt.Fprintf(w, "%s├── %s\n", prefix, n.label)
+		prefix += "│   "
+	}
+	for i, child := range n.children {
+		child.print(w, prefix, i == len(n.children)-1, false)
+	}
+}
+
+func resourcesWhere(g *graph.Graph, t graph.ResourceType, keep graph.FilterFn) ([]*graph.Resource, error) {
+	all, err := g.GetAllResources(t)
+	if err != nil {
+		return nil, err
+	}
+	var res []*graph.Resource
+	for _, r := range all {
+		if keep(r) {
+			res = append(res, r)
+		}
+	}
+	sort.Sort(graph.ResourceById(res))
+	return res, nil
+}
+
+func topologyLabel(r *graph.Resource, details ...string) string {
+	label := r.Id()
+	if name, ok := r.Properties["Name"]; ok && name != "" && name != r.Id() {
+		label += fmt.Sprintf(" @%v", name)
+	}
+	var infos []string
+	for _, d := range details {
+		if d != "" && d != "<nil>" {
+			infos = append(infos, d)
+		}
+	}
+	if len(infos) > 0 {
+		label += fmt.Sprintf(" [%s]", strings.Join(infos, ", "))
+	}
+	return label
+}
+
+func instanceLabel(inst *graph.Resource) string {
+	var details []string
+	if state, ok := inst.Properties["State"]; ok {
+		details = append(details, fmt.Sprint(state))
+	}
+	if typ, ok := inst.Properties["Type"]; ok {
+		details = append(details, fmt.Sprint(typ))
+	}
+	if ip, ok := inst.Properties["PrivateIp"]; ok {
+		details = append(details, fmt.Sprint(ip))
+	}
+	if ip, ok := inst.Properties["PublicIp"]; ok {
+		details = append(details, fmt.Sprintf("public %v", ip))
+	}
+	return topologyLabel(inst, details...)
+}
+
+func routeLabel(route *graph.Route) string {
+	var dest string
+	switch {
+	case route.Destination != nil:
+		dest = route.Destination.String()
+	case route.DestinationIPv6 != nil:
+		dest = route.DestinationIPv6.String()
+	default:
+		dest = route.DestinationPrefixListId
+	}
+	var targets []string
+	for _, t := range route.Targets {
+		targets = append(targets, t.Ref)
+	}
+	return fmt.Sprintf("%s → %s", dest, strings.Join(targets, ", "))
+}
+
+func stringList(prop interface{}) []string {
+	switch v := prop.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		var res []string
+		for _, e := range v {
+			res = append(res, fmt.Sprint(e))
+		}
+		return res
+	}
+	return nil
+}
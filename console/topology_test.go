@@ -0,0 +1,95 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/wallix/awless/graph"
+)
+
+func TestPrintVpcTopology(t *testing.T) {
+	g := graph.NewGraph()
+
+	vpc := graph.InitResource("vpc_1", graph.Vpc)
+	vpc.Properties["Id"] = "vpc_1"
+	vpc.Properties["Name"] = "prod"
+	vpc.Properties["CidrBlock"] = "10.0.0.0/16"
+	igw := graph.InitResource("igw_1", graph.InternetGateway)
+	igw.Properties["Id"] = "igw_1"
+	igw.Properties["Vpcs"] = []string{"vpc_1"}
+	_, anywhere, _ := net.ParseCIDR("0.0.0.0/0")
+	rt := graph.InitResource("rt_1", graph.RouteTable)
+	rt.Properties["Id"] = "rt_1"
+	rt.Properties["VpcId"] = "vpc_1"
+	rt.Properties["Main"] = true
+	rt.Properties["Routes"] = []*graph.Route{{Destination: anywhere, Targets: []*graph.RouteTarget{{Type: graph.GatewayTarget, Ref: "igw_1"}}}}
+	sg := graph.InitResource("sg_1", graph.SecurityGroup)
+	sg.Properties["Id"] = "sg_1"
+	sg.Properties["Name"] = "web"
+	sg.Properties["VpcId"] = "vpc_1"
+	sub1 := graph.InitResource("sub_1", graph.Subnet)
+	sub1.Properties["Id"] = "sub_1"
+	sub1.Properties["VpcId"] = "vpc_1"
+	sub1.Properties["CidrBlock"] = "10.0.1.0/24"
+	sub1.Properties["AvailabilityZone"] = "eu-west-1a"
+	sub2 := graph.InitResource("sub_2", graph.Subnet)
+	sub2.Properties["Id"] = "sub_2"
+	sub2.Properties["VpcId"] = "vpc_1"
+	sub2.Properties["CidrBlock"] = "10.0.2.0/24"
+	sub2.Properties["AvailabilityZone"] = "eu-west-1b"
+	inst1 := graph.InitResource("inst_1", graph.Instance)
+	inst1.Properties["Id"] = "inst_1"
+	inst1.Properties["Name"] = "front"
+	inst1.Properties["SubnetId"] = "sub_1"
+	inst1.Properties["State"] = "running"
+	inst1.Properties["PrivateIp"] = "10.0.1.5"
+	inst2 := graph.InitResource("inst_2", graph.Instance)
+	inst2.Properties["Id"] = "inst_2"
+	inst2.Properties["SubnetId"] = "sub_1"
+	inst2.Properties["State"] = "stopped"
+	otherSub := graph.InitResource("sub_3", graph.Subnet)
+	otherSub.Properties["Id"] = "sub_3"
+	otherSub.Properties["VpcId"] = "vpc_2"
+
+	g.AddResource(vpc, igw, rt, sg, sub1, sub2, inst1, inst2, otherSub)
+
+	var w bytes.Buffer
+	if err := PrintVpcTopology(&w, g, vpc); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `vpc_1 @prod [10.0.0.0/16]
+├── internet gateways (1)
+│   └── igw_1
+├── route tables (1)
+│   └── rt_1 (main)
+│       └── 0.0.0.0/0 → igw_1
+├── security groups (1)
+│   └── sg_1 @web
+└── subnets (2)
+    ├── sub_1 [10.0.1.0/24, eu-west-1a] routed by rt_1
+    │   ├── inst_1 @front [running, 10.0.1.5]
+    │   └── inst_2 [stopped]
+    └── sub_2 [10.0.2.0/24, eu-west-1b] routed by rt_1
+`
+	if got, want := w.String(), expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+)
+
+// Aliases are user defined names for resources ids or values
+type Aliases map[string]string
+
+func (db *DB) GetAliases() (Aliases, error) {
+	a := make(Aliases)
+	b, err := db.GetBytes(aliasesKey)
+	if err != nil {
+		return a, err
+	}
+	if len(b) == 0 {
+		return a, nil
+	}
+
+	dec := gob.NewDecoder(bytes.NewReader(b))
+	if err := dec.Decode(&a); err != nil {
+		return a, err
+	}
+	return a, nil
+}
+
+func (db *DB) GetAlias(name string) (string, bool) {
+	all, err := db.GetAliases()
+	if err != nil {
+		return "", false
+	}
+	v, ok := all[name]
+	return v, ok
+}
+
+func (db *DB) SetAlias(name, value string) error {
+	all, err := db.GetAliases()
+	if err != nil {
+		return err
+	}
+	all[name] = value
+	return db.saveAliases(all)
+}
+
+func (db *DB) UnsetAlias(name string) error {
+	all, err := db.GetAliases()
+	if err != nil {
+		return err
+	}
+	if _, ok := all[name]; !ok {
+		return fmt.Errorf("alias '%s' does not exist", name)
+	}
+	delete(all, name)
+	return db.saveAliases(all)
+}
+
+func (db *DB) saveAliases(a Aliases) error {
+	var buff bytes.Buffer
+	enc := gob.NewEncoder(&buff)
+	if err := enc.Encode(a); err != nil {
+		return err
+	}
+	return db.SetBytes(aliasesKey, buff.Bytes())
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import "testing"
+
+func TestAliases(t *testing.T) {
+	db, close := newTestDb()
+	defer close()
+
+	if _, ok := db.GetAlias("db-prod"); ok {
+		t.Fatal("expected no alias")
+	}
+	if err := db.SetAlias("db-prod", "i-0abc123"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetAlias("web", "i-0def456"); err != nil {
+		t.Fatal(err)
+	}
+
+	v, ok := db.GetAlias("db-prod")
+	if !ok {
+		t.Fatal("expected alias")
+	}
+	if got, want := v, "i-0abc123"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	if err := db.UnsetAlias("db-prod"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UnsetAlias("db-prod"); err == nil {
+		t.Fatal("expected error got none")
+	}
+	all, err := db.GetAliases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(all), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"sort"
+)
+
+// A Context bundles a profile, a region and config values under a name
+type Context struct {
+	Name, Profile, Region string
+	Defaults              map[string]interface{}
+}
+
+// ApplyTo overrides the given defaults with the context ones
+func (c *Context) ApplyTo(d map[string]interface{}) {
+	for k, v := range c.Defaults {
+		d[k] = v
+	}
+	if c.Region != "" {
+		d[RegionKey] = c.Region
+	}
+	if c.Profile != "" {
+		d[ProfileKey] = c.Profile
+	}
+}
+
+type contexts map[string]*Context
+
+func (db *DB) GetContexts() ([]*Context, error) {
+	all, err := db.getContexts()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name := range all {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var res []*Context
+	for _, name := range names {
+		res = append(res, all[name])
+	}
+	return res, nil
+}
+
+func (db *DB) GetContext(name string) (*Context, bool) {
+	all, err := db.getContexts()
+	if err != nil {
+		return nil, false
+	}
+	c, ok := all[name]
+	return c, ok
+}
+
+func (db *DB) SetContext(c *Context) error {
+	all, err := db.getContexts()
+	if err != nil {
+		return err
+	}
+	all[c.Name] = c
+	return db.saveContexts(all)
+}
+
+func (db *DB) DeleteContext(name string) error {
+	all, err := db.getContexts()
+	if err != nil {
+		return err
+	}
+	if _, ok := all[name]; !ok {
+		return fmt.Errorf("context '%s' does not exist", name)
+	}
+	delete(all, name)
+	if current, _ := db.GetStringValue(currentContextKey); current == name {
+		if err = db.SetStringValue(currentContextKey, ""); err != nil {
+			return err
+		}
+	}
+	return db.saveContexts(all)
+}
+
+// UseContext sets the active context. An empty name deactivates the current context
+func (db *DB) UseContext(name string) error {
+	if name != "" {
+		if _, ok := db.GetContext(name); !ok {
+			return fmt.Errorf("context '%s' does not exist", name)
+		}
+	}
+	return db.SetStringValue(currentContextKey, name)
+}
+
+// GetCurrentContext returns the active context, if any
+func (db *DB) GetCurrentContext() (*Context, bool) {
+	name, err := db.GetStringValue(currentContextKey)
+	if err != nil || name == "" {
+		return nil, false
+	}
+	return db.GetContext(name)
+}
+
+func (db *DB) getContexts() (contexts, error) {
+	c := make(contexts)
+	b, err := db.GetBytes(contextsKey)
+	if err != nil {
+		return c, err
+	}
+	if len(b) == 0 {
+		return c, nil
+	}
+
+	dec := gob.NewDecoder(bytes.NewReader(b))
+	if err := dec.Decode(&c); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+func (db *DB) saveContexts(c contexts) error {
+	var buff bytes.Buffer
+	enc := gob.NewEncoder(&buff)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	return db.SetBytes(contextsKey, buff.Bytes())
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContexts(t *testing.T) {
+	db, close := newTestDb()
+	defer close()
+
+	if _, ok := db.GetCurrentContext(); ok {
+		t.Fatal("expected no current context")
+	}
+	if err := db.UseContext("prod"); err == nil {
+		t.Fatal("expected error got none")
+	}
+
+	prod := &Context{Name: "prod", Profile: "prod", Region: "eu-west-1", Defaults: map[string]interface{}{InstanceTypeKey: "t2.small", InstanceCountKey: 2}}
+	dev := &Context{Name: "dev", Region: "us-east-1"}
+	if err := db.SetContext(prod); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetContext(dev); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := db.GetContexts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(all), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := all[0].Name, "dev"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	if err = db.UseContext("prod"); err != nil {
+		t.Fatal(err)
+	}
+	current, ok := db.GetCurrentContext()
+	if !ok {
+		t.Fatal("expected current context")
+	}
+	if got, want := current, prod; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	d := map[string]interface{}{RegionKey: "us-west-1", InstanceTypeKey: "t2.micro", InstanceImageKey: "ami-123"}
+	current.ApplyTo(d)
+	expected := map[string]interface{}{RegionKey: "eu-west-1", ProfileKey: "prod", InstanceTypeKey: "t2.small", InstanceCountKey: 2, InstanceImageKey: "ami-123"}
+	if got, want := d, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	if err = db.DeleteContext("prod"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok = db.GetCurrentContext(); ok {
+		t.Fatal("expected no current context after deletion")
+	}
+	if err = db.DeleteContext("prod"); err == nil {
+		t.Fatal("expected error got none")
+	}
+}
@@ -30,6 +30,7 @@ const (
 	InstanceImageKey = "instance.image"
 	InstanceCountKey = "instance.count"
 	ProfileKey       = "aws.profile"
+	StatsModeKey     = "stats.mode"
 )
 
 type defaults map[string]interface{}
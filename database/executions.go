@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/wallix/awless/statecrypt"
 	"github.com/wallix/awless/template"
 
 	"github.com/boltdb/bolt"
@@ -39,6 +40,9 @@ func (db *DB) AddTemplateExecution(templ *template.TemplateExecution) error {
 		if err != nil {
 			return err
 		}
+		if b, err = statecrypt.Encrypt(b); err != nil {
+			return err
+		}
 
 		return bucket.Put([]byte(templ.ID), b)
 	})
@@ -59,7 +63,11 @@ func (db *DB) GetTemplateExecution(id string) (*template.TemplateExecution, erro
 			return errors.New("no template executions stored yet")
 		}
 		if content := b.Get([]byte(id)); content != nil {
-			return json.Unmarshal(b.Get([]byte(id)), tpl)
+			plain, err := statecrypt.Decrypt(content)
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(plain, tpl)
 		} else {
 			return fmt.Errorf("no content for id '%s'", id)
 		}
@@ -81,7 +89,11 @@ func (db *DB) ListTemplateExecutions() ([]*template.TemplateExecution, error) {
 
 		for k, v := c.First(); k != nil; k, v = c.Next() {
 			t := &template.TemplateExecution{}
-			if err := json.Unmarshal(v, t); err != nil {
+			plain, err := statecrypt.Decrypt(v)
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(plain, t); err != nil {
 				return err
 			}
 			result = append(result, t)
@@ -0,0 +1,68 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/wallix/awless/statecrypt"
+	"github.com/wallix/awless/template"
+)
+
+func TestEncryptedTemplateExecutions(t *testing.T) {
+	db, closing := newTestDb()
+	defer closing()
+
+	os.Setenv(statecrypt.PassphraseEnv, "s3cr3t")
+	defer os.Unsetenv(statecrypt.PassphraseEnv)
+	if err := statecrypt.Enable(statecrypt.PassphraseMode, filepath.Join(os.Getenv("__AWLESS_HOME"), "state.salt"), nil); err != nil {
+		t.Fatal(err)
+	}
+	defer statecrypt.Disable()
+
+	exec := &template.TemplateExecution{ID: "01BA7A8W5ZS8JYFFF4NP6EP5RV", Executed: []*template.ExecutedStatement{{Line: "create instance name=john"}}}
+	if err := db.AddTemplateExecution(exec); err != nil {
+		t.Fatal(err)
+	}
+
+	db.bolt.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket([]byte(EXECUTIONS_BUCKET)).Get([]byte(exec.ID))
+		if !statecrypt.IsEncrypted(raw) || bytes.Contains(raw, []byte("john")) {
+			t.Fatalf("expected encrypted execution, got %q", raw)
+		}
+		return nil
+	})
+
+	got, err := db.GetTemplateExecution(exec.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := got.Executed[0].Line, "create instance name=john"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	all, err := db.ListTemplateExecutions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(all), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
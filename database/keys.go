@@ -21,4 +21,8 @@ const (
 	logsKey           = "logs"
 	historyBucketName = "line"
 	defaultsKey       = "defaults"
+	contextsKey       = "contexts"
+	currentContextKey = "context.current"
+	aliasesKey        = "aliases"
+	usageStatsKey     = "stats.usage"
 )
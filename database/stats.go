@@ -0,0 +1,85 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Stats modes, set in config with the StatsModeKey
+const (
+	StatsOff   = "off"
+	StatsLocal = "local"
+)
+
+// UsageStats are usage counters only kept in the local database
+type UsageStats struct {
+	Since     time.Time
+	Commands  map[string]int
+	Templates map[string]int
+}
+
+func (db *DB) GetUsageStats() (*UsageStats, error) {
+	stats := &UsageStats{Commands: make(map[string]int), Templates: make(map[string]int)}
+	b, err := db.GetBytes(usageStatsKey)
+	if err != nil {
+		return stats, err
+	}
+	if len(b) == 0 {
+		return stats, nil
+	}
+	err = json.Unmarshal(b, stats)
+	return stats, err
+}
+
+// AddCommandUsage increments the usage counter of a command
+func (db *DB) AddCommandUsage(command string) error {
+	return db.updateUsageStats(func(s *UsageStats) { s.Commands[command]++ })
+}
+
+// AddTemplateUsage increments the usage counter of a template action on an entity. Ex: create instance
+func (db *DB) AddTemplateUsage(actionEntity string) error {
+	return db.updateUsageStats(func(s *UsageStats) { s.Templates[actionEntity]++ })
+}
+
+func (db *DB) DeleteUsageStats() error {
+	return db.SetBytes(usageStatsKey, []byte{})
+}
+
+func (db *DB) updateUsageStats(update func(*UsageStats)) error {
+	stats, err := db.GetUsageStats()
+	if err != nil {
+		return err
+	}
+	if stats.Since.IsZero() {
+		stats.Since = time.Now()
+	}
+	if stats.Commands == nil {
+		stats.Commands = make(map[string]int)
+	}
+	if stats.Templates == nil {
+		stats.Templates = make(map[string]int)
+	}
+	update(stats)
+
+	b, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	return db.SetBytes(usageStatsKey, b)
+}
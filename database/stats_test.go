@@ -0,0 +1,57 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import "testing"
+
+func TestUsageStats(t *testing.T) {
+	db, close := newTestDb()
+	defer close()
+
+	stats, err := db.GetUsageStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stats.Since.IsZero() || len(stats.Commands) != 0 {
+		t.Fatalf("expected empty stats, got %#v", stats)
+	}
+
+	db.AddCommandUsage("awless list instances")
+	db.AddCommandUsage("awless list instances")
+	db.AddCommandUsage("awless sync")
+	db.AddTemplateUsage("create instance")
+
+	if stats, err = db.GetUsageStats(); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Since.IsZero() {
+		t.Fatal("expected stats start time")
+	}
+	if got, want := stats.Commands["awless list instances"], 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := stats.Templates["create instance"], 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+
+	if err = db.DeleteUsageStats(); err != nil {
+		t.Fatal(err)
+	}
+	if stats, _ = db.GetUsageStats(); len(stats.Commands) != 0 {
+		t.Fatalf("expected empty stats, got %#v", stats)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	computeURL = "https://compute.googleapis.com/compute/v1"
+	storageURL = "https://storage.googleapis.com/storage/v1"
+	iamURL     = "https://iam.googleapis.com/v1"
+)
+
+// client performs authenticated calls to the GCP REST APIs of a project
+type client struct {
+	httpClient *http.Client
+	token      string
+	project    string
+
+	computeURL, storageURL, iamURL string
+}
+
+func newClient(project, token string) *client {
+	return &client{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		token:      token,
+		project:    project,
+		computeURL: computeURL,
+		storageURL: storageURL,
+		iamURL:     iamURL,
+	}
+}
+
+// apiError is the error body returned by GCP APIs
+type apiError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (c *client) get(rawurl string) ([]byte, error) {
+	return c.do("GET", rawurl, nil)
+}
+
+func (c *client) do(method, rawurl string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, rawurl, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr apiError
+		if json.Unmarshal(content, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, &httpError{status: resp.StatusCode, msg: apiErr.Error.Message}
+		}
+		return nil, &httpError{status: resp.StatusCode, msg: http.StatusText(resp.StatusCode)}
+	}
+	return content, nil
+}
+
+// getPages calls the given paginated listing URL until no next page token is returned
+func (c *client) getPages(rawurl string, onPage func(body []byte) error) error {
+	var pageToken string
+	for {
+		u, err := url.Parse(rawurl)
+		if err != nil {
+			return err
+		}
+		if pageToken != "" {
+			query := u.Query()
+			query.Set("pageToken", pageToken)
+			u.RawQuery = query.Encode()
+		}
+		body, err := c.get(u.String())
+		if err != nil {
+			return err
+		}
+		if err = onPage(body); err != nil {
+			return err
+		}
+		var page struct {
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err = json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		if page.NextPageToken == "" {
+			return nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+type httpError struct {
+	status int
+	msg    string
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("gcp: %d %s", e.status, e.msg)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import "github.com/wallix/awless/template"
+
+// TemplatesDefinitions holds the definitions of the statements run by the GCP driver
+var TemplatesDefinitions = map[string]template.TemplateDefinition{
+	"createinstance": {
+		Action:         "create",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"name", "zone", "type", "image"},
+		ExtraParams:    []string{"subnet", "tags"},
+		ParamTypes:     map[string]string{"image": "string", "name": "string", "subnet": "string", "tags": "map", "type": "string", "zone": "string"},
+	},
+	"deleteinstance": {
+		Action:         "delete",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"id", "zone"},
+		ParamTypes:     map[string]string{"id": "string", "zone": "string"},
+	},
+	"startinstance": {
+		Action:         "start",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"id", "zone"},
+		ParamTypes:     map[string]string{"id": "string", "zone": "string"},
+	},
+	"stopinstance": {
+		Action:         "stop",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"id", "zone"},
+		ParamTypes:     map[string]string{"id": "string", "zone": "string"},
+	},
+	"createvpc": {
+		Action:         "create",
+		Entity:         "vpc",
+		Api:            "compute",
+		RequiredParams: []string{"name"},
+		ParamTypes:     map[string]string{"name": "string"},
+	},
+	"deletevpc": {
+		Action:         "delete",
+		Entity:         "vpc",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"createsubnet": {
+		Action:         "create",
+		Entity:         "subnet",
+		Api:            "compute",
+		RequiredParams: []string{"name", "region", "vpc", "cidr"},
+		ParamTypes:     map[string]string{"cidr": "string", "name": "string", "region": "string", "vpc": "string"},
+	},
+	"deletesubnet": {
+		Action:         "delete",
+		Entity:         "subnet",
+		Api:            "compute",
+		RequiredParams: []string{"id", "region"},
+		ParamTypes:     map[string]string{"id": "string", "region": "string"},
+	},
+	"createbucket": {
+		Action:         "create",
+		Entity:         "bucket",
+		Api:            "cloudstorage",
+		RequiredParams: []string{"name"},
+		ExtraParams:    []string{"location", "class", "tags"},
+		ParamTypes:     map[string]string{"class": "string", "location": "string", "name": "string", "tags": "map"},
+	},
+	"updatebucket": {
+		Action:         "update",
+		Entity:         "bucket",
+		Api:            "cloudstorage",
+		RequiredParams: []string{"name"},
+		ExtraParams:    []string{"class", "tags"},
+		ParamTypes:     map[string]string{"class": "string", "name": "string", "tags": "map"},
+	},
+	"deletebucket": {
+		Action:         "delete",
+		Entity:         "bucket",
+		Api:            "cloudstorage",
+		RequiredParams: []string{"name"},
+		ParamTypes:     map[string]string{"name": "string"},
+	},
+	"createuser": {
+		Action:         "create",
+		Entity:         "user",
+		Api:            "iam",
+		RequiredParams: []string{"name"},
+		ExtraParams:    []string{"displayname"},
+		ParamTypes:     map[string]string{"displayname": "string", "name": "string"},
+	},
+	"deleteuser": {
+		Action:         "delete",
+		Entity:         "user",
+		Api:            "iam",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+}
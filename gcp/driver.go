@@ -0,0 +1,416 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template/driver"
+)
+
+// Driver runs template commands against the GCP REST APIs. It creates and deletes
+// instances, networks, subnetworks, buckets and service accounts (as users), starts
+// and stops instances, and updates the labels and storage class of buckets.
+// Compute resources are identified by their name or numeric id, with the zone
+// of instances and the region of subnetworks given as params
+type Driver struct {
+	api      *client
+	entities []string
+	dryRun   bool
+	logger   *logger.Logger
+}
+
+func NewDriver(api *client, entities ...string) driver.Driver {
+	return &Driver{api: api, entities: entities, logger: logger.DiscardLogger}
+}
+
+func (d *Driver) SetDryRun(dry bool)         { d.dryRun = dry }
+func (d *Driver) SetLogger(l *logger.Logger) { d.logger = l }
+
+// request is an API call built from the params of a template command
+type request struct {
+	method, url string
+	body        interface{}
+	// id returns the id of the resource acted on from the response
+	id func(resp []byte) (string, error)
+}
+
+type requestFunc func(params map[string]interface{}) (*request, error)
+
+func (d *Driver) Lookup(lookups ...string) (driver.DriverFn, error) {
+	if len(lookups) != 2 || !contains(d.entities, lookups[1]) {
+		return nil, driver.ErrDriverFnNotFound
+	}
+	action, entity := lookups[0], lookups[1]
+	var fn requestFunc
+	switch action + " " + entity {
+	case "create instance":
+		fn = d.createInstance
+	case "delete instance":
+		fn = d.instanceRequest("DELETE", "")
+	case "start instance":
+		fn = d.instanceRequest("POST", "start")
+	case "stop instance":
+		fn = d.instanceRequest("POST", "stop")
+	case "create vpc":
+		fn = d.createNetwork
+	case "delete vpc":
+		fn = d.deleteNetwork
+	case "create subnet":
+		fn = d.createSubnetwork
+	case "delete subnet":
+		fn = d.deleteSubnetwork
+	case "create bucket":
+		fn = d.createBucket
+	case "update bucket":
+		fn = d.updateBucket
+	case "delete bucket":
+		fn = d.deleteBucket
+	case "create user":
+		fn = d.createServiceAccount
+	case "delete user":
+		fn = d.deleteServiceAccount
+	default:
+		return nil, driver.ErrDriverFnNotFound
+	}
+	return d.driverFn(action, entity, fn), nil
+}
+
+func (d *Driver) driverFn(action, entity string, fn requestFunc) driver.DriverFn {
+	return func(params map[string]interface{}) (interface{}, error) {
+		req, err := fn(params)
+		if err != nil {
+			err = fmt.Errorf("%s %s: %s", action, entity, err)
+			d.logger.Errorf("%s", err)
+			return nil, err
+		}
+		if d.dryRun {
+			d.logger.Verbosef("dry run: %s %s ok", action, entity)
+			return nil, nil
+		}
+		var body io.Reader
+		if req.body != nil {
+			content, err := json.Marshal(req.body)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(content)
+		}
+		resp, err := d.api.do(req.method, req.url, body)
+		if err != nil {
+			d.logger.Errorf("%s %s error: %s", action, entity, err)
+			return nil, err
+		}
+		id, err := req.id(resp)
+		if err != nil {
+			d.logger.Errorf("%s %s error: %s", action, entity, err)
+			return nil, err
+		}
+		d.logger.Verbosef("%s %s '%s' done", action, entity, id)
+		return id, nil
+	}
+}
+
+func (d *Driver) createInstance(params map[string]interface{}) (*request, error) {
+	name, zone, err := requiredParams(params, "name", "zone")
+	if err != nil {
+		return nil, err
+	}
+	machineType, image, err := requiredParams(params, "type", "image")
+	if err != nil {
+		return nil, err
+	}
+	nic := map[string]interface{}{"network": "global/networks/default"}
+	if subnet, ok := params["subnet"].(string); ok && subnet != "" {
+		nic = map[string]interface{}{"subnetwork": d.regionalLink(zoneRegion(zone), "subnetworks", subnet)}
+	}
+	body := map[string]interface{}{
+		"name":              name,
+		"machineType":       "zones/" + zone + "/machineTypes/" + machineType,
+		"disks":             []interface{}{map[string]interface{}{"boot": true, "autoDelete": true, "initializeParams": map[string]interface{}{"sourceImage": image}}},
+		"networkInterfaces": []interface{}{nic},
+	}
+	if labels, err := labelsParam(params); err != nil {
+		return nil, err
+	} else if len(labels) > 0 {
+		body["labels"] = labels
+	}
+	return &request{method: "POST", url: d.projectURL("zones", zone, "instances"), body: body, id: d.waitOperation}, nil
+}
+
+func (d *Driver) instanceRequest(method, operation string) requestFunc {
+	return func(params map[string]interface{}) (*request, error) {
+		id, zone, err := requiredParams(params, "id", "zone")
+		if err != nil {
+			return nil, err
+		}
+		u := d.projectURL("zones", zone, "instances", id)
+		if operation != "" {
+			u += "/" + operation
+		}
+		return &request{method: method, url: u, id: d.waitOperation}, nil
+	}
+}
+
+func (d *Driver) createNetwork(params map[string]interface{}) (*request, error) {
+	name, ok := params["name"].(string)
+	if !ok || name == "" {
+		return nil, errors.New("missing 'name' param")
+	}
+	body := map[string]interface{}{"name": name, "autoCreateSubnetworks": false}
+	return &request{method: "POST", url: d.projectURL("global", "networks"), body: body, id: d.waitOperation}, nil
+}
+
+func (d *Driver) deleteNetwork(params map[string]interface{}) (*request, error) {
+	id, ok := params["id"].(string)
+	if !ok || id == "" {
+		return nil, errors.New("missing 'id' param")
+	}
+	return &request{method: "DELETE", url: d.projectURL("global", "networks", id), id: d.waitOperation}, nil
+}
+
+func (d *Driver) createSubnetwork(params map[string]interface{}) (*request, error) {
+	name, region, err := requiredParams(params, "name", "region")
+	if err != nil {
+		return nil, err
+	}
+	vpc, cidr, err := requiredParams(params, "vpc", "cidr")
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{"name": name, "network": d.globalLink("networks", vpc), "ipCidrRange": cidr}
+	return &request{method: "POST", url: d.projectURL("regions", region, "subnetworks"), body: body, id: d.waitOperation}, nil
+}
+
+func (d *Driver) deleteSubnetwork(params map[string]interface{}) (*request, error) {
+	id, region, err := requiredParams(params, "id", "region")
+	if err != nil {
+		return nil, err
+	}
+	return &request{method: "DELETE", url: d.projectURL("regions", region, "subnetworks", id), id: d.waitOperation}, nil
+}
+
+func (d *Driver) createBucket(params map[string]interface{}) (*request, error) {
+	name, ok := params["name"].(string)
+	if !ok || name == "" {
+		return nil, errors.New("missing 'name' param")
+	}
+	body, err := bucketBody(params)
+	if err != nil {
+		return nil, err
+	}
+	body["name"] = name
+	u := fmt.Sprintf("%s/b?project=%s", d.api.storageURL, url.QueryEscape(d.api.project))
+	return &request{method: "POST", url: u, body: body, id: jsonField("name")}, nil
+}
+
+func (d *Driver) updateBucket(params map[string]interface{}) (*request, error) {
+	name, ok := params["name"].(string)
+	if !ok || name == "" {
+		return nil, errors.New("missing 'name' param")
+	}
+	body, err := bucketBody(params)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return nil, errors.New("missing 'tags' or 'class' param")
+	}
+	return &request{method: "PATCH", url: d.api.storageURL + "/b/" + url.PathEscape(name), body: body, id: jsonField("name")}, nil
+}
+
+func (d *Driver) deleteBucket(params map[string]interface{}) (*request, error) {
+	name, ok := params["name"].(string)
+	if !ok || name == "" {
+		return nil, errors.New("missing 'name' param")
+	}
+	return &request{method: "DELETE", url: d.api.storageURL + "/b/" + url.PathEscape(name), id: constantId(name)}, nil
+}
+
+// bucketBody returns the location, storage class and labels set by the params
+func bucketBody(params map[string]interface{}) (map[string]interface{}, error) {
+	body := make(map[string]interface{})
+	if location, ok := params["location"].(string); ok && location != "" {
+		body["location"] = location
+	}
+	if class, ok := params["class"].(string); ok && class != "" {
+		body["storageClass"] = class
+	}
+	labels, err := labelsParam(params)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) > 0 {
+		body["labels"] = labels
+	}
+	return body, nil
+}
+
+func (d *Driver) createServiceAccount(params map[string]interface{}) (*request, error) {
+	name, ok := params["name"].(string)
+	if !ok || name == "" {
+		return nil, errors.New("missing 'name' param")
+	}
+	body := map[string]interface{}{"accountId": name}
+	if display, ok := params["displayname"].(string); ok && display != "" {
+		body["serviceAccount"] = map[string]interface{}{"displayName": display}
+	}
+	u := fmt.Sprintf("%s/projects/%s/serviceAccounts", d.api.iamURL, d.api.project)
+	return &request{method: "POST", url: u, body: body, id: jsonField("uniqueId")}, nil
+}
+
+// deleteServiceAccount deletes a service account given its unique id or email
+func (d *Driver) deleteServiceAccount(params map[string]interface{}) (*request, error) {
+	id, ok := params["id"].(string)
+	if !ok || id == "" {
+		return nil, errors.New("missing 'id' param")
+	}
+	u := fmt.Sprintf("%s/projects/%s/serviceAccounts/%s", d.api.iamURL, d.api.project, url.PathEscape(id))
+	return &request{method: "DELETE", url: u, id: constantId(id)}, nil
+}
+
+// operation is the long running operation returned by Compute Engine for changes
+type operation struct {
+	Status   string `json:"status"`
+	TargetId string `json:"targetId"`
+	SelfLink string `json:"selfLink"`
+	Error    *struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"error"`
+}
+
+// waitOperation waits for the Compute Engine operation given as response to be done,
+// returning the id of the resource it acted on
+func (d *Driver) waitOperation(resp []byte) (string, error) {
+	for {
+		var op operation
+		if err := json.Unmarshal(resp, &op); err != nil {
+			return "", err
+		}
+		if op.Status == "DONE" {
+			if op.Error != nil && len(op.Error.Errors) > 0 {
+				var msgs []string
+				for _, e := range op.Error.Errors {
+					msgs = append(msgs, e.Code+": "+e.Message)
+				}
+				return "", errors.New(strings.Join(msgs, ", "))
+			}
+			return op.TargetId, nil
+		}
+		var err error
+		if resp, err = d.api.do("POST", op.SelfLink+"/wait", nil); err != nil {
+			return "", err
+		}
+	}
+}
+
+func (d *Driver) projectURL(path ...string) string {
+	for i, p := range path {
+		path[i] = url.PathEscape(p)
+	}
+	return fmt.Sprintf("%s/projects/%s/%s", d.api.computeURL, d.api.project, strings.Join(path, "/"))
+}
+
+// globalLink returns the partial URL of a global compute resource given its name, unless already a URL
+func (d *Driver) globalLink(collection, name string) string {
+	if strings.Contains(name, "/") {
+		return name
+	}
+	return fmt.Sprintf("projects/%s/global/%s/%s", d.api.project, collection, name)
+}
+
+// regionalLink returns the partial URL of a regional compute resource given its name, unless already a URL
+func (d *Driver) regionalLink(region, collection, name string) string {
+	if strings.Contains(name, "/") {
+		return name
+	}
+	return fmt.Sprintf("projects/%s/regions/%s/%s/%s", d.api.project, region, collection, name)
+}
+
+// zoneRegion returns the region of a zone (ex: europe-west1 for europe-west1-b)
+func zoneRegion(zone string) string {
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		return zone[:i]
+	}
+	return zone
+}
+
+func requiredParams(params map[string]interface{}, first, second string) (string, string, error) {
+	var values [2]string
+	for i, key := range []string{first, second} {
+		s, ok := params[key].(string)
+		if !ok || s == "" {
+			return "", "", fmt.Errorf("missing '%s' param", key)
+		}
+		values[i] = s
+	}
+	return values[0], values[1], nil
+}
+
+// labelsParam converts the 'tags' map param into GCP labels
+func labelsParam(params map[string]interface{}) (map[string]string, error) {
+	tags, ok := params["tags"]
+	if !ok {
+		return nil, nil
+	}
+	m, isMap := tags.(map[string]interface{})
+	if !isMap {
+		return nil, fmt.Errorf("expecting a map for 'tags' (ex: tags={env:prod}), got %v", tags)
+	}
+	labels := make(map[string]string)
+	for k, v := range m {
+		labels[k] = fmt.Sprint(v)
+	}
+	return labels, nil
+}
+
+func jsonField(name string) func([]byte) (string, error) {
+	return func(resp []byte) (string, error) {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(resp, &fields); err != nil {
+			return "", err
+		}
+		s, ok := fields[name].(string)
+		if !ok {
+			return "", fmt.Errorf("missing '%s' in response", name)
+		}
+		return s, nil
+	}
+}
+
+func constantId(id string) func([]byte) (string, error) {
+	return func([]byte) (string, error) { return id, nil }
+}
+
+func contains(arr []string, s string) bool {
+	for _, a := range arr {
+		if a == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/wallix/awless/template/driver"
+)
+
+func TestDriver(t *testing.T) {
+	var calls []string
+	var bodies []map[string]interface{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		var body map[string]interface{}
+		if content, _ := ioutil.ReadAll(r.Body); len(content) > 0 {
+			if err := json.Unmarshal(content, &body); err != nil {
+				t.Fatal(err)
+			}
+		}
+		bodies = append(bodies, body)
+		switch r.URL.Path {
+		case "/projects/myproj/zones/europe-west1-b/instances":
+			w.Write([]byte(`{"status": "RUNNING", "selfLink": "` + server.URL + `/projects/myproj/zones/europe-west1-b/operations/op-1"}`))
+		case "/projects/myproj/zones/europe-west1-b/operations/op-1/wait":
+			w.Write([]byte(`{"status": "DONE", "targetId": "33"}`))
+		case "/projects/myproj/global/networks/prod":
+			w.Write([]byte(`{"status": "DONE", "targetId": "10", "error": {"errors": [{"code": "RESOURCE_IN_USE_BY_ANOTHER_RESOURCE", "message": "in use"}]}}`))
+		case "/b":
+			w.Write([]byte(`{"name": "assets"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	api := newClient("myproj", "mytoken")
+	api.computeURL, api.storageURL, api.iamURL = server.URL, server.URL, server.URL
+
+	d := NewDriver(api, "instance", "vpc", "bucket")
+	if _, err := d.Lookup("create", "role"); err != driver.ErrDriverFnNotFound {
+		t.Fatalf("got %v, want %v", err, driver.ErrDriverFnNotFound)
+	}
+	if _, err := d.Lookup("update", "instance"); err != driver.ErrDriverFnNotFound {
+		t.Fatalf("got %v, want %v", err, driver.ErrDriverFnNotFound)
+	}
+
+	create, err := d.Lookup("create", "instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = create(map[string]interface{}{"name": "web", "type": "n1-standard-1", "image": "projects/debian-cloud/global/images/family/debian-12"}); err == nil {
+		t.Fatal("expected error for missing zone")
+	}
+	id, err := create(map[string]interface{}{"name": "web", "zone": "europe-west1-b", "type": "n1-standard-1", "subnet": "sub-eu",
+		"image": "projects/debian-cloud/global/images/family/debian-12", "tags": map[string]interface{}{"env": "prod"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := id, "33"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	expected := map[string]interface{}{
+		"name":              "web",
+		"machineType":       "zones/europe-west1-b/machineTypes/n1-standard-1",
+		"disks":             []interface{}{map[string]interface{}{"boot": true, "autoDelete": true, "initializeParams": map[string]interface{}{"sourceImage": "projects/debian-cloud/global/images/family/debian-12"}}},
+		"networkInterfaces": []interface{}{map[string]interface{}{"subnetwork": "projects/myproj/regions/europe-west1/subnetworks/sub-eu"}},
+		"labels":            map[string]interface{}{"env": "prod"},
+	}
+	if got, want := bodies[0], expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	del, _ := d.Lookup("delete", "vpc")
+	if _, err = del(map[string]interface{}{"id": "prod"}); err == nil || err.Error() != "RESOURCE_IN_USE_BY_ANOTHER_RESOURCE: in use" {
+		t.Fatalf("got %v, want operation error", err)
+	}
+
+	bucket, _ := d.Lookup("create", "bucket")
+	if id, err = bucket(map[string]interface{}{"name": "assets", "location": "EU"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := id, "assets"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	d.SetDryRun(true)
+	start, _ := d.Lookup("start", "instance")
+	if _, err = start(map[string]interface{}{"id": "web", "zone": "europe-west1-b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedCalls := []string{
+		"POST /projects/myproj/zones/europe-west1-b/instances",
+		"POST /projects/myproj/zones/europe-west1-b/operations/op-1/wait",
+		"DELETE /projects/myproj/global/networks/prod",
+		"POST /b",
+	}
+	if got, want := calls, expectedCalls; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
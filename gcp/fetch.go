@@ -0,0 +1,268 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wallix/awless/graph"
+)
+
+type network struct {
+	Id                    string `json:"id"`
+	Name                  string `json:"name"`
+	SelfLink              string `json:"selfLink"`
+	IPv4Range             string `json:"IPv4Range"`
+	AutoCreateSubnetworks bool   `json:"autoCreateSubnetworks"`
+	CreationTimestamp     string `json:"creationTimestamp"`
+}
+
+type subnetwork struct {
+	Id                string `json:"id"`
+	Name              string `json:"name"`
+	SelfLink          string `json:"selfLink"`
+	Network           string `json:"network"`
+	IpCidrRange       string `json:"ipCidrRange"`
+	Region            string `json:"region"`
+	GatewayAddress    string `json:"gatewayAddress"`
+	CreationTimestamp string `json:"creationTimestamp"`
+}
+
+type instance struct {
+	Id                string            `json:"id"`
+	Name              string            `json:"name"`
+	SelfLink          string            `json:"selfLink"`
+	MachineType       string            `json:"machineType"`
+	Status            string            `json:"status"`
+	Zone              string            `json:"zone"`
+	CreationTimestamp string            `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels"`
+	NetworkInterfaces []struct {
+		Network       string `json:"network"`
+		Subnetwork    string `json:"subnetwork"`
+		NetworkIP     string `json:"networkIP"`
+		AccessConfigs []struct {
+			NatIP string `json:"natIP"`
+		} `json:"accessConfigs"`
+	} `json:"networkInterfaces"`
+}
+
+type bucket struct {
+	Id           string            `json:"id"`
+	Name         string            `json:"name"`
+	Location     string            `json:"location"`
+	StorageClass string            `json:"storageClass"`
+	TimeCreated  string            `json:"timeCreated"`
+	Labels       map[string]string `json:"labels"`
+}
+
+type serviceAccount struct {
+	Name        string `json:"name"`
+	UniqueId    string `json:"uniqueId"`
+	Email       string `json:"email"`
+	DisplayName string `json:"displayName"`
+	Disabled    bool   `json:"disabled"`
+}
+
+type role struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Stage       string `json:"stage"`
+}
+
+func fetchNetworks(api *client) ([]*graph.Resource, error) {
+	var resources []*graph.Resource
+	err := api.getPages(fmt.Sprintf("%s/projects/%s/global/networks", api.computeURL, api.project), func(body []byte) error {
+		var page struct {
+			Items []network `json:"items"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, n := range page.Items {
+			res := graph.InitResource(n.Id, graph.Vpc)
+			res.Properties["Id"] = n.Id
+			res.Properties["Name"] = n.Name
+			res.Properties[selfLinkProp] = n.SelfLink
+			res.Properties["IsDefault"] = n.Name == "default"
+			setIfNotEmpty(res, "CidrBlock", n.IPv4Range)
+			setTime(res, "CreateDate", n.CreationTimestamp)
+			resources = append(resources, res)
+		}
+		return nil
+	})
+	return resources, err
+}
+
+func fetchSubnetworks(api *client) ([]*graph.Resource, error) {
+	var resources []*graph.Resource
+	err := api.getPages(fmt.Sprintf("%s/projects/%s/aggregated/subnetworks", api.computeURL, api.project), func(body []byte) error {
+		var page struct {
+			Items map[string]struct {
+				Subnetworks []subnetwork `json:"subnetworks"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, scope := range page.Items {
+			for _, s := range scope.Subnetworks {
+				res := graph.InitResource(s.Id, graph.Subnet)
+				res.Properties["Id"] = s.Id
+				res.Properties["Name"] = s.Name
+				res.Properties[selfLinkProp] = s.SelfLink
+				res.Properties[networkLinkProp] = s.Network
+				res.Properties["CidrBlock"] = s.IpCidrRange
+				res.Properties["Region"] = lastSegment(s.Region)
+				setIfNotEmpty(res, "Gateway", s.GatewayAddress)
+				setTime(res, "CreateDate", s.CreationTimestamp)
+				resources = append(resources, res)
+			}
+		}
+		return nil
+	})
+	return resources, err
+}
+
+func fetchInstances(api *client) ([]*graph.Resource, error) {
+	var resources []*graph.Resource
+	err := api.getPages(fmt.Sprintf("%s/projects/%s/aggregated/instances", api.computeURL, api.project), func(body []byte) error {
+		var page struct {
+			Items map[string]struct {
+				Instances []instance `json:"instances"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, i := range page.Items {
+			for _, inst := range i.Instances {
+				res := graph.InitResource(inst.Id, graph.Instance)
+				res.Properties["Id"] = inst.Id
+				res.Properties["Name"] = inst.Name
+				setIfNotEmpty(res, selfLinkProp, inst.SelfLink)
+				res.Properties["Type"] = lastSegment(inst.MachineType)
+				res.Properties["State"] = strings.ToLower(inst.Status)
+				res.Properties["AvailabilityZone"] = lastSegment(inst.Zone)
+				setTime(res, "LaunchTime", inst.CreationTimestamp)
+				if tags := labelsToTags(inst.Labels); len(tags) > 0 {
+					res.Properties["Tags"] = tags
+				}
+				if len(inst.NetworkInterfaces) > 0 {
+					nic := inst.NetworkInterfaces[0]
+					setIfNotEmpty(res, subnetLinkProp, nic.Subnetwork)
+					setIfNotEmpty(res, networkLinkProp, nic.Network)
+					setIfNotEmpty(res, "PrivateIp", nic.NetworkIP)
+					if len(nic.AccessConfigs) > 0 {
+						setIfNotEmpty(res, "PublicIp", nic.AccessConfigs[0].NatIP)
+					}
+				}
+				resources = append(resources, res)
+			}
+		}
+		return nil
+	})
+	return resources, err
+}
+
+func fetchBuckets(api *client) ([]*graph.Resource, error) {
+	var resources []*graph.Resource
+	err := api.getPages(fmt.Sprintf("%s/b?project=%s", api.storageURL, url.QueryEscape(api.project)), func(body []byte) error {
+		var page struct {
+			Items []bucket `json:"items"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, b := range page.Items {
+			res := graph.InitResource(b.Name, graph.Bucket)
+			res.Properties["Id"] = b.Name
+			res.Properties["Name"] = b.Name
+			setIfNotEmpty(res, "Location", b.Location)
+			setIfNotEmpty(res, "Class", b.StorageClass)
+			setTime(res, "CreateDate", b.TimeCreated)
+			if tags := labelsToTags(b.Labels); len(tags) > 0 {
+				res.Properties["Tags"] = tags
+			}
+			resources = append(resources, res)
+		}
+		return nil
+	})
+	return resources, err
+}
+
+func fetchServiceAccounts(api *client) ([]*graph.Resource, error) {
+	var resources []*graph.Resource
+	err := api.getPages(fmt.Sprintf("%s/projects/%s/serviceAccounts", api.iamURL, api.project), func(body []byte) error {
+		var page struct {
+			Accounts []serviceAccount `json:"accounts"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, sa := range page.Accounts {
+			res := graph.InitResource(sa.UniqueId, graph.User)
+			res.Properties["Id"] = sa.UniqueId
+			res.Properties["Name"] = sa.Email
+			res.Properties["Arn"] = sa.Name
+			setIfNotEmpty(res, "DisplayName", sa.DisplayName)
+			res.Properties["Disabled"] = sa.Disabled
+			resources = append(resources, res)
+		}
+		return nil
+	})
+	return resources, err
+}
+
+func fetchRoles(api *client) ([]*graph.Resource, error) {
+	var resources []*graph.Resource
+	err := api.getPages(fmt.Sprintf("%s/projects/%s/roles", api.iamURL, api.project), func(body []byte) error {
+		var page struct {
+			Roles []role `json:"roles"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, r := range page.Roles {
+			res := graph.InitResource(r.Name, graph.Role)
+			res.Properties["Id"] = r.Name
+			res.Properties["Name"] = r.Title
+			setIfNotEmpty(res, "Description", r.Description)
+			setIfNotEmpty(res, "Stage", r.Stage)
+			resources = append(resources, res)
+		}
+		return nil
+	})
+	return resources, err
+}
+
+func setIfNotEmpty(res *graph.Resource, key, value string) {
+	if value != "" {
+		res.Properties[key] = value
+	}
+}
+
+func setTime(res *graph.Resource, key, value string) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		res.Properties[key] = t
+	}
+}
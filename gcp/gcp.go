@@ -0,0 +1,102 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gcp provides a Google Cloud Platform provider mapping compute, storage
+// and IAM resources onto the awless graph model, and managing them with templates.
+package gcp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/wallix/awless/cloud"
+)
+
+const (
+	// ProjectKey is the config key of the GCP project to work with
+	ProjectKey = "gcp.project"
+
+	tokenEnv   = "GOOGLE_OAUTH_ACCESS_TOKEN"
+	projectEnv = "GOOGLE_CLOUD_PROJECT"
+)
+
+var ComputeService, StorageService, AccessService cloud.Service
+
+func init() {
+	cloud.RegisterProvider(provider{})
+}
+
+type provider struct{}
+
+func (provider) Name() string { return "gcp" }
+
+// InitServices registers the GCP services for the project of the given config values
+func (provider) InitServices(defaults map[string]interface{}) error {
+	project, err := resolveProject(defaults)
+	if err != nil {
+		return err
+	}
+	token, err := accessToken()
+	if err != nil {
+		return err
+	}
+	api := newClient(project, token)
+
+	ComputeService = NewCompute(api)
+	StorageService = NewStorage(api)
+	AccessService = NewAccess(api)
+
+	cloud.ServiceRegistry[ComputeService.Name()] = ComputeService
+	cloud.ServiceRegistry[StorageService.Name()] = StorageService
+	cloud.ServiceRegistry[AccessService.Name()] = AccessService
+
+	return nil
+}
+
+func resolveProject(defaults map[string]interface{}) (string, error) {
+	if project, ok := defaults[ProjectKey].(string); ok && project != "" {
+		return project, nil
+	}
+	if project := os.Getenv(projectEnv); project != "" {
+		return project, nil
+	}
+	if project, err := gcloud("config", "get-value", "project"); err == nil && project != "" {
+		return project, nil
+	}
+	return "", fmt.Errorf("missing GCP project. Set it with `awless config set %s my-project` or export %s", ProjectKey, projectEnv)
+}
+
+func accessToken() (string, error) {
+	if token := os.Getenv(tokenEnv); token != "" {
+		return token, nil
+	}
+	token, err := gcloud("auth", "print-access-token")
+	if err != nil || token == "" {
+		return "", errors.New("Your GCP credentials seem undefined! Export " + tokenEnv + " or log in with `gcloud auth login`")
+	}
+	return token, nil
+}
+
+func gcloud(args ...string) (string, error) {
+	out, err := exec.Command("gcloud", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
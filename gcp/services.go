@@ -0,0 +1,168 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template/driver"
+)
+
+const (
+	selfLinkProp    = "SelfLink"
+	networkLinkProp = "NetworkLink"
+	subnetLinkProp  = "SubnetLink"
+)
+
+type fetchFunc func(api *client) ([]*graph.Resource, error)
+
+// service is a cloud service fetching GCP resources by type
+type service struct {
+	name     string
+	api      *client
+	types    []string
+	fetchers map[string]fetchFunc
+}
+
+func NewCompute(api *client) cloud.Service {
+	return &service{
+		name:     "compute",
+		api:      api,
+		types:    []string{graph.Vpc.String(), graph.Subnet.String(), graph.Instance.String()},
+		fetchers: map[string]fetchFunc{graph.Vpc.String(): fetchNetworks, graph.Subnet.String(): fetchSubnetworks, graph.Instance.String(): fetchInstances},
+	}
+}
+
+func NewStorage(api *client) cloud.Service {
+	return &service{
+		name:     "cloudstorage",
+		api:      api,
+		types:    []string{graph.Bucket.String()},
+		fetchers: map[string]fetchFunc{graph.Bucket.String(): fetchBuckets},
+	}
+}
+
+func NewAccess(api *client) cloud.Service {
+	return &service{
+		name:     "iam",
+		api:      api,
+		types:    []string{graph.User.String(), graph.Role.String()},
+		fetchers: map[string]fetchFunc{graph.User.String(): fetchServiceAccounts, graph.Role.String(): fetchRoles},
+	}
+}
+
+func (s *service) Name() string { return s.name }
+
+func (s *service) Drivers() []driver.Driver {
+	return []driver.Driver{NewDriver(s.api, s.types...)}
+}
+
+func (s *service) ResourceTypes() []string { return s.types }
+
+func (s *service) FetchResources() (*graph.Graph, error) {
+	var all []*graph.Resource
+	for _, t := range s.types {
+		resources, err := s.fetchers[t](s.api)
+		if err != nil {
+			return graph.NewGraph(), convertError(err)
+		}
+		all = append(all, resources...)
+	}
+	return buildGraph(all)
+}
+
+func (s *service) FetchByType(t string) (*graph.Graph, error) {
+	fetch, ok := s.fetchers[t]
+	if !ok {
+		return nil, fmt.Errorf("gcp %s: unsupported fetch for type %s", s.name, t)
+	}
+	resources, err := fetch(s.api)
+	if err != nil {
+		return graph.NewGraph(), convertError(err)
+	}
+	return buildGraph(resources)
+}
+
+// buildGraph adds the resources to a new graph, relating instances to their subnets
+// and subnets to their networks when fetched together
+func buildGraph(resources []*graph.Resource) (*graph.Graph, error) {
+	g := graph.NewGraph()
+	bySelfLink := make(map[string]*graph.Resource)
+	for _, res := range resources {
+		if link := stringProp(res, selfLinkProp); link != "" {
+			bySelfLink[link] = res
+		}
+	}
+
+	parents := make(map[*graph.Resource]*graph.Resource)
+	for _, res := range resources {
+		if parent, ok := bySelfLink[stringProp(res, subnetLinkProp)]; ok {
+			res.Properties["SubnetId"] = parent.Id()
+			if vpcId, ok := parent.Properties["VpcId"]; ok {
+				res.Properties["VpcId"] = vpcId
+			}
+			parents[res] = parent
+		} else if parent, ok := bySelfLink[stringProp(res, networkLinkProp)]; ok {
+			res.Properties["VpcId"] = parent.Id()
+			parents[res] = parent
+		}
+	}
+
+	if err := g.AddResource(resources...); err != nil {
+		return g, err
+	}
+	for _, res := range resources {
+		if parent, ok := parents[res]; ok {
+			if err := g.AddParentRelation(parent, res); err != nil {
+				return g, err
+			}
+		}
+	}
+	return g, nil
+}
+
+func convertError(err error) error {
+	if e, ok := err.(*httpError); ok && (e.status == http.StatusForbidden || e.status == http.StatusUnauthorized) {
+		return cloud.ErrFetchAccessDenied
+	}
+	return err
+}
+
+func stringProp(res *graph.Resource, key string) string {
+	s, _ := res.Properties[key].(string)
+	return s
+}
+
+// lastSegment returns the resource name ending a GCP URL (ex: zone or machine type)
+func lastSegment(link string) string {
+	return link[strings.LastIndex(link, "/")+1:]
+}
+
+// labelsToTags converts GCP labels into awless 'key=value' tags
+func labelsToTags(labels map[string]string) []string {
+	var tags []string
+	for k, v := range labels {
+		tags = append(tags, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/graph"
+)
+
+var apiResponses = map[string]string{
+	"/projects/myproj/global/networks": `{"items": [{"id": "11", "name": "default", "selfLink": "https://net/default"}]}`,
+	"/projects/myproj/aggregated/subnetworks": `{"items": {"regions/europe-west1": {"subnetworks": [
+		{"id": "22", "name": "sub-eu", "selfLink": "https://sub/eu", "network": "https://net/default", "ipCidrRange": "10.132.0.0/20", "region": "https://regions/europe-west1"}
+	]}, "regions/us-east1": {}}}`,
+	"/projects/myproj/aggregated/instances": `{"items": {"zones/europe-west1-b": {"instances": [
+		{"id": "33", "name": "web", "machineType": "https://zones/europe-west1-b/machineTypes/n1-standard-1", "status": "RUNNING", "zone": "https://zones/europe-west1-b",
+		 "creationTimestamp": "2017-03-01T10:00:00.000-08:00", "labels": {"env": "prod"},
+		 "networkInterfaces": [{"network": "https://net/default", "subnetwork": "https://sub/eu", "networkIP": "10.132.0.2", "accessConfigs": [{"natIP": "35.1.2.3"}]}]}
+	]}}}`,
+	"/b": `{"items": [{"id": "assets", "name": "assets", "location": "EU", "storageClass": "MULTI_REGIONAL"}]}`,
+}
+
+func newTestClient(t *testing.T) (*client, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Authorization"), "Bearer mytoken"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		if r.URL.Path == "/projects/myproj/global/networks" && r.URL.Query().Get("pageToken") == "" {
+			w.Write([]byte(`{"items": [{"id": "10", "name": "prod", "selfLink": "https://net/prod"}], "nextPageToken": "next"}`))
+			return
+		}
+		if r.URL.Path == "/projects/myproj/serviceAccounts" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": {"code": 403, "message": "permission denied"}}`))
+			return
+		}
+		body, ok := apiResponses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	api := newClient("myproj", "mytoken")
+	api.computeURL, api.storageURL, api.iamURL = server.URL, server.URL, server.URL
+	return api, server.Close
+}
+
+func TestFetchCompute(t *testing.T) {
+	api, closeFn := newTestClient(t)
+	defer closeFn()
+
+	g, err := NewCompute(api).FetchResources()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vpcs, _ := g.GetAllResources(graph.Vpc)
+	if got, want := len(vpcs), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	inst, err := g.GetResource(graph.Instance, "33")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"Name":             "web",
+		"Type":             "n1-standard-1",
+		"State":            "running",
+		"AvailabilityZone": "europe-west1-b",
+		"PrivateIp":        "10.132.0.2",
+		"PublicIp":         "35.1.2.3",
+		"SubnetId":         "22",
+		"VpcId":            "11",
+		"Tags":             []interface{}{"env=prod"},
+	}
+	for k, want := range expected {
+		if got := inst.Properties[k]; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %#v, want %#v", k, got, want)
+		}
+	}
+
+	var parents []*graph.Resource
+	if err = g.Accept(&graph.ParentsVisitor{From: inst, Each: graph.VisitorCollectFunc(&parents)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(parents), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := parents[0].Id(), "22"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := parents[1].Id(), "11"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestFetchStorageAndAccess(t *testing.T) {
+	api, closeFn := newTestClient(t)
+	defer closeFn()
+
+	g, err := NewStorage(api).FetchByType("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := g.GetResource(graph.Bucket, "assets")
+	if got, want := b.Properties["Location"], "EU"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if _, err = NewAccess(api).FetchResources(); err != cloud.ErrFetchAccessDenied {
+		t.Fatalf("got %v, want access denied", err)
+	}
+}
@@ -31,6 +31,8 @@ type driver struct {
 	Input, Output, ApiMethod, OutputExtractor string
 	DryRunUnsupported                         bool
 	ManualFuncDefinition                      bool
+	// IAM actions called by manual funcs. Otherwise deduced from the ApiMethod
+	Permissions []string
 }
 
 type driversDef struct {
@@ -96,7 +98,7 @@ var DriversDefs = []driversDef{
 				ExtraParams: []param{
 					{AwsField: "KeyName", TemplateName: "key", AwsType: "awsstr"},
 					{AwsField: "PrivateIpAddress", TemplateName: "ip", AwsType: "awsstr"},
-					{AwsField: "UserData", TemplateName: "userdata", AwsType: "awsstr"},
+					{AwsField: "UserData", TemplateName: "userdata", AwsType: "awsbase64str"},
 					{AwsField: "SecurityGroupIds", TemplateName: "group", AwsType: "awsstringslice"},
 					{AwsField: "DisableApiTermination", TemplateName: "lock", AwsType: "awsboolattribute"},
 				},
@@ -116,7 +118,8 @@ var DriversDefs = []driversDef{
 				},
 			},
 			{
-				Action: "delete", Entity: graph.Instance.String(), Input: "TerminateInstancesInput", Output: "TerminateInstancesOutput", ApiMethod: "TerminateInstances",
+				Action: "delete", Entity: graph.Instance.String(), ManualFuncDefinition: true,
+				Permissions: []string{"ec2:TerminateInstances"},
 				RequiredParams: []param{
 					{AwsField: "InstanceIds", TemplateName: "id", AwsType: "awsstringslice"},
 				},
@@ -135,10 +138,26 @@ var DriversDefs = []driversDef{
 			},
 			{
 				Action: "check", Entity: graph.Instance.String(), ManualFuncDefinition: true,
+				Permissions: []string{"ec2:DescribeInstances"},
 				RequiredParams: []param{
-					{TemplateName: "id"},
-					{TemplateName: "state"},
-					{TemplateName: "timeout"},
+					{TemplateName: "id", AwsType: "awsstr"},
+					{TemplateName: "state", AwsType: "awsstr"},
+					{TemplateName: "timeout", AwsType: "awsint64"},
+				},
+			},
+			{
+				Action: "update", Entity: "instances", ManualFuncDefinition: true,
+				Permissions: []string{"ec2:DescribeInstances", "ec2:DescribeInstanceAttribute", "ec2:RunInstances", "ec2:CreateTags", "ec2:DescribeInstanceStatus", "ec2:TerminateInstances", "iam:PassRole"},
+				RequiredParams: []param{
+					{TemplateName: "name", AwsType: "awsstr"},
+					{TemplateName: "image", AwsType: "awsstr"},
+				},
+				ExtraParams: []param{
+					{TemplateName: "type", AwsType: "awsstr"},
+					{TemplateName: "userdata", AwsType: "awsbase64str"},
+					{TemplateName: "batch", AwsType: "awsint"},
+					{TemplateName: "healthcheck", AwsType: "awsstr"},
+					{TemplateName: "timeout", AwsType: "awsint64"},
 				},
 			},
 
@@ -153,15 +172,16 @@ var DriversDefs = []driversDef{
 			},
 			{
 				Action: "update", Entity: graph.SecurityGroup.String(), ManualFuncDefinition: true,
+				Permissions: []string{"ec2:AuthorizeSecurityGroupIngress", "ec2:RevokeSecurityGroupIngress", "ec2:AuthorizeSecurityGroupEgress", "ec2:RevokeSecurityGroupEgress"},
 				RequiredParams: []param{
-					{TemplateName: "id"},
-					{TemplateName: "cidr"},
-					{TemplateName: "protocol"},
+					{TemplateName: "id", AwsType: "awsstr"},
+					{TemplateName: "cidr", AwsType: "awsstr"},
+					{TemplateName: "protocol", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{TemplateName: "inbound"}, // either inbound or outbound = either authorize or revoke
-					{TemplateName: "outbound"},
-					{TemplateName: "portrange"},
+					{TemplateName: "inbound", AwsType: "awsstr"}, // either inbound or outbound = either authorize or revoke
+					{TemplateName: "outbound", AwsType: "awsstr"},
+					{TemplateName: "portrange", AwsType: "awsstr"},
 				},
 			},
 			{
@@ -261,18 +281,23 @@ var DriversDefs = []driversDef{
 			// TAG
 			{
 				Action: "create", Entity: "tag", ManualFuncDefinition: true,
+				Permissions: []string{"ec2:CreateTags"},
 				RequiredParams: []param{
-					{TemplateName: "resource"},
-					{TemplateName: "key"},
-					{TemplateName: "value"},
+					{TemplateName: "resource", AwsType: "awsstr"},
+				},
+				ExtraParams: []param{
+					{TemplateName: "key", AwsType: "awsstr"},
+					{TemplateName: "value", AwsType: "awsstr"},
+					{TemplateName: "tags", AwsType: "awsstringmap"}, // several tags at once. Ex: tags={Name:web,Env:prod}
 				},
 			},
 
 			// Keypair
 			{
 				Action: "create", Entity: graph.Keypair.String(), ManualFuncDefinition: true,
+				Permissions: []string{"ec2:ImportKeyPair"},
 				RequiredParams: []param{
-					{TemplateName: "name"},
+					{TemplateName: "name", AwsType: "awsstr"},
 				},
 			},
 			{
@@ -342,22 +367,24 @@ var DriversDefs = []driversDef{
 			// POLICY
 			{
 				Action: "attach", Entity: graph.Policy.String(), ManualFuncDefinition: true,
+				Permissions: []string{"iam:AttachUserPolicy", "iam:AttachGroupPolicy"},
 				RequiredParams: []param{
-					{TemplateName: "arn"},
+					{TemplateName: "arn", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{TemplateName: "user"},
-					{TemplateName: "group"},
+					{TemplateName: "user", AwsType: "awsstr"},
+					{TemplateName: "group", AwsType: "awsstr"},
 				},
 			},
 			{
 				Action: "detach", Entity: graph.Policy.String(), ManualFuncDefinition: true,
+				Permissions: []string{"iam:DetachUserPolicy", "iam:DetachGroupPolicy"},
 				RequiredParams: []param{
-					{TemplateName: "arn"},
+					{TemplateName: "arn", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{TemplateName: "user"},
-					{TemplateName: "group"},
+					{TemplateName: "user", AwsType: "awsstr"},
+					{TemplateName: "group", AwsType: "awsstr"},
 				},
 			},
 		},
@@ -382,6 +409,7 @@ var DriversDefs = []driversDef{
 			// OBJECT
 			{
 				Action: "create", Entity: graph.Object.String(), ManualFuncDefinition: true,
+				Permissions: []string{"s3:PutObject"},
 				RequiredParams: []param{
 					{AwsField: "Bucket", TemplateName: "bucket", AwsType: "awsstr"},
 					{AwsField: "Body", TemplateName: "file", AwsType: "awsstr"},
@@ -391,7 +419,8 @@ var DriversDefs = []driversDef{
 				},
 			},
 			{
-				Action: "delete", Entity: graph.Object.String(), DryRunUnsupported: true, Input: "DeleteObjectInput", Output: "DeleteObjectOutput", ApiMethod: "DeleteObject",
+				Action: "delete", Entity: graph.Object.String(), ManualFuncDefinition: true,
+				Permissions: []string{"s3:DeleteObject", "s3:ListBucket"},
 				RequiredParams: []param{
 					{AwsField: "Bucket", TemplateName: "bucket", AwsType: "awsstr"},
 					{AwsField: "Key", TemplateName: "key", AwsType: "awsstr"},
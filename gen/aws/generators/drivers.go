@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"io/ioutil"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"text/template"
 
@@ -27,7 +28,9 @@ import (
 )
 
 func generateTemplateTemplates() {
-	templ, err := template.New("templates_definitions").Parse(templateDefinitions)
+	templ, err := template.New("templates_definitions").Funcs(template.FuncMap{
+		"ParamTypes": paramTypes,
+	}).Parse(templateDefinitions)
 	if err != nil {
 		panic(err)
 	}
@@ -43,6 +46,35 @@ func generateTemplateTemplates() {
 	}
 }
 
+// paramTypes returns the kind of value accepted by each template param of a driver
+func paramTypes(def interface{}) map[string]string {
+	types := make(map[string]string)
+	v := reflect.ValueOf(def)
+	for _, field := range []string{"RequiredParams", "ExtraParams"} {
+		params := v.FieldByName(field)
+		for i := 0; i < params.Len(); i++ {
+			p := params.Index(i)
+			types[p.FieldByName("TemplateName").String()] = templateParamType(p.FieldByName("AwsType").String())
+		}
+	}
+	return types
+}
+
+func templateParamType(awsType string) string {
+	switch awsType {
+	case "awsint", "awsint64", "awsint64slice":
+		return "integer"
+	case "awsbool", "awsboolattribute":
+		return "boolean"
+	case "awsbase64str":
+		return "text"
+	case "awsstringmap":
+		return "map"
+	default:
+		return "string"
+	}
+}
+
 func generateDriverFuncs() {
 	templ, err := template.New("funcs").Funcs(template.FuncMap{
 		"Title": strings.Title,
@@ -82,6 +114,35 @@ func generateDriverTypes() {
 	}
 }
 
+func generateDriverPermissions() {
+	templ, err := template.New("permissions").Funcs(template.FuncMap{
+		"IAMPrefix": iamPrefix,
+	}).Parse(permissionsTempl)
+	if err != nil {
+		panic(err)
+	}
+
+	var buff bytes.Buffer
+	err = templ.Execute(&buff, aws.DriversDefs)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(DRIVERS_DIR, "gen_permissions.go"), buff.Bytes(), 0666); err != nil {
+		panic(err)
+	}
+}
+
+// iamPrefix returns the IAM service prefix of an API (ex: elbv2 -> elasticloadbalancing)
+func iamPrefix(api string) string {
+	switch api {
+	case "elbv2":
+		return "elasticloadbalancing"
+	default:
+		return api
+	}
+}
+
 const templateDefinitions = `/* Copyright 2017 WALLIX
 
 Licensed under the Apache License, Version 2.0 (the "License");
@@ -115,6 +176,7 @@ var AWSTemplatesDefinitions = map[string]template.TemplateDefinition{
 			RequiredParams: []string{ {{- range $awsField, $field := $def.RequiredParams }}"{{ $field.TemplateName }}", {{- end}} },
 			ExtraParams: []string{ {{- range $awsField, $field := $def.ExtraParams }}"{{ $field.TemplateName }}", {{- end}} },
 			TagsMapping: []string{ {{- range $awsField, $field := $def.TagsMapping }}"{{ $field }}", {{- end}} },
+			ParamTypes: map[string]string{ {{- range $param, $type := ParamTypes $def }}"{{ $param }}": "{{ $type }}", {{- end}} },
 		},
 {{- end }}
 {{- end }}
@@ -358,3 +420,37 @@ case "{{ $def.Action}}{{ $def.Entity}}":
 }
 
 {{ end }}`
+
+const permissionsTempl = `/* Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// DO NOT EDIT
+// This file was automatically generated with go generate
+package aws
+
+// DriverPermissions lists the IAM actions called by each driver function, indexed by action and entity
+var DriverPermissions = map[string][]string{
+{{- range $, $service := . }}
+{{- range $index, $def := $service.Drivers }}
+	"{{ $def.Action }}{{ $def.Entity }}": []string{
+	{{- if $def.ManualFuncDefinition }}
+		{{- range $perm := $def.Permissions }}"{{ $perm }}", {{- end }}
+	{{- else }}"{{ IAMPrefix $service.Api }}:{{ $def.ApiMethod }}",
+		{{- if gt (len $def.TagsMapping) 0 }} "ec2:CreateTags",{{- end }}
+	{{- end }}},
+{{- end }}
+{{- end }}
+}
+`
@@ -163,10 +163,7 @@ func (s *{{ Title $service.Name }}) FetchResources() (*graph.Graph, error) {
 	g := graph.NewGraph()
 	regionN := graph.InitResource(s.region, graph.Region)
 	g.AddResource(regionN)
-
-	{{- range $index, $fetcher := $service.Fetchers }}
-  var {{ $fetcher.ResourceType }}List []*{{ $fetcher.AWSType }}
-  {{- end }}
+	deferred := &deferredRelations{}
 
 	errc := make(chan error)
 	var wg sync.WaitGroup
@@ -175,14 +172,9 @@ func (s *{{ Title $service.Name }}) FetchResources() (*graph.Graph, error) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		var resGraph *graph.Graph
-		var err error
-		resGraph, {{ $fetcher.ResourceType }}List, err = s.fetch_all_{{ $fetcher.ResourceType }}_graph()
-		if err != nil {
+		if err := s.fetch_all_{{ $fetcher.ResourceType }}_graph(g, streamRelations(g, "{{ $fetcher.ResourceType }}", deferred)); err != nil {
 			errc <- err
-			return
 		}
-		g.AddGraph(resGraph)
 	}()
   {{- end }}
 
@@ -207,54 +199,28 @@ func (s *{{ Title $service.Name }}) FetchResources() (*graph.Graph, error) {
 		}
 	}
 
-	errc = make(chan error)
-	{{- range $index, $fetcher := $service.Fetchers }}
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for _, r := range {{ $fetcher.ResourceType }}List {
-			for _, fn := range addParentsFns["{{ $fetcher.ResourceType }}"] {
-				err := fn(g, r)
-				if err != nil {
-					errc <- err
-					return
-				}
-			}
-		}
-	}()
-  {{- end }}
-
-	go func() {
-		wg.Wait()
-		close(errc)
-	}()
-
-	for err := range errc {
-		if err != nil {
-			return g, err
-		}
-	}
-
-	return g, nil
+	return g, deferred.apply(g)
 }
 
 func (s *{{ Title $service.Name }}) FetchByType(t string) (*graph.Graph, error) {
+	g := graph.NewGraph()
+	return g, s.fetchByType(g, t, nil)
+}
+
+func (s *{{ Title $service.Name }}) fetchByType(g *graph.Graph, t string, each func(interface{}) error) error {
   switch t {
   {{- range $index, $fetcher := $service.Fetchers }}
   case "{{ $fetcher.ResourceType }}":
-		graph, _, err := s.fetch_all_{{ $fetcher.ResourceType }}_graph()
-    return graph, err
+    return s.fetch_all_{{ $fetcher.ResourceType }}_graph(g, each)
   {{- end }}
   default:
-    return nil, fmt.Errorf("aws {{ $service.Name }}: unsupported fetch for type %s", t)
+    return fmt.Errorf("aws {{ $service.Name }}: unsupported fetch for type %s", t)
   }
 }
 
 {{ range $index, $fetcher := $service.Fetchers }}
 {{- if not $fetcher.ManualFetcher }}
-func (s *{{ Title $service.Name }}) fetch_all_{{ $fetcher.ResourceType }}_graph() (*graph.Graph, []*{{ $fetcher.AWSType }}, error) {
-  g := graph.NewGraph()
-	var cloudResources []*{{ $fetcher.AWSType }}
+func (s *{{ Title $service.Name }}) fetch_all_{{ $fetcher.ResourceType }}_graph(g *graph.Graph, each func(interface{}) error) error {
 	{{- if $fetcher.Multipage }}
 	var badResErr error
 	err := s.{{ $fetcher.ApiMethod }}(&{{ $fetcher.Input }},
@@ -262,60 +228,46 @@ func (s *{{ Title $service.Name }}) fetch_all_{{ $fetcher.ResourceType }}_graph(
 			{{- if ne $fetcher.OutputsContainers "" }}
 			for _, all := range out.{{ $fetcher.OutputsContainers }} {
 	      for _, output := range all.{{ $fetcher.OutputsExtractor }} {
-					cloudResources = append(cloudResources, output)
-					var res *graph.Resource
-					res, badResErr = newResource(output)
-					if badResErr != nil {
+					if badResErr = addFetchedResource(g, output, each); badResErr != nil {
 						return false
 					}
-	        g.AddResource(res)
 	      }
 	    }
 			{{- else }}
 			for _, output := range out.{{ $fetcher.OutputsExtractor }} {
-				cloudResources = append(cloudResources, output)
-				var res *graph.Resource
-				res, badResErr = newResource(output)
-				if badResErr != nil {
+				if badResErr = addFetchedResource(g, output, each); badResErr != nil {
 					return false
 				}
-				g.AddResource(res)
 			}
 			{{- end }}
 			return out.{{ $fetcher.NextPageMarker }} != nil
 		})
 	if err != nil {
-		return g, cloudResources, err
+		return err
 	}
 
-	return g, cloudResources, badResErr
+	return badResErr
 	{{- else }}
   out, err := s.{{ $fetcher.ApiMethod }}(&{{ $fetcher.Input }})
   if err != nil {
-    return nil, cloudResources, err
+    return err
   }
   	{{ if ne $fetcher.OutputsContainers "" }}
     for _, all := range out.{{ $fetcher.OutputsContainers }} {
       for _, output := range all.{{ $fetcher.OutputsExtractor }} {
-				cloudResources = append(cloudResources, output)
-        res, err := newResource(output)
-        if err != nil {
-          return g, cloudResources, err
+        if err := addFetchedResource(g, output, each); err != nil {
+          return err
         }
-        g.AddResource(res)
       }
     }
   	{{ else }}
     for _, output := range out.{{ $fetcher.OutputsExtractor }} {
-			cloudResources = append(cloudResources, output)
-      res, err := newResource(output)
-      if err != nil {
-        return g, cloudResources, err
+      if err := addFetchedResource(g, output, each); err != nil {
+        return err
       }
-      g.AddResource(res)
     }
   	{{ end }}
-  return g, cloudResources, nil
+  return nil
 	{{ end }}
 }
 {{- end }}
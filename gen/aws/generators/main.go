@@ -23,4 +23,5 @@ func main() {
 	generateDriverFuncs()
 	generateTemplateTemplates()
 	generateDriverTypes()
+	generateDriverPermissions()
 }
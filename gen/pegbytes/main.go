@@ -0,0 +1,135 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command pegbytes adapts the parser generated by peg (github.com/pointlander/peg v1.0.1) to scan
+// the UTF-8 bytes of the buffer instead of a copy of it converted to runes, and to start
+// with a token storage sized for one-liner templates.
+//
+// Usage: pegbytes FILE.peg.go
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+)
+
+type rewrite struct {
+	old, new string
+	count    int // expected number of occurrences of old in the peg output
+}
+
+var rewrites = []rewrite{
+	{old: "DO NOT EDIT.\n", new: "DO NOT EDIT.\n// Adapted to scan UTF-8 bytes by gen/pegbytes.\n", count: 1},
+	{
+		old: "const endSymbol rune = 1114112\n",
+		new: `// endSymbol marks the end of the buffer, parsed as UTF-8 bytes.
+// 0xff never appears in valid UTF-8
+const endSymbol byte = 0xff
+
+// initialTokens is the initial capacity of the token storage, enough for one-liner templates
+const initialTokens = 64
+`,
+		count: 1,
+	},
+	{old: "\"strings\"\n)", new: "\"strings\"\n\t\"unicode/utf8\"\n)", count: 1},
+	{old: "string(([]rune(buffer)[node.begin:node.end]))", new: "buffer[node.begin:node.end]", count: 1},
+	{old: "\tbuffer []rune\n", new: "\tbuffer string\n", count: 1},
+	{old: "\tbuffer               []rune\n", new: "\tbuffer               string\n", count: 1},
+	{old: "func translatePositions(buffer []rune,", new: "func translatePositions(buffer string,", count: 1},
+	{old: "string(e.p.buffer[begin:end])", new: "e.p.buffer[begin:end]", count: 1},
+	{old: "text = string(_buffer[begin:end])", new: "text = _buffer[begin:end]", count: 1},
+	{
+		old: `		p.buffer = []rune(p.Buffer)
+		if len(p.buffer) == 0 || p.buffer[len(p.buffer)-1] != endSymbol {
+			p.buffer = append(p.buffer, endSymbol)
+		}
+`,
+		new: `		p.buffer = p.Buffer
+		if strings.IndexByte(p.buffer, endSymbol) >= 0 {
+			p.buffer = strings.ToValidUTF8(p.buffer, string(utf8.RuneError))
+		}
+		p.buffer += string([]byte{endSymbol})
+`,
+		count: 1,
+	},
+	{
+		old: "\ttree := p.tokens32\n",
+		new: `	tree := p.tokens32
+	if tree.tree == nil {
+		tree.tree = make([]token32, 0, initialTokens)
+	}
+`,
+		count: 1,
+	},
+	{
+		old: `		if buffer[position] != endSymbol {
+			position++
+			return true
+		}
+`,
+		new: `		if buffer[position] != endSymbol {
+			_, size := utf8.DecodeRuneInString(buffer[position:])
+			position += uint32(size)
+			return true
+		}
+`,
+		count: 1,
+	},
+}
+
+// asciiRuneRegex matches the conversions of the ASCII characters of the grammar compared to the buffer
+var asciiRuneRegex = regexp.MustCompile(`rune\(('(?:\\.|[ -~])')\)`)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: pegbytes FILE.peg.go")
+		os.Exit(2)
+	}
+	if err := adapt(os.Args[1]); err != nil {
+		fmt.Fprintf(os.Stderr, "pegbytes: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func adapt(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	src := string(b)
+	if strings.Contains(src, "const endSymbol byte") {
+		return fmt.Errorf("%s: already adapted", path)
+	}
+	for _, r := range rewrites {
+		if n := strings.Count(src, r.old); n != r.count {
+			return fmt.Errorf("%s: found %d occurrences of %q, expected %d: unsupported peg version?", path, n, r.old, r.count)
+		}
+		src = strings.Replace(src, r.old, r.new, -1)
+	}
+	src = asciiRuneRegex.ReplaceAllString(src, "$1")
+	if strings.Contains(src, "rune(") {
+		return fmt.Errorf("%s: rune conversion left after rewrite: non ASCII grammar or unsupported peg version?", path)
+	}
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, formatted, 0644)
+}
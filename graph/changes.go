@@ -0,0 +1,92 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// PropertyChange is the change of a property between two states of a resource.
+// Added properties have a nil Old value and removed ones a nil New value.
+// Tags are compared one by one, as 'Tags.<key>' properties
+type PropertyChange struct {
+	Name string      `json:"name"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+func (c PropertyChange) IsAdded() bool   { return c.Old == nil && c.New != nil }
+func (c PropertyChange) IsRemoved() bool { return c.Old != nil && c.New == nil }
+
+// PropertiesDiff returns the changes from the old to the new properties, sorted by name
+func PropertiesDiff(old, new Properties) []PropertyChange {
+	old, new = expandTags(old), expandTags(new)
+
+	names := make(map[string]bool)
+	for k := range old {
+		names[k] = true
+	}
+	for k := range new {
+		names[k] = true
+	}
+
+	var changes []PropertyChange
+	for k := range names {
+		o, n := old[k], new[k]
+		if !reflect.DeepEqual(o, n) {
+			changes = append(changes, PropertyChange{Name: k, Old: o, New: n})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// expandTags returns a copy of the properties with the 'key=value' tags
+// as 'Tags.key' properties, so that each tag is diffed on its own
+func expandTags(props Properties) Properties {
+	expanded := make(Properties)
+	for k, v := range props {
+		if k != "Tags" {
+			expanded[k] = v
+			continue
+		}
+		var tags []string
+		switch vv := v.(type) {
+		case []string:
+			tags = vv
+		case []interface{}:
+			for _, t := range vv {
+				tags = append(tags, fmt.Sprint(t))
+			}
+		default:
+			expanded[k] = v
+			continue
+		}
+		for _, t := range tags {
+			kv := strings.SplitN(t, "=", 2)
+			if len(kv) == 2 {
+				expanded["Tags."+kv[0]] = kv[1]
+			} else {
+				expanded["Tags."+kv[0]] = ""
+			}
+		}
+	}
+	return expanded
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropertiesDiff(t *testing.T) {
+	old := Properties{"Id": "inst_1", "Type": "t2.micro", "State": "running", "Tags": []interface{}{"Env=dev", "Team=ops"}}
+	new := Properties{"Id": "inst_1", "Type": "t2.large", "PublicIp": "1.2.3.4", "Tags": []string{"Env=prod", "Owner=bob"}}
+
+	expected := []PropertyChange{
+		{Name: "PublicIp", New: "1.2.3.4"},
+		{Name: "State", Old: "running"},
+		{Name: "Tags.Env", Old: "dev", New: "prod"},
+		{Name: "Tags.Owner", New: "bob"},
+		{Name: "Tags.Team", Old: "ops"},
+		{Name: "Type", Old: "t2.micro", New: "t2.large"},
+	}
+	if got, want := PropertiesDiff(old, new), expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+	if got, want := len(PropertiesDiff(old, old)), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
@@ -3,6 +3,7 @@ package graph
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 type FilterFn func(*Resource) bool
@@ -24,12 +25,90 @@ func (g *Graph) Filter(entity ResourceType, filters ...FilterFn) (*Graph, error)
 	return filtered, nil
 }
 
+// FilterByTags is Filter on the resources having all the given 'key=value' (or 'key') tags.
+// Candidates are found with the tag index, so that other resources of the type are not loaded
+func (g *Graph) FilterByTags(entity ResourceType, tags []string, filters ...FilterFn) (*Graph, error) {
+	if len(tags) == 0 {
+		return g.Filter(entity, filters...)
+	}
+	filtered := NewGraph()
+
+	var candidates map[string]bool
+	for _, tag := range tags {
+		tagged := make(map[string]bool)
+		nodes := g.rdfG.NodesForTag(tag)
+		if strings.HasPrefix(tag, "Name=") {
+			nodes = append(nodes, g.rdfG.NodesForName(strings.TrimPrefix(tag, "Name="))...)
+		}
+		for _, n := range nodes {
+			if newResourceType(n) == entity && (candidates == nil || candidates[n.ID().String()]) {
+				tagged[n.ID().String()] = true
+			}
+		}
+		candidates = tagged
+	}
+
+	for id := range candidates {
+		r, err := g.GetResource(entity, id)
+		if err != nil {
+			return filtered, err
+		}
+		if apply(filters...)(r) {
+			filtered.AddResource(r)
+		}
+	}
+
+	return filtered, nil
+}
+
 func BuildPropertyFilterFunc(key, val string) FilterFn {
 	return func(r *Resource) bool {
 		return strings.Contains(strings.ToLower(fmt.Sprint(r.Properties[key])), strings.ToLower(val))
 	}
 }
 
+// BuildTagFilterFunc keeps resources having exactly the given tag (Name tag included)
+func BuildTagFilterFunc(key, val string) FilterFn {
+	return func(r *Resource) bool {
+		if key == "Name" && fmt.Sprint(r.Properties["Name"]) == val {
+			return true
+		}
+		var tags []string
+		switch v := r.Properties["Tags"].(type) {
+		case []string:
+			tags = v
+		case []interface{}:
+			for _, t := range v {
+				tags = append(tags, fmt.Sprint(t))
+			}
+		}
+		for _, tag := range tags {
+			if tag == key+"="+val {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+var creationProperties = []string{"LaunchTime", "CreateTime", "CreateDate"}
+
+// BuildOlderThanFilterFunc keeps resources created more than the given duration before now.
+// Resources without creation date are filtered out
+func BuildOlderThanFilterFunc(d time.Duration, now time.Time) FilterFn {
+	return func(r *Resource) bool {
+		for _, key := range creationProperties {
+			switch created := r.Properties[key].(type) {
+			case time.Time:
+				return now.Sub(created) > d
+			case *time.Time:
+				return created != nil && now.Sub(*created) > d
+			}
+		}
+		return false
+	}
+}
+
 func apply(filters ...FilterFn) FilterFn {
 	return func(r *Resource) bool {
 		include := true
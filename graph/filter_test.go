@@ -1,6 +1,11 @@
 package graph
 
-import "testing"
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
 
 func TestFilterGraph(t *testing.T) {
 	g := NewGraph()
@@ -91,3 +96,79 @@ func TestFilterGraph(t *testing.T) {
 		t.Fatalf("got %d, want %d", got, want)
 	}
 }
+
+func TestTagAndAgeFilters(t *testing.T) {
+	now := time.Now()
+	inst1 := InitResource("inst_1", Instance)
+	inst1.Properties["Name"] = "sandbox-web"
+	inst1.Properties["Tags"] = []interface{}{"Name=sandbox-web", "env=sandbox"}
+	inst1.Properties["LaunchTime"] = now.Add(-48 * time.Hour)
+	inst2 := InitResource("inst_2", Instance)
+	inst2.Properties["Tags"] = []string{"env=sandbox-2"}
+	inst2.Properties["LaunchTime"] = now.Add(-1 * time.Hour)
+	vol := InitResource("vol_1", Volume)
+
+	tcases := []struct {
+		filter FilterFn
+		res    *Resource
+		expect bool
+	}{
+		{BuildTagFilterFunc("env", "sandbox"), inst1, true},
+		{BuildTagFilterFunc("env", "sandbox"), inst2, false},
+		{BuildTagFilterFunc("Name", "sandbox-web"), inst1, true},
+		{BuildTagFilterFunc("env", "sandbox"), vol, false},
+		{BuildOlderThanFilterFunc(24*time.Hour, now), inst1, true},
+		{BuildOlderThanFilterFunc(24*time.Hour, now), inst2, false},
+		{BuildOlderThanFilterFunc(24*time.Hour, now), vol, false},
+	}
+	for i, tcase := range tcases {
+		if got, want := tcase.filter(tcase.res), tcase.expect; got != want {
+			t.Fatalf("%d: got %t, want %t", i+1, got, want)
+		}
+	}
+}
+
+func TestFilterByTags(t *testing.T) {
+	g := NewGraph()
+	inst1 := InitResource("inst_1", Instance)
+	inst1.Properties["Type"] = "t2.micro"
+	inst1.Properties["Tags"] = []string{"Env=prod", "Team=web"}
+	inst2 := InitResource("inst_2", Instance)
+	inst2.Properties["Type"] = "t2.large"
+	inst2.Properties["Tags"] = []string{"Env=prod"}
+	inst3 := InitResource("inst_3", Instance)
+	inst3.Properties["Type"] = "t2.micro"
+	inst3.Properties["Name"] = "bastion"
+	g.AddResource(inst1, inst2, inst3)
+
+	tcases := []struct {
+		tags    []string
+		filters []FilterFn
+		expect  []string
+	}{
+		{[]string{"Env=prod"}, nil, []string{"inst_1", "inst_2"}},
+		{[]string{"Env=prod", "Team"}, nil, []string{"inst_1"}},
+		{[]string{"Env=prod"}, []FilterFn{BuildPropertyFilterFunc("Type", "large")}, []string{"inst_2"}},
+		{[]string{"Env=test"}, nil, nil},
+		{[]string{"Name=bastion"}, nil, []string{"inst_3"}},
+		{nil, []FilterFn{BuildPropertyFilterFunc("Type", "micro")}, []string{"inst_1", "inst_3"}},
+	}
+	for i, tcase := range tcases {
+		filtered, err := g.FilterByTags(Instance, tcase.tags, tcase.filters...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := filtered.GetAllResources(Instance)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Sort(ResourceById(res))
+		var ids []string
+		for _, r := range res {
+			ids = append(ids, r.Id())
+		}
+		if got, want := ids, tcase.expect; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %v, want %v", i+1, got, want)
+		}
+	}
+}
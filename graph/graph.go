@@ -87,22 +87,137 @@ func (g *Graph) GetResource(t ResourceType, id string) (*Resource, error) {
 	return resource, nil
 }
 
-func (g *Graph) FindResource(id string) (*Resource, error) {
-	triples, err := g.rdfG.TriplesForGivenPredicate(rdf.HasTypePredicate)
+// SetResourceProperty replaces the value of a property of a resource in the graph.
+// A nil value removes the property
+func (g *Graph) SetResourceProperty(res *Resource, key string, value interface{}) error {
+	n, err := res.toRDFNode()
+	if err != nil {
+		return err
+	}
+	triples, err := g.rdfG.TriplesForSubjectPredicate(n, rdf.PropertyPredicate)
+	if err != nil {
+		return err
+	}
+	for _, t := range triples {
+		var prop Property
+		if err = prop.unmarshalRDF(t); err != nil {
+			return err
+		}
+		if prop.Key == key {
+			g.rdfG.Remove(t)
+		}
+	}
+
+	if value == nil {
+		delete(res.Properties, key)
+		return nil
+	}
+	prop := Property{Key: key, Value: value}
+	propL, err := prop.marshalRDF()
+	if err != nil {
+		return err
+	}
+	t, err := triple.New(n, rdf.PropertyPredicate, propL)
+	if err != nil {
+		return err
+	}
+	g.rdfG.Add(t)
+	res.Properties[key] = value
+	return nil
+}
+
+// RemoveResource removes a resource from the graph, with its properties and all its relations
+func (g *Graph) RemoveResource(res *Resource) error {
+	n, err := res.toRDFNode()
+	if err != nil {
+		return err
+	}
+	subTriples, err := g.rdfG.TriplesForSubject(n)
+	if err != nil {
+		return err
+	}
+	objTriples, err := g.rdfG.TriplesForObject(triple.NewNodeObject(n))
+	if err != nil {
+		return err
+	}
+	g.rdfG.Remove(append(subTriples, objTriples...)...)
+	return nil
+}
+
+// ClearResource removes the properties of a resource and its relations with resources of the
+// given types, keeping the resource and its other relations
+func (g *Graph) ClearResource(res *Resource, related ...ResourceType) error {
+	n, err := res.toRDFNode()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	isRelated := func(other *node.Node) bool {
+		for _, t := range related {
+			if newResourceType(other) == t {
+				return true
+			}
+		}
+		return false
 	}
 
-	for _, triple := range triples {
-		sub := triple.Subject()
-		if sub.ID().String() == id {
-			return g.GetResource(newResourceType(sub), sub.ID().String())
+	var toRemove []*triple.Triple
+	subTriples, err := g.rdfG.TriplesForSubject(n)
+	if err != nil {
+		return err
+	}
+	for _, t := range subTriples {
+		switch t.Predicate().ID() {
+		case rdf.HasTypePredicate.ID():
+		case rdf.ParentOfPredicate.ID(), rdf.AppliesOnPredicate.ID():
+			if other, err := t.Object().Node(); err == nil && isRelated(other) {
+				toRemove = append(toRemove, t)
+			}
+		default:
+			toRemove = append(toRemove, t)
+		}
+	}
+	objTriples, err := g.rdfG.TriplesForObject(triple.NewNodeObject(n))
+	if err != nil {
+		return err
+	}
+	for _, t := range objTriples {
+		if isRelated(t.Subject()) {
+			toRemove = append(toRemove, t)
 		}
 	}
+	g.rdfG.Remove(toRemove...)
+	return nil
+}
 
+func (g *Graph) FindResource(id string) (*Resource, error) {
+	for _, n := range g.rdfG.NodesForID(id) {
+		return g.GetResource(newResourceType(n), id)
+	}
 	return nil, nil
 }
 
+// FindResourcesByTag returns the resources of the given type (or of any type if empty)
+// tagged with key=value, or with the key whatever its value if the value is empty
+func (g *Graph) FindResourcesByTag(t ResourceType, key, value string) ([]*Resource, error) {
+	var res []*Resource
+	tag := key
+	if value != "" {
+		tag = key + "=" + value
+	}
+	for _, n := range g.rdfG.NodesForTag(tag) {
+		resT := newResourceType(n)
+		if t != "" && resT != t {
+			continue
+		}
+		r, err := g.GetResource(resT, n.ID().String())
+		if err != nil {
+			return res, err
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
 func (g *Graph) FindResourcesByProperty(key string, value interface{}) ([]*Resource, error) {
 	var res []*Resource
 	prop := Property{Key: key, Value: value}
@@ -192,6 +307,7 @@ func (g *Graph) Accept(v Visitor) error {
 	return v.Visit(g)
 }
 
+// Unmarshal loads text triples or a binary snapshot (see MarshalSnapshot)
 func (g *Graph) Unmarshal(data []byte) error {
 	return g.rdfG.Unmarshal(data)
 }
@@ -204,6 +320,12 @@ func (g *Graph) Marshal() ([]byte, error) {
 	return g.rdfG.Marshal()
 }
 
+// MarshalSnapshot encodes the graph in a compact binary format, faster to load than text triples.
+// Text triples (see Marshal) remain the format to exchange graphs with other tools
+func (g *Graph) MarshalSnapshot() ([]byte, error) {
+	return g.rdfG.MarshalSnapshot()
+}
+
 func (g *Graph) addRelation(one, other *Resource, pred *predicate.Predicate) error {
 	n, err := other.toRDFNode()
 	if err != nil {
@@ -266,3 +266,97 @@ func TestLoadIpPermissions(t *testing.T) {
 		}
 	}
 }
+
+func TestSetResourceProperty(t *testing.T) {
+	g := NewGraph()
+	g.Unmarshal([]byte(`/instance<inst_1>  "has_type"@[] "/instance"^^type:text
+  /instance<inst_1>  "property"@[] "{"Key":"Id","Value":"inst_1"}"^^type:text
+  /instance<inst_1>  "property"@[] "{"Key":"Name","Value":"redis"}"^^type:text`))
+
+	res, err := g.GetResource(Instance, "inst_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = g.SetResourceProperty(res, "Name", "mysql"); err != nil {
+		t.Fatal(err)
+	}
+	if err = g.SetResourceProperty(res, "Tags", []string{"env=prod"}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err = g.GetResource(Instance, "inst_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Properties{"Id": "inst_1", "Name": "mysql", "Tags": []interface{}{"env=prod"}}
+	if got, want := res.Properties, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	if err = g.SetResourceProperty(res, "Name", nil); err != nil {
+		t.Fatal(err)
+	}
+	res, _ = g.GetResource(Instance, "inst_1")
+	if _, ok := res.Properties["Name"]; ok {
+		t.Fatalf("expected Name property to be removed, got %#v", res.Properties)
+	}
+}
+
+func TestFindResourcesByTag(t *testing.T) {
+	g := NewGraph()
+	inst1 := InitResource("inst_1", Instance)
+	inst1.Properties["Tags"] = []string{"Env=prod", "Team=web"}
+	inst2 := InitResource("inst_2", Instance)
+	inst2.Properties["Tags"] = []string{"Env=test"}
+	vol := InitResource("vol_1", Volume)
+	vol.Properties["Tags"] = []string{"Env=prod"}
+	g.AddResource(inst1, inst2, vol, inst1)
+
+	tcases := []struct {
+		resType    ResourceType
+		key, value string
+		expect     []string
+	}{
+		{Instance, "Env", "prod", []string{"inst_1"}},
+		{"", "Env", "prod", []string{"inst_1", "vol_1"}},
+		{Instance, "Env", "", []string{"inst_1", "inst_2"}},
+		{Instance, "Team", "test", nil},
+		{Volume, "Team", "", nil},
+	}
+	for i, tcase := range tcases {
+		res, err := g.FindResourcesByTag(tcase.resType, tcase.key, tcase.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, r := range res {
+			ids = append(ids, r.Id())
+		}
+		if got, want := ids, tcase.expect; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %v, want %v", i+1, got, want)
+		}
+	}
+
+	if err := g.SetResourceProperty(inst1, "Tags", []string{"Env=test"}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := g.FindResourcesByTag(Instance, "Env", "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(res), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if err = g.RemoveResource(inst2); err != nil {
+		t.Fatal(err)
+	}
+	if res, err = g.FindResourcesByTag(Instance, "Env", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(res), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if r, _ := g.FindResource("inst_2"); r != nil {
+		t.Fatalf("got %s, want none", r)
+	}
+}
@@ -43,14 +43,17 @@ func init() {
 type Graph struct {
 	storage.Graph
 	triplesCount uint32 // atomic
+	index        *index
 }
 
+// NewGraph returns a graph in its own store, so that it is garbage collected once unused
+// (the default badwolf store keeps every graph created)
 func NewGraph() *Graph {
-	g, err := memory.DefaultStore.NewGraph(context.Background(), randString())
+	g, err := memory.NewStore().NewGraph(context.Background(), randString())
 	if err != nil {
 		panic(err) // badwoclf implementation: only happens on duplicates names of graph
 	}
-	return &Graph{Graph: g}
+	return &Graph{Graph: g, index: newIndex()}
 }
 
 func NewGraphFromTriples(triples []*triple.Triple) *Graph {
@@ -76,8 +79,56 @@ func NewGraphFromFile(filepath string) (*Graph, error) {
 }
 
 func (g *Graph) Add(triples ...*triple.Triple) {
+	g.index.mu.Lock()
+	defer g.index.mu.Unlock()
+	indexed := g.indexedTriples(triples, false)
 	atomic.AddUint32(&g.triplesCount, uint32(len(triples)))
 	_ = g.AddTriples(context.Background(), triples) // badwolf mem store implementation always returns nil error
+	g.index.update(1, indexed)
+}
+
+func (g *Graph) Remove(triples ...*triple.Triple) {
+	if len(triples) == 0 {
+		return
+	}
+	g.index.mu.Lock()
+	defer g.index.mu.Unlock()
+	indexed := g.indexedTriples(triples, true)
+	atomic.AddUint32(&g.triplesCount, ^uint32(len(triples)-1))
+	_ = g.RemoveTriples(context.Background(), triples) // badwolf mem store implementation always returns nil error
+	g.index.update(-1, indexed)
+}
+
+// NodesForID returns the nodes of any type with the given id
+func (g *Graph) NodesForID(id string) []*node.Node {
+	return g.index.nodesForID(id)
+}
+
+// NodesForName returns the nodes of any type with the given Name property
+func (g *Graph) NodesForName(name string) []*node.Node {
+	return g.index.nodesForName(name)
+}
+
+// NodesForTag returns the nodes tagged with the given key (any value) or 'key=value'
+func (g *Graph) NodesForTag(tag string) []*node.Node {
+	return g.index.nodesForTag(tag)
+}
+
+// indexedTriples returns the triples to reference in the index, among the ones
+// (not) stored yet, so that triples added twice are referenced once
+func (g *Graph) indexedTriples(triples []*triple.Triple, stored bool) []*triple.Triple {
+	var indexed []*triple.Triple
+	seen := make(map[string]bool)
+	for _, t := range triples {
+		if !isIndexed(t) || g.HasTriple(t) != stored {
+			continue
+		}
+		if uuid := t.UUID().String(); !seen[uuid] {
+			seen[uuid] = true
+			indexed = append(indexed, t)
+		}
+	}
+	return indexed
 }
 
 func (g *Graph) AddGraph(graph *Graph) {
@@ -241,7 +292,11 @@ func (g *Graph) allTriples() ([]*triple.Triple, error) {
 	return triples, <-errc
 }
 
+// Unmarshal adds the triples of data, either text triples or a binary snapshot
 func (g *Graph) Unmarshal(data []byte) error {
+	if IsSnapshot(data) {
+		return g.UnmarshalSnapshot(data)
+	}
 	for _, line := range bytes.Split(data, []byte{'\n'}) {
 		if bytes.Equal(bytes.TrimSpace(line), []byte("")) {
 			continue
@@ -0,0 +1,186 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rdf
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/node"
+)
+
+// index maps the ids, names and tags of resources to their nodes. The store indexes
+// triples by subject, predicate and object, but neither by node id only
+// (nodes are identified with their type), by property value whatever the rest of
+// the serialized property nor by each tag of a property list
+type index struct {
+	mu    sync.RWMutex
+	ids   map[string]nodeRefs
+	names map[string]nodeRefs
+	tags  map[string]nodeRefs // by tag key and by tag key=value
+}
+
+// nodeRefs counts the triples referencing each node of an index entry
+type nodeRefs map[string]*nodeRef
+
+type nodeRef struct {
+	node  *node.Node
+	count int
+}
+
+func newIndex() *index {
+	return &index{ids: make(map[string]nodeRefs), names: make(map[string]nodeRefs), tags: make(map[string]nodeRefs)}
+}
+
+var (
+	namePropertyPrefix = `{"Key":"Name",`
+	tagsPropertyPrefix = `{"Key":"Tags",`
+)
+
+// isIndexed returns whether the triple is referenced in the index
+func isIndexed(t *triple.Triple) bool {
+	switch t.Predicate().ID() {
+	case HasTypePredicate.ID():
+		return true
+	case PropertyPredicate.ID():
+		l, err := t.Object().Literal()
+		if err != nil {
+			return false
+		}
+		text, err := l.Text()
+		return err == nil && (strings.HasPrefix(text, namePropertyPrefix) || strings.HasPrefix(text, tagsPropertyPrefix))
+	}
+	return false
+}
+
+// update references the triples in the index (delta 1) or unreferences them (delta -1).
+// The caller holds the write lock and gives only triples added to or removed from the store
+func (idx *index) update(delta int, triples []*triple.Triple) {
+	for _, t := range triples {
+		switch t.Predicate().ID() {
+		case HasTypePredicate.ID():
+			idx.ids = updateRefs(idx.ids, string(*t.Subject().ID()), t.Subject(), delta)
+		case PropertyPredicate.ID():
+			if name, ok := nameOf(t); ok {
+				idx.names = updateRefs(idx.names, name, t.Subject(), delta)
+			}
+			for _, tag := range tagsOf(t) {
+				idx.tags = updateRefs(idx.tags, tag, t.Subject(), delta)
+				if i := strings.Index(tag, "="); i > 0 {
+					idx.tags = updateRefs(idx.tags, tag[:i], t.Subject(), delta)
+				}
+			}
+		}
+	}
+}
+
+func (idx *index) nodesForID(id string) []*node.Node {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.ids[id].nodes()
+}
+
+func (idx *index) nodesForName(name string) []*node.Node {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.names[name].nodes()
+}
+
+func (idx *index) nodesForTag(tag string) []*node.Node {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.tags[tag].nodes()
+}
+
+func updateRefs(entries map[string]nodeRefs, key string, n *node.Node, delta int) map[string]nodeRefs {
+	refs, ok := entries[key]
+	if !ok {
+		if delta < 0 {
+			return entries
+		}
+		refs = make(nodeRefs)
+		entries[key] = refs
+	}
+	nodeKey := n.String()
+	ref, ok := refs[nodeKey]
+	if !ok {
+		if delta < 0 {
+			return entries
+		}
+		ref = &nodeRef{node: n}
+		refs[nodeKey] = ref
+	}
+	ref.count += delta
+	if ref.count <= 0 {
+		delete(refs, nodeKey)
+		if len(refs) == 0 {
+			delete(entries, key)
+		}
+	}
+	return entries
+}
+
+func (refs nodeRefs) nodes() []*node.Node {
+	var nodes []*node.Node
+	for _, ref := range refs {
+		nodes = append(nodes, ref.node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].String() < nodes[j].String() })
+	return nodes
+}
+
+// nameOf returns the value of a Name property triple
+func nameOf(t *triple.Triple) (string, bool) {
+	var prop struct{ Value interface{} }
+	if !decodeProperty(t, namePropertyPrefix, &prop) {
+		return "", false
+	}
+	name, ok := prop.Value.(string)
+	return name, ok && name != ""
+}
+
+// tagsOf returns the 'key=value' tags of a Tags property triple
+func tagsOf(t *triple.Triple) []string {
+	var prop struct{ Value []interface{} }
+	if !decodeProperty(t, tagsPropertyPrefix, &prop) {
+		return nil
+	}
+	var tags []string
+	for _, v := range prop.Value {
+		if tag, ok := v.(string); ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// decodeProperty decodes the property triple whose serialization starts with prefix. Other properties
+// are recognized from the prefix of their serialization without being decoded
+func decodeProperty(t *triple.Triple, prefix string, prop interface{}) bool {
+	l, err := t.Object().Literal()
+	if err != nil {
+		return false
+	}
+	text, err := l.Text()
+	if err != nil || !strings.HasPrefix(text, prefix) {
+		return false
+	}
+	return json.Unmarshal([]byte(text), prop) == nil
+}
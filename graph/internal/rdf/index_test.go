@@ -0,0 +1,82 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rdf
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+	"github.com/google/badwolf/triple/node"
+)
+
+func TestNameIndex(t *testing.T) {
+	g := NewGraph()
+	inst1 := noErrNode("/instance", "inst_1")
+	inst2 := noErrNode("/instance", "inst_2")
+	subnet := noErrNode("/subnet", "sub_1")
+
+	nameTriple := func(n *node.Node, name string) *triple.Triple {
+		l, err := literal.DefaultBuilder().Build(literal.Text, `{"Key":"Name","Value":"`+name+`"}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return noErrLiteralTriple(n, PropertyPredicate, l)
+	}
+	ids := func(nodes []*node.Node) (ids []string) {
+		for _, n := range nodes {
+			ids = append(ids, n.ID().String())
+		}
+		return
+	}
+
+	g.Add(nameTriple(inst1, "web"), nameTriple(inst2, "web"), nameTriple(subnet, "web"), nameTriple(inst2, "db"))
+	if got, want := ids(g.NodesForName("web")), []string{"inst_1", "inst_2", "sub_1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := ids(g.NodesForName("db")), []string{"inst_2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	g.Add(nameTriple(inst1, "web"))
+	g.Remove(nameTriple(inst1, "web"))
+	if got, want := ids(g.NodesForName("web")), []string{"inst_2", "sub_1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	g.Remove(nameTriple(inst2, "web"), nameTriple(inst2, "web"), nameTriple(inst2, "db"))
+	g.Remove(nameTriple(inst2, "db"))
+	if got, want := ids(g.NodesForName("web")), []string{"sub_1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := len(g.NodesForName("db")), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+
+	g.Remove(nameTriple(subnet, "web"))
+	g.Add(nameTriple(subnet, "private"))
+	if got, want := len(g.NodesForName("web")), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := ids(g.NodesForName("private")), []string{"sub_1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := len(g.index.names), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
@@ -32,6 +32,8 @@ const (
 	SUBJECT_PREDICATE QueryType = iota
 	PREDICATE_OBJECT
 	PREDICATE_ONLY
+	SUBJECT_ONLY
+	OBJECT_ONLY
 )
 
 func (g *Graph) TriplesForSubjectPredicate(subject *node.Node, predicate *predicate.Predicate) ([]*triple.Triple, error) {
@@ -42,6 +44,14 @@ func (g *Graph) TriplesForPredicateObject(predicate *predicate.Predicate, object
 	return g.returnTriples(PREDICATE_OBJECT, predicate, object)
 }
 
+func (g *Graph) TriplesForSubject(subject *node.Node) ([]*triple.Triple, error) {
+	return g.returnTriples(SUBJECT_ONLY, subject)
+}
+
+func (g *Graph) TriplesForObject(object *triple.Object) ([]*triple.Triple, error) {
+	return g.returnTriples(OBJECT_ONLY, object)
+}
+
 func (g *Graph) CountTriplesForSubjectAndPredicate(subject *node.Node, predicate *predicate.Predicate) (int, error) {
 	all, err := g.returnTriples(SUBJECT_PREDICATE, predicate, subject)
 	return len(all), err
@@ -134,6 +144,12 @@ func (g *Graph) returnTriples(kind QueryType, objects ...interface{}) ([]*triple
 		case PREDICATE_ONLY:
 			predicate := objects[0].(*predicate.Predicate)
 			errc <- g.TriplesForPredicate(context.Background(), predicate, storage.DefaultLookup, triplec)
+		case SUBJECT_ONLY:
+			subject := objects[0].(*node.Node)
+			errc <- g.Graph.TriplesForSubject(context.Background(), subject, storage.DefaultLookup, triplec)
+		case OBJECT_ONLY:
+			object := objects[0].(*triple.Object)
+			errc <- g.Graph.TriplesForObject(context.Background(), object, storage.DefaultLookup, triplec)
 		}
 	}()
 
@@ -0,0 +1,380 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/badwolf/triple"
+	"github.com/google/badwolf/triple/literal"
+	"github.com/google/badwolf/triple/node"
+	"github.com/google/badwolf/triple/predicate"
+)
+
+// snapshotMagic starts binary snapshots, so that they are told apart from text triples
+const snapshotMagic = "\x00AWLSGRAPH"
+
+// SnapshotVersion is the version of the binary snapshots written.
+// Snapshots of a later version cannot be read.
+const SnapshotVersion = 1
+
+const (
+	nodeObject byte = iota
+	literalObject
+)
+
+// IsSnapshot tells whether data is a binary snapshot rather than text triples
+func IsSnapshot(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(snapshotMagic))
+}
+
+// MarshalSnapshot encodes the graph as a versioned binary snapshot:
+// the strings (node types, ids, predicates and text literals) are stored once
+// in a table and triples refer to them by index. As text triples, snapshots
+// of the same graph are identical
+func (g *Graph) MarshalSnapshot() ([]byte, error) {
+	triples, err := g.allTriples()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(triples))
+	for i, t := range triples {
+		keys[i] = t.String()
+	}
+	sort.Sort(&keyedTripleSorter{keys: keys, triples: triples})
+
+	w := &snapshotWriter{indexes: make(map[string]uint64)}
+	for _, t := range triples {
+		if err := w.writeTriple(t); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString(snapshotMagic)
+	writeUvarint(&out, SnapshotVersion)
+	writeUvarint(&out, uint64(len(w.strings)))
+	for _, s := range w.strings {
+		writeUvarint(&out, uint64(len(s)))
+		out.WriteString(s)
+	}
+	writeUvarint(&out, uint64(len(triples)))
+	out.Write(w.triples.Bytes())
+	return out.Bytes(), nil
+}
+
+// UnmarshalSnapshot adds the triples of a binary snapshot to the graph
+func (g *Graph) UnmarshalSnapshot(data []byte) error {
+	if !IsSnapshot(data) {
+		return errors.New("snapshot: invalid header")
+	}
+	r := &snapshotReader{data: data[len(snapshotMagic):]}
+	version := r.uvarint()
+	if r.err == nil && version > SnapshotVersion {
+		return fmt.Errorf("snapshot: unsupported version %d (expecting at most %d)", version, SnapshotVersion)
+	}
+	r.readStrings()
+	count := r.uvarint()
+	if r.err != nil {
+		return r.err
+	}
+	if count > uint64(len(r.data)) {
+		return errors.New("snapshot: truncated data")
+	}
+	triples := make([]*triple.Triple, 0, count)
+	for i := uint64(0); i < count; i++ {
+		t := r.readTriple()
+		if r.err != nil {
+			return r.err
+		}
+		triples = append(triples, t)
+	}
+	g.Add(triples...)
+	return nil
+}
+
+type snapshotWriter struct {
+	strings []string
+	indexes map[string]uint64
+	triples bytes.Buffer
+}
+
+func (w *snapshotWriter) writeString(s string) {
+	i, ok := w.indexes[s]
+	if !ok {
+		i = uint64(len(w.strings))
+		w.indexes[s] = i
+		w.strings = append(w.strings, s)
+	}
+	writeUvarint(&w.triples, i)
+}
+
+func (w *snapshotWriter) writeNode(n *node.Node) {
+	w.writeString(n.Type().String())
+	w.writeString(n.ID().String())
+}
+
+func (w *snapshotWriter) writeTriple(t *triple.Triple) error {
+	if t.Predicate().Type() != predicate.Immutable {
+		return fmt.Errorf("snapshot: unsupported temporal predicate %s", t.Predicate())
+	}
+	w.writeNode(t.Subject())
+	w.writeString(string(t.Predicate().ID()))
+
+	if n, err := t.Object().Node(); err == nil {
+		w.triples.WriteByte(nodeObject)
+		w.writeNode(n)
+		return nil
+	}
+	l, err := t.Object().Literal()
+	if err != nil {
+		return fmt.Errorf("snapshot: unsupported object %s", t.Object())
+	}
+	w.triples.WriteByte(literalObject)
+	w.triples.WriteByte(byte(l.Type()))
+	switch l.Type() {
+	case literal.Text:
+		text, _ := l.Text()
+		w.writeString(text)
+	case literal.Bool:
+		if b, _ := l.Bool(); b {
+			w.triples.WriteByte(1)
+		} else {
+			w.triples.WriteByte(0)
+		}
+	case literal.Int64:
+		i, _ := l.Int64()
+		var buf [binary.MaxVarintLen64]byte
+		w.triples.Write(buf[:binary.PutVarint(buf[:], i)])
+	case literal.Float64:
+		f, _ := l.Float64()
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], math.Float64bits(f))
+		w.triples.Write(buf[:])
+	case literal.Blob:
+		blob, _ := l.Blob()
+		writeUvarint(&w.triples, uint64(len(blob)))
+		w.triples.Write(blob)
+	default:
+		return fmt.Errorf("snapshot: unsupported literal type %s", l.Type())
+	}
+	return nil
+}
+
+// snapshotReader stops decoding at the first error, kept in err
+type snapshotReader struct {
+	data    []byte
+	err     error
+	strings []string
+	types   map[uint64]*node.Type
+	preds   map[uint64]*predicate.Predicate
+}
+
+func (r *snapshotReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *snapshotReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail(errors.New("snapshot: truncated data"))
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *snapshotReader) bytes(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.data)) {
+		r.fail(errors.New("snapshot: truncated data"))
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *snapshotReader) readStrings() {
+	count := r.uvarint()
+	if count > uint64(len(r.data)) {
+		r.fail(errors.New("snapshot: truncated data"))
+		return
+	}
+	r.strings = make([]string, 0, count)
+	for i := uint64(0); i < count && r.err == nil; i++ {
+		r.strings = append(r.strings, string(r.bytes(r.uvarint())))
+	}
+	r.types = make(map[uint64]*node.Type)
+	r.preds = make(map[uint64]*predicate.Predicate)
+}
+
+func (r *snapshotReader) stringIndex() uint64 {
+	i := r.uvarint()
+	if r.err == nil && i >= uint64(len(r.strings)) {
+		r.fail(fmt.Errorf("snapshot: invalid string index %d", i))
+	}
+	return i
+}
+
+func (r *snapshotReader) readNode() *node.Node {
+	typeIndex, idIndex := r.stringIndex(), r.stringIndex()
+	if r.err != nil {
+		return nil
+	}
+	typ, ok := r.types[typeIndex]
+	if !ok {
+		var err error
+		if typ, err = node.NewType(r.strings[typeIndex]); err != nil {
+			r.fail(fmt.Errorf("snapshot: %s", err))
+			return nil
+		}
+		r.types[typeIndex] = typ
+	}
+	id, err := node.NewID(r.strings[idIndex])
+	if err != nil {
+		r.fail(fmt.Errorf("snapshot: %s", err))
+		return nil
+	}
+	return node.NewNode(typ, id)
+}
+
+func (r *snapshotReader) readPredicate() *predicate.Predicate {
+	i := r.stringIndex()
+	if r.err != nil {
+		return nil
+	}
+	pred, ok := r.preds[i]
+	if !ok {
+		var err error
+		if pred, err = predicate.NewImmutable(r.strings[i]); err != nil {
+			r.fail(fmt.Errorf("snapshot: %s", err))
+			return nil
+		}
+		r.preds[i] = pred
+	}
+	return pred
+}
+
+func (r *snapshotReader) readObject() *triple.Object {
+	kind := r.bytes(1)
+	if r.err != nil {
+		return nil
+	}
+	if kind[0] == nodeObject {
+		n := r.readNode()
+		if r.err != nil {
+			return nil
+		}
+		return triple.NewNodeObject(n)
+	}
+	if kind[0] != literalObject {
+		r.fail(fmt.Errorf("snapshot: invalid object kind %d", kind[0]))
+		return nil
+	}
+
+	typ := r.bytes(1)
+	if r.err != nil {
+		return nil
+	}
+	var value interface{}
+	switch literal.Type(typ[0]) {
+	case literal.Text:
+		if i := r.stringIndex(); r.err == nil {
+			value = r.strings[i]
+		}
+	case literal.Bool:
+		if b := r.bytes(1); r.err == nil {
+			value = b[0] == 1
+		}
+	case literal.Int64:
+		i, n := binary.Varint(r.data)
+		if n <= 0 {
+			r.fail(errors.New("snapshot: truncated data"))
+			return nil
+		}
+		r.data = r.data[n:]
+		value = i
+	case literal.Float64:
+		if b := r.bytes(8); r.err == nil {
+			value = math.Float64frombits(binary.BigEndian.Uint64(b))
+		}
+	case literal.Blob:
+		if b := r.bytes(r.uvarint()); r.err == nil {
+			value = append([]byte(nil), b...)
+		}
+	default:
+		r.fail(fmt.Errorf("snapshot: invalid literal type %d", typ[0]))
+	}
+	if r.err != nil {
+		return nil
+	}
+	l, err := literal.DefaultBuilder().Build(literal.Type(typ[0]), value)
+	if err != nil {
+		r.fail(fmt.Errorf("snapshot: %s", err))
+		return nil
+	}
+	return triple.NewLiteralObject(l)
+}
+
+func (r *snapshotReader) readTriple() *triple.Triple {
+	s := r.readNode()
+	p := r.readPredicate()
+	o := r.readObject()
+	if r.err != nil {
+		return nil
+	}
+	t, err := triple.New(s, p, o)
+	if err != nil {
+		r.fail(fmt.Errorf("snapshot: %s", err))
+	}
+	return t
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+type keyedTripleSorter struct {
+	keys    []string
+	triples []*triple.Triple
+}
+
+func (s *keyedTripleSorter) Len() int {
+	return len(s.triples)
+}
+func (s *keyedTripleSorter) Less(i, j int) bool {
+	return s.keys[i] < s.keys[j]
+}
+
+func (s *keyedTripleSorter) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.triples[i], s.triples[j] = s.triples[j], s.triples[i]
+}
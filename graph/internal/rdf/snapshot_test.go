@@ -0,0 +1,79 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	g := NewGraph()
+	err := g.Unmarshal([]byte(`/instance<inst_1>	"has_type"@[]	"/instance"^^type:text
+/instance<inst_1>	"property"@[]	"{"Key":"Name","Value":"redis"}"^^type:text
+/instance<inst_1>	"cores"@[]	"2"^^type:int64
+/instance<inst_1>	"public"@[]	"true"^^type:bool
+/instance<inst_1>	"load"@[]	"0.75"^^type:float64
+/subnet<sub_1>	"has_type"@[]	"/subnet"^^type:text
+/subnet<sub_1>	"parent_of"@[]	/instance<inst_1>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := g.MarshalSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSnapshot(snapshot) {
+		t.Fatal("expected snapshot header")
+	}
+	again, err := g.MarshalSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(snapshot, again) {
+		t.Fatal("expected identical snapshots of the same graph")
+	}
+
+	loaded := NewGraph()
+	if err := loaded.Unmarshal(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := loaded.MustMarshal(), g.MustMarshal(); got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+	if got, want := len(loaded.NodesForID("inst_1")), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+
+	t.Run("unsupported version", func(t *testing.T) {
+		future := append([]byte(snapshotMagic), byte(SnapshotVersion+1))
+		err := NewGraph().Unmarshal(future)
+		if err == nil || !strings.Contains(err.Error(), "unsupported version") {
+			t.Fatalf("got %v, want unsupported version error", err)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		for _, size := range []int{len(snapshotMagic), len(snapshot) / 2, len(snapshot) - 1} {
+			if err := NewGraph().Unmarshal(snapshot[:size]); err == nil {
+				t.Fatalf("size %d: expected error", size)
+			}
+		}
+	})
+}
@@ -56,6 +56,14 @@ const (
 
 	//queue
 	Queue ResourceType = "queue"
+
+	//cloudformation
+	Stack ResourceType = "stack"
+
+	//kubernetes
+	KubeCluster ResourceType = "kubecluster"
+	KubeNode    ResourceType = "kubenode"
+	KubeService ResourceType = "kubeservice"
 )
 
 type FirewallRule struct {
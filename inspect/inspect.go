@@ -18,6 +18,9 @@ package inspect
 
 import (
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/wallix/awless/graph"
 	"github.com/wallix/awless/inspect/inspectors"
@@ -27,7 +30,7 @@ var InspectorsRegister map[string]Inspector
 
 func init() {
 	all := []Inspector{
-		&inspectors.Pricer{}, &inspectors.BucketSizer{},
+		&inspectors.Pricer{}, &inspectors.BucketSizer{}, &inspectors.Exposure{},
 	}
 
 	InspectorsRegister = make(map[string]Inspector)
@@ -43,3 +46,27 @@ type Inspector interface {
 	Name() string
 	Services() []string
 }
+
+// Register adds an inspector to the available ones, replacing any inspector with the same name
+func Register(i Inspector) {
+	InspectorsRegister[i.Name()] = i
+}
+
+// LoadExternalInspectors registers as inspectors the executables found in a directory.
+// Each of them inspects the resources of the given services
+func LoadExternalInspectors(dir string, services []string) error {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.IsDir() || f.Mode()&0111 == 0 {
+			continue
+		}
+		Register(inspectors.NewExternal(filepath.Join(dir, f.Name()), services))
+	}
+	return nil
+}
@@ -0,0 +1,366 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inspectors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/wallix/awless/graph"
+)
+
+// Exposure lists the resources reachable from the internet: instances with a public IP
+// in a subnet routed to an internet gateway, internet-facing load balancers and buckets
+// with a public ACL, on the ports opened to anyone by their security groups
+type Exposure struct {
+	exposed []*exposedResource
+}
+
+type exposedResource struct {
+	severity       severity
+	id, kind       string
+	ports, details string
+}
+
+type severity int
+
+const (
+	low severity = iota
+	medium
+	high
+	critical
+)
+
+func (s severity) String() string {
+	return [...]string{"LOW", "MEDIUM", "HIGH", "CRITICAL"}[s]
+}
+
+// Ports of services that should never be reachable from the internet
+var sensitivePorts = map[int64]string{
+	22: "ssh", 23: "telnet", 445: "smb", 1433: "mssql", 2375: "docker", 3306: "mysql", 3389: "rdp",
+	5432: "postgres", 5900: "vnc", 6379: "redis", 9200: "elasticsearch", 11211: "memcached", 27017: "mongodb",
+}
+
+const (
+	allUsersURI           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	authenticatedUsersURI = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+)
+
+func (*Exposure) Name() string {
+	return "exposure"
+}
+
+func (*Exposure) Services() []string {
+	return []string{"infra", "storage"}
+}
+
+func (e *Exposure) Inspect(graphs ...*graph.Graph) error {
+	if len(graphs) < 1 {
+		return errors.New("no graph provided for")
+	}
+
+	e.exposed = nil
+	for _, g := range graphs {
+		publicSubnets, err := publicSubnets(g)
+		if err != nil {
+			return err
+		}
+		openRules, err := openSecurityGroupRules(g)
+		if err != nil {
+			return err
+		}
+		if err = e.inspectInstances(g, publicSubnets, openRules); err != nil {
+			return err
+		}
+		if err = e.inspectLoadBalancers(g, openRules); err != nil {
+			return err
+		}
+		if err = e.inspectBuckets(g); err != nil {
+			return err
+		}
+	}
+
+	sort.Sort(bySeverity(e.exposed))
+
+	return nil
+}
+
+func (e *Exposure) inspectInstances(g *graph.Graph, publicSubnets map[string]bool, openRules map[string][]*graph.FirewallRule) error {
+	instances, err := g.GetAllResources(graph.Instance)
+	if err != nil {
+		return err
+	}
+	for _, inst := range instances {
+		if state, ok := inst.Properties["State"]; ok && fmt.Sprint(state) != "running" {
+			continue
+		}
+		ip, ok := inst.Properties["PublicIp"]
+		if !ok || !publicSubnets[fmt.Sprint(inst.Properties["SubnetId"])] {
+			continue
+		}
+		rules := rulesOf(inst, openRules)
+		if len(rules) == 0 {
+			continue
+		}
+		sev, ports := rulesExposure(rules)
+		e.exposed = append(e.exposed, &exposedResource{severity: sev, id: inst.Id(), kind: "instance", ports: ports, details: fmt.Sprintf("public ip %v", ip)})
+	}
+	return nil
+}
+
+func (e *Exposure) inspectLoadBalancers(g *graph.Graph, openRules map[string][]*graph.FirewallRule) error {
+	lbs, err := g.GetAllResources(graph.LoadBalancer)
+	if err != nil {
+		return err
+	}
+	for _, lb := range lbs {
+		if fmt.Sprint(lb.Properties["Scheme"]) != "internet-facing" {
+			continue
+		}
+		exposed := &exposedResource{severity: medium, id: fmt.Sprint(lb.Properties["Name"]), kind: "loadbalancer", ports: "listeners", details: fmt.Sprint(lb.Properties["DNSName"])}
+		if _, hasGroups := lb.Properties["SecurityGroups"]; hasGroups {
+			rules := rulesOf(lb, openRules)
+			if len(rules) == 0 {
+				continue
+			}
+			exposed.severity, exposed.ports = rulesExposure(rules)
+		}
+		e.exposed = append(e.exposed, exposed)
+	}
+	return nil
+}
+
+func (e *Exposure) inspectBuckets(g *graph.Graph) error {
+	buckets, err := g.GetAllResources(graph.Bucket)
+	if err != nil {
+		return err
+	}
+	for _, b := range buckets {
+		grants, _ := b.Properties["Grants"].([]*graph.Grant)
+		var anyone, authenticated []string
+		for _, grant := range grants {
+			if grant.GranteeType != "Group" {
+				continue
+			}
+			switch {
+			case strings.HasSuffix(grant.GranteeID, allUsersURI):
+				anyone = append(anyone, grant.Permission)
+			case strings.HasSuffix(grant.GranteeID, authenticatedUsersURI):
+				authenticated = append(authenticated, grant.Permission)
+			}
+		}
+		if len(anyone) == 0 && len(authenticated) == 0 {
+			continue
+		}
+
+		exposed := &exposedResource{severity: medium, id: b.Id(), kind: "bucket", ports: "443/tcp"}
+		var details []string
+		if len(anyone) > 0 {
+			exposed.severity = high
+			details = append(details, fmt.Sprintf("anyone: %s", strings.Join(anyone, ",")))
+		}
+		if len(authenticated) > 0 {
+			details = append(details, fmt.Sprintf("any aws user: %s", strings.Join(authenticated, ",")))
+		}
+		for _, perm := range append(anyone, authenticated...) {
+			if perm == "WRITE" || perm == "WRITE_ACP" || perm == "FULL_CONTROL" {
+				exposed.severity = critical
+			}
+		}
+		exposed.details = strings.Join(details, "; ")
+		e.exposed = append(e.exposed, exposed)
+	}
+	return nil
+}
+
+func (e *Exposure) Print(w io.Writer) {
+	if len(e.exposed) == 0 {
+		fmt.Fprintln(w, "No resource reachable from the internet found")
+		return
+	}
+
+	tabw := tabwriter.NewWriter(w, 0, 8, 1, '\t', 0)
+
+	fmt.Fprintln(tabw, "Severity\tResource\tType\tOpen ports\tDetails\t")
+	fmt.Fprintln(tabw, "--------\t--------\t----\t----------\t-------\t")
+	for _, r := range e.exposed {
+		fmt.Fprintf(tabw, "%s\t%s\t%s\t%s\t%s\t\n", r.severity, r.id, r.kind, r.ports, r.details)
+	}
+
+	tabw.Flush()
+}
+
+// publicSubnets returns the subnets whose route table, explicit or main of their VPC, routes to an internet gateway
+func publicSubnets(g *graph.Graph) (map[string]bool, error) {
+	routeTables, err := g.GetAllResources(graph.RouteTable)
+	if err != nil {
+		return nil, err
+	}
+
+	public := make(map[string]bool)
+	associated := make(map[string]bool)
+	publicMainVpcs := make(map[string]bool)
+	for _, rt := range routeTables {
+		isPublic := routesToInternetGateway(rt)
+		if main, _ := rt.Properties["Main"].(bool); main && isPublic {
+			publicMainVpcs[fmt.Sprint(rt.Properties["VpcId"])] = true
+		}
+		subnets, err := associatedSubnets(g, rt)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range subnets {
+			associated[s.Id()] = true
+			if isPublic {
+				public[s.Id()] = true
+			}
+		}
+	}
+
+	subnets, err := g.GetAllResources(graph.Subnet)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range subnets {
+		if !associated[s.Id()] && publicMainVpcs[fmt.Sprint(s.Properties["VpcId"])] {
+			public[s.Id()] = true
+		}
+	}
+	return public, nil
+}
+
+func associatedSubnets(g *graph.Graph, rt *graph.Resource) ([]*graph.Resource, error) {
+	appliedOn, err := g.ListResourcesAppliedOn(rt)
+	if err != nil {
+		return nil, err
+	}
+	dependingOn, err := g.ListResourcesDependingOn(rt)
+	if err != nil {
+		return nil, err
+	}
+	var subnets []*graph.Resource
+	for _, res := range append(appliedOn, dependingOn...) {
+		if res.Type() == graph.Subnet {
+			subnets = append(subnets, res)
+		}
+	}
+	return subnets, nil
+}
+
+func routesToInternetGateway(rt *graph.Resource) bool {
+	routes, _ := rt.Properties["Routes"].([]*graph.Route)
+	for _, r := range routes {
+		if !isAnyNet(r.Destination) && !isAnyNet(r.DestinationIPv6) {
+			continue
+		}
+		for _, t := range r.Targets {
+			if t.Type == graph.GatewayTarget && strings.HasPrefix(t.Ref, "igw-") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// openSecurityGroupRules returns per security group the inbound rules opened to anyone
+func openSecurityGroupRules(g *graph.Graph) (map[string][]*graph.FirewallRule, error) {
+	groups, err := g.GetAllResources(graph.SecurityGroup)
+	if err != nil {
+		return nil, err
+	}
+	open := make(map[string][]*graph.FirewallRule)
+	for _, sg := range groups {
+		rules, _ := sg.Properties["InboundRules"].([]*graph.FirewallRule)
+		for _, r := range rules {
+			for _, ipRange := range r.IPRanges {
+				if isAnyNet(ipRange) {
+					open[sg.Id()] = append(open[sg.Id()], r)
+					break
+				}
+			}
+		}
+	}
+	return open, nil
+}
+
+func rulesOf(res *graph.Resource, openRules map[string][]*graph.FirewallRule) (rules []*graph.FirewallRule) {
+	groups, _ := res.Properties["SecurityGroups"].([]interface{})
+	for _, sg := range groups {
+		rules = append(rules, openRules[fmt.Sprint(sg)]...)
+	}
+	return
+}
+
+// rulesExposure rates the rules opened to anyone and describes their ports
+func rulesExposure(rules []*graph.FirewallRule) (severity, string) {
+	sev := low
+	var ports []string
+	for _, r := range rules {
+		if r.Protocol == "icmp" || r.Protocol == "58" {
+			ports = append(ports, "icmp")
+			continue
+		}
+		if r.PortRange.Any {
+			ports = append(ports, fmt.Sprintf("all/%s", r.Protocol))
+			sev = critical
+			continue
+		}
+		if r.PortRange.FromPort == r.PortRange.ToPort {
+			ports = append(ports, fmt.Sprintf("%d/%s", r.PortRange.FromPort, r.Protocol))
+		} else {
+			ports = append(ports, fmt.Sprintf("%d-%d/%s", r.PortRange.FromPort, r.PortRange.ToPort, r.Protocol))
+		}
+		for port := range sensitivePorts {
+			if port >= r.PortRange.FromPort && port <= r.PortRange.ToPort {
+				sev = critical
+			}
+		}
+		if sev < medium && !isWebRange(r.PortRange) {
+			sev = medium
+		}
+	}
+	sort.Strings(ports)
+	return sev, strings.Join(ports, ", ")
+}
+
+func isWebRange(r graph.PortRange) bool {
+	return r.FromPort == r.ToPort && (r.FromPort == 80 || r.FromPort == 443)
+}
+
+func isAnyNet(n *net.IPNet) bool {
+	if n == nil {
+		return false
+	}
+	ones, _ := n.Mask.Size()
+	return ones == 0
+}
+
+type bySeverity []*exposedResource
+
+func (b bySeverity) Len() int      { return len(b) }
+func (b bySeverity) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b bySeverity) Less(i, j int) bool {
+	if b[i].severity != b[j].severity {
+		return b[i].severity > b[j].severity
+	}
+	return b[i].id < b[j].id
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inspectors
+
+import (
+	"net"
+	"testing"
+
+	"github.com/wallix/awless/graph"
+)
+
+func TestExposure(t *testing.T) {
+	_, anyNet, _ := net.ParseCIDR("0.0.0.0/0")
+	_, officeNet, _ := net.ParseCIDR("10.0.0.0/8")
+
+	g := graph.NewGraph()
+
+	public := resource("sub_public", graph.Subnet, map[string]interface{}{"VpcId": "vpc_1"})
+	private := resource("sub_private", graph.Subnet, map[string]interface{}{"VpcId": "vpc_1"})
+	implicit := resource("sub_implicit", graph.Subnet, map[string]interface{}{"VpcId": "vpc_2"})
+	publicRoutes := resource("rt_1", graph.RouteTable, map[string]interface{}{"VpcId": "vpc_1", "Routes": []*graph.Route{
+		{Destination: anyNet, Targets: []*graph.RouteTarget{{Type: graph.GatewayTarget, Ref: "igw-1"}}},
+	}})
+	privateRoutes := resource("rt_2", graph.RouteTable, map[string]interface{}{"VpcId": "vpc_1", "Main": true, "Routes": []*graph.Route{
+		{Destination: officeNet, Targets: []*graph.RouteTarget{{Type: graph.GatewayTarget, Ref: "local"}}},
+	}})
+	mainPublicRoutes := resource("rt_3", graph.RouteTable, map[string]interface{}{"VpcId": "vpc_2", "Main": true, "Routes": []*graph.Route{
+		{Destination: anyNet, Targets: []*graph.RouteTarget{{Type: graph.GatewayTarget, Ref: "igw-2"}}},
+	}})
+	g.AddResource(public, private, implicit, publicRoutes, privateRoutes, mainPublicRoutes)
+	g.AddAppliesOnRelation(publicRoutes, public)
+	g.AddAppliesOnRelation(privateRoutes, private)
+
+	g.AddResource(
+		resource("sg_ssh", graph.SecurityGroup, map[string]interface{}{"InboundRules": []*graph.FirewallRule{
+			{Protocol: "tcp", PortRange: graph.PortRange{FromPort: 22, ToPort: 22}, IPRanges: []*net.IPNet{anyNet}},
+		}}),
+		resource("sg_web", graph.SecurityGroup, map[string]interface{}{"InboundRules": []*graph.FirewallRule{
+			{Protocol: "tcp", PortRange: graph.PortRange{FromPort: 443, ToPort: 443}, IPRanges: []*net.IPNet{anyNet}},
+			{Protocol: "tcp", PortRange: graph.PortRange{FromPort: 22, ToPort: 22}, IPRanges: []*net.IPNet{officeNet}},
+		}}),
+		resource("inst_exposed", graph.Instance, map[string]interface{}{"PublicIp": "1.2.3.4", "SubnetId": "sub_public", "SecurityGroups": []interface{}{"sg_ssh"}}),
+		resource("inst_private", graph.Instance, map[string]interface{}{"PublicIp": "1.2.3.5", "SubnetId": "sub_private", "SecurityGroups": []interface{}{"sg_ssh"}}),
+		resource("inst_implicit", graph.Instance, map[string]interface{}{"PublicIp": "1.2.3.6", "SubnetId": "sub_implicit", "SecurityGroups": []interface{}{"sg_web"}}),
+		resource("inst_noip", graph.Instance, map[string]interface{}{"SubnetId": "sub_public", "SecurityGroups": []interface{}{"sg_ssh"}}),
+		resource("lb_arn", graph.LoadBalancer, map[string]interface{}{"Name": "my-lb", "Scheme": "internet-facing", "SecurityGroups": []interface{}{"sg_web"}}),
+		resource("lb_internal", graph.LoadBalancer, map[string]interface{}{"Name": "internal-lb", "Scheme": "internal"}),
+		resource("my-bucket", graph.Bucket, map[string]interface{}{"Grants": []*graph.Grant{
+			{Permission: "READ", GranteeType: "Group", GranteeID: "http://acs.amazonaws.com/groups/global/AllUsers"},
+		}}),
+		resource("private-bucket", graph.Bucket, map[string]interface{}{"Grants": []*graph.Grant{
+			{Permission: "FULL_CONTROL", GranteeType: "CanonicalUser", GranteeID: "owner"},
+		}}),
+	)
+
+	inspector := &Exposure{}
+	if err := inspector.Inspect(g); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		sev       severity
+		id, ports string
+	}{
+		{critical, "inst_exposed", "22/tcp"},
+		{high, "my-bucket", "443/tcp"},
+		{low, "inst_implicit", "443/tcp"},
+		{low, "my-lb", "443/tcp"},
+	}
+	if got, want := len(inspector.exposed), len(expected); got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	for i, exp := range expected {
+		got := inspector.exposed[i]
+		if got.severity != exp.sev || got.id != exp.id || got.ports != exp.ports {
+			t.Fatalf("%d: got %s %s %s, want %s %s %s", i, got.severity, got.id, got.ports, exp.sev, exp.id, exp.ports)
+		}
+	}
+}
+
+func resource(id string, t graph.ResourceType, props map[string]interface{}) *graph.Resource {
+	res := graph.InitResource(id, t)
+	res.Properties = props
+	res.Properties["Id"] = id
+	return res
+}
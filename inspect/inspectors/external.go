@@ -0,0 +1,108 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inspectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wallix/awless/graph"
+)
+
+// External is an inspector delegating to an executable. The executable receives
+// on stdin the resources of the inspected services as JSON:
+//
+//	{"resources": [{"type": "volume", "id": "vol-123", "properties": {...}}, ...]}
+//
+// and its standard output is printed as the inspection report
+type External struct {
+	Path     string
+	services []string
+	out      []byte
+}
+
+// NewExternal returns an inspector running the given executable on the graphs of the given services
+func NewExternal(path string, services []string) *External {
+	return &External{Path: path, services: services}
+}
+
+func (e *External) Name() string {
+	return strings.TrimSuffix(filepath.Base(e.Path), filepath.Ext(e.Path))
+}
+
+func (e *External) Services() []string {
+	return e.services
+}
+
+type externalResource struct {
+	Type       string           `json:"type"`
+	Id         string           `json:"id"`
+	Properties graph.Properties `json:"properties"`
+}
+
+func (e *External) Inspect(graphs ...*graph.Graph) error {
+	var resources []externalResource
+	for _, g := range graphs {
+		for _, t := range externalResourceTypes {
+			all, err := g.GetAllResources(t)
+			if err != nil {
+				return err
+			}
+			sort.Sort(graph.ResourceById(all))
+			for _, res := range all {
+				resources = append(resources, externalResource{Type: res.Type().String(), Id: res.Id(), Properties: res.Properties})
+			}
+		}
+	}
+
+	input, err := json.Marshal(map[string]interface{}{"resources": resources})
+	if err != nil {
+		return err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(e.Path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("inspector %s: %s", e.Name(), err)
+	}
+	e.out = stdout.Bytes()
+
+	return nil
+}
+
+func (e *External) Print(w io.Writer) {
+	w.Write(e.out)
+}
+
+var externalResourceTypes = []graph.ResourceType{
+	graph.Region, graph.Vpc, graph.Subnet, graph.Instance, graph.SecurityGroup, graph.Keypair,
+	graph.Volume, graph.InternetGateway, graph.RouteTable, graph.AvailabilityZone, graph.LoadBalancer, graph.TargetGroup,
+	graph.User, graph.Group, graph.Role, graph.Policy,
+	graph.Bucket, graph.Object,
+	graph.Subscription, graph.Topic,
+	graph.Queue,
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inspectors
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wallix/awless/graph"
+)
+
+func TestExternalInspector(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-inspectors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	script := filepath.Join(dir, "echo_input.sh")
+	if err = ioutil.WriteFile(script, []byte("#!/bin/sh\ncat\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	g := graph.NewGraph()
+	vol := graph.InitResource("vol_1", graph.Volume)
+	vol.Properties["Id"] = "vol_1"
+	g.AddResource(vol)
+
+	inspector := NewExternal(script, []string{"infra"})
+	if got, want := inspector.Name(), "echo_input"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if err = inspector.Inspect(g); err != nil {
+		t.Fatal(err)
+	}
+
+	var buff bytes.Buffer
+	inspector.Print(&buff)
+	if got, want := buff.String(), `{"resources":[{"type":"volume","id":"vol_1","properties":{"Id":"vol_1"}}]}`; !strings.Contains(got, want) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
@@ -20,16 +20,32 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
 	"text/tabwriter"
 
+	"github.com/wallix/awless/cloud"
 	"github.com/wallix/awless/graph"
 )
 
+// Pricer estimates the cost of running instances and volumes from live prices,
+// taking into account spot instances and the reserved instances of the account
 type Pricer struct {
-	total float64
-	count map[string]int
+	Prices cloud.PriceSource
+
+	costs  []*resourceCost
+	byTag  map[string]float64
+	total  float64
+	errors []string
+}
+
+type resourceCost struct {
+	id, kind, pricing string
+	hourly            float64
 }
 
+const untagged = "<untagged>"
+
 func (p *Pricer) Name() string {
 	return "pricer"
 }
@@ -39,100 +55,129 @@ func (p *Pricer) Services() []string {
 }
 
 func (p *Pricer) Inspect(graphs ...*graph.Graph) error {
-	if len(graphs) < 0 {
+	if len(graphs) < 1 {
 		return errors.New("no graph provided for")
 	}
+	if p.Prices == nil {
+		return errors.New("pricer: no price source available")
+	}
 
 	g := graphs[0]
 
-	p.count = make(map[string]int)
+	p.costs, p.byTag, p.total, p.errors = nil, make(map[string]float64), 0, nil
+
+	reservations, err := p.Prices.Reservations()
+	if err != nil {
+		p.errors = append(p.errors, fmt.Sprintf("cannot get reserved instances (priced on-demand): %s", err))
+	}
 
 	instances, err := g.GetAllResources(graph.Instance)
 	if err != nil {
 		return err
 	}
+	sort.Sort(graph.ResourceById(instances))
 
 	for _, inst := range instances {
-		typ := inst.Properties["Type"].(string)
-		if price, ok := prices[typ]; ok {
-			p.total = p.total + price
-			p.count[typ] = p.count[typ] + 1
-		} else {
-			fmt.Printf("no price for instance of type %s", typ)
+		if state, ok := inst.Properties["State"]; ok && fmt.Sprint(state) != "running" {
+			continue
 		}
+		typ := fmt.Sprint(inst.Properties["Type"])
+		zone, _ := inst.Properties["Zone"].(string)
+
+		cost := &resourceCost{id: inst.Id(), kind: typ}
+		switch {
+		case inst.Properties["Lifecycle"] == "spot":
+			cost.pricing = "spot"
+			cost.hourly, err = p.Prices.SpotHourly(typ, zone)
+		case consumeReservation(reservations, typ, zone, &cost.hourly):
+			cost.pricing = "reserved"
+		default:
+			cost.pricing = "on-demand"
+			cost.hourly, err = p.Prices.OnDemandHourly(typ)
+		}
+		p.add(inst, cost, err)
+	}
+
+	volumes, err := g.GetAllResources(graph.Volume)
+	if err != nil {
+		return err
+	}
+	sort.Sort(graph.ResourceById(volumes))
+
+	for _, vol := range volumes {
+		typ := fmt.Sprint(vol.Properties["VolumeType"])
+		cost := &resourceCost{id: vol.Id(), kind: typ, pricing: "on-demand"}
+		perGB, err := p.Prices.VolumeMonthlyPerGB(typ)
+		if size, serr := strconv.ParseFloat(fmt.Sprint(vol.Properties["Size"]), 64); serr == nil {
+			cost.hourly = perGB * size / cloud.HoursPerMonth
+		}
+		p.add(vol, cost, err)
 	}
 
 	return nil
 }
 
+func (p *Pricer) add(res *graph.Resource, cost *resourceCost, err error) {
+	if err != nil {
+		p.errors = append(p.errors, fmt.Sprintf("no %s price for %s (%s): %s", cost.pricing, res.Id(), cost.kind, err))
+		return
+	}
+	p.costs = append(p.costs, cost)
+	p.total += cost.hourly
+
+	tags, _ := res.Properties["Tags"].([]interface{})
+	if len(tags) == 0 {
+		p.byTag[untagged] += cost.hourly
+	}
+	for _, tag := range tags {
+		p.byTag[fmt.Sprint(tag)] += cost.hourly
+	}
+}
+
+// consumeReservation uses a reserved instance, zonal then regional, matching the instance if any is left
+func consumeReservation(reservations []*cloud.Reservation, instanceType, zone string, hourly *float64) bool {
+	for _, zonal := range []bool{true, false} {
+		for _, r := range reservations {
+			if r.InstanceType != instanceType || r.Count < 1 || (r.Zone != "") != zonal || zonal && r.Zone != zone {
+				continue
+			}
+			r.Count--
+			*hourly = r.Hourly
+			return true
+		}
+	}
+	return false
+}
+
 func (p *Pricer) Print(w io.Writer) {
-	tabw := tabwriter.NewWriter(w, 0, 8, 0, '\t', 0)
+	tabw := tabwriter.NewWriter(w, 0, 8, 1, '\t', 0)
 
-	fmt.Fprintln(tabw, "Instance\tCount\tEstimated total per day\t")
-	fmt.Fprintln(tabw, "--------\t-----\t-----------------------\t")
+	fmt.Fprintln(tabw, "Resource\tType\tPricing\tPer hour\tPer month\t")
+	fmt.Fprintln(tabw, "--------\t----\t-------\t--------\t---------\t")
+	for _, c := range p.costs {
+		fmt.Fprintf(tabw, "%s\t%s\t%s\t$%.4f\t$%.2f\t\n", c.id, c.kind, c.pricing, c.hourly, c.hourly*cloud.HoursPerMonth)
+	}
+	fmt.Fprintf(tabw, "\t\t\t$%.4f\t$%.2f\t\n", p.total, p.total*cloud.HoursPerMonth)
+	fmt.Fprintln(tabw)
 
-	for instType, count := range p.count {
-		fmt.Fprintf(tabw, "%s\t%d\t%s\t\n", instType, count, "")
+	var tags []string
+	for tag := range p.byTag {
+		tags = append(tags, tag)
 	}
+	sort.Strings(tags)
 
-	fmt.Fprintf(tabw, "%s\t%s\t$%.2f\t\n", "", "", p.total*24)
+	fmt.Fprintln(tabw, "Tag\tPer month\t")
+	fmt.Fprintln(tabw, "---\t---------\t")
+	for _, tag := range tags {
+		fmt.Fprintf(tabw, "%s\t$%.2f\t\n", tag, p.byTag[tag]*cloud.HoursPerMonth)
+	}
 
 	tabw.Flush()
-}
 
-// This prices serve as an example as they are only valid for eu-west-1 region
-var prices = map[string]float64{
-	"t2.nano":     0.0063,
-	"t2.micro":    0.013,
-	"t2.small":    0.025,
-	"t2.medium":   0.05,
-	"t2.large":    0.101,
-	"t2.xlarge":   0.202,
-	"t2.2xlarge":  0.404,
-	"m4.large":    0.119,
-	"m4.xlarge":   0.238,
-	"m4.2xlarge":  0.475,
-	"m4.4xlarge":  0.95,
-	"m4.10xlarge": 2.377,
-	"m4.16xlarge": 3.803,
-	"m3.medium":   0.073,
-	"m3.large":    0.146,
-	"m3.xlarge":   0.293,
-	"m3.2xlarge":  0.585,
-	"c4.large":    0.113,
-	"c4.xlarge":   0.226,
-	"c4.2xlarge":  0.453,
-	"c4.4xlarge":  0.905,
-	"c4.8xlarge":  1.811,
-	"c3.large":    0.12,
-	"c3.xlarge":   0.239,
-	"c3.2xlarge":  0.478,
-	"c3.4xlarge":  0.956,
-	"c3.8xlarge":  1.912,
-	"p2.xlarge":   0.972,
-	"p2.8xlarge":  7.776,
-	"p2.16xlarge": 15.552,
-	"g2.2xlarge":  0.702,
-	"g2.8xlarge":  2.808,
-	"x1.16xlarge": 8.003,
-	"x1.32xlarge": 16.006,
-	"r3.large":    0.185,
-	"r3.xlarge":   0.371,
-	"r3.2xlarge":  0.741,
-	"r3.4xlarge":  1.482,
-	"r3.8xlarge":  2.964,
-	"r4.large":    0.148,
-	"r4.xlarge":   0.296,
-	"r4.2xlarge":  0.593,
-	"r4.4xlarge":  1.186,
-	"r4.8xlarge":  2.371,
-	"r4.16xlarge": 4.742,
-	"i2.xlarge":   0.938,
-	"i2.2xlarge":  1.876,
-	"i2.4xlarge":  3.751,
-	"i2.8xlarge":  7.502,
-	"d2.xlarge":   0.735,
-	"d2.2xlarge":  1.47,
-	"d2.4xlarge":  2.94,
-	"d2.8xlarge":  5.88,
+	for _, e := range p.errors {
+		fmt.Fprintf(w, "\nwarning: %s", e)
+	}
+	if len(p.errors) > 0 {
+		fmt.Fprintln(w)
+	}
 }
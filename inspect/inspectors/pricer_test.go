@@ -0,0 +1,83 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inspectors
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/graph"
+)
+
+type mockPrices struct{}
+
+func (mockPrices) OnDemandHourly(string) (float64, error)     { return 0.1, nil }
+func (mockPrices) SpotHourly(string, string) (float64, error) { return 0.03, nil }
+func (mockPrices) VolumeMonthlyPerGB(string) (float64, error) { return 0.1, nil }
+func (mockPrices) Reservations() ([]*cloud.Reservation, error) {
+	return []*cloud.Reservation{{InstanceType: "t2.micro", Zone: "eu-west-1a", Count: 1, Hourly: 0.05}}, nil
+}
+
+func TestPricer(t *testing.T) {
+	g := graph.NewGraph()
+	for _, props := range []map[string]interface{}{
+		{"Id": "inst_1", "Type": "t2.micro", "State": "running", "Zone": "eu-west-1a", "Tags": []interface{}{"Team=web"}},
+		{"Id": "inst_2", "Type": "t2.micro", "State": "running", "Zone": "eu-west-1a", "Tags": []interface{}{"Team=web"}},
+		{"Id": "inst_3", "Type": "t2.micro", "State": "running", "Lifecycle": "spot"},
+		{"Id": "inst_4", "Type": "t2.micro", "State": "stopped"},
+	} {
+		inst := graph.InitResource(props["Id"].(string), graph.Instance)
+		inst.Properties = props
+		g.AddResource(inst)
+	}
+	vol := graph.InitResource("vol_1", graph.Volume)
+	vol.Properties = map[string]interface{}{"Id": "vol_1", "VolumeType": "gp2", "Size": 73}
+	g.AddResource(vol)
+
+	pricer := &Pricer{Prices: mockPrices{}}
+	if err := pricer.Inspect(g); err != nil {
+		t.Fatal(err)
+	}
+
+	pricing := make(map[string]string)
+	for _, c := range pricer.costs {
+		pricing[c.id] = c.pricing
+	}
+	expected := map[string]string{"inst_1": "reserved", "inst_2": "on-demand", "inst_3": "spot", "vol_1": "on-demand"}
+	for id, want := range expected {
+		if got := pricing[id]; got != want {
+			t.Fatalf("%s: got %s, want %s", id, got, want)
+		}
+	}
+	if got, want := len(pricer.costs), 4; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := pricer.byTag["Team=web"], 0.15; got < want-1e-9 || got > want+1e-9 {
+		t.Fatalf("got %f, want %f", got, want)
+	}
+	if got, want := pricer.total, 0.19; got < want-1e-9 || got > want+1e-9 {
+		t.Fatalf("got %f, want %f", got, want)
+	}
+
+	var buff bytes.Buffer
+	pricer.Print(&buff)
+	if !strings.Contains(buff.String(), "<untagged>") {
+		t.Fatalf("expected untagged costs in %s", buff.String())
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// kubeconfig is the subset of a kubectl config file needed to reach clusters
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string   `yaml:"name"`
+		User authInfo `yaml:"user"`
+	} `yaml:"users"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+}
+
+type authInfo struct {
+	Token                 string `yaml:"token"`
+	TokenFile             string `yaml:"tokenFile"`
+	Username              string `yaml:"username"`
+	Password              string `yaml:"password"`
+	ClientCertificate     string `yaml:"client-certificate"`
+	ClientCertificateData string `yaml:"client-certificate-data"`
+	ClientKey             string `yaml:"client-key"`
+	ClientKeyData         string `yaml:"client-key-data"`
+	Exec                  *struct {
+		Command string    `yaml:"command"`
+		Args    []string  `yaml:"args"`
+		Env     []execEnv `yaml:"env"`
+	} `yaml:"exec"`
+}
+
+type execEnv struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// cluster is a connection to the API server of a kubeconfig context
+type cluster struct {
+	name       string
+	server     string
+	httpClient *http.Client
+	authorize  func(*http.Request) error
+}
+
+// DefaultKubeconfigPath returns $KUBECONFIG (first path) or ~/.kube/config
+func DefaultKubeconfigPath() string {
+	if paths := os.Getenv("KUBECONFIG"); paths != "" {
+		return filepath.SplitList(paths)[0]
+	}
+	return filepath.Join(os.Getenv("HOME"), ".kube", "config")
+}
+
+func loadKubeconfig(path string) (*kubeconfig, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	conf := &kubeconfig{}
+	if err := yaml.Unmarshal(content, conf); err != nil {
+		return nil, fmt.Errorf("kubeconfig %s: %s", path, err)
+	}
+	return conf, nil
+}
+
+// cluster returns the connection of the named context, the current one if empty
+func (k *kubeconfig) cluster(contextName string) (*cluster, error) {
+	if contextName == "" {
+		contextName = k.CurrentContext
+	}
+	var clusterName, userName string
+	var found bool
+	for _, c := range k.Contexts {
+		if c.Name == contextName {
+			clusterName, userName, found = c.Context.Cluster, c.Context.User, true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("kubeconfig: unknown context '%s'", contextName)
+	}
+
+	c := &cluster{name: contextName}
+	tlsConfig := &tls.Config{}
+	for _, cl := range k.Clusters {
+		if cl.Name != clusterName {
+			continue
+		}
+		c.server = strings.TrimSuffix(cl.Cluster.Server, "/")
+		tlsConfig.InsecureSkipVerify = cl.Cluster.InsecureSkipTLSVerify
+		ca, err := dataOrFile(cl.Cluster.CertificateAuthorityData, cl.Cluster.CertificateAuthority)
+		if err != nil {
+			return nil, fmt.Errorf("kubeconfig: cluster '%s' certificate authority: %s", clusterName, err)
+		}
+		if len(ca) > 0 {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(ca) {
+				return nil, fmt.Errorf("kubeconfig: cluster '%s': invalid certificate authority", clusterName)
+			}
+			tlsConfig.RootCAs = pool
+		}
+	}
+	if c.server == "" {
+		return nil, fmt.Errorf("kubeconfig: no server for cluster '%s' of context '%s'", clusterName, contextName)
+	}
+
+	var user authInfo
+	for _, u := range k.Users {
+		if u.Name == userName {
+			user = u.User
+		}
+	}
+	cert, err := dataOrFile(user.ClientCertificateData, user.ClientCertificate)
+	if err != nil {
+		return nil, err
+	}
+	key, err := dataOrFile(user.ClientKeyData, user.ClientKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(cert) > 0 && len(key) > 0 {
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("kubeconfig: user '%s' client certificate: %s", userName, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	c.httpClient = &http.Client{Timeout: 30 * time.Second, Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}}
+	c.authorize = authorizer(user)
+	return c, nil
+}
+
+// authorizer returns the function setting the credentials of the user on requests
+func authorizer(user authInfo) func(*http.Request) error {
+	switch {
+	case user.Token != "":
+		return bearer(func() (string, error) { return user.Token, nil })
+	case user.TokenFile != "":
+		return bearer(func() (string, error) {
+			content, err := ioutil.ReadFile(user.TokenFile)
+			return strings.TrimSpace(string(content)), err
+		})
+	case user.Exec != nil:
+		var token string
+		return bearer(func() (string, error) {
+			if token != "" {
+				return token, nil
+			}
+			var err error
+			token, err = execToken(user.Exec.Command, user.Exec.Args, user.Exec.Env)
+			return token, err
+		})
+	case user.Username != "":
+		return func(req *http.Request) error {
+			req.SetBasicAuth(user.Username, user.Password)
+			return nil
+		}
+	}
+	return func(*http.Request) error { return nil }
+}
+
+func bearer(token func() (string, error)) func(*http.Request) error {
+	return func(req *http.Request) error {
+		t, err := token()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+t)
+		return nil
+	}
+}
+
+// execToken runs a credential plugin (ex: aws eks get-token) returning an ExecCredential
+func execToken(command string, args []string, env []execEnv) (string, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Env = os.Environ()
+	for _, e := range env {
+		cmd.Env = append(cmd.Env, e.Name+"="+e.Value)
+	}
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("kubeconfig: running credential plugin '%s': %s", command, err)
+	}
+	var cred struct {
+		Status struct {
+			Token string `json:"token"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(out, &cred); err != nil {
+		return "", fmt.Errorf("kubeconfig: credential plugin '%s' output: %s", command, err)
+	}
+	if cred.Status.Token == "" {
+		return "", errors.New("kubeconfig: credential plugin '" + command + "' returned no token")
+	}
+	return cred.Status.Token, nil
+}
+
+func dataOrFile(data, path string) ([]byte, error) {
+	if data != "" {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	if path != "" {
+		return ioutil.ReadFile(path)
+	}
+	return nil, nil
+}
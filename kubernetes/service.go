@@ -0,0 +1,368 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kubernetes fetches the nodes and services of Kubernetes clusters reachable
+// through kubeconfig contexts, relating them to the cloud resources backing them.
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template/driver"
+)
+
+const (
+	// ContextsKey is the config key listing the kubeconfig contexts to inventory
+	// Ex: awless config set kubernetes.contexts prod,staging
+	ContextsKey = "kubernetes.contexts"
+	// KubeconfigKey is the config key of the kubeconfig path, defaulting to $KUBECONFIG or ~/.kube/config
+	KubeconfigKey = "kubernetes.kubeconfig"
+)
+
+var ResourceTypes = []string{graph.KubeCluster.String(), graph.KubeNode.String(), graph.KubeService.String()}
+
+// LoadBalancerResolver returns the id of the cloud load balancer having the given DNS name
+type LoadBalancerResolver func(dnsName string) (string, bool)
+
+type service struct {
+	clusters  []*cluster
+	resolveLB LoadBalancerResolver
+}
+
+// NewService returns the service fetching the given contexts of a kubeconfig file
+func NewService(kubeconfigPath string, contexts []string, resolveLB LoadBalancerResolver) (cloud.Service, error) {
+	conf, err := loadKubeconfig(kubeconfigPath)
+	if err != nil {
+		return nil, err
+	}
+	srv := &service{resolveLB: resolveLB}
+	for _, name := range contexts {
+		c, err := conf.cluster(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		srv.clusters = append(srv.clusters, c)
+	}
+	return srv, nil
+}
+
+func (s *service) Name() string { return "kubernetes" }
+
+func (s *service) Drivers() []driver.Driver { return nil }
+
+func (s *service) ResourceTypes() []string { return ResourceTypes }
+
+func (s *service) FetchResources() (*graph.Graph, error) {
+	g := graph.NewGraph()
+	for _, c := range s.clusters {
+		if err := s.fetchCluster(g, c); err != nil {
+			return g, fmt.Errorf("cluster %s: %s", c.name, err)
+		}
+	}
+	return g, nil
+}
+
+func (s *service) FetchByType(t string) (*graph.Graph, error) {
+	all, err := s.FetchResources()
+	if err != nil {
+		return all, err
+	}
+	resources, err := all.GetAllResources(graph.ResourceType(t))
+	if err != nil {
+		return nil, err
+	}
+	g := graph.NewGraph()
+	return g, g.AddResource(resources...)
+}
+
+type objectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	Uid               string            `json:"uid"`
+	CreationTimestamp string            `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels"`
+}
+
+type node struct {
+	Metadata objectMeta `json:"metadata"`
+	Spec     struct {
+		ProviderID string `json:"providerID"`
+	} `json:"spec"`
+	Status struct {
+		Addresses []struct {
+			Type    string `json:"type"`
+			Address string `json:"address"`
+		} `json:"addresses"`
+		Conditions []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+		NodeInfo struct {
+			KubeletVersion string `json:"kubeletVersion"`
+		} `json:"nodeInfo"`
+	} `json:"status"`
+}
+
+type kubeService struct {
+	Metadata objectMeta `json:"metadata"`
+	Spec     struct {
+		Type      string `json:"type"`
+		ClusterIP string `json:"clusterIP"`
+		Ports     []struct {
+			Port     int    `json:"port"`
+			NodePort int    `json:"nodePort"`
+			Protocol string `json:"protocol"`
+		} `json:"ports"`
+	} `json:"spec"`
+	Status struct {
+		LoadBalancer struct {
+			Ingress []struct {
+				IP       string `json:"ip"`
+				Hostname string `json:"hostname"`
+			} `json:"ingress"`
+		} `json:"loadBalancer"`
+	} `json:"status"`
+}
+
+func (s *service) fetchCluster(g *graph.Graph, c *cluster) error {
+	clusterRes := graph.InitResource(c.name, graph.KubeCluster)
+	clusterRes.Properties["Id"] = c.name
+	clusterRes.Properties["Name"] = c.name
+	clusterRes.Properties["Server"] = c.server
+	var version struct {
+		GitVersion string `json:"gitVersion"`
+	}
+	if err := c.get("/version", &version); err == nil && version.GitVersion != "" {
+		clusterRes.Properties["Version"] = version.GitVersion
+	}
+	if err := g.AddResource(clusterRes); err != nil {
+		return err
+	}
+
+	err := c.list("/api/v1/nodes", func(items json.RawMessage) error {
+		var nodes []node
+		if err := json.Unmarshal(items, &nodes); err != nil {
+			return err
+		}
+		for _, n := range nodes {
+			res := nodeResource(c.name, n)
+			if err := g.AddResource(res); err != nil {
+				return err
+			}
+			if err := g.AddParentRelation(clusterRes, res); err != nil {
+				return err
+			}
+			if id, ok := res.Properties["InstanceId"].(string); ok {
+				if err := g.AddAppliesOnRelation(graph.InitResource(id, graph.Instance), res); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.list("/api/v1/services", func(items json.RawMessage) error {
+		var services []kubeService
+		if err := json.Unmarshal(items, &services); err != nil {
+			return err
+		}
+		for _, svc := range services {
+			res := serviceResource(c.name, svc)
+			if dns, ok := res.Properties["LoadBalancerDNS"].(string); ok && s.resolveLB != nil {
+				if lbId, found := s.resolveLB(dns); found {
+					res.Properties["LoadBalancerId"] = lbId
+				}
+			}
+			if err := g.AddResource(res); err != nil {
+				return err
+			}
+			if err := g.AddParentRelation(clusterRes, res); err != nil {
+				return err
+			}
+			if lbId, ok := res.Properties["LoadBalancerId"].(string); ok {
+				if err := g.AddAppliesOnRelation(graph.InitResource(lbId, graph.LoadBalancer), res); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func nodeResource(clusterName string, n node) *graph.Resource {
+	res := graph.InitResource(n.Metadata.Uid, graph.KubeNode)
+	res.Properties["Id"] = n.Metadata.Uid
+	res.Properties["Name"] = n.Metadata.Name
+	res.Properties["Cluster"] = clusterName
+	setTime(res, "CreateDate", n.Metadata.CreationTimestamp)
+	if id := instanceIdFromProviderID(n.Spec.ProviderID); id != "" {
+		res.Properties["InstanceId"] = id
+	}
+	setFirstLabel(res, "Type", n.Metadata.Labels, "node.kubernetes.io/instance-type", "beta.kubernetes.io/instance-type")
+	setFirstLabel(res, "AvailabilityZone", n.Metadata.Labels, "topology.kubernetes.io/zone", "failure-domain.beta.kubernetes.io/zone")
+	for _, addr := range n.Status.Addresses {
+		switch addr.Type {
+		case "InternalIP":
+			res.Properties["PrivateIp"] = addr.Address
+		case "ExternalIP":
+			res.Properties["PublicIp"] = addr.Address
+		}
+	}
+	for _, cond := range n.Status.Conditions {
+		if cond.Type == "Ready" {
+			if cond.Status == "True" {
+				res.Properties["State"] = "ready"
+			} else {
+				res.Properties["State"] = "notready"
+			}
+		}
+	}
+	if v := n.Status.NodeInfo.KubeletVersion; v != "" {
+		res.Properties["KubeletVersion"] = v
+	}
+	return res
+}
+
+func serviceResource(clusterName string, svc kubeService) *graph.Resource {
+	res := graph.InitResource(svc.Metadata.Uid, graph.KubeService)
+	res.Properties["Id"] = svc.Metadata.Uid
+	res.Properties["Name"] = svc.Metadata.Name
+	res.Properties["Namespace"] = svc.Metadata.Namespace
+	res.Properties["Cluster"] = clusterName
+	res.Properties["Type"] = svc.Spec.Type
+	setTime(res, "CreateDate", svc.Metadata.CreationTimestamp)
+	if ip := svc.Spec.ClusterIP; ip != "" && ip != "None" {
+		res.Properties["ClusterIp"] = ip
+	}
+	var ports []string
+	for _, p := range svc.Spec.Ports {
+		port := fmt.Sprint(p.Port)
+		if p.NodePort != 0 {
+			port += fmt.Sprintf(":%d", p.NodePort)
+		}
+		ports = append(ports, port+"/"+p.Protocol)
+	}
+	if len(ports) > 0 {
+		res.Properties["Ports"] = ports
+	}
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.Hostname != "" {
+			res.Properties["LoadBalancerDNS"] = ingress.Hostname
+		}
+		if ingress.IP != "" {
+			res.Properties["PublicIp"] = ingress.IP
+		}
+	}
+	return res
+}
+
+// instanceIdFromProviderID extracts the instance id of AWS nodes (ex: aws:///eu-west-1a/i-0123456)
+func instanceIdFromProviderID(providerID string) string {
+	if !strings.HasPrefix(providerID, "aws://") {
+		return ""
+	}
+	id := providerID[strings.LastIndex(providerID, "/")+1:]
+	if strings.HasPrefix(id, "i-") {
+		return id
+	}
+	return ""
+}
+
+func setFirstLabel(res *graph.Resource, key string, labels map[string]string, names ...string) {
+	for _, name := range names {
+		if v, ok := labels[name]; ok && v != "" {
+			res.Properties[key] = v
+			return
+		}
+	}
+}
+
+func setTime(res *graph.Resource, key, value string) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		res.Properties[key] = t
+	}
+}
+
+func (c *cluster) get(path string, v interface{}) error {
+	req, err := http.NewRequest("GET", c.server+path, nil)
+	if err != nil {
+		return err
+	}
+	if err = c.authorize(req); err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return cloud.ErrFetchAccessDenied
+	}
+	if resp.StatusCode >= 300 {
+		var status struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &status) == nil && status.Message != "" {
+			return fmt.Errorf("%d %s", resp.StatusCode, status.Message)
+		}
+		return fmt.Errorf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return json.Unmarshal(body, v)
+}
+
+// list calls a list endpoint by chunks, following the continue tokens
+func (c *cluster) list(path string, onItems func(json.RawMessage) error) error {
+	var cont string
+	for {
+		query := url.Values{"limit": {"500"}}
+		if cont != "" {
+			query.Set("continue", cont)
+		}
+		var page struct {
+			Items    json.RawMessage `json:"items"`
+			Metadata struct {
+				Continue string `json:"continue"`
+			} `json:"metadata"`
+		}
+		if err := c.get(path+"?"+query.Encode(), &page); err != nil {
+			return err
+		}
+		if err := onItems(page.Items); err != nil {
+			return err
+		}
+		if page.Metadata.Continue == "" {
+			return nil
+		}
+		cont = page.Metadata.Continue
+	}
+}
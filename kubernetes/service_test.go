@@ -0,0 +1,150 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wallix/awless/graph"
+)
+
+const kubeconfigTemplate = `apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- name: prod-cluster
+  cluster:
+    server: %s/
+contexts:
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: admin
+users:
+- name: admin
+  user:
+    token: mytoken
+`
+
+func TestFetchCluster(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Authorization"), "Bearer mytoken"; got != want {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/version":
+			w.Write([]byte(`{"gitVersion": "v1.27.3"}`))
+		case "/api/v1/nodes":
+			if r.URL.Query().Get("continue") == "" {
+				w.Write([]byte(`{"items": [{"metadata": {"name": "ip-10-0-1-5", "uid": "node-1", "labels": {"node.kubernetes.io/instance-type": "m5.large"}},
+					"spec": {"providerID": "aws:///eu-west-1a/i-0abc"},
+					"status": {"addresses": [{"type": "InternalIP", "address": "10.0.1.5"}], "conditions": [{"type": "Ready", "status": "True"}]}}],
+					"metadata": {"continue": "next"}}`))
+				return
+			}
+			w.Write([]byte(`{"items": [{"metadata": {"name": "gke-node", "uid": "node-2"}, "spec": {"providerID": "gce://proj/zone/gke-node"},
+				"status": {"conditions": [{"type": "Ready", "status": "Unknown"}]}}], "metadata": {}}`))
+		case "/api/v1/services":
+			w.Write([]byte(`{"items": [
+				{"metadata": {"name": "web", "namespace": "shop", "uid": "svc-1"}, "spec": {"type": "LoadBalancer", "clusterIP": "172.20.0.10", "ports": [{"port": 80, "nodePort": 30080, "protocol": "TCP"}]},
+				 "status": {"loadBalancer": {"ingress": [{"hostname": "web-123.elb.eu-west-1.amazonaws.com"}]}}},
+				{"metadata": {"name": "kubernetes", "namespace": "default", "uid": "svc-2"}, "spec": {"type": "ClusterIP", "clusterIP": "172.20.0.1"}}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "awless-kube")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config")
+	if err = ioutil.WriteFile(path, []byte(fmt.Sprintf(kubeconfigTemplate, server.URL)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = NewService(path, []string{"staging"}, nil); err == nil {
+		t.Fatal("expected error for unknown context")
+	}
+
+	resolver := func(dns string) (string, bool) {
+		if dns == "web-123.elb.eu-west-1.amazonaws.com" {
+			return "arn:aws:elasticloadbalancing:lb/web", true
+		}
+		return "", false
+	}
+	srv, err := NewService(path, []string{"prod"}, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := srv.FetchResources()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cluster, _ := g.GetResource(graph.KubeCluster, "prod")
+	if got, want := cluster.Properties["Version"], "v1.27.3"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	nodes, _ := g.GetAllResources(graph.KubeNode)
+	if got, want := len(nodes), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	node, _ := g.GetResource(graph.KubeNode, "node-1")
+	expected := map[string]interface{}{"Name": "ip-10-0-1-5", "Cluster": "prod", "InstanceId": "i-0abc", "Type": "m5.large", "PrivateIp": "10.0.1.5", "State": "ready"}
+	for k, want := range expected {
+		if got := node.Properties[k]; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %#v, want %#v", k, got, want)
+		}
+	}
+	gkeNode, _ := g.GetResource(graph.KubeNode, "node-2")
+	if _, ok := gkeNode.Properties["InstanceId"]; ok {
+		t.Fatal("expected no instance id for non AWS node")
+	}
+
+	web, _ := g.GetResource(graph.KubeService, "svc-1")
+	expected = map[string]interface{}{"Namespace": "shop", "Type": "LoadBalancer", "LoadBalancerId": "arn:aws:elasticloadbalancing:lb/web", "Ports": []interface{}{"80:30080/TCP"}}
+	for k, want := range expected {
+		if got := web.Properties[k]; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %#v, want %#v", k, got, want)
+		}
+	}
+
+	backed, err := g.ListResourcesAppliedOn(graph.InitResource("i-0abc", graph.Instance))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backed) != 1 || backed[0].Id() != "node-1" {
+		t.Fatalf("got %v, want node-1 backed by instance", backed)
+	}
+	backed, _ = g.ListResourcesAppliedOn(graph.InitResource("arn:aws:elasticloadbalancing:lb/web", graph.LoadBalancer))
+	if len(backed) != 1 || backed[0].Id() != "svc-1" {
+		t.Fatalf("got %v, want svc-1 backed by load balancer", backed)
+	}
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lock prevents concurrent template runs against the same account and region
+// with a lock item conditionally written in a DynamoDB table
+package lock
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/wallix/awless/aws"
+	"github.com/wallix/awless/database"
+)
+
+const (
+	// TableKey is the config key of the DynamoDB table holding the locks.
+	// Its partition key must be the string attribute LockID
+	TableKey = "lock.dynamodb.table"
+	// RegionKey is the config key of the region of the lock table (default: the current region)
+	RegionKey = "lock.dynamodb.region"
+	// TimeoutKey is the config key of the duration after which a lock is stale and can be
+	// acquired by others, in case its holder crashed (default: 1h)
+	TimeoutKey = "lock.timeout"
+
+	DefaultTimeout = time.Hour
+
+	conditionalCheckFailed = "ConditionalCheckFailedException"
+)
+
+// Lock is held during a template run
+type Lock struct {
+	// Key identifies what is locked. Ex: 123456789012/eu-west-1
+	Key string
+	// ID is unique to the holder so that it only releases its own lock
+	ID        string
+	Who       string
+	Host      string
+	Operation string
+	Created   time.Time
+	Expires   time.Time
+}
+
+func (l *Lock) String() string {
+	return fmt.Sprintf("%s locked by %s on %s since %s (%s, expires %s)", l.Key, l.Who, l.Host, l.Created.Format(time.Stamp), l.Operation, l.Expires.Format(time.Stamp))
+}
+
+// New returns a lock on the account and region for the given operation, expiring after timeout
+func New(account, region, who, operation string, timeout time.Duration) *Lock {
+	if who == "" {
+		who = currentUser()
+	}
+	host, _ := os.Hostname()
+	now := time.Now().UTC().Truncate(time.Second)
+	return &Lock{Key: Key(account, region), ID: randomID(), Who: who, Host: host, Operation: operation, Created: now, Expires: now.Add(timeout)}
+}
+
+// Key is the lock key of an account and region
+func Key(account, region string) string {
+	return account + "/" + region
+}
+
+// HeldError is returned when the lock is held by someone else
+type HeldError struct {
+	Holder *Lock
+}
+
+func (e *HeldError) Error() string {
+	if e.Holder == nil {
+		return "lock already held"
+	}
+	return fmt.Sprintf("%s. Retry later or, if you are sure no run is in progress, `awless lock force-unlock`", e.Holder)
+}
+
+// DynamoDB stores locks as items of a table
+type DynamoDB struct {
+	table string
+	api   *aws.JSONRPCClient
+}
+
+func NewDynamoDB(sess *session.Session, table string) *DynamoDB {
+	return &DynamoDB{table: table, api: aws.NewJSONRPCClient(sess, "dynamodb", "2012-08-10", "1.0", "DynamoDB_20120810")}
+}
+
+type attributeValue struct {
+	S string `json:",omitempty"`
+	N string `json:",omitempty"`
+}
+
+type item map[string]attributeValue
+
+// attribute names are given as expression placeholders to avoid clashes with DynamoDB reserved words
+type putItemInput struct {
+	TableName                 string
+	Item                      item
+	ConditionExpression       string            `json:",omitempty"`
+	ExpressionAttributeNames  map[string]string `json:",omitempty"`
+	ExpressionAttributeValues item              `json:",omitempty"`
+}
+
+type keyInput struct {
+	TableName                 string
+	Key                       item
+	ConsistentRead            bool              `json:",omitempty"`
+	ConditionExpression       string            `json:",omitempty"`
+	ExpressionAttributeNames  map[string]string `json:",omitempty"`
+	ExpressionAttributeValues item              `json:",omitempty"`
+}
+
+type getItemOutput struct {
+	Item item
+}
+
+// Acquire writes the lock unless a lock on the same key is held and not expired
+func (d *DynamoDB) Acquire(l *Lock) error {
+	input := &putItemInput{
+		TableName:                 d.table,
+		Item:                      toItem(l),
+		ConditionExpression:       "attribute_not_exists(#key) OR #expires < :now",
+		ExpressionAttributeNames:  map[string]string{"#key": "LockID", "#expires": "Expires"},
+		ExpressionAttributeValues: item{":now": number(time.Now().Unix())},
+	}
+	err := d.api.Call("PutItem", input, &struct{}{})
+	if isConditionalCheckFailed(err) {
+		holder, gerr := d.Get(l.Key)
+		if gerr != nil {
+			return &HeldError{}
+		}
+		return &HeldError{Holder: holder}
+	}
+	return err
+}
+
+// Release deletes the lock if it is still the one acquired
+func (d *DynamoDB) Release(l *Lock) error {
+	input := &keyInput{
+		TableName:                 d.table,
+		Key:                       item{"LockID": {S: l.Key}},
+		ConditionExpression:       "#id = :id",
+		ExpressionAttributeNames:  map[string]string{"#id": "ID"},
+		ExpressionAttributeValues: item{":id": {S: l.ID}},
+	}
+	err := d.api.Call("DeleteItem", input, &struct{}{})
+	if isConditionalCheckFailed(err) {
+		return fmt.Errorf("lock %s is not held anymore (forced unlock or expired)", l.Key)
+	}
+	return err
+}
+
+// ForceRelease deletes the lock whoever holds it
+func (d *DynamoDB) ForceRelease(key string) error {
+	return d.api.Call("DeleteItem", &keyInput{TableName: d.table, Key: item{"LockID": {S: key}}}, &struct{}{})
+}
+
+// Get returns the lock on the key, or nil when not locked
+func (d *DynamoDB) Get(key string) (*Lock, error) {
+	out := &getItemOutput{}
+	if err := d.api.Call("GetItem", &keyInput{TableName: d.table, Key: item{"LockID": {S: key}}, ConsistentRead: true}, out); err != nil {
+		return nil, err
+	}
+	if len(out.Item) == 0 {
+		return nil, nil
+	}
+	return fromItem(out.Item), nil
+}
+
+func toItem(l *Lock) item {
+	return item{
+		"LockID":    {S: l.Key},
+		"ID":        {S: l.ID},
+		"Who":       {S: l.Who},
+		"Host":      {S: l.Host},
+		"Operation": {S: l.Operation},
+		"Created":   number(l.Created.Unix()),
+		"Expires":   number(l.Expires.Unix()),
+	}
+}
+
+func fromItem(i item) *Lock {
+	parseTime := func(v attributeValue) time.Time {
+		sec, _ := strconv.ParseInt(v.N, 10, 64)
+		return time.Unix(sec, 0).UTC()
+	}
+	return &Lock{
+		Key: i["LockID"].S, ID: i["ID"].S, Who: i["Who"].S, Host: i["Host"].S, Operation: i["Operation"].S,
+		Created: parseTime(i["Created"]), Expires: parseTime(i["Expires"]),
+	}
+}
+
+func number(n int64) attributeValue {
+	return attributeValue{N: strconv.FormatInt(n, 10)}
+}
+
+func isConditionalCheckFailed(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == conditionalCheckFailed
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
+
+func randomID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// FromConfig returns the DynamoDB lock table set in the given config values, or nil
+func FromConfig(defaults map[string]interface{}) (*DynamoDB, error) {
+	table := configString(defaults, TableKey)
+	if table == "" {
+		return nil, nil
+	}
+	region := configString(defaults, RegionKey)
+	if region == "" {
+		region = configString(defaults, database.RegionKey)
+	}
+	sess, err := aws.InitSession(region, configString(defaults, database.ProfileKey))
+	if err != nil {
+		return nil, err
+	}
+	return NewDynamoDB(sess, table), nil
+}
+
+// Timeout returns the lock timeout set in the given config values
+func Timeout(defaults map[string]interface{}) (time.Duration, error) {
+	value := configString(defaults, TimeoutKey)
+	if value == "" {
+		return DefaultTimeout, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid lock timeout '%s': expecting a duration. Ex: 30m", value)
+	}
+	return d, nil
+}
+
+func configString(defaults map[string]interface{}, key string) string {
+	if v, ok := defaults[key]; ok && v != nil {
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+	return ""
+}
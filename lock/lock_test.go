@@ -0,0 +1,158 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// fakeDynamoDB evaluates the only conditions written by the lock
+type fakeDynamoDB struct {
+	mu    sync.Mutex
+	items map[string]item
+}
+
+func (f *fakeDynamoDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var input struct {
+		TableName                 string
+		Item, Key                 item
+		ConditionExpression       string
+		ExpressionAttributeValues item
+	}
+	json.NewDecoder(r.Body).Decode(&input)
+	conditionFailed := func() {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`))
+	}
+	switch target := r.Header.Get("X-Amz-Target"); target {
+	case "DynamoDB_20120810.PutItem":
+		if existing, ok := f.items[input.Item["LockID"].S]; ok {
+			expires, _ := strconv.ParseInt(existing["Expires"].N, 10, 64)
+			now, _ := strconv.ParseInt(input.ExpressionAttributeValues[":now"].N, 10, 64)
+			if expires >= now {
+				conditionFailed()
+				return
+			}
+		}
+		f.items[input.Item["LockID"].S] = input.Item
+	case "DynamoDB_20120810.GetItem":
+		json.NewEncoder(w).Encode(map[string]interface{}{"Item": f.items[input.Key["LockID"].S]})
+		return
+	case "DynamoDB_20120810.DeleteItem":
+		existing, ok := f.items[input.Key["LockID"].S]
+		if input.ConditionExpression != "" && (!ok || existing["ID"].S != input.ExpressionAttributeValues[":id"].S) {
+			conditionFailed()
+			return
+		}
+		delete(f.items, input.Key["LockID"].S)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"UnknownOperationException"}`))
+		return
+	}
+	w.Write([]byte(`{}`))
+}
+
+func TestLock(t *testing.T) {
+	server := httptest.NewServer(&fakeDynamoDB{items: make(map[string]item)})
+	defer server.Close()
+	sess := session.Must(session.NewSession(&awssdk.Config{
+		Region:      awssdk.String("eu-west-1"),
+		Endpoint:    awssdk.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+		MaxRetries:  awssdk.Int(0),
+	}))
+	table := NewDynamoDB(sess, "locks")
+
+	if l, err := table.Get("123456789012/eu-west-1"); err != nil || l != nil {
+		t.Fatalf("expected no lock, got %v, %v", l, err)
+	}
+
+	alice := New("123456789012", "eu-west-1", "alice", "run web", time.Hour)
+	if err := table.Acquire(alice); err != nil {
+		t.Fatal(err)
+	}
+
+	bob := New("123456789012", "eu-west-1", "bob", "run db", time.Hour)
+	err := table.Acquire(bob)
+	held, ok := err.(*HeldError)
+	if !ok {
+		t.Fatalf("expected held error, got %#v", err)
+	}
+	if got, want := held.Holder.Who, "alice"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := held.Holder.Operation, "run web"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if !held.Holder.Created.Equal(alice.Created) {
+		t.Fatalf("got %s, want %s", held.Holder.Created, alice.Created)
+	}
+	if !strings.Contains(err.Error(), "force-unlock") {
+		t.Fatalf("unexpected error message %s", err)
+	}
+
+	if err := table.Acquire(New("123456789012", "us-east-1", "bob", "run db", time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := table.Release(bob); err == nil {
+		t.Fatal("expected error releasing a lock not held")
+	}
+	if err := table.Release(alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Acquire(bob); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := table.ForceRelease(bob.Key); err != nil {
+		t.Fatal(err)
+	}
+	expired := New("123456789012", "eu-west-1", "carol", "run web", -time.Minute)
+	if err := table.Acquire(expired); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Acquire(alice); err != nil {
+		t.Fatalf("expected expired lock to be acquired: %s", err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	if d, err := Timeout(map[string]interface{}{}); err != nil || d != DefaultTimeout {
+		t.Fatalf("got %s, %v", d, err)
+	}
+	if d, err := Timeout(map[string]interface{}{TimeoutKey: "30m"}); err != nil || d != 30*time.Minute {
+		t.Fatalf("got %s, %v", d, err)
+	}
+	if _, err := Timeout(map[string]interface{}{TimeoutKey: 30}); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -17,11 +17,15 @@ limitations under the License.
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
+	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/fatih/color"
 )
@@ -34,70 +38,137 @@ const (
 	ExtraVerboseF
 )
 
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+)
+
 type Logger struct {
 	verbose uint32 // atomic
+	quiet   uint32 // atomic
+	json    uint32 // atomic
 	out     *log.Logger
 }
 
+type level struct {
+	name  string
+	color color.Attribute
+}
+
 var (
-	infoPrefix         = color.GreenString("[info]")
-	errorPrefix        = color.RedString("[error]")
-	verbosePrefix      = color.YellowString("[verbo]")
-	extraVerbosePrefix = color.MagentaString("[extra]")
+	infoLevel         = level{"info", color.FgGreen}
+	errorLevel        = level{"error", color.FgRed}
+	warningLevel      = level{"warn", color.FgYellow}
+	verboseLevel      = level{"verbo", color.FgYellow}
+	extraVerboseLevel = level{"extra", color.FgMagenta}
 )
 
+// prefix is computed on each log so that disabling colors applies to already created loggers
+func (lvl level) prefix() string {
+	return color.New(lvl.color).SprintFunc()("[" + lvl.name + "]")
+}
+
 func New(prefix string, flag int) *Logger {
 	return &Logger{out: log.New(os.Stdout, prefix, flag)}
 }
 
 func (l *Logger) Verbosef(format string, v ...interface{}) {
 	if l.verbosity() > 0 {
-		l.out.Println(prepend(verbosePrefix, fmt.Sprintf(format, v...))...)
+		l.print(verboseLevel, fmt.Sprintf(format, v...))
 	}
 }
 
 func (l *Logger) Verbose(v ...interface{}) {
 	if l.verbosity() > 0 {
-		l.out.Println(prepend(verbosePrefix, v...)...)
+		l.print(verboseLevel, sprint(v...))
 	}
 }
 
 func (l *Logger) ExtraVerbosef(format string, v ...interface{}) {
 	if l.verbosity() > 1 {
-		l.out.Println(prepend(extraVerbosePrefix, fmt.Sprintf(format, v...))...)
+		l.print(extraVerboseLevel, fmt.Sprintf(format, v...))
 	}
 }
 
 func (l *Logger) ExtraVerbose(v ...interface{}) {
 	if l.verbosity() > 1 {
-		l.out.Println(prepend(extraVerbosePrefix, v...)...)
+		l.print(extraVerboseLevel, sprint(v...))
 	}
 }
 
 func (l *Logger) Info(v ...interface{}) {
-	l.out.Println(prepend(infoPrefix, v...)...)
+	l.print(infoLevel, sprint(v...))
 }
 
 func (l *Logger) Infof(format string, v ...interface{}) {
-	l.out.Println(prepend(infoPrefix, fmt.Sprintf(format, v...))...)
+	l.print(infoLevel, fmt.Sprintf(format, v...))
+}
+
+func (l *Logger) Warning(v ...interface{}) {
+	l.print(warningLevel, sprint(v...))
+}
+
+func (l *Logger) Warningf(format string, v ...interface{}) {
+	l.print(warningLevel, fmt.Sprintf(format, v...))
 }
 
 func (l *Logger) Error(v ...interface{}) {
-	l.out.Println(prepend(errorPrefix, v...)...)
+	l.print(errorLevel, sprint(v...))
 }
 
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	l.out.Println(prepend(errorPrefix, fmt.Sprintf(format, v...))...)
+	l.print(errorLevel, fmt.Sprintf(format, v...))
 }
 
 func (l *Logger) SetVerbose(level int) {
 	atomic.StoreUint32(&l.verbose, uint32(level))
 }
 
+// SetOutput redirects the logs to the given writer
+func (l *Logger) SetOutput(w io.Writer) {
+	l.out.SetOutput(w)
+}
+
+// SetQuiet discards all logs but errors
+func (l *Logger) SetQuiet(quiet bool) {
+	atomic.StoreUint32(&l.quiet, boolToUint32(quiet))
+}
+
+// SetFormat sets the logs format: text (default) or json, one object per line
+func (l *Logger) SetFormat(format string) error {
+	switch format {
+	case TextFormat, "":
+		atomic.StoreUint32(&l.json, 0)
+	case JSONFormat:
+		atomic.StoreUint32(&l.json, 1)
+	default:
+		return fmt.Errorf("unknown log format '%s' (expecting %s or %s)", format, TextFormat, JSONFormat)
+	}
+	return nil
+}
+
 func (l *Logger) verbosity() uint32 {
 	return atomic.LoadUint32(&l.verbose)
 }
 
+func (l *Logger) print(lvl level, msg string) {
+	if atomic.LoadUint32(&l.quiet) == 1 && lvl != errorLevel {
+		return
+	}
+	if atomic.LoadUint32(&l.json) == 1 {
+		b, err := json.Marshal(struct {
+			Time  time.Time `json:"time"`
+			Level string    `json:"level"`
+			Msg   string    `json:"msg"`
+		}{time.Now().UTC(), lvl.name, msg})
+		if err == nil {
+			l.out.Println(string(b))
+			return
+		}
+	}
+	l.out.Println(lvl.prefix(), msg)
+}
+
 func Verbosef(format string, v ...interface{}) {
 	DefaultLogger.Verbosef(format, v...)
 }
@@ -122,6 +193,14 @@ func Infof(format string, v ...interface{}) {
 	DefaultLogger.Infof(format, v...)
 }
 
+func Warning(v ...interface{}) {
+	DefaultLogger.Warning(v...)
+}
+
+func Warningf(format string, v ...interface{}) {
+	DefaultLogger.Warningf(format, v...)
+}
+
 func Error(v ...interface{}) {
 	DefaultLogger.Error(v...)
 }
@@ -130,6 +209,13 @@ func Errorf(format string, v ...interface{}) {
 	DefaultLogger.Errorf(format, v...)
 }
 
-func prepend(s interface{}, v ...interface{}) []interface{} {
-	return append([]interface{}{s}, v...)
+func sprint(v ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(v...), "\n")
+}
+
+func boolToUint32(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
 }
@@ -0,0 +1,82 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestLoggerOutputControls(t *testing.T) {
+	color.NoColor = true
+	var buff bytes.Buffer
+	l := &Logger{out: log.New(&buff, "", 0)}
+
+	l.Info("hello", "world")
+	l.Verbose("not shown")
+	if got, want := buff.String(), "[info] hello world\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	buff.Reset()
+	l.SetQuiet(true)
+	l.Infof("not shown %d", 1)
+	l.Errorf("failed %d", 2)
+	if got, want := buff.String(), "[error] failed 2\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	buff.Reset()
+	l.SetQuiet(false)
+	if err := l.SetFormat("json"); err != nil {
+		t.Fatal(err)
+	}
+	l.Info("hello")
+	var entry map[string]string
+	if err := json.Unmarshal(buff.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := entry["level"], "info"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := entry["msg"], "hello"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if !strings.HasSuffix(buff.String(), "}\n") {
+		t.Fatalf("expected one json object per line, got %q", buff.String())
+	}
+
+	if err := l.SetFormat("xml"); err == nil {
+		t.Fatal("expected error got none")
+	}
+
+	var other bytes.Buffer
+	buff.Reset()
+	l.SetOutput(&other)
+	l.Error("redirected")
+	if got, want := buff.Len(), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if !strings.Contains(other.String(), "redirected") {
+		t.Fatalf("expected log in redirected output, got %q", other.String())
+	}
+}
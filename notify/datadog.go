@@ -0,0 +1,192 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// DatadogAPIKeyKey is the config key of the Datadog API key used to post events
+	DatadogAPIKeyKey = "notify.datadog.apikey"
+	// DatadogSiteKey is the config key of the Datadog site. Ex: datadoghq.eu (default: datadoghq.com)
+	DatadogSiteKey = "notify.datadog.site"
+	// DatadogTagsKey is the config key of comma separated tags added to all events. Ex: team:ops,env:prod
+	DatadogTagsKey = "notify.datadog.tags"
+
+	defaultDatadogSite = "datadoghq.com"
+
+	datadogMaxTitle = 100
+	datadogMaxText  = 4000
+	datadogMaxTags  = 20
+)
+
+type datadog struct {
+	url, apiKey string
+	tags        []string
+	client      *http.Client
+}
+
+func NewDatadog(apiKey, site string, tags ...string) Notifier {
+	if site == "" {
+		site = defaultDatadogSite
+	}
+	return &datadog{
+		url:    fmt.Sprintf("https://api.%s/api/v1/events", strings.TrimPrefix(site, "api.")),
+		apiKey: apiKey,
+		tags:   tags,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (d *datadog) Name() string { return "datadog" }
+
+type datadogEvent struct {
+	Title          string   `json:"title"`
+	Text           string   `json:"text"`
+	DateHappened   int64    `json:"date_happened"`
+	AlertType      string   `json:"alert_type"`
+	Priority       string   `json:"priority"`
+	AggregationKey string   `json:"aggregation_key,omitempty"`
+	SourceTypeName string   `json:"source_type_name"`
+	Tags           []string `json:"tags"`
+}
+
+func (d *datadog) Notify(e *Event) error {
+	body, err := json.Marshal(d.event(e))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", d.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("DD-API-KEY", d.apiKey)
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%d %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (d *datadog) event(e *Event) *datadogEvent {
+	ev := &datadogEvent{
+		Title:          truncate(e.Summary(), datadogMaxTitle),
+		DateHappened:   e.Time.Unix(),
+		AlertType:      "info",
+		Priority:       "normal",
+		SourceTypeName: "awless",
+		Tags:           datadogTags(e, d.tags),
+	}
+	switch e.Type {
+	case RunSucceeded, RunReverted:
+		ev.AlertType = "success"
+	case RunFailed:
+		ev.AlertType = "error"
+	case Drift:
+		if e.Drift != nil && !e.Drift.Empty() {
+			ev.AlertType = "warning"
+		}
+		ev.AggregationKey = "awless-drift"
+	}
+	if e.RunID != "" {
+		ev.AggregationKey = "awless-run-" + e.RunID
+	}
+
+	var text bytes.Buffer
+	if e.Template != "" {
+		fmt.Fprintf(&text, "```\n%s\n```\n", e.Template)
+	}
+	writeResources := func(title string, resources []Resource) {
+		if len(resources) == 0 {
+			return
+		}
+		fmt.Fprintf(&text, "**%s**\n", title)
+		for i, r := range resources {
+			if i == maxListedResources {
+				fmt.Fprintf(&text, "- ... and %d more\n", len(resources)-maxListedResources)
+				break
+			}
+			label := fmt.Sprintf("%s %s", r.Type, r.Id)
+			if r.Link != "" {
+				label = fmt.Sprintf("%s [%s](%s)", r.Type, r.Id, r.Link)
+			}
+			if r.By != "" {
+				label += " by " + r.By
+			}
+			fmt.Fprintf(&text, "- %s\n", label)
+		}
+	}
+	writeResources("Created", e.Created)
+	if len(e.Errors) > 0 {
+		fmt.Fprintf(&text, "**Errors**\n")
+		for _, err := range e.Errors {
+			fmt.Fprintf(&text, "- %s\n", err)
+		}
+	}
+	if e.Drift != nil {
+		writeResources("Created", e.Drift.Created)
+		writeResources("Deleted", e.Drift.Deleted)
+		writeResources("Modified", e.Drift.Modified)
+	}
+	if text.Len() > 0 {
+		ev.Text = "%%% \n" + truncate(text.String(), datadogMaxText-10) + "\n %%%"
+	}
+	return ev
+}
+
+var invalidTagChars = regexp.MustCompile(`[^a-z0-9_\-:./]`)
+
+func datadogTag(key, value string) string {
+	return invalidTagChars.ReplaceAllString(strings.ToLower(fmt.Sprintf("%s:%s", key, value)), "_")
+}
+
+// datadogTags tags the event with its origin so that it can be filtered and
+// overlaid on dashboards. Ex: account:123456789012, region:eu-west-1, template:create_instance
+func datadogTags(e *Event, extra []string) []string {
+	tags := []string{"source:awless", datadogTag("event", string(e.Type))}
+	for _, kv := range [][2]string{{"account", e.Account}, {"region", e.Region}, {"context", e.Context}, {"template", e.TemplateName}} {
+		if kv[1] != "" {
+			tags = append(tags, datadogTag(kv[0], kv[1]))
+		}
+	}
+	if e.RunID != "" {
+		tags = append(tags, datadogTag("run", e.RunID))
+	}
+	for _, t := range extra {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	if len(tags) > datadogMaxTags {
+		tags = tags[:datadogMaxTags]
+	}
+	return tags
+}
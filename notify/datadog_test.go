@@ -0,0 +1,102 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDatadogNotify(t *testing.T) {
+	var received datadogEvent
+	var apiKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("DD-API-KEY")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	n := NewDatadog("secretkey", "", "team:ops", " ").(*datadog)
+	if got, want := n.url, "https://api.datadoghq.com/api/v1/events"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	n.url = server.URL
+
+	e := &Event{
+		Type: RunFailed, Time: time.Unix(1500000000, 0), RunID: "01B", Template: "create instance name=web",
+		TemplateName: "Web Server", Account: "123456789012", Region: "eu-west-1",
+		Created: []Resource{{Id: "i-1", Type: "instance", Link: "https://console/i-1"}}, Errors: []string{"create instance: denied"},
+	}
+	if err := n.Notify(e); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := apiKey, "secretkey"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := received.Title, "awless run 01B failed in eu-west-1: create instance: denied"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := received.AlertType, "error"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := received.DateHappened, int64(1500000000); got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := received.AggregationKey, "awless-run-01B"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := received.Tags, []string{"source:awless", "event:run.failed", "account:123456789012", "region:eu-west-1", "template:web_server", "run:01b", "team:ops"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for _, want := range []string{"%%%", "create instance name=web", "- instance [i-1](https://console/i-1)", "- create instance: denied"} {
+		if !strings.Contains(received.Text, want) {
+			t.Fatalf("expected %q in %s", want, received.Text)
+		}
+	}
+}
+
+func TestDatadogDriftEvent(t *testing.T) {
+	n := NewDatadog("key", "datadoghq.eu").(*datadog)
+	if got, want := n.url, "https://api.datadoghq.eu/api/v1/events"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	ev := n.event(&Event{Type: Drift, Drift: &DriftReport{}})
+	if got, want := ev.AlertType, "info"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := ev.Text, ""; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	ev = n.event(&Event{Type: Drift, Drift: &DriftReport{Deleted: []Resource{{Id: "i-1", Type: "instance"}}}})
+	if got, want := ev.AlertType, "warning"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := ev.AggregationKey, "awless-drift"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if !strings.Contains(ev.Text, "**Deleted**\n- instance i-1") {
+		t.Fatalf("unexpected text %s", ev.Text)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/wallix/awless/graph"
+)
+
+// DriftReport lists the resources created, deleted and modified between two graphs
+type DriftReport struct {
+	Created  []Resource `json:"created"`
+	Deleted  []Resource `json:"deleted"`
+	Modified []Resource `json:"modified"`
+}
+
+// NewDriftReport compares the resources of the given types in two graphs
+func NewDriftReport(from, to *graph.Graph, types ...string) (*DriftReport, error) {
+	report := &DriftReport{}
+	for _, t := range types {
+		before, err := resourcesById(from, t)
+		if err != nil {
+			return nil, err
+		}
+		after, err := resourcesById(to, t)
+		if err != nil {
+			return nil, err
+		}
+		for id, res := range after {
+			old, ok := before[id]
+			switch {
+			case !ok:
+				report.Created = append(report.Created, Resource{Id: id, Type: t})
+			case !reflect.DeepEqual(old.Properties, res.Properties):
+				report.Modified = append(report.Modified, Resource{Id: id, Type: t, Changes: graph.PropertiesDiff(old.Properties, res.Properties)})
+			}
+		}
+		for id := range before {
+			if _, ok := after[id]; !ok {
+				report.Deleted = append(report.Deleted, Resource{Id: id, Type: t})
+			}
+		}
+	}
+	for _, list := range [][]Resource{report.Created, report.Deleted, report.Modified} {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Type != list[j].Type {
+				return list[i].Type < list[j].Type
+			}
+			return list[i].Id < list[j].Id
+		})
+	}
+	return report, nil
+}
+
+func (r *DriftReport) Empty() bool {
+	return len(r.Created) == 0 && len(r.Deleted) == 0 && len(r.Modified) == 0
+}
+
+func resourcesById(g *graph.Graph, t string) (map[string]*graph.Resource, error) {
+	resources, err := g.GetAllResources(graph.ResourceType(t))
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[string]*graph.Resource)
+	for _, res := range resources {
+		byId[res.Id()] = res
+	}
+	return byId, nil
+}
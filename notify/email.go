@@ -0,0 +1,162 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+const (
+	// EmailToKey is the config key of the comma separated recipients of the emails. Ex: ops@example.com
+	EmailToKey = "notify.email.to"
+	// EmailFromKey is the config key of the sender of the emails
+	EmailFromKey = "notify.email.from"
+	// EmailSMTPKey is the config key of the SMTP server (with STARTTLS support for authentication).
+	// Ex: email-smtp.eu-west-1.amazonaws.com:587 for Amazon SES
+	EmailSMTPKey = "notify.email.smtp"
+	// EmailUsernameKey and EmailPasswordKey are the config keys of the SMTP credentials, if any
+	EmailUsernameKey = "notify.email.username"
+	EmailPasswordKey = "notify.email.password"
+	// EmailEventsKey is the config key of the comma separated event types sent by email (default: drift)
+	EmailEventsKey = "notify.email.events"
+
+	defaultSMTPPort = "25"
+)
+
+type email struct {
+	addr, from string
+	to         []string
+	auth       smtp.Auth
+	events     map[EventType]bool
+	send       func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmail returns a notifier mailing the given types of events (drift digests by default)
+func NewEmail(addr, from string, to []string, username, password string, events ...EventType) Notifier {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, defaultSMTPPort)
+	}
+	n := &email{addr: addr, from: from, events: make(map[EventType]bool), send: smtp.SendMail}
+	for _, t := range to {
+		if t = strings.TrimSpace(t); t != "" {
+			n.to = append(n.to, t)
+		}
+	}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		n.auth = smtp.PlainAuth("", username, password, host)
+	}
+	if len(events) == 0 {
+		events = []EventType{Drift}
+	}
+	for _, e := range events {
+		n.events[e] = true
+	}
+	return n
+}
+
+// ValidateAddresses checks the given comma separated email addresses
+func ValidateAddresses(value string) error {
+	if _, err := mail.ParseAddressList(value); err != nil {
+		return fmt.Errorf("invalid email addresses '%s': %s", value, err)
+	}
+	return nil
+}
+
+func (m *email) Name() string { return "email " + strings.Join(m.to, ",") }
+
+func (m *email) Notify(e *Event) error {
+	if !m.events[e.Type] {
+		return nil
+	}
+	if len(m.to) == 0 {
+		return fmt.Errorf("no recipients")
+	}
+	return m.send(m.addr, m.auth, m.from, m.to, m.message(e))
+}
+
+func (m *email) message(e *Event) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(m.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", e.Summary()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", e.Time.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.Replace(emailBody(e), "\n", "\r\n", -1))
+	return msg.Bytes()
+}
+
+func emailBody(e *Event) string {
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "%s\n", e.Summary())
+	for _, kv := range [][2]string{{"Account", e.Account}, {"Region", e.Region}, {"Context", e.Context}, {"Run", e.RunID}} {
+		if kv[1] != "" {
+			fmt.Fprintf(&body, "%s: %s\n", kv[0], kv[1])
+		}
+	}
+	writeResources := func(title string, resources []Resource) {
+		if len(resources) == 0 {
+			return
+		}
+		fmt.Fprintf(&body, "\n%s (%d):\n", title, len(resources))
+		for _, r := range resources {
+			line := fmt.Sprintf("  %s %s", r.Type, r.Id)
+			if r.By != "" {
+				line += " by " + r.By
+			}
+			if r.Link != "" {
+				line += "\n    " + r.Link
+			}
+			for _, c := range r.Changes {
+				switch {
+				case c.IsAdded():
+					line += fmt.Sprintf("\n    + %s: %v", c.Name, c.New)
+				case c.IsRemoved():
+					line += fmt.Sprintf("\n    - %s: %v", c.Name, c.Old)
+				default:
+					line += fmt.Sprintf("\n    ~ %s: %v -> %v", c.Name, c.Old, c.New)
+				}
+			}
+			fmt.Fprintln(&body, line)
+		}
+	}
+	if d := e.Drift; d != nil {
+		writeResources("Created", d.Created)
+		writeResources("Deleted", d.Deleted)
+		writeResources("Modified", d.Modified)
+	}
+	writeResources("Created", e.Created)
+	if len(e.Errors) > 0 {
+		fmt.Fprintf(&body, "\nErrors:\n")
+		for _, err := range e.Errors {
+			fmt.Fprintf(&body, "  %s\n", err)
+		}
+	}
+	if e.Template != "" {
+		fmt.Fprintf(&body, "\nTemplate:\n%s\n", e.Template)
+	}
+	return body.String()
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"net/smtp"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmailNotify(t *testing.T) {
+	var sent []string
+	var gotAddr, gotFrom string
+	var gotTo []string
+	n := NewEmail("email-smtp.eu-west-1.amazonaws.com:587", "awless@example.com", []string{"ops@example.com", " dev@example.com"}, "user", "pass").(*email)
+	n.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		if a == nil {
+			t.Fatal("expected auth")
+		}
+		gotAddr, gotFrom, gotTo = addr, from, to
+		sent = append(sent, string(msg))
+		return nil
+	}
+
+	if err := n.Notify(&Event{Type: RunSucceeded, RunID: "01B"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(sent), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+
+	drift := &Event{Type: Drift, Time: time.Date(2017, 7, 14, 6, 0, 0, 0, time.UTC), Account: "123456789012", Region: "eu-west-1", Drift: &DriftReport{
+		Created:  []Resource{{Id: "i-1", Type: "instance", By: "alice"}},
+		Deleted:  []Resource{{Id: "AIDA1", Type: "user"}},
+		Modified: []Resource{{Id: "sg-1", Type: "securitygroup", By: "bob"}},
+	}}
+	if err := n.Notify(drift); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := gotAddr, "email-smtp.eu-west-1.amazonaws.com:587"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := gotFrom, "awless@example.com"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := gotTo, []string{"ops@example.com", "dev@example.com"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for _, want := range []string{
+		"Subject: awless drift digest in eu-west-1: 1 created, 1 deleted, 1 modified\r\n",
+		"To: ops@example.com, dev@example.com\r\n",
+		"Account: 123456789012\r\n",
+		"Created (1):\r\n  instance i-1 by alice\r\n",
+		"Deleted (1):\r\n  user AIDA1\r\n",
+		"Modified (1):\r\n  securitygroup sg-1 by bob\r\n",
+	} {
+		if !strings.Contains(sent[0], want) {
+			t.Fatalf("expected %q in\n%s", want, sent[0])
+		}
+	}
+}
+
+func TestEmailDefaults(t *testing.T) {
+	n := NewEmail("localhost", "awless@example.com", []string{"ops@example.com"}, "", "", RunFailed).(*email)
+	if got, want := n.addr, "localhost:25"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if n.auth != nil {
+		t.Fatal("expected no auth")
+	}
+	if got, want := n.events, map[EventType]bool{RunFailed: true}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if err := ValidateAddresses("ops@example.com, Dev Team <dev@example.com>"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateAddresses("ops"); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notify sends awless activity (template runs, drift) to external systems
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/graph"
+)
+
+type EventType string
+
+const (
+	RunStarted   EventType = "run.started"
+	RunSucceeded EventType = "run.succeeded"
+	RunFailed    EventType = "run.failed"
+	RunReverted  EventType = "run.reverted"
+	Drift        EventType = "drift"
+)
+
+// Event describes an awless activity to notify
+type Event struct {
+	Type         EventType    `json:"type"`
+	Time         time.Time    `json:"time"`
+	RunID        string       `json:"runId,omitempty"`
+	Reverted     string       `json:"revertedRunId,omitempty"`
+	Template     string       `json:"template,omitempty"`
+	TemplateName string       `json:"templateName,omitempty"`
+	Account      string       `json:"account,omitempty"`
+	Region       string       `json:"region,omitempty"`
+	Context      string       `json:"context,omitempty"`
+	Created      []Resource   `json:"created,omitempty"`
+	Errors       []string     `json:"errors,omitempty"`
+	Drift        *DriftReport `json:"drift,omitempty"`
+	// Log lists the executed statements of the run with their result or error
+	Log []string `json:"log,omitempty"`
+	// Unattended is set for runs without a user at the terminal. Ex: with --ci
+	Unattended bool `json:"unattended,omitempty"`
+}
+
+// Resource is a cloud resource referenced by an event, with its web console link if any
+type Resource struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+	Link string `json:"link,omitempty"`
+	// By is the user who last changed the resource, when known. Ex: from CloudTrail
+	By string `json:"by,omitempty"`
+	// Changes are the property changes of a modified resource
+	Changes []graph.PropertyChange `json:"changes,omitempty"`
+}
+
+// Summary is the one line description of the event
+func (e *Event) Summary() string {
+	var where string
+	if e.Region != "" {
+		where = " in " + e.Region
+	}
+	if e.Context != "" {
+		where += fmt.Sprintf(" (context %s)", e.Context)
+	}
+	switch e.Type {
+	case RunStarted:
+		return fmt.Sprintf("awless run %s started%s", e.RunID, where)
+	case RunSucceeded:
+		summary := fmt.Sprintf("awless run %s succeeded%s", e.RunID, where)
+		if len(e.Created) > 0 {
+			summary += fmt.Sprintf(": %d resource(s) created", len(e.Created))
+		}
+		return summary
+	case RunReverted:
+		return fmt.Sprintf("awless run %s reverted run %s%s", e.RunID, e.Reverted, where)
+	case RunFailed:
+		if e.Reverted != "" {
+			return fmt.Sprintf("awless revert of run %s failed%s: %s", e.Reverted, where, strings.Join(e.Errors, "; "))
+		}
+		return fmt.Sprintf("awless run %s failed%s: %s", e.RunID, where, strings.Join(e.Errors, "; "))
+	case Drift:
+		if e.Drift == nil || e.Drift.Empty() {
+			return fmt.Sprintf("awless drift digest%s: no changes", where)
+		}
+		return fmt.Sprintf("awless drift digest%s: %d created, %d deleted, %d modified", where, len(e.Drift.Created), len(e.Drift.Deleted), len(e.Drift.Modified))
+	default:
+		return fmt.Sprintf("awless %s%s", e.Type, where)
+	}
+}
+
+// Notifier sends events to an external system
+type Notifier interface {
+	Name() string
+	Notify(*Event) error
+}
+
+// FromConfig returns the notifiers configured in the given config values
+func FromConfig(defaults map[string]interface{}) []Notifier {
+	var notifiers []Notifier
+	if webhook := configString(defaults, SlackWebhookKey); webhook != "" {
+		notifiers = append(notifiers, NewSlack(webhook, configString(defaults, SlackChannelKey)))
+	}
+	secret := configString(defaults, SecretKey)
+	for _, endpoint := range strings.Split(configString(defaults, WebhookURLsKey), ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			notifiers = append(notifiers, NewWebhook(endpoint, secret))
+		}
+	}
+	if topic := configString(defaults, SNSTopicKey); topic != "" {
+		profile, _ := defaults[database.ProfileKey].(string)
+		notifiers = append(notifiers, NewSNS(topic, profile, secret))
+	}
+	if apiKey := configString(defaults, DatadogAPIKeyKey); apiKey != "" {
+		notifiers = append(notifiers, NewDatadog(apiKey, configString(defaults, DatadogSiteKey), strings.Split(configString(defaults, DatadogTagsKey), ",")...))
+	}
+	if to := configString(defaults, EmailToKey); to != "" {
+		var events []EventType
+		for _, t := range strings.Split(configString(defaults, EmailEventsKey), ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				events = append(events, EventType(t))
+			}
+		}
+		notifiers = append(notifiers, NewEmail(configString(defaults, EmailSMTPKey), configString(defaults, EmailFromKey), strings.Split(to, ","),
+			configString(defaults, EmailUsernameKey), configString(defaults, EmailPasswordKey), events...))
+	}
+	if routingKey := configString(defaults, PagerDutyRoutingKeyKey); routingKey != "" {
+		notifiers = append(notifiers, NewPagerDuty(routingKey, configString(defaults, PagerDutyInteractiveKey) == "true"))
+	}
+	return notifiers
+}
+
+// Send notifies the event to all notifiers, returning their errors
+func Send(notifiers []Notifier, e *Event) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	var errs []string
+	for _, n := range notifiers {
+		if err := n.Notify(e); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", n.Name(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ValidateURL checks the given notifier endpoint is an absolute http(s) URL
+func ValidateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid notifier url '%s': %s", value, err)
+	}
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid notifier url '%s': expecting http(s)://host/...", value)
+	}
+	return nil
+}
+
+func configString(defaults map[string]interface{}, key string) string {
+	if v, ok := defaults[key]; ok && v != nil {
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+	return ""
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wallix/awless/graph"
+)
+
+func TestEventSummary(t *testing.T) {
+	tcases := []struct {
+		event *Event
+		exp   string
+	}{
+		{&Event{Type: RunStarted, RunID: "01B", Region: "eu-west-1"}, "awless run 01B started in eu-west-1"},
+		{&Event{Type: RunSucceeded, RunID: "01B", Region: "eu-west-1", Context: "prod", Created: []Resource{{Id: "i-1"}}}, "awless run 01B succeeded in eu-west-1 (context prod): 1 resource(s) created"},
+		{&Event{Type: RunFailed, RunID: "01B", Errors: []string{"create instance: denied", "timeout"}}, "awless run 01B failed: create instance: denied; timeout"},
+		{&Event{Type: Drift, Drift: &DriftReport{}}, "awless drift digest: no changes"},
+		{&Event{Type: Drift, Drift: &DriftReport{Deleted: []Resource{{Id: "i-1"}}, Modified: []Resource{{Id: "i-2"}}}}, "awless drift digest: 0 created, 1 deleted, 1 modified"},
+	}
+	for i, tcase := range tcases {
+		if got, want := tcase.event.Summary(), tcase.exp; got != want {
+			t.Fatalf("%d: got %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestDriftReport(t *testing.T) {
+	from, to := graph.NewGraph(), graph.NewGraph()
+	for _, res := range []*graph.Resource{instance("i-1", "running"), instance("i-2", "running"), instance("i-3", "running")} {
+		from.AddResource(res)
+	}
+	for _, res := range []*graph.Resource{instance("i-1", "running"), instance("i-3", "stopped"), instance("i-4", "running")} {
+		to.AddResource(res)
+	}
+	vpc := graph.InitResource("vpc-1", graph.Vpc)
+	to.AddResource(vpc)
+
+	report, err := NewDriftReport(from, to, "instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := &DriftReport{
+		Created:  []Resource{{Id: "i-4", Type: "instance"}},
+		Deleted:  []Resource{{Id: "i-2", Type: "instance"}},
+		Modified: []Resource{{Id: "i-3", Type: "instance", Changes: []graph.PropertyChange{{Name: "State", Old: "running", New: "stopped"}}}},
+	}
+	if got, want := report, exp; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	report, err = NewDriftReport(from, from, "instance", "vpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := report.Empty(), true; got != want {
+		t.Fatalf("got %t, want %t", got, want)
+	}
+}
+
+func TestSlackNotify(t *testing.T) {
+	var received slackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Content-Type"), "application/json"; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	notifiers := FromConfig(map[string]interface{}{SlackWebhookKey: server.URL, SlackChannelKey: "#ops"})
+	if got, want := len(notifiers), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	event := &Event{
+		Type:     RunSucceeded,
+		RunID:    "01B",
+		Template: "create instance name=web",
+		Created:  []Resource{{Id: "i-1", Type: "instance", Link: "https://console.aws.amazon.com/ec2/i-1"}},
+	}
+	if err := Send(notifiers, event); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := received.Channel, "#ops"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := received.Text, "awless run 01B succeeded: 1 resource(s) created"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := len(received.Attachments), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	attachment := received.Attachments[0]
+	if got, want := attachment.Color, "good"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := attachment.Text, "```create instance name=web```"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	exp := []slackField{{Title: "Created", Value: "instance <https://console.aws.amazon.com/ec2/i-1|i-1>"}}
+	if got, want := attachment.Fields, exp; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestSlackNotifyError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := Send([]Notifier{NewSlack(server.URL, "")}, &Event{Type: RunStarted})
+	if err == nil {
+		t.Fatal("expected error got none")
+	}
+	if got, want := err.Error(), "slack: 403 invalid_token"; !strings.Contains(got, want) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := len(FromConfig(map[string]interface{}{})), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
+
+func instance(id, state string) *graph.Resource {
+	res := graph.InitResource(id, graph.Instance)
+	res.Properties["State"] = state
+	return res
+}
+
+func TestValidateURL(t *testing.T) {
+	for _, valid := range []string{"https://hooks.slack.com/services/T0/B0/xyz", "http://localhost:8080/hook"} {
+		if err := ValidateURL(valid); err != nil {
+			t.Fatalf("%s: %s", valid, err)
+		}
+	}
+	for _, invalid := range []string{"hooks.slack.com/services", "ftp://host/file", "https://"} {
+		if err := ValidateURL(invalid); err == nil {
+			t.Fatalf("%s: expected error got none", invalid)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// PagerDutyRoutingKeyKey is the config key of the integration key of a PagerDuty Events API v2 service
+	PagerDutyRoutingKeyKey = "notify.pagerduty.routingkey"
+	// PagerDutyInteractiveKey is the config key enabling incidents for runs failing in an interactive terminal too
+	// (by default only unattended runs, ex: with --ci from cron or pipelines, open incidents)
+	PagerDutyInteractiveKey = "notify.pagerduty.interactive"
+
+	pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+)
+
+type pagerDuty struct {
+	url, routingKey string
+	interactive     bool
+	client          *http.Client
+}
+
+func NewPagerDuty(routingKey string, interactive bool) Notifier {
+	return &pagerDuty{url: pagerDutyEventsURL, routingKey: routingKey, interactive: interactive, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (p *pagerDuty) Name() string { return "pagerduty" }
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp,omitempty"`
+	Component     string                 `json:"component,omitempty"`
+	Group         string                 `json:"group,omitempty"`
+	Class         string                 `json:"class,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key,omitempty"`
+	Client      string            `json:"client"`
+	Payload     *pagerDutyPayload `json:"payload"`
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+// Notify opens an incident for failed runs and reverts. Other events are ignored
+func (p *pagerDuty) Notify(e *Event) error {
+	if e.Type != RunFailed || !(e.Unattended || p.interactive) {
+		return nil
+	}
+	body, err := json.Marshal(p.event(e))
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Post(p.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%d %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (p *pagerDuty) event(e *Event) *pagerDutyEvent {
+	payload := &pagerDutyPayload{
+		Summary:   truncate(e.Summary(), 1024),
+		Source:    "awless",
+		Severity:  "error",
+		Timestamp: e.Time.UTC().Format(time.RFC3339),
+		Component: e.TemplateName,
+		Group:     e.Region,
+		Class:     "run.failed",
+	}
+	switch {
+	case e.Account != "" && e.Region != "":
+		payload.Source = fmt.Sprintf("%s/%s", e.Account, e.Region)
+	case e.Region != "":
+		payload.Source = e.Region
+	}
+	dedup := "awless-run-" + e.RunID
+	if e.Reverted != "" {
+		// resources may be left behind: a revert not completing is more urgent than a failed run
+		payload.Severity = "critical"
+		payload.Class = "revert.failed"
+		dedup = "awless-revert-" + e.Reverted
+	}
+
+	details := make(map[string]interface{})
+	for k, v := range map[string]string{"run": e.RunID, "reverted run": e.Reverted, "account": e.Account, "region": e.Region, "context": e.Context, "template": e.Template} {
+		if v != "" {
+			details[k] = v
+		}
+	}
+	if len(e.Errors) > 0 {
+		details["errors"] = e.Errors
+	}
+	if len(e.Log) > 0 {
+		details["log"] = e.Log
+	}
+	if len(e.Created) > 0 {
+		var created []string
+		for _, r := range e.Created {
+			created = append(created, fmt.Sprintf("%s %s", r.Type, r.Id))
+		}
+		details["created"] = created
+	}
+	payload.CustomDetails = details
+
+	var links []pagerDutyLink
+	for _, r := range e.Created {
+		if r.Link != "" {
+			links = append(links, pagerDutyLink{Href: r.Link, Text: fmt.Sprintf("%s %s", r.Type, r.Id)})
+		}
+	}
+
+	return &pagerDutyEvent{RoutingKey: p.routingKey, EventAction: "trigger", DedupKey: dedup, Client: "awless", Payload: payload, Links: links}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPagerDutyNotify(t *testing.T) {
+	var received []*pagerDutyEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ev := &pagerDutyEvent{}
+		if err := json.NewDecoder(r.Body).Decode(ev); err != nil {
+			t.Fatal(err)
+		}
+		received = append(received, ev)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	n := NewPagerDuty("routing", false).(*pagerDuty)
+	n.url = server.URL
+
+	failed := &Event{
+		Type: RunFailed, Time: time.Date(2017, 7, 14, 2, 40, 0, 0, time.UTC), RunID: "01B", TemplateName: "web", Account: "123456789012", Region: "eu-west-1",
+		Errors: []string{"create instance: denied"}, Log: []string{"OK create subnet <- sub-1", "KO create instance: denied"},
+		Created: []Resource{{Id: "sub-1", Type: "subnet", Link: "https://console/sub-1"}},
+	}
+	for _, e := range []*Event{failed, {Type: RunSucceeded, RunID: "01C", Unattended: true}, {Type: RunStarted, Unattended: true}} {
+		if err := n.Notify(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := len(received), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+
+	failed.Unattended = true
+	if err := n.Notify(failed); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(received), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	ev := received[0]
+	if got, want := ev.RoutingKey, "routing"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := ev.EventAction, "trigger"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := ev.DedupKey, "awless-run-01B"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := ev.Payload.Summary, "awless run 01B failed in eu-west-1: create instance: denied"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := ev.Payload.Source, "123456789012/eu-west-1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := ev.Payload.Severity, "error"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := ev.Payload.Timestamp, "2017-07-14T02:40:00Z"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := ev.Payload.CustomDetails["log"], []interface{}{"OK create subnet <- sub-1", "KO create instance: denied"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := ev.Links, []pagerDutyLink{{Href: "https://console/sub-1", Text: "subnet sub-1"}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestPagerDutyRevertFailure(t *testing.T) {
+	n := NewPagerDuty("routing", true).(*pagerDuty)
+	ev := n.event(&Event{Type: RunFailed, Reverted: "01A", Region: "us-east-1", Errors: []string{"not revertible"}})
+	if got, want := ev.DedupKey, "awless-revert-01A"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := ev.Payload.Severity, "critical"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := ev.Payload.Summary, "awless revert of run 01A failed in us-east-1: not revertible"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := ev.Payload.Source, "us-east-1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// SlackWebhookKey is the config key of the Slack incoming webhook URL
+	SlackWebhookKey = "notify.slack.webhook"
+	// SlackChannelKey is the config key overriding the channel of the webhook. Ex: #ops
+	SlackChannelKey = "notify.slack.channel"
+
+	maxListedResources = 20
+)
+
+type slack struct {
+	webhook, channel string
+	client           *http.Client
+}
+
+func NewSlack(webhook, channel string) Notifier {
+	return &slack{webhook: webhook, channel: channel, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *slack) Name() string { return "slack" }
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+type slackAttachment struct {
+	Color    string       `json:"color,omitempty"`
+	Text     string       `json:"text,omitempty"`
+	Fields   []slackField `json:"fields,omitempty"`
+	Ts       int64        `json:"ts,omitempty"`
+	Markdown []string     `json:"mrkdwn_in,omitempty"`
+}
+
+type slackMessage struct {
+	Channel     string            `json:"channel,omitempty"`
+	Username    string            `json:"username"`
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+func (s *slack) Notify(e *Event) error {
+	body, err := json.Marshal(s.message(e))
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%d %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (s *slack) message(e *Event) *slackMessage {
+	attachment := slackAttachment{Ts: e.Time.Unix(), Markdown: []string{"text", "fields"}}
+	switch e.Type {
+	case RunStarted:
+		attachment.Color = "#439FE0"
+	case RunSucceeded:
+		attachment.Color = "good"
+	case RunFailed:
+		attachment.Color = "danger"
+	case Drift:
+		attachment.Color = "warning"
+	}
+	if e.Template != "" {
+		attachment.Text = "```" + e.Template + "```"
+	}
+	if len(e.Created) > 0 {
+		attachment.Fields = append(attachment.Fields, slackField{Title: "Created", Value: slackResources(e.Created)})
+	}
+	if len(e.Errors) > 0 {
+		attachment.Fields = append(attachment.Fields, slackField{Title: "Errors", Value: strings.Join(e.Errors, "\n")})
+	}
+	if d := e.Drift; d != nil {
+		for _, f := range []struct {
+			title     string
+			resources []Resource
+		}{{"Created", d.Created}, {"Deleted", d.Deleted}, {"Modified", d.Modified}} {
+			if len(f.resources) > 0 {
+				attachment.Fields = append(attachment.Fields, slackField{Title: f.title, Value: slackResources(f.resources), Short: true})
+			}
+		}
+	}
+	return &slackMessage{Channel: s.channel, Username: "awless", Text: e.Summary(), Attachments: []slackAttachment{attachment}}
+}
+
+func slackResources(resources []Resource) string {
+	var lines []string
+	for i, r := range resources {
+		if i == maxListedResources {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(resources)-maxListedResources))
+			break
+		}
+		label := fmt.Sprintf("%s %s", r.Type, r.Id)
+		if r.Link != "" {
+			label = fmt.Sprintf("%s <%s|%s>", r.Type, r.Link, r.Id)
+		}
+		if r.By != "" {
+			label += " by " + r.By
+		}
+		lines = append(lines, label)
+	}
+	return strings.Join(lines, "\n")
+}
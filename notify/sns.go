@@ -0,0 +1,93 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"fmt"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/wallix/awless/aws"
+)
+
+const (
+	// SNSTopicKey is the config key of the ARN of the SNS topic receiving the JSON events
+	SNSTopicKey = "notify.sns.topic"
+
+	eventAttribute     = "awless.event"
+	signatureAttribute = "awless.signature"
+)
+
+type snsTopic struct {
+	topic, profile, secret string
+	api                    snsiface.SNSAPI
+}
+
+func NewSNS(topic, profile, secret string) Notifier {
+	return &snsTopic{topic: topic, profile: profile, secret: secret}
+}
+
+func (s *snsTopic) Name() string { return "sns " + s.topic }
+
+func (s *snsTopic) Notify(e *Event) error {
+	if s.api == nil {
+		region, err := TopicRegion(s.topic)
+		if err != nil {
+			return err
+		}
+		sess, err := aws.InitSession(region, s.profile)
+		if err != nil {
+			return err
+		}
+		s.api = sns.New(sess)
+	}
+	body, err := Payload(e)
+	if err != nil {
+		return err
+	}
+	attributes := map[string]*sns.MessageAttributeValue{
+		eventAttribute: {DataType: awssdk.String("String"), StringValue: awssdk.String(string(e.Type))},
+	}
+	if signature := Sign(body, s.secret); signature != "" {
+		attributes[signatureAttribute] = &sns.MessageAttributeValue{DataType: awssdk.String("String"), StringValue: awssdk.String(signature)}
+	}
+	_, err = s.api.Publish(&sns.PublishInput{
+		TopicArn:          awssdk.String(s.topic),
+		Subject:           awssdk.String(truncate(e.Summary(), 100)),
+		Message:           awssdk.String(string(body)),
+		MessageAttributes: attributes,
+	})
+	return err
+}
+
+// TopicRegion returns the region of a SNS topic ARN. Ex: arn:aws:sns:eu-west-1:123456789012:awless
+func TopicRegion(arn string) (string, error) {
+	splits := strings.Split(arn, ":")
+	if len(splits) != 6 || splits[0] != "arn" || splits[2] != "sns" || splits[3] == "" {
+		return "", fmt.Errorf("invalid SNS topic ARN '%s': expecting arn:aws:sns:{region}:{account}:{name}", arn)
+	}
+	return splits[3], nil
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
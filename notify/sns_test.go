@@ -0,0 +1,76 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+)
+
+type mockSNS struct {
+	snsiface.SNSAPI
+	published []*sns.PublishInput
+}
+
+func (m *mockSNS) Publish(input *sns.PublishInput) (*sns.PublishOutput, error) {
+	m.published = append(m.published, input)
+	return &sns.PublishOutput{}, nil
+}
+
+func TestSNSNotify(t *testing.T) {
+	mock := &mockSNS{}
+	topic := "arn:aws:sns:eu-west-1:123456789012:awless"
+	notifier := &snsTopic{topic: topic, secret: "s3cr3t", api: mock}
+
+	event := &Event{Type: RunFailed, RunID: "01B", Errors: []string{"create instance: denied"}}
+	if err := notifier.Notify(event); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(mock.published), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	input := mock.published[0]
+	if got, want := *input.TopicArn, topic; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := *input.Subject, "awless run 01B failed: create instance: denied"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := *input.MessageAttributes[eventAttribute].StringValue, "run.failed"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := VerifySignature([]byte(*input.Message), *input.MessageAttributes[signatureAttribute].StringValue, "s3cr3t"), true; got != want {
+		t.Fatalf("got %t, want %t", got, want)
+	}
+}
+
+func TestTopicRegion(t *testing.T) {
+	region, err := TopicRegion("arn:aws:sns:us-east-2:123456789012:ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := region, "us-east-2"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	for _, invalid := range []string{"ops", "arn:aws:sqs:us-east-2:123456789012:ops", "arn:aws:sns::123456789012:ops"} {
+		if _, err := TopicRegion(invalid); err == nil {
+			t.Fatalf("%s: expected error got none", invalid)
+		}
+	}
+}
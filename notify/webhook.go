@@ -0,0 +1,107 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// WebhookURLsKey is the config key of the comma separated HTTP endpoints receiving the JSON events
+	WebhookURLsKey = "notify.webhook.urls"
+	// SecretKey is the config key of the secret signing the JSON events sent to webhooks and SNS
+	SecretKey = "notify.secret"
+
+	// EventHeader is the HTTP header holding the event type
+	EventHeader = "X-Awless-Event"
+	// SignatureHeader is the HTTP header holding the HMAC SHA256 signature of the body. Ex: sha256=6f2a...
+	SignatureHeader = "X-Awless-Signature"
+)
+
+type payload struct {
+	*Event
+	Summary string `json:"summary"`
+}
+
+// Payload is the JSON document describing the event sent to webhooks and SNS
+func Payload(e *Event) ([]byte, error) {
+	return json.Marshal(&payload{Event: e, Summary: e.Summary()})
+}
+
+// Sign returns the hex encoded HMAC SHA256 of the payload prefixed with the algorithm,
+// or an empty string without secret
+func Sign(body []byte, secret string) string {
+	if secret == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether the signature matches the payload for the secret
+func VerifySignature(body []byte, signature, secret string) bool {
+	expected := Sign(body, secret)
+	return expected != "" && hmac.Equal([]byte(expected), []byte(strings.TrimSpace(signature)))
+}
+
+type webhook struct {
+	url, secret string
+	client      *http.Client
+}
+
+func NewWebhook(url, secret string) Notifier {
+	return &webhook{url: url, secret: secret, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (w *webhook) Name() string { return "webhook " + w.url }
+
+func (w *webhook) Notify(e *Event) error {
+	body, err := Payload(e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "awless")
+	req.Header.Set(EventHeader, string(e.Type))
+	if signature := Sign(body, w.secret); signature != "" {
+		req.Header.Set(SignatureHeader, signature)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%d %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
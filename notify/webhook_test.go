@@ -0,0 +1,83 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookNotify(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	notifiers := FromConfig(map[string]interface{}{WebhookURLsKey: server.URL + "/a, " + server.URL + "/b", SecretKey: "s3cr3t"})
+	if got, want := len(notifiers), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	event := &Event{Type: RunReverted, RunID: "02C", Reverted: "01B", Template: "delete instance id=i-1"}
+	if err := notifiers[0].Notify(event); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := header.Get(EventHeader), "run.reverted"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := VerifySignature(body, header.Get(SignatureHeader), "s3cr3t"), true; got != want {
+		t.Fatalf("got %t, want %t", got, want)
+	}
+	if got, want := VerifySignature(body, header.Get(SignatureHeader), "other"), false; got != want {
+		t.Fatalf("got %t, want %t", got, want)
+	}
+
+	var received map[string]interface{}
+	if err := json.Unmarshal(body, &received); err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{"type": "run.reverted", "runId": "02C", "revertedRunId": "01B", "template": "delete instance id=i-1", "summary": "awless run 02C reverted run 01B"}
+	for k, want := range exp {
+		if got := received[k]; got != want {
+			t.Fatalf("%s: got %v, want %v", k, got, want)
+		}
+	}
+}
+
+func TestWebhookWithoutSecret(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+	}))
+	defer server.Close()
+
+	if err := NewWebhook(server.URL, "").Notify(&Event{Type: RunStarted}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := header.Get(SignatureHeader), ""; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := VerifySignature([]byte("{}"), "", ""), false; got != want {
+		t.Fatalf("got %t, want %t", got, want)
+	}
+}
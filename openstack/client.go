@@ -0,0 +1,231 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstack
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// credentials are the Keystone v3 password credentials of a project
+type credentials struct {
+	AuthURL                   string
+	Username, Password        string
+	UserDomain, ProjectDomain string
+	ProjectName, ProjectID    string
+	Region                    string
+}
+
+// client performs authenticated calls to the endpoints of the OpenStack services catalog
+type client struct {
+	httpClient *http.Client
+	token      string
+	endpoints  map[string]string
+}
+
+type httpError struct {
+	status int
+	msg    string
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("openstack: %d %s", e.status, e.msg)
+}
+
+// authenticate gets a project scoped token from Keystone and the public
+// endpoints of the catalog services in the credentials region
+func authenticate(httpClient *http.Client, creds credentials) (*client, error) {
+	project := map[string]interface{}{"domain": map[string]string{"name": creds.ProjectDomain}}
+	if creds.ProjectID != "" {
+		project = map[string]interface{}{"id": creds.ProjectID}
+	} else {
+		project["name"] = creds.ProjectName
+	}
+	payload := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []string{"password"},
+				"password": map[string]interface{}{
+					"user": map[string]interface{}{
+						"name":     creds.Username,
+						"password": creds.Password,
+						"domain":   map[string]string{"name": creds.UserDomain},
+					},
+				},
+			},
+			"scope": map[string]interface{}{"project": project},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	authURL := strings.TrimSuffix(creds.AuthURL, "/")
+	if !strings.HasSuffix(authURL, "/v3") {
+		authURL += "/v3"
+	}
+	resp, err := httpClient.Post(authURL+"/auth/tokens", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, &httpError{status: resp.StatusCode, msg: errorMessage(content, resp.StatusCode)}
+	}
+
+	var auth struct {
+		Token struct {
+			Catalog []struct {
+				Type      string `json:"type"`
+				Endpoints []struct {
+					Interface string `json:"interface"`
+					Region    string `json:"region"`
+					RegionID  string `json:"region_id"`
+					URL       string `json:"url"`
+				} `json:"endpoints"`
+			} `json:"catalog"`
+		} `json:"token"`
+	}
+	if err = json.Unmarshal(content, &auth); err != nil {
+		return nil, err
+	}
+
+	c := &client{httpClient: httpClient, token: resp.Header.Get("X-Subject-Token"), endpoints: make(map[string]string)}
+	if c.token == "" {
+		return nil, errors.New("openstack: no token returned by keystone")
+	}
+	for _, service := range auth.Token.Catalog {
+		for _, e := range service.Endpoints {
+			if e.Interface != "public" {
+				continue
+			}
+			if creds.Region != "" && creds.Region != e.Region && creds.Region != e.RegionID {
+				continue
+			}
+			c.endpoints[service.Type] = strings.TrimSuffix(e.URL, "/")
+		}
+	}
+	return c, nil
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// endpoint returns the public URL of a catalog service type (ex: compute, network, object-store)
+func (c *client) endpoint(serviceType string) (string, error) {
+	url, ok := c.endpoints[serviceType]
+	if !ok {
+		return "", fmt.Errorf("openstack: no public '%s' endpoint in catalog", serviceType)
+	}
+	if serviceType == "network" && !strings.HasSuffix(url, "/v2.0") {
+		url += "/v2.0"
+	}
+	return url, nil
+}
+
+func (c *client) do(method, url string, body io.Reader) ([]byte, error) {
+	return c.doWithHeader(method, url, body, nil)
+}
+
+// doWithHeader calls the API with extra headers (ex: Swift container metadata)
+func (c *client) doWithHeader(method, url string, body io.Reader, header http.Header) ([]byte, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("X-Auth-Token", c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, &httpError{status: resp.StatusCode, msg: errorMessage(content, resp.StatusCode)}
+	}
+	return content, nil
+}
+
+// getPages calls the given listing URL then follows the 'next' links
+// of the named links field (ex: servers_links)
+func (c *client) getPages(url, linksField string, onPage func(body []byte) error) error {
+	for url != "" {
+		body, err := c.do("GET", url, nil)
+		if err != nil {
+			return err
+		}
+		if err = onPage(body); err != nil {
+			return err
+		}
+		var page map[string]json.RawMessage
+		if err = json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		var links []struct {
+			Rel  string `json:"rel"`
+			Href string `json:"href"`
+		}
+		json.Unmarshal(page[linksField], &links)
+		url = ""
+		for _, l := range links {
+			if l.Rel == "next" {
+				url = l.Href
+			}
+		}
+	}
+	return nil
+}
+
+// errorMessage extracts the message of the various OpenStack error bodies
+// (ex: {"itemNotFound": {"message": "..."}} or {"NeutronError": {"message": "..."}})
+func errorMessage(body []byte, status int) string {
+	var errs map[string]struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &errs) == nil {
+		for _, e := range errs {
+			if e.Message != "" {
+				return e.Message
+			}
+		}
+	}
+	return http.StatusText(status)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthenticate(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/v3/auth/tokens"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		var body struct {
+			Auth struct {
+				Identity struct {
+					Password struct {
+						User struct {
+							Name     string `json:"name"`
+							Password string `json:"password"`
+						} `json:"user"`
+					} `json:"password"`
+				} `json:"identity"`
+				Scope struct {
+					Project struct {
+						Name string `json:"name"`
+					} `json:"project"`
+				} `json:"scope"`
+			} `json:"auth"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Auth.Identity.Password.User.Name != "john" || body.Auth.Identity.Password.User.Password != "secret" || body.Auth.Scope.Project.Name != "demo" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": {"message": "The request you have made requires authentication."}}`))
+			return
+		}
+		w.Header().Set("X-Subject-Token", "mytoken")
+		w.Write([]byte(`{"token": {"catalog": [
+			{"type": "compute", "endpoints": [
+				{"interface": "public", "region": "RegionOne", "url": "https://nova.one/v2.1/"},
+				{"interface": "internal", "region": "RegionOne", "url": "http://nova.internal/v2.1"},
+				{"interface": "public", "region": "RegionTwo", "url": "https://nova.two/v2.1"}]},
+			{"type": "network", "endpoints": [{"interface": "public", "region_id": "RegionOne", "url": "https://neutron.one"}]}
+		]}}`))
+	}))
+	defer server.Close()
+
+	creds := credentials{AuthURL: server.URL, Username: "john", Password: "secret", ProjectName: "demo", UserDomain: "Default", ProjectDomain: "Default", Region: "RegionOne"}
+	api, err := authenticate(server.Client(), creds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := api.token, "mytoken"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	compute, _ := api.endpoint("compute")
+	if got, want := compute, "https://nova.one/v2.1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	network, _ := api.endpoint("network")
+	if got, want := network, "https://neutron.one/v2.0"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if _, err = api.endpoint("object-store"); err == nil {
+		t.Fatal("expected error for missing endpoint")
+	}
+
+	creds.Password = "wrong"
+	_, err = authenticate(server.Client(), creds)
+	if err == nil {
+		t.Fatal("expected authentication error")
+	}
+	if got, want := err.Error(), "openstack: 401 The request you have made requires authentication."; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstack
+
+import "github.com/wallix/awless/template"
+
+// TemplatesDefinitions holds the definitions of the statements run by the OpenStack driver
+var TemplatesDefinitions = map[string]template.TemplateDefinition{
+	"createinstance": {
+		Action:         "create",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"name", "image", "type", "vpc"},
+		ExtraParams:    []string{"keypair", "securitygroup", "tags"},
+		ParamTypes:     map[string]string{"image": "string", "keypair": "string", "name": "string", "securitygroup": "string", "tags": "map", "type": "string", "vpc": "string"},
+	},
+	"createvpc": {
+		Action:      "create",
+		Entity:      "vpc",
+		Api:         "network",
+		ExtraParams: []string{"name"},
+		ParamTypes:  map[string]string{"name": "string"},
+	},
+	"createsubnet": {
+		Action:         "create",
+		Entity:         "subnet",
+		Api:            "network",
+		RequiredParams: []string{"vpc", "cidr"},
+		ExtraParams:    []string{"name"},
+		ParamTypes:     map[string]string{"cidr": "string", "name": "string", "vpc": "string"},
+	},
+	"createsecuritygroup": {
+		Action:         "create",
+		Entity:         "securitygroup",
+		Api:            "network",
+		RequiredParams: []string{"name"},
+		ExtraParams:    []string{"description"},
+		ParamTypes:     map[string]string{"description": "string", "name": "string"},
+	},
+	"createbucket": {
+		Action:         "create",
+		Entity:         "bucket",
+		Api:            "objectstorage",
+		RequiredParams: []string{"name"},
+		ExtraParams:    []string{"tags"},
+		ParamTypes:     map[string]string{"name": "string", "tags": "map"},
+	},
+	"updateinstance": {
+		Action:         "update",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ExtraParams:    []string{"name", "tags"},
+		ParamTypes:     map[string]string{"id": "string", "name": "string", "tags": "map"},
+	},
+	"updatevpc": {
+		Action:         "update",
+		Entity:         "vpc",
+		Api:            "network",
+		RequiredParams: []string{"id"},
+		ExtraParams:    []string{"name"},
+		ParamTypes:     map[string]string{"id": "string", "name": "string"},
+	},
+	"updatesubnet": {
+		Action:         "update",
+		Entity:         "subnet",
+		Api:            "network",
+		RequiredParams: []string{"id"},
+		ExtraParams:    []string{"name"},
+		ParamTypes:     map[string]string{"id": "string", "name": "string"},
+	},
+	"updatesecuritygroup": {
+		Action:         "update",
+		Entity:         "securitygroup",
+		Api:            "network",
+		RequiredParams: []string{"id"},
+		ExtraParams:    []string{"name", "description"},
+		ParamTypes:     map[string]string{"description": "string", "id": "string", "name": "string"},
+	},
+	"updatebucket": {
+		Action:         "update",
+		Entity:         "bucket",
+		Api:            "objectstorage",
+		RequiredParams: []string{"id"},
+		ExtraParams:    []string{"tags"},
+		ParamTypes:     map[string]string{"id": "string", "tags": "map"},
+	},
+	"deleteinstance": {
+		Action:         "delete",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"deletevpc": {
+		Action:         "delete",
+		Entity:         "vpc",
+		Api:            "network",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"deletesubnet": {
+		Action:         "delete",
+		Entity:         "subnet",
+		Api:            "network",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"deletesecuritygroup": {
+		Action:         "delete",
+		Entity:         "securitygroup",
+		Api:            "network",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"deletebucket": {
+		Action:         "delete",
+		Entity:         "bucket",
+		Api:            "objectstorage",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"startinstance": {
+		Action:         "start",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+	"stopinstance": {
+		Action:         "stop",
+		Entity:         "instance",
+		Api:            "compute",
+		RequiredParams: []string{"id"},
+		ParamTypes:     map[string]string{"id": "string"},
+	},
+}
@@ -0,0 +1,387 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstack
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template/driver"
+)
+
+// resourcePaths locates resources per type as catalog service type and collection path,
+// with the key wrapping the resources in request and response bodies
+var resourcePaths = map[string]struct{ serviceType, collection, wrapper string }{
+	graph.Instance.String():      {"compute", "/servers/", "server"},
+	graph.Vpc.String():           {"network", "/networks/", "network"},
+	graph.Subnet.String():        {"network", "/subnets/", "subnet"},
+	graph.SecurityGroup.String(): {"network", "/security-groups/", "security_group"},
+	graph.Bucket.String():        {"object-store", "/", ""},
+}
+
+// serverPoll is the interval and number of checks of the status of created servers
+var serverPoll = struct {
+	interval time.Duration
+	attempts int
+}{5 * time.Second, 120}
+
+// Driver runs template commands against the OpenStack APIs. It supports creating,
+// updating and deleting resources, and starting and stopping servers.
+type Driver struct {
+	api      *client
+	entities []string
+	dryRun   bool
+	logger   *logger.Logger
+}
+
+func NewDriver(api *client, entities ...string) driver.Driver {
+	return &Driver{api: api, entities: entities, logger: logger.DiscardLogger}
+}
+
+func (d *Driver) SetDryRun(dry bool)         { d.dryRun = dry }
+func (d *Driver) SetLogger(l *logger.Logger) { d.logger = l }
+
+func (d *Driver) Lookup(lookups ...string) (driver.DriverFn, error) {
+	if len(lookups) != 2 {
+		return nil, driver.ErrDriverFnNotFound
+	}
+	action, entity := lookups[0], lookups[1]
+	var supported bool
+	for _, e := range d.entities {
+		supported = supported || e == entity
+	}
+	if !supported {
+		return nil, driver.ErrDriverFnNotFound
+	}
+	switch {
+	case action == "create" && entity == graph.Bucket.String():
+		return d.createContainerFn(action, entity), nil
+	case action == "create":
+		return d.createFn(action, entity), nil
+	case action == "update" && entity == graph.Bucket.String():
+		return d.updateContainerFn(action, entity), nil
+	case action == "update":
+		return d.updateFn(action, entity), nil
+	case action == "delete":
+		return d.actionFn(action, entity, "DELETE", ""), nil
+	case action == "start" && entity == graph.Instance.String():
+		return d.actionFn(action, entity, "POST", `{"os-start": null}`), nil
+	case action == "stop" && entity == graph.Instance.String():
+		return d.actionFn(action, entity, "POST", `{"os-stop": null}`), nil
+	}
+	return nil, driver.ErrDriverFnNotFound
+}
+
+func (d *Driver) actionFn(action, entity, method, serverAction string) driver.DriverFn {
+	return func(params map[string]interface{}) (interface{}, error) {
+		id, ok := params["id"].(string)
+		if !ok || id == "" {
+			return nil, fmt.Errorf("%s %s: missing id", action, entity)
+		}
+		path := resourcePaths[entity]
+		endpoint, err := d.api.endpoint(path.serviceType)
+		if err != nil {
+			return nil, err
+		}
+		resourceURL := endpoint + path.collection + url.PathEscape(id)
+
+		if d.dryRun {
+			method := "GET"
+			if entity == graph.Bucket.String() {
+				method = "HEAD"
+			}
+			if _, err := d.api.do(method, resourceURL, nil); err != nil {
+				d.logger.Errorf("dry run: %s %s error: %s", action, entity, err)
+				return nil, err
+			}
+			d.logger.Verbosef("full dry run: %s %s ok", action, entity)
+			return id, nil
+		}
+
+		if serverAction != "" {
+			_, err = d.api.do(method, resourceURL+"/action", strings.NewReader(serverAction))
+		} else {
+			_, err = d.api.do(method, resourceURL, nil)
+		}
+		if err != nil {
+			d.logger.Errorf("%s %s error: %s", action, entity, err)
+			return nil, err
+		}
+		d.logger.Verbosef("%s %s '%s' done", action, entity, id)
+		return id, nil
+	}
+}
+
+// createFn creates servers, networks, subnets and security groups. Servers are created
+// with the 'image' and 'type' (flavor) params in the 'vpc' network, and are waited for
+// to be active. Subnets are created with the 'vpc' and 'cidr' params
+func (d *Driver) createFn(action, entity string) driver.DriverFn {
+	return func(params map[string]interface{}) (interface{}, error) {
+		body, err := createBody(entity, params)
+		if err != nil {
+			err = fmt.Errorf("%s %s: %s", action, entity, err)
+			d.logger.Errorf("%s", err)
+			return nil, err
+		}
+		path := resourcePaths[entity]
+		endpoint, err := d.api.endpoint(path.serviceType)
+		if err != nil {
+			return nil, err
+		}
+		if d.dryRun {
+			d.logger.Verbosef("full dry run: %s %s ok", action, entity)
+			return nil, nil
+		}
+
+		resp, err := d.postJSON("POST", endpoint+strings.TrimSuffix(path.collection, "/"), map[string]interface{}{path.wrapper: body})
+		if err != nil {
+			d.logger.Errorf("%s %s error: %s", action, entity, err)
+			return nil, err
+		}
+		var created map[string]struct {
+			Id string `json:"id"`
+		}
+		if err = json.Unmarshal(resp, &created); err != nil {
+			return nil, err
+		}
+		id := created[path.wrapper].Id
+		if entity == graph.Instance.String() {
+			if err = d.waitServerActive(endpoint + path.collection + url.PathEscape(id)); err != nil {
+				d.logger.Errorf("%s %s error: %s", action, entity, err)
+				return nil, err
+			}
+		}
+		d.logger.Verbosef("%s %s '%s' done", action, entity, id)
+		return id, nil
+	}
+}
+
+func createBody(entity string, params map[string]interface{}) (map[string]interface{}, error) {
+	body := make(map[string]interface{})
+	if name := stringParam(params, "name"); name != "" {
+		body["name"] = name
+	}
+	switch entity {
+	case graph.Instance.String():
+		image, flavor, network := stringParam(params, "image"), stringParam(params, "type"), stringParam(params, "vpc")
+		if body["name"] == nil || image == "" || flavor == "" || network == "" {
+			return nil, errors.New("missing 'name', 'image', 'type' or 'vpc' param")
+		}
+		body["imageRef"], body["flavorRef"] = image, flavor
+		body["networks"] = []interface{}{map[string]interface{}{"uuid": network}}
+		if keypair := stringParam(params, "keypair"); keypair != "" {
+			body["key_name"] = keypair
+		}
+		if sg := stringParam(params, "securitygroup"); sg != "" {
+			body["security_groups"] = []interface{}{map[string]interface{}{"name": sg}}
+		}
+		metadata, err := metadataParam(params)
+		if err != nil {
+			return nil, err
+		}
+		if len(metadata) > 0 {
+			body["metadata"] = metadata
+		}
+	case graph.Subnet.String():
+		network, cidr := stringParam(params, "vpc"), stringParam(params, "cidr")
+		if network == "" || cidr == "" {
+			return nil, errors.New("missing 'vpc' or 'cidr' param")
+		}
+		body["network_id"], body["cidr"], body["ip_version"] = network, cidr, 4
+		if strings.Contains(cidr, ":") {
+			body["ip_version"] = 6
+		}
+	case graph.SecurityGroup.String():
+		if body["name"] == nil {
+			return nil, errors.New("missing 'name' param")
+		}
+		if description := stringParam(params, "description"); description != "" {
+			body["description"] = description
+		}
+	}
+	return body, nil
+}
+
+// updateFn renames resources given by id, sets the description of security groups
+// and updates the metadata of servers with the 'tags' param
+func (d *Driver) updateFn(action, entity string) driver.DriverFn {
+	return func(params map[string]interface{}) (interface{}, error) {
+		id := stringParam(params, "id")
+		if id == "" {
+			return nil, fmt.Errorf("%s %s: missing id", action, entity)
+		}
+		body := make(map[string]interface{})
+		if name := stringParam(params, "name"); name != "" {
+			body["name"] = name
+		}
+		if description := stringParam(params, "description"); description != "" && entity == graph.SecurityGroup.String() {
+			body["description"] = description
+		}
+		metadata, err := metadataParam(params)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %s", action, entity, err)
+		}
+		if entity != graph.Instance.String() {
+			metadata = nil
+		}
+		if len(body) == 0 && len(metadata) == 0 {
+			return nil, fmt.Errorf("%s %s: nothing to update", action, entity)
+		}
+		path := resourcePaths[entity]
+		endpoint, err := d.api.endpoint(path.serviceType)
+		if err != nil {
+			return nil, err
+		}
+		resourceURL := endpoint + path.collection + url.PathEscape(id)
+
+		if d.dryRun {
+			if _, err := d.api.do("GET", resourceURL, nil); err != nil {
+				d.logger.Errorf("dry run: %s %s error: %s", action, entity, err)
+				return nil, err
+			}
+			d.logger.Verbosef("full dry run: %s %s ok", action, entity)
+			return id, nil
+		}
+		if len(body) > 0 {
+			if _, err = d.postJSON("PUT", resourceURL, map[string]interface{}{path.wrapper: body}); err != nil {
+				d.logger.Errorf("%s %s error: %s", action, entity, err)
+				return nil, err
+			}
+		}
+		if len(metadata) > 0 {
+			if _, err = d.postJSON("POST", resourceURL+"/metadata", map[string]interface{}{"metadata": metadata}); err != nil {
+				d.logger.Errorf("%s %s error: %s", action, entity, err)
+				return nil, err
+			}
+		}
+		d.logger.Verbosef("%s %s '%s' done", action, entity, id)
+		return id, nil
+	}
+}
+
+// createContainerFn creates the Swift container named by the 'name' param, with the 'tags' param as metadata
+func (d *Driver) createContainerFn(action, entity string) driver.DriverFn {
+	return d.containerFn(action, entity, "PUT", "name")
+}
+
+// updateContainerFn sets the 'tags' param as metadata of the Swift container given by id
+func (d *Driver) updateContainerFn(action, entity string) driver.DriverFn {
+	return d.containerFn(action, entity, "POST", "id")
+}
+
+func (d *Driver) containerFn(action, entity, method, nameParam string) driver.DriverFn {
+	return func(params map[string]interface{}) (interface{}, error) {
+		name := stringParam(params, nameParam)
+		if name == "" {
+			return nil, fmt.Errorf("%s %s: missing %s", action, entity, nameParam)
+		}
+		metadata, err := metadataParam(params)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %s", action, entity, err)
+		}
+		if method == "POST" && len(metadata) == 0 {
+			return nil, fmt.Errorf("%s %s: nothing to update", action, entity)
+		}
+		endpoint, err := d.api.endpoint(resourcePaths[entity].serviceType)
+		if err != nil {
+			return nil, err
+		}
+		if d.dryRun {
+			d.logger.Verbosef("full dry run: %s %s ok", action, entity)
+			return nil, nil
+		}
+		header := make(http.Header)
+		for k, v := range metadata {
+			header.Set("X-Container-Meta-"+k, v)
+		}
+		if _, err = d.api.doWithHeader(method, endpoint+"/"+url.PathEscape(name), nil, header); err != nil {
+			d.logger.Errorf("%s %s error: %s", action, entity, err)
+			return nil, err
+		}
+		d.logger.Verbosef("%s %s '%s' done", action, entity, name)
+		return name, nil
+	}
+}
+
+func (d *Driver) postJSON(method, url string, body interface{}) ([]byte, error) {
+	content, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return d.api.do(method, url, bytes.NewReader(content))
+}
+
+// waitServerActive checks the status of a server until it is active or in error
+func (d *Driver) waitServerActive(serverURL string) error {
+	for i := 0; i < serverPoll.attempts; i++ {
+		if i > 0 {
+			time.Sleep(serverPoll.interval)
+		}
+		body, err := d.api.do("GET", serverURL, nil)
+		if err != nil {
+			return err
+		}
+		var resp struct {
+			Server struct {
+				Status string `json:"status"`
+				Fault  struct {
+					Message string `json:"message"`
+				} `json:"fault"`
+			} `json:"server"`
+		}
+		if err = json.Unmarshal(body, &resp); err != nil {
+			return err
+		}
+		switch resp.Server.Status {
+		case "ACTIVE":
+			return nil
+		case "ERROR":
+			return fmt.Errorf("server in error: %s", resp.Server.Fault.Message)
+		}
+	}
+	return fmt.Errorf("server not active after %s", time.Duration(serverPoll.attempts)*serverPoll.interval)
+}
+
+func stringParam(params map[string]interface{}, key string) string {
+	s, _ := params[key].(string)
+	return s
+}
+
+// metadataParam converts the 'tags' map param into OpenStack metadata
+func metadataParam(params map[string]interface{}) (map[string]string, error) {
+	tags, ok := params["tags"]
+	if !ok {
+		return nil, nil
+	}
+	m, isMap := tags.(map[string]interface{})
+	if !isMap {
+		return nil, fmt.Errorf("expecting a map for 'tags' (ex: tags={env:prod}), got %v", tags)
+	}
+	metadata := make(map[string]string)
+	for k, v := range m {
+		metadata[k] = fmt.Sprint(v)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstack
+
+import (
+	"encoding/json"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wallix/awless/graph"
+)
+
+type server struct {
+	Id        string            `json:"id"`
+	Name      string            `json:"name"`
+	Status    string            `json:"status"`
+	KeyName   string            `json:"key_name"`
+	Created   string            `json:"created"`
+	Zone      string            `json:"OS-EXT-AZ:availability_zone"`
+	Metadata  map[string]string `json:"metadata"`
+	Flavor    json.RawMessage   `json:"flavor"`
+	Addresses map[string][]struct {
+		Addr string `json:"addr"`
+		Type string `json:"OS-EXT-IPS:type"`
+	} `json:"addresses"`
+	SecurityGroups []struct {
+		Name string `json:"name"`
+	} `json:"security_groups"`
+}
+
+type network struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Shared   bool   `json:"shared"`
+	External bool   `json:"router:external"`
+}
+
+type subnet struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	NetworkId  string `json:"network_id"`
+	Cidr       string `json:"cidr"`
+	GatewayIp  string `json:"gateway_ip"`
+	EnableDhcp bool   `json:"enable_dhcp"`
+}
+
+type securityGroup struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Rules       []struct {
+		Direction      string `json:"direction"`
+		Protocol       string `json:"protocol"`
+		PortRangeMin   *int64 `json:"port_range_min"`
+		PortRangeMax   *int64 `json:"port_range_max"`
+		RemoteIpPrefix string `json:"remote_ip_prefix"`
+	} `json:"security_group_rules"`
+}
+
+type container struct {
+	Name         string `json:"name"`
+	Count        int64  `json:"count"`
+	Bytes        int64  `json:"bytes"`
+	LastModified string `json:"last_modified"`
+}
+
+func fetchServers(api *client) ([]*graph.Resource, error) {
+	subnets, err := listSubnets(api)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := api.endpoint("compute")
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*graph.Resource
+	err = api.getPages(endpoint+"/servers/detail", "servers_links", func(body []byte) error {
+		var page struct {
+			Servers []server `json:"servers"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, s := range page.Servers {
+			res := graph.InitResource(s.Id, graph.Instance)
+			res.Properties["Id"] = s.Id
+			res.Properties["Name"] = s.Name
+			res.Properties["State"] = instanceState(s.Status)
+			setIfNotEmpty(res, "Type", flavorName(s.Flavor))
+			setIfNotEmpty(res, "KeyName", s.KeyName)
+			setIfNotEmpty(res, "AvailabilityZone", s.Zone)
+			if t, err := time.Parse(time.RFC3339, s.Created); err == nil {
+				res.Properties["LaunchTime"] = t
+			}
+			if tags := metadataToTags(s.Metadata); len(tags) > 0 {
+				res.Properties["Tags"] = tags
+			}
+			var groups []string
+			for _, sg := range s.SecurityGroups {
+				groups = append(groups, sg.Name)
+			}
+			if len(groups) > 0 {
+				res.Properties["SecurityGroups"] = groups
+			}
+			for _, addresses := range s.Addresses {
+				for _, addr := range addresses {
+					switch addr.Type {
+					case "floating":
+						setIfNotEmpty(res, "PublicIp", addr.Addr)
+					default:
+						if _, ok := res.Properties["PrivateIp"]; !ok {
+							setIfNotEmpty(res, "PrivateIp", addr.Addr)
+							if sub := subnetOf(subnets, addr.Addr); sub != nil {
+								res.Properties["SubnetId"] = sub.Id
+								res.Properties["VpcId"] = sub.NetworkId
+							}
+						}
+					}
+				}
+			}
+			resources = append(resources, res)
+		}
+		return nil
+	})
+	return resources, err
+}
+
+// instanceState maps Nova statuses onto the instance states of awless (ex: running, stopped)
+func instanceState(status string) string {
+	switch status {
+	case "ACTIVE":
+		return "running"
+	case "SHUTOFF":
+		return "stopped"
+	case "BUILD":
+		return "pending"
+	default:
+		return strings.ToLower(status)
+	}
+}
+
+// flavorName returns the flavor name embedded in recent Nova versions or the flavor id
+func flavorName(raw json.RawMessage) string {
+	var flavor struct {
+		Id           string `json:"id"`
+		OriginalName string `json:"original_name"`
+	}
+	if err := json.Unmarshal(raw, &flavor); err != nil {
+		return ""
+	}
+	if flavor.OriginalName != "" {
+		return flavor.OriginalName
+	}
+	return flavor.Id
+}
+
+func subnetOf(subnets []subnet, addr string) *subnet {
+	ip := net.ParseIP(addr)
+	for i, s := range subnets {
+		if _, cidr, err := net.ParseCIDR(s.Cidr); err == nil && ip != nil && cidr.Contains(ip) {
+			return &subnets[i]
+		}
+	}
+	return nil
+}
+
+func listSubnets(api *client) ([]subnet, error) {
+	endpoint, err := api.endpoint("network")
+	if err != nil {
+		return nil, err
+	}
+	var subnets []subnet
+	err = api.getPages(endpoint+"/subnets", "subnets_links", func(body []byte) error {
+		var page struct {
+			Subnets []subnet `json:"subnets"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		subnets = append(subnets, page.Subnets...)
+		return nil
+	})
+	return subnets, err
+}
+
+func fetchNetworks(api *client) ([]*graph.Resource, error) {
+	endpoint, err := api.endpoint("network")
+	if err != nil {
+		return nil, err
+	}
+	var resources []*graph.Resource
+	err = api.getPages(endpoint+"/networks", "networks_links", func(body []byte) error {
+		var page struct {
+			Networks []network `json:"networks"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, n := range page.Networks {
+			res := graph.InitResource(n.Id, graph.Vpc)
+			res.Properties["Id"] = n.Id
+			res.Properties["Name"] = n.Name
+			res.Properties["State"] = strings.ToLower(n.Status)
+			res.Properties["Shared"] = n.Shared
+			res.Properties["External"] = n.External
+			resources = append(resources, res)
+		}
+		return nil
+	})
+	return resources, err
+}
+
+func fetchSubnets(api *client) ([]*graph.Resource, error) {
+	subnets, err := listSubnets(api)
+	if err != nil {
+		return nil, err
+	}
+	var resources []*graph.Resource
+	for _, s := range subnets {
+		res := graph.InitResource(s.Id, graph.Subnet)
+		res.Properties["Id"] = s.Id
+		res.Properties["Name"] = s.Name
+		res.Properties["VpcId"] = s.NetworkId
+		res.Properties["CidrBlock"] = s.Cidr
+		setIfNotEmpty(res, "Gateway", s.GatewayIp)
+		res.Properties["EnableDhcp"] = s.EnableDhcp
+		resources = append(resources, res)
+	}
+	return resources, nil
+}
+
+func fetchSecurityGroups(api *client) ([]*graph.Resource, error) {
+	endpoint, err := api.endpoint("network")
+	if err != nil {
+		return nil, err
+	}
+	var resources []*graph.Resource
+	err = api.getPages(endpoint+"/security-groups", "security_groups_links", func(body []byte) error {
+		var page struct {
+			SecurityGroups []securityGroup `json:"security_groups"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, sg := range page.SecurityGroups {
+			res := graph.InitResource(sg.Id, graph.SecurityGroup)
+			res.Properties["Id"] = sg.Id
+			res.Properties["Name"] = sg.Name
+			setIfNotEmpty(res, "Description", sg.Description)
+			var inbound, outbound []*graph.FirewallRule
+			for _, r := range sg.Rules {
+				rule := &graph.FirewallRule{Protocol: r.Protocol, PortRange: graph.PortRange{Any: true}}
+				if rule.Protocol == "" {
+					rule.Protocol = "any"
+				}
+				if r.PortRangeMin != nil && r.PortRangeMax != nil {
+					rule.PortRange = graph.PortRange{FromPort: *r.PortRangeMin, ToPort: *r.PortRangeMax}
+				}
+				if _, ipnet, err := net.ParseCIDR(r.RemoteIpPrefix); err == nil {
+					rule.IPRanges = append(rule.IPRanges, ipnet)
+				}
+				if r.Direction == "ingress" {
+					inbound = append(inbound, rule)
+				} else {
+					outbound = append(outbound, rule)
+				}
+			}
+			if len(inbound) > 0 {
+				res.Properties["InboundRules"] = inbound
+			}
+			if len(outbound) > 0 {
+				res.Properties["OutboundRules"] = outbound
+			}
+			resources = append(resources, res)
+		}
+		return nil
+	})
+	return resources, err
+}
+
+// fetchContainers lists the Swift containers of the account, paginating with markers
+func fetchContainers(api *client) ([]*graph.Resource, error) {
+	endpoint, err := api.endpoint("object-store")
+	if err != nil {
+		return nil, err
+	}
+	var resources []*graph.Resource
+	var marker string
+	for {
+		body, err := api.do("GET", endpoint+"?format=json&marker="+url.QueryEscape(marker), nil)
+		if err != nil {
+			return resources, err
+		}
+		var containers []container
+		if err = json.Unmarshal(body, &containers); err != nil {
+			return resources, err
+		}
+		if len(containers) == 0 {
+			return resources, nil
+		}
+		for _, c := range containers {
+			res := graph.InitResource(c.Name, graph.Bucket)
+			res.Properties["Id"] = c.Name
+			res.Properties["Name"] = c.Name
+			res.Properties["Objects"] = c.Count
+			res.Properties["Size"] = c.Bytes
+			if t, err := time.Parse("2006-01-02T15:04:05.999999", c.LastModified); err == nil {
+				res.Properties["ModifiedDate"] = t
+			}
+			resources = append(resources, res)
+		}
+		marker = containers[len(containers)-1].Name
+	}
+}
+
+func setIfNotEmpty(res *graph.Resource, key, value string) {
+	if value != "" {
+		res.Properties[key] = value
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package openstack provides an OpenStack provider mapping Nova servers, Neutron networks
+// and security groups, and Swift containers onto the awless graph model.
+package openstack
+
+import (
+	"errors"
+	"os"
+
+	"github.com/wallix/awless/cloud"
+)
+
+// RegionKey is the config key of the OpenStack region, defaulting to OS_REGION_NAME
+const RegionKey = "openstack.region"
+
+var ComputeService, NetworkService, StorageService cloud.Service
+
+func init() {
+	cloud.RegisterProvider(provider{})
+}
+
+type provider struct{}
+
+func (provider) Name() string { return "openstack" }
+
+// InitServices authenticates against Keystone with the OS_* variables of an openrc file
+// and registers the OpenStack services of the configured region
+func (provider) InitServices(defaults map[string]interface{}) error {
+	creds, err := credentialsFromEnv(defaults)
+	if err != nil {
+		return err
+	}
+	api, err := authenticate(newHTTPClient(), creds)
+	if err != nil {
+		return err
+	}
+
+	ComputeService = NewCompute(api)
+	NetworkService = NewNetwork(api)
+	StorageService = NewStorage(api)
+
+	cloud.ServiceRegistry[ComputeService.Name()] = ComputeService
+	cloud.ServiceRegistry[NetworkService.Name()] = NetworkService
+	cloud.ServiceRegistry[StorageService.Name()] = StorageService
+
+	return nil
+}
+
+func credentialsFromEnv(defaults map[string]interface{}) (credentials, error) {
+	creds := credentials{
+		AuthURL:       os.Getenv("OS_AUTH_URL"),
+		Username:      os.Getenv("OS_USERNAME"),
+		Password:      os.Getenv("OS_PASSWORD"),
+		UserDomain:    envOr("OS_USER_DOMAIN_NAME", "Default"),
+		ProjectDomain: envOr("OS_PROJECT_DOMAIN_NAME", "Default"),
+		ProjectName:   envOr("OS_PROJECT_NAME", os.Getenv("OS_TENANT_NAME")),
+		ProjectID:     envOr("OS_PROJECT_ID", os.Getenv("OS_TENANT_ID")),
+		Region:        os.Getenv("OS_REGION_NAME"),
+	}
+	if region, ok := defaults[RegionKey].(string); ok && region != "" {
+		creds.Region = region
+	}
+	if creds.AuthURL == "" || creds.Username == "" || creds.Password == "" || (creds.ProjectName == "" && creds.ProjectID == "") {
+		return creds, errors.New("Your OpenStack credentials seem undefined! Source your openrc file exporting OS_AUTH_URL, OS_USERNAME, OS_PASSWORD and OS_PROJECT_NAME")
+	}
+	return creds, nil
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstack
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template/driver"
+)
+
+type fetchFunc func(api *client) ([]*graph.Resource, error)
+
+type fetcher struct {
+	resourceType string
+	fetch        fetchFunc
+}
+
+type service struct {
+	name     string
+	api      *client
+	fetchers []fetcher
+}
+
+// NewCompute returns the service of Nova servers and of the Neutron networks they live in
+func NewCompute(api *client) cloud.Service {
+	return &service{name: "compute", api: api, fetchers: []fetcher{
+		{resourceType: graph.Instance.String(), fetch: fetchServers},
+	}}
+}
+
+func NewNetwork(api *client) cloud.Service {
+	return &service{name: "network", api: api, fetchers: []fetcher{
+		{resourceType: graph.Vpc.String(), fetch: fetchNetworks},
+		{resourceType: graph.Subnet.String(), fetch: fetchSubnets},
+		{resourceType: graph.SecurityGroup.String(), fetch: fetchSecurityGroups},
+	}}
+}
+
+func NewStorage(api *client) cloud.Service {
+	return &service{name: "objectstorage", api: api, fetchers: []fetcher{
+		{resourceType: graph.Bucket.String(), fetch: fetchContainers},
+	}}
+}
+
+func (s *service) Name() string { return s.name }
+
+func (s *service) Drivers() []driver.Driver {
+	return []driver.Driver{NewDriver(s.api, s.ResourceTypes()...)}
+}
+
+func (s *service) ResourceTypes() (types []string) {
+	for _, f := range s.fetchers {
+		types = append(types, f.resourceType)
+	}
+	return
+}
+
+func (s *service) FetchResources() (*graph.Graph, error) {
+	var all []*graph.Resource
+	for _, f := range s.fetchers {
+		resources, err := f.fetch(s.api)
+		if err != nil {
+			return graph.NewGraph(), convertError(err)
+		}
+		all = append(all, resources...)
+	}
+	return buildGraph(all)
+}
+
+func (s *service) FetchByType(t string) (*graph.Graph, error) {
+	for _, f := range s.fetchers {
+		if f.resourceType == t {
+			resources, err := f.fetch(s.api)
+			if err != nil {
+				return graph.NewGraph(), convertError(err)
+			}
+			return buildGraph(resources)
+		}
+	}
+	return nil, fmt.Errorf("openstack %s: unsupported fetch for type %s", s.name, t)
+}
+
+// buildGraph adds the resources to a new graph, relating subnets to their network
+func buildGraph(resources []*graph.Resource) (*graph.Graph, error) {
+	g := graph.NewGraph()
+	networks := make(map[string]*graph.Resource)
+	for _, res := range resources {
+		if res.Type() == graph.Vpc {
+			networks[res.Id()] = res
+		}
+	}
+	if err := g.AddResource(resources...); err != nil {
+		return g, err
+	}
+	for _, res := range resources {
+		if res.Type() != graph.Subnet {
+			continue
+		}
+		if parent, ok := networks[stringProp(res, "VpcId")]; ok {
+			if err := g.AddParentRelation(parent, res); err != nil {
+				return g, err
+			}
+		}
+	}
+	return g, nil
+}
+
+func convertError(err error) error {
+	if e, ok := err.(*httpError); ok && (e.status == http.StatusForbidden || e.status == http.StatusUnauthorized) {
+		return cloud.ErrFetchAccessDenied
+	}
+	return err
+}
+
+func stringProp(res *graph.Resource, key string) string {
+	s, _ := res.Properties[key].(string)
+	return s
+}
+
+// metadataToTags converts OpenStack metadata into awless 'key=value' tags
+func metadataToTags(metadata map[string]string) []string {
+	var tags []string
+	for k, v := range metadata {
+		tags = append(tags, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstack
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template/driver"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*client, func()) {
+	server := httptest.NewServer(handler)
+	api := &client{httpClient: server.Client(), token: "mytoken", endpoints: map[string]string{
+		"compute":      server.URL + "/compute",
+		"network":      server.URL + "/network",
+		"object-store": server.URL + "/swift",
+	}}
+	return api, server.Close
+}
+
+func TestFetchServers(t *testing.T) {
+	api, closeFn := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("X-Auth-Token"), "mytoken"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		switch r.URL.Path {
+		case "/network/v2.0/subnets":
+			w.Write([]byte(`{"subnets": [{"id": "sub-1", "network_id": "net-1", "cidr": "192.168.0.0/24"}, {"id": "sub-2", "network_id": "net-1", "cidr": "10.0.0.0/24"}]}`))
+		case "/compute/servers/detail":
+			if r.URL.Query().Get("marker") == "" {
+				w.Write([]byte(`{"servers": [{"id": "srv-1", "name": "web", "status": "ACTIVE", "key_name": "mykey", "flavor": {"original_name": "m1.small"},
+					"metadata": {"env": "prod"}, "created": "2017-03-01T10:00:00Z",
+					"addresses": {"private": [{"addr": "10.0.0.5", "OS-EXT-IPS:type": "fixed"}, {"addr": "172.24.4.3", "OS-EXT-IPS:type": "floating"}]}}],
+					"servers_links": [{"rel": "next", "href": "` + "http://" + r.Host + `/compute/servers/detail?marker=srv-1"}]}`))
+				return
+			}
+			w.Write([]byte(`{"servers": [{"id": "srv-2", "name": "db", "status": "SHUTOFF", "flavor": {"id": "2"}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer closeFn()
+
+	g, err := NewCompute(api).FetchResources()
+	if err != nil {
+		t.Fatal(err)
+	}
+	web, _ := g.GetResource(graph.Instance, "srv-1")
+	expected := map[string]interface{}{
+		"Name":      "web",
+		"State":     "running",
+		"Type":      "m1.small",
+		"KeyName":   "mykey",
+		"PrivateIp": "10.0.0.5",
+		"PublicIp":  "172.24.4.3",
+		"SubnetId":  "sub-2",
+		"VpcId":     "net-1",
+		"Tags":      []interface{}{"env=prod"},
+	}
+	for k, want := range expected {
+		if got := web.Properties[k]; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %#v, want %#v", k, got, want)
+		}
+	}
+	db, _ := g.GetResource(graph.Instance, "srv-2")
+	if got, want := db.Properties["State"], "stopped"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := db.Properties["Type"], "2"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestDriver(t *testing.T) {
+	var calls []string
+	api, closeFn := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+string(body))
+	})
+	defer closeFn()
+
+	d := NewDriver(api, "instance")
+	if _, err := d.Lookup("delete", "subnet"); err == nil {
+		t.Fatal("expected driver function not found")
+	}
+	stop, _ := d.Lookup("stop", "instance")
+	if _, err := stop(map[string]interface{}{"id": "srv-1"}); err != nil {
+		t.Fatal(err)
+	}
+	del, _ := d.Lookup("delete", "instance")
+	if _, err := del(map[string]interface{}{"id": "srv-1"}); err != nil {
+		t.Fatal(err)
+	}
+	d.SetDryRun(true)
+	if _, err := del(map[string]interface{}{"id": "srv-2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`POST /compute/servers/srv-1/action {"os-stop": null}`,
+		`DELETE /compute/servers/srv-1 `,
+		`GET /compute/servers/srv-2 `,
+	}
+	if got, want := calls, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestDriverCreateAndUpdate(t *testing.T) {
+	serverPoll.interval = time.Millisecond
+	var calls []string
+	bodies := make(map[string]interface{})
+	statuses := []string{"BUILD", "ACTIVE"}
+	api, closeFn := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.Path
+		calls = append(calls, call)
+		if content, _ := ioutil.ReadAll(r.Body); len(content) > 0 {
+			var body interface{}
+			if err := json.Unmarshal(content, &body); err != nil {
+				t.Fatal(err)
+			}
+			bodies[call] = body
+		}
+		switch call {
+		case "POST /compute/servers":
+			w.Write([]byte(`{"server": {"id": "srv-1"}}`))
+		case "GET /compute/servers/srv-1":
+			w.Write([]byte(`{"server": {"status": "` + statuses[0] + `"}}`))
+			statuses = statuses[1:]
+		case "POST /network/v2.0/subnets":
+			w.Write([]byte(`{"subnet": {"id": "sub-1"}}`))
+		case "PUT /swift/assets":
+			if got, want := r.Header.Get("X-Container-Meta-Env"), "prod"; got != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		case "PUT /network/v2.0/security-groups/sg-1", "POST /compute/servers/srv-1/metadata":
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer closeFn()
+
+	d := NewDriver(api, "instance", "subnet", "securitygroup", "bucket")
+	if _, err := d.Lookup("create", "vpc"); err != driver.ErrDriverFnNotFound {
+		t.Fatalf("got %v, want %v", err, driver.ErrDriverFnNotFound)
+	}
+
+	create, err := d.Lookup("create", "instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = create(map[string]interface{}{"name": "web", "image": "img-1", "type": "m1.small"}); err == nil {
+		t.Fatal("expected error for missing vpc")
+	}
+	id, err := create(map[string]interface{}{"name": "web", "image": "img-1", "type": "m1.small", "vpc": "net-1", "keypair": "mykey", "tags": map[string]interface{}{"env": "prod"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := id, "srv-1"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	expected := map[string]interface{}{"server": map[string]interface{}{
+		"name": "web", "imageRef": "img-1", "flavorRef": "m1.small", "key_name": "mykey",
+		"networks": []interface{}{map[string]interface{}{"uuid": "net-1"}},
+		"metadata": map[string]interface{}{"env": "prod"},
+	}}
+	if got, want := bodies["POST /compute/servers"], expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	subnet, _ := d.Lookup("create", "subnet")
+	if id, err = subnet(map[string]interface{}{"vpc": "net-1", "cidr": "10.0.0.0/24"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := id, "sub-1"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	expected = map[string]interface{}{"subnet": map[string]interface{}{"network_id": "net-1", "cidr": "10.0.0.0/24", "ip_version": float64(4)}}
+	if got, want := bodies["POST /network/v2.0/subnets"], expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	update, _ := d.Lookup("update", "securitygroup")
+	if _, err = update(map[string]interface{}{"id": "sg-1", "tags": map[string]interface{}{"env": "prod"}}); err == nil {
+		t.Fatal("expected error for nothing to update")
+	}
+	if _, err = update(map[string]interface{}{"id": "sg-1", "description": "web access"}); err != nil {
+		t.Fatal(err)
+	}
+	update, _ = d.Lookup("update", "instance")
+	if _, err = update(map[string]interface{}{"id": "srv-1", "tags": map[string]interface{}{"env": "staging"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	bucket, _ := d.Lookup("create", "bucket")
+	if id, err = bucket(map[string]interface{}{"name": "assets", "tags": map[string]interface{}{"Env": "prod"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := id, "assets"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	expectedCalls := []string{
+		"POST /compute/servers",
+		"GET /compute/servers/srv-1",
+		"GET /compute/servers/srv-1",
+		"POST /network/v2.0/subnets",
+		"PUT /network/v2.0/security-groups/sg-1",
+		"POST /compute/servers/srv-1/metadata",
+		"PUT /swift/assets",
+	}
+	if got, want := calls, expectedCalls; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
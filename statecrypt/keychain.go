@@ -0,0 +1,92 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statecrypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const (
+	keychainService = "awless"
+	keychainAccount = "state-encryption-key"
+)
+
+// keychainKey returns the key stored in the OS keychain, creating it on first use.
+// It relies on the `security` tool on macOS and `secret-tool` (libsecret) on Linux
+func keychainKey() ([]byte, error) {
+	stored, err := keychainLookup()
+	if err != nil {
+		return nil, err
+	}
+	if stored == "" {
+		k := make([]byte, keySize)
+		if _, err := io.ReadFull(rand.Reader, k); err != nil {
+			return nil, err
+		}
+		stored = hex.EncodeToString(k)
+		if err := keychainStore(stored); err != nil {
+			return nil, err
+		}
+	}
+	return hex.DecodeString(stored)
+}
+
+func keychainLookup() (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", keychainAccount, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "account", keychainAccount)
+	default:
+		return "", fmt.Errorf("no keychain support on %s: use the %s mode", runtime.GOOS, PassphraseMode)
+	}
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			// not found
+			return "", nil
+		}
+		return "", fmt.Errorf("keychain: %s", err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func keychainStore(secret string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "add-generic-password", "-s", keychainService, "-a", keychainAccount, "-w", secret)
+	case "linux":
+		cmd = exec.Command("secret-tool", "store", "--label=awless state encryption key", "service", keychainService, "account", keychainAccount)
+		cmd.Stdin = strings.NewReader(secret)
+	default:
+		return fmt.Errorf("no keychain support on %s: use the %s mode", runtime.GOOS, PassphraseMode)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("keychain: %s: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statecrypt encrypts the local state of awless (run history, graphs and
+// their snapshots, cached credentials) with AES-256-GCM.
+//
+// The key is either a random key stored in the OS keychain, or derived from a passphrase.
+// Encrypted data is prefixed with a header so that state written in plain text
+// before the encryption was enabled can still be read.
+package statecrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	gosync "sync"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// ModeKey is the config key enabling the encryption of the local state: keychain or passphrase
+	ModeKey = "state.encryption"
+
+	KeychainMode   = "keychain"
+	PassphraseMode = "passphrase"
+
+	// PassphraseEnv holds the passphrase in passphrase mode, prompted otherwise
+	PassphraseEnv = "AWLESS_STATE_PASSPHRASE"
+
+	pbkdf2Iterations = 100000
+	keySize          = 32
+)
+
+var header = []byte("awless-enc-v1\n")
+
+var (
+	mu      gosync.Mutex
+	loadKey func() ([]byte, error)
+	key     []byte
+)
+
+// Enable encrypts the state written from now on with the key of the given mode.
+// The key is only retrieved (keychain lookup, passphrase prompt) when first needed.
+// The salt file holds the random salt used to derive a key from a passphrase
+func Enable(mode, saltFile string, prompt func() (string, error)) error {
+	var load func() ([]byte, error)
+	switch mode {
+	case KeychainMode:
+		load = keychainKey
+	case PassphraseMode:
+		load = func() ([]byte, error) {
+			pass := os.Getenv(PassphraseEnv)
+			if pass == "" && prompt != nil {
+				var err error
+				if pass, err = prompt(); err != nil {
+					return nil, err
+				}
+			}
+			if pass == "" {
+				return nil, fmt.Errorf("missing passphrase to encrypt local state: export %s", PassphraseEnv)
+			}
+			salt, err := loadOrCreateSalt(saltFile)
+			if err != nil {
+				return nil, err
+			}
+			return KeyFromPassphrase(pass, salt), nil
+		}
+	default:
+		return fmt.Errorf("invalid state encryption '%s': expecting %s or %s", mode, KeychainMode, PassphraseMode)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	loadKey, key = load, nil
+	return nil
+}
+
+// Disable writes the state in plain text from now on. Encrypted state can no longer be read
+func Disable() {
+	mu.Lock()
+	defer mu.Unlock()
+	loadKey, key = nil, nil
+}
+
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return loadKey != nil
+}
+
+// IsEncrypted tells whether the data was encrypted by this package
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, header)
+}
+
+// Encrypt returns the data encrypted when encryption is enabled, unchanged otherwise
+func Encrypt(data []byte) ([]byte, error) {
+	if !Enabled() {
+		return data, nil
+	}
+	gcm, err := currentAEAD()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	out := append(append([]byte{}, header...), nonce...)
+	return gcm.Seal(out, nonce, data, header), nil
+}
+
+// Decrypt returns the plain content of encrypted data. Plain data is returned unchanged
+func Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	if !Enabled() {
+		return nil, fmt.Errorf("local state is encrypted: set `%s` in config to read it", ModeKey)
+	}
+	gcm, err := currentAEAD()
+	if err != nil {
+		return nil, err
+	}
+	data = data[len(header):]
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("decrypting local state: truncated data")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], header)
+	if err != nil {
+		return nil, errors.New("decrypting local state: wrong key or passphrase, or corrupted data")
+	}
+	return plain, nil
+}
+
+// ReadFile reads a file of the local state, decrypting it if needed
+func ReadFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Decrypt(data)
+}
+
+// WriteFile writes a file of the local state, encrypted when encryption is enabled
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	out, err := Encrypt(data)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, out, perm)
+}
+
+func currentAEAD() (cipher.AEAD, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if key == nil {
+		k, err := loadKey()
+		if err != nil {
+			return nil, fmt.Errorf("state encryption key: %s", err)
+		}
+		if len(k) != keySize {
+			return nil, fmt.Errorf("state encryption key: invalid size %d", len(k))
+		}
+		key = k
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func loadOrCreateSalt(path string) ([]byte, error) {
+	salt, err := ioutil.ReadFile(path)
+	if err == nil && len(salt) > 0 {
+		return salt, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	salt = make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	return salt, ioutil.WriteFile(path, salt, 0600)
+}
+
+// KeyFromPassphrase derives a key with PBKDF2-HMAC-SHA256
+func KeyFromPassphrase(passphrase string, salt []byte) []byte {
+	return pbkdf2.Key([]byte(passphrase), salt, pbkdf2Iterations, keySize, sha256.New)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statecrypt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKeyFromPassphrase(t *testing.T) {
+	if got, want := hex.EncodeToString(KeyFromPassphrase("password", []byte("salt"))), "0394a2ede332c9a13eb82e9b24631604c31df978b4e2f0fbd2c549944f9d79a5"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-statecrypt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer Disable()
+	saltFile := filepath.Join(dir, "state.salt")
+	plain := []byte("<infra> <has_type> <instance>")
+
+	Disable()
+	out, err := Encrypt(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out, plain; !bytes.Equal(got, want) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	prompts := 0
+	prompt := func(pass string) func() (string, error) {
+		return func() (string, error) {
+			prompts++
+			return pass, nil
+		}
+	}
+	if err := Enable(PassphraseMode, saltFile, prompt("s3cr3t")); err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := Encrypt(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(encrypted) || bytes.Contains(encrypted, plain) {
+		t.Fatalf("expected encrypted data, got %q", encrypted)
+	}
+	decrypted, err := Decrypt(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := decrypted, plain; !bytes.Equal(got, want) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := prompts, 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if decrypted, err = Decrypt(plain); err != nil || !bytes.Equal(decrypted, plain) {
+		t.Fatalf("expected plain data to be read as is, got %s, %v", decrypted, err)
+	}
+
+	if err := Enable(PassphraseMode, saltFile, prompt("wrong")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Decrypt(encrypted); err == nil || !strings.Contains(err.Error(), "wrong key or passphrase") {
+		t.Fatalf("got %v, want wrong passphrase error", err)
+	}
+
+	os.Setenv(PassphraseEnv, "s3cr3t")
+	defer os.Unsetenv(PassphraseEnv)
+	if err := Enable(PassphraseMode, saltFile, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Decrypt(encrypted); err != nil {
+		t.Fatal(err)
+	}
+
+	Disable()
+	if _, err := Decrypt(encrypted); err == nil {
+		t.Fatal("expected error reading encrypted data without key")
+	}
+	if err := Enable("plain", saltFile, nil); err == nil {
+		t.Fatal("expected error for invalid mode")
+	}
+}
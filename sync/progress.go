@@ -0,0 +1,95 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/wallix/awless/config"
+)
+
+const progressFilename = "sync.progress.json"
+
+// Progress records the services left to sync after a sync failed halfway (throttling,
+// network failure, ...), so that the next sync resumes with them only
+type Progress struct {
+	Target   string    `json:"target"`
+	Pending  []string  `json:"pending"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// Error gathers the errors of a sync with the services that were not synced completely
+type Error struct {
+	Services []string
+	errs     []error
+}
+
+func (e *Error) Error() string {
+	return concatErrors(e.errs).Error()
+}
+
+// FailedServices returns the services that were not synced completely by a sync returning err
+func FailedServices(err error) []string {
+	if serr, ok := err.(*Error); ok {
+		return serr.Services
+	}
+	return nil
+}
+
+// LoadProgress returns the progress of the last failed sync of target (ex: region and profile),
+// or nil when the last sync of target succeeded
+func LoadProgress(target string) (*Progress, error) {
+	data, err := ioutil.ReadFile(progressPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	p := &Progress{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	if p.Target != target || len(p.Pending) == 0 {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// SaveProgress records the services of target left to sync. Without pending services,
+// the progress is cleared
+func SaveProgress(target string, pending []string) error {
+	if len(pending) == 0 {
+		if err := os.Remove(progressPath()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(&Progress{Target: target, Pending: pending, FailedAt: time.Now().UTC()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(progressPath(), data, 0600)
+}
+
+func progressPath() string {
+	return filepath.Join(config.Dir, progressFilename)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/wallix/awless/config"
+)
+
+func TestProgress(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-progress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(d string) { config.Dir = d }(config.Dir)
+	config.Dir = dir
+
+	if p, err := LoadProgress("eu-west-1"); err != nil || p != nil {
+		t.Fatalf("got %v, %v, want no progress", p, err)
+	}
+	if err := SaveProgress("eu-west-1", []string{"infra", "storage"}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadProgress("eu-west-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.Pending, []string{"infra", "storage"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if p, _ := LoadProgress("us-east-1"); p != nil {
+		t.Fatalf("got %v, want no progress for another target", p)
+	}
+	if err := SaveProgress("eu-west-1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := LoadProgress("eu-west-1"); p != nil {
+		t.Fatalf("got %v, want cleared progress", p)
+	}
+}
+
+func TestFailedServices(t *testing.T) {
+	err := &Error{Services: []string{"access"}}
+	if got, want := FailedServices(err), []string{"access"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got := FailedServices(nil); got != nil {
+		t.Fatalf("got %v, want nil", got)
+	}
+}
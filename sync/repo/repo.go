@@ -28,6 +28,7 @@ import (
 
 	"github.com/wallix/awless/config"
 	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/statecrypt"
 
 	git "gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
@@ -163,7 +164,11 @@ func unmarshalIntoGraph(g *graph.Graph, commit *object.Commit, filename string)
 		if err != nil {
 			return err
 		}
-		g.Unmarshal([]byte(contents))
+		plain, err := statecrypt.Decrypt([]byte(contents))
+		if err != nil {
+			return err
+		}
+		g.Unmarshal(plain)
 	}
 	return nil
 }
@@ -17,10 +17,13 @@ limitations under the License.
 package sync
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	gosync "sync"
 	"time"
@@ -29,11 +32,41 @@ import (
 	"github.com/wallix/awless/config"
 	"github.com/wallix/awless/graph"
 	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/statecrypt"
 	"github.com/wallix/awless/sync/repo"
+	"github.com/wallix/awless/tracing"
 )
 
 var DefaultSyncer Syncer
 
+// GraphFormatKey is the config key of the format of the local graphs written by sync
+const GraphFormatKey = "graph.format"
+
+const (
+	// BinaryGraphFormat writes local graphs as binary snapshots, faster to load (default)
+	BinaryGraphFormat = "binary"
+	// TextGraphFormat writes local graphs as text triples
+	TextGraphFormat = "text"
+)
+
+// GraphFormat is the format of the local graphs written. Graphs are read in any format
+var GraphFormat = BinaryGraphFormat
+
+// CheckGraphFormat returns an error for unknown graph formats
+func CheckGraphFormat(format string) error {
+	if format != BinaryGraphFormat && format != TextGraphFormat {
+		return fmt.Errorf("invalid graph format '%s': expecting %s or %s", format, BinaryGraphFormat, TextGraphFormat)
+	}
+	return nil
+}
+
+func marshalGraph(g *graph.Graph) ([]byte, error) {
+	if GraphFormat == TextGraphFormat {
+		return g.Marshal()
+	}
+	return g.MarshalSnapshot()
+}
+
 type Syncer interface {
 	repo.Repo
 	Sync(...cloud.Service) (map[string]*graph.Graph, error)
@@ -46,14 +79,30 @@ type syncer struct {
 }
 
 func NewSyncer() Syncer {
-	repo, err := repo.New()
-	if err != nil {
-		panic(err)
-	}
+	return &syncer{Repo: &lazyRepo{}, logger: logger.DiscardLogger}
+}
+
+// lazyRepo opens (or creates) the local repository on first use, sparing
+// its setup to commands that never sync nor read the resources history
+type lazyRepo struct {
+	once gosync.Once
+	repo repo.Repo
+}
 
-	return &syncer{Repo: repo, logger: logger.DiscardLogger}
+func (r *lazyRepo) get() repo.Repo {
+	r.once.Do(func() {
+		var err error
+		if r.repo, err = repo.New(); err != nil {
+			panic(err)
+		}
+	})
+	return r.repo
 }
 
+func (r *lazyRepo) Commit(files ...string) error              { return r.get().Commit(files...) }
+func (r *lazyRepo) List() ([]*repo.Rev, error)                { return r.get().List() }
+func (r *lazyRepo) LoadRev(version string) (*repo.Rev, error) { return r.get().LoadRev(version) }
+
 func (s *syncer) SetLogger(l *logger.Logger) { s.logger = l }
 
 func (s *syncer) Sync(services ...cloud.Service) (map[string]*graph.Graph, error) {
@@ -79,7 +128,10 @@ func (s *syncer) Sync(services ...cloud.Service) (map[string]*graph.Graph, error
 		go func(srv cloud.Service) {
 			defer workers.Done()
 			start := time.Now()
+			span := tracing.Start("sync "+srv.Name(), tracing.KindInternal)
+			span.SetAttribute("awless.service", srv.Name())
 			g, err := srv.FetchResources()
+			span.Finish(err)
 			errorc <- &srvErr{name: srv.Name(), err: err}
 			resultc <- &result{name: srv.Name(), gph: g, start: start}
 		}(service)
@@ -92,6 +144,7 @@ func (s *syncer) Sync(services ...cloud.Service) (map[string]*graph.Graph, error
 	}()
 
 	var allErrors []error
+	failed := make(map[string]bool)
 
 Loop:
 	for {
@@ -99,6 +152,7 @@ Loop:
 		case srvErr, ok := <-errorc:
 			if ok && srvErr.err != nil {
 				allErrors = append(allErrors, fmt.Errorf("syncing %s: %s", srvErr.name, srvErr.err))
+				failed[srvErr.name] = true
 			}
 		case res, ok := <-resultc:
 			if !ok {
@@ -108,18 +162,26 @@ Loop:
 			graphs[res.name] = res.gph
 		}
 	}
+	for srvErr := range errorc {
+		if srvErr.err != nil {
+			allErrors = append(allErrors, fmt.Errorf("syncing %s: %s", srvErr.name, srvErr.err))
+			failed[srvErr.name] = true
+		}
+	}
 
 	var filenames []string
 
 	for name, g := range graphs {
 		filename := fmt.Sprintf("%s.rdf", name)
-		tofile, err := g.Marshal()
+		tofile, err := marshalGraph(g)
 		if err != nil {
 			allErrors = append(allErrors, fmt.Errorf("marshal %s: %s", filename, err))
+			failed[name] = true
 		}
 		filepath := filepath.Join(config.RepoDir, filename)
-		if err = ioutil.WriteFile(filepath, tofile, 0600); err != nil {
+		if err = writeGraphFile(filepath, tofile); err != nil {
 			allErrors = append(allErrors, fmt.Errorf("writing %s: %s", filepath, err))
+			failed[name] = true
 		}
 		filenames = append(filenames, filename)
 	}
@@ -128,7 +190,15 @@ Loop:
 		allErrors = append(allErrors, fmt.Errorf("commit %s: %s", strings.Join(filenames, ", "), err))
 	}
 
-	return graphs, concatErrors(allErrors)
+	if len(allErrors) == 0 {
+		return graphs, nil
+	}
+	var failedServices []string
+	for name := range failed {
+		failedServices = append(failedServices, name)
+	}
+	sort.Strings(failedServices)
+	return graphs, &Error{Services: failedServices, errs: allErrors}
 }
 
 func concatErrors(errs []error) error {
@@ -146,9 +216,50 @@ func concatErrors(errs []error) error {
 
 func LoadCurrentLocalGraph(serviceName string) *graph.Graph {
 	path := filepath.Join(config.RepoDir, fmt.Sprintf("%s.rdf", serviceName))
-	g, err := graph.NewGraphFromFile(path)
+	data, err := statecrypt.ReadFile(path)
 	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Errorf("loading local %s graph: %s", serviceName, err)
+		}
+		return graph.NewGraph()
+	}
+	g := graph.NewGraph()
+	if err := g.Unmarshal(data); err != nil {
 		return graph.NewGraph()
 	}
 	return g
 }
+
+// CheckLocalGraph verifies that the local graph of a service, if any, can be read and decoded
+func CheckLocalGraph(serviceName string) error {
+	path := filepath.Join(config.RepoDir, fmt.Sprintf("%s.rdf", serviceName))
+	data, err := statecrypt.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return graph.NewGraph().Unmarshal(data)
+}
+
+// SaveLocalGraph overwrites the local graph of a service, without committing it.
+// The next sync records the change in the local resources history
+func SaveLocalGraph(serviceName string, g *graph.Graph) error {
+	data, err := marshalGraph(g)
+	if err != nil {
+		return err
+	}
+	return writeGraphFile(filepath.Join(config.RepoDir, fmt.Sprintf("%s.rdf", serviceName)), data)
+}
+
+// writeGraphFile leaves the file untouched when its content does not change, so that
+// encrypted graphs (never twice the same ciphertext) do not add snapshots on each sync
+func writeGraphFile(path string, data []byte) error {
+	if raw, err := ioutil.ReadFile(path); err == nil && statecrypt.IsEncrypted(raw) == statecrypt.Enabled() {
+		if existing, err := statecrypt.Decrypt(raw); err == nil && bytes.Equal(existing, data) {
+			return nil
+		}
+	}
+	return statecrypt.WriteFile(path, data, 0600)
+}
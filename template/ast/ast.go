@@ -18,9 +18,17 @@ package ast
 
 import (
 	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
+// GrammarVersion identifies the template grammar (awless-template-syntax.peg) and the AST it builds.
+// Increment it on any change to them, so that templates parsed by previous versions are parsed again
+const GrammarVersion = 26
+
 type Node interface {
 	clone() Node
 	String() string
@@ -28,21 +36,72 @@ type Node interface {
 
 type AST struct {
 	Statements []*Statement
+	// Comments are the comment lines following the last statement (see Statement.Comments)
+	Comments []string
 
 	// state to build the AST
 	currentStatement *Statement
 	currentKey       string
+	currentMapKey    string
+	currentRepeat    *Repeat
+	// paramSets are the params of the sets declared in the template by name (see ParamSetNode),
+	// copied into the statements splatting them (ex: create instance ...webdefaults subnet=$sub)
+	paramSets map[string]*CommandNode
+	buildErr  error
+	// source is the parsed text, scanned from scanOffset at scanPos to set the positions of the statements and params
+	source     string
+	scanOffset int
+	scanPos    Position
+}
+
+// BuildError returns the first error met building the AST of a parsed template (ex: splatting an undeclared param set)
+func (a *AST) BuildError() error {
+	return a.buildErr
 }
 
 type Statement struct {
 	Node
+	// Comment is the trailing comment of the statement line (ex: create vpc cidr=10.0.0.0/16 # main vpc)
+	Comment string
+	// Comments are the lines preceding the statement since the previous one: comment lines with their
+	// marker (ex: # network), and empty strings for blank lines
+	Comments []string
+	// Pos is the position of the statement in the template text, at its declared identifier or action
+	Pos Position
+	// Repeat is set on the statements to run several times (ex: repeat 3: create instance name="web-$i")
+	Repeat *Repeat
+}
+
+// Repeat is the number of times a statement is run, given directly or by a hole (ex: repeat {count}: ...).
+// Repeated statements are expanded into copies once the count is known, the ref $i being set to the index
+// of each copy starting at 1
+type Repeat struct {
+	Count int
+	Hole  string
+}
+
+func (r *Repeat) String() string {
+	if r.Hole != "" {
+		return fmt.Sprintf("repeat {%s}:", r.Hole)
+	}
+	return fmt.Sprintf("repeat %d:", r.Count)
 }
 
+// RepeatIndexRef is the ref set to the index of each copy of a repeated statement
+const RepeatIndexRef = "i"
+
 type DeclarationNode struct {
 	Ident string
 	Expr  ExpressionNode
 }
 
+// ParamSetNode declares a named set of params splatted into the following statements (ex: webdefaults = type=t2.micro count=1).
+// Splats are expanded once parsed: the params of a set include the ones of the sets it splats
+type ParamSetNode struct {
+	Ident  string
+	Params *CommandNode
+}
+
 type ExpressionNode interface {
 	Node
 	Result() interface{}
@@ -58,13 +117,94 @@ type CommandNode struct {
 	Params         map[string]interface{}
 	Aliases        map[string]string
 	Holes          map[string]string
+	// HoleTypes are the types declared by the holes of params (ex: count={count as int}), checked when holes are filled
+	HoleTypes map[string]string
+	// Optionals are the keys of the params declared optional (ex: keypair?={keypair}), dropped when left without value
+	Optionals map[string]bool
+	// ParamPositions are the positions of the params in the template text, by key
+	ParamPositions map[string]Position
+}
+
+// FileValue is the path of a file given as a param value (ex: userdata=@./cloud-init.yaml),
+// replaced by the content of the file before the template is run
+type FileValue string
+
+func (f FileValue) String() string { return "@" + string(f) }
+
+// ConcatenationValue is a param value concatenating strings and holes (ex: name={env}+"-web-"+{index}),
+// replaced by the resulting string once all its holes are filled
+type ConcatenationValue []interface{}
+
+// FuncValue is a call to a built-in function given as param value (ex: name=uuid() or cidr=cidrsubnet(10.0.0.0/16,8,1)),
+// replaced by its result when the template is compiled. Args can be holes
+type FuncValue struct {
+	Name string
+	Args []interface{}
+}
+
+func (f *FuncValue) String() string {
+	var args []string
+	for _, arg := range f.Args {
+		args = append(args, quoteValue(arg))
+	}
+	return fmt.Sprintf("%s(%s)", f.Name, strings.Join(args, ","))
+}
+
+// Hole is a hole inside a concatenation value or the args of a function
+type Hole string
+
+func (h Hole) String() string { return "{" + string(h) + "}" }
+
+func (c ConcatenationValue) String() string {
+	var items []string
+	for _, item := range c {
+		if str, ok := item.(string); ok {
+			items = append(items, strconv.Quote(str))
+		} else {
+			items = append(items, fmt.Sprint(item))
+		}
+	}
+	return strings.Join(items, "+")
+}
+
+// fill replaces the holes of the concatenation having a value, returning the string
+// concatenated once there are no holes left
+func (c ConcatenationValue) fill(fill func(hole string) (interface{}, bool)) (interface{}, bool) {
+	var filled ConcatenationValue
+	changed, complete := false, true
+	for _, item := range c {
+		if hole, ok := item.(Hole); ok {
+			if val, ok := fill(string(hole)); ok {
+				item = fmt.Sprint(val)
+				changed = true
+			} else {
+				complete = false
+			}
+		}
+		filled = append(filled, item)
+	}
+	if !complete {
+		return filled, changed
+	}
+	var items []string
+	for _, item := range filled {
+		items = append(items, item.(string))
+	}
+	return strings.Join(items, ""), true
 }
 
 func (n *CommandNode) Result() interface{} { return n.CmdResult }
 func (n *CommandNode) Err() error          { return n.CmdErr }
 
 func (s *Statement) clone() *Statement {
-	newStat := &Statement{}
+	newStat := &Statement{Comment: s.Comment, Pos: s.Pos}
+	if s.Comments != nil {
+		newStat.Comments = append([]string{}, s.Comments...)
+	}
+	if s.Repeat != nil {
+		repeat := *s.Repeat
+		newStat.Repeat = &repeat
+	}
 	newStat.Node = s.Node.clone()
 
 	return newStat
@@ -73,7 +213,14 @@ func (s *Statement) clone() *Statement {
 func (a *AST) String() string {
 	var all []string
 	for _, stat := range a.Statements {
-		all = append(all, stat.String())
+		line := stat.String()
+		if stat.Repeat != nil {
+			line = fmt.Sprintf("%s %s", stat.Repeat, line)
+		}
+		if stat.Comment != "" {
+			line = fmt.Sprintf("%s # %s", line, stat.Comment)
+		}
+		all = append(all, line)
 	}
 	return strings.Join(all, "\n")
 }
@@ -89,6 +236,18 @@ func (n *DeclarationNode) String() string {
 	return fmt.Sprintf("%s = %s", n.Ident, n.Expr)
 }
 
+func (n *ParamSetNode) clone() Node {
+	return &ParamSetNode{Ident: n.Ident, Params: n.Params.clone().(*CommandNode)}
+}
+
+func (n *ParamSetNode) String() string {
+	var all []string
+	for _, param := range n.Params.ParamNodes() {
+		all = append(all, param.String())
+	}
+	return fmt.Sprintf("%s = %s", n.Ident, strings.Join(all, " "))
+}
+
 func (n *CommandNode) clone() Node {
 	cmd := &CommandNode{
 		Action: n.Action, Entity: n.Entity,
@@ -110,6 +269,24 @@ func (n *CommandNode) clone() Node {
 	for k, v := range n.Holes {
 		cmd.Holes[k] = v
 	}
+	if n.HoleTypes != nil {
+		cmd.HoleTypes = make(map[string]string)
+		for k, v := range n.HoleTypes {
+			cmd.HoleTypes[k] = v
+		}
+	}
+	if n.Optionals != nil {
+		cmd.Optionals = make(map[string]bool)
+		for k, v := range n.Optionals {
+			cmd.Optionals[k] = v
+		}
+	}
+	if n.ParamPositions != nil {
+		cmd.ParamPositions = make(map[string]Position)
+		for k, v := range n.ParamPositions {
+			cmd.ParamPositions[k] = v
+		}
+	}
 
 	return cmd
 }
@@ -117,33 +294,272 @@ func (n *CommandNode) clone() Node {
 func (n *CommandNode) String() string {
 	var all []string
 	for k, v := range n.Refs {
-		all = append(all, fmt.Sprintf("%s=$%v", k, v))
+		all = append(all, fmt.Sprintf("%s=$%v", n.paramKey(k), v))
 	}
 	for k, v := range n.Params {
-		all = append(all, fmt.Sprintf("%s=%v", k, v))
+		all = append(all, fmt.Sprintf("%s=%s", n.paramKey(k), quoteValue(v)))
 	}
 	for k, v := range n.Aliases {
-		all = append(all, fmt.Sprintf("%s=@%s", k, v))
+		all = append(all, fmt.Sprintf("%s=@%s", n.paramKey(k), v))
 	}
 	for k, v := range n.Holes {
-		all = append(all, fmt.Sprintf("%s={%s}", k, v))
+		if typ, ok := n.HoleTypes[k]; ok {
+			all = append(all, fmt.Sprintf("%s={%s as %s}", n.paramKey(k), v, typ))
+		} else {
+			all = append(all, fmt.Sprintf("%s={%s}", n.paramKey(k), v))
+		}
 	}
 	return fmt.Sprintf("%s %s %s", n.Action, n.Entity, strings.Join(all, " "))
 }
 
-func (n *CommandNode) ProcessHoles(fills map[string]interface{}) map[string]interface{} {
+func (n *CommandNode) paramKey(key string) string {
+	if n.Optionals[key] {
+		return key + "?"
+	}
+	return key
+}
+
+// DropUnfilledOptionals removes the optional params whose value still holds holes,
+// no value having been given for them. It returns the keys of the removed params
+func (n *CommandNode) DropUnfilledOptionals() (dropped []string) {
+	for key := range n.Optionals {
+		_, isHole := n.Holes[key]
+		if !isHole && len(valueHoles(n.Params[key])) == 0 {
+			continue
+		}
+		delete(n.Holes, key)
+		delete(n.HoleTypes, key)
+		delete(n.Params, key)
+		delete(n.Optionals, key)
+		dropped = append(dropped, key)
+	}
+	return
+}
+
+func (n *CommandNode) ProcessHoles(fills map[string]interface{}) (map[string]interface{}, error) {
 	processed := make(map[string]interface{})
 	if n.Params == nil {
 		n.Params = make(map[string]interface{})
 	}
 	for key, hole := range n.Holes {
-		if val, ok := fills[hole]; ok {
-			n.Params[key] = val
-			processed[key] = val
-			delete(n.Holes, key)
+		val, ok := fills[hole]
+		if !ok {
+			continue
+		}
+		if typ, ok := n.HoleTypes[key]; ok {
+			var err error
+			if val, err = CastHoleValue(typ, val); err != nil {
+				return processed, LocateError(n.ParamPositions[key], fmt.Errorf("%s %s: %s: %s", n.Action, n.Entity, hole, err))
+			}
+			delete(n.HoleTypes, key)
+		}
+		n.Params[key] = val
+		processed[key] = val
+		delete(n.Holes, key)
+	}
+	for key, val := range n.Params {
+		if fn, ok := val.(*FuncValue); ok {
+			filled := &FuncValue{Name: fn.Name}
+			for _, arg := range fn.Args {
+				if hole, ok := arg.(Hole); ok {
+					if fill, ok := fills[string(hole)]; ok {
+						arg = fill
+					}
+				}
+				filled.Args = append(filled.Args, arg)
+			}
+			n.Params[key] = filled
+			continue
+		}
+		embeddedFill := func(hole string) (interface{}, bool) {
+			fill, ok := fills[hole]
+			if quote := n.embeddedQuoter(key); ok && quote != nil {
+				return quote(fmt.Sprint(fill)), true
+			}
+			return fill, ok
+		}
+		if concat, ok := val.(ConcatenationValue); ok {
+			if filled, changed := concat.fill(embeddedFill); changed {
+				n.Params[key] = filled
+				processed[key] = filled
+			}
+			continue
+		}
+		str, ok := val.(string)
+		if !ok {
+			continue
+		}
+		filled := replaceEmbeddedHoles(str, embeddedFill)
+		if filled != str {
+			n.Params[key] = filled
+			processed[key] = filled
 		}
 	}
-	return processed
+	return processed, nil
+}
+
+// CastHoleValue checks that the value filling a hole is of the type declared by the hole,
+// converting the values given as strings (ex: typed in at prompt) to this type
+func CastHoleValue(typ string, val interface{}) (interface{}, error) {
+	str, isStr := val.(string)
+	switch typ {
+	case "string":
+		return val, nil
+	case "int":
+		if _, ok := val.(int); ok {
+			return val, nil
+		}
+		if num, err := strconv.Atoi(str); isStr && err == nil {
+			return num, nil
+		}
+	case "float":
+		switch v := val.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		}
+		if num, err := strconv.ParseFloat(str, 64); isStr && err == nil {
+			return num, nil
+		}
+	case "bool":
+		if _, ok := val.(bool); ok {
+			return val, nil
+		}
+		if b, err := strconv.ParseBool(str); isStr && err == nil {
+			return b, nil
+		}
+	case "cidr":
+		if _, ipnet, err := net.ParseCIDR(str); isStr && err == nil {
+			return ipnet.String(), nil
+		}
+	case "ip":
+		if ip := net.ParseIP(str); isStr && ip != nil {
+			return ip.String(), nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown type '%s'", typ)
+	}
+	return nil, fmt.Errorf("invalid %s value '%v'", typ, val)
+}
+
+// embeddedQuoters quote the values filling the holes and references embedded in params, indexed by statement and param
+var embeddedQuoters = make(map[string]func(string) string)
+
+// RegisterEmbeddedQuoter sets the quoting of the values filling the holes and references embedded in a param
+// of the statements of the action and entity (ex: shell quoting of the cmd of run local statements).
+// Values filling a whole param are not quoted
+func RegisterEmbeddedQuoter(action, entity, param string, quote func(string) string) {
+	embeddedQuoters[action+" "+entity+" "+param] = quote
+}
+
+func (n *CommandNode) embeddedQuoter(param string) func(string) string {
+	return embeddedQuoters[n.Action+" "+n.Entity+" "+param]
+}
+
+// embeddedHoleRegex matches the holes inside quoted, heredoc or file values, written with a leading '@'
+// (ex: cmd="ansible-playbook -i @{inventory}") so that other braces are left as is (ex: awk '{print}', JSON, ${HOME})
+var embeddedHoleRegex = regexp.MustCompile(`@{\s*([\pL\pM_.-]+)\s*}`)
+
+func replaceEmbeddedHoles(str string, fill func(hole string) (interface{}, bool)) string {
+	return embeddedHoleRegex.ReplaceAllStringFunc(str, func(match string) string {
+		if val, ok := fill(embeddedHoleRegex.FindStringSubmatch(match)[1]); ok {
+			return fmt.Sprint(val)
+		}
+		return match
+	})
+}
+
+// EmbeddedHoles returns the holes left inside the quoted, file, concatenation or function values of the params
+func (n *CommandNode) EmbeddedHoles() (holes []string) {
+	for _, val := range n.Params {
+		holes = append(holes, valueHoles(val)...)
+	}
+	return
+}
+
+func valueHoles(val interface{}) (holes []string) {
+	if fn, ok := val.(*FuncValue); ok {
+		for _, arg := range fn.Args {
+			if hole, ok := arg.(Hole); ok {
+				holes = append(holes, string(hole))
+			}
+		}
+	}
+	if concat, ok := val.(ConcatenationValue); ok {
+		for _, item := range concat {
+			if hole, ok := item.(Hole); ok {
+				holes = append(holes, string(hole))
+			}
+		}
+	}
+	if str, ok := val.(string); ok {
+		replaceEmbeddedHoles(str, func(hole string) (interface{}, bool) {
+			holes = append(holes, hole)
+			return nil, false
+		})
+	}
+	return
+}
+
+// embeddedRefRegex matches the references inside quoted or file values. Ex: cmd="curl $elb"
+var embeddedRefRegex = regexp.MustCompile(`\$([\pL\pM_.-]+)`)
+
+// plainValueRegex matches the string values written without quotes in templates
+var plainValueRegex = regexp.MustCompile(`^[a-zA-Z0-9-._:/*?]+$`)
+
+// ambiguousStringRegex matches the string values that may be parsed as another type when written without quotes
+var ambiguousStringRegex = regexp.MustCompile(`^(-?[0-9]+|[0-9]+\.[0-9]+|true|false)$`)
+
+// isAmbiguousString tells whether a string value is parsed as another type when written without quotes.
+// Floats not written in their shortest form stay strings (ex: 1.10)
+func isAmbiguousString(str string) bool {
+	if !ambiguousStringRegex.MatchString(str) {
+		return false
+	}
+	if num, err := strconv.ParseFloat(str, 64); err == nil && strings.Contains(str, ".") {
+		return isShortestFloat(num, str)
+	}
+	return true
+}
+
+// quoteValue quotes and escapes the string values needing it to be parsed again
+func quoteValue(v interface{}) string {
+	switch list := v.(type) {
+	case ConcatenationValue:
+		return list.String()
+	case *FuncValue:
+		return list.String()
+	case Hole:
+		return list.String()
+	case []interface{}:
+		var items []string
+		for _, item := range list {
+			items = append(items, quoteValue(item))
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	case []string:
+		var items []string
+		for _, item := range list {
+			items = append(items, quoteValue(item))
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	case map[string]interface{}:
+		var entries []string
+		for k, item := range list {
+			entries = append(entries, k+":"+quoteValue(item))
+		}
+		sort.Strings(entries)
+		return "{" + strings.Join(entries, ",") + "}"
+	}
+	if num, ok := v.(float64); ok {
+		return strconv.FormatFloat(num, 'f', -1, 64)
+	}
+	str, ok := v.(string)
+	if !ok || (plainValueRegex.MatchString(str) && !isAmbiguousString(str)) {
+		return fmt.Sprint(v)
+	}
+	return strconv.Quote(str)
 }
 
 func (n *CommandNode) ProcessRefs(fills map[string]interface{}) {
@@ -156,6 +572,81 @@ func (n *CommandNode) ProcessRefs(fills map[string]interface{}) {
 			delete(n.Refs, key)
 		}
 	}
+	for key, val := range n.Params {
+		str, ok := val.(string)
+		if !ok {
+			continue
+		}
+		n.Params[key] = embeddedRefRegex.ReplaceAllStringFunc(str, func(match string) string {
+			// only declared variables are replaced, leaving shell variables (ex: $HOME) and punctuation
+			ref := strings.TrimRight(match[1:], ".-")
+			if val, ok := fills[ref]; ok {
+				if quote := n.embeddedQuoter(key); quote != nil {
+					return quote(fmt.Sprint(val)) + match[1+len(ref):]
+				}
+				return fmt.Sprint(val) + match[1+len(ref):]
+			}
+			return match
+		})
+	}
+}
+
+// ExpandRepeats replaces the repeated statements whose count is known by as many copies of the statement.
+// Counts below 1 are rejected rather than leaving the statement out
+func (a *AST) ExpandRepeats() error {
+	var expanded []*Statement
+	for _, stat := range a.Statements {
+		if stat.Repeat == nil || stat.Repeat.Hole != "" {
+			expanded = append(expanded, stat)
+			continue
+		}
+		if stat.Repeat.Count < 1 {
+			return LocateError(stat.Pos, fmt.Errorf("%s %s: count must be at least 1", stat.Repeat, stat.Node))
+		}
+		for i := 1; i <= stat.Repeat.Count; i++ {
+			copied := stat.clone()
+			copied.Repeat = nil
+			switch n := copied.Node.(type) {
+			case *CommandNode:
+				n.ProcessRefs(map[string]interface{}{RepeatIndexRef: i})
+			case *DeclarationNode:
+				if cmd, ok := n.Expr.(*CommandNode); ok {
+					cmd.ProcessRefs(map[string]interface{}{RepeatIndexRef: i})
+				}
+			}
+			expanded = append(expanded, copied)
+		}
+	}
+	a.Statements = expanded
+	return nil
+}
+
+// ExpandCommands replaces the command statements for which expand returns values by a copy of the statement
+// per value, the param key of each copy being set to one of the values
+func (a *AST) ExpandCommands(key string, expand func(cmd *CommandNode) ([]interface{}, error)) error {
+	var expanded []*Statement
+	for _, stat := range a.Statements {
+		cmd, ok := stat.Node.(*CommandNode)
+		if !ok {
+			expanded = append(expanded, stat)
+			continue
+		}
+		values, err := expand(cmd)
+		if err != nil {
+			return err
+		}
+		if values == nil {
+			expanded = append(expanded, stat)
+			continue
+		}
+		for _, val := range values {
+			copied := stat.clone()
+			copied.Node.(*CommandNode).Params[key] = val
+			expanded = append(expanded, copied)
+		}
+	}
+	a.Statements = expanded
+	return nil
 }
 
 func (a *AST) Clone() *AST {
@@ -163,5 +654,8 @@ func (a *AST) Clone() *AST {
 	for _, stat := range a.Statements {
 		clone.Statements = append(clone.Statements, stat.clone())
 	}
+	if a.Comments != nil {
+		clone.Comments = append([]string{}, a.Comments...)
+	}
 	return clone
 }
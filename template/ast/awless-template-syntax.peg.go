@@ -1,13 +1,24 @@
 package ast
 
+// Code generated by peg -inline -switch awless-template-syntax.peg DO NOT EDIT.
+// Adapted to scan UTF-8 bytes by gen/pegbytes.
+
 import (
 	"fmt"
-	"math"
+	"io"
+	"os"
 	"sort"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
-const endSymbol rune = 1114112
+// endSymbol marks the end of the buffer, parsed as UTF-8 bytes.
+// 0xff never appears in valid UTF-8
+const endSymbol byte = 0xff
+
+// initialTokens is the initial capacity of the token storage, enough for one-liner templates
+const initialTokens = 64
 
 /* The rule types inferred from the grammar are below. */
 type pegRule uint8
@@ -19,11 +30,32 @@ const (
 	ruleAction
 	ruleEntity
 	ruleDeclaration
+	ruleRepeat
 	ruleExpr
 	ruleParams
+	ruleParamSet
+	ruleParamSplat
 	ruleParam
 	ruleIdentifier
+	ruleUnicodeLetter
 	ruleValue
+	ruleFuncValue
+	ruleFuncArg
+	ruleHeredocValue
+	ruleHeredocBody
+	ruleHeredocEnd
+	ruleConcatValue
+	ruleConcatItem
+	ruleListValue
+	ruleListItem
+	ruleMapValue
+	ruleMapEntry
+	ruleBoolValue
+	ruleFloatValue
+	ruleIpv6CidrValue
+	ruleIpv6Value
+	ruleIpv6Address
+	ruleGlobValue
 	ruleStringValue
 	ruleCidrValue
 	ruleIpValue
@@ -32,17 +64,24 @@ const (
 	ruleRefValue
 	ruleAliasValue
 	ruleHoleValue
+	ruleTypedHoleValue
+	ruleHoleType
+	ruleQuotedValue
+	ruleFileValue
+	ruleFilePath
 	ruleComment
+	ruleTrailingComment
 	ruleSpacing
 	ruleWhiteSpacing
 	ruleMustWhiteSpacing
 	ruleEqual
 	ruleSpace
 	ruleWhitespace
+	ruleLineContinuation
 	ruleEndOfLine
 	ruleEndOfFile
-	rulePegText
 	ruleAction0
+	rulePegText
 	ruleAction1
 	ruleAction2
 	ruleAction3
@@ -56,6 +95,41 @@ const (
 	ruleAction11
 	ruleAction12
 	ruleAction13
+	ruleAction14
+	ruleAction15
+	ruleAction16
+	ruleAction17
+	ruleAction18
+	ruleAction19
+	ruleAction20
+	ruleAction21
+	ruleAction22
+	ruleAction23
+	ruleAction24
+	ruleAction25
+	ruleAction26
+	ruleAction27
+	ruleAction28
+	ruleAction29
+	ruleAction30
+	ruleAction31
+	ruleAction32
+	ruleAction33
+	ruleAction34
+	ruleAction35
+	ruleAction36
+	ruleAction37
+	ruleAction38
+	ruleAction39
+	ruleAction40
+	ruleAction41
+	ruleAction42
+	ruleAction43
+	ruleAction44
+	ruleAction45
+	ruleAction46
+	ruleAction47
+	ruleAction48
 )
 
 var rul3s = [...]string{
@@ -65,11 +139,32 @@ var rul3s = [...]string{
 	"Action",
 	"Entity",
 	"Declaration",
+	"Repeat",
 	"Expr",
 	"Params",
+	"ParamSet",
+	"ParamSplat",
 	"Param",
 	"Identifier",
+	"UnicodeLetter",
 	"Value",
+	"FuncValue",
+	"FuncArg",
+	"HeredocValue",
+	"HeredocBody",
+	"HeredocEnd",
+	"ConcatValue",
+	"ConcatItem",
+	"ListValue",
+	"ListItem",
+	"MapValue",
+	"MapEntry",
+	"BoolValue",
+	"FloatValue",
+	"Ipv6CidrValue",
+	"Ipv6Value",
+	"Ipv6Address",
+	"GlobValue",
 	"StringValue",
 	"CidrValue",
 	"IpValue",
@@ -78,17 +173,24 @@ var rul3s = [...]string{
 	"RefValue",
 	"AliasValue",
 	"HoleValue",
+	"TypedHoleValue",
+	"HoleType",
+	"QuotedValue",
+	"FileValue",
+	"FilePath",
 	"Comment",
+	"TrailingComment",
 	"Spacing",
 	"WhiteSpacing",
 	"MustWhiteSpacing",
 	"Equal",
 	"Space",
 	"Whitespace",
+	"LineContinuation",
 	"EndOfLine",
 	"EndOfFile",
-	"PegText",
 	"Action0",
+	"PegText",
 	"Action1",
 	"Action2",
 	"Action3",
@@ -102,6 +204,41 @@ var rul3s = [...]string{
 	"Action11",
 	"Action12",
 	"Action13",
+	"Action14",
+	"Action15",
+	"Action16",
+	"Action17",
+	"Action18",
+	"Action19",
+	"Action20",
+	"Action21",
+	"Action22",
+	"Action23",
+	"Action24",
+	"Action25",
+	"Action26",
+	"Action27",
+	"Action28",
+	"Action29",
+	"Action30",
+	"Action31",
+	"Action32",
+	"Action33",
+	"Action34",
+	"Action35",
+	"Action36",
+	"Action37",
+	"Action38",
+	"Action39",
+	"Action40",
+	"Action41",
+	"Action42",
+	"Action43",
+	"Action44",
+	"Action45",
+	"Action46",
+	"Action47",
+	"Action48",
 }
 
 type token32 struct {
@@ -118,19 +255,19 @@ type node32 struct {
 	up, next *node32
 }
 
-func (node *node32) print(pretty bool, buffer string) {
+func (node *node32) print(w io.Writer, pretty bool, buffer string) {
 	var print func(node *node32, depth int)
 	print = func(node *node32, depth int) {
 		for node != nil {
 			for c := 0; c < depth; c++ {
-				fmt.Printf(" ")
+				fmt.Fprintf(w, " ")
 			}
 			rule := rul3s[node.pegRule]
-			quote := strconv.Quote(string(([]rune(buffer)[node.begin:node.end])))
+			quote := strconv.Quote(buffer[node.begin:node.end])
 			if !pretty {
-				fmt.Printf("%v %v\n", rule, quote)
+				fmt.Fprintf(w, "%v %v\n", rule, quote)
 			} else {
-				fmt.Printf("\x1B[34m%v\x1B[m %v\n", rule, quote)
+				fmt.Fprintf(w, "\x1B[36m%v\x1B[m %v\n", rule, quote)
 			}
 			if node.up != nil {
 				print(node.up, depth+1)
@@ -141,12 +278,12 @@ func (node *node32) print(pretty bool, buffer string) {
 	print(node, 0)
 }
 
-func (node *node32) Print(buffer string) {
-	node.print(false, buffer)
+func (node *node32) Print(w io.Writer, buffer string) {
+	node.print(w, false, buffer)
 }
 
-func (node *node32) PrettyPrint(buffer string) {
-	node.print(true, buffer)
+func (node *node32) PrettyPrint(w io.Writer, buffer string) {
+	node.print(w, true, buffer)
 }
 
 type tokens32 struct {
@@ -189,24 +326,24 @@ func (t *tokens32) AST() *node32 {
 }
 
 func (t *tokens32) PrintSyntaxTree(buffer string) {
-	t.AST().Print(buffer)
+	t.AST().Print(os.Stdout, buffer)
+}
+
+func (t *tokens32) WriteSyntaxTree(w io.Writer, buffer string) {
+	t.AST().Print(w, buffer)
 }
 
 func (t *tokens32) PrettyPrintSyntaxTree(buffer string) {
-	t.AST().PrettyPrint(buffer)
+	t.AST().PrettyPrint(os.Stdout, buffer)
 }
 
 func (t *tokens32) Add(rule pegRule, begin, end, index uint32) {
-	if tree := t.tree; int(index) >= len(tree) {
-		expanded := make([]token32, 2*len(tree))
-		copy(expanded, tree)
-		t.tree = expanded
-	}
-	t.tree[index] = token32{
-		pegRule: rule,
-		begin:   begin,
-		end:     end,
+	tree, i := t.tree, int(index)
+	if i >= len(tree) {
+		t.tree = append(tree, token32{pegRule: rule, begin: begin, end: end})
+		return
 	}
+	tree[i] = token32{pegRule: rule, begin: begin, end: end}
 }
 
 func (t *tokens32) Tokens() []token32 {
@@ -217,8 +354,8 @@ type Peg struct {
 	*AST
 
 	Buffer string
-	buffer []rune
-	rules  [43]func() bool
+	buffer string
+	rules  [106]func() bool
 	parse  func(rule ...int) error
 	reset  func()
 	Pretty bool
@@ -239,7 +376,7 @@ type textPosition struct {
 
 type textPositionMap map[int]textPosition
 
-func translatePositions(buffer []rune, positions []int) textPositionMap {
+func translatePositions(buffer string, positions []int) textPositionMap {
 	length, translations, j, line, symbol := len(positions), make(textPositionMap, len(positions)), 0, 1, 0
 	sort.Ints(positions)
 
@@ -270,7 +407,7 @@ type parseError struct {
 }
 
 func (e *parseError) Error() string {
-	tokens, error := []token32{e.max}, "\n"
+	tokens, err := []token32{e.max}, "\n"
 	positions, p := make([]int, 2*len(tokens)), 0
 	for _, token := range tokens {
 		positions[p], p = int(token.begin), p+1
@@ -283,14 +420,14 @@ func (e *parseError) Error() string {
 	}
 	for _, token := range tokens {
 		begin, end := int(token.begin), int(token.end)
-		error += fmt.Sprintf(format,
+		err += fmt.Sprintf(format,
 			rul3s[token.pegRule],
 			translations[begin].line, translations[begin].symbol,
 			translations[end].line, translations[end].symbol,
-			strconv.Quote(string(e.p.buffer[begin:end])))
+			strconv.Quote(e.p.buffer[begin:end]))
 	}
 
-	return error
+	return err
 }
 
 func (p *Peg) PrintSyntaxTree() {
@@ -301,6 +438,16 @@ func (p *Peg) PrintSyntaxTree() {
 	}
 }
 
+func (p *Peg) WriteSyntaxTree(w io.Writer) {
+	p.tokens32.WriteSyntaxTree(w, p.Buffer)
+}
+
+func (p *Peg) SprintSyntaxTree() string {
+	var bldr strings.Builder
+	p.WriteSyntaxTree(&bldr)
+	return bldr.String()
+}
+
 func (p *Peg) Execute() {
 	buffer, _buffer, text, begin, end := p.Buffer, p.buffer, "", 0, 0
 	for _, token := range p.Tokens() {
@@ -308,62 +455,155 @@ func (p *Peg) Execute() {
 
 		case rulePegText:
 			begin, end = int(token.begin), int(token.end)
-			text = string(_buffer[begin:end])
+			text = _buffer[begin:end]
 
 		case ruleAction0:
-			p.addDeclarationIdentifier(text)
+			p.setSource(_buffer)
 		case ruleAction1:
-			p.addAction(text)
+			p.addStatementSpacing(text)
 		case ruleAction2:
-			p.addEntity(text)
+			p.addDeclarationIdentifier(text, begin)
 		case ruleAction3:
-			p.LineDone()
+			p.addRepeatCount(text)
 		case ruleAction4:
-			p.addParamKey(text)
+			p.addRepeatHole(text)
 		case ruleAction5:
-			p.addParamHoleValue(text)
+			p.addAction(text, begin)
 		case ruleAction6:
-			p.addParamAliasValue(text)
+			p.addEntity(text)
 		case ruleAction7:
-			p.addParamRefValue(text)
+			p.LineDone()
 		case ruleAction8:
-			p.addParamCidrValue(text)
+			p.addParamSetIdentifier(text, begin)
 		case ruleAction9:
-			p.addParamIpValue(text)
+			p.LineDone()
 		case ruleAction10:
-			p.addParamValue(text)
+			p.addParamSplat(text)
 		case ruleAction11:
-			p.addParamIntValue(text)
+			p.addParamKey(text, begin)
 		case ruleAction12:
-			p.addParamValue(text)
+			p.addParamOptional()
 		case ruleAction13:
-			p.LineDone()
+			p.addParamHoleValue(text)
+		case ruleAction14:
+			p.addParamFileValue(text)
+		case ruleAction15:
+			p.addParamAliasValue(text)
+		case ruleAction16:
+			p.addParamRefValue(text)
+		case ruleAction17:
+			p.addParamCidrValue(text)
+		case ruleAction18:
+			p.addParamIpValue(text)
+		case ruleAction19:
+			p.addParamValue(text)
+		case ruleAction20:
+			p.addParamIntValue(text)
+		case ruleAction21:
+			p.addParamValue(text)
+		case ruleAction22:
+			p.addParamQuotedValue(text)
+		case ruleAction23:
+			p.addParamFuncValue(text)
+		case ruleAction24:
+			p.addParamFuncHoleArg(text)
+		case ruleAction25:
+			p.addParamFuncQuotedArg(text)
+		case ruleAction26:
+			p.addParamFuncArg(text)
+		case ruleAction27:
+			p.addParamValue(text)
+		case ruleAction28:
+			p.addParamConcatValue()
+		case ruleAction29:
+			p.addParamConcatHole(text)
+		case ruleAction30:
+			p.addParamConcatQuotedItem(text)
+		case ruleAction31:
+			p.addParamConcatItem(text)
+		case ruleAction32:
+			p.addParamListValue()
+		case ruleAction33:
+			p.addParamListValue()
+		case ruleAction34:
+			p.addParamListQuotedItem(text)
+		case ruleAction35:
+			p.addParamListItem(text)
+		case ruleAction36:
+			p.addParamMapValue()
+		case ruleAction37:
+			p.addParamMapKey(text)
+		case ruleAction38:
+			p.addParamMapQuotedItem(text)
+		case ruleAction39:
+			p.addParamMapItem(text)
+		case ruleAction40:
+			p.addParamBoolValue(text)
+		case ruleAction41:
+			p.addParamFloatValue(text)
+		case ruleAction42:
+			p.addParamIpv6CidrValue(text)
+		case ruleAction43:
+			p.addParamIpv6Value(text)
+		case ruleAction44:
+			p.addParamValue(text)
+		case ruleAction45:
+			p.addParamHoleValue(text)
+		case ruleAction46:
+			p.addParamHoleType(text)
+		case ruleAction47:
+			p.addCommentLine(text)
+		case ruleAction48:
+			p.addStatementComment(text)
 
 		}
 	}
 	_, _, _, _, _ = buffer, _buffer, text, begin, end
 }
 
-func (p *Peg) Init() {
+func Pretty(pretty bool) func(*Peg) error {
+	return func(p *Peg) error {
+		p.Pretty = pretty
+		return nil
+	}
+}
+
+func Size(size int) func(*Peg) error {
+	return func(p *Peg) error {
+		p.tokens32 = tokens32{tree: make([]token32, 0, size)}
+		return nil
+	}
+}
+func (p *Peg) Init(options ...func(*Peg) error) error {
 	var (
 		max                  token32
 		position, tokenIndex uint32
-		buffer               []rune
+		buffer               string
 	)
+	for _, option := range options {
+		err := option(p)
+		if err != nil {
+			return err
+		}
+	}
 	p.reset = func() {
 		max = token32{}
 		position, tokenIndex = 0, 0
 
-		p.buffer = []rune(p.Buffer)
-		if len(p.buffer) == 0 || p.buffer[len(p.buffer)-1] != endSymbol {
-			p.buffer = append(p.buffer, endSymbol)
+		p.buffer = p.Buffer
+		if strings.IndexByte(p.buffer, endSymbol) >= 0 {
+			p.buffer = strings.ToValidUTF8(p.buffer, string(utf8.RuneError))
 		}
+		p.buffer += string([]byte{endSymbol})
 		buffer = p.buffer
 	}
 	p.reset()
 
 	_rules := p.rules
-	tree := tokens32{tree: make([]token32, math.MaxInt16)}
+	tree := p.tokens32
+	if tree.tree == nil {
+		tree.tree = make([]token32, 0, initialTokens)
+	}
 	p.parse = func(rule ...int) error {
 		r := 1
 		if len(rule) > 0 {
@@ -388,7 +628,8 @@ func (p *Peg) Init() {
 
 	matchDot := func() bool {
 		if buffer[position] != endSymbol {
-			position++
+			_, size := utf8.DecodeRuneInString(buffer[position:])
+			position += uint32(size)
 			return true
 		}
 		return false
@@ -412,178 +653,204 @@ func (p *Peg) Init() {
 
 	_rules = [...]func() bool{
 		nil,
-		/* 0 Script <- <(Spacing Statement+ EndOfFile)> */
+		/* 0 Script <- <(Action0 Spacing Statement+ EndOfFile)> */
 		func() bool {
 			position0, tokenIndex0 := position, tokenIndex
 			{
 				position1 := position
+				{
+					add(ruleAction0, position)
+				}
 				if !_rules[ruleSpacing]() {
 					goto l0
 				}
 				{
-					position4 := position
+					position5 := position
 					if !_rules[ruleSpacing]() {
 						goto l0
 					}
 					{
-						position5, tokenIndex5 := position, tokenIndex
-						if !_rules[ruleExpr]() {
-							goto l6
-						}
-						goto l5
-					l6:
-						position, tokenIndex = position5, tokenIndex5
+						position6, tokenIndex6 := position, tokenIndex
 						{
-							position8 := position
+							position8, tokenIndex8 := position, tokenIndex
 							{
-								position9 := position
-								if !_rules[ruleIdentifier]() {
-									goto l7
+								position10 := position
+								if buffer[position] != 'r' {
+									goto l9
 								}
-								add(rulePegText, position9)
-							}
-							{
-								add(ruleAction0, position)
-							}
-							if !_rules[ruleEqual]() {
-								goto l7
-							}
-							if !_rules[ruleExpr]() {
-								goto l7
-							}
-							add(ruleDeclaration, position8)
-						}
-						goto l5
-					l7:
-						position, tokenIndex = position5, tokenIndex5
-						{
-							position11 := position
-							{
-								position12, tokenIndex12 := position, tokenIndex
-								if buffer[position] != rune('#') {
-									goto l13
+								position++
+								if buffer[position] != 'e' {
+									goto l9
 								}
 								position++
-							l14:
-								{
-									position15, tokenIndex15 := position, tokenIndex
-									{
-										position16, tokenIndex16 := position, tokenIndex
-										if !_rules[ruleEndOfLine]() {
-											goto l16
-										}
-										goto l15
-									l16:
-										position, tokenIndex = position16, tokenIndex16
-									}
-									if !matchDot() {
-										goto l15
-									}
-									goto l14
-								l15:
-									position, tokenIndex = position15, tokenIndex15
+								if buffer[position] != 'p' {
+									goto l9
+								}
+								position++
+								if buffer[position] != 'e' {
+									goto l9
 								}
-								goto l12
-							l13:
-								position, tokenIndex = position12, tokenIndex12
-								if buffer[position] != rune('/') {
-									goto l0
+								position++
+								if buffer[position] != 'a' {
+									goto l9
 								}
 								position++
-								if buffer[position] != rune('/') {
-									goto l0
+								if buffer[position] != 't' {
+									goto l9
 								}
 								position++
-							l17:
+								if !_rules[ruleMustWhiteSpacing]() {
+									goto l9
+								}
 								{
-									position18, tokenIndex18 := position, tokenIndex
+									position11, tokenIndex11 := position, tokenIndex
+									{
+										position13 := position
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l12
+										}
+										position++
+									l14:
+										{
+											position15, tokenIndex15 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l15
+											}
+											position++
+											goto l14
+										l15:
+											position, tokenIndex = position15, tokenIndex15
+										}
+										add(rulePegText, position13)
+									}
 									{
-										position19, tokenIndex19 := position, tokenIndex
-										if !_rules[ruleEndOfLine]() {
-											goto l19
+										add(ruleAction3, position)
+									}
+									goto l11
+								l12:
+									position, tokenIndex = position11, tokenIndex11
+									if buffer[position] != '{' {
+										goto l9
+									}
+									position++
+									if !_rules[ruleWhiteSpacing]() {
+										goto l9
+									}
+									{
+										position17 := position
+										if !_rules[ruleIdentifier]() {
+											goto l9
 										}
-										goto l18
-									l19:
-										position, tokenIndex = position19, tokenIndex19
+										add(rulePegText, position17)
+									}
+									if !_rules[ruleWhiteSpacing]() {
+										goto l9
 									}
-									if !matchDot() {
-										goto l18
+									if buffer[position] != '}' {
+										goto l9
+									}
+									position++
+									{
+										add(ruleAction4, position)
 									}
-									goto l17
-								l18:
-									position, tokenIndex = position18, tokenIndex18
+								}
+							l11:
+								if !_rules[ruleWhiteSpacing]() {
+									goto l9
+								}
+								if buffer[position] != ':' {
+									goto l9
+								}
+								position++
+								if !_rules[ruleWhiteSpacing]() {
+									goto l9
 								}
 								{
-									add(ruleAction13, position)
+									position19, tokenIndex19 := position, tokenIndex
+									if !_rules[ruleExpr]() {
+										goto l20
+									}
+									goto l19
+								l20:
+									position, tokenIndex = position19, tokenIndex19
+									if !_rules[ruleDeclaration]() {
+										goto l9
+									}
 								}
+							l19:
+								add(ruleRepeat, position10)
 							}
-						l12:
-							add(ruleComment, position11)
-						}
-					}
-				l5:
-					if !_rules[ruleSpacing]() {
-						goto l0
-					}
-				l21:
-					{
-						position22, tokenIndex22 := position, tokenIndex
-						if !_rules[ruleEndOfLine]() {
-							goto l22
-						}
-						goto l21
-					l22:
-						position, tokenIndex = position22, tokenIndex22
-					}
-					add(ruleStatement, position4)
-				}
-			l2:
-				{
-					position3, tokenIndex3 := position, tokenIndex
-					{
-						position23 := position
-						if !_rules[ruleSpacing]() {
-							goto l3
-						}
-						{
-							position24, tokenIndex24 := position, tokenIndex
+							goto l8
+						l9:
+							position, tokenIndex = position8, tokenIndex8
 							if !_rules[ruleExpr]() {
-								goto l25
+								goto l21
 							}
-							goto l24
-						l25:
-							position, tokenIndex = position24, tokenIndex24
+							goto l8
+						l21:
+							position, tokenIndex = position8, tokenIndex8
+							if !_rules[ruleDeclaration]() {
+								goto l22
+							}
+							goto l8
+						l22:
+							position, tokenIndex = position8, tokenIndex8
 							{
-								position27 := position
+								position23 := position
 								{
-									position28 := position
+									position24 := position
 									if !_rules[ruleIdentifier]() {
-										goto l26
+										goto l7
 									}
-									add(rulePegText, position28)
+									add(rulePegText, position24)
 								}
 								{
-									add(ruleAction0, position)
+									add(ruleAction8, position)
 								}
 								if !_rules[ruleEqual]() {
-									goto l26
+									goto l7
 								}
-								if !_rules[ruleExpr]() {
-									goto l26
+								if !_rules[ruleParams]() {
+									goto l7
 								}
-								add(ruleDeclaration, position27)
+								{
+									add(ruleAction9, position)
+								}
+								add(ruleParamSet, position23)
 							}
-							goto l24
-						l26:
-							position, tokenIndex = position24, tokenIndex24
+						}
+					l8:
+						{
+							position27, tokenIndex27 := position, tokenIndex
 							{
-								position30 := position
+								position29 := position
+								if !_rules[ruleWhiteSpacing]() {
+									goto l27
+								}
 								{
-									position31, tokenIndex31 := position, tokenIndex
-									if buffer[position] != rune('#') {
-										goto l32
+									position30, tokenIndex30 := position, tokenIndex
+									if buffer[position] != '#' {
+										goto l31
+									}
+									position++
+									goto l30
+								l31:
+									position, tokenIndex = position30, tokenIndex30
+									if buffer[position] != '/' {
+										goto l27
 									}
 									position++
+									if buffer[position] != '/' {
+										goto l27
+									}
+									position++
+								}
+							l30:
+								if !_rules[ruleWhiteSpacing]() {
+									goto l27
+								}
+								{
+									position32 := position
 								l33:
 									{
 										position34, tokenIndex34 := position, tokenIndex
@@ -603,2246 +870,5953 @@ func (p *Peg) Init() {
 									l34:
 										position, tokenIndex = position34, tokenIndex34
 									}
-									goto l31
-								l32:
-									position, tokenIndex = position31, tokenIndex31
-									if buffer[position] != rune('/') {
-										goto l3
-									}
-									position++
-									if buffer[position] != rune('/') {
-										goto l3
+									add(rulePegText, position32)
+								}
+								{
+									add(ruleAction48, position)
+								}
+								add(ruleTrailingComment, position29)
+							}
+							goto l28
+						l27:
+							position, tokenIndex = position27, tokenIndex27
+						}
+					l28:
+						goto l6
+					l7:
+						position, tokenIndex = position6, tokenIndex6
+						{
+							position37 := position
+							{
+								position38 := position
+								{
+									position39, tokenIndex39 := position, tokenIndex
+									if buffer[position] != '#' {
+										goto l40
 									}
 									position++
-								l36:
+								l41:
 									{
-										position37, tokenIndex37 := position, tokenIndex
+										position42, tokenIndex42 := position, tokenIndex
 										{
-											position38, tokenIndex38 := position, tokenIndex
+											position43, tokenIndex43 := position, tokenIndex
 											if !_rules[ruleEndOfLine]() {
-												goto l38
+												goto l43
 											}
-											goto l37
-										l38:
-											position, tokenIndex = position38, tokenIndex38
+											goto l42
+										l43:
+											position, tokenIndex = position43, tokenIndex43
 										}
 										if !matchDot() {
-											goto l37
+											goto l42
 										}
-										goto l36
-									l37:
-										position, tokenIndex = position37, tokenIndex37
+										goto l41
+									l42:
+										position, tokenIndex = position42, tokenIndex42
+									}
+									goto l39
+								l40:
+									position, tokenIndex = position39, tokenIndex39
+									if buffer[position] != '/' {
+										goto l0
+									}
+									position++
+									if buffer[position] != '/' {
+										goto l0
 									}
+									position++
+								l44:
 									{
-										add(ruleAction13, position)
+										position45, tokenIndex45 := position, tokenIndex
+										{
+											position46, tokenIndex46 := position, tokenIndex
+											if !_rules[ruleEndOfLine]() {
+												goto l46
+											}
+											goto l45
+										l46:
+											position, tokenIndex = position46, tokenIndex46
+										}
+										if !matchDot() {
+											goto l45
+										}
+										goto l44
+									l45:
+										position, tokenIndex = position45, tokenIndex45
 									}
 								}
-							l31:
-								add(ruleComment, position30)
+							l39:
+								add(rulePegText, position38)
+							}
+							{
+								add(ruleAction47, position)
 							}
+							add(ruleComment, position37)
 						}
-					l24:
+					}
+				l6:
+					{
+						position48 := position
 						if !_rules[ruleSpacing]() {
-							goto l3
-						}
-					l40:
-						{
-							position41, tokenIndex41 := position, tokenIndex
-							if !_rules[ruleEndOfLine]() {
-								goto l41
-							}
-							goto l40
-						l41:
-							position, tokenIndex = position41, tokenIndex41
+							goto l0
 						}
-						add(ruleStatement, position23)
+						add(rulePegText, position48)
 					}
-					goto l2
-				l3:
-					position, tokenIndex = position3, tokenIndex3
-				}
-				{
-					position42 := position
 					{
-						position43, tokenIndex43 := position, tokenIndex
-						if !matchDot() {
-							goto l43
+						add(ruleAction1, position)
+					}
+				l50:
+					{
+						position51, tokenIndex51 := position, tokenIndex
+						if !_rules[ruleEndOfLine]() {
+							goto l51
 						}
-						goto l0
-					l43:
-						position, tokenIndex = position43, tokenIndex43
+						goto l50
+					l51:
+						position, tokenIndex = position51, tokenIndex51
 					}
-					add(ruleEndOfFile, position42)
+					add(ruleStatement, position5)
 				}
-				add(ruleScript, position1)
-			}
-			return true
-		l0:
-			position, tokenIndex = position0, tokenIndex0
-			return false
-		},
-		/* 1 Statement <- <(Spacing (Expr / Declaration / Comment) Spacing EndOfLine*)> */
-		nil,
-		/* 2 Action <- <(('c' 'r' 'e' 'a' 't' 'e') / ('d' 'e' 'l' 'e' 't' 'e') / ('s' 't' 'a' 'r' 't') / ((&('d') ('d' 'e' 't' 'a' 'c' 'h')) | (&('c') ('c' 'h' 'e' 'c' 'k')) | (&('a') ('a' 't' 't' 'a' 'c' 'h')) | (&('u') ('u' 'p' 'd' 'a' 't' 'e')) | (&('s') ('s' 't' 'o' 'p'))))> */
-		nil,
-		/* 3 Entity <- <(('v' 'p' 'c') / ('s' 'u' 'b' 'n' 'e' 't') / ('i' 'n' 's' 't' 'a' 'n' 'c' 'e') / ('t' 'a' 'g') / ('r' 'o' 'l' 'e') / ('s' 'e' 'c' 'u' 'r' 'i' 't' 'y' 'g' 'r' 'o' 'u' 'p') / ('r' 'o' 'u' 't' 'e' 't' 'a' 'b' 'l' 'e') / ('s' 't' 'o' 'r' 'a' 'g' 'e' 'o' 'b' 'j' 'e' 'c' 't') / ((&('q') ('q' 'u' 'e' 'u' 'e')) | (&('t') ('t' 'o' 'p' 'i' 'c')) | (&('s') ('s' 'u' 'b' 's' 'c' 'r' 'i' 'p' 't' 'i' 'o' 'n')) | (&('b') ('b' 'u' 'c' 'k' 'e' 't')) | (&('r') ('r' 'o' 'u' 't' 'e')) | (&('i') ('i' 'n' 't' 'e' 'r' 'n' 'e' 't' 'g' 'a' 't' 'e' 'w' 'a' 'y')) | (&('k') ('k' 'e' 'y' 'p' 'a' 'i' 'r')) | (&('p') ('p' 'o' 'l' 'i' 'c' 'y')) | (&('g') ('g' 'r' 'o' 'u' 'p')) | (&('u') ('u' 's' 'e' 'r')) | (&('v') ('v' 'o' 'l' 'u' 'm' 'e'))))> */
-		nil,
-		/* 4 Declaration <- <(<Identifier> Action0 Equal Expr)> */
-		nil,
-		/* 5 Expr <- <(<Action> Action1 MustWhiteSpacing <Entity> Action2 (MustWhiteSpacing Params)? Action3)> */
-		func() bool {
-			position48, tokenIndex48 := position, tokenIndex
-			{
-				position49 := position
+			l3:
 				{
-					position50 := position
+					position4, tokenIndex4 := position, tokenIndex
 					{
-						position51 := position
+						position52 := position
+						if !_rules[ruleSpacing]() {
+							goto l4
+						}
 						{
-							position52, tokenIndex52 := position, tokenIndex
-							if buffer[position] != rune('c') {
-								goto l53
-							}
-							position++
-							if buffer[position] != rune('r') {
-								goto l53
-							}
-							position++
-							if buffer[position] != rune('e') {
-								goto l53
-							}
-							position++
-							if buffer[position] != rune('a') {
-								goto l53
-							}
-							position++
-							if buffer[position] != rune('t') {
-								goto l53
-							}
-							position++
-							if buffer[position] != rune('e') {
-								goto l53
-							}
-							position++
-							goto l52
-						l53:
-							position, tokenIndex = position52, tokenIndex52
-							if buffer[position] != rune('d') {
-								goto l54
-							}
-							position++
-							if buffer[position] != rune('e') {
-								goto l54
-							}
-							position++
-							if buffer[position] != rune('l') {
-								goto l54
-							}
-							position++
-							if buffer[position] != rune('e') {
-								goto l54
-							}
-							position++
-							if buffer[position] != rune('t') {
-								goto l54
-							}
-							position++
-							if buffer[position] != rune('e') {
-								goto l54
-							}
-							position++
-							goto l52
-						l54:
-							position, tokenIndex = position52, tokenIndex52
-							if buffer[position] != rune('s') {
-								goto l55
-							}
-							position++
-							if buffer[position] != rune('t') {
-								goto l55
-							}
-							position++
-							if buffer[position] != rune('a') {
-								goto l55
-							}
-							position++
-							if buffer[position] != rune('r') {
-								goto l55
-							}
-							position++
-							if buffer[position] != rune('t') {
-								goto l55
-							}
-							position++
-							goto l52
-						l55:
-							position, tokenIndex = position52, tokenIndex52
+							position53, tokenIndex53 := position, tokenIndex
 							{
-								switch buffer[position] {
-								case 'd':
-									if buffer[position] != rune('d') {
-										goto l48
-									}
-									position++
-									if buffer[position] != rune('e') {
-										goto l48
+								position55, tokenIndex55 := position, tokenIndex
+								{
+									position57 := position
+									if buffer[position] != 'r' {
+										goto l56
 									}
 									position++
-									if buffer[position] != rune('t') {
-										goto l48
+									if buffer[position] != 'e' {
+										goto l56
 									}
 									position++
-									if buffer[position] != rune('a') {
-										goto l48
+									if buffer[position] != 'p' {
+										goto l56
 									}
 									position++
-									if buffer[position] != rune('c') {
-										goto l48
+									if buffer[position] != 'e' {
+										goto l56
 									}
 									position++
-									if buffer[position] != rune('h') {
-										goto l48
+									if buffer[position] != 'a' {
+										goto l56
 									}
 									position++
-									break
-								case 'c':
-									if buffer[position] != rune('c') {
-										goto l48
+									if buffer[position] != 't' {
+										goto l56
 									}
 									position++
-									if buffer[position] != rune('h') {
-										goto l48
+									if !_rules[ruleMustWhiteSpacing]() {
+										goto l56
 									}
-									position++
-									if buffer[position] != rune('e') {
-										goto l48
+									{
+										position58, tokenIndex58 := position, tokenIndex
+										{
+											position60 := position
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l59
+											}
+											position++
+										l61:
+											{
+												position62, tokenIndex62 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l62
+												}
+												position++
+												goto l61
+											l62:
+												position, tokenIndex = position62, tokenIndex62
+											}
+											add(rulePegText, position60)
+										}
+										{
+											add(ruleAction3, position)
+										}
+										goto l58
+									l59:
+										position, tokenIndex = position58, tokenIndex58
+										if buffer[position] != '{' {
+											goto l56
+										}
+										position++
+										if !_rules[ruleWhiteSpacing]() {
+											goto l56
+										}
+										{
+											position64 := position
+											if !_rules[ruleIdentifier]() {
+												goto l56
+											}
+											add(rulePegText, position64)
+										}
+										if !_rules[ruleWhiteSpacing]() {
+											goto l56
+										}
+										if buffer[position] != '}' {
+											goto l56
+										}
+										position++
+										{
+											add(ruleAction4, position)
+										}
 									}
-									position++
-									if buffer[position] != rune('c') {
-										goto l48
+								l58:
+									if !_rules[ruleWhiteSpacing]() {
+										goto l56
 									}
-									position++
-									if buffer[position] != rune('k') {
-										goto l48
+									if buffer[position] != ':' {
+										goto l56
 									}
 									position++
-									break
-								case 'a':
-									if buffer[position] != rune('a') {
-										goto l48
+									if !_rules[ruleWhiteSpacing]() {
+										goto l56
 									}
-									position++
-									if buffer[position] != rune('t') {
-										goto l48
+									{
+										position66, tokenIndex66 := position, tokenIndex
+										if !_rules[ruleExpr]() {
+											goto l67
+										}
+										goto l66
+									l67:
+										position, tokenIndex = position66, tokenIndex66
+										if !_rules[ruleDeclaration]() {
+											goto l56
+										}
+									}
+								l66:
+									add(ruleRepeat, position57)
+								}
+								goto l55
+							l56:
+								position, tokenIndex = position55, tokenIndex55
+								if !_rules[ruleExpr]() {
+									goto l68
+								}
+								goto l55
+							l68:
+								position, tokenIndex = position55, tokenIndex55
+								if !_rules[ruleDeclaration]() {
+									goto l69
+								}
+								goto l55
+							l69:
+								position, tokenIndex = position55, tokenIndex55
+								{
+									position70 := position
+									{
+										position71 := position
+										if !_rules[ruleIdentifier]() {
+											goto l54
+										}
+										add(rulePegText, position71)
+									}
+									{
+										add(ruleAction8, position)
+									}
+									if !_rules[ruleEqual]() {
+										goto l54
+									}
+									if !_rules[ruleParams]() {
+										goto l54
+									}
+									{
+										add(ruleAction9, position)
+									}
+									add(ruleParamSet, position70)
+								}
+							}
+						l55:
+							{
+								position74, tokenIndex74 := position, tokenIndex
+								{
+									position76 := position
+									if !_rules[ruleWhiteSpacing]() {
+										goto l74
+									}
+									{
+										position77, tokenIndex77 := position, tokenIndex
+										if buffer[position] != '#' {
+											goto l78
+										}
+										position++
+										goto l77
+									l78:
+										position, tokenIndex = position77, tokenIndex77
+										if buffer[position] != '/' {
+											goto l74
+										}
+										position++
+										if buffer[position] != '/' {
+											goto l74
+										}
+										position++
+									}
+								l77:
+									if !_rules[ruleWhiteSpacing]() {
+										goto l74
+									}
+									{
+										position79 := position
+									l80:
+										{
+											position81, tokenIndex81 := position, tokenIndex
+											{
+												position82, tokenIndex82 := position, tokenIndex
+												if !_rules[ruleEndOfLine]() {
+													goto l82
+												}
+												goto l81
+											l82:
+												position, tokenIndex = position82, tokenIndex82
+											}
+											if !matchDot() {
+												goto l81
+											}
+											goto l80
+										l81:
+											position, tokenIndex = position81, tokenIndex81
+										}
+										add(rulePegText, position79)
+									}
+									{
+										add(ruleAction48, position)
+									}
+									add(ruleTrailingComment, position76)
+								}
+								goto l75
+							l74:
+								position, tokenIndex = position74, tokenIndex74
+							}
+						l75:
+							goto l53
+						l54:
+							position, tokenIndex = position53, tokenIndex53
+							{
+								position84 := position
+								{
+									position85 := position
+									{
+										position86, tokenIndex86 := position, tokenIndex
+										if buffer[position] != '#' {
+											goto l87
+										}
+										position++
+									l88:
+										{
+											position89, tokenIndex89 := position, tokenIndex
+											{
+												position90, tokenIndex90 := position, tokenIndex
+												if !_rules[ruleEndOfLine]() {
+													goto l90
+												}
+												goto l89
+											l90:
+												position, tokenIndex = position90, tokenIndex90
+											}
+											if !matchDot() {
+												goto l89
+											}
+											goto l88
+										l89:
+											position, tokenIndex = position89, tokenIndex89
+										}
+										goto l86
+									l87:
+										position, tokenIndex = position86, tokenIndex86
+										if buffer[position] != '/' {
+											goto l4
+										}
+										position++
+										if buffer[position] != '/' {
+											goto l4
+										}
+										position++
+									l91:
+										{
+											position92, tokenIndex92 := position, tokenIndex
+											{
+												position93, tokenIndex93 := position, tokenIndex
+												if !_rules[ruleEndOfLine]() {
+													goto l93
+												}
+												goto l92
+											l93:
+												position, tokenIndex = position93, tokenIndex93
+											}
+											if !matchDot() {
+												goto l92
+											}
+											goto l91
+										l92:
+											position, tokenIndex = position92, tokenIndex92
+										}
+									}
+								l86:
+									add(rulePegText, position85)
+								}
+								{
+									add(ruleAction47, position)
+								}
+								add(ruleComment, position84)
+							}
+						}
+					l53:
+						{
+							position95 := position
+							if !_rules[ruleSpacing]() {
+								goto l4
+							}
+							add(rulePegText, position95)
+						}
+						{
+							add(ruleAction1, position)
+						}
+					l97:
+						{
+							position98, tokenIndex98 := position, tokenIndex
+							if !_rules[ruleEndOfLine]() {
+								goto l98
+							}
+							goto l97
+						l98:
+							position, tokenIndex = position98, tokenIndex98
+						}
+						add(ruleStatement, position52)
+					}
+					goto l3
+				l4:
+					position, tokenIndex = position4, tokenIndex4
+				}
+				{
+					position99 := position
+					{
+						position100, tokenIndex100 := position, tokenIndex
+						if !matchDot() {
+							goto l100
+						}
+						goto l0
+					l100:
+						position, tokenIndex = position100, tokenIndex100
+					}
+					add(ruleEndOfFile, position99)
+				}
+				add(ruleScript, position1)
+			}
+			return true
+		l0:
+			position, tokenIndex = position0, tokenIndex0
+			return false
+		},
+		/* 1 Statement <- <(Spacing (((Repeat / Expr / Declaration / ParamSet) TrailingComment?) / Comment) <Spacing> Action1 EndOfLine*)> */
+		nil,
+		/* 2 Action <- <(('c' 'r' 'e' 'a' 't' 'e') / ('d' 'e' 'l' 'e' 't' 'e') / ('s' 't' 'a' 'r' 't') / ((&('r') ('r' 'u' 'n')) | (&('d') ('d' 'e' 't' 'a' 'c' 'h')) | (&('c') ('c' 'h' 'e' 'c' 'k')) | (&('a') ('a' 't' 't' 'a' 'c' 'h')) | (&('u') ('u' 'p' 'd' 'a' 't' 'e')) | (&('s') ('s' 't' 'o' 'p'))))> */
+		nil,
+		/* 3 Entity <- <(('v' 'p' 'c') / ('s' 'u' 'b' 'n' 'e' 't') / ('i' 'n' 's' 't' 'a' 'n' 'c' 'e' 's') / ('i' 'n' 's' 't' 'a' 'n' 'c' 'e') / ('t' 'a' 'g') / ('r' 'o' 'l' 'e') / ('s' 'e' 'c' 'u' 'r' 'i' 't' 'y' 'g' 'r' 'o' 'u' 'p') / ('r' 'o' 'u' 't' 'e' 't' 'a' 'b' 'l' 'e') / ('s' 't' 'o' 'r' 'a' 'g' 'e' 'o' 'b' 'j' 'e' 'c' 't') / ((&('l') ('l' 'o' 'c' 'a' 'l')) | (&('q') ('q' 'u' 'e' 'u' 'e')) | (&('t') ('t' 'o' 'p' 'i' 'c')) | (&('s') ('s' 'u' 'b' 's' 'c' 'r' 'i' 'p' 't' 'i' 'o' 'n')) | (&('b') ('b' 'u' 'c' 'k' 'e' 't')) | (&('r') ('r' 'o' 'u' 't' 'e')) | (&('i') ('i' 'n' 't' 'e' 'r' 'n' 'e' 't' 'g' 'a' 't' 'e' 'w' 'a' 'y')) | (&('k') ('k' 'e' 'y' 'p' 'a' 'i' 'r')) | (&('p') ('p' 'o' 'l' 'i' 'c' 'y')) | (&('g') ('g' 'r' 'o' 'u' 'p')) | (&('u') ('u' 's' 'e' 'r')) | (&('v') ('v' 'o' 'l' 'u' 'm' 'e'))))> */
+		nil,
+		/* 4 Declaration <- <(<Identifier> Action2 Equal Expr)> */
+		func() bool {
+			position104, tokenIndex104 := position, tokenIndex
+			{
+				position105 := position
+				{
+					position106 := position
+					if !_rules[ruleIdentifier]() {
+						goto l104
+					}
+					add(rulePegText, position106)
+				}
+				{
+					add(ruleAction2, position)
+				}
+				if !_rules[ruleEqual]() {
+					goto l104
+				}
+				if !_rules[ruleExpr]() {
+					goto l104
+				}
+				add(ruleDeclaration, position105)
+			}
+			return true
+		l104:
+			position, tokenIndex = position104, tokenIndex104
+			return false
+		},
+		/* 5 Repeat <- <('r' 'e' 'p' 'e' 'a' 't' MustWhiteSpacing ((<[0-9]+> Action3) / ('{' WhiteSpacing <Identifier> WhiteSpacing '}' Action4)) WhiteSpacing ':' WhiteSpacing (Expr / Declaration))> */
+		nil,
+		/* 6 Expr <- <(<Action> Action5 MustWhiteSpacing <Entity> Action6 (MustWhiteSpacing Params)? Action7)> */
+		func() bool {
+			position109, tokenIndex109 := position, tokenIndex
+			{
+				position110 := position
+				{
+					position111 := position
+					{
+						position112 := position
+						{
+							position113, tokenIndex113 := position, tokenIndex
+							if buffer[position] != 'c' {
+								goto l114
+							}
+							position++
+							if buffer[position] != 'r' {
+								goto l114
+							}
+							position++
+							if buffer[position] != 'e' {
+								goto l114
+							}
+							position++
+							if buffer[position] != 'a' {
+								goto l114
+							}
+							position++
+							if buffer[position] != 't' {
+								goto l114
+							}
+							position++
+							if buffer[position] != 'e' {
+								goto l114
+							}
+							position++
+							goto l113
+						l114:
+							position, tokenIndex = position113, tokenIndex113
+							if buffer[position] != 'd' {
+								goto l115
+							}
+							position++
+							if buffer[position] != 'e' {
+								goto l115
+							}
+							position++
+							if buffer[position] != 'l' {
+								goto l115
+							}
+							position++
+							if buffer[position] != 'e' {
+								goto l115
+							}
+							position++
+							if buffer[position] != 't' {
+								goto l115
+							}
+							position++
+							if buffer[position] != 'e' {
+								goto l115
+							}
+							position++
+							goto l113
+						l115:
+							position, tokenIndex = position113, tokenIndex113
+							if buffer[position] != 's' {
+								goto l116
+							}
+							position++
+							if buffer[position] != 't' {
+								goto l116
+							}
+							position++
+							if buffer[position] != 'a' {
+								goto l116
+							}
+							position++
+							if buffer[position] != 'r' {
+								goto l116
+							}
+							position++
+							if buffer[position] != 't' {
+								goto l116
+							}
+							position++
+							goto l113
+						l116:
+							position, tokenIndex = position113, tokenIndex113
+							{
+								switch buffer[position] {
+								case 'r':
+									if buffer[position] != 'r' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'u' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'n' {
+										goto l109
+									}
+									position++
+								case 'd':
+									if buffer[position] != 'd' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'e' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 't' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'a' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'c' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'h' {
+										goto l109
+									}
+									position++
+								case 'c':
+									if buffer[position] != 'c' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'h' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'e' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'c' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'k' {
+										goto l109
+									}
+									position++
+								case 'a':
+									if buffer[position] != 'a' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 't' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('t') {
-										goto l48
+									if buffer[position] != 't' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('a') {
-										goto l48
+									if buffer[position] != 'a' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('c') {
-										goto l48
+									if buffer[position] != 'c' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('h') {
-										goto l48
+									if buffer[position] != 'h' {
+										goto l109
 									}
 									position++
-									break
 								case 'u':
-									if buffer[position] != rune('u') {
-										goto l48
+									if buffer[position] != 'u' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('p') {
-										goto l48
+									if buffer[position] != 'p' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('d') {
-										goto l48
+									if buffer[position] != 'd' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('a') {
-										goto l48
+									if buffer[position] != 'a' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('t') {
-										goto l48
+									if buffer[position] != 't' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l48
+									if buffer[position] != 'e' {
+										goto l109
 									}
 									position++
-									break
 								default:
-									if buffer[position] != rune('s') {
-										goto l48
+									if buffer[position] != 's' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('t') {
-										goto l48
+									if buffer[position] != 't' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('o') {
-										goto l48
+									if buffer[position] != 'o' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('p') {
-										goto l48
+									if buffer[position] != 'p' {
+										goto l109
 									}
 									position++
-									break
 								}
 							}
 
 						}
-					l52:
-						add(ruleAction, position51)
+					l113:
+						add(ruleAction, position112)
 					}
-					add(rulePegText, position50)
+					add(rulePegText, position111)
 				}
 				{
-					add(ruleAction1, position)
+					add(ruleAction5, position)
 				}
 				if !_rules[ruleMustWhiteSpacing]() {
-					goto l48
+					goto l109
 				}
 				{
-					position58 := position
+					position119 := position
 					{
-						position59 := position
+						position120 := position
 						{
-							position60, tokenIndex60 := position, tokenIndex
-							if buffer[position] != rune('v') {
-								goto l61
+							position121, tokenIndex121 := position, tokenIndex
+							if buffer[position] != 'v' {
+								goto l122
+							}
+							position++
+							if buffer[position] != 'p' {
+								goto l122
+							}
+							position++
+							if buffer[position] != 'c' {
+								goto l122
+							}
+							position++
+							goto l121
+						l122:
+							position, tokenIndex = position121, tokenIndex121
+							if buffer[position] != 's' {
+								goto l123
+							}
+							position++
+							if buffer[position] != 'u' {
+								goto l123
 							}
 							position++
-							if buffer[position] != rune('p') {
-								goto l61
+							if buffer[position] != 'b' {
+								goto l123
 							}
 							position++
-							if buffer[position] != rune('c') {
-								goto l61
+							if buffer[position] != 'n' {
+								goto l123
 							}
 							position++
-							goto l60
-						l61:
-							position, tokenIndex = position60, tokenIndex60
-							if buffer[position] != rune('s') {
-								goto l62
+							if buffer[position] != 'e' {
+								goto l123
 							}
 							position++
-							if buffer[position] != rune('u') {
-								goto l62
+							if buffer[position] != 't' {
+								goto l123
 							}
 							position++
-							if buffer[position] != rune('b') {
-								goto l62
+							goto l121
+						l123:
+							position, tokenIndex = position121, tokenIndex121
+							if buffer[position] != 'i' {
+								goto l124
 							}
 							position++
-							if buffer[position] != rune('n') {
-								goto l62
+							if buffer[position] != 'n' {
+								goto l124
 							}
 							position++
-							if buffer[position] != rune('e') {
-								goto l62
+							if buffer[position] != 's' {
+								goto l124
 							}
 							position++
-							if buffer[position] != rune('t') {
-								goto l62
+							if buffer[position] != 't' {
+								goto l124
 							}
 							position++
-							goto l60
-						l62:
-							position, tokenIndex = position60, tokenIndex60
-							if buffer[position] != rune('i') {
-								goto l63
+							if buffer[position] != 'a' {
+								goto l124
 							}
 							position++
-							if buffer[position] != rune('n') {
-								goto l63
+							if buffer[position] != 'n' {
+								goto l124
 							}
 							position++
-							if buffer[position] != rune('s') {
-								goto l63
+							if buffer[position] != 'c' {
+								goto l124
 							}
 							position++
-							if buffer[position] != rune('t') {
-								goto l63
+							if buffer[position] != 'e' {
+								goto l124
 							}
 							position++
-							if buffer[position] != rune('a') {
-								goto l63
+							if buffer[position] != 's' {
+								goto l124
 							}
 							position++
-							if buffer[position] != rune('n') {
-								goto l63
+							goto l121
+						l124:
+							position, tokenIndex = position121, tokenIndex121
+							if buffer[position] != 'i' {
+								goto l125
 							}
 							position++
-							if buffer[position] != rune('c') {
-								goto l63
+							if buffer[position] != 'n' {
+								goto l125
 							}
 							position++
-							if buffer[position] != rune('e') {
-								goto l63
+							if buffer[position] != 's' {
+								goto l125
 							}
 							position++
-							goto l60
-						l63:
-							position, tokenIndex = position60, tokenIndex60
-							if buffer[position] != rune('t') {
-								goto l64
+							if buffer[position] != 't' {
+								goto l125
 							}
 							position++
-							if buffer[position] != rune('a') {
-								goto l64
+							if buffer[position] != 'a' {
+								goto l125
 							}
 							position++
-							if buffer[position] != rune('g') {
-								goto l64
+							if buffer[position] != 'n' {
+								goto l125
 							}
 							position++
-							goto l60
-						l64:
-							position, tokenIndex = position60, tokenIndex60
-							if buffer[position] != rune('r') {
-								goto l65
+							if buffer[position] != 'c' {
+								goto l125
 							}
 							position++
-							if buffer[position] != rune('o') {
-								goto l65
+							if buffer[position] != 'e' {
+								goto l125
 							}
 							position++
-							if buffer[position] != rune('l') {
-								goto l65
+							goto l121
+						l125:
+							position, tokenIndex = position121, tokenIndex121
+							if buffer[position] != 't' {
+								goto l126
 							}
 							position++
-							if buffer[position] != rune('e') {
-								goto l65
+							if buffer[position] != 'a' {
+								goto l126
 							}
 							position++
-							goto l60
-						l65:
-							position, tokenIndex = position60, tokenIndex60
-							if buffer[position] != rune('s') {
-								goto l66
+							if buffer[position] != 'g' {
+								goto l126
 							}
 							position++
-							if buffer[position] != rune('e') {
-								goto l66
+							goto l121
+						l126:
+							position, tokenIndex = position121, tokenIndex121
+							if buffer[position] != 'r' {
+								goto l127
 							}
 							position++
-							if buffer[position] != rune('c') {
-								goto l66
+							if buffer[position] != 'o' {
+								goto l127
 							}
 							position++
-							if buffer[position] != rune('u') {
-								goto l66
+							if buffer[position] != 'l' {
+								goto l127
 							}
 							position++
-							if buffer[position] != rune('r') {
-								goto l66
+							if buffer[position] != 'e' {
+								goto l127
 							}
 							position++
-							if buffer[position] != rune('i') {
-								goto l66
+							goto l121
+						l127:
+							position, tokenIndex = position121, tokenIndex121
+							if buffer[position] != 's' {
+								goto l128
 							}
 							position++
-							if buffer[position] != rune('t') {
-								goto l66
+							if buffer[position] != 'e' {
+								goto l128
 							}
 							position++
-							if buffer[position] != rune('y') {
-								goto l66
+							if buffer[position] != 'c' {
+								goto l128
 							}
 							position++
-							if buffer[position] != rune('g') {
-								goto l66
+							if buffer[position] != 'u' {
+								goto l128
 							}
 							position++
-							if buffer[position] != rune('r') {
-								goto l66
+							if buffer[position] != 'r' {
+								goto l128
 							}
 							position++
-							if buffer[position] != rune('o') {
-								goto l66
+							if buffer[position] != 'i' {
+								goto l128
 							}
 							position++
-							if buffer[position] != rune('u') {
-								goto l66
+							if buffer[position] != 't' {
+								goto l128
 							}
 							position++
-							if buffer[position] != rune('p') {
-								goto l66
+							if buffer[position] != 'y' {
+								goto l128
 							}
 							position++
-							goto l60
-						l66:
-							position, tokenIndex = position60, tokenIndex60
-							if buffer[position] != rune('r') {
-								goto l67
+							if buffer[position] != 'g' {
+								goto l128
 							}
 							position++
-							if buffer[position] != rune('o') {
-								goto l67
+							if buffer[position] != 'r' {
+								goto l128
 							}
 							position++
-							if buffer[position] != rune('u') {
-								goto l67
+							if buffer[position] != 'o' {
+								goto l128
 							}
 							position++
-							if buffer[position] != rune('t') {
-								goto l67
+							if buffer[position] != 'u' {
+								goto l128
 							}
 							position++
-							if buffer[position] != rune('e') {
-								goto l67
+							if buffer[position] != 'p' {
+								goto l128
 							}
 							position++
-							if buffer[position] != rune('t') {
-								goto l67
+							goto l121
+						l128:
+							position, tokenIndex = position121, tokenIndex121
+							if buffer[position] != 'r' {
+								goto l129
 							}
 							position++
-							if buffer[position] != rune('a') {
-								goto l67
+							if buffer[position] != 'o' {
+								goto l129
 							}
 							position++
-							if buffer[position] != rune('b') {
-								goto l67
+							if buffer[position] != 'u' {
+								goto l129
 							}
 							position++
-							if buffer[position] != rune('l') {
-								goto l67
+							if buffer[position] != 't' {
+								goto l129
 							}
 							position++
-							if buffer[position] != rune('e') {
-								goto l67
+							if buffer[position] != 'e' {
+								goto l129
 							}
 							position++
-							goto l60
-						l67:
-							position, tokenIndex = position60, tokenIndex60
-							if buffer[position] != rune('s') {
-								goto l68
+							if buffer[position] != 't' {
+								goto l129
 							}
 							position++
-							if buffer[position] != rune('t') {
-								goto l68
+							if buffer[position] != 'a' {
+								goto l129
 							}
 							position++
-							if buffer[position] != rune('o') {
-								goto l68
+							if buffer[position] != 'b' {
+								goto l129
 							}
 							position++
-							if buffer[position] != rune('r') {
-								goto l68
+							if buffer[position] != 'l' {
+								goto l129
 							}
 							position++
-							if buffer[position] != rune('a') {
-								goto l68
+							if buffer[position] != 'e' {
+								goto l129
 							}
 							position++
-							if buffer[position] != rune('g') {
-								goto l68
+							goto l121
+						l129:
+							position, tokenIndex = position121, tokenIndex121
+							if buffer[position] != 's' {
+								goto l130
 							}
 							position++
-							if buffer[position] != rune('e') {
-								goto l68
+							if buffer[position] != 't' {
+								goto l130
 							}
 							position++
-							if buffer[position] != rune('o') {
-								goto l68
+							if buffer[position] != 'o' {
+								goto l130
 							}
 							position++
-							if buffer[position] != rune('b') {
-								goto l68
+							if buffer[position] != 'r' {
+								goto l130
 							}
 							position++
-							if buffer[position] != rune('j') {
-								goto l68
+							if buffer[position] != 'a' {
+								goto l130
 							}
 							position++
-							if buffer[position] != rune('e') {
-								goto l68
+							if buffer[position] != 'g' {
+								goto l130
 							}
 							position++
-							if buffer[position] != rune('c') {
-								goto l68
+							if buffer[position] != 'e' {
+								goto l130
 							}
 							position++
-							if buffer[position] != rune('t') {
-								goto l68
+							if buffer[position] != 'o' {
+								goto l130
 							}
 							position++
-							goto l60
-						l68:
-							position, tokenIndex = position60, tokenIndex60
+							if buffer[position] != 'b' {
+								goto l130
+							}
+							position++
+							if buffer[position] != 'j' {
+								goto l130
+							}
+							position++
+							if buffer[position] != 'e' {
+								goto l130
+							}
+							position++
+							if buffer[position] != 'c' {
+								goto l130
+							}
+							position++
+							if buffer[position] != 't' {
+								goto l130
+							}
+							position++
+							goto l121
+						l130:
+							position, tokenIndex = position121, tokenIndex121
 							{
 								switch buffer[position] {
+								case 'l':
+									if buffer[position] != 'l' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'o' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'c' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'a' {
+										goto l109
+									}
+									position++
+									if buffer[position] != 'l' {
+										goto l109
+									}
+									position++
 								case 'q':
-									if buffer[position] != rune('q') {
-										goto l48
+									if buffer[position] != 'q' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('u') {
-										goto l48
+									if buffer[position] != 'u' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l48
+									if buffer[position] != 'e' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('u') {
-										goto l48
+									if buffer[position] != 'u' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l48
+									if buffer[position] != 'e' {
+										goto l109
 									}
 									position++
-									break
 								case 't':
-									if buffer[position] != rune('t') {
-										goto l48
+									if buffer[position] != 't' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('o') {
-										goto l48
+									if buffer[position] != 'o' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('p') {
-										goto l48
+									if buffer[position] != 'p' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('i') {
-										goto l48
+									if buffer[position] != 'i' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('c') {
-										goto l48
+									if buffer[position] != 'c' {
+										goto l109
 									}
 									position++
-									break
 								case 's':
-									if buffer[position] != rune('s') {
-										goto l48
+									if buffer[position] != 's' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('u') {
-										goto l48
+									if buffer[position] != 'u' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('b') {
-										goto l48
+									if buffer[position] != 'b' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('s') {
-										goto l48
+									if buffer[position] != 's' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('c') {
-										goto l48
+									if buffer[position] != 'c' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('r') {
-										goto l48
+									if buffer[position] != 'r' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('i') {
-										goto l48
+									if buffer[position] != 'i' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('p') {
-										goto l48
+									if buffer[position] != 'p' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('t') {
-										goto l48
+									if buffer[position] != 't' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('i') {
-										goto l48
+									if buffer[position] != 'i' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('o') {
-										goto l48
+									if buffer[position] != 'o' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('n') {
-										goto l48
+									if buffer[position] != 'n' {
+										goto l109
 									}
 									position++
-									break
 								case 'b':
-									if buffer[position] != rune('b') {
-										goto l48
+									if buffer[position] != 'b' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('u') {
-										goto l48
+									if buffer[position] != 'u' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('c') {
-										goto l48
+									if buffer[position] != 'c' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('k') {
-										goto l48
+									if buffer[position] != 'k' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l48
+									if buffer[position] != 'e' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('t') {
-										goto l48
+									if buffer[position] != 't' {
+										goto l109
 									}
 									position++
-									break
 								case 'r':
-									if buffer[position] != rune('r') {
-										goto l48
+									if buffer[position] != 'r' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('o') {
-										goto l48
+									if buffer[position] != 'o' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('u') {
-										goto l48
+									if buffer[position] != 'u' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('t') {
-										goto l48
+									if buffer[position] != 't' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l48
+									if buffer[position] != 'e' {
+										goto l109
 									}
 									position++
-									break
 								case 'i':
-									if buffer[position] != rune('i') {
-										goto l48
+									if buffer[position] != 'i' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('n') {
-										goto l48
+									if buffer[position] != 'n' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('t') {
-										goto l48
+									if buffer[position] != 't' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l48
+									if buffer[position] != 'e' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('r') {
-										goto l48
+									if buffer[position] != 'r' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('n') {
-										goto l48
+									if buffer[position] != 'n' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l48
+									if buffer[position] != 'e' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('t') {
-										goto l48
+									if buffer[position] != 't' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('g') {
-										goto l48
+									if buffer[position] != 'g' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('a') {
-										goto l48
+									if buffer[position] != 'a' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('t') {
-										goto l48
+									if buffer[position] != 't' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l48
+									if buffer[position] != 'e' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('w') {
-										goto l48
+									if buffer[position] != 'w' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('a') {
-										goto l48
+									if buffer[position] != 'a' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('y') {
-										goto l48
+									if buffer[position] != 'y' {
+										goto l109
 									}
 									position++
-									break
 								case 'k':
-									if buffer[position] != rune('k') {
-										goto l48
+									if buffer[position] != 'k' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l48
+									if buffer[position] != 'e' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('y') {
-										goto l48
+									if buffer[position] != 'y' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('p') {
-										goto l48
+									if buffer[position] != 'p' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('a') {
-										goto l48
+									if buffer[position] != 'a' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('i') {
-										goto l48
+									if buffer[position] != 'i' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('r') {
-										goto l48
+									if buffer[position] != 'r' {
+										goto l109
 									}
 									position++
-									break
 								case 'p':
-									if buffer[position] != rune('p') {
-										goto l48
+									if buffer[position] != 'p' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('o') {
-										goto l48
+									if buffer[position] != 'o' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('l') {
-										goto l48
+									if buffer[position] != 'l' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('i') {
-										goto l48
+									if buffer[position] != 'i' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('c') {
-										goto l48
+									if buffer[position] != 'c' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('y') {
-										goto l48
+									if buffer[position] != 'y' {
+										goto l109
 									}
 									position++
-									break
 								case 'g':
-									if buffer[position] != rune('g') {
-										goto l48
+									if buffer[position] != 'g' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('r') {
-										goto l48
+									if buffer[position] != 'r' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('o') {
-										goto l48
+									if buffer[position] != 'o' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('u') {
-										goto l48
+									if buffer[position] != 'u' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('p') {
-										goto l48
+									if buffer[position] != 'p' {
+										goto l109
 									}
 									position++
-									break
 								case 'u':
-									if buffer[position] != rune('u') {
-										goto l48
+									if buffer[position] != 'u' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('s') {
-										goto l48
+									if buffer[position] != 's' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l48
+									if buffer[position] != 'e' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('r') {
-										goto l48
+									if buffer[position] != 'r' {
+										goto l109
 									}
 									position++
-									break
 								default:
-									if buffer[position] != rune('v') {
-										goto l48
+									if buffer[position] != 'v' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('o') {
-										goto l48
+									if buffer[position] != 'o' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('l') {
-										goto l48
+									if buffer[position] != 'l' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('u') {
-										goto l48
+									if buffer[position] != 'u' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('m') {
-										goto l48
+									if buffer[position] != 'm' {
+										goto l109
 									}
 									position++
-									if buffer[position] != rune('e') {
-										goto l48
+									if buffer[position] != 'e' {
+										goto l109
 									}
 									position++
-									break
 								}
 							}
 
 						}
-					l60:
-						add(ruleEntity, position59)
+					l121:
+						add(ruleEntity, position120)
 					}
-					add(rulePegText, position58)
+					add(rulePegText, position119)
 				}
 				{
-					add(ruleAction2, position)
+					add(ruleAction6, position)
 				}
 				{
-					position71, tokenIndex71 := position, tokenIndex
+					position133, tokenIndex133 := position, tokenIndex
 					if !_rules[ruleMustWhiteSpacing]() {
-						goto l71
+						goto l133
+					}
+					if !_rules[ruleParams]() {
+						goto l133
 					}
+					goto l134
+				l133:
+					position, tokenIndex = position133, tokenIndex133
+				}
+			l134:
+				{
+					add(ruleAction7, position)
+				}
+				add(ruleExpr, position110)
+			}
+			return true
+		l109:
+			position, tokenIndex = position109, tokenIndex109
+			return false
+		},
+		/* 7 Params <- <(ParamSplat / Param)+> */
+		func() bool {
+			position136, tokenIndex136 := position, tokenIndex
+			{
+				position137 := position
+				{
+					position140, tokenIndex140 := position, tokenIndex
 					{
-						position73 := position
+						position142 := position
+						if buffer[position] != '.' {
+							goto l141
+						}
+						position++
+						if buffer[position] != '.' {
+							goto l141
+						}
+						position++
+						if buffer[position] != '.' {
+							goto l141
+						}
+						position++
 						{
-							position76 := position
-							{
-								position77 := position
-								if !_rules[ruleIdentifier]() {
-									goto l71
-								}
-								add(rulePegText, position77)
-							}
-							{
-								add(ruleAction4, position)
-							}
-							if !_rules[ruleEqual]() {
-								goto l71
+							position143 := position
+							if !_rules[ruleIdentifier]() {
+								goto l141
 							}
-							{
-								position79 := position
-								{
-									position80, tokenIndex80 := position, tokenIndex
-									{
-										position82 := position
+							add(rulePegText, position143)
+						}
+						{
+							add(ruleAction10, position)
+						}
+						if !_rules[ruleWhiteSpacing]() {
+							goto l141
+						}
+						add(ruleParamSplat, position142)
+					}
+					goto l140
+				l141:
+					position, tokenIndex = position140, tokenIndex140
+					{
+						position145 := position
+						{
+							position146 := position
+							if !_rules[ruleIdentifier]() {
+								goto l136
+							}
+							add(rulePegText, position146)
+						}
+						{
+							add(ruleAction11, position)
+						}
+						{
+							position148, tokenIndex148 := position, tokenIndex
+							if buffer[position] != '?' {
+								goto l148
+							}
+							position++
+							{
+								add(ruleAction12, position)
+							}
+							goto l149
+						l148:
+							position, tokenIndex = position148, tokenIndex148
+						}
+					l149:
+						if !_rules[ruleEqual]() {
+							goto l136
+						}
+						{
+							position151 := position
+							{
+								position152, tokenIndex152 := position, tokenIndex
+								{
+									position154 := position
+									{
+										position155 := position
+										if !_rules[ruleIdentifier]() {
+											goto l153
+										}
+										add(rulePegText, position155)
+									}
+									{
+										add(ruleAction23, position)
+									}
+									if buffer[position] != '(' {
+										goto l153
+									}
+									position++
+									if !_rules[ruleWhiteSpacing]() {
+										goto l153
+									}
+									{
+										position157, tokenIndex157 := position, tokenIndex
+										if !_rules[ruleFuncArg]() {
+											goto l157
+										}
+									l159:
+										{
+											position160, tokenIndex160 := position, tokenIndex
+											if !_rules[ruleWhiteSpacing]() {
+												goto l160
+											}
+											if buffer[position] != ',' {
+												goto l160
+											}
+											position++
+											if !_rules[ruleWhiteSpacing]() {
+												goto l160
+											}
+											if !_rules[ruleFuncArg]() {
+												goto l160
+											}
+											goto l159
+										l160:
+											position, tokenIndex = position160, tokenIndex160
+										}
+										goto l158
+									l157:
+										position, tokenIndex = position157, tokenIndex157
+									}
+								l158:
+									if !_rules[ruleWhiteSpacing]() {
+										goto l153
+									}
+									if buffer[position] != ')' {
+										goto l153
+									}
+									position++
+									add(ruleFuncValue, position154)
+								}
+								goto l152
+							l153:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position162 := position
+									{
+										add(ruleAction28, position)
+									}
+									if !_rules[ruleConcatItem]() {
+										goto l161
+									}
+									if !_rules[ruleWhiteSpacing]() {
+										goto l161
+									}
+									if buffer[position] != '+' {
+										goto l161
+									}
+									position++
+									if !_rules[ruleWhiteSpacing]() {
+										goto l161
+									}
+									if !_rules[ruleConcatItem]() {
+										goto l161
+									}
+								l164:
+									{
+										position165, tokenIndex165 := position, tokenIndex
+										if !_rules[ruleWhiteSpacing]() {
+											goto l165
+										}
+										if buffer[position] != '+' {
+											goto l165
+										}
+										position++
+										if !_rules[ruleWhiteSpacing]() {
+											goto l165
+										}
+										if !_rules[ruleConcatItem]() {
+											goto l165
+										}
+										goto l164
+									l165:
+										position, tokenIndex = position165, tokenIndex165
+									}
+									add(ruleConcatValue, position162)
+								}
+								goto l152
+							l161:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position167 := position
+									{
+										position168, tokenIndex168 := position, tokenIndex
+										if buffer[position] != '[' {
+											goto l169
+										}
+										position++
+										if !_rules[ruleWhiteSpacing]() {
+											goto l169
+										}
+										{
+											add(ruleAction32, position)
+										}
+										{
+											position171, tokenIndex171 := position, tokenIndex
+											if !_rules[ruleListItem]() {
+												goto l171
+											}
+										l173:
+											{
+												position174, tokenIndex174 := position, tokenIndex
+												if !_rules[ruleWhiteSpacing]() {
+													goto l174
+												}
+												if buffer[position] != ',' {
+													goto l174
+												}
+												position++
+												if !_rules[ruleWhiteSpacing]() {
+													goto l174
+												}
+												if !_rules[ruleListItem]() {
+													goto l174
+												}
+												goto l173
+											l174:
+												position, tokenIndex = position174, tokenIndex174
+											}
+											goto l172
+										l171:
+											position, tokenIndex = position171, tokenIndex171
+										}
+									l172:
+										if !_rules[ruleWhiteSpacing]() {
+											goto l169
+										}
+										if buffer[position] != ']' {
+											goto l169
+										}
+										position++
+										goto l168
+									l169:
+										position, tokenIndex = position168, tokenIndex168
+										{
+											add(ruleAction33, position)
+										}
+										if !_rules[ruleListItem]() {
+											goto l166
+										}
+										if buffer[position] != ',' {
+											goto l166
+										}
+										position++
+										if !_rules[ruleListItem]() {
+											goto l166
+										}
+									l176:
+										{
+											position177, tokenIndex177 := position, tokenIndex
+											if buffer[position] != ',' {
+												goto l177
+											}
+											position++
+											if !_rules[ruleListItem]() {
+												goto l177
+											}
+											goto l176
+										l177:
+											position, tokenIndex = position177, tokenIndex177
+										}
+									}
+								l168:
+									add(ruleListValue, position167)
+								}
+								goto l152
+							l166:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position179 := position
+									if buffer[position] != '{' {
+										goto l178
+									}
+									position++
+									if !_rules[ruleWhiteSpacing]() {
+										goto l178
+									}
+									{
+										position180 := position
+										if !_rules[ruleIdentifier]() {
+											goto l178
+										}
+										add(rulePegText, position180)
+									}
+									{
+										add(ruleAction45, position)
+									}
+									if !_rules[ruleMustWhiteSpacing]() {
+										goto l178
+									}
+									if buffer[position] != 'a' {
+										goto l178
+									}
+									position++
+									if buffer[position] != 's' {
+										goto l178
+									}
+									position++
+									if !_rules[ruleMustWhiteSpacing]() {
+										goto l178
+									}
+									{
+										position182 := position
+										{
+											position183 := position
+											{
+												position184, tokenIndex184 := position, tokenIndex
+												if buffer[position] != 'i' {
+													goto l185
+												}
+												position++
+												if buffer[position] != 'n' {
+													goto l185
+												}
+												position++
+												if buffer[position] != 't' {
+													goto l185
+												}
+												position++
+												goto l184
+											l185:
+												position, tokenIndex = position184, tokenIndex184
+												{
+													switch buffer[position] {
+													case 'i':
+														if buffer[position] != 'i' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'p' {
+															goto l178
+														}
+														position++
+													case 'c':
+														if buffer[position] != 'c' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'i' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'd' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'r' {
+															goto l178
+														}
+														position++
+													case 'b':
+														if buffer[position] != 'b' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'o' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'o' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'l' {
+															goto l178
+														}
+														position++
+													case 'f':
+														if buffer[position] != 'f' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'l' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'o' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'a' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 't' {
+															goto l178
+														}
+														position++
+													default:
+														if buffer[position] != 's' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 't' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'r' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'i' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'n' {
+															goto l178
+														}
+														position++
+														if buffer[position] != 'g' {
+															goto l178
+														}
+														position++
+													}
+												}
+
+											}
+										l184:
+											add(ruleHoleType, position183)
+										}
+										add(rulePegText, position182)
+									}
+									{
+										add(ruleAction46, position)
+									}
+									if !_rules[ruleWhiteSpacing]() {
+										goto l178
+									}
+									if buffer[position] != '}' {
+										goto l178
+									}
+									position++
+									add(ruleTypedHoleValue, position179)
+								}
+								goto l152
+							l178:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position189 := position
+									if buffer[position] != '{' {
+										goto l188
+									}
+									position++
+									if !_rules[ruleWhiteSpacing]() {
+										goto l188
+									}
+									{
+										add(ruleAction36, position)
+									}
+									{
+										position191, tokenIndex191 := position, tokenIndex
+										if !_rules[ruleMapEntry]() {
+											goto l191
+										}
+									l193:
+										{
+											position194, tokenIndex194 := position, tokenIndex
+											if !_rules[ruleWhiteSpacing]() {
+												goto l194
+											}
+											if buffer[position] != ',' {
+												goto l194
+											}
+											position++
+											if !_rules[ruleWhiteSpacing]() {
+												goto l194
+											}
+											if !_rules[ruleMapEntry]() {
+												goto l194
+											}
+											goto l193
+										l194:
+											position, tokenIndex = position194, tokenIndex194
+										}
+										goto l192
+									l191:
+										position, tokenIndex = position191, tokenIndex191
+									}
+								l192:
+									if !_rules[ruleWhiteSpacing]() {
+										goto l188
+									}
+									if buffer[position] != '}' {
+										goto l188
+									}
+									position++
+									add(ruleMapValue, position189)
+								}
+								goto l152
+							l188:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position196 := position
+									{
+										position197 := position
+										{
+											position198, tokenIndex198 := position, tokenIndex
+											if buffer[position] != 't' {
+												goto l199
+											}
+											position++
+											if buffer[position] != 'r' {
+												goto l199
+											}
+											position++
+											if buffer[position] != 'u' {
+												goto l199
+											}
+											position++
+											if buffer[position] != 'e' {
+												goto l199
+											}
+											position++
+											goto l198
+										l199:
+											position, tokenIndex = position198, tokenIndex198
+											if buffer[position] != 'f' {
+												goto l195
+											}
+											position++
+											if buffer[position] != 'a' {
+												goto l195
+											}
+											position++
+											if buffer[position] != 'l' {
+												goto l195
+											}
+											position++
+											if buffer[position] != 's' {
+												goto l195
+											}
+											position++
+											if buffer[position] != 'e' {
+												goto l195
+											}
+											position++
+										}
+									l198:
+										add(rulePegText, position197)
+									}
+									{
+										position200, tokenIndex200 := position, tokenIndex
+										{
+											switch buffer[position] {
+											case '?':
+												if buffer[position] != '?' {
+													goto l200
+												}
+												position++
+											case '*':
+												if buffer[position] != '*' {
+													goto l200
+												}
+												position++
+											case '/':
+												if buffer[position] != '/' {
+													goto l200
+												}
+												position++
+											case ':':
+												if buffer[position] != ':' {
+													goto l200
+												}
+												position++
+											case '_':
+												if buffer[position] != '_' {
+													goto l200
+												}
+												position++
+											case '.':
+												if buffer[position] != '.' {
+													goto l200
+												}
+												position++
+											case '-':
+												if buffer[position] != '-' {
+													goto l200
+												}
+												position++
+											case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l200
+												}
+												position++
+											case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+												if c := buffer[position]; c < 'A' || c > 'Z' {
+													goto l200
+												}
+												position++
+											default:
+												if c := buffer[position]; c < 'a' || c > 'z' {
+													goto l200
+												}
+												position++
+											}
+										}
+
+										goto l195
+									l200:
+										position, tokenIndex = position200, tokenIndex200
+									}
+									{
+										add(ruleAction40, position)
+									}
+									add(ruleBoolValue, position196)
+								}
+								goto l152
+							l195:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position204 := position
+									{
+										position205 := position
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l203
+										}
+										position++
+									l206:
+										{
+											position207, tokenIndex207 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l207
+											}
+											position++
+											goto l206
+										l207:
+											position, tokenIndex = position207, tokenIndex207
+										}
+										if buffer[position] != '.' {
+											goto l203
+										}
+										position++
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l203
+										}
+										position++
+									l208:
+										{
+											position209, tokenIndex209 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l209
+											}
+											position++
+											goto l208
+										l209:
+											position, tokenIndex = position209, tokenIndex209
+										}
+										add(rulePegText, position205)
+									}
+									{
+										position210, tokenIndex210 := position, tokenIndex
+										{
+											switch buffer[position] {
+											case '?':
+												if buffer[position] != '?' {
+													goto l210
+												}
+												position++
+											case '*':
+												if buffer[position] != '*' {
+													goto l210
+												}
+												position++
+											case '/':
+												if buffer[position] != '/' {
+													goto l210
+												}
+												position++
+											case ':':
+												if buffer[position] != ':' {
+													goto l210
+												}
+												position++
+											case '_':
+												if buffer[position] != '_' {
+													goto l210
+												}
+												position++
+											case '.':
+												if buffer[position] != '.' {
+													goto l210
+												}
+												position++
+											case '-':
+												if buffer[position] != '-' {
+													goto l210
+												}
+												position++
+											case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l210
+												}
+												position++
+											case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+												if c := buffer[position]; c < 'A' || c > 'Z' {
+													goto l210
+												}
+												position++
+											default:
+												if c := buffer[position]; c < 'a' || c > 'z' {
+													goto l210
+												}
+												position++
+											}
+										}
+
+										goto l203
+									l210:
+										position, tokenIndex = position210, tokenIndex210
+									}
+									{
+										add(ruleAction41, position)
+									}
+									add(ruleFloatValue, position204)
+								}
+								goto l152
+							l203:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position214 := position
+									{
+										position215 := position
+										if !_rules[ruleIpv6Address]() {
+											goto l213
+										}
+										if buffer[position] != '/' {
+											goto l213
+										}
+										position++
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l213
+										}
+										position++
+									l216:
+										{
+											position217, tokenIndex217 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l217
+											}
+											position++
+											goto l216
+										l217:
+											position, tokenIndex = position217, tokenIndex217
+										}
+										add(rulePegText, position215)
+									}
+									{
+										position218, tokenIndex218 := position, tokenIndex
+										{
+											switch buffer[position] {
+											case '?':
+												if buffer[position] != '?' {
+													goto l218
+												}
+												position++
+											case '*':
+												if buffer[position] != '*' {
+													goto l218
+												}
+												position++
+											case '/':
+												if buffer[position] != '/' {
+													goto l218
+												}
+												position++
+											case ':':
+												if buffer[position] != ':' {
+													goto l218
+												}
+												position++
+											case '_':
+												if buffer[position] != '_' {
+													goto l218
+												}
+												position++
+											case '.':
+												if buffer[position] != '.' {
+													goto l218
+												}
+												position++
+											case '-':
+												if buffer[position] != '-' {
+													goto l218
+												}
+												position++
+											case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l218
+												}
+												position++
+											case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+												if c := buffer[position]; c < 'A' || c > 'Z' {
+													goto l218
+												}
+												position++
+											default:
+												if c := buffer[position]; c < 'a' || c > 'z' {
+													goto l218
+												}
+												position++
+											}
+										}
+
+										goto l213
+									l218:
+										position, tokenIndex = position218, tokenIndex218
+									}
+									{
+										add(ruleAction42, position)
+									}
+									add(ruleIpv6CidrValue, position214)
+								}
+								goto l152
+							l213:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position222 := position
+									{
+										position223 := position
+										if !_rules[ruleIpv6Address]() {
+											goto l221
+										}
+										add(rulePegText, position223)
+									}
+									{
+										position224, tokenIndex224 := position, tokenIndex
+										{
+											switch buffer[position] {
+											case '?':
+												if buffer[position] != '?' {
+													goto l224
+												}
+												position++
+											case '*':
+												if buffer[position] != '*' {
+													goto l224
+												}
+												position++
+											case '/':
+												if buffer[position] != '/' {
+													goto l224
+												}
+												position++
+											case ':':
+												if buffer[position] != ':' {
+													goto l224
+												}
+												position++
+											case '_':
+												if buffer[position] != '_' {
+													goto l224
+												}
+												position++
+											case '.':
+												if buffer[position] != '.' {
+													goto l224
+												}
+												position++
+											case '-':
+												if buffer[position] != '-' {
+													goto l224
+												}
+												position++
+											case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l224
+												}
+												position++
+											case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+												if c := buffer[position]; c < 'A' || c > 'Z' {
+													goto l224
+												}
+												position++
+											default:
+												if c := buffer[position]; c < 'a' || c > 'z' {
+													goto l224
+												}
+												position++
+											}
+										}
+
+										goto l221
+									l224:
+										position, tokenIndex = position224, tokenIndex224
+									}
+									{
+										add(ruleAction43, position)
+									}
+									add(ruleIpv6Value, position222)
+								}
+								goto l152
+							l221:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position228 := position
+									{
+										position229 := position
+									l230:
+										{
+											position231, tokenIndex231 := position, tokenIndex
+											{
+												switch buffer[position] {
+												case '/':
+													if buffer[position] != '/' {
+														goto l231
+													}
+													position++
+												case ':':
+													if buffer[position] != ':' {
+														goto l231
+													}
+													position++
+												case '_':
+													if buffer[position] != '_' {
+														goto l231
+													}
+													position++
+												case '.':
+													if buffer[position] != '.' {
+														goto l231
+													}
+													position++
+												case '-':
+													if buffer[position] != '-' {
+														goto l231
+													}
+													position++
+												case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+													if c := buffer[position]; c < '0' || c > '9' {
+														goto l231
+													}
+													position++
+												case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+													if c := buffer[position]; c < 'A' || c > 'Z' {
+														goto l231
+													}
+													position++
+												default:
+													if c := buffer[position]; c < 'a' || c > 'z' {
+														goto l231
+													}
+													position++
+												}
+											}
+
+											goto l230
+										l231:
+											position, tokenIndex = position231, tokenIndex231
+										}
+										{
+											position233, tokenIndex233 := position, tokenIndex
+											if buffer[position] != '*' {
+												goto l234
+											}
+											position++
+											goto l233
+										l234:
+											position, tokenIndex = position233, tokenIndex233
+											if buffer[position] != '?' {
+												goto l227
+											}
+											position++
+										}
+									l233:
+									l235:
+										{
+											position236, tokenIndex236 := position, tokenIndex
+											{
+												switch buffer[position] {
+												case '?':
+													if buffer[position] != '?' {
+														goto l236
+													}
+													position++
+												case '*':
+													if buffer[position] != '*' {
+														goto l236
+													}
+													position++
+												case '/':
+													if buffer[position] != '/' {
+														goto l236
+													}
+													position++
+												case ':':
+													if buffer[position] != ':' {
+														goto l236
+													}
+													position++
+												case '_':
+													if buffer[position] != '_' {
+														goto l236
+													}
+													position++
+												case '.':
+													if buffer[position] != '.' {
+														goto l236
+													}
+													position++
+												case '-':
+													if buffer[position] != '-' {
+														goto l236
+													}
+													position++
+												case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+													if c := buffer[position]; c < '0' || c > '9' {
+														goto l236
+													}
+													position++
+												case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+													if c := buffer[position]; c < 'A' || c > 'Z' {
+														goto l236
+													}
+													position++
+												default:
+													if c := buffer[position]; c < 'a' || c > 'z' {
+														goto l236
+													}
+													position++
+												}
+											}
+
+											goto l235
+										l236:
+											position, tokenIndex = position236, tokenIndex236
+										}
+										add(rulePegText, position229)
+									}
+									{
+										add(ruleAction44, position)
+									}
+									add(ruleGlobValue, position228)
+								}
+								goto l152
+							l227:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position240 := position
+									if buffer[position] != '@' {
+										goto l239
+									}
+									position++
+									{
+										position241 := position
+										{
+											position242 := position
+										l243:
+											{
+												position244, tokenIndex244 := position, tokenIndex
+												{
+													switch buffer[position] {
+													case '_':
+														if buffer[position] != '_' {
+															goto l244
+														}
+														position++
+													case '.':
+														if buffer[position] != '.' {
+															goto l244
+														}
+														position++
+													case '-':
+														if buffer[position] != '-' {
+															goto l244
+														}
+														position++
+													case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+														if c := buffer[position]; c < '0' || c > '9' {
+															goto l244
+														}
+														position++
+													case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+														if c := buffer[position]; c < 'A' || c > 'Z' {
+															goto l244
+														}
+														position++
+													default:
+														if c := buffer[position]; c < 'a' || c > 'z' {
+															goto l244
+														}
+														position++
+													}
+												}
+
+												goto l243
+											l244:
+												position, tokenIndex = position244, tokenIndex244
+											}
+											if buffer[position] != '/' {
+												goto l239
+											}
+											position++
+											{
+												switch buffer[position] {
+												case '/':
+													if buffer[position] != '/' {
+														goto l239
+													}
+													position++
+												case '_':
+													if buffer[position] != '_' {
+														goto l239
+													}
+													position++
+												case '.':
+													if buffer[position] != '.' {
+														goto l239
+													}
+													position++
+												case '-':
+													if buffer[position] != '-' {
+														goto l239
+													}
+													position++
+												case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+													if c := buffer[position]; c < '0' || c > '9' {
+														goto l239
+													}
+													position++
+												case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+													if c := buffer[position]; c < 'A' || c > 'Z' {
+														goto l239
+													}
+													position++
+												default:
+													if c := buffer[position]; c < 'a' || c > 'z' {
+														goto l239
+													}
+													position++
+												}
+											}
+
+										l246:
+											{
+												position247, tokenIndex247 := position, tokenIndex
+												{
+													switch buffer[position] {
+													case '/':
+														if buffer[position] != '/' {
+															goto l247
+														}
+														position++
+													case '_':
+														if buffer[position] != '_' {
+															goto l247
+														}
+														position++
+													case '.':
+														if buffer[position] != '.' {
+															goto l247
+														}
+														position++
+													case '-':
+														if buffer[position] != '-' {
+															goto l247
+														}
+														position++
+													case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+														if c := buffer[position]; c < '0' || c > '9' {
+															goto l247
+														}
+														position++
+													case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+														if c := buffer[position]; c < 'A' || c > 'Z' {
+															goto l247
+														}
+														position++
+													default:
+														if c := buffer[position]; c < 'a' || c > 'z' {
+															goto l247
+														}
+														position++
+													}
+												}
+
+												goto l246
+											l247:
+												position, tokenIndex = position247, tokenIndex247
+											}
+											add(ruleFilePath, position242)
+										}
+										add(rulePegText, position241)
+									}
+									add(ruleFileValue, position240)
+								}
+								{
+									add(ruleAction14, position)
+								}
+								goto l152
+							l239:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position252 := position
+									{
+										position253 := position
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l251
+										}
+										position++
+									l254:
+										{
+											position255, tokenIndex255 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l255
+											}
+											position++
+											goto l254
+										l255:
+											position, tokenIndex = position255, tokenIndex255
+										}
+										if !matchDot() {
+											goto l251
+										}
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l251
+										}
+										position++
+									l256:
+										{
+											position257, tokenIndex257 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l257
+											}
+											position++
+											goto l256
+										l257:
+											position, tokenIndex = position257, tokenIndex257
+										}
+										if !matchDot() {
+											goto l251
+										}
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l251
+										}
+										position++
+									l258:
+										{
+											position259, tokenIndex259 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l259
+											}
+											position++
+											goto l258
+										l259:
+											position, tokenIndex = position259, tokenIndex259
+										}
+										if !matchDot() {
+											goto l251
+										}
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l251
+										}
+										position++
+									l260:
+										{
+											position261, tokenIndex261 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l261
+											}
+											position++
+											goto l260
+										l261:
+											position, tokenIndex = position261, tokenIndex261
+										}
+										if buffer[position] != '/' {
+											goto l251
+										}
+										position++
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l251
+										}
+										position++
+									l262:
+										{
+											position263, tokenIndex263 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l263
+											}
+											position++
+											goto l262
+										l263:
+											position, tokenIndex = position263, tokenIndex263
+										}
+										add(ruleCidrValue, position253)
+									}
+									add(rulePegText, position252)
+								}
+								{
+									add(ruleAction17, position)
+								}
+								goto l152
+							l251:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position266 := position
+									{
+										position267 := position
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l265
+										}
+										position++
+									l268:
+										{
+											position269, tokenIndex269 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l269
+											}
+											position++
+											goto l268
+										l269:
+											position, tokenIndex = position269, tokenIndex269
+										}
+										if !matchDot() {
+											goto l265
+										}
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l265
+										}
+										position++
+									l270:
+										{
+											position271, tokenIndex271 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l271
+											}
+											position++
+											goto l270
+										l271:
+											position, tokenIndex = position271, tokenIndex271
+										}
+										if !matchDot() {
+											goto l265
+										}
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l265
+										}
+										position++
+									l272:
+										{
+											position273, tokenIndex273 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l273
+											}
+											position++
+											goto l272
+										l273:
+											position, tokenIndex = position273, tokenIndex273
+										}
+										if !matchDot() {
+											goto l265
+										}
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l265
+										}
+										position++
+									l274:
+										{
+											position275, tokenIndex275 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l275
+											}
+											position++
+											goto l274
+										l275:
+											position, tokenIndex = position275, tokenIndex275
+										}
+										add(ruleIpValue, position267)
+									}
+									add(rulePegText, position266)
+								}
+								{
+									add(ruleAction18, position)
+								}
+								goto l152
+							l265:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position278 := position
+									{
+										position279 := position
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l277
+										}
+										position++
+									l280:
+										{
+											position281, tokenIndex281 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l281
+											}
+											position++
+											goto l280
+										l281:
+											position, tokenIndex = position281, tokenIndex281
+										}
+										if buffer[position] != '-' {
+											goto l277
+										}
+										position++
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l277
+										}
+										position++
+									l282:
+										{
+											position283, tokenIndex283 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l283
+											}
+											position++
+											goto l282
+										l283:
+											position, tokenIndex = position283, tokenIndex283
+										}
+										add(ruleIntRangeValue, position279)
+									}
+									add(rulePegText, position278)
+								}
+								{
+									add(ruleAction19, position)
+								}
+								goto l152
+							l277:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									position286 := position
+									{
+										position287 := position
+										{
+											position288, tokenIndex288 := position, tokenIndex
+											if buffer[position] != '-' {
+												goto l288
+											}
+											position++
+											goto l289
+										l288:
+											position, tokenIndex = position288, tokenIndex288
+										}
+									l289:
+										if c := buffer[position]; c < '0' || c > '9' {
+											goto l285
+										}
+										position++
+									l290:
+										{
+											position291, tokenIndex291 := position, tokenIndex
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l291
+											}
+											position++
+											goto l290
+										l291:
+											position, tokenIndex = position291, tokenIndex291
+										}
+										add(ruleIntValue, position287)
+									}
+									add(rulePegText, position286)
+								}
+								{
+									add(ruleAction20, position)
+								}
+								goto l152
+							l285:
+								position, tokenIndex = position152, tokenIndex152
+								{
+									switch buffer[position] {
+									case '"':
+										if buffer[position] != '"' {
+											goto l136
+										}
+										position++
+										{
+											position294 := position
+											if !_rules[ruleQuotedValue]() {
+												goto l136
+											}
+											add(rulePegText, position294)
+										}
+										if buffer[position] != '"' {
+											goto l136
+										}
+										position++
+										{
+											add(ruleAction22, position)
+										}
+									case '$':
+										{
+											position296 := position
+											if buffer[position] != '$' {
+												goto l136
+											}
+											position++
+											{
+												position297 := position
+												if !_rules[ruleIdentifier]() {
+													goto l136
+												}
+												add(rulePegText, position297)
+											}
+											add(ruleRefValue, position296)
+										}
+										{
+											add(ruleAction16, position)
+										}
+									case '@':
+										{
+											position299 := position
+											if buffer[position] != '@' {
+												goto l136
+											}
+											position++
+											{
+												position300 := position
+												if !_rules[ruleIdentifier]() {
+													goto l136
+												}
+												add(rulePegText, position300)
+											}
+											add(ruleAliasValue, position299)
+										}
+										{
+											add(ruleAction15, position)
+										}
+									case '{':
+										{
+											position302 := position
+											if buffer[position] != '{' {
+												goto l136
+											}
+											position++
+											if !_rules[ruleWhiteSpacing]() {
+												goto l136
+											}
+											{
+												position303 := position
+												if !_rules[ruleIdentifier]() {
+													goto l136
+												}
+												add(rulePegText, position303)
+											}
+											if !_rules[ruleWhiteSpacing]() {
+												goto l136
+											}
+											if buffer[position] != '}' {
+												goto l136
+											}
+											position++
+											add(ruleHoleValue, position302)
+										}
+										{
+											add(ruleAction13, position)
+										}
+									case '<':
+										{
+											position305 := position
+											if buffer[position] != '<' {
+												goto l136
+											}
+											position++
+											if buffer[position] != '<' {
+												goto l136
+											}
+											position++
+											if c := buffer[position]; c < 'A' || c > 'Z' {
+												goto l136
+											}
+											position++
+										l306:
+											{
+												position307, tokenIndex307 := position, tokenIndex
+												{
+													switch buffer[position] {
+													case '_':
+														if buffer[position] != '_' {
+															goto l307
+														}
+														position++
+													case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+														if c := buffer[position]; c < '0' || c > '9' {
+															goto l307
+														}
+														position++
+													default:
+														if c := buffer[position]; c < 'A' || c > 'Z' {
+															goto l307
+														}
+														position++
+													}
+												}
+
+												goto l306
+											l307:
+												position, tokenIndex = position307, tokenIndex307
+											}
+											if !_rules[ruleEndOfLine]() {
+												goto l136
+											}
+											{
+												position309 := position
+												{
+													position310 := position
+													if !(skipHeredocBody(buffer, &position)) {
+														goto l136
+													}
+													add(ruleHeredocBody, position310)
+												}
+												add(rulePegText, position309)
+											}
+											{
+												add(ruleAction27, position)
+											}
+											{
+												position312 := position
+												{
+													position313, tokenIndex313 := position, tokenIndex
+													if !_rules[ruleEndOfLine]() {
+														goto l313
+													}
+													goto l314
+												l313:
+													position, tokenIndex = position313, tokenIndex313
+												}
+											l314:
+											l315:
+												{
+													position316, tokenIndex316 := position, tokenIndex
+													{
+														position317, tokenIndex317 := position, tokenIndex
+														if buffer[position] != ' ' {
+															goto l318
+														}
+														position++
+														goto l317
+													l318:
+														position, tokenIndex = position317, tokenIndex317
+														if buffer[position] != '\t' {
+															goto l316
+														}
+														position++
+													}
+												l317:
+													goto l315
+												l316:
+													position, tokenIndex = position316, tokenIndex316
+												}
+												if c := buffer[position]; c < 'A' || c > 'Z' {
+													goto l136
+												}
+												position++
+											l319:
+												{
+													position320, tokenIndex320 := position, tokenIndex
+													{
+														switch buffer[position] {
+														case '_':
+															if buffer[position] != '_' {
+																goto l320
+															}
+															position++
+														case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+															if c := buffer[position]; c < '0' || c > '9' {
+																goto l320
+															}
+															position++
+														default:
+															if c := buffer[position]; c < 'A' || c > 'Z' {
+																goto l320
+															}
+															position++
+														}
+													}
+
+													goto l319
+												l320:
+													position, tokenIndex = position320, tokenIndex320
+												}
+											l322:
+												{
+													position323, tokenIndex323 := position, tokenIndex
+													{
+														position324, tokenIndex324 := position, tokenIndex
+														if buffer[position] != ' ' {
+															goto l325
+														}
+														position++
+														goto l324
+													l325:
+														position, tokenIndex = position324, tokenIndex324
+														if buffer[position] != '\t' {
+															goto l323
+														}
+														position++
+													}
+												l324:
+													goto l322
+												l323:
+													position, tokenIndex = position323, tokenIndex323
+												}
+												add(ruleHeredocEnd, position312)
+											}
+											add(ruleHeredocValue, position305)
+										}
+									default:
+										{
+											position326 := position
+											if !_rules[ruleStringValue]() {
+												goto l136
+											}
+											add(rulePegText, position326)
+										}
+										{
+											add(ruleAction21, position)
+										}
+									}
+								}
+
+							}
+						l152:
+							add(ruleValue, position151)
+						}
+						if !_rules[ruleWhiteSpacing]() {
+							goto l136
+						}
+						add(ruleParam, position145)
+					}
+				}
+			l140:
+			l138:
+				{
+					position139, tokenIndex139 := position, tokenIndex
+					{
+						position328, tokenIndex328 := position, tokenIndex
+						{
+							position330 := position
+							if buffer[position] != '.' {
+								goto l329
+							}
+							position++
+							if buffer[position] != '.' {
+								goto l329
+							}
+							position++
+							if buffer[position] != '.' {
+								goto l329
+							}
+							position++
+							{
+								position331 := position
+								if !_rules[ruleIdentifier]() {
+									goto l329
+								}
+								add(rulePegText, position331)
+							}
+							{
+								add(ruleAction10, position)
+							}
+							if !_rules[ruleWhiteSpacing]() {
+								goto l329
+							}
+							add(ruleParamSplat, position330)
+						}
+						goto l328
+					l329:
+						position, tokenIndex = position328, tokenIndex328
+						{
+							position333 := position
+							{
+								position334 := position
+								if !_rules[ruleIdentifier]() {
+									goto l139
+								}
+								add(rulePegText, position334)
+							}
+							{
+								add(ruleAction11, position)
+							}
+							{
+								position336, tokenIndex336 := position, tokenIndex
+								if buffer[position] != '?' {
+									goto l336
+								}
+								position++
+								{
+									add(ruleAction12, position)
+								}
+								goto l337
+							l336:
+								position, tokenIndex = position336, tokenIndex336
+							}
+						l337:
+							if !_rules[ruleEqual]() {
+								goto l139
+							}
+							{
+								position339 := position
+								{
+									position340, tokenIndex340 := position, tokenIndex
+									{
+										position342 := position
+										{
+											position343 := position
+											if !_rules[ruleIdentifier]() {
+												goto l341
+											}
+											add(rulePegText, position343)
+										}
+										{
+											add(ruleAction23, position)
+										}
+										if buffer[position] != '(' {
+											goto l341
+										}
+										position++
+										if !_rules[ruleWhiteSpacing]() {
+											goto l341
+										}
+										{
+											position345, tokenIndex345 := position, tokenIndex
+											if !_rules[ruleFuncArg]() {
+												goto l345
+											}
+										l347:
+											{
+												position348, tokenIndex348 := position, tokenIndex
+												if !_rules[ruleWhiteSpacing]() {
+													goto l348
+												}
+												if buffer[position] != ',' {
+													goto l348
+												}
+												position++
+												if !_rules[ruleWhiteSpacing]() {
+													goto l348
+												}
+												if !_rules[ruleFuncArg]() {
+													goto l348
+												}
+												goto l347
+											l348:
+												position, tokenIndex = position348, tokenIndex348
+											}
+											goto l346
+										l345:
+											position, tokenIndex = position345, tokenIndex345
+										}
+									l346:
+										if !_rules[ruleWhiteSpacing]() {
+											goto l341
+										}
+										if buffer[position] != ')' {
+											goto l341
+										}
+										position++
+										add(ruleFuncValue, position342)
+									}
+									goto l340
+								l341:
+									position, tokenIndex = position340, tokenIndex340
+									{
+										position350 := position
+										{
+											add(ruleAction28, position)
+										}
+										if !_rules[ruleConcatItem]() {
+											goto l349
+										}
+										if !_rules[ruleWhiteSpacing]() {
+											goto l349
+										}
+										if buffer[position] != '+' {
+											goto l349
+										}
+										position++
+										if !_rules[ruleWhiteSpacing]() {
+											goto l349
+										}
+										if !_rules[ruleConcatItem]() {
+											goto l349
+										}
+									l352:
+										{
+											position353, tokenIndex353 := position, tokenIndex
+											if !_rules[ruleWhiteSpacing]() {
+												goto l353
+											}
+											if buffer[position] != '+' {
+												goto l353
+											}
+											position++
+											if !_rules[ruleWhiteSpacing]() {
+												goto l353
+											}
+											if !_rules[ruleConcatItem]() {
+												goto l353
+											}
+											goto l352
+										l353:
+											position, tokenIndex = position353, tokenIndex353
+										}
+										add(ruleConcatValue, position350)
+									}
+									goto l340
+								l349:
+									position, tokenIndex = position340, tokenIndex340
+									{
+										position355 := position
+										{
+											position356, tokenIndex356 := position, tokenIndex
+											if buffer[position] != '[' {
+												goto l357
+											}
+											position++
+											if !_rules[ruleWhiteSpacing]() {
+												goto l357
+											}
+											{
+												add(ruleAction32, position)
+											}
+											{
+												position359, tokenIndex359 := position, tokenIndex
+												if !_rules[ruleListItem]() {
+													goto l359
+												}
+											l361:
+												{
+													position362, tokenIndex362 := position, tokenIndex
+													if !_rules[ruleWhiteSpacing]() {
+														goto l362
+													}
+													if buffer[position] != ',' {
+														goto l362
+													}
+													position++
+													if !_rules[ruleWhiteSpacing]() {
+														goto l362
+													}
+													if !_rules[ruleListItem]() {
+														goto l362
+													}
+													goto l361
+												l362:
+													position, tokenIndex = position362, tokenIndex362
+												}
+												goto l360
+											l359:
+												position, tokenIndex = position359, tokenIndex359
+											}
+										l360:
+											if !_rules[ruleWhiteSpacing]() {
+												goto l357
+											}
+											if buffer[position] != ']' {
+												goto l357
+											}
+											position++
+											goto l356
+										l357:
+											position, tokenIndex = position356, tokenIndex356
+											{
+												add(ruleAction33, position)
+											}
+											if !_rules[ruleListItem]() {
+												goto l354
+											}
+											if buffer[position] != ',' {
+												goto l354
+											}
+											position++
+											if !_rules[ruleListItem]() {
+												goto l354
+											}
+										l364:
+											{
+												position365, tokenIndex365 := position, tokenIndex
+												if buffer[position] != ',' {
+													goto l365
+												}
+												position++
+												if !_rules[ruleListItem]() {
+													goto l365
+												}
+												goto l364
+											l365:
+												position, tokenIndex = position365, tokenIndex365
+											}
+										}
+									l356:
+										add(ruleListValue, position355)
+									}
+									goto l340
+								l354:
+									position, tokenIndex = position340, tokenIndex340
+									{
+										position367 := position
+										if buffer[position] != '{' {
+											goto l366
+										}
+										position++
+										if !_rules[ruleWhiteSpacing]() {
+											goto l366
+										}
+										{
+											position368 := position
+											if !_rules[ruleIdentifier]() {
+												goto l366
+											}
+											add(rulePegText, position368)
+										}
+										{
+											add(ruleAction45, position)
+										}
+										if !_rules[ruleMustWhiteSpacing]() {
+											goto l366
+										}
+										if buffer[position] != 'a' {
+											goto l366
+										}
+										position++
+										if buffer[position] != 's' {
+											goto l366
+										}
+										position++
+										if !_rules[ruleMustWhiteSpacing]() {
+											goto l366
+										}
+										{
+											position370 := position
+											{
+												position371 := position
+												{
+													position372, tokenIndex372 := position, tokenIndex
+													if buffer[position] != 'i' {
+														goto l373
+													}
+													position++
+													if buffer[position] != 'n' {
+														goto l373
+													}
+													position++
+													if buffer[position] != 't' {
+														goto l373
+													}
+													position++
+													goto l372
+												l373:
+													position, tokenIndex = position372, tokenIndex372
+													{
+														switch buffer[position] {
+														case 'i':
+															if buffer[position] != 'i' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'p' {
+																goto l366
+															}
+															position++
+														case 'c':
+															if buffer[position] != 'c' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'i' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'd' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'r' {
+																goto l366
+															}
+															position++
+														case 'b':
+															if buffer[position] != 'b' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'o' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'o' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'l' {
+																goto l366
+															}
+															position++
+														case 'f':
+															if buffer[position] != 'f' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'l' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'o' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'a' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 't' {
+																goto l366
+															}
+															position++
+														default:
+															if buffer[position] != 's' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 't' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'r' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'i' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'n' {
+																goto l366
+															}
+															position++
+															if buffer[position] != 'g' {
+																goto l366
+															}
+															position++
+														}
+													}
+
+												}
+											l372:
+												add(ruleHoleType, position371)
+											}
+											add(rulePegText, position370)
+										}
+										{
+											add(ruleAction46, position)
+										}
+										if !_rules[ruleWhiteSpacing]() {
+											goto l366
+										}
+										if buffer[position] != '}' {
+											goto l366
+										}
+										position++
+										add(ruleTypedHoleValue, position367)
+									}
+									goto l340
+								l366:
+									position, tokenIndex = position340, tokenIndex340
+									{
+										position377 := position
+										if buffer[position] != '{' {
+											goto l376
+										}
+										position++
+										if !_rules[ruleWhiteSpacing]() {
+											goto l376
+										}
+										{
+											add(ruleAction36, position)
+										}
+										{
+											position379, tokenIndex379 := position, tokenIndex
+											if !_rules[ruleMapEntry]() {
+												goto l379
+											}
+										l381:
+											{
+												position382, tokenIndex382 := position, tokenIndex
+												if !_rules[ruleWhiteSpacing]() {
+													goto l382
+												}
+												if buffer[position] != ',' {
+													goto l382
+												}
+												position++
+												if !_rules[ruleWhiteSpacing]() {
+													goto l382
+												}
+												if !_rules[ruleMapEntry]() {
+													goto l382
+												}
+												goto l381
+											l382:
+												position, tokenIndex = position382, tokenIndex382
+											}
+											goto l380
+										l379:
+											position, tokenIndex = position379, tokenIndex379
+										}
+									l380:
+										if !_rules[ruleWhiteSpacing]() {
+											goto l376
+										}
+										if buffer[position] != '}' {
+											goto l376
+										}
+										position++
+										add(ruleMapValue, position377)
+									}
+									goto l340
+								l376:
+									position, tokenIndex = position340, tokenIndex340
+									{
+										position384 := position
+										{
+											position385 := position
+											{
+												position386, tokenIndex386 := position, tokenIndex
+												if buffer[position] != 't' {
+													goto l387
+												}
+												position++
+												if buffer[position] != 'r' {
+													goto l387
+												}
+												position++
+												if buffer[position] != 'u' {
+													goto l387
+												}
+												position++
+												if buffer[position] != 'e' {
+													goto l387
+												}
+												position++
+												goto l386
+											l387:
+												position, tokenIndex = position386, tokenIndex386
+												if buffer[position] != 'f' {
+													goto l383
+												}
+												position++
+												if buffer[position] != 'a' {
+													goto l383
+												}
+												position++
+												if buffer[position] != 'l' {
+													goto l383
+												}
+												position++
+												if buffer[position] != 's' {
+													goto l383
+												}
+												position++
+												if buffer[position] != 'e' {
+													goto l383
+												}
+												position++
+											}
+										l386:
+											add(rulePegText, position385)
+										}
+										{
+											position388, tokenIndex388 := position, tokenIndex
+											{
+												switch buffer[position] {
+												case '?':
+													if buffer[position] != '?' {
+														goto l388
+													}
+													position++
+												case '*':
+													if buffer[position] != '*' {
+														goto l388
+													}
+													position++
+												case '/':
+													if buffer[position] != '/' {
+														goto l388
+													}
+													position++
+												case ':':
+													if buffer[position] != ':' {
+														goto l388
+													}
+													position++
+												case '_':
+													if buffer[position] != '_' {
+														goto l388
+													}
+													position++
+												case '.':
+													if buffer[position] != '.' {
+														goto l388
+													}
+													position++
+												case '-':
+													if buffer[position] != '-' {
+														goto l388
+													}
+													position++
+												case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+													if c := buffer[position]; c < '0' || c > '9' {
+														goto l388
+													}
+													position++
+												case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+													if c := buffer[position]; c < 'A' || c > 'Z' {
+														goto l388
+													}
+													position++
+												default:
+													if c := buffer[position]; c < 'a' || c > 'z' {
+														goto l388
+													}
+													position++
+												}
+											}
+
+											goto l383
+										l388:
+											position, tokenIndex = position388, tokenIndex388
+										}
+										{
+											add(ruleAction40, position)
+										}
+										add(ruleBoolValue, position384)
+									}
+									goto l340
+								l383:
+									position, tokenIndex = position340, tokenIndex340
+									{
+										position392 := position
+										{
+											position393 := position
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l391
+											}
+											position++
+										l394:
+											{
+												position395, tokenIndex395 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l395
+												}
+												position++
+												goto l394
+											l395:
+												position, tokenIndex = position395, tokenIndex395
+											}
+											if buffer[position] != '.' {
+												goto l391
+											}
+											position++
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l391
+											}
+											position++
+										l396:
+											{
+												position397, tokenIndex397 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l397
+												}
+												position++
+												goto l396
+											l397:
+												position, tokenIndex = position397, tokenIndex397
+											}
+											add(rulePegText, position393)
+										}
+										{
+											position398, tokenIndex398 := position, tokenIndex
+											{
+												switch buffer[position] {
+												case '?':
+													if buffer[position] != '?' {
+														goto l398
+													}
+													position++
+												case '*':
+													if buffer[position] != '*' {
+														goto l398
+													}
+													position++
+												case '/':
+													if buffer[position] != '/' {
+														goto l398
+													}
+													position++
+												case ':':
+													if buffer[position] != ':' {
+														goto l398
+													}
+													position++
+												case '_':
+													if buffer[position] != '_' {
+														goto l398
+													}
+													position++
+												case '.':
+													if buffer[position] != '.' {
+														goto l398
+													}
+													position++
+												case '-':
+													if buffer[position] != '-' {
+														goto l398
+													}
+													position++
+												case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+													if c := buffer[position]; c < '0' || c > '9' {
+														goto l398
+													}
+													position++
+												case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+													if c := buffer[position]; c < 'A' || c > 'Z' {
+														goto l398
+													}
+													position++
+												default:
+													if c := buffer[position]; c < 'a' || c > 'z' {
+														goto l398
+													}
+													position++
+												}
+											}
+
+											goto l391
+										l398:
+											position, tokenIndex = position398, tokenIndex398
+										}
+										{
+											add(ruleAction41, position)
+										}
+										add(ruleFloatValue, position392)
+									}
+									goto l340
+								l391:
+									position, tokenIndex = position340, tokenIndex340
+									{
+										position402 := position
+										{
+											position403 := position
+											if !_rules[ruleIpv6Address]() {
+												goto l401
+											}
+											if buffer[position] != '/' {
+												goto l401
+											}
+											position++
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l401
+											}
+											position++
+										l404:
+											{
+												position405, tokenIndex405 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l405
+												}
+												position++
+												goto l404
+											l405:
+												position, tokenIndex = position405, tokenIndex405
+											}
+											add(rulePegText, position403)
+										}
+										{
+											position406, tokenIndex406 := position, tokenIndex
+											{
+												switch buffer[position] {
+												case '?':
+													if buffer[position] != '?' {
+														goto l406
+													}
+													position++
+												case '*':
+													if buffer[position] != '*' {
+														goto l406
+													}
+													position++
+												case '/':
+													if buffer[position] != '/' {
+														goto l406
+													}
+													position++
+												case ':':
+													if buffer[position] != ':' {
+														goto l406
+													}
+													position++
+												case '_':
+													if buffer[position] != '_' {
+														goto l406
+													}
+													position++
+												case '.':
+													if buffer[position] != '.' {
+														goto l406
+													}
+													position++
+												case '-':
+													if buffer[position] != '-' {
+														goto l406
+													}
+													position++
+												case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+													if c := buffer[position]; c < '0' || c > '9' {
+														goto l406
+													}
+													position++
+												case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+													if c := buffer[position]; c < 'A' || c > 'Z' {
+														goto l406
+													}
+													position++
+												default:
+													if c := buffer[position]; c < 'a' || c > 'z' {
+														goto l406
+													}
+													position++
+												}
+											}
+
+											goto l401
+										l406:
+											position, tokenIndex = position406, tokenIndex406
+										}
+										{
+											add(ruleAction42, position)
+										}
+										add(ruleIpv6CidrValue, position402)
+									}
+									goto l340
+								l401:
+									position, tokenIndex = position340, tokenIndex340
+									{
+										position410 := position
+										{
+											position411 := position
+											if !_rules[ruleIpv6Address]() {
+												goto l409
+											}
+											add(rulePegText, position411)
+										}
+										{
+											position412, tokenIndex412 := position, tokenIndex
+											{
+												switch buffer[position] {
+												case '?':
+													if buffer[position] != '?' {
+														goto l412
+													}
+													position++
+												case '*':
+													if buffer[position] != '*' {
+														goto l412
+													}
+													position++
+												case '/':
+													if buffer[position] != '/' {
+														goto l412
+													}
+													position++
+												case ':':
+													if buffer[position] != ':' {
+														goto l412
+													}
+													position++
+												case '_':
+													if buffer[position] != '_' {
+														goto l412
+													}
+													position++
+												case '.':
+													if buffer[position] != '.' {
+														goto l412
+													}
+													position++
+												case '-':
+													if buffer[position] != '-' {
+														goto l412
+													}
+													position++
+												case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+													if c := buffer[position]; c < '0' || c > '9' {
+														goto l412
+													}
+													position++
+												case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+													if c := buffer[position]; c < 'A' || c > 'Z' {
+														goto l412
+													}
+													position++
+												default:
+													if c := buffer[position]; c < 'a' || c > 'z' {
+														goto l412
+													}
+													position++
+												}
+											}
+
+											goto l409
+										l412:
+											position, tokenIndex = position412, tokenIndex412
+										}
+										{
+											add(ruleAction43, position)
+										}
+										add(ruleIpv6Value, position410)
+									}
+									goto l340
+								l409:
+									position, tokenIndex = position340, tokenIndex340
+									{
+										position416 := position
+										{
+											position417 := position
+										l418:
+											{
+												position419, tokenIndex419 := position, tokenIndex
+												{
+													switch buffer[position] {
+													case '/':
+														if buffer[position] != '/' {
+															goto l419
+														}
+														position++
+													case ':':
+														if buffer[position] != ':' {
+															goto l419
+														}
+														position++
+													case '_':
+														if buffer[position] != '_' {
+															goto l419
+														}
+														position++
+													case '.':
+														if buffer[position] != '.' {
+															goto l419
+														}
+														position++
+													case '-':
+														if buffer[position] != '-' {
+															goto l419
+														}
+														position++
+													case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+														if c := buffer[position]; c < '0' || c > '9' {
+															goto l419
+														}
+														position++
+													case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+														if c := buffer[position]; c < 'A' || c > 'Z' {
+															goto l419
+														}
+														position++
+													default:
+														if c := buffer[position]; c < 'a' || c > 'z' {
+															goto l419
+														}
+														position++
+													}
+												}
+
+												goto l418
+											l419:
+												position, tokenIndex = position419, tokenIndex419
+											}
+											{
+												position421, tokenIndex421 := position, tokenIndex
+												if buffer[position] != '*' {
+													goto l422
+												}
+												position++
+												goto l421
+											l422:
+												position, tokenIndex = position421, tokenIndex421
+												if buffer[position] != '?' {
+													goto l415
+												}
+												position++
+											}
+										l421:
+										l423:
+											{
+												position424, tokenIndex424 := position, tokenIndex
+												{
+													switch buffer[position] {
+													case '?':
+														if buffer[position] != '?' {
+															goto l424
+														}
+														position++
+													case '*':
+														if buffer[position] != '*' {
+															goto l424
+														}
+														position++
+													case '/':
+														if buffer[position] != '/' {
+															goto l424
+														}
+														position++
+													case ':':
+														if buffer[position] != ':' {
+															goto l424
+														}
+														position++
+													case '_':
+														if buffer[position] != '_' {
+															goto l424
+														}
+														position++
+													case '.':
+														if buffer[position] != '.' {
+															goto l424
+														}
+														position++
+													case '-':
+														if buffer[position] != '-' {
+															goto l424
+														}
+														position++
+													case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+														if c := buffer[position]; c < '0' || c > '9' {
+															goto l424
+														}
+														position++
+													case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+														if c := buffer[position]; c < 'A' || c > 'Z' {
+															goto l424
+														}
+														position++
+													default:
+														if c := buffer[position]; c < 'a' || c > 'z' {
+															goto l424
+														}
+														position++
+													}
+												}
+
+												goto l423
+											l424:
+												position, tokenIndex = position424, tokenIndex424
+											}
+											add(rulePegText, position417)
+										}
 										{
-											position83 := position
-											if c := buffer[position]; c < rune('0') || c > rune('9') {
-												goto l81
+											add(ruleAction44, position)
+										}
+										add(ruleGlobValue, position416)
+									}
+									goto l340
+								l415:
+									position, tokenIndex = position340, tokenIndex340
+									{
+										position428 := position
+										if buffer[position] != '@' {
+											goto l427
+										}
+										position++
+										{
+											position429 := position
+											{
+												position430 := position
+											l431:
+												{
+													position432, tokenIndex432 := position, tokenIndex
+													{
+														switch buffer[position] {
+														case '_':
+															if buffer[position] != '_' {
+																goto l432
+															}
+															position++
+														case '.':
+															if buffer[position] != '.' {
+																goto l432
+															}
+															position++
+														case '-':
+															if buffer[position] != '-' {
+																goto l432
+															}
+															position++
+														case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+															if c := buffer[position]; c < '0' || c > '9' {
+																goto l432
+															}
+															position++
+														case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+															if c := buffer[position]; c < 'A' || c > 'Z' {
+																goto l432
+															}
+															position++
+														default:
+															if c := buffer[position]; c < 'a' || c > 'z' {
+																goto l432
+															}
+															position++
+														}
+													}
+
+													goto l431
+												l432:
+													position, tokenIndex = position432, tokenIndex432
+												}
+												if buffer[position] != '/' {
+													goto l427
+												}
+												position++
+												{
+													switch buffer[position] {
+													case '/':
+														if buffer[position] != '/' {
+															goto l427
+														}
+														position++
+													case '_':
+														if buffer[position] != '_' {
+															goto l427
+														}
+														position++
+													case '.':
+														if buffer[position] != '.' {
+															goto l427
+														}
+														position++
+													case '-':
+														if buffer[position] != '-' {
+															goto l427
+														}
+														position++
+													case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+														if c := buffer[position]; c < '0' || c > '9' {
+															goto l427
+														}
+														position++
+													case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+														if c := buffer[position]; c < 'A' || c > 'Z' {
+															goto l427
+														}
+														position++
+													default:
+														if c := buffer[position]; c < 'a' || c > 'z' {
+															goto l427
+														}
+														position++
+													}
+												}
+
+											l434:
+												{
+													position435, tokenIndex435 := position, tokenIndex
+													{
+														switch buffer[position] {
+														case '/':
+															if buffer[position] != '/' {
+																goto l435
+															}
+															position++
+														case '_':
+															if buffer[position] != '_' {
+																goto l435
+															}
+															position++
+														case '.':
+															if buffer[position] != '.' {
+																goto l435
+															}
+															position++
+														case '-':
+															if buffer[position] != '-' {
+																goto l435
+															}
+															position++
+														case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+															if c := buffer[position]; c < '0' || c > '9' {
+																goto l435
+															}
+															position++
+														case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+															if c := buffer[position]; c < 'A' || c > 'Z' {
+																goto l435
+															}
+															position++
+														default:
+															if c := buffer[position]; c < 'a' || c > 'z' {
+																goto l435
+															}
+															position++
+														}
+													}
+
+													goto l434
+												l435:
+													position, tokenIndex = position435, tokenIndex435
+												}
+												add(ruleFilePath, position430)
+											}
+											add(rulePegText, position429)
+										}
+										add(ruleFileValue, position428)
+									}
+									{
+										add(ruleAction14, position)
+									}
+									goto l340
+								l427:
+									position, tokenIndex = position340, tokenIndex340
+									{
+										position440 := position
+										{
+											position441 := position
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l439
 											}
 											position++
-										l84:
+										l442:
 											{
-												position85, tokenIndex85 := position, tokenIndex
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l85
+												position443, tokenIndex443 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l443
 												}
 												position++
-												goto l84
-											l85:
-												position, tokenIndex = position85, tokenIndex85
+												goto l442
+											l443:
+												position, tokenIndex = position443, tokenIndex443
 											}
 											if !matchDot() {
-												goto l81
+												goto l439
 											}
-											if c := buffer[position]; c < rune('0') || c > rune('9') {
-												goto l81
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l439
 											}
 											position++
-										l86:
+										l444:
 											{
-												position87, tokenIndex87 := position, tokenIndex
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l87
+												position445, tokenIndex445 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l445
 												}
 												position++
-												goto l86
-											l87:
-												position, tokenIndex = position87, tokenIndex87
+												goto l444
+											l445:
+												position, tokenIndex = position445, tokenIndex445
 											}
 											if !matchDot() {
-												goto l81
+												goto l439
 											}
-											if c := buffer[position]; c < rune('0') || c > rune('9') {
-												goto l81
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l439
 											}
 											position++
-										l88:
+										l446:
 											{
-												position89, tokenIndex89 := position, tokenIndex
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l89
+												position447, tokenIndex447 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l447
 												}
 												position++
-												goto l88
-											l89:
-												position, tokenIndex = position89, tokenIndex89
+												goto l446
+											l447:
+												position, tokenIndex = position447, tokenIndex447
 											}
 											if !matchDot() {
-												goto l81
+												goto l439
 											}
-											if c := buffer[position]; c < rune('0') || c > rune('9') {
-												goto l81
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l439
 											}
 											position++
-										l90:
+										l448:
 											{
-												position91, tokenIndex91 := position, tokenIndex
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l91
+												position449, tokenIndex449 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l449
 												}
 												position++
-												goto l90
-											l91:
-												position, tokenIndex = position91, tokenIndex91
+												goto l448
+											l449:
+												position, tokenIndex = position449, tokenIndex449
 											}
-											if buffer[position] != rune('/') {
-												goto l81
+											if buffer[position] != '/' {
+												goto l439
 											}
 											position++
-											if c := buffer[position]; c < rune('0') || c > rune('9') {
-												goto l81
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l439
 											}
 											position++
-										l92:
+										l450:
 											{
-												position93, tokenIndex93 := position, tokenIndex
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l93
+												position451, tokenIndex451 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l451
 												}
 												position++
-												goto l92
-											l93:
-												position, tokenIndex = position93, tokenIndex93
+												goto l450
+											l451:
+												position, tokenIndex = position451, tokenIndex451
 											}
-											add(ruleCidrValue, position83)
+											add(ruleCidrValue, position441)
 										}
-										add(rulePegText, position82)
+										add(rulePegText, position440)
 									}
 									{
-										add(ruleAction8, position)
+										add(ruleAction17, position)
 									}
-									goto l80
-								l81:
-									position, tokenIndex = position80, tokenIndex80
+									goto l340
+								l439:
+									position, tokenIndex = position340, tokenIndex340
 									{
-										position96 := position
+										position454 := position
 										{
-											position97 := position
-											if c := buffer[position]; c < rune('0') || c > rune('9') {
-												goto l95
+											position455 := position
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l453
 											}
 											position++
-										l98:
+										l456:
 											{
-												position99, tokenIndex99 := position, tokenIndex
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l99
+												position457, tokenIndex457 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l457
 												}
 												position++
-												goto l98
-											l99:
-												position, tokenIndex = position99, tokenIndex99
+												goto l456
+											l457:
+												position, tokenIndex = position457, tokenIndex457
 											}
 											if !matchDot() {
-												goto l95
+												goto l453
 											}
-											if c := buffer[position]; c < rune('0') || c > rune('9') {
-												goto l95
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l453
 											}
 											position++
-										l100:
+										l458:
 											{
-												position101, tokenIndex101 := position, tokenIndex
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l101
+												position459, tokenIndex459 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l459
 												}
 												position++
-												goto l100
-											l101:
-												position, tokenIndex = position101, tokenIndex101
+												goto l458
+											l459:
+												position, tokenIndex = position459, tokenIndex459
 											}
 											if !matchDot() {
-												goto l95
+												goto l453
 											}
-											if c := buffer[position]; c < rune('0') || c > rune('9') {
-												goto l95
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l453
 											}
 											position++
-										l102:
+										l460:
 											{
-												position103, tokenIndex103 := position, tokenIndex
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l103
+												position461, tokenIndex461 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l461
 												}
 												position++
-												goto l102
-											l103:
-												position, tokenIndex = position103, tokenIndex103
+												goto l460
+											l461:
+												position, tokenIndex = position461, tokenIndex461
 											}
 											if !matchDot() {
-												goto l95
+												goto l453
 											}
-											if c := buffer[position]; c < rune('0') || c > rune('9') {
-												goto l95
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l453
 											}
 											position++
-										l104:
+										l462:
 											{
-												position105, tokenIndex105 := position, tokenIndex
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l105
+												position463, tokenIndex463 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l463
 												}
 												position++
-												goto l104
-											l105:
-												position, tokenIndex = position105, tokenIndex105
+												goto l462
+											l463:
+												position, tokenIndex = position463, tokenIndex463
 											}
-											add(ruleIpValue, position97)
+											add(ruleIpValue, position455)
 										}
-										add(rulePegText, position96)
+										add(rulePegText, position454)
 									}
 									{
-										add(ruleAction9, position)
+										add(ruleAction18, position)
 									}
-									goto l80
-								l95:
-									position, tokenIndex = position80, tokenIndex80
+									goto l340
+								l453:
+									position, tokenIndex = position340, tokenIndex340
 									{
-										position108 := position
+										position466 := position
 										{
-											position109 := position
-											if c := buffer[position]; c < rune('0') || c > rune('9') {
-												goto l107
+											position467 := position
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l465
 											}
 											position++
-										l110:
+										l468:
 											{
-												position111, tokenIndex111 := position, tokenIndex
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l111
+												position469, tokenIndex469 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l469
 												}
 												position++
-												goto l110
-											l111:
-												position, tokenIndex = position111, tokenIndex111
+												goto l468
+											l469:
+												position, tokenIndex = position469, tokenIndex469
 											}
-											if buffer[position] != rune('-') {
-												goto l107
+											if buffer[position] != '-' {
+												goto l465
 											}
 											position++
-											if c := buffer[position]; c < rune('0') || c > rune('9') {
-												goto l107
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l465
 											}
 											position++
-										l112:
+										l470:
 											{
-												position113, tokenIndex113 := position, tokenIndex
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l113
+												position471, tokenIndex471 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l471
 												}
 												position++
-												goto l112
-											l113:
-												position, tokenIndex = position113, tokenIndex113
+												goto l470
+											l471:
+												position, tokenIndex = position471, tokenIndex471
 											}
-											add(ruleIntRangeValue, position109)
+											add(ruleIntRangeValue, position467)
 										}
-										add(rulePegText, position108)
+										add(rulePegText, position466)
 									}
 									{
-										add(ruleAction10, position)
+										add(ruleAction19, position)
 									}
-									goto l80
-								l107:
-									position, tokenIndex = position80, tokenIndex80
+									goto l340
+								l465:
+									position, tokenIndex = position340, tokenIndex340
 									{
-										position116 := position
+										position474 := position
 										{
-											position117 := position
-											if c := buffer[position]; c < rune('0') || c > rune('9') {
-												goto l115
+											position475 := position
+											{
+												position476, tokenIndex476 := position, tokenIndex
+												if buffer[position] != '-' {
+													goto l476
+												}
+												position++
+												goto l477
+											l476:
+												position, tokenIndex = position476, tokenIndex476
+											}
+										l477:
+											if c := buffer[position]; c < '0' || c > '9' {
+												goto l473
 											}
 											position++
-										l118:
+										l478:
 											{
-												position119, tokenIndex119 := position, tokenIndex
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l119
+												position479, tokenIndex479 := position, tokenIndex
+												if c := buffer[position]; c < '0' || c > '9' {
+													goto l479
 												}
 												position++
-												goto l118
-											l119:
-												position, tokenIndex = position119, tokenIndex119
+												goto l478
+											l479:
+												position, tokenIndex = position479, tokenIndex479
 											}
-											add(ruleIntValue, position117)
+											add(ruleIntValue, position475)
 										}
-										add(rulePegText, position116)
+										add(rulePegText, position474)
 									}
 									{
-										add(ruleAction11, position)
+										add(ruleAction20, position)
 									}
-									goto l80
-								l115:
-									position, tokenIndex = position80, tokenIndex80
+									goto l340
+								l473:
+									position, tokenIndex = position340, tokenIndex340
 									{
 										switch buffer[position] {
-										case '$':
-											{
-												position122 := position
-												if buffer[position] != rune('$') {
-													goto l71
-												}
-												position++
-												{
-													position123 := position
-													if !_rules[ruleIdentifier]() {
-														goto l71
-													}
-													add(rulePegText, position123)
-												}
-												add(ruleRefValue, position122)
-											}
-											{
-												add(ruleAction7, position)
-											}
-											break
-										case '@':
-											{
-												position125 := position
-												if buffer[position] != rune('@') {
-													goto l71
-												}
-												position++
-												{
-													position126 := position
-													if !_rules[ruleIdentifier]() {
-														goto l71
-													}
-													add(rulePegText, position126)
-												}
-												add(ruleAliasValue, position125)
-											}
-											{
-												add(ruleAction6, position)
+										case '"':
+											if buffer[position] != '"' {
+												goto l139
 											}
-											break
-										case '{':
+											position++
 											{
-												position128 := position
-												if buffer[position] != rune('{') {
-													goto l71
-												}
-												position++
-												if !_rules[ruleWhiteSpacing]() {
-													goto l71
+												position482 := position
+												if !_rules[ruleQuotedValue]() {
+													goto l139
 												}
-												{
-													position129 := position
-													if !_rules[ruleIdentifier]() {
-														goto l71
-													}
-													add(rulePegText, position129)
-												}
-												if !_rules[ruleWhiteSpacing]() {
-													goto l71
-												}
-												if buffer[position] != rune('}') {
-													goto l71
-												}
-												position++
-												add(ruleHoleValue, position128)
+												add(rulePegText, position482)
 											}
-											{
-												add(ruleAction5, position)
-											}
-											break
-										default:
-											{
-												position131 := position
-												{
-													position132 := position
-													{
-														switch buffer[position] {
-														case '/':
-															if buffer[position] != rune('/') {
-																goto l71
-															}
-															position++
-															break
-														case ':':
-															if buffer[position] != rune(':') {
-																goto l71
-															}
-															position++
-															break
-														case '_':
-															if buffer[position] != rune('_') {
-																goto l71
-															}
-															position++
-															break
-														case '.':
-															if buffer[position] != rune('.') {
-																goto l71
-															}
-															position++
-															break
-														case '-':
-															if buffer[position] != rune('-') {
-																goto l71
-															}
-															position++
-															break
-														case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-															if c := buffer[position]; c < rune('0') || c > rune('9') {
-																goto l71
-															}
-															position++
-															break
-														case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
-															if c := buffer[position]; c < rune('A') || c > rune('Z') {
-																goto l71
-															}
-															position++
-															break
-														default:
-															if c := buffer[position]; c < rune('a') || c > rune('z') {
-																goto l71
-															}
-															position++
-															break
-														}
-													}
-
-												l133:
-													{
-														position134, tokenIndex134 := position, tokenIndex
-														{
-															switch buffer[position] {
-															case '/':
-																if buffer[position] != rune('/') {
-																	goto l134
-																}
-																position++
-																break
-															case ':':
-																if buffer[position] != rune(':') {
-																	goto l134
-																}
-																position++
-																break
-															case '_':
-																if buffer[position] != rune('_') {
-																	goto l134
-																}
-																position++
-																break
-															case '.':
-																if buffer[position] != rune('.') {
-																	goto l134
-																}
-																position++
-																break
-															case '-':
-																if buffer[position] != rune('-') {
-																	goto l134
-																}
-																position++
-																break
-															case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-																if c := buffer[position]; c < rune('0') || c > rune('9') {
-																	goto l134
-																}
-																position++
-																break
-															case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
-																if c := buffer[position]; c < rune('A') || c > rune('Z') {
-																	goto l134
-																}
-																position++
-																break
-															default:
-																if c := buffer[position]; c < rune('a') || c > rune('z') {
-																	goto l134
-																}
-																position++
-																break
-															}
-														}
-
-														goto l133
-													l134:
-														position, tokenIndex = position134, tokenIndex134
-													}
-													add(ruleStringValue, position132)
-												}
-												add(rulePegText, position131)
+											if buffer[position] != '"' {
+												goto l139
 											}
+											position++
 											{
-												add(ruleAction12, position)
+												add(ruleAction22, position)
 											}
-											break
-										}
-									}
-
-								}
-							l80:
-								add(ruleValue, position79)
-							}
-							if !_rules[ruleWhiteSpacing]() {
-								goto l71
-							}
-							add(ruleParam, position76)
-						}
-					l74:
-						{
-							position75, tokenIndex75 := position, tokenIndex
-							{
-								position138 := position
-								{
-									position139 := position
-									if !_rules[ruleIdentifier]() {
-										goto l75
-									}
-									add(rulePegText, position139)
-								}
-								{
-									add(ruleAction4, position)
-								}
-								if !_rules[ruleEqual]() {
-									goto l75
-								}
-								{
-									position141 := position
-									{
-										position142, tokenIndex142 := position, tokenIndex
-										{
-											position144 := position
+										case '$':
 											{
-												position145 := position
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l143
-												}
-												position++
-											l146:
-												{
-													position147, tokenIndex147 := position, tokenIndex
-													if c := buffer[position]; c < rune('0') || c > rune('9') {
-														goto l147
-													}
-													position++
-													goto l146
-												l147:
-													position, tokenIndex = position147, tokenIndex147
-												}
-												if !matchDot() {
-													goto l143
-												}
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l143
-												}
-												position++
-											l148:
-												{
-													position149, tokenIndex149 := position, tokenIndex
-													if c := buffer[position]; c < rune('0') || c > rune('9') {
-														goto l149
-													}
-													position++
-													goto l148
-												l149:
-													position, tokenIndex = position149, tokenIndex149
-												}
-												if !matchDot() {
-													goto l143
-												}
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l143
-												}
-												position++
-											l150:
-												{
-													position151, tokenIndex151 := position, tokenIndex
-													if c := buffer[position]; c < rune('0') || c > rune('9') {
-														goto l151
-													}
-													position++
-													goto l150
-												l151:
-													position, tokenIndex = position151, tokenIndex151
-												}
-												if !matchDot() {
-													goto l143
-												}
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l143
-												}
-												position++
-											l152:
-												{
-													position153, tokenIndex153 := position, tokenIndex
-													if c := buffer[position]; c < rune('0') || c > rune('9') {
-														goto l153
-													}
-													position++
-													goto l152
-												l153:
-													position, tokenIndex = position153, tokenIndex153
-												}
-												if buffer[position] != rune('/') {
-													goto l143
-												}
-												position++
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l143
+												position484 := position
+												if buffer[position] != '$' {
+													goto l139
 												}
 												position++
-											l154:
 												{
-													position155, tokenIndex155 := position, tokenIndex
-													if c := buffer[position]; c < rune('0') || c > rune('9') {
-														goto l155
+													position485 := position
+													if !_rules[ruleIdentifier]() {
+														goto l139
 													}
-													position++
-													goto l154
-												l155:
-													position, tokenIndex = position155, tokenIndex155
+													add(rulePegText, position485)
 												}
-												add(ruleCidrValue, position145)
+												add(ruleRefValue, position484)
 											}
-											add(rulePegText, position144)
-										}
-										{
-											add(ruleAction8, position)
-										}
-										goto l142
-									l143:
-										position, tokenIndex = position142, tokenIndex142
-										{
-											position158 := position
 											{
-												position159 := position
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l157
+												add(ruleAction16, position)
+											}
+										case '@':
+											{
+												position487 := position
+												if buffer[position] != '@' {
+													goto l139
 												}
 												position++
-											l160:
 												{
-													position161, tokenIndex161 := position, tokenIndex
-													if c := buffer[position]; c < rune('0') || c > rune('9') {
-														goto l161
+													position488 := position
+													if !_rules[ruleIdentifier]() {
+														goto l139
 													}
-													position++
-													goto l160
-												l161:
-													position, tokenIndex = position161, tokenIndex161
-												}
-												if !matchDot() {
-													goto l157
+													add(rulePegText, position488)
 												}
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l157
+												add(ruleAliasValue, position487)
+											}
+											{
+												add(ruleAction15, position)
+											}
+										case '{':
+											{
+												position490 := position
+												if buffer[position] != '{' {
+													goto l139
 												}
 												position++
-											l162:
-												{
-													position163, tokenIndex163 := position, tokenIndex
-													if c := buffer[position]; c < rune('0') || c > rune('9') {
-														goto l163
-													}
-													position++
-													goto l162
-												l163:
-													position, tokenIndex = position163, tokenIndex163
-												}
-												if !matchDot() {
-													goto l157
-												}
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l157
+												if !_rules[ruleWhiteSpacing]() {
+													goto l139
 												}
-												position++
-											l164:
 												{
-													position165, tokenIndex165 := position, tokenIndex
-													if c := buffer[position]; c < rune('0') || c > rune('9') {
-														goto l165
+													position491 := position
+													if !_rules[ruleIdentifier]() {
+														goto l139
 													}
-													position++
-													goto l164
-												l165:
-													position, tokenIndex = position165, tokenIndex165
+													add(rulePegText, position491)
 												}
-												if !matchDot() {
-													goto l157
+												if !_rules[ruleWhiteSpacing]() {
+													goto l139
 												}
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l157
+												if buffer[position] != '}' {
+													goto l139
 												}
 												position++
-											l166:
-												{
-													position167, tokenIndex167 := position, tokenIndex
-													if c := buffer[position]; c < rune('0') || c > rune('9') {
-														goto l167
-													}
-													position++
-													goto l166
-												l167:
-													position, tokenIndex = position167, tokenIndex167
-												}
-												add(ruleIpValue, position159)
+												add(ruleHoleValue, position490)
 											}
-											add(rulePegText, position158)
-										}
-										{
-											add(ruleAction9, position)
-										}
-										goto l142
-									l157:
-										position, tokenIndex = position142, tokenIndex142
-										{
-											position170 := position
 											{
-												position171 := position
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l169
-												}
-												position++
-											l172:
-												{
-													position173, tokenIndex173 := position, tokenIndex
-													if c := buffer[position]; c < rune('0') || c > rune('9') {
-														goto l173
-													}
-													position++
-													goto l172
-												l173:
-													position, tokenIndex = position173, tokenIndex173
-												}
-												if buffer[position] != rune('-') {
-													goto l169
+												add(ruleAction13, position)
+											}
+										case '<':
+											{
+												position493 := position
+												if buffer[position] != '<' {
+													goto l139
 												}
 												position++
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l169
+												if buffer[position] != '<' {
+													goto l139
 												}
 												position++
-											l174:
-												{
-													position175, tokenIndex175 := position, tokenIndex
-													if c := buffer[position]; c < rune('0') || c > rune('9') {
-														goto l175
-													}
-													position++
-													goto l174
-												l175:
-													position, tokenIndex = position175, tokenIndex175
-												}
-												add(ruleIntRangeValue, position171)
-											}
-											add(rulePegText, position170)
-										}
-										{
-											add(ruleAction10, position)
-										}
-										goto l142
-									l169:
-										position, tokenIndex = position142, tokenIndex142
-										{
-											position178 := position
-											{
-												position179 := position
-												if c := buffer[position]; c < rune('0') || c > rune('9') {
-													goto l177
+												if c := buffer[position]; c < 'A' || c > 'Z' {
+													goto l139
 												}
 												position++
-											l180:
-												{
-													position181, tokenIndex181 := position, tokenIndex
-													if c := buffer[position]; c < rune('0') || c > rune('9') {
-														goto l181
-													}
-													position++
-													goto l180
-												l181:
-													position, tokenIndex = position181, tokenIndex181
-												}
-												add(ruleIntValue, position179)
-											}
-											add(rulePegText, position178)
-										}
-										{
-											add(ruleAction11, position)
-										}
-										goto l142
-									l177:
-										position, tokenIndex = position142, tokenIndex142
-										{
-											switch buffer[position] {
-											case '$':
+											l494:
 												{
-													position184 := position
-													if buffer[position] != rune('$') {
-														goto l75
-													}
-													position++
+													position495, tokenIndex495 := position, tokenIndex
 													{
-														position185 := position
-														if !_rules[ruleIdentifier]() {
-															goto l75
+														switch buffer[position] {
+														case '_':
+															if buffer[position] != '_' {
+																goto l495
+															}
+															position++
+														case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+															if c := buffer[position]; c < '0' || c > '9' {
+																goto l495
+															}
+															position++
+														default:
+															if c := buffer[position]; c < 'A' || c > 'Z' {
+																goto l495
+															}
+															position++
 														}
-														add(rulePegText, position185)
 													}
-													add(ruleRefValue, position184)
+
+													goto l494
+												l495:
+													position, tokenIndex = position495, tokenIndex495
 												}
-												{
-													add(ruleAction7, position)
+												if !_rules[ruleEndOfLine]() {
+													goto l139
 												}
-												break
-											case '@':
 												{
-													position187 := position
-													if buffer[position] != rune('@') {
-														goto l75
-													}
-													position++
+													position497 := position
 													{
-														position188 := position
-														if !_rules[ruleIdentifier]() {
-															goto l75
+														position498 := position
+														if !(skipHeredocBody(buffer, &position)) {
+															goto l139
 														}
-														add(rulePegText, position188)
+														add(ruleHeredocBody, position498)
 													}
-													add(ruleAliasValue, position187)
+													add(rulePegText, position497)
 												}
 												{
-													add(ruleAction6, position)
+													add(ruleAction27, position)
 												}
-												break
-											case '{':
 												{
-													position190 := position
-													if buffer[position] != rune('{') {
-														goto l75
-													}
-													position++
-													if !_rules[ruleWhiteSpacing]() {
-														goto l75
-													}
+													position500 := position
 													{
-														position191 := position
-														if !_rules[ruleIdentifier]() {
-															goto l75
+														position501, tokenIndex501 := position, tokenIndex
+														if !_rules[ruleEndOfLine]() {
+															goto l501
 														}
-														add(rulePegText, position191)
+														goto l502
+													l501:
+														position, tokenIndex = position501, tokenIndex501
 													}
-													if !_rules[ruleWhiteSpacing]() {
-														goto l75
+												l502:
+												l503:
+													{
+														position504, tokenIndex504 := position, tokenIndex
+														{
+															position505, tokenIndex505 := position, tokenIndex
+															if buffer[position] != ' ' {
+																goto l506
+															}
+															position++
+															goto l505
+														l506:
+															position, tokenIndex = position505, tokenIndex505
+															if buffer[position] != '\t' {
+																goto l504
+															}
+															position++
+														}
+													l505:
+														goto l503
+													l504:
+														position, tokenIndex = position504, tokenIndex504
 													}
-													if buffer[position] != rune('}') {
-														goto l75
+													if c := buffer[position]; c < 'A' || c > 'Z' {
+														goto l139
 													}
 													position++
-													add(ruleHoleValue, position190)
-												}
-												{
-													add(ruleAction5, position)
-												}
-												break
-											default:
-												{
-													position193 := position
+												l507:
 													{
-														position194 := position
+														position508, tokenIndex508 := position, tokenIndex
 														{
 															switch buffer[position] {
-															case '/':
-																if buffer[position] != rune('/') {
-																	goto l75
-																}
-																position++
-																break
-															case ':':
-																if buffer[position] != rune(':') {
-																	goto l75
-																}
-																position++
-																break
 															case '_':
-																if buffer[position] != rune('_') {
-																	goto l75
+																if buffer[position] != '_' {
+																	goto l508
 																}
 																position++
-																break
-															case '.':
-																if buffer[position] != rune('.') {
-																	goto l75
-																}
-																position++
-																break
-															case '-':
-																if buffer[position] != rune('-') {
-																	goto l75
-																}
-																position++
-																break
 															case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-																if c := buffer[position]; c < rune('0') || c > rune('9') {
-																	goto l75
+																if c := buffer[position]; c < '0' || c > '9' {
+																	goto l508
 																}
 																position++
-																break
-															case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
-																if c := buffer[position]; c < rune('A') || c > rune('Z') {
-																	goto l75
-																}
-																position++
-																break
 															default:
-																if c := buffer[position]; c < rune('a') || c > rune('z') {
-																	goto l75
+																if c := buffer[position]; c < 'A' || c > 'Z' {
+																	goto l508
 																}
 																position++
-																break
 															}
 														}
 
-													l195:
+														goto l507
+													l508:
+														position, tokenIndex = position508, tokenIndex508
+													}
+												l510:
+													{
+														position511, tokenIndex511 := position, tokenIndex
 														{
-															position196, tokenIndex196 := position, tokenIndex
-															{
-																switch buffer[position] {
-																case '/':
-																	if buffer[position] != rune('/') {
-																		goto l196
-																	}
-																	position++
-																	break
-																case ':':
-																	if buffer[position] != rune(':') {
-																		goto l196
-																	}
-																	position++
-																	break
-																case '_':
-																	if buffer[position] != rune('_') {
-																		goto l196
-																	}
-																	position++
-																	break
-																case '.':
-																	if buffer[position] != rune('.') {
-																		goto l196
-																	}
-																	position++
-																	break
-																case '-':
-																	if buffer[position] != rune('-') {
-																		goto l196
-																	}
-																	position++
-																	break
-																case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-																	if c := buffer[position]; c < rune('0') || c > rune('9') {
-																		goto l196
-																	}
-																	position++
-																	break
-																case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
-																	if c := buffer[position]; c < rune('A') || c > rune('Z') {
-																		goto l196
-																	}
-																	position++
-																	break
-																default:
-																	if c := buffer[position]; c < rune('a') || c > rune('z') {
-																		goto l196
-																	}
-																	position++
-																	break
-																}
+															position512, tokenIndex512 := position, tokenIndex
+															if buffer[position] != ' ' {
+																goto l513
 															}
-
-															goto l195
-														l196:
-															position, tokenIndex = position196, tokenIndex196
+															position++
+															goto l512
+														l513:
+															position, tokenIndex = position512, tokenIndex512
+															if buffer[position] != '\t' {
+																goto l511
+															}
+															position++
 														}
-														add(ruleStringValue, position194)
+													l512:
+														goto l510
+													l511:
+														position, tokenIndex = position511, tokenIndex511
 													}
-													add(rulePegText, position193)
+													add(ruleHeredocEnd, position500)
 												}
-												{
-													add(ruleAction12, position)
+												add(ruleHeredocValue, position493)
+											}
+										default:
+											{
+												position514 := position
+												if !_rules[ruleStringValue]() {
+													goto l139
 												}
-												break
+												add(rulePegText, position514)
+											}
+											{
+												add(ruleAction21, position)
 											}
 										}
+									}
+
+								}
+							l340:
+								add(ruleValue, position339)
+							}
+							if !_rules[ruleWhiteSpacing]() {
+								goto l139
+							}
+							add(ruleParam, position333)
+						}
+					}
+				l328:
+					goto l138
+				l139:
+					position, tokenIndex = position139, tokenIndex139
+				}
+				add(ruleParams, position137)
+			}
+			return true
+		l136:
+			position, tokenIndex = position136, tokenIndex136
+			return false
+		},
+		/* 8 ParamSet <- <(<Identifier> Action8 Equal Params Action9)> */
+		nil,
+		/* 9 ParamSplat <- <('.' '.' '.' <Identifier> Action10 WhiteSpacing)> */
+		nil,
+		/* 10 Param <- <(<Identifier> Action11 ('?' Action12)? Equal Value WhiteSpacing)> */
+		nil,
+		/* 11 Identifier <- <([a-z] / [A-Z] / '-' / '_' / '.' / UnicodeLetter)+> */
+		func() bool {
+			position519, tokenIndex519 := position, tokenIndex
+			{
+				position520 := position
+				{
+					position523, tokenIndex523 := position, tokenIndex
+					if c := buffer[position]; c < 'a' || c > 'z' {
+						goto l524
+					}
+					position++
+					goto l523
+				l524:
+					position, tokenIndex = position523, tokenIndex523
+					if c := buffer[position]; c < 'A' || c > 'Z' {
+						goto l525
+					}
+					position++
+					goto l523
+				l525:
+					position, tokenIndex = position523, tokenIndex523
+					if buffer[position] != '-' {
+						goto l526
+					}
+					position++
+					goto l523
+				l526:
+					position, tokenIndex = position523, tokenIndex523
+					if buffer[position] != '_' {
+						goto l527
+					}
+					position++
+					goto l523
+				l527:
+					position, tokenIndex = position523, tokenIndex523
+					if buffer[position] != '.' {
+						goto l528
+					}
+					position++
+					goto l523
+				l528:
+					position, tokenIndex = position523, tokenIndex523
+					{
+						position529 := position
+						if !(isUnicodeLetter(buffer[position:])) {
+							goto l519
+						}
+						if !matchDot() {
+							goto l519
+						}
+						add(ruleUnicodeLetter, position529)
+					}
+				}
+			l523:
+			l521:
+				{
+					position522, tokenIndex522 := position, tokenIndex
+					{
+						position530, tokenIndex530 := position, tokenIndex
+						if c := buffer[position]; c < 'a' || c > 'z' {
+							goto l531
+						}
+						position++
+						goto l530
+					l531:
+						position, tokenIndex = position530, tokenIndex530
+						if c := buffer[position]; c < 'A' || c > 'Z' {
+							goto l532
+						}
+						position++
+						goto l530
+					l532:
+						position, tokenIndex = position530, tokenIndex530
+						if buffer[position] != '-' {
+							goto l533
+						}
+						position++
+						goto l530
+					l533:
+						position, tokenIndex = position530, tokenIndex530
+						if buffer[position] != '_' {
+							goto l534
+						}
+						position++
+						goto l530
+					l534:
+						position, tokenIndex = position530, tokenIndex530
+						if buffer[position] != '.' {
+							goto l535
+						}
+						position++
+						goto l530
+					l535:
+						position, tokenIndex = position530, tokenIndex530
+						{
+							position536 := position
+							if !(isUnicodeLetter(buffer[position:])) {
+								goto l522
+							}
+							if !matchDot() {
+								goto l522
+							}
+							add(ruleUnicodeLetter, position536)
+						}
+					}
+				l530:
+					goto l521
+				l522:
+					position, tokenIndex = position522, tokenIndex522
+				}
+				add(ruleIdentifier, position520)
+			}
+			return true
+		l519:
+			position, tokenIndex = position519, tokenIndex519
+			return false
+		},
+		/* 12 UnicodeLetter <- <(&{ isUnicodeLetter(buffer[position:]) } .)> */
+		nil,
+		/* 13 Value <- <(FuncValue / ConcatValue / ListValue / TypedHoleValue / MapValue / BoolValue / FloatValue / Ipv6CidrValue / Ipv6Value / GlobValue / (FileValue Action14) / (<CidrValue> Action17) / (<IpValue> Action18) / (<IntRangeValue> Action19) / (<IntValue> Action20) / ((&('"') ('"' <QuotedValue> '"' Action22)) | (&('$') (RefValue Action16)) | (&('@') (AliasValue Action15)) | (&('{') (HoleValue Action13)) | (&('<') HeredocValue) | (&('*' | '-' | '.' | '/' | '0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9' | ':' | '?' | 'A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z' | '_' | 'a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') (<StringValue> Action21))))> */
+		nil,
+		/* 14 FuncValue <- <(<Identifier> Action23 '(' WhiteSpacing (FuncArg (WhiteSpacing ',' WhiteSpacing FuncArg)*)? WhiteSpacing ')')> */
+		nil,
+		/* 15 FuncArg <- <((&('"') ('"' <QuotedValue> '"' Action25)) | (&('{') ('{' WhiteSpacing <Identifier> WhiteSpacing '}' Action24)) | (&('*' | '-' | '.' | '/' | '0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9' | ':' | '?' | 'A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z' | '_' | 'a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') (<StringValue> Action26)))> */
+		func() bool {
+			position540, tokenIndex540 := position, tokenIndex
+			{
+				position541 := position
+				{
+					switch buffer[position] {
+					case '"':
+						if buffer[position] != '"' {
+							goto l540
+						}
+						position++
+						{
+							position543 := position
+							if !_rules[ruleQuotedValue]() {
+								goto l540
+							}
+							add(rulePegText, position543)
+						}
+						if buffer[position] != '"' {
+							goto l540
+						}
+						position++
+						{
+							add(ruleAction25, position)
+						}
+					case '{':
+						if buffer[position] != '{' {
+							goto l540
+						}
+						position++
+						if !_rules[ruleWhiteSpacing]() {
+							goto l540
+						}
+						{
+							position545 := position
+							if !_rules[ruleIdentifier]() {
+								goto l540
+							}
+							add(rulePegText, position545)
+						}
+						if !_rules[ruleWhiteSpacing]() {
+							goto l540
+						}
+						if buffer[position] != '}' {
+							goto l540
+						}
+						position++
+						{
+							add(ruleAction24, position)
+						}
+					default:
+						{
+							position547 := position
+							if !_rules[ruleStringValue]() {
+								goto l540
+							}
+							add(rulePegText, position547)
+						}
+						{
+							add(ruleAction26, position)
+						}
+					}
+				}
+
+				add(ruleFuncArg, position541)
+			}
+			return true
+		l540:
+			position, tokenIndex = position540, tokenIndex540
+			return false
+		},
+		/* 16 HeredocValue <- <('<' '<' [A-Z] ((&('_') '_') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]))* EndOfLine <HeredocBody> Action27 HeredocEnd)> */
+		nil,
+		/* 17 HeredocBody <- <&{ skipHeredocBody(buffer, &position) }> */
+		nil,
+		/* 18 HeredocEnd <- <(EndOfLine? (' ' / '\t')* [A-Z] ((&('_') '_') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]))* (' ' / '\t')*)> */
+		nil,
+		/* 19 ConcatValue <- <(Action28 ConcatItem (WhiteSpacing '+' WhiteSpacing ConcatItem)+)> */
+		nil,
+		/* 20 ConcatItem <- <((&('"') ('"' <QuotedValue> '"' Action30)) | (&('{') ('{' WhiteSpacing <Identifier> WhiteSpacing '}' Action29)) | (&('*' | '-' | '.' | '/' | '0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9' | ':' | '?' | 'A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z' | '_' | 'a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') (<StringValue> Action31)))> */
+		func() bool {
+			position553, tokenIndex553 := position, tokenIndex
+			{
+				position554 := position
+				{
+					switch buffer[position] {
+					case '"':
+						if buffer[position] != '"' {
+							goto l553
+						}
+						position++
+						{
+							position556 := position
+							if !_rules[ruleQuotedValue]() {
+								goto l553
+							}
+							add(rulePegText, position556)
+						}
+						if buffer[position] != '"' {
+							goto l553
+						}
+						position++
+						{
+							add(ruleAction30, position)
+						}
+					case '{':
+						if buffer[position] != '{' {
+							goto l553
+						}
+						position++
+						if !_rules[ruleWhiteSpacing]() {
+							goto l553
+						}
+						{
+							position558 := position
+							if !_rules[ruleIdentifier]() {
+								goto l553
+							}
+							add(rulePegText, position558)
+						}
+						if !_rules[ruleWhiteSpacing]() {
+							goto l553
+						}
+						if buffer[position] != '}' {
+							goto l553
+						}
+						position++
+						{
+							add(ruleAction29, position)
+						}
+					default:
+						{
+							position560 := position
+							if !_rules[ruleStringValue]() {
+								goto l553
+							}
+							add(rulePegText, position560)
+						}
+						{
+							add(ruleAction31, position)
+						}
+					}
+				}
 
-									}
-								l142:
-									add(ruleValue, position141)
+				add(ruleConcatItem, position554)
+			}
+			return true
+		l553:
+			position, tokenIndex = position553, tokenIndex553
+			return false
+		},
+		/* 21 ListValue <- <(('[' WhiteSpacing Action32 (ListItem (WhiteSpacing ',' WhiteSpacing ListItem)*)? WhiteSpacing ']') / (Action33 ListItem (',' ListItem)+))> */
+		nil,
+		/* 22 ListItem <- <(('"' <QuotedValue> '"' Action34) / (<StringValue> Action35))> */
+		func() bool {
+			position563, tokenIndex563 := position, tokenIndex
+			{
+				position564 := position
+				{
+					position565, tokenIndex565 := position, tokenIndex
+					if buffer[position] != '"' {
+						goto l566
+					}
+					position++
+					{
+						position567 := position
+						if !_rules[ruleQuotedValue]() {
+							goto l566
+						}
+						add(rulePegText, position567)
+					}
+					if buffer[position] != '"' {
+						goto l566
+					}
+					position++
+					{
+						add(ruleAction34, position)
+					}
+					goto l565
+				l566:
+					position, tokenIndex = position565, tokenIndex565
+					{
+						position569 := position
+						if !_rules[ruleStringValue]() {
+							goto l563
+						}
+						add(rulePegText, position569)
+					}
+					{
+						add(ruleAction35, position)
+					}
+				}
+			l565:
+				add(ruleListItem, position564)
+			}
+			return true
+		l563:
+			position, tokenIndex = position563, tokenIndex563
+			return false
+		},
+		/* 23 MapValue <- <('{' WhiteSpacing Action36 (MapEntry (WhiteSpacing ',' WhiteSpacing MapEntry)*)? WhiteSpacing '}')> */
+		nil,
+		/* 24 MapEntry <- <(<((&('/') '/') | (&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> Action37 WhiteSpacing ':' WhiteSpacing (('"' <QuotedValue> '"' Action38) / (<StringValue> Action39)))> */
+		func() bool {
+			position572, tokenIndex572 := position, tokenIndex
+			{
+				position573 := position
+				{
+					position574 := position
+					{
+						switch buffer[position] {
+						case '/':
+							if buffer[position] != '/' {
+								goto l572
+							}
+							position++
+						case '_':
+							if buffer[position] != '_' {
+								goto l572
+							}
+							position++
+						case '.':
+							if buffer[position] != '.' {
+								goto l572
+							}
+							position++
+						case '-':
+							if buffer[position] != '-' {
+								goto l572
+							}
+							position++
+						case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+							if c := buffer[position]; c < '0' || c > '9' {
+								goto l572
+							}
+							position++
+						case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+							if c := buffer[position]; c < 'A' || c > 'Z' {
+								goto l572
+							}
+							position++
+						default:
+							if c := buffer[position]; c < 'a' || c > 'z' {
+								goto l572
+							}
+							position++
+						}
+					}
+
+				l575:
+					{
+						position576, tokenIndex576 := position, tokenIndex
+						{
+							switch buffer[position] {
+							case '/':
+								if buffer[position] != '/' {
+									goto l576
 								}
-								if !_rules[ruleWhiteSpacing]() {
-									goto l75
+								position++
+							case '_':
+								if buffer[position] != '_' {
+									goto l576
+								}
+								position++
+							case '.':
+								if buffer[position] != '.' {
+									goto l576
+								}
+								position++
+							case '-':
+								if buffer[position] != '-' {
+									goto l576
 								}
-								add(ruleParam, position138)
+								position++
+							case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+								if c := buffer[position]; c < '0' || c > '9' {
+									goto l576
+								}
+								position++
+							case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+								if c := buffer[position]; c < 'A' || c > 'Z' {
+									goto l576
+								}
+								position++
+							default:
+								if c := buffer[position]; c < 'a' || c > 'z' {
+									goto l576
+								}
+								position++
 							}
-							goto l74
-						l75:
-							position, tokenIndex = position75, tokenIndex75
 						}
-						add(ruleParams, position73)
+
+						goto l575
+					l576:
+						position, tokenIndex = position576, tokenIndex576
 					}
-					goto l72
-				l71:
-					position, tokenIndex = position71, tokenIndex71
+					add(rulePegText, position574)
+				}
+				{
+					add(ruleAction37, position)
+				}
+				if !_rules[ruleWhiteSpacing]() {
+					goto l572
+				}
+				if buffer[position] != ':' {
+					goto l572
+				}
+				position++
+				if !_rules[ruleWhiteSpacing]() {
+					goto l572
 				}
-			l72:
 				{
-					add(ruleAction3, position)
+					position580, tokenIndex580 := position, tokenIndex
+					if buffer[position] != '"' {
+						goto l581
+					}
+					position++
+					{
+						position582 := position
+						if !_rules[ruleQuotedValue]() {
+							goto l581
+						}
+						add(rulePegText, position582)
+					}
+					if buffer[position] != '"' {
+						goto l581
+					}
+					position++
+					{
+						add(ruleAction38, position)
+					}
+					goto l580
+				l581:
+					position, tokenIndex = position580, tokenIndex580
+					{
+						position584 := position
+						if !_rules[ruleStringValue]() {
+							goto l572
+						}
+						add(rulePegText, position584)
+					}
+					{
+						add(ruleAction39, position)
+					}
 				}
-				add(ruleExpr, position49)
+			l580:
+				add(ruleMapEntry, position573)
 			}
 			return true
-		l48:
-			position, tokenIndex = position48, tokenIndex48
+		l572:
+			position, tokenIndex = position572, tokenIndex572
 			return false
 		},
-		/* 6 Params <- <Param+> */
+		/* 25 BoolValue <- <(<(('t' 'r' 'u' 'e') / ('f' 'a' 'l' 's' 'e'))> !((&('?') '?') | (&('*') '*') | (&('/') '/') | (&(':') ':') | (&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z])) Action40)> */
+		nil,
+		/* 26 FloatValue <- <(<([0-9]+ '.' [0-9]+)> !((&('?') '?') | (&('*') '*') | (&('/') '/') | (&(':') ':') | (&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z])) Action41)> */
+		nil,
+		/* 27 Ipv6CidrValue <- <(<(Ipv6Address '/' [0-9]+)> !((&('?') '?') | (&('*') '*') | (&('/') '/') | (&(':') ':') | (&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z])) Action42)> */
+		nil,
+		/* 28 Ipv6Value <- <(<Ipv6Address> !((&('?') '?') | (&('*') '*') | (&('/') '/') | (&(':') ':') | (&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z])) Action43)> */
 		nil,
-		/* 7 Param <- <(<Identifier> Action4 Equal Value WhiteSpacing)> */
+		/* 29 Ipv6Address <- <(((&('A' | 'B' | 'C' | 'D' | 'E' | 'F') [A-F]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f') [a-f]) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]))* ':' ((&('A' | 'B' | 'C' | 'D' | 'E' | 'F') [A-F]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f') [a-f]) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]))* ':' ((&('.') '.') | (&(':') ':') | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F') [A-F]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f') [a-f]) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]))*)> */
+		func() bool {
+			position590, tokenIndex590 := position, tokenIndex
+			{
+				position591 := position
+			l592:
+				{
+					position593, tokenIndex593 := position, tokenIndex
+					{
+						switch buffer[position] {
+						case 'A', 'B', 'C', 'D', 'E', 'F':
+							if c := buffer[position]; c < 'A' || c > 'F' {
+								goto l593
+							}
+							position++
+						case 'a', 'b', 'c', 'd', 'e', 'f':
+							if c := buffer[position]; c < 'a' || c > 'f' {
+								goto l593
+							}
+							position++
+						default:
+							if c := buffer[position]; c < '0' || c > '9' {
+								goto l593
+							}
+							position++
+						}
+					}
+
+					goto l592
+				l593:
+					position, tokenIndex = position593, tokenIndex593
+				}
+				if buffer[position] != ':' {
+					goto l590
+				}
+				position++
+			l595:
+				{
+					position596, tokenIndex596 := position, tokenIndex
+					{
+						switch buffer[position] {
+						case 'A', 'B', 'C', 'D', 'E', 'F':
+							if c := buffer[position]; c < 'A' || c > 'F' {
+								goto l596
+							}
+							position++
+						case 'a', 'b', 'c', 'd', 'e', 'f':
+							if c := buffer[position]; c < 'a' || c > 'f' {
+								goto l596
+							}
+							position++
+						default:
+							if c := buffer[position]; c < '0' || c > '9' {
+								goto l596
+							}
+							position++
+						}
+					}
+
+					goto l595
+				l596:
+					position, tokenIndex = position596, tokenIndex596
+				}
+				if buffer[position] != ':' {
+					goto l590
+				}
+				position++
+			l598:
+				{
+					position599, tokenIndex599 := position, tokenIndex
+					{
+						switch buffer[position] {
+						case '.':
+							if buffer[position] != '.' {
+								goto l599
+							}
+							position++
+						case ':':
+							if buffer[position] != ':' {
+								goto l599
+							}
+							position++
+						case 'A', 'B', 'C', 'D', 'E', 'F':
+							if c := buffer[position]; c < 'A' || c > 'F' {
+								goto l599
+							}
+							position++
+						case 'a', 'b', 'c', 'd', 'e', 'f':
+							if c := buffer[position]; c < 'a' || c > 'f' {
+								goto l599
+							}
+							position++
+						default:
+							if c := buffer[position]; c < '0' || c > '9' {
+								goto l599
+							}
+							position++
+						}
+					}
+
+					goto l598
+				l599:
+					position, tokenIndex = position599, tokenIndex599
+				}
+				add(ruleIpv6Address, position591)
+			}
+			return true
+		l590:
+			position, tokenIndex = position590, tokenIndex590
+			return false
+		},
+		/* 30 GlobValue <- <(<(((&('/') '/') | (&(':') ':') | (&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))* ('*' / '?') ((&('?') '?') | (&('*') '*') | (&('/') '/') | (&(':') ':') | (&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))*)> Action44)> */
 		nil,
-		/* 8 Identifier <- <((&('.') '.') | (&('_') '_') | (&('-') '-') | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> */
+		/* 31 StringValue <- <((&('?') '?') | (&('*') '*') | (&('/') '/') | (&(':') ':') | (&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> */
 		func() bool {
-			position203, tokenIndex203 := position, tokenIndex
+			position602, tokenIndex602 := position, tokenIndex
 			{
-				position204 := position
+				position603 := position
 				{
 					switch buffer[position] {
-					case '.':
-						if buffer[position] != rune('.') {
-							goto l203
+					case '?':
+						if buffer[position] != '?' {
+							goto l602
+						}
+						position++
+					case '*':
+						if buffer[position] != '*' {
+							goto l602
+						}
+						position++
+					case '/':
+						if buffer[position] != '/' {
+							goto l602
+						}
+						position++
+					case ':':
+						if buffer[position] != ':' {
+							goto l602
 						}
 						position++
-						break
 					case '_':
-						if buffer[position] != rune('_') {
-							goto l203
+						if buffer[position] != '_' {
+							goto l602
+						}
+						position++
+					case '.':
+						if buffer[position] != '.' {
+							goto l602
 						}
 						position++
-						break
 					case '-':
-						if buffer[position] != rune('-') {
-							goto l203
+						if buffer[position] != '-' {
+							goto l602
+						}
+						position++
+					case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+						if c := buffer[position]; c < '0' || c > '9' {
+							goto l602
 						}
 						position++
-						break
 					case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
-						if c := buffer[position]; c < rune('A') || c > rune('Z') {
-							goto l203
+						if c := buffer[position]; c < 'A' || c > 'Z' {
+							goto l602
 						}
 						position++
-						break
 					default:
-						if c := buffer[position]; c < rune('a') || c > rune('z') {
-							goto l203
+						if c := buffer[position]; c < 'a' || c > 'z' {
+							goto l602
 						}
 						position++
-						break
 					}
 				}
 
-			l205:
+			l604:
 				{
-					position206, tokenIndex206 := position, tokenIndex
+					position605, tokenIndex605 := position, tokenIndex
 					{
 						switch buffer[position] {
-						case '.':
-							if buffer[position] != rune('.') {
-								goto l206
+						case '?':
+							if buffer[position] != '?' {
+								goto l605
+							}
+							position++
+						case '*':
+							if buffer[position] != '*' {
+								goto l605
+							}
+							position++
+						case '/':
+							if buffer[position] != '/' {
+								goto l605
+							}
+							position++
+						case ':':
+							if buffer[position] != ':' {
+								goto l605
 							}
 							position++
-							break
 						case '_':
-							if buffer[position] != rune('_') {
-								goto l206
+							if buffer[position] != '_' {
+								goto l605
+							}
+							position++
+						case '.':
+							if buffer[position] != '.' {
+								goto l605
 							}
 							position++
-							break
 						case '-':
-							if buffer[position] != rune('-') {
-								goto l206
+							if buffer[position] != '-' {
+								goto l605
+							}
+							position++
+						case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+							if c := buffer[position]; c < '0' || c > '9' {
+								goto l605
 							}
 							position++
-							break
 						case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
-							if c := buffer[position]; c < rune('A') || c > rune('Z') {
-								goto l206
+							if c := buffer[position]; c < 'A' || c > 'Z' {
+								goto l605
 							}
 							position++
-							break
 						default:
-							if c := buffer[position]; c < rune('a') || c > rune('z') {
-								goto l206
+							if c := buffer[position]; c < 'a' || c > 'z' {
+								goto l605
 							}
 							position++
-							break
 						}
 					}
 
-					goto l205
-				l206:
-					position, tokenIndex = position206, tokenIndex206
+					goto l604
+				l605:
+					position, tokenIndex = position605, tokenIndex605
 				}
-				add(ruleIdentifier, position204)
+				add(ruleStringValue, position603)
 			}
 			return true
-		l203:
-			position, tokenIndex = position203, tokenIndex203
+		l602:
+			position, tokenIndex = position602, tokenIndex602
 			return false
 		},
-		/* 9 Value <- <((<CidrValue> Action8) / (<IpValue> Action9) / (<IntRangeValue> Action10) / (<IntValue> Action11) / ((&('$') (RefValue Action7)) | (&('@') (AliasValue Action6)) | (&('{') (HoleValue Action5)) | (&('-' | '.' | '/' | '0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9' | ':' | 'A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z' | '_' | 'a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') (<StringValue> Action12))))> */
+		/* 32 CidrValue <- <([0-9]+ . [0-9]+ . [0-9]+ . [0-9]+ '/' [0-9]+)> */
+		nil,
+		/* 33 IpValue <- <([0-9]+ . [0-9]+ . [0-9]+ . [0-9]+)> */
+		nil,
+		/* 34 IntValue <- <('-'? [0-9]+)> */
+		nil,
+		/* 35 IntRangeValue <- <([0-9]+ '-' [0-9]+)> */
 		nil,
-		/* 10 StringValue <- <((&('/') '/') | (&(':') ':') | (&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> */
+		/* 36 RefValue <- <('$' <Identifier>)> */
 		nil,
-		/* 11 CidrValue <- <([0-9]+ . [0-9]+ . [0-9]+ . [0-9]+ '/' [0-9]+)> */
+		/* 37 AliasValue <- <('@' <Identifier>)> */
 		nil,
-		/* 12 IpValue <- <([0-9]+ . [0-9]+ . [0-9]+ . [0-9]+)> */
+		/* 38 HoleValue <- <('{' WhiteSpacing <Identifier> WhiteSpacing '}')> */
 		nil,
-		/* 13 IntValue <- <[0-9]+> */
+		/* 39 TypedHoleValue <- <('{' WhiteSpacing <Identifier> Action45 MustWhiteSpacing ('a' 's') MustWhiteSpacing <HoleType> Action46 WhiteSpacing '}')> */
 		nil,
-		/* 14 IntRangeValue <- <([0-9]+ '-' [0-9]+)> */
+		/* 40 HoleType <- <(('i' 'n' 't') / ((&('i') ('i' 'p')) | (&('c') ('c' 'i' 'd' 'r')) | (&('b') ('b' 'o' 'o' 'l')) | (&('f') ('f' 'l' 'o' 'a' 't')) | (&('s') ('s' 't' 'r' 'i' 'n' 'g'))))> */
 		nil,
-		/* 15 RefValue <- <('$' <Identifier>)> */
+		/* 41 QuotedValue <- <(('\\' .) / (!'"' !EndOfLine .))*> */
+		func() bool {
+			{
+				position618 := position
+			l619:
+				{
+					position620, tokenIndex620 := position, tokenIndex
+					{
+						position621, tokenIndex621 := position, tokenIndex
+						if buffer[position] != '\\' {
+							goto l622
+						}
+						position++
+						if !matchDot() {
+							goto l622
+						}
+						goto l621
+					l622:
+						position, tokenIndex = position621, tokenIndex621
+						{
+							position623, tokenIndex623 := position, tokenIndex
+							if buffer[position] != '"' {
+								goto l623
+							}
+							position++
+							goto l620
+						l623:
+							position, tokenIndex = position623, tokenIndex623
+						}
+						{
+							position624, tokenIndex624 := position, tokenIndex
+							if !_rules[ruleEndOfLine]() {
+								goto l624
+							}
+							goto l620
+						l624:
+							position, tokenIndex = position624, tokenIndex624
+						}
+						if !matchDot() {
+							goto l620
+						}
+					}
+				l621:
+					goto l619
+				l620:
+					position, tokenIndex = position620, tokenIndex620
+				}
+				add(ruleQuotedValue, position618)
+			}
+			return true
+		},
+		/* 42 FileValue <- <('@' <FilePath>)> */
 		nil,
-		/* 16 AliasValue <- <('@' <Identifier>)> */
+		/* 43 FilePath <- <(((&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))* '/' ((&('/') '/') | (&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+)> */
 		nil,
-		/* 17 HoleValue <- <('{' WhiteSpacing <Identifier> WhiteSpacing '}')> */
+		/* 44 Comment <- <(<(('#' (!EndOfLine .)*) / ('/' '/' (!EndOfLine .)*))> Action47)> */
 		nil,
-		/* 18 Comment <- <(('#' (!EndOfLine .)*) / ('/' '/' (!EndOfLine .)* Action13))> */
+		/* 45 TrailingComment <- <(WhiteSpacing ('#' / ('/' '/')) WhiteSpacing <(!EndOfLine .)*> Action48)> */
 		nil,
-		/* 19 Spacing <- <Space*> */
+		/* 46 Spacing <- <Space*> */
 		func() bool {
 			{
-				position220 := position
-			l221:
+				position630 := position
+			l631:
 				{
-					position222, tokenIndex222 := position, tokenIndex
+					position632, tokenIndex632 := position, tokenIndex
 					{
-						position223 := position
+						position633 := position
 						{
-							position224, tokenIndex224 := position, tokenIndex
+							position634, tokenIndex634 := position, tokenIndex
 							if !_rules[ruleWhitespace]() {
-								goto l225
+								goto l635
 							}
-							goto l224
-						l225:
-							position, tokenIndex = position224, tokenIndex224
+							goto l634
+						l635:
+							position, tokenIndex = position634, tokenIndex634
 							if !_rules[ruleEndOfLine]() {
-								goto l222
+								goto l632
 							}
 						}
-					l224:
-						add(ruleSpace, position223)
+					l634:
+						add(ruleSpace, position633)
 					}
-					goto l221
-				l222:
-					position, tokenIndex = position222, tokenIndex222
+					goto l631
+				l632:
+					position, tokenIndex = position632, tokenIndex632
 				}
-				add(ruleSpacing, position220)
+				add(ruleSpacing, position630)
 			}
 			return true
 		},
-		/* 20 WhiteSpacing <- <Whitespace*> */
+		/* 47 WhiteSpacing <- <Whitespace*> */
 		func() bool {
 			{
-				position227 := position
-			l228:
+				position637 := position
+			l638:
 				{
-					position229, tokenIndex229 := position, tokenIndex
+					position639, tokenIndex639 := position, tokenIndex
 					if !_rules[ruleWhitespace]() {
-						goto l229
+						goto l639
 					}
-					goto l228
-				l229:
-					position, tokenIndex = position229, tokenIndex229
+					goto l638
+				l639:
+					position, tokenIndex = position639, tokenIndex639
 				}
-				add(ruleWhiteSpacing, position227)
+				add(ruleWhiteSpacing, position637)
 			}
 			return true
 		},
-		/* 21 MustWhiteSpacing <- <Whitespace+> */
+		/* 48 MustWhiteSpacing <- <Whitespace+> */
 		func() bool {
-			position230, tokenIndex230 := position, tokenIndex
+			position640, tokenIndex640 := position, tokenIndex
 			{
-				position231 := position
+				position641 := position
 				if !_rules[ruleWhitespace]() {
-					goto l230
+					goto l640
 				}
-			l232:
+			l642:
 				{
-					position233, tokenIndex233 := position, tokenIndex
+					position643, tokenIndex643 := position, tokenIndex
 					if !_rules[ruleWhitespace]() {
-						goto l233
+						goto l643
 					}
-					goto l232
-				l233:
-					position, tokenIndex = position233, tokenIndex233
+					goto l642
+				l643:
+					position, tokenIndex = position643, tokenIndex643
 				}
-				add(ruleMustWhiteSpacing, position231)
+				add(ruleMustWhiteSpacing, position641)
 			}
 			return true
-		l230:
-			position, tokenIndex = position230, tokenIndex230
+		l640:
+			position, tokenIndex = position640, tokenIndex640
 			return false
 		},
-		/* 22 Equal <- <(Spacing '=' Spacing)> */
+		/* 49 Equal <- <(Spacing '=' Spacing)> */
 		func() bool {
-			position234, tokenIndex234 := position, tokenIndex
+			position644, tokenIndex644 := position, tokenIndex
 			{
-				position235 := position
+				position645 := position
 				if !_rules[ruleSpacing]() {
-					goto l234
+					goto l644
 				}
-				if buffer[position] != rune('=') {
-					goto l234
+				if buffer[position] != '=' {
+					goto l644
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l234
+					goto l644
 				}
-				add(ruleEqual, position235)
+				add(ruleEqual, position645)
 			}
 			return true
-		l234:
-			position, tokenIndex = position234, tokenIndex234
+		l644:
+			position, tokenIndex = position644, tokenIndex644
 			return false
 		},
-		/* 23 Space <- <(Whitespace / EndOfLine)> */
+		/* 50 Space <- <(Whitespace / EndOfLine)> */
 		nil,
-		/* 24 Whitespace <- <(' ' / '\t')> */
+		/* 51 Whitespace <- <((&('\\') LineContinuation) | (&('\t') '\t') | (&(' ') ' '))> */
 		func() bool {
-			position237, tokenIndex237 := position, tokenIndex
+			position647, tokenIndex647 := position, tokenIndex
 			{
-				position238 := position
+				position648 := position
 				{
-					position239, tokenIndex239 := position, tokenIndex
-					if buffer[position] != rune(' ') {
-						goto l240
-					}
-					position++
-					goto l239
-				l240:
-					position, tokenIndex = position239, tokenIndex239
-					if buffer[position] != rune('\t') {
-						goto l237
+					switch buffer[position] {
+					case '\\':
+						{
+							position650 := position
+							if buffer[position] != '\\' {
+								goto l647
+							}
+							position++
+							if !_rules[ruleEndOfLine]() {
+								goto l647
+							}
+							add(ruleLineContinuation, position650)
+						}
+					case '\t':
+						if buffer[position] != '\t' {
+							goto l647
+						}
+						position++
+					default:
+						if buffer[position] != ' ' {
+							goto l647
+						}
+						position++
 					}
-					position++
 				}
-			l239:
-				add(ruleWhitespace, position238)
+
+				add(ruleWhitespace, position648)
 			}
 			return true
-		l237:
-			position, tokenIndex = position237, tokenIndex237
+		l647:
+			position, tokenIndex = position647, tokenIndex647
 			return false
 		},
-		/* 25 EndOfLine <- <(('\r' '\n') / '\n' / '\r')> */
+		/* 52 LineContinuation <- <('\\' EndOfLine)> */
+		nil,
+		/* 53 EndOfLine <- <(('\r' '\n') / '\n' / '\r')> */
 		func() bool {
-			position241, tokenIndex241 := position, tokenIndex
+			position652, tokenIndex652 := position, tokenIndex
 			{
-				position242 := position
+				position653 := position
 				{
-					position243, tokenIndex243 := position, tokenIndex
-					if buffer[position] != rune('\r') {
-						goto l244
+					position654, tokenIndex654 := position, tokenIndex
+					if buffer[position] != '\r' {
+						goto l655
 					}
 					position++
-					if buffer[position] != rune('\n') {
-						goto l244
+					if buffer[position] != '\n' {
+						goto l655
 					}
 					position++
-					goto l243
-				l244:
-					position, tokenIndex = position243, tokenIndex243
-					if buffer[position] != rune('\n') {
-						goto l245
+					goto l654
+				l655:
+					position, tokenIndex = position654, tokenIndex654
+					if buffer[position] != '\n' {
+						goto l656
 					}
 					position++
-					goto l243
-				l245:
-					position, tokenIndex = position243, tokenIndex243
-					if buffer[position] != rune('\r') {
-						goto l241
+					goto l654
+				l656:
+					position, tokenIndex = position654, tokenIndex654
+					if buffer[position] != '\r' {
+						goto l652
 					}
 					position++
 				}
-			l243:
-				add(ruleEndOfLine, position242)
+			l654:
+				add(ruleEndOfLine, position653)
 			}
 			return true
-		l241:
-			position, tokenIndex = position241, tokenIndex241
+		l652:
+			position, tokenIndex = position652, tokenIndex652
 			return false
 		},
-		/* 26 EndOfFile <- <!.> */
+		/* 54 EndOfFile <- <!.> */
+		nil,
+		/* 56 Action0 <- <{ p.setSource(_buffer) }> */
+		nil,
+		nil,
+		/* 58 Action1 <- <{ p.addStatementSpacing(text) }> */
+		nil,
+		/* 59 Action2 <- <{ p.addDeclarationIdentifier(text, begin) }> */
 		nil,
+		/* 60 Action3 <- <{ p.addRepeatCount(text) }> */
 		nil,
-		/* 29 Action0 <- <{ p.addDeclarationIdentifier(text) }> */
+		/* 61 Action4 <- <{ p.addRepeatHole(text) }> */
 		nil,
-		/* 30 Action1 <- <{ p.addAction(text) }> */
+		/* 62 Action5 <- <{ p.addAction(text, begin) }> */
 		nil,
-		/* 31 Action2 <- <{ p.addEntity(text) }> */
+		/* 63 Action6 <- <{ p.addEntity(text) }> */
 		nil,
-		/* 32 Action3 <- <{ p.LineDone() }> */
+		/* 64 Action7 <- <{ p.LineDone() }> */
 		nil,
-		/* 33 Action4 <- <{ p.addParamKey(text) }> */
+		/* 65 Action8 <- <{ p.addParamSetIdentifier(text, begin) }> */
 		nil,
-		/* 34 Action5 <- <{  p.addParamHoleValue(text) }> */
+		/* 66 Action9 <- <{ p.LineDone() }> */
 		nil,
-		/* 35 Action6 <- <{  p.addParamAliasValue(text) }> */
+		/* 67 Action10 <- <{ p.addParamSplat(text) }> */
 		nil,
-		/* 36 Action7 <- <{  p.addParamRefValue(text) }> */
+		/* 68 Action11 <- <{ p.addParamKey(text, begin) }> */
 		nil,
-		/* 37 Action8 <- <{ p.addParamCidrValue(text) }> */
+		/* 69 Action12 <- <{ p.addParamOptional() }> */
 		nil,
-		/* 38 Action9 <- <{ p.addParamIpValue(text) }> */
+		/* 70 Action13 <- <{  p.addParamHoleValue(text) }> */
 		nil,
-		/* 39 Action10 <- <{ p.addParamValue(text) }> */
+		/* 71 Action14 <- <{ p.addParamFileValue(text) }> */
 		nil,
-		/* 40 Action11 <- <{ p.addParamIntValue(text) }> */
+		/* 72 Action15 <- <{  p.addParamAliasValue(text) }> */
 		nil,
-		/* 41 Action12 <- <{ p.addParamValue(text) }> */
+		/* 73 Action16 <- <{  p.addParamRefValue(text) }> */
 		nil,
-		/* 42 Action13 <- <{ p.LineDone() }> */
+		/* 74 Action17 <- <{ p.addParamCidrValue(text) }> */
+		nil,
+		/* 75 Action18 <- <{ p.addParamIpValue(text) }> */
+		nil,
+		/* 76 Action19 <- <{ p.addParamValue(text) }> */
+		nil,
+		/* 77 Action20 <- <{ p.addParamIntValue(text) }> */
+		nil,
+		/* 78 Action21 <- <{ p.addParamValue(text) }> */
+		nil,
+		/* 79 Action22 <- <{ p.addParamQuotedValue(text) }> */
+		nil,
+		/* 80 Action23 <- <{ p.addParamFuncValue(text) }> */
+		nil,
+		/* 81 Action24 <- <{ p.addParamFuncHoleArg(text) }> */
+		nil,
+		/* 82 Action25 <- <{ p.addParamFuncQuotedArg(text) }> */
+		nil,
+		/* 83 Action26 <- <{ p.addParamFuncArg(text) }> */
+		nil,
+		/* 84 Action27 <- <{ p.addParamValue(text) }> */
+		nil,
+		/* 85 Action28 <- <{ p.addParamConcatValue() }> */
+		nil,
+		/* 86 Action29 <- <{ p.addParamConcatHole(text) }> */
+		nil,
+		/* 87 Action30 <- <{ p.addParamConcatQuotedItem(text) }> */
+		nil,
+		/* 88 Action31 <- <{ p.addParamConcatItem(text) }> */
+		nil,
+		/* 89 Action32 <- <{ p.addParamListValue() }> */
+		nil,
+		/* 90 Action33 <- <{ p.addParamListValue() }> */
+		nil,
+		/* 91 Action34 <- <{ p.addParamListQuotedItem(text) }> */
+		nil,
+		/* 92 Action35 <- <{ p.addParamListItem(text) }> */
+		nil,
+		/* 93 Action36 <- <{ p.addParamMapValue() }> */
+		nil,
+		/* 94 Action37 <- <{ p.addParamMapKey(text) }> */
+		nil,
+		/* 95 Action38 <- <{ p.addParamMapQuotedItem(text) }> */
+		nil,
+		/* 96 Action39 <- <{ p.addParamMapItem(text) }> */
+		nil,
+		/* 97 Action40 <- <{ p.addParamBoolValue(text) }> */
+		nil,
+		/* 98 Action41 <- <{ p.addParamFloatValue(text) }> */
+		nil,
+		/* 99 Action42 <- <{ p.addParamIpv6CidrValue(text) }> */
+		nil,
+		/* 100 Action43 <- <{ p.addParamIpv6Value(text) }> */
+		nil,
+		/* 101 Action44 <- <{ p.addParamValue(text) }> */
+		nil,
+		/* 102 Action45 <- <{ p.addParamHoleValue(text) }> */
+		nil,
+		/* 103 Action46 <- <{ p.addParamHoleType(text) }> */
+		nil,
+		/* 104 Action47 <- <{ p.addCommentLine(text) }> */
+		nil,
+		/* 105 Action48 <- <{ p.addStatementComment(text) }> */
 		nil,
 	}
 	p.rules = _rules
+	return nil
 }
@@ -3,10 +3,13 @@ package ast
 import (
 	"fmt"
 	"net"
+	"regexp"
 	"strconv"
+	"strings"
+	"unicode"
 )
 
-func (a *AST) addAction(text string) {
+func (a *AST) addAction(text string, offset int) {
 	cmd := &CommandNode{Action: text}
 	decl := a.currentDeclaration()
 	if decl != nil {
@@ -14,7 +17,7 @@ func (a *AST) addAction(text string) {
 	} else {
 		node := a.currentCommand()
 		if node == nil {
-			a.addStatement(cmd)
+			a.addStatement(cmd, offset)
 		} else {
 			node.Action = text
 		}
@@ -26,8 +29,20 @@ func (a *AST) addEntity(text string) {
 	node.Entity = text
 }
 
-func (a *AST) addDeclarationIdentifier(text string) {
-	a.addStatement(&DeclarationNode{Ident: text})
+func (a *AST) addDeclarationIdentifier(text string, offset int) {
+	a.addStatement(&DeclarationNode{Ident: text}, offset)
+}
+
+func (a *AST) addRepeatCount(text string) {
+	num, err := strconv.Atoi(text)
+	if err != nil {
+		panic(fmt.Sprintf("cannot convert '%s' to int", text))
+	}
+	a.currentRepeat = &Repeat{Count: num}
+}
+
+func (a *AST) addRepeatHole(text string) {
+	a.currentRepeat = &Repeat{Hole: text}
 }
 
 func (a *AST) LineDone() {
@@ -35,15 +50,120 @@ func (a *AST) LineDone() {
 	a.currentKey = ""
 }
 
-func (a *AST) addParamKey(text string) {
+func (a *AST) addStatementComment(text string) {
+	if len(a.Statements) > 0 {
+		a.Statements[len(a.Statements)-1].Comment = strings.TrimSpace(text)
+	}
+}
+
+// addCommentLine keeps the comment lines, to be set on the next statement
+func (a *AST) addCommentLine(text string) {
+	a.Comments = append(a.Comments, strings.TrimRightFunc(text, unicode.IsSpace))
+	a.LineDone()
+}
+
+// addStatementSpacing keeps a blank line following a statement or a comment line as an empty comment line
+func (a *AST) addStatementSpacing(text string) {
+	text = strings.NewReplacer("\\\r\n", "", "\\\n", "", "\\\r", "", "\r\n", "\n", "\r", "\n").Replace(text)
+	if strings.Count(text, "\n") > 1 {
+		a.Comments = append(a.Comments, "")
+	}
+}
+
+func (a *AST) addParamSetIdentifier(text string, offset int) {
+	set := &CommandNode{}
+	if a.paramSets == nil {
+		a.paramSets = make(map[string]*CommandNode)
+	}
+	a.paramSets[text] = set
+	a.addStatement(&ParamSetNode{Ident: text, Params: set}, offset)
+}
+
+func (a *AST) addParamKey(text string, offset int) {
+	node := a.currentCommand()
+	initParams(node)
+	node.removeParam(text)
+	if node.ParamPositions == nil {
+		node.ParamPositions = make(map[string]Position)
+	}
+	node.ParamPositions[text] = a.position(offset)
+	a.currentKey = text
+}
+
+// addParamSplat copies the params of a declared set into the current statement, overriding the params given before
+func (a *AST) addParamSplat(text string) {
+	set, ok := a.paramSets[text]
+	if !ok {
+		if a.buildErr == nil {
+			a.buildErr = fmt.Errorf("undeclared param set '%s'", text)
+		}
+		return
+	}
 	node := a.currentCommand()
+	initParams(node)
+	copied := set.clone().(*CommandNode)
+	for k, v := range copied.Params {
+		node.removeParam(k)
+		node.Params[k] = v
+	}
+	for k, v := range copied.Refs {
+		node.removeParam(k)
+		node.Refs[k] = v
+	}
+	for k, v := range copied.Aliases {
+		node.removeParam(k)
+		node.Aliases[k] = v
+	}
+	for k, v := range copied.Holes {
+		node.removeParam(k)
+		node.Holes[k] = v
+	}
+	for k, v := range copied.HoleTypes {
+		if node.HoleTypes == nil {
+			node.HoleTypes = make(map[string]string)
+		}
+		node.HoleTypes[k] = v
+	}
+	for k, v := range copied.Optionals {
+		if node.Optionals == nil {
+			node.Optionals = make(map[string]bool)
+		}
+		node.Optionals[k] = v
+	}
+	for k, v := range copied.ParamPositions {
+		if node.ParamPositions == nil {
+			node.ParamPositions = make(map[string]Position)
+		}
+		node.ParamPositions[k] = v
+	}
+}
+
+func initParams(node *CommandNode) {
 	if node.Params == nil {
 		node.Refs = make(map[string]string)
 		node.Params = make(map[string]interface{})
 		node.Aliases = make(map[string]string)
 		node.Holes = make(map[string]string)
 	}
-	a.currentKey = text
+}
+
+// removeParam removes the param of the key whatever its kind, so that the last value given for a key wins
+func (n *CommandNode) removeParam(key string) {
+	delete(n.Params, key)
+	delete(n.Refs, key)
+	delete(n.Aliases, key)
+	delete(n.Holes, key)
+	delete(n.HoleTypes, key)
+	delete(n.Optionals, key)
+	delete(n.ParamPositions, key)
+}
+
+func (a *AST) addParamOptional() {
+	node := a.currentCommand()
+	if node.Optionals == nil {
+		node.Optionals = make(map[string]bool)
+	}
+	node.Optionals[a.currentKey] = true
 }
 
 func (a *AST) addParamValue(text string) {
@@ -51,6 +171,128 @@ func (a *AST) addParamValue(text string) {
 	node.Params[a.currentKey] = text
 }
 
+func (a *AST) addParamQuotedValue(text string) {
+	node := a.currentCommand()
+	if unquoted, err := strconv.Unquote(`"` + text + `"`); err == nil {
+		text = unquoted
+	}
+	node.Params[a.currentKey] = text
+}
+
+func (a *AST) addParamFileValue(text string) {
+	node := a.currentCommand()
+	node.Params[a.currentKey] = FileValue(text)
+}
+
+func (a *AST) addParamListValue() {
+	node := a.currentCommand()
+	node.Params[a.currentKey] = []interface{}{}
+}
+
+func (a *AST) addParamListItem(text string) {
+	node := a.currentCommand()
+	node.Params[a.currentKey] = append(node.Params[a.currentKey].([]interface{}), itemValue(text))
+}
+
+func (a *AST) addParamListQuotedItem(text string) {
+	node := a.currentCommand()
+	if unquoted, err := strconv.Unquote(`"` + text + `"`); err == nil {
+		text = unquoted
+	}
+	node.Params[a.currentKey] = append(node.Params[a.currentKey].([]interface{}), text)
+}
+
+func (a *AST) addParamFuncValue(text string) {
+	node := a.currentCommand()
+	node.Params[a.currentKey] = &FuncValue{Name: text}
+}
+
+func (a *AST) addParamFuncHoleArg(text string) {
+	a.addFuncArg(Hole(text))
+}
+
+func (a *AST) addParamFuncQuotedArg(text string) {
+	if unquoted, err := strconv.Unquote(`"` + text + `"`); err == nil {
+		text = unquoted
+	}
+	a.addFuncArg(text)
+}
+
+func (a *AST) addParamFuncArg(text string) {
+	a.addFuncArg(itemValue(text))
+}
+
+func (a *AST) addFuncArg(arg interface{}) {
+	node := a.currentCommand()
+	fn := node.Params[a.currentKey].(*FuncValue)
+	fn.Args = append(fn.Args, arg)
+}
+
+func (a *AST) addParamConcatValue() {
+	node := a.currentCommand()
+	node.Params[a.currentKey] = ConcatenationValue{}
+}
+
+func (a *AST) addParamConcatHole(text string) {
+	node := a.currentCommand()
+	node.Params[a.currentKey] = append(node.Params[a.currentKey].(ConcatenationValue), Hole(text))
+}
+
+func (a *AST) addParamConcatQuotedItem(text string) {
+	if unquoted, err := strconv.Unquote(`"` + text + `"`); err == nil {
+		text = unquoted
+	}
+	a.addParamConcatItem(text)
+}
+
+func (a *AST) addParamConcatItem(text string) {
+	node := a.currentCommand()
+	node.Params[a.currentKey] = append(node.Params[a.currentKey].(ConcatenationValue), text)
+}
+
+func (a *AST) addParamMapValue() {
+	node := a.currentCommand()
+	node.Params[a.currentKey] = map[string]interface{}{}
+}
+
+func (a *AST) addParamMapKey(text string) {
+	a.currentMapKey = text
+}
+
+func (a *AST) addParamMapItem(text string) {
+	node := a.currentCommand()
+	node.Params[a.currentKey].(map[string]interface{})[a.currentMapKey] = itemValue(text)
+}
+
+var floatValueRegex = regexp.MustCompile(`^[0-9]+\.[0-9]+$`)
+
+// itemValue types the unquoted items of lists and maps like top level values: ints, floats, booleans or strings
+func itemValue(text string) interface{} {
+	if num, err := strconv.Atoi(text); err == nil {
+		return num
+	}
+	if floatValueRegex.MatchString(text) {
+		if num, err := strconv.ParseFloat(text, 64); err == nil && isShortestFloat(num, text) {
+			return num
+		}
+	}
+	switch text {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return text
+}
+
+func (a *AST) addParamMapQuotedItem(text string) {
+	node := a.currentCommand()
+	if unquoted, err := strconv.Unquote(`"` + text + `"`); err == nil {
+		text = unquoted
+	}
+	node.Params[a.currentKey].(map[string]interface{})[a.currentMapKey] = text
+}
+
 func (a *AST) addParamIntValue(text string) {
 	node := a.currentCommand()
 	num, err := strconv.Atoi(text)
@@ -60,6 +302,30 @@ func (a *AST) addParamIntValue(text string) {
 	node.Params[a.currentKey] = num
 }
 
+func (a *AST) addParamBoolValue(text string) {
+	node := a.currentCommand()
+	node.Params[a.currentKey] = text == "true"
+}
+
+// addParamFloatValue keeps as string the floats not written in their shortest form (ex: 1.10),
+// which would not be printed back as written
+func (a *AST) addParamFloatValue(text string) {
+	node := a.currentCommand()
+	num, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		panic(fmt.Sprintf("cannot convert '%s' to float", text))
+	}
+	if !isShortestFloat(num, text) {
+		node.Params[a.currentKey] = text
+		return
+	}
+	node.Params[a.currentKey] = num
+}
+
+func isShortestFloat(num float64, text string) bool {
+	return strconv.FormatFloat(num, 'f', -1, 64) == text
+}
+
 func (a *AST) addParamCidrValue(text string) {
 	node := a.currentCommand()
 	_, ipnet, err := net.ParseCIDR(text)
@@ -78,6 +344,25 @@ func (a *AST) addParamIpValue(text string) {
 	node.Params[a.currentKey] = ip.String()
 }
 
+// Values looking like IPv6 but not parsing as such (ex: 10:30:00) are kept as strings
+func (a *AST) addParamIpv6CidrValue(text string) {
+	node := a.currentCommand()
+	if _, ipnet, err := net.ParseCIDR(text); err == nil {
+		node.Params[a.currentKey] = ipnet.String()
+	} else {
+		node.Params[a.currentKey] = text
+	}
+}
+
+func (a *AST) addParamIpv6Value(text string) {
+	node := a.currentCommand()
+	if ip := net.ParseIP(text); ip != nil {
+		node.Params[a.currentKey] = ip.String()
+	} else {
+		node.Params[a.currentKey] = text
+	}
+}
+
 func (a *AST) addParamRefValue(text string) {
 	node := a.currentCommand()
 	node.Refs[a.currentKey] = text
@@ -93,6 +378,14 @@ func (a *AST) addParamHoleValue(text string) {
 	node.Holes[a.currentKey] = text
 }
 
+func (a *AST) addParamHoleType(text string) {
+	node := a.currentCommand()
+	if node.HoleTypes == nil {
+		node.HoleTypes = make(map[string]string)
+	}
+	node.HoleTypes[a.currentKey] = text
+}
+
 func (a *AST) currentDeclaration() *DeclarationNode {
 	st := a.currentStatement
 	if st == nil {
@@ -123,13 +416,17 @@ func (a *AST) currentCommand() *CommandNode {
 			return expr.(*CommandNode)
 		}
 		return nil
+	case *ParamSetNode:
+		return st.Node.(*ParamSetNode).Params
 	default:
 		panic("last expression: unexpected node type")
 	}
 }
 
-func (a *AST) addStatement(n Node) {
-	stat := &Statement{Node: n}
+func (a *AST) addStatement(n Node, offset int) {
+	stat := &Statement{Node: n, Repeat: a.currentRepeat, Comments: a.Comments, Pos: a.position(offset)}
+	a.currentRepeat = nil
+	a.Comments = nil
 	a.currentStatement = stat
 	a.Statements = append(a.Statements, stat)
 }
@@ -0,0 +1,185 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ast
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// formattedStatement is a statement split in the parts aligned with the ones of the neighbouring statements
+type formattedStatement struct {
+	// ident is the identifier of declarations, following the repeat prefix if any
+	ident   string
+	expr    string
+	comment string
+	// heredoc holds the lines following the statement line for a multiline value
+	heredoc string
+}
+
+// Format returns the canonical text of the template: one statement per line with single spaces, params
+// sorted by key, the '=' of consecutive declarations and the trailing comments of consecutive statements
+// aligned. Comment lines are kept, blank lines are collapsed and multiline values are written as heredocs.
+// Param sets are kept, while the statements splatting them are written with the params of the sets expanded
+func (a *AST) Format() (string, error) {
+	var lines []string
+	var block []*formattedStatement
+	flush := func() {
+		lines = append(lines, formatBlock(block)...)
+		block = nil
+	}
+	addComments := func(comments []string) {
+		for _, comment := range comments {
+			flush()
+			if comment != "" {
+				lines = append(lines, comment)
+			} else if len(lines) > 0 && lines[len(lines)-1] != "" {
+				lines = append(lines, "")
+			}
+		}
+	}
+
+	for _, stat := range a.Statements {
+		addComments(stat.Comments)
+		fstat, err := formatStatement(stat)
+		if err != nil {
+			return "", err
+		}
+		if fstat.heredoc != "" && fstat.comment != "" {
+			addComments([]string{"# " + fstat.comment})
+			fstat.comment = ""
+		}
+		block = append(block, fstat)
+		if fstat.heredoc != "" {
+			flush()
+		}
+	}
+	addComments(a.Comments)
+	flush()
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+func formatStatement(stat *Statement) (*formattedStatement, error) {
+	fstat := &formattedStatement{comment: stat.Comment}
+	var cmd *CommandNode
+	var all []string
+	switch n := stat.Node.(type) {
+	case *CommandNode:
+		cmd = n
+	case *DeclarationNode:
+		fstat.ident = n.Ident
+		cmd, _ = n.Expr.(*CommandNode)
+	case *ParamSetNode:
+		fstat.ident = n.Ident
+		cmd = n.Params
+	}
+	if cmd == nil {
+		return nil, fmt.Errorf("format: unexpected statement %s", stat)
+	}
+	if stat.Repeat != nil {
+		if fstat.ident != "" {
+			fstat.ident = fmt.Sprintf("%s %s", stat.Repeat, fstat.ident)
+		} else {
+			fstat.expr = fmt.Sprintf("%s ", stat.Repeat)
+		}
+	}
+
+	if cmd.Action != "" {
+		all = append(all, cmd.Action, cmd.Entity)
+	}
+	var heredoc *ParamNode
+	for _, param := range cmd.ParamNodes() {
+		if str, ok := param.Value.(string); ok && param.Kind == ValueParam && heredoc == nil && strings.ContainsAny(str, "\r\n") {
+			heredoc = param
+			continue
+		}
+		all = append(all, param.String())
+	}
+	if heredoc != nil {
+		body := heredoc.Value.(string)
+		marker := heredocMarker(body)
+		all = append(all, fmt.Sprintf("%s=<<%s", heredoc.paramKey(), marker))
+		fstat.heredoc = body + "\n" + marker
+	}
+	fstat.expr += strings.Join(all, " ")
+	return fstat, nil
+}
+
+// formatBlock aligns the '=' of the declarations and the trailing comments of consecutive statements
+func formatBlock(block []*formattedStatement) (lines []string) {
+	var identWidth, codeWidth int
+	for _, fstat := range block {
+		if w := utf8.RuneCountInString(fstat.ident); w > identWidth {
+			identWidth = w
+		}
+	}
+	codes := make([]string, len(block))
+	for i, fstat := range block {
+		codes[i] = fstat.expr
+		if fstat.ident != "" {
+			codes[i] = fmt.Sprintf("%s = %s", padRight(fstat.ident, identWidth), fstat.expr)
+		}
+		if w := utf8.RuneCountInString(codes[i]); fstat.comment != "" && w > codeWidth {
+			codeWidth = w
+		}
+	}
+	for i, fstat := range block {
+		line := codes[i]
+		if fstat.comment != "" {
+			line = fmt.Sprintf("%s # %s", padRight(line, codeWidth), fstat.comment)
+		}
+		lines = append(lines, line)
+		if fstat.heredoc != "" {
+			lines = append(lines, fstat.heredoc)
+		}
+	}
+	return
+}
+
+// heredocMarker returns a marker not found alone on a line of the heredoc body
+func heredocMarker(body string) string {
+	lines := strings.FieldsFunc(body, func(r rune) bool { return r == '\n' || r == '\r' })
+	marker := "EOF"
+	for i := 1; ; i++ {
+		found := false
+		for _, line := range lines {
+			if strings.TrimSpace(line) == marker {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return marker
+		}
+		marker = fmt.Sprintf("EOF%d", i)
+	}
+}
+
+func padRight(s string, width int) string {
+	if n := width - utf8.RuneCountInString(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// jsonAST is the stable JSON form of an AST, its statements being flattened
+// (ex: {"statements":[{"ident":"myvpc","action":"create","entity":"vpc","params":{"cidr":{"type":"string","value":"10.0.0.0/16"}}}]})
+type jsonAST struct {
+	Statements []*jsonStatement `json:"statements"`
+	Comments   []string         `json:"comments,omitempty"`
+}
+
+type jsonStatement struct {
+	Ident string `json:"ident,omitempty"`
+	// ParamSet is set on the declarations of param sets (ex: webdefaults = type=t2.micro count=1), without action nor entity
+	ParamSet  bool                  `json:"paramSet,omitempty"`
+	Action    string                `json:"action,omitempty"`
+	Entity    string                `json:"entity,omitempty"`
+	Params    map[string]*jsonValue `json:"params,omitempty"`
+	Refs      map[string]string     `json:"refs,omitempty"`
+	Aliases   map[string]string     `json:"aliases,omitempty"`
+	Holes     map[string]string     `json:"holes,omitempty"`
+	HoleTypes map[string]string     `json:"holeTypes,omitempty"`
+	Optionals []string              `json:"optionals,omitempty"`
+	Repeat    *jsonRepeat           `json:"repeat,omitempty"`
+	Comment   string                `json:"comment,omitempty"`
+	Comments  []string              `json:"comments,omitempty"`
+	// Position and ParamPositions locate the statement and its params in the template text, when parsed
+	Position       *Position           `json:"position,omitempty"`
+	ParamPositions map[string]Position `json:"paramPositions,omitempty"`
+}
+
+type jsonRepeat struct {
+	Count int    `json:"count,omitempty"`
+	Hole  string `json:"hole,omitempty"`
+}
+
+// jsonValue is a param value tagged with its type, among string, int, float, bool, list, map,
+// concat (strings and holes), func, file and hole (in concatenations and function args)
+type jsonValue struct {
+	Type    string                `json:"type"`
+	Value   interface{}           `json:"value,omitempty"`
+	Items   []*jsonValue          `json:"items,omitempty"`
+	Entries map[string]*jsonValue `json:"entries,omitempty"`
+}
+
+// MarshalJSON converts the AST to a stable JSON form, parsed back by UnmarshalJSON
+func (a *AST) MarshalJSON() ([]byte, error) {
+	tree := &jsonAST{Statements: []*jsonStatement{}, Comments: a.Comments}
+	for _, stat := range a.Statements {
+		var cmd *CommandNode
+		jsonStat := &jsonStatement{Comment: stat.Comment, Comments: stat.Comments}
+		switch n := stat.Node.(type) {
+		case *CommandNode:
+			cmd = n
+		case *DeclarationNode:
+			jsonStat.Ident = n.Ident
+			cmd, _ = n.Expr.(*CommandNode)
+		case *ParamSetNode:
+			jsonStat.Ident, jsonStat.ParamSet = n.Ident, true
+			cmd = n.Params
+		}
+		if cmd == nil {
+			return nil, fmt.Errorf("json: unexpected statement %s", stat)
+		}
+		jsonStat.Action, jsonStat.Entity = cmd.Action, cmd.Entity
+		if stat.Pos.IsValid() {
+			pos := stat.Pos
+			jsonStat.Position = &pos
+		}
+		jsonStat.ParamPositions = cmd.ParamPositions
+		if len(cmd.Params) > 0 {
+			jsonStat.Params = make(map[string]*jsonValue)
+			for k, v := range cmd.Params {
+				val, err := newJSONValue(v)
+				if err != nil {
+					return nil, fmt.Errorf("json: %s %s: param %s: %s", cmd.Action, cmd.Entity, k, err)
+				}
+				jsonStat.Params[k] = val
+			}
+		}
+		jsonStat.Refs, jsonStat.Aliases, jsonStat.Holes, jsonStat.HoleTypes = cmd.Refs, cmd.Aliases, cmd.Holes, cmd.HoleTypes
+		for k, optional := range cmd.Optionals {
+			if optional {
+				jsonStat.Optionals = append(jsonStat.Optionals, k)
+			}
+		}
+		sort.Strings(jsonStat.Optionals)
+		if stat.Repeat != nil {
+			jsonStat.Repeat = &jsonRepeat{Count: stat.Repeat.Count, Hole: stat.Repeat.Hole}
+		}
+		tree.Statements = append(tree.Statements, jsonStat)
+	}
+	return json.Marshal(tree)
+}
+
+// UnmarshalJSON replaces the statements of the AST by the ones of its JSON form given by MarshalJSON
+func (a *AST) UnmarshalJSON(b []byte) error {
+	var tree jsonAST
+	if err := json.Unmarshal(b, &tree); err != nil {
+		return err
+	}
+	var statements []*Statement
+	for _, jsonStat := range tree.Statements {
+		switch {
+		case jsonStat == nil:
+			return fmt.Errorf("json: statement %d: missing action or entity", len(statements)+1)
+		case jsonStat.ParamSet && jsonStat.Ident == "":
+			return fmt.Errorf("json: statement %d: missing param set identifier", len(statements)+1)
+		case !jsonStat.ParamSet && (jsonStat.Action == "" || jsonStat.Entity == ""):
+			return fmt.Errorf("json: statement %d: missing action or entity", len(statements)+1)
+		}
+		cmd := &CommandNode{Action: jsonStat.Action, Entity: jsonStat.Entity}
+		if len(jsonStat.Params)+len(jsonStat.Refs)+len(jsonStat.Aliases)+len(jsonStat.Holes) > 0 {
+			initParams(cmd)
+		}
+		for k, v := range jsonStat.Params {
+			val, err := v.value()
+			if err != nil {
+				return fmt.Errorf("json: %s %s: param %s: %s", cmd.Action, cmd.Entity, k, err)
+			}
+			cmd.Params[k] = val
+		}
+		for k, v := range jsonStat.Refs {
+			cmd.Refs[k] = v
+		}
+		for k, v := range jsonStat.Aliases {
+			cmd.Aliases[k] = v
+		}
+		for k, v := range jsonStat.Holes {
+			cmd.Holes[k] = v
+		}
+		if len(jsonStat.HoleTypes) > 0 {
+			cmd.HoleTypes = jsonStat.HoleTypes
+		}
+		if len(jsonStat.ParamPositions) > 0 {
+			cmd.ParamPositions = jsonStat.ParamPositions
+		}
+		for _, k := range jsonStat.Optionals {
+			if cmd.Optionals == nil {
+				cmd.Optionals = make(map[string]bool)
+			}
+			cmd.Optionals[k] = true
+		}
+
+		stat := &Statement{Node: cmd, Comment: jsonStat.Comment, Comments: jsonStat.Comments}
+		switch {
+		case jsonStat.ParamSet:
+			stat.Node = &ParamSetNode{Ident: jsonStat.Ident, Params: cmd}
+		case jsonStat.Ident != "":
+			stat.Node = &DeclarationNode{Ident: jsonStat.Ident, Expr: cmd}
+		}
+		if jsonStat.Position != nil {
+			stat.Pos = *jsonStat.Position
+		}
+		if r := jsonStat.Repeat; r != nil {
+			stat.Repeat = &Repeat{Count: r.Count, Hole: r.Hole}
+		}
+		statements = append(statements, stat)
+	}
+	a.Statements, a.Comments = statements, tree.Comments
+	return nil
+}
+
+func newJSONValue(v interface{}) (*jsonValue, error) {
+	switch val := v.(type) {
+	case string:
+		return &jsonValue{Type: "string", Value: val}, nil
+	case int:
+		return &jsonValue{Type: "int", Value: val}, nil
+	case float64:
+		return &jsonValue{Type: "float", Value: val}, nil
+	case bool:
+		return &jsonValue{Type: "bool", Value: val}, nil
+	case FileValue:
+		return &jsonValue{Type: "file", Value: string(val)}, nil
+	case Hole:
+		return &jsonValue{Type: "hole", Value: string(val)}, nil
+	case []interface{}:
+		items, err := newJSONValues(val)
+		return &jsonValue{Type: "list", Items: items}, err
+	case ConcatenationValue:
+		items, err := newJSONValues(val)
+		return &jsonValue{Type: "concat", Items: items}, err
+	case *FuncValue:
+		args, err := newJSONValues(val.Args)
+		return &jsonValue{Type: "func", Value: val.Name, Items: args}, err
+	case map[string]interface{}:
+		entries := make(map[string]*jsonValue)
+		for k, item := range val {
+			entry, err := newJSONValue(item)
+			if err != nil {
+				return nil, err
+			}
+			entries[k] = entry
+		}
+		return &jsonValue{Type: "map", Entries: entries}, nil
+	default:
+		return nil, fmt.Errorf("unexpected value %v of type %T", v, v)
+	}
+}
+
+func newJSONValues(values []interface{}) (items []*jsonValue, err error) {
+	for _, v := range values {
+		item, err := newJSONValue(v)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return
+}
+
+func (v *jsonValue) value() (interface{}, error) {
+	if v == nil {
+		return nil, fmt.Errorf("missing value")
+	}
+	switch v.Type {
+	case "string", "file", "hole", "func":
+		str, ok := v.Value.(string)
+		if !ok && v.Value != nil {
+			return nil, fmt.Errorf("invalid %s value %v", v.Type, v.Value)
+		}
+		switch v.Type {
+		case "file":
+			return FileValue(str), nil
+		case "hole":
+			return Hole(str), nil
+		case "func":
+			args, err := jsonValuesItems(v.Items)
+			return &FuncValue{Name: str, Args: args}, err
+		}
+		return str, nil
+	case "int", "float":
+		num, ok := v.Value.(float64)
+		if !ok && v.Value != nil {
+			return nil, fmt.Errorf("invalid %s value %v", v.Type, v.Value)
+		}
+		if v.Type == "int" {
+			return int(num), nil
+		}
+		return num, nil
+	case "bool":
+		b, ok := v.Value.(bool)
+		if !ok && v.Value != nil {
+			return nil, fmt.Errorf("invalid bool value %v", v.Value)
+		}
+		return b, nil
+	case "list":
+		items, err := jsonValuesItems(v.Items)
+		if items == nil {
+			items = []interface{}{}
+		}
+		return items, err
+	case "concat":
+		items, err := jsonValuesItems(v.Items)
+		return ConcatenationValue(items), err
+	case "map":
+		entries := make(map[string]interface{})
+		for k, entry := range v.Entries {
+			val, err := entry.value()
+			if err != nil {
+				return nil, err
+			}
+			entries[k] = val
+		}
+		return entries, nil
+	default:
+		return nil, fmt.Errorf("unknown value type '%s'", v.Type)
+	}
+}
+
+func jsonValuesItems(values []*jsonValue) (items []interface{}, err error) {
+	for _, v := range values {
+		item, err := v.value()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ast
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	tree := visitedTree()
+	tree.Statements = append(tree.Statements,
+		&Statement{Node: &CommandNode{
+			Action: "create", Entity: "instance",
+			Params: map[string]interface{}{
+				"count":    2,
+				"price":    0.5,
+				"public":   true,
+				"userdata": FileValue("/tmp/script.sh"),
+				"name":     &FuncValue{Name: "Join", Args: []interface{}{"-", Hole("env"), 1}},
+				"tags":     map[string]interface{}{"env": "prod", "ports": []interface{}{80, 443}},
+			},
+			Refs:    make(map[string]string),
+			Aliases: make(map[string]string),
+			Holes:   make(map[string]string),
+		}, Comment: "# web servers", Repeat: &Repeat{Hole: "web.count"}},
+		&Statement{Node: &CommandNode{Action: "delete", Entity: "bucket"}, Repeat: &Repeat{Count: 3}},
+	)
+
+	b, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &AST{}
+	if err := json.Unmarshal(b, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := decoded.Statements, tree.Statements; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got\n%#v\nwant\n%#v", got, want)
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(again), string(b); got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONForm(t *testing.T) {
+	b, err := json.Marshal(&AST{Statements: []*Statement{
+		{Node: &DeclarationNode{Ident: "sub", Expr: &CommandNode{
+			Action: "create", Entity: "subnet",
+			Params:  map[string]interface{}{"name": ConcatenationValue{Hole("env"), "-sub"}},
+			Refs:    map[string]string{"vpc": "myvpc"},
+			Aliases: make(map[string]string),
+			Holes:   map[string]string{"cidr": "cidr"},
+		}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `{"statements":[{"ident":"sub","action":"create","entity":"subnet",` +
+		`"params":{"name":{"type":"concat","items":[{"type":"hole","value":"env"},{"type":"string","value":"-sub"}]}},` +
+		`"refs":{"vpc":"myvpc"},"holes":{"cidr":"cidr"}}]}`
+	if got, want := string(b), exp; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	tcases := []struct {
+		in     string
+		expErr string
+	}{
+		{in: `{"statements":[{"entity":"vpc"}]}`, expErr: "missing action or entity"},
+		{in: `{"statements":[{"action":"create","entity":"vpc","params":{"cidr":{"type":"ipv4"}}}]}`, expErr: "unknown value type 'ipv4'"},
+		{in: `{"statements":[{"action":"create","entity":"vpc","params":{"count":{"type":"int","value":"2"}}}]}`, expErr: "invalid int value 2"},
+		{in: `{"statements":[{"action":"create","entity":"vpc","params":{"cidr":null}}]}`, expErr: "missing value"},
+	}
+	for i, tcase := range tcases {
+		err := json.Unmarshal([]byte(tcase.in), &AST{})
+		if err == nil {
+			t.Fatalf("%d: expected error", i+1)
+		}
+		if got, want := err.Error(), tcase.expErr; !strings.Contains(got, want) {
+			t.Fatalf("%d: got %s, want %s", i+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ast
+
+import "fmt"
+
+// Position is the location of a statement or a param in the text of a parsed template,
+// its line and column starting at 1, columns counting characters. The zero value is an unknown position
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("line %d column %d", p.Line, p.Column)
+}
+
+// PositionError is an error met on a statement or a param of a parsed template, located in its text
+type PositionError struct {
+	Pos Position
+	Err error
+}
+
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Err)
+}
+
+// LocateError returns the error located at the position, unless the position is unknown
+// or the error already located (ex: LocateError(stat.Pos, err) gives "line 7 column 1: ...")
+func LocateError(pos Position, err error) error {
+	if err == nil || !pos.IsValid() {
+		return err
+	}
+	if _, ok := err.(*PositionError); ok {
+		return err
+	}
+	return &PositionError{Pos: pos, Err: err}
+}
+
+// position returns the position of the source offset given to an action. Actions being run in
+// the order of the text, the source is scanned from the position previously returned
+func (a *AST) position(offset int) Position {
+	if offset < a.scanOffset || a.scanPos.Line == 0 {
+		a.scanOffset, a.scanPos = 0, Position{Line: 1, Column: 1}
+	}
+	for _, c := range a.source[a.scanOffset:offset] {
+		if c == '\n' {
+			a.scanPos.Line, a.scanPos.Column = a.scanPos.Line+1, 1
+		} else {
+			a.scanPos.Column++
+		}
+	}
+	a.scanOffset = offset
+	return a.scanPos
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ast
+
+import (
+	"errors"
+	"strings"
+)
+
+// ParseErrors are the syntax errors of a template reported at once by ParseAll, in the order of the text
+type ParseErrors []error
+
+func (e ParseErrors) Error() string {
+	var all []string
+	for _, err := range e {
+		all = append(all, err.Error())
+	}
+	return strings.Join(all, "")
+}
+
+// ParseAll parses the buffer reporting all its syntax errors at once: after an error, the line holding it
+// is skipped (replaced by a comment line of the same length, to keep the positions of the next errors)
+// and the buffer parsed again, until no error is left. It returns nil or the ParseErrors met
+func (p *Peg) ParseAll() error {
+	var errs ParseErrors
+	var last string
+	skipped := make(map[int]bool)
+	for {
+		err := p.Parse()
+		if err == nil {
+			break
+		}
+		perr, ok := err.(*parseError)
+		if !ok {
+			return err
+		}
+		// the error of a line is met again when the error is found on the next line (ex: unterminated heredoc)
+		if msg := perr.Error(); msg != last {
+			errs = append(errs, errors.New(msg))
+			last = msg
+		}
+
+		start, end := p.lineAt(int(perr.max.end))
+		if skipped[start] {
+			start, end = p.lineAt(int(perr.max.begin))
+		}
+		if skipped[start] {
+			break
+		}
+		skipped[start] = true
+		comment := "#"
+		if end > start {
+			comment += strings.Repeat(" ", end-start-1)
+		}
+		p.Buffer = p.Buffer[:start] + comment + p.Buffer[end:]
+		p.Reset()
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// lineAt returns the offsets of the start and the end of the line holding the offset in the buffer
+func (p *Peg) lineAt(offset int) (start, end int) {
+	if offset > len(p.Buffer) {
+		offset = len(p.Buffer)
+	}
+	start, end = strings.LastIndexAny(p.Buffer[:offset], "\r\n")+1, len(p.Buffer)
+	if i := strings.IndexAny(p.Buffer[offset:], "\r\n"); i > -1 {
+		end = offset + i
+	}
+	return
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ast
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// isUnicodeLetter reports whether s starts with a non ASCII rune of the Unicode letter or mark categories
+func isUnicodeLetter(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r >= utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsMark(r))
+}
+
+// skipHeredocBody moves the position, following the line break after the heredoc marker, to the end
+// of the lines preceding the one holding only the marker. The line break before the marker line
+// is not part of the body. It reports false when the marker line is missing
+func skipHeredocBody(buffer string, position *uint32) bool {
+	start := int(*position)
+	markerEnd := start
+	if markerEnd > 0 && buffer[markerEnd-1] == '\n' {
+		markerEnd--
+	}
+	if markerEnd > 0 && buffer[markerEnd-1] == '\r' {
+		markerEnd--
+	}
+	markerStart := markerEnd
+	for markerStart > 0 && isHeredocMarkerByte(buffer[markerStart-1]) {
+		markerStart--
+	}
+	marker := buffer[markerStart:markerEnd]
+
+	for line := start; ; {
+		end := line
+		for end < len(buffer) && buffer[end] != '\n' && buffer[end] != '\r' && buffer[end] != endSymbol {
+			end++
+		}
+		if strings.Trim(buffer[line:end], " \t") == marker {
+			if line > start {
+				line--
+				if line > start && buffer[line] == '\n' && buffer[line-1] == '\r' {
+					line--
+				}
+			}
+			*position = uint32(line)
+			return true
+		}
+		if end == len(buffer) || buffer[end] == endSymbol {
+			return false
+		}
+		if buffer[end] == '\r' && end+1 < len(buffer) && buffer[end+1] == '\n' {
+			end++
+		}
+		line = end + 1
+	}
+}
+
+func isHeredocMarkerByte(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
+
+func (a *AST) setSource(source string) {
+	a.source = source
+}
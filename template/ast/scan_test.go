@@ -0,0 +1,84 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSkipHeredocBody(t *testing.T) {
+	tcases := []struct {
+		buffer       string
+		wantOK       bool
+		wantPosition int
+	}{
+		{buffer: "doc=<<EOF\n{}\nEOF", wantOK: true, wantPosition: 12},
+		{buffer: "doc=<<EOF\nline 1\nline 2\n  EOF \ncreate vpc", wantOK: true, wantPosition: 23},
+		{buffer: "doc=<<SH_1\r\necho EOF\r\nSH_1", wantOK: true, wantPosition: 20},
+		{buffer: "doc=<<EOF\nEOF", wantOK: true, wantPosition: 10},
+		{buffer: "doc=<<EOF\nEOF2\nEOF", wantOK: true, wantPosition: 14},
+		{buffer: "doc=<<EOF\n{}\nEO", wantOK: false},
+		{buffer: "doc=<<EOF\n", wantOK: false},
+	}
+	for i, tcase := range tcases {
+		buffer := tcase.buffer + string([]byte{endSymbol})
+		position := uint32(markerLineEnd(tcase.buffer))
+		ok := skipHeredocBody(buffer, &position)
+		if got, want := ok, tcase.wantOK; got != want {
+			t.Fatalf("%d: got %t, want %t", i+1, got, want)
+		}
+		if !ok {
+			continue
+		}
+		if got, want := int(position), tcase.wantPosition; got != want {
+			t.Fatalf("%d: got %d (%q), want %d", i+1, got, buffer[:got], want)
+		}
+	}
+}
+
+// markerLineEnd returns the offset following the line break after the heredoc marker
+func markerLineEnd(s string) int {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return i + 1
+	}
+	return len(s)
+}
+
+func TestIsUnicodeLetter(t *testing.T) {
+	tcases := []struct {
+		in   string
+		want bool
+	}{
+		{"é=1", true},
+		{"жук", true},
+		{"日本", true},
+		{"́", true},
+		{"a", false},
+		{"_", false},
+		{"€", false},
+		{"∑", false},
+		{"🚀", false},
+		{"\xff", false},
+		{"", false},
+	}
+	for _, tcase := range tcases {
+		if got, want := isUnicodeLetter(tcase.in), tcase.want; got != want {
+			t.Fatalf("%q: got %t, want %t", tcase.in, got, want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// SkipChildren returned when visiting a declaration or a command skips the nodes it holds
+var SkipChildren = errors.New("skip children")
+
+// ParamKind tells how the value of a param is given in a template
+type ParamKind int
+
+const (
+	// ValueParam is a param given by value (ex: count=1, name="web", cidr=cidrsubnet(10.0.0.0/16,8,1))
+	ValueParam ParamKind = iota
+	// RefParam is a param referencing a declared variable (ex: vpc=$myvpc)
+	RefParam
+	// AliasParam is a param given by an alias (ex: subnet=@my-subnet)
+	AliasParam
+	// HoleParam is a param to fill before the template runs (ex: keypair={keypair})
+	HoleParam
+)
+
+func (k ParamKind) String() string {
+	switch k {
+	case RefParam:
+		return "ref"
+	case AliasParam:
+		return "alias"
+	case HoleParam:
+		return "hole"
+	default:
+		return "value"
+	}
+}
+
+// ParamNode is a param of a command, visited after the command. Value is the name of the variable, alias
+// or hole for these kinds of params, the value itself otherwise (ex: int, []interface{}, ConcatenationValue)
+type ParamNode struct {
+	Key      string
+	Kind     ParamKind
+	Value    interface{}
+	HoleType string
+	Optional bool
+	// Pos is the position of the param in the template text, unknown for params not parsed
+	Pos Position
+}
+
+func (n *ParamNode) clone() Node {
+	param := *n
+	return &param
+}
+
+func (n *ParamNode) String() string {
+	key := n.paramKey()
+	switch n.Kind {
+	case RefParam:
+		return fmt.Sprintf("%s=$%v", key, n.Value)
+	case AliasParam:
+		return fmt.Sprintf("%s=@%v", key, n.Value)
+	case HoleParam:
+		if n.HoleType != "" {
+			return fmt.Sprintf("%s={%v as %s}", key, n.Value, n.HoleType)
+		}
+		return fmt.Sprintf("%s={%v}", key, n.Value)
+	default:
+		return fmt.Sprintf("%s=%s", key, quoteValue(n.Value))
+	}
+}
+
+// paramKey returns the key of the param as written in templates, ending with '?' when optional
+func (n *ParamNode) paramKey() string {
+	if n.Optional {
+		return n.Key + "?"
+	}
+	return n.Key
+}
+
+// ParamNodes returns the params of the command sorted by key
+func (n *CommandNode) ParamNodes() (params []*ParamNode) {
+	for k, v := range n.Params {
+		params = append(params, &ParamNode{Key: k, Kind: ValueParam, Value: v, Optional: n.Optionals[k], Pos: n.ParamPositions[k]})
+	}
+	for k, v := range n.Refs {
+		params = append(params, &ParamNode{Key: k, Kind: RefParam, Value: v, Optional: n.Optionals[k], Pos: n.ParamPositions[k]})
+	}
+	for k, v := range n.Aliases {
+		params = append(params, &ParamNode{Key: k, Kind: AliasParam, Value: v, Optional: n.Optionals[k], Pos: n.ParamPositions[k]})
+	}
+	for k, v := range n.Holes {
+		params = append(params, &ParamNode{Key: k, Kind: HoleParam, Value: v, HoleType: n.HoleTypes[k], Optional: n.Optionals[k], Pos: n.ParamPositions[k]})
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Key < params[j].Key })
+	return
+}
+
+// Visit calls fn on the nodes of the statements in order: a declaration, then its command, then the params
+// of the command sorted by key. The walk stops on the first error returned by fn, except SkipChildren
+func (a *AST) Visit(fn func(Node) error) error {
+	var visit func(n Node) error
+	visit = func(n Node) error {
+		err := fn(n)
+		if err == SkipChildren {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch node := n.(type) {
+		case *DeclarationNode:
+			if node.Expr != nil {
+				return visit(node.Expr)
+			}
+		case *CommandNode:
+			for _, param := range node.ParamNodes() {
+				if err := visit(param); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for _, stat := range a.Statements {
+		if err := visit(stat.Node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Visitor is called on the typed nodes of the AST walked by Walk
+type Visitor interface {
+	VisitDeclaration(*DeclarationNode) error
+	VisitCommand(*CommandNode) error
+	VisitParam(*ParamNode) error
+}
+
+// Walk visits the nodes of the AST in the order of Visit, calling the visitor method of each node type
+func (a *AST) Walk(v Visitor) error {
+	return a.Visit(func(n Node) error {
+		switch node := n.(type) {
+		case *DeclarationNode:
+			return v.VisitDeclaration(node)
+		case *CommandNode:
+			return v.VisitCommand(node)
+		case *ParamNode:
+			return v.VisitParam(node)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func visitedTree() *AST {
+	return &AST{Statements: []*Statement{
+		{Node: &DeclarationNode{
+			Ident: "myvpc",
+			Expr: &CommandNode{
+				Action: "create", Entity: "vpc",
+				Params:  map[string]interface{}{"cidr": "10.0.0.0/16", "tags": []interface{}{"a", "b"}},
+				Refs:    make(map[string]string),
+				Aliases: make(map[string]string),
+				Holes:   make(map[string]string),
+			}}},
+		{Node: &CommandNode{
+			Action: "create", Entity: "subnet",
+			Params:    map[string]interface{}{"name": ConcatenationValue{Hole("env"), "-sub"}},
+			Refs:      map[string]string{"vpc": "myvpc"},
+			Aliases:   map[string]string{"zone": "eu-west"},
+			Holes:     map[string]string{"count": "count", "cidr": "cidr"},
+			HoleTypes: map[string]string{"count": "int"},
+			Optionals: map[string]bool{"cidr": true},
+		}},
+	}}
+}
+
+func TestVisitAST(t *testing.T) {
+	var visited []string
+	err := visitedTree().Visit(func(n Node) error {
+		switch node := n.(type) {
+		case *DeclarationNode:
+			visited = append(visited, "declaration "+node.Ident)
+		case *CommandNode:
+			visited = append(visited, "command "+node.Entity)
+		case *ParamNode:
+			visited = append(visited, fmt.Sprintf("%s param %s", node.Kind, node))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"declaration myvpc",
+		"command vpc",
+		"value param cidr=10.0.0.0/16",
+		"value param tags=[a,b]",
+		"command subnet",
+		"hole param cidr?={cidr}",
+		"hole param count={count as int}",
+		"value param name={env}+\"-sub\"",
+		"ref param vpc=$myvpc",
+		"alias param zone=@eu-west",
+	}
+	if got, want := visited, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	visited = nil
+	err = visitedTree().Visit(func(n Node) error {
+		visited = append(visited, fmt.Sprintf("%T", n))
+		if _, ok := n.(*DeclarationNode); ok {
+			return SkipChildren
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := visited, []string{"*ast.DeclarationNode", "*ast.CommandNode", "*ast.ParamNode", "*ast.ParamNode", "*ast.ParamNode", "*ast.ParamNode", "*ast.ParamNode"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	stop := errors.New("stop")
+	var count int
+	err = visitedTree().Visit(func(n Node) error {
+		count++
+		if _, ok := n.(*ParamNode); ok {
+			return stop
+		}
+		return nil
+	})
+	if got, want := err, stop; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := count, 3; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
+
+type holesCollector struct {
+	entities []string
+	holes    []string
+}
+
+func (c *holesCollector) VisitDeclaration(*DeclarationNode) error { return nil }
+func (c *holesCollector) VisitCommand(n *CommandNode) error {
+	c.entities = append(c.entities, n.Entity)
+	return nil
+}
+func (c *holesCollector) VisitParam(n *ParamNode) error {
+	if n.Kind == HoleParam {
+		c.holes = append(c.holes, fmt.Sprint(n.Value))
+	}
+	return nil
+}
+
+func TestWalkAST(t *testing.T) {
+	c := &holesCollector{}
+	if err := visitedTree().Walk(c); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.entities, []string{"vpc", "subnet"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := c.holes, []string{"cidr", "count"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/wallix/awless/template/ast"
+)
+
+func init() {
+	gob.Register(&ast.CommandNode{})
+	gob.Register(&ast.DeclarationNode{})
+	gob.Register(ast.FileValue(""))
+	gob.Register(ast.ConcatenationValue{})
+	gob.Register(ast.Hole(""))
+	gob.Register(&ast.FuncValue{})
+	gob.Register([]interface{}{})
+	gob.Register(map[string]interface{}{})
+}
+
+const (
+	cacheEntryExt      = ".gob"
+	cacheStatsFilename = "stats.json"
+)
+
+// Cache keeps the parsed form of templates in a directory, keyed by the hash
+// of their content and of the grammar version, so that parsing the same
+// template again (ex: in CI) decodes it instead. Failing to read or write
+// the cache only falls back to parsing.
+type Cache struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// CacheStats counts the lookups of a cache since it was created or cleared
+// (hits and misses, across runs) and its current entries
+type CacheStats struct {
+	Hits, Misses int
+	Entries      int   `json:"-"`
+	Size         int64 `json:"-"`
+}
+
+// HitRatio returns the ratio of lookups found in cache, between 0 and 1
+func (s CacheStats) HitRatio() float64 {
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+	return 0
+}
+
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Parse returns the template of text from cache, or parses it and caches it
+func (c *Cache) Parse(text string) (*Template, error) {
+	path := filepath.Join(c.dir, cacheKey(text)+cacheEntryExt)
+	if tpl, err := readCacheEntry(path); err == nil {
+		c.count(true)
+		return tpl, nil
+	}
+
+	tpl, err := Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	c.count(false)
+	c.write(path, tpl)
+	return tpl, nil
+}
+
+// Stats returns the lookups counted and the current entries of the cache
+func (c *Cache) Stats() (CacheStats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.readStats()
+	infos, err := ioutil.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, err
+	}
+	for _, info := range infos {
+		if strings.HasSuffix(info.Name(), cacheEntryExt) {
+			stats.Entries++
+			stats.Size += info.Size()
+		}
+	}
+	return stats, nil
+}
+
+// Clear removes all the cached templates and resets the stats
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return os.RemoveAll(c.dir)
+}
+
+func cacheKey(text string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%s", ast.GrammarVersion, text)))
+	return hex.EncodeToString(sum[:])
+}
+
+func readCacheEntry(path string) (*Template, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var statements []*ast.Statement
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&statements); err != nil {
+		return nil, err
+	}
+	tpl := &Template{AST: &ast.AST{Statements: statements}}
+	for _, stat := range statements {
+		if stat == nil || stat.Node == nil {
+			return nil, fmt.Errorf("invalid cached template %s", path)
+		}
+	}
+	tpl.visitCommandNodes(initCommandMaps)
+	return tpl, nil
+}
+
+// initCommandMaps restores the empty maps of a parsed command node, not kept by gob
+func initCommandMaps(n *ast.CommandNode) {
+	if n.Params == nil {
+		return
+	}
+	if n.Refs == nil {
+		n.Refs = make(map[string]string)
+	}
+	if n.Aliases == nil {
+		n.Aliases = make(map[string]string)
+	}
+	if n.Holes == nil {
+		n.Holes = make(map[string]string)
+	}
+}
+
+func (c *Cache) write(path string, tpl *Template) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(tpl.Statements); err != nil {
+		return
+	}
+	writeFileAtomic(path, buf.Bytes())
+}
+
+func (c *Cache) count(hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.readStats()
+	if hit {
+		stats.Hits++
+	} else {
+		stats.Misses++
+	}
+	if data, err := json.Marshal(stats); err == nil {
+		writeFileAtomic(filepath.Join(c.dir, cacheStatsFilename), data)
+	}
+}
+
+func (c *Cache) readStats() (stats CacheStats) {
+	if data, err := ioutil.ReadFile(filepath.Join(c.dir, cacheStatsFilename)); err == nil {
+		json.Unmarshal(data, &stats)
+	}
+	return
+}
+
+// writeFileAtomic writes to a temporary file renamed to path, so that
+// concurrent runs never read a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-template-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	text := "sub = create subnet cidr=10.0.0.0/24 vpc={vpc.id}\ncreate instance subnet=$sub count=2 name=@web"
+	cache := NewCache(dir)
+
+	parsed, err := cache.Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := cache.Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cached.String(), parsed.String(); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := cached.CommandNodesIterator()[1].Params["count"], 2; got != want {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+	cached.ResolveHoles(map[string]interface{}{"vpc.id": "vpc-1"})
+	if got, want := cached.CommandNodesIterator()[0].Params["vpc"], "vpc-1"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	stats := mustStats(t, cache)
+	if got, want := stats, (CacheStats{Hits: 1, Misses: 1, Entries: 1, Size: stats.Size}); got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	t.Run("corrupted entry", func(t *testing.T) {
+		if err := ioutil.WriteFile(filepath.Join(dir, cacheKey(text)+cacheEntryExt), []byte("garbage"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		tpl, err := cache.Parse(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := tpl.String(), parsed.String(); got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		if got, want := mustStats(t, cache).Misses, 2; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		if _, err := cache.Parse("create instance subnet=="); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		if err := cache.Clear(); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := mustStats(t, cache), (CacheStats{}); got != want {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+	})
+}
+
+func mustStats(t *testing.T, c *Cache) CacheStats {
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stats
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wallix/awless/template/ast"
+	"github.com/wallix/awless/template/driver"
+)
+
+// RunConcurrently runs up to the given number of commands in parallel, each command once the commands it
+// depends on have succeeded: deps[i] lists the indexes of the previous commands to run before the i-th
+// (see TemplateExecution.RevertDependencies). After a failure, no other command is started.
+// Statements of the returned template are ordered as they completed, failed ones last, then the ones not run
+func (s *Template) RunConcurrently(d driver.Driver, deps [][]int, concurrency int) (*Template, error) {
+	current := &Template{AST: s.Clone()}
+
+	var cmds []*ast.CommandNode
+	for _, sts := range current.Statements {
+		if _, ok := sts.Node.(*ast.ParamSetNode); ok {
+			continue
+		}
+		cmd, ok := sts.Node.(*ast.CommandNode)
+		if !ok {
+			return current, errors.New("cannot run declarations concurrently")
+		}
+		cmds = append(cmds, cmd)
+	}
+	if len(deps) != len(cmds) {
+		return current, fmt.Errorf("got dependencies of %d commands, expecting %d", len(deps), len(cmds))
+	}
+
+	fns := make([]driver.DriverFn, len(cmds))
+	remaining := make([]int, len(cmds))
+	dependents := make([][]int, len(cmds))
+	var ready []int
+	for i, cmd := range cmds {
+		fn, err := d.Lookup(cmd.Action, cmd.Entity)
+		if err != nil {
+			return current, err
+		}
+		fns[i] = fn
+		for _, dep := range deps[i] {
+			if dep < 0 || dep >= i {
+				return current, fmt.Errorf("command %d cannot depend on command %d", i, dep)
+			}
+			dependents[dep] = append(dependents[dep], i)
+		}
+		if remaining[i] = len(deps[i]); remaining[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	donec := make(chan int)
+	var running int
+	var runErr error
+	var succeeded, failed []int
+	for {
+		for ; runErr == nil && running < concurrency && len(ready) > 0; ready = ready[1:] {
+			running++
+			go func(i int) {
+				cmds[i].CmdResult, cmds[i].CmdErr = fns[i](cmds[i].Params)
+				donec <- i
+			}(ready[0])
+		}
+		if running == 0 {
+			break
+		}
+		i := <-donec
+		running--
+		if err := cmds[i].CmdErr; err != nil {
+			failed = append(failed, i)
+			if runErr == nil {
+				runErr = err
+			}
+			continue
+		}
+		succeeded = append(succeeded, i)
+		for _, dependent := range dependents[i] {
+			if remaining[dependent]--; remaining[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	done := make(map[int]bool)
+	var statements []*ast.Statement
+	for _, i := range append(succeeded, failed...) {
+		done[i] = true
+		statements = append(statements, current.Statements[i])
+	}
+	for i, sts := range current.Statements {
+		if !done[i] {
+			statements = append(statements, sts)
+		}
+	}
+	current.Statements = statements
+
+	return current, runErr
+}
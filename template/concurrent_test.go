@@ -0,0 +1,111 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template/driver"
+)
+
+// recordingDriver records the ids of the commands run, failing for the given id
+type recordingDriver struct {
+	mu                  sync.Mutex
+	done                []string
+	running, maxRunning int
+	failOn              string
+}
+
+func (d *recordingDriver) Lookup(lookups ...string) (driver.DriverFn, error) {
+	return func(params map[string]interface{}) (interface{}, error) {
+		id := fmt.Sprint(params["id"])
+		d.mu.Lock()
+		if d.running++; d.running > d.maxRunning {
+			d.maxRunning = d.running
+		}
+		d.mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.running--
+		if id == d.failOn {
+			return nil, errors.New("cannot delete " + id)
+		}
+		d.done = append(d.done, id)
+		return nil, nil
+	}, nil
+}
+func (d *recordingDriver) SetLogger(*logger.Logger) {}
+func (d *recordingDriver) SetDryRun(bool)           {}
+
+func TestRunConcurrently(t *testing.T) {
+	tpl := MustParse("delete instance id=i-1\ndelete instance id=i-2\ndelete instance id=i-3\ndelete subnet id=sub-1\ndelete vpc id=vpc-1")
+	deps := [][]int{nil, nil, nil, {0, 1, 2}, {3}}
+
+	t.Run("independent commands in parallel", func(t *testing.T) {
+		d := &recordingDriver{}
+		done, err := tpl.RunConcurrently(d, deps, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := d.maxRunning, 2; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		if got, want := d.done[3:], []string{"sub-1", "vpc-1"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		var ids []string
+		for _, cmd := range done.CommandNodesIterator() {
+			ids = append(ids, fmt.Sprint(cmd.Params["id"]))
+		}
+		if got, want := ids, d.done; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("no command started after a failure", func(t *testing.T) {
+		d := &recordingDriver{failOn: "i-2"}
+		done, err := tpl.RunConcurrently(d, deps, 3)
+		if err == nil {
+			t.Fatal("expected error got none")
+		}
+		if got, want := len(d.done), 2; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		executed := NewTemplateExecution(done)
+		if got, want := len(executed.Executed), 3; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		if got, want := executed.Executed[2].Err, "cannot delete i-2"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("invalid dependencies", func(t *testing.T) {
+		if _, err := tpl.RunConcurrently(&noopDriver{}, [][]int{nil, {1}, nil, nil, nil}, 2); err == nil {
+			t.Fatal("expected error got none")
+		}
+	})
+}
@@ -105,3 +105,45 @@ func (d *mockDriver) SetLogger(l *logger.Logger) { d.logger = l }
 func (d *mockDriver) Lookup(lookups ...string) (driverFn driver.DriverFn, err error) {
 	return d.lookupFn(lookups...)
 }
+
+func TestReadOnlyDriver(t *testing.T) {
+	var called []string
+	mock := &mockDriver{
+		lookupFn: func(lookups ...string) (driver.DriverFn, error) {
+			return func(map[string]interface{}) (interface{}, error) {
+				called = append(called, lookups[0]+lookups[1])
+				return nil, nil
+			}, nil
+		},
+	}
+	d := driver.NewReadOnlyDriver(mock)
+
+	d.SetDryRun(true)
+	fn, err := d.Lookup("create", "instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn(nil)
+	if !mock.dryRun {
+		t.Fatal("expected dry run to be forwarded")
+	}
+
+	d.SetDryRun(false)
+	if _, err := d.Lookup("create", "instance"); err == nil {
+		t.Fatal("expected create instance to be refused")
+	} else if got, want := err.Error(), "read-only mode: 'create instance' refused as it changes resources"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if _, err := d.Lookup("delete", "vpc"); err == nil {
+		t.Fatal("expected delete vpc to be refused")
+	}
+	fn, err = d.Lookup("check", "instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn(nil)
+
+	if got, want := called, []string{"createinstance", "checkinstance"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
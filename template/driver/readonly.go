@@ -0,0 +1,65 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// readOnlyActions are the template actions that do not change resources
+var readOnlyActions = map[string]bool{
+	"check": true,
+}
+
+// IsReadOnlyAction tells whether a template action leaves resources untouched
+func IsReadOnlyAction(action string) bool {
+	return readOnlyActions[action]
+}
+
+// ReadOnlyError is returned when running an action changing resources in read-only mode
+type ReadOnlyError struct {
+	Statement string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("read-only mode: '%s' refused as it changes resources", e.Statement)
+}
+
+type readOnlyDriver struct {
+	Driver
+	dryRun bool
+}
+
+// NewReadOnlyDriver refuses to run actions changing resources. Dry runs
+// are still allowed so that templates can be validated and planned
+func NewReadOnlyDriver(d Driver) Driver {
+	return &readOnlyDriver{Driver: d}
+}
+
+func (d *readOnlyDriver) SetDryRun(dry bool) {
+	d.dryRun = dry
+	d.Driver.SetDryRun(dry)
+}
+
+func (d *readOnlyDriver) Lookup(lookups ...string) (DriverFn, error) {
+	fn, err := d.Driver.Lookup(lookups...)
+	if err != nil || d.dryRun || (len(lookups) > 0 && IsReadOnlyAction(lookups[0])) {
+		return fn, err
+	}
+	return nil, &ReadOnlyError{Statement: strings.Join(lookups, " ")}
+}
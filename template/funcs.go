@@ -0,0 +1,149 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/wallix/awless/template/ast"
+)
+
+// Funcs are the built-in functions callable in param values (ex: name=uuid()),
+// evaluated when the template is compiled
+var Funcs = map[string]func(args ...interface{}) (interface{}, error){
+	"now":        nowFunc,
+	"uuid":       uuidFunc,
+	"upper":      upperFunc,
+	"cidrsubnet": cidrSubnetFunc,
+}
+
+// EvaluateFuncs replaces the function values of the params by their results
+func (s *Template) EvaluateFuncs() error {
+	for _, cmd := range s.CommandNodesIterator() {
+		for key, val := range cmd.Params {
+			fn, ok := val.(*ast.FuncValue)
+			if !ok {
+				continue
+			}
+			res, err := evaluateFunc(fn)
+			if err != nil {
+				return ast.LocateError(cmd.ParamPositions[key], fmt.Errorf("%s %s: %s: %s", cmd.Action, cmd.Entity, key, err))
+			}
+			cmd.Params[key] = res
+		}
+	}
+	return nil
+}
+
+func evaluateFunc(fn *ast.FuncValue) (interface{}, error) {
+	f, ok := Funcs[fn.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function '%s'", fn.Name)
+	}
+	for _, arg := range fn.Args {
+		if hole, ok := arg.(ast.Hole); ok {
+			return nil, fmt.Errorf("%s: missing value for hole %s", fn, hole)
+		}
+	}
+	res, err := f(fn.Args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", fn, err)
+	}
+	return res, nil
+}
+
+// nowFunc returns the current UTC time as RFC3339 or with the given Go layout (ex: now("20060102"))
+func nowFunc(args ...interface{}) (interface{}, error) {
+	layout := time.RFC3339
+	switch len(args) {
+	case 0:
+	case 1:
+		layout = fmt.Sprint(args[0])
+	default:
+		return nil, errors.New("expecting at most 1 argument: layout")
+	}
+	return time.Now().UTC().Format(layout), nil
+}
+
+func uuidFunc(args ...interface{}) (interface{}, error) {
+	if len(args) > 0 {
+		return nil, errors.New("expecting no argument")
+	}
+	return uuid.New(), nil
+}
+
+func upperFunc(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, errors.New("expecting 1 argument: string")
+	}
+	return strings.ToUpper(fmt.Sprint(args[0])), nil
+}
+
+// cidrSubnetFunc computes the CIDR of a subnet of a network (ex: cidrsubnet(10.0.0.0/16,8,2) gives 10.0.2.0/24),
+// adding newbits to the prefix of the network and numbering the subnet with netnum
+func cidrSubnetFunc(args ...interface{}) (interface{}, error) {
+	if len(args) != 3 {
+		return nil, errors.New("expecting 3 arguments: prefix, newbits, netnum")
+	}
+	_, network, err := net.ParseCIDR(fmt.Sprint(args[0]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prefix '%v'", args[0])
+	}
+	newbits, err := intArg(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid newbits: %s", err)
+	}
+	netnum, err := intArg(args[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid netnum: %s", err)
+	}
+	ones, bits := network.Mask.Size()
+	if newbits < 0 || ones+newbits > bits {
+		return nil, fmt.Errorf("cannot extend prefix /%d by %d bits", ones, newbits)
+	}
+	max := new(big.Int).Lsh(big.NewInt(1), uint(newbits))
+	if netnum < 0 || big.NewInt(int64(netnum)).Cmp(max) >= 0 {
+		return nil, fmt.Errorf("netnum %d does not fit in %d bits", netnum, newbits)
+	}
+
+	num := new(big.Int).SetBytes(network.IP)
+	num.Or(num, new(big.Int).Lsh(big.NewInt(int64(netnum)), uint(bits-ones-newbits)))
+	ip := make(net.IP, len(network.IP))
+	numBytes := num.Bytes()
+	copy(ip[len(ip)-len(numBytes):], numBytes)
+
+	subnet := &net.IPNet{IP: ip, Mask: net.CIDRMask(ones+newbits, bits)}
+	return subnet.String(), nil
+}
+
+func intArg(arg interface{}) (int, error) {
+	switch v := arg.(type) {
+	case int:
+		return v, nil
+	case string:
+		return strconv.Atoi(v)
+	default:
+		return 0, fmt.Errorf("'%v' is not an integer", arg)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/template/ast"
+)
+
+func TestEvaluateFuncs(t *testing.T) {
+	tpl := MustParse(`create instance name=uuid()
+create subnet cidr=cidrsubnet(10.0.0.0/16, 8, {index})
+create tag key=upper("env name") value=now( "2006" )`)
+
+	if got, want := tpl.CommandNodesIterator()[1].Params["cidr"], (&ast.FuncValue{Name: "cidrsubnet", Args: []interface{}{"10.0.0.0/16", 8, ast.Hole("index")}}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+	if got, want := tpl.CommandNodesIterator()[1].String(), "create subnet cidr=cidrsubnet(10.0.0.0/16,8,{index})"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := tpl.GetHolesValuesSet(), []string{"index"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if err := (&Template{AST: tpl.Clone()}).EvaluateFuncs(); err == nil {
+		t.Fatal("expected error got none")
+	}
+
+	if _, err := tpl.ResolveHoles(map[string]interface{}{"index": "3"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tpl.EvaluateFuncs(); err != nil {
+		t.Fatal(err)
+	}
+	cmds := tpl.CommandNodesIterator()
+	if got := cmds[0].Params["name"].(string); !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`).MatchString(got) {
+		t.Fatalf("got %s, want uuid", got)
+	}
+	if got, want := cmds[1].Params["cidr"], "10.0.3.0/24"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := cmds[2].Params["key"], "ENV NAME"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := cmds[2].Params["value"], time.Now().UTC().Format("2006"); got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if err := MustParse("create instance name=lower(WEB)").EvaluateFuncs(); err == nil {
+		t.Fatal("expected error got none")
+	}
+}
+
+func TestCidrSubnetFunc(t *testing.T) {
+	tcases := []struct {
+		args   []interface{}
+		expect string
+		err    bool
+	}{
+		{args: []interface{}{"10.0.0.0/16", 8, 2}, expect: "10.0.2.0/24"},
+		{args: []interface{}{"10.0.0.0/16", 4, 15}, expect: "10.0.240.0/20"},
+		{args: []interface{}{"10.1.2.3/16", "0", "0"}, expect: "10.1.0.0/16"},
+		{args: []interface{}{"2001:db8::/32", 16, 1}, expect: "2001:db8:1::/48"},
+		{args: []interface{}{"10.0.0.0/16", 8, 256}, err: true},
+		{args: []interface{}{"10.0.0.0/30", 8, 0}, err: true},
+		{args: []interface{}{"10.0.0.0", 8, 0}, err: true},
+		{args: []interface{}{"10.0.0.0/16", 8}, err: true},
+	}
+	for i, tcase := range tcases {
+		res, err := cidrSubnetFunc(tcase.args...)
+		if tcase.err {
+			if err == nil {
+				t.Fatalf("%d: expected error got none", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+		if got, want := res, tcase.expect; got != want {
+			t.Fatalf("%d: got %v, want %v", i, got, want)
+		}
+	}
+}
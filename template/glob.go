@@ -0,0 +1,108 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template/ast"
+)
+
+// IsGlob tells whether the value is a string holding wildcards (ex: name=2016-*),
+// '*' matching any characters and '?' a single one
+func IsGlob(v interface{}) bool {
+	str, ok := v.(string)
+	return ok && strings.ContainsAny(str, "*?")
+}
+
+// GlobPrefix returns the part of the glob before its first wildcard, usable by the APIs listing resources by prefix
+func GlobPrefix(glob string) string {
+	if i := strings.IndexAny(glob, "*?"); i > -1 {
+		return glob[:i]
+	}
+	return glob
+}
+
+// MatchGlob tells whether the whole text matches the glob. Unlike path.Match, '*' also matches '/'
+func MatchGlob(glob, text string) bool {
+	var g, t int
+	star, next := -1, 0
+	for t < len(text) {
+		switch {
+		case g < len(glob) && glob[g] == '*':
+			star, next = g, t
+			g++
+		case g < len(glob) && (glob[g] == '?' || glob[g] == text[t]):
+			g++
+			t++
+		case star > -1:
+			next++
+			g, t = star+1, next
+		default:
+			return false
+		}
+	}
+	for g < len(glob) && glob[g] == '*' {
+		g++
+	}
+	return g == len(glob)
+}
+
+// ExpandGlobs replaces the statements whose id is a glob (ex: stop instance id=web-*) by a statement
+// per resource of the entity whose id or name matches the glob in the graph given by lookup
+func (s *Template) ExpandGlobs(lookup LookupGraphFunc) error {
+	for _, sts := range s.Statements {
+		if decl, ok := sts.Node.(*ast.DeclarationNode); ok {
+			if cmd, ok := decl.Expr.(*ast.CommandNode); ok && IsGlob(cmd.Params["id"]) {
+				return ast.LocateError(sts.Pos, fmt.Errorf("%s: id '%s' matching several resources cannot be assigned", decl, cmd.Params["id"]))
+			}
+		}
+	}
+	return s.ExpandCommands("id", func(cmd *ast.CommandNode) ([]interface{}, error) {
+		glob, ok := cmd.Params["id"].(string)
+		if !ok || !IsGlob(glob) {
+			return nil, nil
+		}
+		g, ok := lookup(cmd.Entity)
+		if !ok {
+			return nil, ast.LocateError(cmd.ParamPositions["id"], fmt.Errorf("%s %s: cannot match id '%s': no resources known", cmd.Action, cmd.Entity, glob))
+		}
+		resources, err := g.GetAllResources(graph.ResourceType(cmd.Entity))
+		if err != nil {
+			return nil, err
+		}
+		var ids []string
+		for _, res := range resources {
+			name, _ := res.Properties["Name"].(string)
+			if MatchGlob(glob, res.Id()) || (name != "" && MatchGlob(glob, name)) {
+				ids = append(ids, res.Id())
+			}
+		}
+		if len(ids) == 0 {
+			return nil, ast.LocateError(cmd.ParamPositions["id"], fmt.Errorf("%s %s: no %s matching id '%s'", cmd.Action, cmd.Entity, cmd.Entity, glob))
+		}
+		sort.Strings(ids)
+		var values []interface{}
+		for _, id := range ids {
+			values = append(values, id)
+		}
+		return values, nil
+	})
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wallix/awless/graph"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tcases := []struct {
+		glob, text string
+		match      bool
+	}{
+		{"2016-*", "2016-01/a.log", true},
+		{"2016-*", "2017-01", false},
+		{"*.log", "a/b.log", true},
+		{"*.log", "a.log.gz", false},
+		{"web-??", "web-01", true},
+		{"web-??", "web-1", false},
+		{"a*b*c", "axxbyybzc", true},
+		{"a*b*c", "axxbyy", false},
+		{"*", "", true},
+		{"?", "", false},
+	}
+	for _, tcase := range tcases {
+		if got, want := MatchGlob(tcase.glob, tcase.text), tcase.match; got != want {
+			t.Fatalf("%s on %s: got %t, want %t", tcase.glob, tcase.text, got, want)
+		}
+	}
+	if got, want := GlobPrefix("logs/2016-*.log"), "logs/2016-"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestExpandGlobs(t *testing.T) {
+	g := graph.NewGraph()
+	g.Unmarshal([]byte(`
+      /instance<inst_1> "has_type"@[] "/instance"^^type:text
+      /instance<inst_1> "property"@[] "{"Key":"Name","Value":"web-1"}"^^type:text
+      /instance<inst_2> "has_type"@[] "/instance"^^type:text
+      /instance<inst_2> "property"@[] "{"Key":"Name","Value":"db-1"}"^^type:text
+      /instance<inst_3> "has_type"@[] "/instance"^^type:text
+      /instance<inst_3> "property"@[] "{"Key":"Name","Value":"web-2"}"^^type:text
+    `))
+	lookup := func(key string) (*graph.Graph, bool) { return g, true }
+
+	tpl := MustParse("stop instance id=web-*\ndelete storageobject bucket=logs key=2016-*\nstart instance id=inst_?")
+	if err := tpl.ExpandGlobs(lookup); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"stop instance id=inst_1",
+		"stop instance id=inst_3",
+		"delete storageobject bucket=logs key=2016-*",
+		"start instance id=inst_1",
+		"start instance id=inst_2",
+		"start instance id=inst_3",
+	}
+	if got, want := len(tpl.Statements), len(expected); got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	for i := range expected {
+		if got, want := tpl.CommandNodesIterator()[i].Params, MustParse(expected[i]).CommandNodesIterator()[0].Params; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %v, want %v", i, got, want)
+		}
+	}
+
+	if err := MustParse("stop instance id=app-*").ExpandGlobs(lookup); err == nil {
+		t.Fatal("expected error got none")
+	}
+	if err := MustParse("inst = stop instance id=web-*").ExpandGlobs(lookup); err == nil {
+		t.Fatal("expected error got none")
+	}
+}
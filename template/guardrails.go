@@ -0,0 +1,78 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// GuardrailsValidator enforces the policy of an organization on the statements of templates.
+// Rules are statements such as 'delete vpc' where action and entity accept wildcards ('delete *')
+type GuardrailsValidator struct {
+	Deny, Allow []string
+	// allowed values of params per entity.param (ex: instance.type)
+	Values map[string][]string
+}
+
+func (v *GuardrailsValidator) Execute(t *Template) (errs []error) {
+	for _, cmd := range t.CommandNodesIterator() {
+		statement := fmt.Sprintf("%s %s", cmd.Action, cmd.Entity)
+		if rule, ok := matchRule(v.Deny, cmd.Action, cmd.Entity); ok {
+			errs = append(errs, fmt.Errorf("%s: denied by guardrails policy (rule '%s')", statement, rule))
+			continue
+		}
+		if len(v.Allow) > 0 {
+			if _, ok := matchRule(v.Allow, cmd.Action, cmd.Entity); !ok {
+				errs = append(errs, fmt.Errorf("%s: not allowed by guardrails policy (allowed: %s)", statement, strings.Join(v.Allow, ", ")))
+				continue
+			}
+		}
+		for param, value := range cmd.Params {
+			allowed, ok := v.Values[fmt.Sprintf("%s.%s", cmd.Entity, param)]
+			if !ok {
+				continue
+			}
+			values, isList := value.([]interface{})
+			if !isList {
+				values = []interface{}{value}
+			}
+			for _, val := range values {
+				if !sliceContains(fmt.Sprint(val), allowed) {
+					errs = append(errs, fmt.Errorf("%s: %s '%v' not allowed by guardrails policy (allowed: %s)", statement, param, val, strings.Join(allowed, ", ")))
+				}
+			}
+		}
+	}
+	return
+}
+
+func matchRule(rules []string, action, entity string) (string, bool) {
+	for _, rule := range rules {
+		splits := strings.Fields(rule)
+		if len(splits) != 2 {
+			continue
+		}
+		actionOk, _ := path.Match(splits[0], action)
+		entityOk, _ := path.Match(splits[1], entity)
+		if actionOk && entityOk {
+			return rule, true
+		}
+	}
+	return "", false
+}
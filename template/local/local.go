@@ -0,0 +1,151 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package local runs the template statements executed on the operator's
+// machine rather than against a cloud, such as `run local cmd="..."`
+package local
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template"
+	"github.com/wallix/awless/template/ast"
+	"github.com/wallix/awless/template/driver"
+)
+
+func init() {
+	ast.RegisterEmbeddedQuoter("run", "local", "cmd", shellQuote)
+}
+
+// TemplatesDefinitions holds the definitions of the statements run locally
+var TemplatesDefinitions = map[string]template.TemplateDefinition{
+	"runlocal": {
+		Action:         "run",
+		Entity:         "local",
+		RequiredParams: []string{"cmd"},
+		ExtraParams:    []string{"dir"},
+		ParamTypes:     map[string]string{"cmd": "string", "dir": "string"},
+	},
+}
+
+// Driver runs local commands. Lookups of other statements return driver.ErrDriverFnNotFound
+// so that it can be combined with cloud drivers in a driver.MultiDriver
+type Driver struct {
+	dryRun         bool
+	logger         *logger.Logger
+	stdout, stderr io.Writer
+}
+
+func NewDriver() *Driver {
+	return &Driver{logger: logger.DiscardLogger, stdout: os.Stdout, stderr: os.Stderr}
+}
+
+func (d *Driver) SetDryRun(dry bool)         { d.dryRun = dry }
+func (d *Driver) SetLogger(l *logger.Logger) { d.logger = l }
+
+func (d *Driver) Lookup(lookups ...string) (driver.DriverFn, error) {
+	if len(lookups) != 2 || lookups[0] != "run" || lookups[1] != "local" {
+		return nil, driver.ErrDriverFnNotFound
+	}
+	if d.dryRun {
+		return d.Run_Local_DryRun, nil
+	}
+	return d.Run_Local, nil
+}
+
+// Run_Local_DryRun checks that the command line is given and that its program can be found
+func (d *Driver) Run_Local_DryRun(params map[string]interface{}) (interface{}, error) {
+	line, err := commandLine(params)
+	if err != nil {
+		d.logger.Errorf("dry run: run local error: %s", err)
+		return nil, err
+	}
+	if program := strings.Fields(line)[0]; !strings.ContainsAny(program, "{}$'\"") {
+		if _, err := exec.LookPath(program); err != nil {
+			err = fmt.Errorf("run local: %s", err)
+			d.logger.Errorf("dry run: %s", err)
+			return nil, err
+		}
+	}
+	d.logger.Verbose("full dry run: run local ok")
+	return nil, nil
+}
+
+// Run_Local runs the command line through the shell of the operator's machine, streaming its
+// output. The values of the holes and references embedded in the command line are quoted (see shellQuote). The trimmed standard output is returned so that it can be referenced by later statements.
+// A non-zero exit code fails the statement, and stops the template run
+func (d *Driver) Run_Local(params map[string]interface{}) (interface{}, error) {
+	line, err := commandLine(params)
+	if err != nil {
+		d.logger.Errorf("run local error: %s", err)
+		return nil, err
+	}
+
+	cmd := shellCommand(line)
+	if dir, ok := params["dir"]; ok {
+		cmd.Dir = fmt.Sprint(dir)
+	}
+	var out bytes.Buffer
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = io.MultiWriter(d.stdout, &out)
+	cmd.Stderr = d.stderr
+
+	d.logger.Verbosef("run local: %s", line)
+	if err := cmd.Run(); err != nil {
+		err = fmt.Errorf("run local '%s': %s", line, err)
+		d.logger.Errorf("%s", err)
+		return nil, err
+	}
+	d.logger.Infof("run local '%s' done", line)
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+func commandLine(params map[string]interface{}) (string, error) {
+	cmd, ok := params["cmd"]
+	if !ok {
+		return "", errors.New("missing required param 'cmd'")
+	}
+	line := strings.TrimSpace(fmt.Sprint(cmd))
+	if line == "" {
+		return "", errors.New("empty 'cmd' param")
+	}
+	return line, nil
+}
+
+func shellCommand(line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", line)
+	}
+	return exec.Command("sh", "-c", line)
+}
+
+// shellQuote quotes a value filling a hole or a reference of a command line as a single shell word,
+// so that values holding spaces or shell syntax (ex: "a; rm -rf ~") are passed as is
+func shellQuote(s string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + strings.Replace(s, `"`, `""`, -1) + `"`
+	}
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
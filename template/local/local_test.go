@@ -0,0 +1,107 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/wallix/awless/template"
+	"github.com/wallix/awless/template/driver"
+)
+
+func TestRunLocal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("commands are run with sh")
+	}
+	var stdout, stderr bytes.Buffer
+	d := NewDriver()
+	d.stdout, d.stderr = &stdout, &stderr
+
+	t.Run("run", func(t *testing.T) {
+		tpl, err := template.Parse(`out = run local cmd="echo @{greeting} from $(pwd)" dir=/
+run local cmd="echo ok > /dev/stderr; exit 3"
+run local cmd="echo never"`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = tpl.ResolveHoles(map[string]interface{}{"greeting": "hello"}); err != nil {
+			t.Fatal(err)
+		}
+
+		ran, err := tpl.Run(d)
+		if err == nil || !strings.Contains(err.Error(), "exit status 3") {
+			t.Fatalf("got %v, want exit status error", err)
+		}
+		if got, want := stdout.String(), "hello from /\n"; got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+		if got, want := stderr.String(), "ok\n"; got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+		if got, want := ran.CommandNodesIterator()[0].CmdResult, "hello from /"; got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got, want := ran.CommandNodesIterator()[2].CmdResult, interface{}(nil); got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("quoted values", func(t *testing.T) {
+		stdout.Reset()
+		tpl, err := template.Parse(`name = run local cmd="printf %s @{msg}"
+run local cmd="printf '[%s]' $name @{msg}"`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = tpl.ResolveHoles(map[string]interface{}{"msg": "it's; echo injected $HOME"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = tpl.Run(d); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := stdout.String(), "it's; echo injected $HOME[it's; echo injected $HOME][it's; echo injected $HOME]"; got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		d.SetDryRun(true)
+		defer d.SetDryRun(false)
+		fn, err := d.Lookup("run", "local")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = fn(map[string]interface{}{"cmd": "sh -c true"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = fn(map[string]interface{}{"cmd": "awless-unknown-program --flag"}); err == nil {
+			t.Fatal("expected error for unknown program")
+		}
+		if _, err = fn(map[string]interface{}{"dir": "/"}); err == nil {
+			t.Fatal("expected error for missing cmd")
+		}
+	})
+
+	t.Run("other statements", func(t *testing.T) {
+		if _, err := d.Lookup("create", "instance"); err != driver.ErrDriverFnNotFound {
+			t.Fatalf("got %v, want %v", err, driver.ErrDriverFnNotFound)
+		}
+	})
+}
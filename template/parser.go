@@ -22,10 +22,13 @@ func Parse(text string) (*Template, error) {
 	p := &ast.Peg{AST: &ast.AST{}, Buffer: string(text), Pretty: true}
 	p.Init()
 
-	if err := p.Parse(); err != nil {
+	if err := p.ParseAll(); err != nil {
 		return nil, err
 	}
 	p.Execute()
+	if err := p.AST.BuildError(); err != nil {
+		return nil, err
+	}
 
 	return &Template{AST: p.AST}, nil
 }
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/wallix/awless/template/ast"
@@ -74,6 +75,32 @@ func TestTemplateParsing(t *testing.T) {
 					return nil
 				},
 			},
+			{
+				input: "create vpc # créer le réseau 🚀\ncreate subnet",
+				verifyFn: func(tpl *Template) error {
+					if got, want := len(tpl.Statements), 2; got != want {
+						t.Fatalf("got %d, want %d", got, want)
+					}
+					return nil
+				},
+			},
+			{
+				input: "create vpc cidr=10.0.0.0/16 # main vpc \nsub = create subnet cidr=10.0.0.0/24 //public\n# whole line\ncreate instance",
+				verifyFn: func(tpl *Template) error {
+					if got, want := len(tpl.Statements), 3; got != want {
+						t.Fatalf("got %d, want %d", got, want)
+					}
+					for i, want := range []string{"main vpc", "public", ""} {
+						if got := tpl.Statements[i].Comment; got != want {
+							t.Fatalf("%d: got %q, want %q", i, got, want)
+						}
+					}
+					if got, want := tpl.String(), "create vpc cidr=10.0.0.0/16 # main vpc\nsub = create subnet cidr=10.0.0.0/24 # public\ncreate instance "; got != want {
+						t.Fatalf("got %q, want %q", got, want)
+					}
+					return nil
+				},
+			},
 			{
 				input: "create vpc \n//my comment\ncreate subnet",
 				verifyFn: func(tpl *Template) error {
@@ -103,6 +130,201 @@ func TestTemplateParsing(t *testing.T) {
 		}
 	})
 
+	t.Run("Glob values", func(t *testing.T) {
+		tpl, err := Parse("delete storageobject bucket=logs key=2016-* name=web-?? all=* enabled=true*")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]interface{}{"bucket": "logs", "key": "2016-*", "name": "web-??", "all": "*", "enabled": "true*"}
+		if got, want := tpl.CommandNodesIterator()[0].Params, expected; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %#v, want %#v", got, want)
+		}
+	})
+
+	t.Run("Heredoc values", func(t *testing.T) {
+		tpl, err := Parse("create policy name=s3 description=read document=<<EOF\n{\n  \"Version\": \"2012-10-17\"\n}\n  EOF\ncreate instance userdata=<<SH_1\r\n#!/bin/sh\r\necho EOF\r\nSH_1\ncreate tag value=<<EOF\nEOF")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(tpl.Statements), 3; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		expected := []map[string]interface{}{
+			{"name": "s3", "document": "{\n  \"Version\": \"2012-10-17\"\n}", "description": "read"},
+			{"userdata": "#!/bin/sh\r\necho EOF"},
+			{"value": ""},
+		}
+		for i, cmd := range tpl.CommandNodesIterator() {
+			if got, want := cmd.Params, expected[i]; !reflect.DeepEqual(got, want) {
+				t.Fatalf("%d: got %q, want %q", i, got, want)
+			}
+		}
+		if _, err := Parse("create policy document=<<EOF\n{}\nEO"); err == nil {
+			t.Fatal("expected error got none")
+		}
+	})
+
+	t.Run("Positions", func(t *testing.T) {
+		tpl, err := Parse("# network\n\nréseau = create vpc cidr=10.0.0.0/16 \\\n  name=main\n  repeat 2: create subnet vpc=$réseau document=<<EOF\nline\nEOF\ndefaults = type=t2.micro\ncreate instance ...defaults count=1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []struct {
+			pos    ast.Position
+			params map[string]ast.Position
+		}{
+			{pos: ast.Position{Line: 3, Column: 1}, params: map[string]ast.Position{"cidr": {Line: 3, Column: 21}, "name": {Line: 4, Column: 3}}},
+			{pos: ast.Position{Line: 5, Column: 13}, params: map[string]ast.Position{"vpc": {Line: 5, Column: 27}, "document": {Line: 5, Column: 39}}},
+			{pos: ast.Position{Line: 8, Column: 1}, params: map[string]ast.Position{"type": {Line: 8, Column: 12}}},
+			{pos: ast.Position{Line: 9, Column: 1}, params: map[string]ast.Position{"type": {Line: 8, Column: 12}, "count": {Line: 9, Column: 29}}},
+		}
+		for i, stat := range tpl.Statements {
+			if got, want := stat.Pos, expected[i].pos; got != want {
+				t.Fatalf("%d: got %s, want %s", i+1, got, want)
+			}
+			var cmd *ast.CommandNode
+			switch n := stat.Node.(type) {
+			case *ast.DeclarationNode:
+				cmd = n.Expr.(*ast.CommandNode)
+			case *ast.CommandNode:
+				cmd = n
+			case *ast.ParamSetNode:
+				cmd = n.Params
+			}
+			if got, want := cmd.ParamPositions, expected[i].params; !reflect.DeepEqual(got, want) {
+				t.Fatalf("%d: got %v, want %v", i+1, got, want)
+			}
+		}
+	})
+
+	t.Run("Param sets", func(t *testing.T) {
+		tpl, err := Parse(`webdefaults = type=t2.micro count=1 keypair={key} # shared by web instances
+sub = create subnet
+create instance ...webdefaults subnet=$sub type=t3.small
+web = create instance name?=@web ...webdefaults
+tagged = ...webdefaults tags={Env:prod}
+create instance ...tagged`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(tpl.Statements), 6; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		set, ok := tpl.Statements[0].Node.(*ast.ParamSetNode)
+		if !ok {
+			t.Fatalf("got %T, want param set", tpl.Statements[0].Node)
+		}
+		if got, want := set.Ident, "webdefaults"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		if got, want := tpl.Statements[0].Comment, "shared by web instances"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		cmds := tpl.CommandNodesIterator()
+		if got, want := len(cmds), 4; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		if err := assertCommandNode(cmds[1], "create", "instance",
+			map[string]string{"subnet": "sub"},
+			map[string]interface{}{"type": "t3.small", "count": 1},
+			map[string]string{"keypair": "key"},
+			map[string]string{},
+		); err != nil {
+			t.Fatal(err)
+		}
+		if err := assertCommandNode(cmds[2], "create", "instance",
+			map[string]string{},
+			map[string]interface{}{"type": "t2.micro", "count": 1},
+			map[string]string{"keypair": "key"},
+			map[string]string{"name": "web"},
+		); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := cmds[2].Optionals, map[string]bool{"name": true}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got, want := cmds[3].Params["tags"], map[string]interface{}{"Env": "prod"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got, want := cmds[3].Params["type"], "t2.micro"; got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+
+		if _, err := Parse("create instance ...webdefaults"); err == nil {
+			t.Fatal("expected error got none")
+		}
+	})
+
+	t.Run("Unicode identifiers", func(t *testing.T) {
+		tpl, err := Parse("réseau = create vpc\ncreate subnet vpc=$réseau name={nom_région} 名前=web desc=\"@{région} $réseau\"\ncafe\u0301 = create vpc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := tpl.Statements[0].Node.(*ast.DeclarationNode).Ident, "réseau"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		if got, want := tpl.Statements[2].Node.(*ast.DeclarationNode).Ident, "cafe\u0301"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		cmd := tpl.Statements[1].Node.(*ast.CommandNode)
+		if got, want := cmd.Refs, map[string]string{"vpc": "réseau"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got, want := cmd.Holes, map[string]string{"name": "nom_région"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got, want := cmd.Params["名前"], "web"; got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if got, want := cmd.EmbeddedHoles(), []string{"région"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		cmd.ProcessRefs(map[string]interface{}{"réseau": "vpc-1"})
+		if got, want := cmd.Params["desc"], "@{région} vpc-1"; got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Report errors at character positions", func(t *testing.T) {
+		_, err := Parse("# déploiement\ncreate vpc cidr=10.0.0.0/16 é=€")
+		if err == nil {
+			t.Fatal("expected error got none")
+		}
+		if got, want := err.Error(), "line 2 symbol 30 - line 2 symbol 31"; !strings.Contains(got, want) {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		_, err = Parse("réseau = create vpc\n日本 = create subnet vpc=$réseau ∑=1")
+		if err == nil {
+			t.Fatal("expected error got none")
+		}
+		if got, want := err.Error(), "line 2 symbol 31 - line 2 symbol 32"; !strings.Contains(got, want) {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		if _, err = Parse("create vpc\xff create subnet"); err == nil {
+			t.Fatal("expected error on invalid UTF-8 got none")
+		}
+	})
+
+	t.Run("Report all errors", func(t *testing.T) {
+		_, err := Parse("create vpc cidr=[\ncreate subnet\n# é=€\ncreate vpc é=€\n!!!\ncreate instance name=web\ncreate policy document=<<EOF\n{}")
+		errs, ok := err.(ast.ParseErrors)
+		if !ok {
+			t.Fatalf("got %T, want ast.ParseErrors", err)
+		}
+		expected := []string{"line 1 symbol 16 - line 1 symbol 17", "line 4 symbol 13 - line 4 symbol 14", "line 5 symbol 0 - line 5 symbol 1", "line 8 symbol 0 - line 8 symbol 1"}
+		if got, want := len(errs), len(expected); got != want {
+			t.Fatalf("got %d, want %d: %s", got, want, err)
+		}
+		for i, want := range expected {
+			if got := errs[i].Error(); !strings.Contains(got, want) {
+				t.Fatalf("%d: got %s, want %s", i+1, got, want)
+			}
+		}
+		if got, want := err.Error(), errs[0].Error()+errs[1].Error()+errs[2].Error()+errs[3].Error(); got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	})
+
 	t.Run("Onliner statement", func(t *testing.T) {
 		tcases := []struct {
 			input    string
@@ -138,6 +360,78 @@ func TestTemplateParsing(t *testing.T) {
 					return assertAliases(n, map[string]string{"subnet": "my-subnet"})
 				},
 			},
+			{
+				input: `update instances name=web image=ami-12 batch=2`,
+				verifyFn: func(n ast.Node) error {
+					return assertCommandNode(n, "update", "instances", map[string]string{}, map[string]interface{}{"name": "web", "image": "ami-12", "batch": 2}, map[string]string{}, map[string]string{})
+				},
+			},
+			{
+				input: `run local cmd="ansible-playbook site.yml -i {inventory}"`,
+				verifyFn: func(n ast.Node) error {
+					return assertCommandNode(n, "run", "local", map[string]string{}, map[string]interface{}{"cmd": "ansible-playbook site.yml -i {inventory}"}, map[string]string{}, map[string]string{})
+				},
+			},
+			{
+				input: `create instance userdata=@./cloud-init.yaml subnet=@my-subnet`,
+				verifyFn: func(n ast.Node) error {
+					return assertCommandNode(n, "create", "instance", map[string]string{}, map[string]interface{}{"userdata": ast.FileValue("./cloud-init.yaml")}, map[string]string{}, map[string]string{"subnet": "my-subnet"})
+				},
+			},
+			{
+				input: `run local cmd="echo \"$HOME\"\tdone\n"`,
+				verifyFn: func(n ast.Node) error {
+					return assertParams(n, map[string]interface{}{"cmd": "echo \"$HOME\"\tdone\n"})
+				},
+			},
+			{
+				input: `create instance securitygroups=[sg-1, sg-2,sg-3] ports=80,443 type=t2.micro`,
+				verifyFn: func(n ast.Node) error {
+					return assertParams(n, map[string]interface{}{"securitygroups": []interface{}{"sg-1", "sg-2", "sg-3"}, "ports": []interface{}{80, 443}, "type": "t2.micro"})
+				},
+			},
+			{
+				input: `create instance subnets=[] names=["web 1","web,2"] zones=[eu-west-1a]`,
+				verifyFn: func(n ast.Node) error {
+					return assertParams(n, map[string]interface{}{"subnets": []interface{}{}, "names": []interface{}{"web 1", "web,2"}, "zones": []interface{}{"eu-west-1a"}})
+				},
+			},
+			{
+				input: `create tag resource=i-1 tags={Name:web, Env:prod,Team:"ops team",Count:2} extra={}`,
+				verifyFn: func(n ast.Node) error {
+					return assertParams(n, map[string]interface{}{"resource": "i-1", "tags": map[string]interface{}{"Name": "web", "Env": "prod", "Team": "ops team", "Count": 2}, "extra": map[string]interface{}{}})
+				},
+			},
+			{
+				input: `create instance lock=true publicip=false name=trueish flags=[true,no] opts={dns:false}`,
+				verifyFn: func(n ast.Node) error {
+					return assertParams(n, map[string]interface{}{"lock": true, "publicip": false, "name": "trueish", "flags": []interface{}{true, "no"}, "opts": map[string]interface{}{"dns": false}})
+				},
+			},
+			{
+				input: `create instance price=0.042 threshold=10.50 ip=10.0.0.1 prices=[0.5,1,1.10] opts={ratio:2.5}`,
+				verifyFn: func(n ast.Node) error {
+					return assertParams(n, map[string]interface{}{"price": 0.042, "threshold": "10.50", "ip": "10.0.0.1", "prices": []interface{}{0.5, 1, "1.10"}, "opts": map[string]interface{}{"ratio": 2.5}})
+				},
+			},
+			{
+				input: `create securitygroup protocol=-1 offset=-20 port=20-80 name=-web counts=[-1,2]`,
+				verifyFn: func(n ast.Node) error {
+					return assertParams(n, map[string]interface{}{"protocol": -1, "offset": -20, "port": "20-80", "name": "-web", "counts": []interface{}{-1, 2}})
+				},
+			},
+			{
+				input: `create route cidr=2001:DB8::1/32 ip=2001:0db8:0000:0000:0000:0000:0000:0001 any=::/0 local=::1 mapped=::ffff:10.0.0.1 time=10:30:00 arn=arn:aws:iam::0123:user/jdoe`,
+				verifyFn: func(n ast.Node) error {
+					return assertParams(n, map[string]interface{}{"cidr": "2001:db8::/32", "ip": "2001:db8::1", "any": "::/0", "local": "::1", "mapped": "10.0.0.1", "time": "10:30:00", "arn": "arn:aws:iam::0123:user/jdoe"})
+				},
+			},
+			{
+				input: `create instance name={env}+"-web-"+{ index } type=t2+.micro`,
+				verifyFn: func(n ast.Node) error {
+					return assertParams(n, map[string]interface{}{"name": ast.ConcatenationValue{ast.Hole("env"), "-web-", ast.Hole("index")}, "type": ast.ConcatenationValue{"t2", ".micro"}})
+				},
+			},
 			{
 				input: `delete vpc id={my-vpc-id}`,
 				verifyFn: func(n ast.Node) error {
@@ -245,6 +539,29 @@ create instance count=1 instance.type=t2.micro subnet=$mysubnet image=ami-9398d3
 					return err
 				},
 			},
+			{
+				input: "myinstance = create instance \\\n  count=1 type=t2.micro \\\r\n\tsubnet=$mysubnet \\\n  name=web\ncreate vpc cidr=10.0.0.0/24",
+				verifyFn: func(s *Template) error {
+					if got, want := len(s.Statements), 2; got != want {
+						return fmt.Errorf("statements: got %d, want %d", got, want)
+					}
+					err := assertDeclarationNode(s.Statements[0].Node, "myinstance", "create", "instance",
+						map[string]string{"subnet": "mysubnet"},
+						map[string]interface{}{"count": 1, "type": "t2.micro", "name": "web"},
+						map[string]string{},
+						map[string]string{},
+					)
+					if err != nil {
+						return err
+					}
+					return assertCommandNode(s.Statements[1].Node, "create", "vpc",
+						map[string]string{},
+						map[string]interface{}{"cidr": "10.0.0.0/24"},
+						map[string]string{},
+						map[string]string{},
+					)
+				},
+			},
 		}
 
 		for _, tcase := range tcases {
@@ -396,3 +713,27 @@ func isDeclarationNode(n ast.Node) error {
 	}
 	return nil
 }
+
+// Run with the parser before and after a grammar or runtime change to compare (ex: the rune based parser
+// preallocating its tokens took about 5 times longer and 50 times more memory to parse the one-liner)
+func BenchmarkParse(b *testing.B) {
+	benchs := []struct {
+		name, text string
+	}{
+		{name: "one-liner", text: "create instance name=my-instance subnet=@my-subnet image=ami-12345678 count=1"},
+		{name: "template", text: strings.Repeat(`subnet = create subnet cidr=10.0.0.0/24 vpc=@my-vpc name={subnet.name}
+create instance name=my-instance subnet=$subnet image=ami-12345678 securitygroup=sg-1
+update securitygroup id=@web inbound=authorize protocol=tcp cidr=0.0.0.0/0 portrange=443
+`, 10)},
+	}
+	for _, bench := range benchs {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := Parse(bench.text); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
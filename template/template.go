@@ -19,6 +19,8 @@ package template
 import (
 	"crypto/rand"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -35,19 +37,28 @@ func (s *Template) Run(d driver.Driver) (*Template, error) {
 	vars := map[string]interface{}{}
 
 	current := &Template{AST: s.Clone()}
+	if err := current.ExpandRepeats(); err != nil {
+		return current, err
+	}
+	if err := current.EvaluateFuncs(); err != nil {
+		return current, err
+	}
 
 	for _, sts := range current.Statements {
+		if sts.Repeat != nil {
+			return current, ast.LocateError(sts.Pos, fmt.Errorf("%s %s: missing count", sts.Repeat, sts.Node))
+		}
 		switch sts.Node.(type) {
 		case *ast.CommandNode:
 			cmd := sts.Node.(*ast.CommandNode)
 			fn, err := d.Lookup(cmd.Action, cmd.Entity)
 			if err != nil {
-				return current, err
+				return current, ast.LocateError(sts.Pos, err)
 			}
 			cmd.ProcessRefs(vars)
 
 			if cmd.CmdResult, cmd.CmdErr = fn(cmd.Params); cmd.CmdErr != nil {
-				return current, cmd.CmdErr
+				return current, ast.LocateError(sts.Pos, cmd.CmdErr)
 			}
 		case *ast.DeclarationNode:
 			ident := sts.Node.(*ast.DeclarationNode).Ident
@@ -57,12 +68,12 @@ func (s *Template) Run(d driver.Driver) (*Template, error) {
 				cmd := expr.(*ast.CommandNode)
 				fn, err := d.Lookup(cmd.Action, cmd.Entity)
 				if err != nil {
-					return current, err
+					return current, ast.LocateError(sts.Pos, err)
 				}
 				cmd.ProcessRefs(vars)
 
 				if cmd.CmdResult, cmd.CmdErr = fn(cmd.Params); cmd.CmdErr != nil {
-					return current, cmd.CmdErr
+					return current, ast.LocateError(sts.Pos, cmd.CmdErr)
 				}
 				vars[ident] = cmd.CmdResult
 			}
@@ -88,6 +99,14 @@ func (s *Template) Validate(rules ...Validator) (all []error) {
 	return
 }
 
+// UnmarshalJSON decodes the JSON form of the template AST, allocating the AST when unset
+func (s *Template) UnmarshalJSON(b []byte) error {
+	if s.AST == nil {
+		s.AST = &ast.AST{}
+	}
+	return s.AST.UnmarshalJSON(b)
+}
+
 func (s *Template) Visit(v Visitor) error {
 	return v.Visit(s.CommandNodesIterator())
 }
@@ -98,8 +117,16 @@ func (s *Template) GetHolesValuesSet() (values []string) {
 		for _, hole := range expr.Holes {
 			holes[hole] = true
 		}
+		for _, hole := range expr.EmbeddedHoles() {
+			holes[hole] = true
+		}
 	}
 	s.visitCommandNodes(each)
+	for _, sts := range s.Statements {
+		if sts.Repeat != nil && sts.Repeat.Hole != "" {
+			holes[sts.Repeat.Hole] = true
+		}
+	}
 
 	for k := range holes {
 		values = append(values, k)
@@ -152,6 +179,58 @@ func (s *Template) MergeParams(newParams map[string]interface{}) {
 	s.visitCommandNodes(each)
 }
 
+// LoadFiles replaces the file values of the params (ex: userdata=@./cloud-init.yaml) by the content
+// of the files, relative paths being relative to dir. Holes (ex: @{name}) and references in the content are then
+// resolved as in quoted values
+func (s *Template) LoadFiles(dir string) error {
+	for _, cmd := range s.CommandNodesIterator() {
+		for k, v := range cmd.Params {
+			file, ok := v.(ast.FileValue)
+			if !ok {
+				continue
+			}
+			path := string(file)
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				return ast.LocateError(cmd.ParamPositions[k], fmt.Errorf("%s %s: param %s: %s", cmd.Action, cmd.Entity, k, err))
+			}
+			cmd.Params[k] = string(content)
+		}
+	}
+	return nil
+}
+
+// FileValues returns the file values of the params not loaded yet
+func (s *Template) FileValues() (files []string) {
+	for _, cmd := range s.CommandNodesIterator() {
+		for _, v := range cmd.Params {
+			if file, ok := v.(ast.FileValue); ok {
+				files = append(files, string(file))
+			}
+		}
+	}
+	return
+}
+
+// GetHolesTypes returns the types declared by the holes (ex: {count as int}) indexed by hole
+func (s *Template) GetHolesTypes() map[string]string {
+	types := make(map[string]string)
+	s.visitCommandNodes(func(expr *ast.CommandNode) {
+		for key, typ := range expr.HoleTypes {
+			types[expr.Holes[key]] = typ
+		}
+	})
+	for _, sts := range s.Statements {
+		if sts.Repeat != nil && sts.Repeat.Hole != "" {
+			types[sts.Repeat.Hole] = "int"
+		}
+	}
+	return types
+}
+
 func (s *Template) ResolveHoles(refs ...map[string]interface{}) (map[string]interface{}, error) {
 	all := make(map[string]interface{})
 	for _, ref := range refs {
@@ -161,18 +240,46 @@ func (s *Template) ResolveHoles(refs ...map[string]interface{}) (map[string]inte
 	}
 
 	resolved := make(map[string]interface{})
-	each := func(expr *ast.CommandNode) {
-		processed := expr.ProcessHoles(all)
+	for _, expr := range s.CommandNodesIterator() {
+		processed, err := expr.ProcessHoles(all)
 		for key, v := range processed {
 			resolved[expr.Entity+"."+key] = v
 		}
+		if err != nil {
+			return resolved, err
+		}
+	}
+	for _, sts := range s.Statements {
+		if sts.Repeat == nil || sts.Repeat.Hole == "" {
+			continue
+		}
+		if val, ok := all[sts.Repeat.Hole]; ok {
+			count, err := ast.CastHoleValue("int", val)
+			if err != nil {
+				return resolved, ast.LocateError(sts.Pos, fmt.Errorf("%s: %s", sts.Repeat, err))
+			}
+			resolved["repeat."+sts.Repeat.Hole] = count
+			sts.Repeat = &ast.Repeat{Count: count.(int)}
+		}
+	}
+	if err := s.ExpandRepeats(); err != nil {
+		return resolved, err
 	}
-
-	s.visitCommandNodes(each)
 
 	return resolved, nil
 }
 
+// DropUnfilledOptionals removes the optional params (ex: keypair?={keypair}) whose holes have not been filled,
+// returning the removed params prefixed by their entity
+func (s *Template) DropUnfilledOptionals() (dropped []string) {
+	s.visitCommandNodes(func(expr *ast.CommandNode) {
+		for _, key := range expr.DropUnfilledOptionals() {
+			dropped = append(dropped, expr.Entity+"."+key)
+		}
+	})
+	return
+}
+
 func (s *Template) visitCommandNodes(fn func(n *ast.CommandNode)) {
 	for _, cmd := range s.CommandNodesIterator() {
 		fn(cmd)
@@ -198,6 +305,14 @@ func (s *Template) CommandNodesIterator() (nodes []*ast.CommandNode) {
 type TemplateExecution struct {
 	ID       string
 	Executed []*ExecutedStatement
+	// ID of the execution reverted by this one, if any
+	Reverted string  `json:",omitempty"`
+	Caller   *Caller `json:",omitempty"`
+}
+
+// Caller identifies who ran a template execution, for audit
+type Caller struct {
+	Arn, Account, SourceIP, Version string
 }
 
 type ExecutedStatement struct {
@@ -205,7 +320,7 @@ type ExecutedStatement struct {
 }
 
 func (ex *ExecutedStatement) IsRevertible() bool {
-	if ex.Err != "" {
+	if ex.Err != "" || strings.HasPrefix(ex.Line, "run ") {
 		return false
 	}
 	if ex.Result != "" {
@@ -272,8 +387,86 @@ func (te *TemplateExecution) lines() (lines []string) {
 	return
 }
 
+// revertActions maps the actions of revertible statements to the action undoing them
+var revertActions = map[string]string{
+	"create": "delete",
+	"start":  "stop",
+	"stop":   "start",
+	"detach": "attach",
+	"attach": "detach",
+}
+
+// RevertAction returns the action undoing the given one, if revertible
+func RevertAction(action string) (string, bool) {
+	revert, ok := revertActions[action]
+	return revert, ok
+}
+
 func (te *TemplateExecution) Revert() (*Template, error) {
+	statements, err := te.revertStatements()
+	if err != nil {
+		return nil, err
+	}
+
 	var lines []string
+	for _, st := range statements {
+		lines = append(lines, st.line)
+	}
+
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("revert: found nothing to revert from:\n%s\n(note: no revert provided for statement in error)", strings.Join(te.lines(), "\n"))
+	}
+
+	text := strings.Join(lines, "\n")
+	tpl, err := Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("revert: \n%s\n%s", text, err)
+	}
+
+	return tpl, nil
+}
+
+// RevertDependencies returns, for each command of the template returned by Revert, the indexes of
+// the previous commands to run before it: the reverts of statements using the resource it reverts
+// (ex: an instance created in a subnet is deleted before the subnet) or acting on the same resource
+func (te *TemplateExecution) RevertDependencies() ([][]int, error) {
+	statements, err := te.revertStatements()
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[int]*revertRefs)
+	for _, st := range statements {
+		if _, ok := refs[st.origin]; ok {
+			continue
+		}
+		n, err := ParseStatement(te.Executed[st.origin].Line)
+		if err != nil {
+			return nil, err
+		}
+		refs[st.origin] = newRevertRefs(n.(*ast.CommandNode), te.Executed[st.origin].Result)
+	}
+
+	deps := make([][]int, len(statements))
+	for k, st := range statements {
+		for l := 0; l < k; l++ {
+			previous := statements[l]
+			if previous.origin == st.origin || refs[previous.origin].uses(refs[st.origin]) {
+				deps[k] = append(deps[k], l)
+			}
+		}
+	}
+	return deps, nil
+}
+
+// revertStatement is a line of a revert template with the index of the executed statement it reverts
+type revertStatement struct {
+	line   string
+	origin int
+}
+
+func (te *TemplateExecution) revertStatements() ([]revertStatement, error) {
+	var statements []revertStatement
 
 	for i := len(te.Executed) - 1; i >= 0; i-- {
 		if exec := te.Executed[i]; exec.IsRevertible() {
@@ -285,20 +478,8 @@ func (te *TemplateExecution) Revert() (*Template, error) {
 			switch n.(type) {
 			case *ast.CommandNode:
 				node := n.(*ast.CommandNode)
-				var revertAction string
+				revertAction, _ := RevertAction(node.Action)
 				var params []string
-				switch node.Action {
-				case "create":
-					revertAction = "delete"
-				case "start":
-					revertAction = "stop"
-				case "stop":
-					revertAction = "start"
-				case "detach":
-					revertAction = "attach"
-				case "attach":
-					revertAction = "detach"
-				}
 
 				switch node.Action {
 				case "start", "stop", "attach", "detach":
@@ -309,10 +490,10 @@ func (te *TemplateExecution) Revert() (*Template, error) {
 					params = append(params, fmt.Sprintf("id=%s", exec.Result))
 				}
 
-				lines = append(lines, fmt.Sprintf("%s %s %s", revertAction, node.Entity, strings.Join(params, " ")))
+				statements = append(statements, revertStatement{fmt.Sprintf("%s %s %s", revertAction, node.Entity, strings.Join(params, " ")), i})
 
 				if node.Action == "create" && node.Entity == "instance" {
-					lines = append(lines, fmt.Sprintf("check instance id=%s state=terminated timeout=180", exec.Result))
+					statements = append(statements, revertStatement{fmt.Sprintf("check instance id=%s state=terminated timeout=180", exec.Result), i})
 				}
 			default:
 				return nil, fmt.Errorf("cannot parse [%s] as expression node", exec.Line)
@@ -320,15 +501,32 @@ func (te *TemplateExecution) Revert() (*Template, error) {
 		}
 	}
 
-	if len(lines) == 0 {
-		return nil, fmt.Errorf("revert: found nothing to revert from:\n%s\n(note: no revert provided for statement in error)", strings.Join(te.lines(), "\n"))
-	}
+	return statements, nil
+}
 
-	text := strings.Join(lines, "\n")
-	tpl, err := Parse(text)
-	if err != nil {
-		return nil, fmt.Errorf("revert: \n%s\n%s", text, err)
+// revertRefs are the resources referenced by an executed statement
+type revertRefs struct {
+	created, id string
+	values      map[string]bool
+}
+
+func newRevertRefs(n *ast.CommandNode, result string) *revertRefs {
+	refs := &revertRefs{values: make(map[string]bool)}
+	if n.Action == "create" {
+		refs.created = result
+	} else if id, ok := n.Params["id"]; ok {
+		refs.id = fmt.Sprint(id)
+	}
+	for _, v := range n.Params {
+		refs.values[fmt.Sprint(v)] = true
 	}
+	return refs
+}
 
-	return tpl, nil
+// uses returns whether a statement executed after the other one depends on it
+func (refs *revertRefs) uses(other *revertRefs) bool {
+	if other.created != "" {
+		return refs.values[other.created]
+	}
+	return refs.id != "" && refs.id == other.id
 }
@@ -26,6 +26,8 @@ type LookupTemplateDefFunc func(key string) (TemplateDefinition, bool)
 type TemplateDefinition struct {
 	Action, Entity, Api                      string
 	RequiredParams, ExtraParams, TagsMapping []string
+	// ParamTypes maps params to the kind of value they accept: string, integer, boolean, text or map
+	ParamTypes map[string]string
 }
 
 func (def TemplateDefinition) Name() string {
@@ -44,6 +46,14 @@ func (def TemplateDefinition) String() string {
 	return fmt.Sprintf("%s %s %s %s", def.Action, def.Entity, strings.Join(required, " "), strings.Join(tags, " "))
 }
 
+// ParamType returns the kind of value accepted by a param, defaulting to string
+func (def TemplateDefinition) ParamType(param string) string {
+	if t, ok := def.ParamTypes[param]; ok {
+		return t
+	}
+	return "string"
+}
+
 func (def TemplateDefinition) Required() []string {
 	return def.RequiredParams
 }
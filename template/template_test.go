@@ -17,9 +17,14 @@ limitations under the License.
 package template
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/oklog/ulid"
@@ -68,6 +73,27 @@ func TestRunDriverReportsInStatement(t *testing.T) {
 				{expString: "delete subnet id=sub-5f4g3hj"},
 			},
 		},
+		{
+			input:  `update instance group=[sg-1, "sg 2"]`,
+			driver: &noopDriver{},
+			lines: []line{
+				{expString: `update instance group=[sg-1,"sg 2"]`},
+			},
+		},
+		{
+			input:  `create tag tags={Team:"ops team", Name:web}`,
+			driver: &noopDriver{},
+			lines: []line{
+				{expString: `create tag tags={Name:web,Team:"ops team"}`},
+			},
+		},
+		{
+			input:  `create instance prices=[0.5,2.50,0.0000042]`,
+			driver: &noopDriver{},
+			lines: []line{
+				{expString: `create instance prices=[0.5,2.50,0.0000042]`},
+			},
+		},
 		{
 			input:  "create vpc cidr=10.0.0.0/25",
 			driver: &errorDriver{anErr},
@@ -98,6 +124,35 @@ func TestRunDriverReportsInStatement(t *testing.T) {
 	}
 }
 
+func TestRunLocatesErrors(t *testing.T) {
+	anErr := errors.New("missing required params 'subnet'")
+	templ, err := Parse("create vpc cidr=10.0.0.0/25\n\n  web = create instance name=web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = templ.Run(&errorDriver{anErr})
+	if got, want := err.Error(), "line 1 column 1: missing required params 'subnet'"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	templ.Statements = templ.Statements[1:]
+	_, err = templ.Run(&errorDriver{anErr})
+	posErr, ok := err.(*ast.PositionError)
+	if !ok {
+		t.Fatalf("got %T, want *ast.PositionError", err)
+	}
+	if got, want := posErr.Pos, (ast.Position{Line: 3, Column: 3}); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := posErr.Err, anErr; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	templ = &Template{AST: &ast.AST{Statements: []*ast.Statement{{Node: &ast.CommandNode{Action: "create", Entity: "vpc"}}}}}
+	if _, err = templ.Run(&errorDriver{anErr}); err != anErr {
+		t.Fatalf("got %v, want %v", err, anErr)
+	}
+}
+
 func TestNewTemplateExecutionFromTemplate(t *testing.T) {
 	temp, err := Parse("create vpc name=any\ncreate subnet ip=10.0.0.0\ndelete instance id=i-5d678\nstop instance id=i-5d678")
 	if err != nil {
@@ -259,6 +314,7 @@ func TestExecutedStatementIsRevertible(t *testing.T) {
 		{line: "stop instance", result: "any", revertible: true},
 		{line: "attach policy", result: "", revertible: true},
 		{line: "detach policy", result: "", revertible: true},
+		{line: "run local cmd=\"create vpc\"", result: "any", revertible: false},
 	}
 
 	for _, tc := range tcases {
@@ -514,6 +570,279 @@ func TestResolveHoles(t *testing.T) {
 	}
 }
 
+func TestTypedHolesAndMaps(t *testing.T) {
+	tpl := MustParse("create instance tags={Env:int} count={count as int}")
+	cmd := tpl.CommandNodesIterator()[0]
+	if got, want := cmd.Params["tags"], map[string]interface{}{"Env": "int"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+	if got, want := cmd.HoleTypes, map[string]string{"count": "int"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if _, err := Parse("create instance count={count as integer}"); err == nil {
+		t.Fatal("expected error for unknown hole type")
+	}
+}
+
+func TestResolveTypedHoles(t *testing.T) {
+	tpl := MustParse("create instance count={instance.count as int} ip={ip as ip} public={ public as bool } name={name as string} tags={Name:web}\ncreate subnet cidr={subnet.cidr as cidr}")
+
+	if got, want := tpl.GetHolesTypes(), map[string]string{"instance.count": "int", "ip": "ip", "public": "bool", "name": "string", "subnet.cidr": "cidr"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := tpl.CommandNodesIterator()[0].Params["tags"], map[string]interface{}{"Name": "web"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := tpl.CommandNodesIterator()[1].String(), "create subnet cidr={subnet.cidr as cidr}"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	if _, err := (&Template{AST: tpl.Clone()}).ResolveHoles(map[string]interface{}{"instance.count": "two"}); err == nil {
+		t.Fatal("expected error got none")
+	} else if got, want := err.Error(), "line 1 column 17: create instance: instance.count: invalid int value 'two'"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if _, err := (&Template{AST: tpl.Clone()}).ResolveHoles(map[string]interface{}{"subnet.cidr": "10.0.0.300/24"}); err == nil {
+		t.Fatal("expected error got none")
+	}
+
+	_, err := tpl.ResolveHoles(map[string]interface{}{"instance.count": "2", "ip": "10.0.0.1", "public": true, "name": 42, "subnet.cidr": "10.0.0.1/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"count": 2, "ip": "10.0.0.1", "public": true, "name": 42, "tags": map[string]interface{}{"Name": "web"}}
+	if got, want := tpl.CommandNodesIterator()[0].Params, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := tpl.CommandNodesIterator()[1].Params["cidr"], "10.0.0.0/24"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := len(tpl.GetHolesTypes()), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
+
+func TestResolveConcatenationHoles(t *testing.T) {
+	tpl := MustParse(`create instance name={env}+"-web-"+{index} subnet="sub"+"-"+1`)
+
+	if got, want := MustParse(`create instance name={env} + "-web-"+ {index}+1`).String(), `create instance name={env}+"-web-"+{index}+"1"`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	holes := tpl.GetHolesValuesSet()
+	sort.Strings(holes)
+	if got, want := holes, []string{"env", "index"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if _, err := tpl.ResolveHoles(map[string]interface{}{"env": "prod"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tpl.GetHolesValuesSet(), []string{"index"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := tpl.CommandNodesIterator()[0].Params["subnet"], "sub-1"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	resolved, err := tpl.ResolveHoles(map[string]interface{}{"index": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resolved, map[string]interface{}{"instance.name": "prod-web-2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := tpl.CommandNodesIterator()[0].Params["name"], "prod-web-2"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestRepeatStatements(t *testing.T) {
+	tpl := MustParse("vpc = create vpc\nrepeat 2: create instance name=\"web-$i\" # web\nrepeat { count }: sub = create subnet vpc=$vpc")
+
+	if got, want := tpl.String(), "vpc = create vpc \nrepeat 2: create instance name=\"web-$i\" # web\nrepeat {count}: sub = create subnet vpc=$vpc"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := tpl.GetHolesValuesSet(), []string{"count"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := tpl.GetHolesTypes(), map[string]string{"count": "int"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if _, err := tpl.Run(&noopDriver{}); err == nil {
+		t.Fatal("expected error got none")
+	}
+	if _, err := (&Template{AST: tpl.Clone()}).ResolveHoles(map[string]interface{}{"count": "two"}); err == nil {
+		t.Fatal("expected error got none")
+	}
+	if _, err := (&Template{AST: tpl.Clone()}).ResolveHoles(map[string]interface{}{"count": 0}); err == nil || err.Error() != "line 3 column 19: repeat 0: sub = create subnet vpc=$vpc: count must be at least 1" {
+		t.Fatalf("got %v, want count error", err)
+	}
+	if _, err := MustParse("repeat 0: create vpc").Run(&noopDriver{}); err == nil {
+		t.Fatal("expected error got none")
+	}
+
+	if _, err := tpl.ResolveHoles(map[string]interface{}{"count": "3"}); err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, sts := range tpl.Statements {
+		lines = append(lines, sts.String())
+	}
+	expected := []string{
+		"vpc = create vpc ",
+		"create instance name=web-1",
+		"create instance name=web-2",
+		"sub = create subnet vpc=$vpc",
+		"sub = create subnet vpc=$vpc",
+		"sub = create subnet vpc=$vpc",
+	}
+	if got, want := lines, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := tpl.Statements[1].Comment, "web"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if _, err := tpl.Run(&noopDriver{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := MustParse("repeat = create vpc").String(), "repeat = create vpc "; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestDropUnfilledOptionals(t *testing.T) {
+	tpl := MustParse("create instance keypair?={key} name?={name} subnet?=\"@{subnet}-a\" type?=t2.micro")
+
+	if got, want := tpl.CommandNodesIterator()[0].Optionals, map[string]bool{"keypair": true, "name": true, "subnet": true, "type": true}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := MustParse(tpl.String()).CommandNodesIterator()[0].Optionals, tpl.CommandNodesIterator()[0].Optionals; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if _, err := tpl.ResolveHoles(map[string]interface{}{"name": ""}); err != nil {
+		t.Fatal(err)
+	}
+	dropped := tpl.DropUnfilledOptionals()
+	sort.Strings(dropped)
+	if got, want := dropped, []string{"instance.keypair", "instance.subnet"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	cmd := tpl.CommandNodesIterator()[0]
+	if got, want := cmd.Params, map[string]interface{}{"name": "", "type": "t2.micro"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := len(cmd.Holes), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := tpl.String(), "create instance name?=\"\" type?=t2.micro"; got != want && got != "create instance type?=t2.micro name?=\"\"" {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestResolveEmbeddedHoles(t *testing.T) {
+	tpl, err := Parse(`run local cmd="ansible-playbook site.yml -i @{inventory} --limit @{ group }"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	holes := tpl.GetHolesValuesSet()
+	sort.Strings(holes)
+	if got, want := holes, []string{"group", "inventory"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if _, err = tpl.ResolveHoles(map[string]interface{}{"inventory": "hosts.ini", "group": "web"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tpl.String(), `run local cmd="ansible-playbook site.yml -i hosts.ini --limit web"`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := len(tpl.GetHolesValuesSet()), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
+
+func TestLiteralBracesInValues(t *testing.T) {
+	tpl, err := Parse("run local cmd=\"awk '{print}' {file} | jq '{name: .Name}'\"\ncreate policy name=admin definition=<<EOF\n{\"Statement\": {\"Effect\": \"{effect}\"}}\nEOF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(tpl.GetHolesValuesSet()), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if _, err = tpl.ResolveHoles(map[string]interface{}{"print": "x", "file": "x", "effect": "x"}); err != nil {
+		t.Fatal(err)
+	}
+	cmds := tpl.CommandNodesIterator()
+	if got, want := cmds[0].Params["cmd"], "awk '{print}' {file} | jq '{name: .Name}'"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := cmds[1].Params["definition"], `{"Statement": {"Effect": "{effect}"}}`; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestLoadFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-template-files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	script := "#!/bin/sh\necho \"@{ greeting }\" > $HOME/vpc-${SUFFIX}\necho $vpc. {{ jinja }} {greeting}\n"
+	if err = ioutil.WriteFile(filepath.Join(dir, "init.sh"), []byte(script), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tpl, err := Parse("vpc = create vpc cidr=10.0.0.0/16\ncreate instance userdata=@./init.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tpl.FileValues(), []string{"./init.sh"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if err = tpl.LoadFiles(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(tpl.FileValues()), 0; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := tpl.GetHolesValuesSet(), []string{"greeting"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if _, err = tpl.ResolveHoles(map[string]interface{}{"greeting": "hello"}); err != nil {
+		t.Fatal(err)
+	}
+
+	userdata := "#!/bin/sh\necho \"hello\" > $HOME/vpc-${SUFFIX}\necho mockvpc. {{ jinja }} {greeting}\n"
+	d := &mockDriver{prefix: "mock", expects: []*expectation{
+		{action: "create", entity: "vpc", expectedParams: map[string]interface{}{"cidr": "10.0.0.0/16"}},
+		{action: "create", entity: "instance", expectedParams: map[string]interface{}{"userdata": userdata}},
+	}}
+	ran, err := tpl.Run(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = d.lookupsCalled(); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := ParseStatement(ran.CommandNodesIterator()[1].String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := n.(*ast.CommandNode).Params["userdata"], userdata; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	tpl, err = Parse("create instance userdata=@./missing.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tpl.LoadFiles(dir); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
+
 type expectation struct {
 	lookupDone     bool
 	action, entity string
@@ -556,3 +885,211 @@ func (r *mockDriver) Lookup(lookups ...string) (driver.DriverFn, error) {
 
 func (r *mockDriver) SetLogger(*logger.Logger) {}
 func (r *mockDriver) SetDryRun(bool)           {}
+
+func TestRevertDependencies(t *testing.T) {
+	exec := &TemplateExecution{
+		Executed: []*ExecutedStatement{
+			{Line: "create vpc cidr=10.0.0.0/16", Result: "vpc-1"},
+			{Line: "create subnet cidr=10.0.0.0/24 vpc=vpc-1", Result: "sub-1"},
+			{Line: "create securitygroup description=web vpc=vpc-1", Result: "sg-1"},
+			{Line: "create instance subnet=sub-1 securitygroup=sg-1", Result: "i-1"},
+			{Line: "create instance subnet=sub-1", Result: "i-2"},
+			{Line: "stop instance id=i-3", Result: "i-3"},
+			{Line: "start instance id=i-3", Result: "i-3"},
+		},
+	}
+
+	tpl, err := exec.Revert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, cmd := range tpl.CommandNodesIterator() {
+		lines = append(lines, fmt.Sprintf("%s %s", cmd.Action, cmd.Params["id"]))
+	}
+	expLines := []string{"stop i-3", "start i-3", "delete i-2", "check i-2", "delete i-1", "check i-1", "delete sg-1", "delete sub-1", "delete vpc-1"}
+	if got, want := lines, expLines; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	deps, err := exec.RevertDependencies()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expDeps := [][]int{nil, {0}, nil, {2}, nil, {4}, {4, 5}, {2, 3, 4, 5}, {6, 7}}
+	if got, want := deps, expDeps; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestTemplateJSON(t *testing.T) {
+	text := `# network
+
+vpc = create vpc cidr=10.0.0.0/16 name="my-"+{env}
+create subnet vpc=$vpc cidr={subnet.cidr} zone=@eu-west tags=[a,b] count=2
+create instance name=web userdata=@/tmp/script.sh keypair?={keypair}`
+	templ := MustParse(text)
+
+	b, err := json.Marshal(templ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Template
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := decoded.Statements, templ.Statements; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got\n%#v\nwant\n%#v", got, want)
+	}
+	formatted, err := templ.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err = decoded.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := text, formatted; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tcases := []struct {
+		in, out string
+	}{
+		{in: "create   vpc  name=main   cidr=10.0.0.0/16", out: "create vpc cidr=10.0.0.0/16 name=main\n"},
+		{
+			in: "# network\n\n\n\nmyvpc   =  create vpc cidr=10.0.0.0/16   # main vpc\nsub = create subnet vpc=$myvpc zone=@eu-west cidr={sub.cidr as cidr} // public\ncreate tag key=Env value=\"10\"\n\n\n# instances\nrepeat 3: web=create instance keypair?={key}\n# end\n\n",
+			out: "# network\n\n" +
+				"myvpc = create vpc cidr=10.0.0.0/16                                    # main vpc\n" +
+				"sub   = create subnet cidr={sub.cidr as cidr} vpc=$myvpc zone=@eu-west # public\n" +
+				"create tag key=Env value=\"10\"\n\n" +
+				"# instances\nrepeat 3: web = create instance keypair?={key}\n# end\n",
+		},
+		{
+			in:  "create policy name=s3 document=\"{\\n  \\\"Version\\\": \\\"2012-10-17\\\"\\n}\" # read only\ncreate instance userdata=<<SH\n#!/bin/sh\nEOF\nSH\n",
+			out: "# read only\ncreate policy name=s3 document=<<EOF\n{\n  \"Version\": \"2012-10-17\"\n}\nEOF\ncreate instance userdata=<<EOF1\n#!/bin/sh\nEOF\nEOF1\n",
+		},
+		{in: "create instance threshold=1.10 price=0.5 version=\"1.5\" tags=[1.10,2.0]", out: "create instance price=0.5 tags=[1.10,2.0] threshold=1.10 version=\"1.5\"\n"},
+		{
+			in:  "webdefaults =  type=t2.micro   count=1 # web\ncreate instance ...webdefaults subnet=sub-1",
+			out: "webdefaults = count=1 type=t2.micro # web\ncreate instance count=1 subnet=sub-1 type=t2.micro\n",
+		},
+	}
+	for i, tcase := range tcases {
+		templ := MustParse(tcase.in)
+		out, err := templ.Format()
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := out, tcase.out; got != want {
+			t.Fatalf("%d: got\n%s\nwant\n%s", i+1, got, want)
+		}
+		again, err := MustParse(out).Format()
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := again, out; got != want {
+			t.Fatalf("%d: got\n%s\nwant\n%s", i+1, got, want)
+		}
+	}
+
+	templ := MustParse("# network\n\n// main\nmyvpc = create vpc\n# end")
+	if got, want := templ.Statements[0].Comments, []string{"# network", "", "// main"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := templ.Comments, []string{"# end"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestParamSetsRoundTrip(t *testing.T) {
+	templ := MustParse("webdefaults = type=t2.micro keypair={key} # web\ntagged = ...webdefaults tags={Env:prod}\ncreate instance ...tagged subnet=sub-1")
+	out, err := templ.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "webdefaults = keypair={key} type=t2.micro # web\n" +
+		"tagged      = keypair={key} tags={Env:prod} type=t2.micro\n" +
+		"create instance keypair={key} subnet=sub-1 tags={Env:prod} type=t2.micro\n"
+	if got, want := out, expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+
+	b, err := json.Marshal(templ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &Template{}
+	if err = json.Unmarshal(b, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(decoded.Statements), 3; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if _, ok := decoded.Statements[1].Node.(*ast.ParamSetNode); !ok {
+		t.Fatalf("got %T, want param set", decoded.Statements[1].Node)
+	}
+	again, err := decoded.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := again, out; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+	reparsed, err := MustParse(out).Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := reparsed, out; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFloatLiteralRoundTrip(t *testing.T) {
+	tpl := MustParse("create instance threshold=1.10")
+	if got, want := tpl.String(), "create instance threshold=1.10"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	params := MustParse(tpl.String()).CommandNodesIterator()[0].Params
+	if got, want := params, map[string]interface{}{"threshold": "1.10"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestLiteralLookingStringsRoundTrip(t *testing.T) {
+	tcases := []struct {
+		in, out string
+		params  map[string]interface{}
+	}{
+		{
+			in:     `create tag key=public value="true"`,
+			out:    `create tag key=public value="true"`,
+			params: map[string]interface{}{"key": "public", "value": "true"},
+		},
+		{
+			in:     `create instance count="5" name="-3" price="0.5" version="007"`,
+			params: map[string]interface{}{"count": "5", "name": "-3", "price": "0.5", "version": "007"},
+		},
+		{
+			in:     `create instance ports=["80",443,"true",false] tags={Env:"1",Team:web}`,
+			params: map[string]interface{}{"ports": []interface{}{"80", 443, "true", false}, "tags": map[string]interface{}{"Env": "1", "Team": "web"}},
+		},
+	}
+	for i, tcase := range tcases {
+		tpl := MustParse(tcase.in)
+		if tcase.out != "" {
+			out, err := tpl.Format()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := out, tcase.out+"\n"; got != want {
+				t.Fatalf("%d: got %s, want %s", i+1, got, want)
+			}
+		}
+		if got, want := MustParse(tpl.String()).CommandNodesIterator()[0].Params, tcase.params; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %#v, want %#v", i+1, got, want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template/ast"
 )
 
 type Validator interface {
@@ -78,6 +79,83 @@ func (v *UniqueNameValidator) Execute(t *Template) (errs []error) {
 	return
 }
 
+type RequiredTagsValidator struct {
+	Keys     []string
+	Taggable func(entity string) bool
+}
+
+// Execute checks every created taggable resource is tagged with all the required keys
+// through a 'create tag' statement referencing it (or its name param for the Name tag)
+func (v *RequiredTagsValidator) Execute(t *Template) (errs []error) {
+	if len(v.Keys) == 0 {
+		return
+	}
+
+	tagged := make(map[string]map[string]bool)
+	for _, cmd := range t.CommandNodesIterator() {
+		if cmd.Action != "create" || cmd.Entity != "tag" {
+			continue
+		}
+		if ref, ok := cmd.Refs["resource"]; ok {
+			if tagged[ref] == nil {
+				tagged[ref] = make(map[string]bool)
+			}
+			if key, ok := cmd.Params["key"]; ok {
+				tagged[ref][fmt.Sprint(key)] = true
+			}
+			if tags, ok := cmd.Params["tags"].(map[string]interface{}); ok {
+				for key := range tags {
+					tagged[ref][key] = true
+				}
+			}
+		}
+	}
+
+	for _, st := range t.Statements {
+		var ident string
+		var cmd *ast.CommandNode
+		switch n := st.Node.(type) {
+		case *ast.CommandNode:
+			cmd = n
+		case *ast.DeclarationNode:
+			ident = n.Ident
+			cmd, _ = n.Expr.(*ast.CommandNode)
+		}
+		if cmd == nil || cmd.Action != "create" || cmd.Entity == "tag" || !v.Taggable(cmd.Entity) {
+			continue
+		}
+
+		var missing []string
+		for _, key := range v.Keys {
+			if key == "Name" && hasParam(cmd, "name") {
+				continue
+			}
+			if !tagged[ident][key] {
+				missing = append(missing, fmt.Sprintf("'%s'", key))
+			}
+		}
+		if len(missing) > 0 {
+			errs = append(errs, fmt.Errorf("%s %s: missing required tags %s\n", cmd.Action, cmd.Entity, strings.Join(missing, ", ")))
+		}
+	}
+
+	return
+}
+
+func hasParam(cmd *ast.CommandNode, key string) bool {
+	if _, ok := cmd.Params[key]; ok {
+		return true
+	}
+	if _, ok := cmd.Refs[key]; ok {
+		return true
+	}
+	if _, ok := cmd.Aliases[key]; ok {
+		return true
+	}
+	_, ok := cmd.Holes[key]
+	return ok
+}
+
 func sliceContains(s string, arrs ...[]string) bool {
 	for _, arr := range arrs {
 		for _, el := range arr {
@@ -62,4 +62,68 @@ func TestValidation(t *testing.T) {
 			t.Fatalf("got %q, want %q", got, want)
 		}
 	})
+
+	t.Run("Validate required tags", func(t *testing.T) {
+		text := `myvpc = create vpc cidr=10.0.0.0/16
+create tag resource=$myvpc key=Team value=infra
+inst = create instance name=nemo subnet={subnet.id}
+create tag resource=$inst key=Env value=prod
+create subnet cidr=10.0.0.0/24 vpc=$myvpc
+tagged = create subnet cidr=10.0.1.0/24 vpc=$myvpc
+create tag resource=$tagged tags={Name:private,Team:infra}
+create keypair name=mykey`
+
+		tpl := template.MustParse(text)
+
+		taggable := func(entity string) bool { return entity != "keypair" }
+		rule := &template.RequiredTagsValidator{Keys: []string{"Name", "Team"}, Taggable: taggable}
+
+		errs := tpl.Validate(rule)
+		if got, want := len(errs), 3; got != want {
+			t.Fatalf("got %d, want %d: %v", got, want, errs)
+		}
+		expected := []string{
+			"create vpc: missing required tags 'Name'\n",
+			"create instance: missing required tags 'Team'\n",
+			"create subnet: missing required tags 'Name', 'Team'\n",
+		}
+		for i, exp := range expected {
+			if got, want := errs[i].Error(), exp; got != want {
+				t.Fatalf("got %q, want %q", got, want)
+			}
+		}
+	})
+
+	t.Run("guardrails", func(t *testing.T) {
+		text := `create vpc cidr=10.0.0.0/16
+create instance type=t2.micro name=web
+create instance type=m4.16xlarge name=big
+create instance type=t2.small group=[sg-1,sg-9] name=multi
+delete vpc id=vpc-1
+create user name=john
+check instance id=i-1 state=running timeout=30`
+		tpl := template.MustParse(text)
+
+		rule := &template.GuardrailsValidator{
+			Deny:   []string{"delete *"},
+			Allow:  []string{"create instance", "create vpc", "check *", "delete vpc"},
+			Values: map[string][]string{"instance.type": {"t2.micro", "t2.small"}, "instance.group": {"sg-1", "sg-2"}},
+		}
+
+		errs := tpl.Validate(rule)
+		expected := []string{
+			"create instance: type 'm4.16xlarge' not allowed by guardrails policy (allowed: t2.micro, t2.small)",
+			"create instance: group 'sg-9' not allowed by guardrails policy (allowed: sg-1, sg-2)",
+			"delete vpc: denied by guardrails policy (rule 'delete *')",
+			"create user: not allowed by guardrails policy (allowed: create instance, create vpc, check *, delete vpc)",
+		}
+		if got, want := len(errs), len(expected); got != want {
+			t.Fatalf("got %d, want %d: %v", got, want, errs)
+		}
+		for i, exp := range expected {
+			if got, want := errs[i].Error(), exp; got != want {
+				t.Fatalf("got %q, want %q", got, want)
+			}
+		}
+	})
 }
@@ -0,0 +1,75 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+var (
+	requestSpansMu sync.Mutex
+	requestSpans   = make(map[*request.Request]*Span)
+)
+
+// InstrumentAWS adds to the handlers of an AWS session a client span per API call
+// (all retries included) with the operation, retries and error code
+func InstrumentAWS(handlers *request.Handlers) {
+	handlers.Validate.PushFrontNamed(request.NamedHandler{Name: "awless.tracing.Start", Fn: startRequestSpan})
+	handlers.Unmarshal.PushBackNamed(request.NamedHandler{Name: "awless.tracing.Success", Fn: finishRequestSpan})
+	handlers.AfterRetry.PushBackNamed(request.NamedHandler{Name: "awless.tracing.Failure", Fn: func(r *request.Request) {
+		if r.Error != nil {
+			finishRequestSpan(r)
+		}
+	}})
+}
+
+func startRequestSpan(r *request.Request) {
+	span := Start(r.ClientInfo.ServiceName+"."+r.Operation.Name, KindClient)
+	span.SetAttribute("rpc.system", "aws-api")
+	span.SetAttribute("rpc.service", r.ClientInfo.ServiceName)
+	span.SetAttribute("rpc.method", r.Operation.Name)
+	if r.Config.Region != nil {
+		span.SetAttribute("aws.region", *r.Config.Region)
+	}
+	requestSpansMu.Lock()
+	requestSpans[r] = span
+	requestSpansMu.Unlock()
+}
+
+func finishRequestSpan(r *request.Request) {
+	requestSpansMu.Lock()
+	span, ok := requestSpans[r]
+	delete(requestSpans, r)
+	requestSpansMu.Unlock()
+	if !ok {
+		return
+	}
+	span.SetAttribute("aws.retries", r.RetryCount)
+	if r.HTTPResponse != nil {
+		span.SetAttribute("http.status_code", r.HTTPResponse.StatusCode)
+	}
+	if r.RequestID != "" {
+		span.SetAttribute("aws.request_id", r.RequestID)
+	}
+	if aerr, ok := r.Error.(awserr.Error); ok {
+		span.SetAttribute("aws.error_code", aerr.Code())
+	}
+	span.Finish(r.Error)
+}
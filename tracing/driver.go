@@ -0,0 +1,54 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"strings"
+
+	"github.com/wallix/awless/template/driver"
+)
+
+type tracedDriver struct {
+	driver.Driver
+	dryRun bool
+}
+
+// WrapDriver traces the template statements run by the driver, parents of their API calls
+func WrapDriver(d driver.Driver) driver.Driver {
+	return &tracedDriver{Driver: d}
+}
+
+func (d *tracedDriver) SetDryRun(dry bool) {
+	d.dryRun = dry
+	d.Driver.SetDryRun(dry)
+}
+
+func (d *tracedDriver) Lookup(lookups ...string) (driver.DriverFn, error) {
+	fn, err := d.Driver.Lookup(lookups...)
+	if err != nil {
+		return fn, err
+	}
+	statement, dryRun := strings.Join(lookups, " "), d.dryRun
+	return func(params map[string]interface{}) (interface{}, error) {
+		span, finish := StartActive("template " + statement)
+		span.SetAttribute("awless.statement", statement)
+		span.SetAttribute("awless.dry_run", dryRun)
+		result, err := fn(params)
+		finish(err)
+		return result, err
+	}, nil
+}
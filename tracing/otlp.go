@@ -0,0 +1,161 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EndpointKey is the config key of the base URL of the OTLP/HTTP collector. Ex: http://localhost:4318
+	EndpointKey = "tracing.otlp.endpoint"
+
+	serviceName = "awless"
+)
+
+// Endpoint returns the URL receiving the traces from the standard OTEL_EXPORTER_OTLP_* env variables,
+// or else from the given config values. Tracing is disabled when empty
+func Endpoint(defaults map[string]interface{}) string {
+	if url := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")); url != "" {
+		return url
+	}
+	base := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"))
+	if base == "" {
+		base, _ = defaults[EndpointKey].(string)
+	}
+	if base = strings.TrimSpace(base); base == "" {
+		return ""
+	}
+	return strings.TrimSuffix(base, "/") + "/v1/traces"
+}
+
+// Export sends the finished spans to the OTLP/HTTP endpoint, with the headers
+// of the OTEL_EXPORTER_OTLP_HEADERS env variable (ex: api-key=secret,tenant=ops)
+func (t *Tracer) Export(url, version string) error {
+	spans := t.Spans()
+	if len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(otlpRequest(spans, version))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for _, h := range strings.Split(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"), ",") {
+		if splits := strings.SplitN(h, "=", 2); len(splits) == 2 {
+			req.Header.Set(strings.TrimSpace(splits[0]), strings.TrimSpace(splits[1]))
+		}
+	}
+	resp, err := (&http.Client{Timeout: 5 * time.Second}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("otlp export: %d %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+type otlpKeyValue struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              Kind           `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+func otlpRequest(spans []*Span, version string) map[string]interface{} {
+	var all []otlpSpan
+	for _, s := range spans {
+		s.mu.Lock()
+		span := otlpSpan{
+			TraceID:           s.TraceID,
+			SpanID:            s.SpanID,
+			ParentSpanID:      s.ParentID,
+			Name:              s.Name,
+			Kind:              s.Kind,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        otlpAttributes(s.Attributes),
+			Status:            otlpStatus{Code: 1},
+		}
+		if s.Err != "" {
+			span.Status = otlpStatus{Code: 2, Message: s.Err}
+		}
+		s.mu.Unlock()
+		all = append(all, span)
+	}
+	resource := otlpAttributes(map[string]interface{}{"service.name": serviceName, "service.version": version})
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource":   map[string]interface{}{"attributes": resource},
+				"scopeSpans": []interface{}{map[string]interface{}{"scope": map[string]string{"name": serviceName}, "spans": all}},
+			},
+		},
+	}
+}
+
+func otlpAttributes(attrs map[string]interface{}) (all []otlpKeyValue) {
+	for k, v := range attrs {
+		var value map[string]interface{}
+		switch vv := v.(type) {
+		case bool:
+			value = map[string]interface{}{"boolValue": vv}
+		case int:
+			value = map[string]interface{}{"intValue": strconv.Itoa(vv)}
+		case int64:
+			value = map[string]interface{}{"intValue": strconv.FormatInt(vv, 10)}
+		case float64:
+			value = map[string]interface{}{"doubleValue": vv}
+		default:
+			value = map[string]interface{}{"stringValue": fmt.Sprint(vv)}
+		}
+		all = append(all, otlpKeyValue{Key: k, Value: value})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Key < all[j].Key })
+	return
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing records spans of awless commands, template statements and cloud API calls,
+// and exports them to an OpenTelemetry collector with the OTLP/HTTP JSON protocol
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Span is a timed operation. Spans are safe to use concurrently
+type Span struct {
+	TraceID, SpanID, ParentID string
+	Name                      string
+	Kind                      Kind
+	Start, End                time.Time
+	Attributes                map[string]interface{}
+	Err                       string
+
+	mu       sync.Mutex
+	previous *Span // active span when this one was started active
+}
+
+type Kind int
+
+// Span kinds as defined by OpenTelemetry
+const (
+	KindInternal Kind = 1
+	KindClient   Kind = 3
+)
+
+// SetAttribute sets a string, bool, int or float attribute
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+// Finish ends the span, with an error status if err is not nil
+func (s *Span) Finish(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.End.IsZero() {
+		return
+	}
+	s.End = time.Now()
+	if err != nil {
+		s.Err = err.Error()
+	}
+}
+
+func (s *Span) finished() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.End.IsZero()
+}
+
+// Tracer records the spans of a trace
+type Tracer struct {
+	mu      sync.Mutex
+	traceID string
+	root    *Span
+	active  *Span
+	spans   []*Span
+}
+
+// DefaultTracer traces the current awless command
+var DefaultTracer = NewTracer()
+
+func NewTracer() *Tracer {
+	return &Tracer{traceID: randomHex(16)}
+}
+
+// StartRoot starts the span of the whole command, parent of the other spans
+func (t *Tracer) StartRoot(name string) *Span {
+	span := t.Start(name, KindInternal)
+	t.mu.Lock()
+	t.root, span.ParentID = span, ""
+	t.mu.Unlock()
+	return span
+}
+
+// Start starts a span, child of the active span if any or else of the root span
+func (t *Tracer) Start(name string, kind Kind) *Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &Span{TraceID: t.traceID, SpanID: randomHex(8), Name: name, Kind: kind, Start: time.Now(), Attributes: make(map[string]interface{})}
+	switch {
+	case t.active != nil:
+		span.ParentID = t.active.SpanID
+	case t.root != nil:
+		span.ParentID = t.root.SpanID
+	}
+	t.spans = append(t.spans, span)
+	return span
+}
+
+// StartActive starts a span becoming the parent of the spans started until it is finished
+// with the returned func. Ex: a template statement parent of its API calls.
+// Active spans finished in any order (ex: statements run concurrently) restore the last unfinished one
+func (t *Tracer) StartActive(name string) (*Span, func(error)) {
+	span := t.Start(name, KindInternal)
+	t.mu.Lock()
+	span.previous = t.active
+	t.active = span
+	t.mu.Unlock()
+	return span, func(err error) {
+		span.Finish(err)
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.active != span {
+			return
+		}
+		t.active = span.previous
+		for t.active != nil && t.active.finished() {
+			t.active = t.active.previous
+		}
+	}
+}
+
+// Root returns the root span if started
+func (t *Tracer) Root() *Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.root
+}
+
+// Spans returns the finished spans
+func (t *Tracer) Spans() (spans []*Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.spans {
+		if s.finished() {
+			spans = append(spans, s)
+		}
+	}
+	return
+}
+
+func Start(name string, kind Kind) *Span {
+	return DefaultTracer.Start(name, kind)
+}
+
+func StartActive(name string) (*Span, func(error)) {
+	return DefaultTracer.StartActive(name)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
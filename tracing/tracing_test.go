@@ -0,0 +1,220 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template/driver"
+)
+
+func TestSpansHierarchy(t *testing.T) {
+	tracer := NewTracer()
+	root := tracer.StartRoot("awless run")
+	statement, finish := tracer.StartActive("template create instance")
+	call := tracer.Start("ec2.RunInstances", KindClient)
+	call.Finish(errors.New("throttled"))
+	finish(nil)
+	after := tracer.Start("sync infra", KindInternal)
+	unfinished := tracer.Start("never finished", KindInternal)
+	after.Finish(nil)
+	root.Finish(nil)
+
+	if got, want := root.ParentID, ""; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := statement.ParentID, root.SpanID; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := call.ParentID, statement.SpanID; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := after.ParentID, root.SpanID; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := call.Err, "throttled"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	for _, s := range tracer.Spans() {
+		if s == unfinished {
+			t.Fatal("unfinished span should not be exported")
+		}
+		if got, want := s.TraceID, root.TraceID; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	}
+	if got, want := len(tracer.Spans()), 4; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
+
+func TestActiveSpansFinishedInAnyOrder(t *testing.T) {
+	tracer := NewTracer()
+	root := tracer.StartRoot("awless revert")
+	first, finishFirst := tracer.StartActive("template delete instance")
+	_, finishSecond := tracer.StartActive("template delete subnet")
+	finishFirst(nil)
+	finishSecond(nil)
+	after := tracer.Start("sync infra", KindInternal)
+
+	if got, want := after.ParentID, root.SpanID; got != want {
+		t.Fatalf("got %s, want %s (first statement %s)", got, want, first.SpanID)
+	}
+}
+
+func TestExport(t *testing.T) {
+	var received map[string]interface{}
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/v1/traces"; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		header = r.Header
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	os.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "api-key=secret")
+	defer os.Unsetenv("OTEL_EXPORTER_OTLP_HEADERS")
+
+	tracer := NewTracer()
+	span := tracer.StartRoot("awless sync")
+	span.SetAttribute("aws.retries", 2)
+	span.Finish(errors.New("access denied"))
+
+	url := Endpoint(map[string]interface{}{EndpointKey: server.URL + "/"})
+	if got, want := url, server.URL+"/v1/traces"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if err := tracer.Export(url, "0.0.17"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := header.Get("api-key"), "secret"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	spans := received["resourceSpans"].([]interface{})[0].(map[string]interface{})["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})
+	if got, want := len(spans), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	exported := spans[0].(map[string]interface{})
+	if got, want := exported["name"], "awless sync"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := exported["status"].(map[string]interface{})["code"], float64(2); got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	attr := exported["attributes"].([]interface{})[0].(map[string]interface{})
+	if got, want := attr["value"].(map[string]interface{})["intValue"], "2"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if got, want := Endpoint(nil), ""; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestInstrumentAWS(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("x-amzn-RequestId", "req-1")
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`<ErrorResponse><Error><Code>ServiceUnavailable</Code><Message>retry</Message></Error></ErrorResponse>`))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`<ErrorResponse><Error><Code>AuthorizationError</Code><Message>denied</Message></Error></ErrorResponse>`))
+	}))
+	defer server.Close()
+
+	DefaultTracer = NewTracer()
+	sess := session.Must(session.NewSession(&awssdk.Config{
+		Region:      awssdk.String("eu-west-1"),
+		Endpoint:    awssdk.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+		MaxRetries:  awssdk.Int(1),
+	}))
+	InstrumentAWS(&sess.Handlers)
+	sess.Config.SleepDelay = func(time.Duration) {}
+
+	if _, err := sns.New(sess).ListTopics(&sns.ListTopicsInput{}); err == nil {
+		t.Fatal("expected error got none")
+	}
+	spans := DefaultTracer.Spans()
+	if got, want := len(spans), 1; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	expected := map[string]interface{}{"rpc.method": "ListTopics", "aws.retries": 1, "aws.error_code": "AuthorizationError", "http.status_code": 403, "aws.region": "eu-west-1"}
+	for k, want := range expected {
+		if got := spans[0].Attributes[k]; got != want {
+			t.Fatalf("%s: got %v, want %v", k, got, want)
+		}
+	}
+	if got, want := spans[0].Name, "sns.ListTopics"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Lookup(...string) (driver.DriverFn, error) {
+	return func(map[string]interface{}) (interface{}, error) {
+		Start("ec2.RunInstances", KindClient).Finish(nil)
+		return "i-1", nil
+	}, nil
+}
+func (fakeDriver) SetDryRun(bool)           {}
+func (fakeDriver) SetLogger(*logger.Logger) {}
+
+func TestWrapDriver(t *testing.T) {
+	DefaultTracer = NewTracer()
+	d := WrapDriver(fakeDriver{})
+	d.SetDryRun(true)
+	fn, err := d.Lookup("create", "instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fn(nil); err != nil {
+		t.Fatal(err)
+	}
+	spans := DefaultTracer.Spans()
+	if got, want := len(spans), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	statement, call := spans[0], spans[1]
+	if got, want := statement.Name, "template create instance"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := statement.Attributes["awless.dry_run"], true; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := call.ParentID, statement.SpanID; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}